@@ -0,0 +1,43 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package api
+
+import "github.com/hyperledger/aries-framework-go/cmd/aries-agent-mobile/pkg/wrappers/models"
+
+// ActionMenuController defines methods for the ActionMenu controller.
+type ActionMenuController interface {
+
+	// Actions returns unfinished actions for the async usage.
+	Actions(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// PublishMenu publishes the menu for the connection.
+	PublishMenu(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// SendMenu publishes the menu for the connection and sends it to the connection.
+	SendMenu(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// RequestMenu requests the menu from the connection.
+	RequestMenu(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// GetMenu returns the last menu received from the connection.
+	GetMenu(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// Perform asks the connection to perform the option of its menu.
+	Perform(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// AcceptMenuRequest sends the menu in response to the menu request.
+	AcceptMenuRequest(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// DeclineMenuRequest ignores the menu request.
+	DeclineMenuRequest(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// AcceptPerform is used when the action of the perform is done.
+	AcceptPerform(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// DeclinePerform ignores the perform.
+	DeclinePerform(request *models.RequestEnvelope) *models.ResponseEnvelope
+}
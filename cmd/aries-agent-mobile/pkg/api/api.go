@@ -39,6 +39,12 @@ type AriesController interface {
 	// GetKMSController returns an implementation of KMSController
 	GetKMSController() (KMSController, error)
 
+	// GetTrustPingController returns an implementation of TrustPingController
+	GetTrustPingController() (TrustPingController, error)
+
+	// GetActionMenuController returns an implementation of ActionMenuController
+	GetActionMenuController() (ActionMenuController, error)
+
 	// RegisterHandler registers handler for handling notifications
 	RegisterHandler(h Handler, topics string) string
 
@@ -31,4 +31,10 @@ type OutOfBandController interface {
 
 	// ActionStop stops the protocol after an action event was triggered.
 	ActionStop(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// EncodeInvitation encodes an invitation into an invitation URL or a QR code payload.
+	EncodeInvitation(request *models.RequestEnvelope) *models.ResponseEnvelope
+
+	// DecodeInvitation decodes an invitation from an invitation URL or a QR code payload.
+	DecodeInvitation(request *models.RequestEnvelope) *models.ResponseEnvelope
 }
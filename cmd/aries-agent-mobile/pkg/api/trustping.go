@@ -0,0 +1,16 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package api
+
+import "github.com/hyperledger/aries-framework-go/cmd/aries-agent-mobile/pkg/wrappers/models"
+
+// TrustPingController defines methods for the TrustPing controller.
+type TrustPingController interface {
+
+	// Ping sends the ping to the connection and returns the round-trip time.
+	Ping(request *models.RequestEnvelope) *models.ResponseEnvelope
+}
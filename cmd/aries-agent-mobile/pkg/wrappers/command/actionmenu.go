@@ -0,0 +1,89 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package command
+
+import (
+	"encoding/json"
+
+	"github.com/hyperledger/aries-framework-go/cmd/aries-agent-mobile/pkg/wrappers/models"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/command"
+	cmdactionmenu "github.com/hyperledger/aries-framework-go/pkg/controller/command/actionmenu"
+)
+
+// ActionMenu contains handler function for action menu protocol commands.
+type ActionMenu struct {
+	handlers map[string]command.Exec
+}
+
+// Actions returns unfinished actions for the async usage.
+func (a *ActionMenu) Actions(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	response, cmdErr := exec(a.handlers[cmdactionmenu.Actions], request.Payload)
+	if cmdErr != nil {
+		return &models.ResponseEnvelope{Error: cmdErr}
+	}
+
+	return &models.ResponseEnvelope{Payload: response}
+}
+
+// PublishMenu publishes the menu for the connection.
+func (a *ActionMenu) PublishMenu(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return a.execute(cmdactionmenu.PublishMenu, request, &cmdactionmenu.MenuArgs{})
+}
+
+// SendMenu publishes the menu for the connection and sends it to the connection.
+func (a *ActionMenu) SendMenu(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return a.execute(cmdactionmenu.SendMenu, request, &cmdactionmenu.MenuArgs{})
+}
+
+// RequestMenu requests the menu from the connection.
+func (a *ActionMenu) RequestMenu(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return a.execute(cmdactionmenu.RequestMenu, request, &cmdactionmenu.ConnectionArgs{})
+}
+
+// GetMenu returns the last menu received from the connection.
+func (a *ActionMenu) GetMenu(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return a.execute(cmdactionmenu.GetMenu, request, &cmdactionmenu.ConnectionArgs{})
+}
+
+// Perform asks the connection to perform the option of its menu.
+func (a *ActionMenu) Perform(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return a.execute(cmdactionmenu.Perform, request, &cmdactionmenu.PerformArgs{})
+}
+
+// AcceptMenuRequest sends the menu in response to the menu request.
+func (a *ActionMenu) AcceptMenuRequest(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return a.execute(cmdactionmenu.AcceptMenuRequest, request, &cmdactionmenu.AcceptArgs{})
+}
+
+// DeclineMenuRequest ignores the menu request.
+func (a *ActionMenu) DeclineMenuRequest(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return a.execute(cmdactionmenu.DeclineMenuRequest, request, &cmdactionmenu.DeclineArgs{})
+}
+
+// AcceptPerform is used when the action of the perform is done.
+func (a *ActionMenu) AcceptPerform(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return a.execute(cmdactionmenu.AcceptPerform, request, &cmdactionmenu.AcceptArgs{})
+}
+
+// DeclinePerform ignores the perform.
+func (a *ActionMenu) DeclinePerform(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return a.execute(cmdactionmenu.DeclinePerform, request, &cmdactionmenu.DeclineArgs{})
+}
+
+func (a *ActionMenu) execute(method string, request *models.RequestEnvelope,
+	args interface{}) *models.ResponseEnvelope {
+	if err := json.Unmarshal(request.Payload, args); err != nil {
+		return &models.ResponseEnvelope{Error: &models.CommandError{Message: err.Error()}}
+	}
+
+	response, cmdErr := exec(a.handlers[method], args)
+	if cmdErr != nil {
+		return &models.ResponseEnvelope{Error: cmdErr}
+	}
+
+	return &models.ResponseEnvelope{Payload: response}
+}
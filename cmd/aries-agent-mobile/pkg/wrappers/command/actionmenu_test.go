@@ -0,0 +1,93 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package command
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/cmd/aries-agent-mobile/pkg/wrappers/models"
+	cmdactionmenu "github.com/hyperledger/aries-framework-go/pkg/controller/command/actionmenu"
+)
+
+func getActionMenuController(t *testing.T) *ActionMenu {
+	a, err := getAgent()
+	require.NotNil(t, a)
+	require.NoError(t, err)
+
+	controller, err := a.GetActionMenuController()
+	require.NoError(t, err)
+	require.NotNil(t, controller)
+
+	am, ok := controller.(*ActionMenu)
+	require.Equal(t, ok, true)
+
+	return am
+}
+
+func TestActionMenu_Actions(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		controller := getActionMenuController(t)
+
+		mockResponse := `{"actions":[{"PIID":"piid-1"}]}`
+		fakeHandler := mockCommandRunner{data: []byte(mockResponse)}
+		controller.handlers[cmdactionmenu.Actions] = fakeHandler.exec
+
+		resp := controller.Actions(&models.RequestEnvelope{})
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, mockResponse, string(resp.Payload))
+	})
+}
+
+func TestActionMenu_Commands(t *testing.T) {
+	controller := getActionMenuController(t)
+
+	tests := []struct {
+		method  string
+		request string
+		call    func(*models.RequestEnvelope) *models.ResponseEnvelope
+	}{
+		{
+			method:  cmdactionmenu.PublishMenu,
+			request: `{"connectionID":"123-abc","menu":{"title":"Issuer bot"}}`,
+			call:    controller.PublishMenu,
+		},
+		{
+			method:  cmdactionmenu.SendMenu,
+			request: `{"connectionID":"123-abc","menu":{"title":"Issuer bot"}}`,
+			call:    controller.SendMenu,
+		},
+		{method: cmdactionmenu.RequestMenu, request: sampleConnRequest, call: controller.RequestMenu},
+		{method: cmdactionmenu.GetMenu, request: sampleConnRequest, call: controller.GetMenu},
+		{method: cmdactionmenu.Perform, request: `{"connectionID":"123-abc","name":"issue"}`, call: controller.Perform},
+		{method: cmdactionmenu.AcceptMenuRequest, request: `{"piid":"piid-1"}`, call: controller.AcceptMenuRequest},
+		{method: cmdactionmenu.DeclineMenuRequest, request: `{"piid":"piid-1"}`, call: controller.DeclineMenuRequest},
+		{method: cmdactionmenu.AcceptPerform, request: `{"piid":"piid-1"}`, call: controller.AcceptPerform},
+		{method: cmdactionmenu.DeclinePerform, request: `{"piid":"piid-1"}`, call: controller.DeclinePerform},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+
+		t.Run(tc.method, func(t *testing.T) {
+			mockResponse := `{"menu":{"title":"Issuer bot"}}`
+			fakeHandler := mockCommandRunner{data: []byte(mockResponse)}
+			controller.handlers[tc.method] = fakeHandler.exec
+
+			resp := tc.call(&models.RequestEnvelope{Payload: []byte(tc.request)})
+			require.NotNil(t, resp)
+			require.Nil(t, resp.Error)
+			require.Equal(t, mockResponse, string(resp.Payload))
+
+			resp = tc.call(&models.RequestEnvelope{Payload: []byte("{")})
+			require.NotNil(t, resp)
+			require.NotNil(t, resp.Error)
+		})
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/common/log"
 	"github.com/hyperledger/aries-framework-go/pkg/controller"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/command"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/command/actionmenu"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/command/didexchange"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/command/introduce"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/command/issuecredential"
@@ -30,6 +31,7 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/controller/command/messaging"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/command/outofband"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/command/presentproof"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/command/trustping"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/command/vdr"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/command/verifiable"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/messaging/msghandler"
@@ -69,10 +71,16 @@ func NewAries(opts *config.Options) (*Aries, error) {
 
 	notifications := make(chan notifier.NotificationPayload)
 
-	commandHandlers, err := controller.GetCommandHandlers(context,
+	controllerOpts := []controller.Opt{
 		controller.WithNotifier(notifier.NewNotifier(notifications)),
 		controller.WithAutoAccept(opts.AutoAccept),
-	)
+	}
+
+	if opts.InvitationURLResolver != nil {
+		controllerOpts = append(controllerOpts, controller.WithInvitationURLResolver(opts.InvitationURLResolver))
+	}
+
+	commandHandlers, err := controller.GetCommandHandlers(context, controllerOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get command handlers: %w", err)
 	}
@@ -322,3 +330,23 @@ func (a *Aries) GetKMSController() (api.KMSController, error) {
 
 	return &KMS{handlers: handlers}, nil
 }
+
+// GetTrustPingController returns a TrustPing instance.
+func (a *Aries) GetTrustPingController() (api.TrustPingController, error) {
+	handlers, ok := a.handlers[trustping.CommandName]
+	if !ok {
+		return nil, fmt.Errorf("no handlers found for controller [%s]", trustping.CommandName)
+	}
+
+	return &TrustPing{handlers: handlers}, nil
+}
+
+// GetActionMenuController returns an ActionMenu instance.
+func (a *Aries) GetActionMenuController() (api.ActionMenuController, error) {
+	handlers, ok := a.handlers[actionmenu.CommandName]
+	if !ok {
+		return nil, fmt.Errorf("no handlers found for controller [%s]", actionmenu.CommandName)
+	}
+
+	return &ActionMenu{handlers: handlers}, nil
+}
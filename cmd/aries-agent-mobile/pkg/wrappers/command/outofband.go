@@ -124,3 +124,35 @@ func (oob *OutOfBand) ActionStop(request *models.RequestEnvelope) *models.Respon
 
 	return &models.ResponseEnvelope{Payload: response}
 }
+
+// EncodeInvitation encodes an invitation into an invitation URL or a QR code payload.
+func (oob *OutOfBand) EncodeInvitation(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	args := outofband.EncodeInvitationArgs{}
+
+	if err := json.Unmarshal(request.Payload, &args); err != nil {
+		return &models.ResponseEnvelope{Error: &models.CommandError{Message: err.Error()}}
+	}
+
+	response, cmdErr := exec(oob.handlers[outofband.EncodeInvitation], args)
+	if cmdErr != nil {
+		return &models.ResponseEnvelope{Error: cmdErr}
+	}
+
+	return &models.ResponseEnvelope{Payload: response}
+}
+
+// DecodeInvitation decodes an invitation from an invitation URL or a QR code payload.
+func (oob *OutOfBand) DecodeInvitation(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	args := outofband.DecodeInvitationArgs{}
+
+	if err := json.Unmarshal(request.Payload, &args); err != nil {
+		return &models.ResponseEnvelope{Error: &models.CommandError{Message: err.Error()}}
+	}
+
+	response, cmdErr := exec(oob.handlers[outofband.DecodeInvitation], args)
+	if cmdErr != nil {
+		return &models.ResponseEnvelope{Error: cmdErr}
+	}
+
+	return &models.ResponseEnvelope{Payload: response}
+}
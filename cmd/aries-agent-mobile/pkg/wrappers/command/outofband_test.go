@@ -184,3 +184,43 @@ func TestOutOfBand_CreateRequest(t *testing.T) {
 			string(resp.Payload))
 	})
 }
+
+func TestOutOfBand_EncodeInvitation(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		controller := getOutOfBandController(t)
+
+		mockResponse := `{"url":"https://example.com?oob=eyJAaWQiOiJpbnYtaWQifQ"}`
+		fakeHandler := mockCommandRunner{data: []byte(mockResponse)}
+		controller.handlers[outofband.EncodeInvitation] = fakeHandler.exec
+
+		payload := `{"base_url":"https://example.com","invitation":{"@id":"inv-id"},"qr":true}`
+
+		req := &models.RequestEnvelope{Payload: []byte(payload)}
+		resp := controller.EncodeInvitation(req)
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t,
+			mockResponse,
+			string(resp.Payload))
+	})
+}
+
+func TestOutOfBand_DecodeInvitation(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		controller := getOutOfBandController(t)
+
+		mockResponse := `{"invitation":{"@id":"inv-id","@type":"","service":null,"handshake_protocols":null}}`
+		fakeHandler := mockCommandRunner{data: []byte(mockResponse)}
+		controller.handlers[outofband.DecodeInvitation] = fakeHandler.exec
+
+		payload := `{"url":"https://example.com?oob=eyJAaWQiOiJpbnYtaWQifQ"}`
+
+		req := &models.RequestEnvelope{Payload: []byte(payload)}
+		resp := controller.DecodeInvitation(req)
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t,
+			mockResponse,
+			string(resp.Payload))
+	})
+}
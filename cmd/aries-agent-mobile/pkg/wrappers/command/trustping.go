@@ -0,0 +1,37 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package command
+
+import (
+	"encoding/json"
+
+	"github.com/hyperledger/aries-framework-go/cmd/aries-agent-mobile/pkg/wrappers/models"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/command"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/command/trustping"
+)
+
+// TrustPing contains necessary fields to support its operations.
+type TrustPing struct {
+	handlers map[string]command.Exec
+}
+
+// Ping sends the ping to the connection and returns the round-trip time.
+func (tp *TrustPing) Ping(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	// response is requested unless the request says otherwise
+	args := trustping.PingArgs{ResponseRequested: true}
+
+	if err := json.Unmarshal(request.Payload, &args); err != nil {
+		return &models.ResponseEnvelope{Error: &models.CommandError{Message: err.Error()}}
+	}
+
+	response, cmdErr := exec(tp.handlers[trustping.PingCommandMethod], args)
+	if cmdErr != nil {
+		return &models.ResponseEnvelope{Error: cmdErr}
+	}
+
+	return &models.ResponseEnvelope{Payload: response}
+}
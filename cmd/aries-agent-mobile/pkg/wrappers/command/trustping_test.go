@@ -0,0 +1,55 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package command
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/cmd/aries-agent-mobile/pkg/wrappers/models"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/command/trustping"
+)
+
+func getTrustPingController(t *testing.T) *TrustPing {
+	a, err := getAgent()
+	require.NotNil(t, a)
+	require.NoError(t, err)
+
+	controller, err := a.GetTrustPingController()
+	require.NoError(t, err)
+	require.NotNil(t, controller)
+
+	tp, ok := controller.(*TrustPing)
+	require.Equal(t, ok, true)
+
+	return tp
+}
+
+func TestTrustPing_Ping(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		controller := getTrustPingController(t)
+
+		mockResponse := `{"round_trip_time":1.5}`
+		fakeHandler := mockCommandRunner{data: []byte(mockResponse)}
+		controller.handlers[trustping.PingCommandMethod] = fakeHandler.exec
+
+		req := &models.RequestEnvelope{Payload: []byte(sampleConnRequest)}
+		resp := controller.Ping(req)
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, mockResponse, string(resp.Payload))
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		controller := getTrustPingController(t)
+
+		resp := controller.Ping(&models.RequestEnvelope{Payload: []byte("{")})
+		require.NotNil(t, resp)
+		require.NotNil(t, resp.Error)
+	})
+}
@@ -6,7 +6,10 @@ SPDX-License-Identifier: Apache-2.0
 
 package config
 
-import "github.com/hyperledger/aries-framework-go/cmd/aries-agent-mobile/pkg/api"
+import (
+	"github.com/hyperledger/aries-framework-go/cmd/aries-agent-mobile/pkg/api"
+	"github.com/hyperledger/aries-framework-go/pkg/client/outofband"
+)
 
 // Options represents configurations for Aries.
 type Options struct {
@@ -25,6 +28,9 @@ type Options struct {
 	HTTPResolvers     []string
 	OutboundTransport []string
 	WebsocketURL      string
+
+	// InvitationURLResolver resolves the out-of-band invitation URLs, the default HTTP client is used if not set.
+	InvitationURLResolver *outofband.URLResolver
 }
 
 // New returns an instance of Options which can be used to configure an aries controller instance.
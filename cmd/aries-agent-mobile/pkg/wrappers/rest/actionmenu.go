@@ -0,0 +1,81 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package rest
+
+import (
+	"github.com/hyperledger/aries-framework-go/cmd/aries-agent-mobile/pkg/wrappers/models"
+	cmdactionmenu "github.com/hyperledger/aries-framework-go/pkg/controller/command/actionmenu"
+)
+
+// ActionMenu contains necessary fields for each of its operations.
+type ActionMenu struct {
+	httpClient httpClient
+	endpoints  map[string]*endpoint
+
+	URL   string
+	Token string
+}
+
+// Actions returns unfinished actions for the async usage.
+func (ar *ActionMenu) Actions(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return ar.createRespEnvelope(request, cmdactionmenu.Actions)
+}
+
+// PublishMenu publishes the menu for the connection.
+func (ar *ActionMenu) PublishMenu(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return ar.createRespEnvelope(request, cmdactionmenu.PublishMenu)
+}
+
+// SendMenu publishes the menu for the connection and sends it to the connection.
+func (ar *ActionMenu) SendMenu(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return ar.createRespEnvelope(request, cmdactionmenu.SendMenu)
+}
+
+// RequestMenu requests the menu from the connection.
+func (ar *ActionMenu) RequestMenu(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return ar.createRespEnvelope(request, cmdactionmenu.RequestMenu)
+}
+
+// GetMenu returns the last menu received from the connection.
+func (ar *ActionMenu) GetMenu(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return ar.createRespEnvelope(request, cmdactionmenu.GetMenu)
+}
+
+// Perform asks the connection to perform the option of its menu.
+func (ar *ActionMenu) Perform(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return ar.createRespEnvelope(request, cmdactionmenu.Perform)
+}
+
+// AcceptMenuRequest sends the menu in response to the menu request.
+func (ar *ActionMenu) AcceptMenuRequest(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return ar.createRespEnvelope(request, cmdactionmenu.AcceptMenuRequest)
+}
+
+// DeclineMenuRequest ignores the menu request.
+func (ar *ActionMenu) DeclineMenuRequest(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return ar.createRespEnvelope(request, cmdactionmenu.DeclineMenuRequest)
+}
+
+// AcceptPerform is used when the action of the perform is done.
+func (ar *ActionMenu) AcceptPerform(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return ar.createRespEnvelope(request, cmdactionmenu.AcceptPerform)
+}
+
+// DeclinePerform ignores the perform.
+func (ar *ActionMenu) DeclinePerform(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return ar.createRespEnvelope(request, cmdactionmenu.DeclinePerform)
+}
+
+func (ar *ActionMenu) createRespEnvelope(request *models.RequestEnvelope, endpoint string) *models.ResponseEnvelope {
+	return exec(&restOperation{
+		url:        ar.URL,
+		token:      ar.Token,
+		httpClient: ar.httpClient,
+		endpoint:   ar.endpoints[endpoint],
+		request:    request,
+	})
+}
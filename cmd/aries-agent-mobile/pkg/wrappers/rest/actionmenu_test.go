@@ -0,0 +1,105 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package rest
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/cmd/aries-agent-mobile/pkg/wrappers/models"
+	opactionmenu "github.com/hyperledger/aries-framework-go/pkg/controller/rest/actionmenu"
+)
+
+func getActionMenuController(t *testing.T) *ActionMenu {
+	a, err := getAgent()
+	require.NotNil(t, a)
+	require.NoError(t, err)
+
+	controller, err := a.GetActionMenuController()
+	require.NoError(t, err)
+	require.NotNil(t, controller)
+
+	am, ok := controller.(*ActionMenu)
+	require.Equal(t, ok, true)
+
+	return am
+}
+
+func TestActionMenu_Operations(t *testing.T) {
+	controller := getActionMenuController(t)
+
+	tests := []struct {
+		name    string
+		method  string
+		url     string
+		request string
+		call    func(*models.RequestEnvelope) *models.ResponseEnvelope
+	}{
+		{
+			name: "actions", method: http.MethodGet, url: opactionmenu.Actions,
+			call: controller.Actions,
+		},
+		{
+			name: "publish menu", method: http.MethodPost, url: opactionmenu.PublishMenu,
+			request: `{"connectionID":"123-abc","menu":{"title":"Issuer bot"}}`, call: controller.PublishMenu,
+		},
+		{
+			name: "send menu", method: http.MethodPost, url: opactionmenu.SendMenu,
+			request: `{"connectionID":"123-abc","menu":{"title":"Issuer bot"}}`, call: controller.SendMenu,
+		},
+		{
+			name: "request menu", method: http.MethodPost, url: opactionmenu.RequestMenu,
+			request: sampleConnRequest, call: controller.RequestMenu,
+		},
+		{
+			name: "get menu", method: http.MethodGet,
+			url:     strings.Replace(opactionmenu.GetMenu, "{connectionID}", "123-abc", 1),
+			request: sampleConnRequest, call: controller.GetMenu,
+		},
+		{
+			name: "perform", method: http.MethodPost, url: opactionmenu.Perform,
+			request: `{"connectionID":"123-abc","name":"issue"}`, call: controller.Perform,
+		},
+		{
+			name: "accept menu request", method: http.MethodPost,
+			url:     strings.Replace(opactionmenu.AcceptMenuRequest, "{piid}", mockPIID, 1),
+			request: `{"piid":"` + mockPIID + `"}`, call: controller.AcceptMenuRequest,
+		},
+		{
+			name: "decline menu request", method: http.MethodPost,
+			url:     strings.Replace(opactionmenu.DeclineMenuRequest, "{piid}", mockPIID, 1),
+			request: `{"piid":"` + mockPIID + `"}`, call: controller.DeclineMenuRequest,
+		},
+		{
+			name: "accept perform", method: http.MethodPost,
+			url:     strings.Replace(opactionmenu.AcceptPerform, "{piid}", mockPIID, 1),
+			request: `{"piid":"` + mockPIID + `"}`, call: controller.AcceptPerform,
+		},
+		{
+			name: "decline perform", method: http.MethodPost,
+			url:     strings.Replace(opactionmenu.DeclinePerform, "{piid}", mockPIID, 1),
+			request: `{"piid":"` + mockPIID + `"}`, call: controller.DeclinePerform,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			mockResponse := `{"menu":{"title":"Issuer bot"}}`
+			controller.httpClient = &mockHTTPClient{data: mockResponse, method: tc.method, url: mockAgentURL + tc.url}
+
+			resp := tc.call(&models.RequestEnvelope{Payload: []byte(tc.request)})
+			require.NotNil(t, resp)
+			require.Nil(t, resp.Error)
+			require.Equal(t, mockResponse, string(resp.Payload))
+		})
+	}
+}
@@ -21,6 +21,7 @@ import (
 
 	"github.com/hyperledger/aries-framework-go/cmd/aries-agent-mobile/pkg/api"
 	"github.com/hyperledger/aries-framework-go/cmd/aries-agent-mobile/pkg/wrappers/config"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/rest/actionmenu"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/rest/didexchange"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/rest/introduce"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/rest/issuecredential"
@@ -29,6 +30,7 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/controller/rest/messaging"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/rest/outofband"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/rest/presentproof"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/rest/trustping"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/rest/vdr"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/rest/verifiable"
 )
@@ -266,3 +268,23 @@ func (ar *Aries) GetKMSController() (api.KMSController, error) {
 
 	return &KMS{endpoints: endpoints, URL: ar.URL, Token: ar.Token, httpClient: &http.Client{}}, nil
 }
+
+// GetTrustPingController returns a TrustPing instance.
+func (ar *Aries) GetTrustPingController() (api.TrustPingController, error) {
+	endpoints, ok := ar.endpoints[trustping.OperationID]
+	if !ok {
+		return nil, fmt.Errorf("no endpoints found for controller [%s]", trustping.OperationID)
+	}
+
+	return &TrustPing{endpoints: endpoints, URL: ar.URL, Token: ar.Token, httpClient: &http.Client{}}, nil
+}
+
+// GetActionMenuController returns an ActionMenu instance.
+func (ar *Aries) GetActionMenuController() (api.ActionMenuController, error) {
+	endpoints, ok := ar.endpoints[actionmenu.OperationID]
+	if !ok {
+		return nil, fmt.Errorf("no endpoints found for controller [%s]", actionmenu.OperationID)
+	}
+
+	return &ActionMenu{endpoints: endpoints, URL: ar.URL, Token: ar.Token, httpClient: &http.Client{}}, nil
+}
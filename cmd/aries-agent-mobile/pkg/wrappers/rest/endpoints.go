@@ -9,6 +9,7 @@ package rest
 import (
 	"net/http"
 
+	cmdactionmenu "github.com/hyperledger/aries-framework-go/pkg/controller/command/actionmenu"
 	cmddidexch "github.com/hyperledger/aries-framework-go/pkg/controller/command/didexchange"
 	cmdintroduce "github.com/hyperledger/aries-framework-go/pkg/controller/command/introduce"
 	cmdisscred "github.com/hyperledger/aries-framework-go/pkg/controller/command/issuecredential"
@@ -17,8 +18,10 @@ import (
 	cmdmessaging "github.com/hyperledger/aries-framework-go/pkg/controller/command/messaging"
 	cmdoob "github.com/hyperledger/aries-framework-go/pkg/controller/command/outofband"
 	cmdpresproof "github.com/hyperledger/aries-framework-go/pkg/controller/command/presentproof"
+	cmdtrustping "github.com/hyperledger/aries-framework-go/pkg/controller/command/trustping"
 	cmdvdr "github.com/hyperledger/aries-framework-go/pkg/controller/command/vdr"
 	cmdverifiable "github.com/hyperledger/aries-framework-go/pkg/controller/command/verifiable"
+	opactionmenu "github.com/hyperledger/aries-framework-go/pkg/controller/rest/actionmenu"
 	opdidexch "github.com/hyperledger/aries-framework-go/pkg/controller/rest/didexchange"
 	opintroduce "github.com/hyperledger/aries-framework-go/pkg/controller/rest/introduce"
 	opisscred "github.com/hyperledger/aries-framework-go/pkg/controller/rest/issuecredential"
@@ -27,6 +30,7 @@ import (
 	opmessaging "github.com/hyperledger/aries-framework-go/pkg/controller/rest/messaging"
 	opoob "github.com/hyperledger/aries-framework-go/pkg/controller/rest/outofband"
 	oppresproof "github.com/hyperledger/aries-framework-go/pkg/controller/rest/presentproof"
+	optrustping "github.com/hyperledger/aries-framework-go/pkg/controller/rest/trustping"
 	opvdr "github.com/hyperledger/aries-framework-go/pkg/controller/rest/vdr"
 	opverifiable "github.com/hyperledger/aries-framework-go/pkg/controller/rest/verifiable"
 )
@@ -50,6 +54,8 @@ func getControllerEndpoints() map[string]map[string]*endpoint {
 	allEndpoints[opmessaging.MsgServiceOperationID] = getMessagingEndpoints()
 	allEndpoints[opoob.OperationID] = getOutOfBandEndpoints()
 	allEndpoints[opkms.KmsOperationID] = getKMSEndpoints()
+	allEndpoints[optrustping.OperationID] = getTrustPingEndpoints()
+	allEndpoints[opactionmenu.OperationID] = getActionMenuEndpoints()
 
 	return allEndpoints
 }
@@ -424,6 +430,14 @@ func getOutOfBandEndpoints() map[string]*endpoint {
 			Path:   opoob.ActionStop,
 			Method: http.MethodPost,
 		},
+		cmdoob.EncodeInvitation: {
+			Path:   opoob.EncodeInvitation,
+			Method: http.MethodPost,
+		},
+		cmdoob.DecodeInvitation: {
+			Path:   opoob.DecodeInvitation,
+			Method: http.MethodPost,
+		},
 	}
 }
 
@@ -439,3 +453,57 @@ func getKMSEndpoints() map[string]*endpoint {
 		},
 	}
 }
+
+func getTrustPingEndpoints() map[string]*endpoint {
+	return map[string]*endpoint{
+		cmdtrustping.PingCommandMethod: {
+			Path:   optrustping.PingPath,
+			Method: http.MethodPost,
+		},
+	}
+}
+
+func getActionMenuEndpoints() map[string]*endpoint {
+	return map[string]*endpoint{
+		cmdactionmenu.Actions: {
+			Path:   opactionmenu.Actions,
+			Method: http.MethodGet,
+		},
+		cmdactionmenu.PublishMenu: {
+			Path:   opactionmenu.PublishMenu,
+			Method: http.MethodPost,
+		},
+		cmdactionmenu.SendMenu: {
+			Path:   opactionmenu.SendMenu,
+			Method: http.MethodPost,
+		},
+		cmdactionmenu.RequestMenu: {
+			Path:   opactionmenu.RequestMenu,
+			Method: http.MethodPost,
+		},
+		cmdactionmenu.GetMenu: {
+			Path:   opactionmenu.GetMenu,
+			Method: http.MethodGet,
+		},
+		cmdactionmenu.Perform: {
+			Path:   opactionmenu.Perform,
+			Method: http.MethodPost,
+		},
+		cmdactionmenu.AcceptMenuRequest: {
+			Path:   opactionmenu.AcceptMenuRequest,
+			Method: http.MethodPost,
+		},
+		cmdactionmenu.DeclineMenuRequest: {
+			Path:   opactionmenu.DeclineMenuRequest,
+			Method: http.MethodPost,
+		},
+		cmdactionmenu.AcceptPerform: {
+			Path:   opactionmenu.AcceptPerform,
+			Method: http.MethodPost,
+		},
+		cmdactionmenu.DeclinePerform: {
+			Path:   opactionmenu.DeclinePerform,
+			Method: http.MethodPost,
+		},
+	}
+}
@@ -55,6 +55,16 @@ func (oob *OutOfBand) ActionStop(request *models.RequestEnvelope) *models.Respon
 	return oob.createRespEnvelope(request, outofband.ActionStop)
 }
 
+// EncodeInvitation encodes an invitation into an invitation URL or a QR code payload.
+func (oob *OutOfBand) EncodeInvitation(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return oob.createRespEnvelope(request, outofband.EncodeInvitation)
+}
+
+// DecodeInvitation decodes an invitation from an invitation URL or a QR code payload.
+func (oob *OutOfBand) DecodeInvitation(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return oob.createRespEnvelope(request, outofband.DecodeInvitation)
+}
+
 func (oob *OutOfBand) createRespEnvelope(request *models.RequestEnvelope, endpoint string) *models.ResponseEnvelope {
 	return exec(&restOperation{
 		url:        oob.URL,
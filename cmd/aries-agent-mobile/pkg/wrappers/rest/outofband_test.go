@@ -197,3 +197,45 @@ func TestOutOfBand_CreateRequest(t *testing.T) {
 		require.Equal(t, mockResponse, string(resp.Payload))
 	})
 }
+
+func TestOutOfBand_EncodeInvitation(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		controller := getOutOfBandController(t)
+
+		reqData := `{"base_url":"https://example.com","invitation":{"@id":"inv-id"},"qr":true}`
+		mockResponse := `{"url":"https://example.com?oob=eyJAaWQiOiJpbnYtaWQifQ"}`
+
+		controller.httpClient = &mockHTTPClient{
+			data:   mockResponse,
+			method: http.MethodPost, url: mockAgentURL + outofband.EncodeInvitation,
+		}
+
+		req := &models.RequestEnvelope{Payload: []byte(reqData)}
+		resp := controller.EncodeInvitation(req)
+
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, mockResponse, string(resp.Payload))
+	})
+}
+
+func TestOutOfBand_DecodeInvitation(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		controller := getOutOfBandController(t)
+
+		reqData := `{"url":"https://example.com?oob=eyJAaWQiOiJpbnYtaWQifQ"}`
+		mockResponse := `{"invitation":{"@id":"inv-id","@type":"","service":null,"handshake_protocols":null}}`
+
+		controller.httpClient = &mockHTTPClient{
+			data:   mockResponse,
+			method: http.MethodPost, url: mockAgentURL + outofband.DecodeInvitation,
+		}
+
+		req := &models.RequestEnvelope{Payload: []byte(reqData)}
+		resp := controller.DecodeInvitation(req)
+
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, mockResponse, string(resp.Payload))
+	})
+}
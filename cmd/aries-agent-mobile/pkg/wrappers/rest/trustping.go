@@ -0,0 +1,32 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package rest
+
+import (
+	"github.com/hyperledger/aries-framework-go/cmd/aries-agent-mobile/pkg/wrappers/models"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/command/trustping"
+)
+
+// TrustPing contains necessary fields to support its operations.
+type TrustPing struct {
+	httpClient httpClient
+	endpoints  map[string]*endpoint
+
+	URL   string
+	Token string
+}
+
+// Ping sends the ping to the connection and returns the round-trip time.
+func (tp *TrustPing) Ping(request *models.RequestEnvelope) *models.ResponseEnvelope {
+	return exec(&restOperation{
+		url:        tp.URL,
+		token:      tp.Token,
+		httpClient: tp.httpClient,
+		endpoint:   tp.endpoints[trustping.PingCommandMethod],
+		request:    request,
+	})
+}
@@ -0,0 +1,51 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package rest
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/cmd/aries-agent-mobile/pkg/wrappers/models"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/rest/trustping"
+)
+
+func getTrustPingController(t *testing.T) *TrustPing {
+	a, err := getAgent()
+	require.NotNil(t, a)
+	require.NoError(t, err)
+
+	controller, err := a.GetTrustPingController()
+	require.NoError(t, err)
+	require.NotNil(t, controller)
+
+	tp, ok := controller.(*TrustPing)
+	require.Equal(t, ok, true)
+
+	return tp
+}
+
+func TestTrustPing_Ping(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		controller := getTrustPingController(t)
+
+		mockResponse := `{"round_trip_time":1.5}`
+		controller.httpClient = &mockHTTPClient{
+			data:   mockResponse,
+			method: http.MethodPost, url: mockAgentURL + trustping.PingPath,
+		}
+
+		req := &models.RequestEnvelope{Payload: []byte(sampleConnRequest)}
+		resp := controller.Ping(req)
+
+		require.NotNil(t, resp)
+		require.Nil(t, resp.Error)
+		require.Equal(t, mockResponse, string(resp.Payload))
+	})
+}
@@ -9,6 +9,7 @@ import (
 	"crypto/subtle"
 	"errors"
 	"fmt"
+	"math"
 	"net/http"
 	"os"
 	"strconv"
@@ -26,8 +27,10 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/controller"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/command"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/messaging/msghandler"
+	mdissuecredential "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/middleware/issuecredential"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/transport"
 	arieshttp "github.com/hyperledger/aries-framework-go/pkg/didcomm/transport/http"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/transport/middleware"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/transport/ws"
 	"github.com/hyperledger/aries-framework-go/pkg/framework/aries"
 	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/defaults"
@@ -156,6 +159,136 @@ const (
 		" Refer https://github.com/hyperledger/aries-framework-go/blob/8449c727c7c44f47ed7c9f10f35f0cd051dcb4e9/pkg/framework/aries/framework.go#L165-L168." + // nolint: lll
 		" Alternatively, this can be set with the following environment variable: " + agentTransportReturnRouteEnvKey
 
+	// inbound max payload size flag.
+	agentInboundMaxPayloadSizeFlagName  = "inbound-max-payload-size"
+	agentInboundMaxPayloadSizeEnvKey    = "ARIESD_INBOUND_MAX_PAYLOAD_SIZE"
+	agentInboundMaxPayloadSizeFlagUsage = "Maximum size in bytes of the messages received by the inbound transports." +
+		" The size is not limited by the HTTP transport and is 32KB for the WebSocket transport by default." +
+		" Alternatively, this can be set with the following environment variable: " + agentInboundMaxPayloadSizeEnvKey
+
+	// inbound IP rate limit flag.
+	agentInboundIPRateLimitFlagName  = "inbound-ip-rate-limit"
+	agentInboundIPRateLimitEnvKey    = "ARIESD_INBOUND_IP_RATE_LIMIT"
+	agentInboundIPRateLimitFlagUsage = "Maximum number of requests per second accepted from a client IP address by" +
+		" the inbound transports (HTTP requests and WebSocket connections). The rate is not limited by default." +
+		" Alternatively, this can be set with the following environment variable: " + agentInboundIPRateLimitEnvKey
+
+	// inbound sender rate limit flag.
+	agentInboundSenderRateLimitFlagName  = "inbound-sender-rate-limit"
+	agentInboundSenderRateLimitEnvKey    = "ARIESD_INBOUND_SENDER_RATE_LIMIT"
+	agentInboundSenderRateLimitFlagUsage = "Maximum number of messages per second accepted from a sender key by the" +
+		" inbound transports. The rate is not limited by default." +
+		" Alternatively, this can be set with the following environment variable: " + agentInboundSenderRateLimitEnvKey
+
+	// inbound rate burst flag.
+	agentInboundRateBurstFlagName  = "inbound-rate-burst"
+	agentInboundRateBurstEnvKey    = "ARIESD_INBOUND_RATE_BURST"
+	agentInboundRateBurstFlagUsage = "Number of requests or messages accepted at once over the inbound rate limits." +
+		" Defaults to the rate limit." +
+		" Alternatively, this can be set with the following environment variable: " + agentInboundRateBurstEnvKey
+
+	// inbound content types flag.
+	agentInboundContentTypesFlagName  = "inbound-content-types"
+	agentInboundContentTypesEnvKey    = "ARIESD_INBOUND_CONTENT_TYPES"
+	agentInboundContentTypesFlagUsage = "Content types accepted by the HTTP inbound transport." +
+		" All the DIDComm envelope media types are accepted by default." +
+		" Alternatively, this can be set with the following environment variable (in CSV format): " +
+		agentInboundContentTypesEnvKey
+
+	// inbound CORS allowed origins flag.
+	agentInboundCORSOriginsFlagName  = "inbound-cors-origins"
+	agentInboundCORSOriginsEnvKey    = "ARIESD_INBOUND_CORS_ORIGINS"
+	agentInboundCORSOriginsFlagUsage = "Origins allowed to send cross-origin requests to the inbound transports." +
+		" All origins are allowed by default." +
+		" Alternatively, this can be set with the following environment variable (in CSV format): " +
+		agentInboundCORSOriginsEnvKey
+
+	// issuer verification method flag.
+	agentIssuerVerificationMethodFlagName  = "issuer-verification-method"
+	agentIssuerVerificationMethodEnvKey    = "ARIESD_ISSUER_VERIFICATION_METHOD"
+	agentIssuerVerificationMethodFlagUsage = "Verification method (DID URL) signing the linked data proof credentials" +
+		" requested over the issue credential protocol. Setting it enables the issuance of the credentials" +
+		" requested in the aries/ld-proof-vc-detail@v1.0 format once the request is accepted." +
+		" Alternatively, this can be set with the following environment variable: " +
+		agentIssuerVerificationMethodEnvKey
+
+	// issuer key ID flag.
+	agentIssuerKeyIDFlagName  = "issuer-key-id"
+	agentIssuerKeyIDEnvKey    = "ARIESD_ISSUER_KEY_ID"
+	agentIssuerKeyIDFlagUsage = "ID of the KMS key signing the issued credentials." +
+		" Defaults to the fragment of the issuer verification method." +
+		" Alternatively, this can be set with the following environment variable: " + agentIssuerKeyIDEnvKey
+
+	// issuer signature type flag.
+	agentIssuerSignatureTypeFlagName  = "issuer-signature-type"
+	agentIssuerSignatureTypeEnvKey    = "ARIESD_ISSUER_SIGNATURE_TYPE"
+	agentIssuerSignatureTypeFlagUsage = "Signature suite of the issued credentials." +
+		" Possible values [Ed25519Signature2018] [JsonWebSignature2020]. Defaults to Ed25519Signature2018." +
+		" Alternatively, this can be set with the following environment variable: " + agentIssuerSignatureTypeEnvKey
+
+	// issuer allowed types flag.
+	agentIssuerAllowedTypesFlagName  = "issuer-allowed-types"
+	agentIssuerAllowedTypesEnvKey    = "ARIESD_ISSUER_ALLOWED_TYPES"
+	agentIssuerAllowedTypesFlagUsage = "Credential types the issuer issues, any type is issued if not set." +
+		" Alternatively, this can be set with the following environment variable (in CSV format): " +
+		agentIssuerAllowedTypesEnvKey
+
+	// issuer allowed claims flag.
+	agentIssuerAllowedClaimsFlagName  = "issuer-allowed-claims"
+	agentIssuerAllowedClaimsEnvKey    = "ARIESD_ISSUER_ALLOWED_CLAIMS"
+	agentIssuerAllowedClaimsFlagUsage = "Claims of the credential subjects the issuer issues," +
+		" any claim is issued if not set." +
+		" Alternatively, this can be set with the following environment variable (in CSV format): " +
+		agentIssuerAllowedClaimsEnvKey
+
+	// issuer allowed fields flag.
+	agentIssuerAllowedFieldsFlagName  = "issuer-allowed-fields"
+	agentIssuerAllowedFieldsEnvKey    = "ARIESD_ISSUER_ALLOWED_FIELDS"
+	agentIssuerAllowedFieldsFlagUsage = "Optional credential fields (e.g. credentialSchema, evidence) the issuer" +
+		" issues, the credentials with other fields than @context, id, type, issuer, issuanceDate, expirationDate" +
+		" and credentialSubject are not issued if not set." +
+		" Alternatively, this can be set with the following environment variable (in CSV format): " +
+		agentIssuerAllowedFieldsEnvKey
+
+	// issuer subject binding flag.
+	agentIssuerSubjectBindingFlagName  = "issuer-subject-binding"
+	agentIssuerSubjectBindingEnvKey    = "ARIESD_ISSUER_SUBJECT_BINDING"
+	agentIssuerSubjectBindingFlagUsage = "Issue only the credentials whose subject is the DID of the connection" +
+		" the credential is requested over. Possible values [true] [false]. Defaults to false if not set." +
+		" Alternatively, this can be set with the following environment variable: " + agentIssuerSubjectBindingEnvKey
+
+	// issuer issuance date tolerance flag.
+	agentIssuerIssuanceDateToleranceFlagName  = "issuer-issuance-date-tolerance"
+	agentIssuerIssuanceDateToleranceEnvKey    = "ARIESD_ISSUER_ISSUANCE_DATE_TOLERANCE"
+	agentIssuerIssuanceDateToleranceFlagUsage = "Issue only the credentials whose issuance date is at most this" +
+		" duration (e.g. 10m) away from the time the credential is issued, any issuance date is issued if not set." +
+		" Alternatively, this can be set with the following environment variable: " +
+		agentIssuerIssuanceDateToleranceEnvKey
+
+	// issuer max validity flag.
+	agentIssuerMaxValidityFlagName  = "issuer-max-validity"
+	agentIssuerMaxValidityEnvKey    = "ARIESD_ISSUER_MAX_VALIDITY"
+	agentIssuerMaxValidityFlagUsage = "Issue only the credentials expiring at most this duration (e.g. 8760h)" +
+		" after their issuance date, any expiration date (or none) is issued if not set." +
+		" Alternatively, this can be set with the following environment variable: " + agentIssuerMaxValidityEnvKey
+
+	// issuer without credential ID flag.
+	agentIssuerWithoutCredentialIDFlagName  = "issuer-without-credential-id"
+	agentIssuerWithoutCredentialIDEnvKey    = "ARIESD_ISSUER_WITHOUT_CREDENTIAL_ID"
+	agentIssuerWithoutCredentialIDFlagUsage = "Issue only the credentials without an id chosen by the holder." +
+		" Possible values [true] [false]. Defaults to false if not set." +
+		" Alternatively, this can be set with the following environment variable: " +
+		agentIssuerWithoutCredentialIDEnvKey
+
+	// issuer auto accept flag.
+	agentIssuerAutoAcceptFlagName  = "issuer-auto-accept"
+	agentIssuerAutoAcceptEnvKey    = "ARIESD_ISSUER_AUTO_ACCEPT"
+	agentIssuerAutoAcceptFlagUsage = "Accept the requests of linked data proof credentials approved by the issuer" +
+		" settings without raising action events, the other requests raise action events as usual." +
+		" Requires the issuer allowed types and subject binding." +
+		" Possible values [true] [false]. Defaults to false if not set." +
+		" Alternatively, this can be set with the following environment variable: " + agentIssuerAutoAcceptEnvKey
+
 	httpProtocol      = "http"
 	websocketProtocol = "ws"
 
@@ -178,6 +311,8 @@ type agentParameters struct {
 	autoAccept                                     bool
 	msgHandler                                     command.MessageHandler
 	dbParam                                        *dbParam
+	inboundParam                                   *inboundParam
+	issuerParam                                    *issuerParam
 }
 
 type dbParam struct {
@@ -186,6 +321,32 @@ type dbParam struct {
 	timeout uint64
 }
 
+// inboundParam holds the limits and the middlewares settings of the inbound transports, zero values are not set.
+type inboundParam struct {
+	maxPayloadSize  int64
+	ipRateLimit     float64
+	senderRateLimit float64
+	rateBurst       int
+	contentTypes    []string
+	corsOrigins     []string
+}
+
+// issuerParam holds the settings of the linked data proof credentials issuer, it's disabled without
+// a verification method.
+type issuerParam struct {
+	verificationMethod string
+	keyID              string
+	signatureType      string
+	allowedTypes       []string
+	allowedClaims      []string
+	allowedFields      []string
+	subjectBinding     bool
+	dateTolerance      time.Duration
+	maxValidity        time.Duration
+	noCredentialID     bool
+	autoAccept         bool
+}
+
 // nolint:gochecknoglobals
 var supportedStorageProviders = map[string]func(prefix string) (storage.Provider, error){
 	databaseTypeMemOption: func(_ string) (storage.Provider, error) { // nolint:unparam
@@ -264,6 +425,16 @@ func createStartCMD(server server) *cobra.Command { //nolint: funlen, gocyclo
 				return err
 			}
 
+			inboundParam, err := getInboundParam(cmd)
+			if err != nil {
+				return err
+			}
+
+			issuerParam, err := getIssuerParam(cmd)
+			if err != nil {
+				return err
+			}
+
 			defaultLabel, err := getUserSetVar(cmd, agentDefaultLabelFlagName, agentDefaultLabelEnvKey, true)
 			if err != nil {
 				return err
@@ -313,6 +484,8 @@ func createStartCMD(server server) *cobra.Command { //nolint: funlen, gocyclo
 				inboundHostInternals: inboundHosts,
 				inboundHostExternals: inboundHostExternals,
 				dbParam:              dbParam,
+				inboundParam:         inboundParam,
+				issuerParam:          issuerParam,
 				defaultLabel:         defaultLabel,
 				webhookURLs:          webhookURLs,
 				httpResolvers:        httpResolvers,
@@ -362,6 +535,238 @@ func getDBParam(cmd *cobra.Command) (*dbParam, error) {
 	return dbParam, nil
 }
 
+func getInboundParam(cmd *cobra.Command) (*inboundParam, error) { // nolint:gocyclo
+	param := &inboundParam{}
+
+	maxPayloadSize, err := getUserSetVar(cmd, agentInboundMaxPayloadSizeFlagName, agentInboundMaxPayloadSizeEnvKey,
+		true)
+	if err != nil {
+		return nil, err
+	}
+
+	if maxPayloadSize != "" {
+		param.maxPayloadSize, err = strconv.ParseInt(maxPayloadSize, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse inbound max payload size %s: %w", maxPayloadSize, err)
+		}
+	}
+
+	ipRateLimit, err := getUserSetVar(cmd, agentInboundIPRateLimitFlagName, agentInboundIPRateLimitEnvKey, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if ipRateLimit != "" {
+		param.ipRateLimit, err = strconv.ParseFloat(ipRateLimit, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse inbound IP rate limit %s: %w", ipRateLimit, err)
+		}
+	}
+
+	senderRateLimit, err := getUserSetVar(cmd, agentInboundSenderRateLimitFlagName,
+		agentInboundSenderRateLimitEnvKey, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if senderRateLimit != "" {
+		param.senderRateLimit, err = strconv.ParseFloat(senderRateLimit, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse inbound sender rate limit %s: %w", senderRateLimit, err)
+		}
+	}
+
+	rateBurst, err := getUserSetVar(cmd, agentInboundRateBurstFlagName, agentInboundRateBurstEnvKey, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if rateBurst != "" {
+		param.rateBurst, err = strconv.Atoi(rateBurst)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse inbound rate burst %s: %w", rateBurst, err)
+		}
+	}
+
+	param.contentTypes, err = getUserSetVars(cmd, agentInboundContentTypesFlagName, agentInboundContentTypesEnvKey,
+		true)
+	if err != nil {
+		return nil, err
+	}
+
+	param.corsOrigins, err = getUserSetVars(cmd, agentInboundCORSOriginsFlagName, agentInboundCORSOriginsEnvKey, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return param, nil
+}
+
+func getIssuerParam(cmd *cobra.Command) (*issuerParam, error) { // nolint:gocyclo
+	param := &issuerParam{}
+
+	var err error
+
+	param.verificationMethod, err = getUserSetVar(cmd, agentIssuerVerificationMethodFlagName,
+		agentIssuerVerificationMethodEnvKey, true)
+	if err != nil {
+		return nil, err
+	}
+
+	param.keyID, err = getUserSetVar(cmd, agentIssuerKeyIDFlagName, agentIssuerKeyIDEnvKey, true)
+	if err != nil {
+		return nil, err
+	}
+
+	param.signatureType, err = getUserSetVar(cmd, agentIssuerSignatureTypeFlagName, agentIssuerSignatureTypeEnvKey,
+		true)
+	if err != nil {
+		return nil, err
+	}
+
+	switch param.signatureType {
+	case "", mdissuecredential.Ed25519Signature2018, mdissuecredential.JSONWebSignature2020:
+	default:
+		return nil, fmt.Errorf("issuer signature type %s not supported", param.signatureType)
+	}
+
+	param.allowedTypes, err = getUserSetVars(cmd, agentIssuerAllowedTypesFlagName, agentIssuerAllowedTypesEnvKey,
+		true)
+	if err != nil {
+		return nil, err
+	}
+
+	param.allowedClaims, err = getUserSetVars(cmd, agentIssuerAllowedClaimsFlagName, agentIssuerAllowedClaimsEnvKey,
+		true)
+	if err != nil {
+		return nil, err
+	}
+
+	param.allowedFields, err = getUserSetVars(cmd, agentIssuerAllowedFieldsFlagName, agentIssuerAllowedFieldsEnvKey,
+		true)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = getIssuerPolicyParam(cmd, param); err != nil {
+		return nil, err
+	}
+
+	param.autoAccept, err = getBoolVar(cmd, agentIssuerAutoAcceptFlagName, agentIssuerAutoAcceptEnvKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse issuer auto accept: %w", err)
+	}
+
+	if param.verificationMethod == "" && (param.keyID != "" || param.signatureType != "" ||
+		len(param.allowedTypes) > 0 || len(param.allowedClaims) > 0 || len(param.allowedFields) > 0 ||
+		param.subjectBinding || param.dateTolerance != 0 || param.maxValidity != 0 || param.noCredentialID ||
+		param.autoAccept) {
+		return nil, errors.New("issuer verification method not provided")
+	}
+
+	if param.autoAccept && (len(param.allowedTypes) == 0 || !param.subjectBinding) {
+		return nil, errors.New("issuer auto accept requires the issuer allowed types and subject binding")
+	}
+
+	return param, nil
+}
+
+// getIssuerPolicyParam sets the settings of the issuer policy checking the subjects, the dates and the id of
+// the requested credentials.
+func getIssuerPolicyParam(cmd *cobra.Command, param *issuerParam) error {
+	var err error
+
+	param.subjectBinding, err = getBoolVar(cmd, agentIssuerSubjectBindingFlagName, agentIssuerSubjectBindingEnvKey)
+	if err != nil {
+		return fmt.Errorf("failed to parse issuer subject binding: %w", err)
+	}
+
+	param.dateTolerance, err = getDurationVar(cmd, agentIssuerIssuanceDateToleranceFlagName,
+		agentIssuerIssuanceDateToleranceEnvKey)
+	if err != nil {
+		return fmt.Errorf("failed to parse issuer issuance date tolerance: %w", err)
+	}
+
+	param.maxValidity, err = getDurationVar(cmd, agentIssuerMaxValidityFlagName, agentIssuerMaxValidityEnvKey)
+	if err != nil {
+		return fmt.Errorf("failed to parse issuer max validity: %w", err)
+	}
+
+	param.noCredentialID, err = getBoolVar(cmd, agentIssuerWithoutCredentialIDFlagName,
+		agentIssuerWithoutCredentialIDEnvKey)
+	if err != nil {
+		return fmt.Errorf("failed to parse issuer without credential id: %w", err)
+	}
+
+	return nil
+}
+
+// options returns the framework option enabling the credentials issuer, none if the issuer is not configured.
+func (p *issuerParam) options() []aries.Option {
+	if p == nil || p.verificationMethod == "" {
+		return nil
+	}
+
+	var policyOpts []mdissuecredential.PolicyOption
+
+	if len(p.allowedTypes) > 0 {
+		policyOpts = append(policyOpts, mdissuecredential.WithAllowedTypes(p.allowedTypes...))
+	}
+
+	if len(p.allowedClaims) > 0 {
+		policyOpts = append(policyOpts, mdissuecredential.WithAllowedClaims(p.allowedClaims...))
+	}
+
+	if len(p.allowedFields) > 0 {
+		policyOpts = append(policyOpts, mdissuecredential.WithAllowedFields(p.allowedFields...))
+	}
+
+	if p.subjectBinding {
+		policyOpts = append(policyOpts, mdissuecredential.WithSubjectBinding())
+	}
+
+	if p.dateTolerance != 0 {
+		policyOpts = append(policyOpts, mdissuecredential.WithIssuanceDateTolerance(p.dateTolerance))
+	}
+
+	if p.maxValidity != 0 {
+		policyOpts = append(policyOpts, mdissuecredential.WithMaxValidity(p.maxValidity))
+	}
+
+	if p.noCredentialID {
+		policyOpts = append(policyOpts, mdissuecredential.WithoutCredentialID())
+	}
+
+	issuerOpts := []mdissuecredential.IssuerOpt{
+		mdissuecredential.WithIssuerPolicy(mdissuecredential.NewIssuerPolicy(policyOpts...)),
+	}
+
+	if p.keyID != "" {
+		issuerOpts = append(issuerOpts, mdissuecredential.WithKeyID(p.keyID))
+	}
+
+	if p.signatureType != "" {
+		issuerOpts = append(issuerOpts, mdissuecredential.WithSignatureType(p.signatureType))
+	}
+
+	opts := []aries.Option{aries.WithLDProofCredentialIssuer(p.verificationMethod, issuerOpts...)}
+
+	if p.autoAccept {
+		opts = append(opts, aries.WithLDProofCredentialAutoAccept())
+	}
+
+	return opts
+}
+
+// burst returns the burst of the rate limit, the rate (rounded up) if the burst is not set.
+func (p *inboundParam) burst(rate float64) int {
+	if p.rateBurst > 0 {
+		return p.rateBurst
+	}
+
+	return int(math.Ceil(rate))
+}
+
 func getAutoAcceptValue(cmd *cobra.Command) (bool, error) {
 	v, err := getUserSetVar(cmd, agentAutoAcceptFlagName, agentAutoAcceptEnvKey, true)
 	if err != nil {
@@ -375,6 +780,24 @@ func getAutoAcceptValue(cmd *cobra.Command) (bool, error) {
 	return strconv.ParseBool(v)
 }
 
+func getBoolVar(cmd *cobra.Command, flagName, envKey string) (bool, error) {
+	v, err := getUserSetVar(cmd, flagName, envKey, true)
+	if err != nil || v == "" {
+		return false, err
+	}
+
+	return strconv.ParseBool(v)
+}
+
+func getDurationVar(cmd *cobra.Command, flagName, envKey string) (time.Duration, error) {
+	v, err := getUserSetVar(cmd, flagName, envKey, true)
+	if err != nil || v == "" {
+		return 0, err
+	}
+
+	return time.ParseDuration(v)
+}
+
 func createFlags(startCmd *cobra.Command) {
 	// agent host flag
 	startCmd.Flags().StringP(agentHostFlagName, agentHostFlagShorthand, "", agentHostFlagUsage)
@@ -430,6 +853,60 @@ func createFlags(startCmd *cobra.Command) {
 
 	// db timeout
 	startCmd.Flags().StringP(databaseTimeoutFlagName, "", "", databaseTimeoutFlagUsage)
+
+	// inbound max payload size
+	startCmd.Flags().StringP(agentInboundMaxPayloadSizeFlagName, "", "", agentInboundMaxPayloadSizeFlagUsage)
+
+	// inbound IP rate limit
+	startCmd.Flags().StringP(agentInboundIPRateLimitFlagName, "", "", agentInboundIPRateLimitFlagUsage)
+
+	// inbound sender rate limit
+	startCmd.Flags().StringP(agentInboundSenderRateLimitFlagName, "", "", agentInboundSenderRateLimitFlagUsage)
+
+	// inbound rate burst
+	startCmd.Flags().StringP(agentInboundRateBurstFlagName, "", "", agentInboundRateBurstFlagUsage)
+
+	// inbound content types
+	startCmd.Flags().StringSliceP(agentInboundContentTypesFlagName, "", []string{},
+		agentInboundContentTypesFlagUsage)
+
+	// inbound CORS origins
+	startCmd.Flags().StringSliceP(agentInboundCORSOriginsFlagName, "", []string{}, agentInboundCORSOriginsFlagUsage)
+
+	// issuer verification method
+	startCmd.Flags().StringP(agentIssuerVerificationMethodFlagName, "", "", agentIssuerVerificationMethodFlagUsage)
+
+	// issuer key ID
+	startCmd.Flags().StringP(agentIssuerKeyIDFlagName, "", "", agentIssuerKeyIDFlagUsage)
+
+	// issuer signature type
+	startCmd.Flags().StringP(agentIssuerSignatureTypeFlagName, "", "", agentIssuerSignatureTypeFlagUsage)
+
+	// issuer allowed types
+	startCmd.Flags().StringSliceP(agentIssuerAllowedTypesFlagName, "", []string{}, agentIssuerAllowedTypesFlagUsage)
+
+	// issuer allowed claims
+	startCmd.Flags().StringSliceP(agentIssuerAllowedClaimsFlagName, "", []string{},
+		agentIssuerAllowedClaimsFlagUsage)
+
+	// issuer allowed fields
+	startCmd.Flags().StringSliceP(agentIssuerAllowedFieldsFlagName, "", []string{}, agentIssuerAllowedFieldsFlagUsage)
+
+	// issuer subject binding
+	startCmd.Flags().StringP(agentIssuerSubjectBindingFlagName, "", "", agentIssuerSubjectBindingFlagUsage)
+
+	// issuer issuance date tolerance
+	startCmd.Flags().StringP(agentIssuerIssuanceDateToleranceFlagName, "", "",
+		agentIssuerIssuanceDateToleranceFlagUsage)
+
+	// issuer max validity
+	startCmd.Flags().StringP(agentIssuerMaxValidityFlagName, "", "", agentIssuerMaxValidityFlagUsage)
+
+	// issuer without credential ID
+	startCmd.Flags().StringP(agentIssuerWithoutCredentialIDFlagName, "", "", agentIssuerWithoutCredentialIDFlagUsage)
+
+	// issuer auto accept
+	startCmd.Flags().StringP(agentIssuerAutoAcceptFlagName, "", "", agentIssuerAutoAcceptFlagUsage)
 }
 
 func getUserSetVar(cmd *cobra.Command, flagName, envKey string, isOptional bool) (string, error) {
@@ -532,7 +1009,7 @@ func getOutboundTransportOpts(outboundTransports []string) ([]aries.Option, erro
 }
 
 func getInboundTransportOpts(inboundHostInternals, inboundHostExternals []string, certFile,
-	keyFile string) ([]aries.Option, error) {
+	keyFile string, param *inboundParam) ([]aries.Option, error) {
 	internalHost, err := getInboundSchemeToURLMap(inboundHostInternals)
 	if err != nil {
 		return nil, fmt.Errorf("inbound internal host : %w", err)
@@ -545,12 +1022,19 @@ func getInboundTransportOpts(inboundHostInternals, inboundHostExternals []string
 
 	var opts []aries.Option
 
+	httpOpts, wsOpts, poolOpts := getInboundMiddlewareOpts(param)
+
+	if len(poolOpts) > 0 {
+		opts = append(opts, aries.WithWebSocketPoolOptions(poolOpts...))
+	}
+
 	for scheme, host := range internalHost {
 		switch scheme {
 		case httpProtocol:
-			opts = append(opts, defaults.WithInboundHTTPAddr(host, externalHost[scheme], certFile, keyFile))
+			opts = append(opts, defaults.WithInboundHTTPAddr(host, externalHost[scheme], certFile, keyFile,
+				httpOpts...))
 		case websocketProtocol:
-			opts = append(opts, defaults.WithInboundWSAddr(host, externalHost[scheme], certFile, keyFile))
+			opts = append(opts, defaults.WithInboundWSAddr(host, externalHost[scheme], certFile, keyFile, wsOpts...))
 		default:
 			return nil, fmt.Errorf("inbound transport [%s] not supported", scheme)
 		}
@@ -559,6 +1043,53 @@ func getInboundTransportOpts(inboundHostInternals, inboundHostExternals []string
 	return opts, nil
 }
 
+// getInboundMiddlewareOpts returns the options of the HTTP and WebSocket inbound transports and of the websocket
+// connection pool of the framework, the transports share the rate limiters so the limits apply to the agent as a whole.
+func getInboundMiddlewareOpts(param *inboundParam) ([]arieshttp.InboundHTTPOpt, []ws.Option, []ws.PoolOption) {
+	if param == nil {
+		return nil, nil, nil
+	}
+
+	var (
+		httpOpts []arieshttp.InboundHTTPOpt
+		wsOpts   []ws.Option
+		poolOpts []ws.PoolOption
+	)
+
+	if len(param.corsOrigins) > 0 {
+		corsOpts := cors.Options{AllowedOrigins: param.corsOrigins}
+
+		httpOpts = append(httpOpts, arieshttp.WithInboundCORS(corsOpts))
+		wsOpts = append(wsOpts, ws.WithMiddleware(middleware.CORS(corsOpts)))
+	}
+
+	if param.ipRateLimit > 0 {
+		ipRateLimit := middleware.RateLimitByIP(
+			middleware.NewRateLimiter(param.ipRateLimit, param.burst(param.ipRateLimit)))
+
+		httpOpts = append(httpOpts, arieshttp.WithInboundMiddleware(ipRateLimit))
+		wsOpts = append(wsOpts, ws.WithMiddleware(ipRateLimit))
+	}
+
+	if param.senderRateLimit > 0 {
+		limiter := middleware.NewRateLimiter(param.senderRateLimit, param.burst(param.senderRateLimit))
+
+		httpOpts = append(httpOpts, arieshttp.WithInboundSenderRateLimiter(limiter))
+		poolOpts = append(poolOpts, ws.WithSenderRateLimiter(limiter))
+	}
+
+	if param.maxPayloadSize > 0 {
+		httpOpts = append(httpOpts, arieshttp.WithInboundMaxPayloadSize(param.maxPayloadSize))
+		poolOpts = append(poolOpts, ws.WithReadLimit(param.maxPayloadSize))
+	}
+
+	if len(param.contentTypes) > 0 {
+		httpOpts = append(httpOpts, arieshttp.WithInboundContentTypes(param.contentTypes...))
+	}
+
+	return httpOpts, wsOpts, poolOpts
+}
+
 func getInboundSchemeToURLMap(schemeHostStr []string) (map[string]string, error) {
 	const validSliceLen = 2
 
@@ -681,7 +1212,7 @@ func createAriesAgent(parameters *agentParameters) (*context.Provider, error) {
 	}
 
 	inboundTransportOpt, err := getInboundTransportOpts(parameters.inboundHostInternals,
-		parameters.inboundHostExternals, parameters.tlsCertFile, parameters.tlsKeyFile)
+		parameters.inboundHostExternals, parameters.tlsCertFile, parameters.tlsKeyFile, parameters.inboundParam)
 	if err != nil {
 		return nil, fmt.Errorf("failed to start aries agent rest on port [%s], failed to inbound tranpsort opt : %w",
 			parameters.host, err)
@@ -704,6 +1235,7 @@ func createAriesAgent(parameters *agentParameters) (*context.Provider, error) {
 	}
 
 	opts = append(opts, outboundTransportOpts...)
+	opts = append(opts, parameters.issuerParam.options()...)
 	opts = append(opts, aries.WithMessageServiceProvider(parameters.msgHandler))
 
 	framework, err := aries.New(opts...)
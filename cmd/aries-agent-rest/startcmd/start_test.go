@@ -651,6 +651,220 @@ func TestStartAriesWithInboundTransport(t *testing.T) {
 	})
 }
 
+func TestStartCmdWithInboundLimits(t *testing.T) {
+	baseArgs := []string{
+		"--" + agentHostFlagName,
+		randomURL(),
+		"--" + agentInboundHostFlagName,
+		httpProtocol + "@" + randomURL(),
+		"--" + databaseTypeFlagName,
+		databaseTypeMemOption,
+	}
+
+	t.Run("valid inbound limits", func(t *testing.T) {
+		startCmd, err := Cmd(&mockServer{})
+		require.NoError(t, err)
+
+		startCmd.SetArgs(append(baseArgs,
+			"--"+agentInboundMaxPayloadSizeFlagName, "65536",
+			"--"+agentInboundIPRateLimitFlagName, "10",
+			"--"+agentInboundSenderRateLimitFlagName, "0.5",
+			"--"+agentInboundRateBurstFlagName, "20",
+			"--"+agentInboundContentTypesFlagName, "application/didcomm-encrypted+json",
+			"--"+agentInboundCORSOriginsFlagName, "https://example.com",
+		))
+
+		require.NoError(t, startCmd.Execute())
+	})
+
+	for _, flag := range []string{
+		agentInboundMaxPayloadSizeFlagName, agentInboundIPRateLimitFlagName,
+		agentInboundSenderRateLimitFlagName, agentInboundRateBurstFlagName,
+	} {
+		t.Run("invalid "+flag, func(t *testing.T) {
+			startCmd, err := Cmd(&mockServer{})
+			require.NoError(t, err)
+
+			startCmd.SetArgs(append(baseArgs, "--"+flag, "invalid"))
+
+			err = startCmd.Execute()
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "failed to parse inbound")
+		})
+	}
+}
+
+func TestStartCmdWithIssuer(t *testing.T) {
+	baseArgs := []string{
+		"--" + agentHostFlagName,
+		randomURL(),
+		"--" + agentInboundHostFlagName,
+		httpProtocol + "@" + randomURL(),
+		"--" + databaseTypeFlagName,
+		databaseTypeMemOption,
+	}
+
+	t.Run("valid issuer settings", func(t *testing.T) {
+		startCmd, err := Cmd(&mockServer{})
+		require.NoError(t, err)
+
+		startCmd.SetArgs(append(baseArgs,
+			"--"+agentAutoAcceptFlagName, "true",
+			"--"+agentIssuerVerificationMethodFlagName, "did:example:issuer#key-1",
+			"--"+agentIssuerKeyIDFlagName, "key-1",
+			"--"+agentIssuerSignatureTypeFlagName, "JsonWebSignature2020",
+			"--"+agentIssuerAllowedTypesFlagName, "UniversityDegreeCredential",
+			"--"+agentIssuerAllowedClaimsFlagName, "name,degree",
+			"--"+agentIssuerAllowedFieldsFlagName, "credentialSchema,evidence",
+			"--"+agentIssuerSubjectBindingFlagName, "true",
+			"--"+agentIssuerIssuanceDateToleranceFlagName, "10m",
+			"--"+agentIssuerMaxValidityFlagName, "8760h",
+			"--"+agentIssuerWithoutCredentialIDFlagName, "true",
+			"--"+agentIssuerAutoAcceptFlagName, "true",
+		))
+
+		require.NoError(t, startCmd.Execute())
+	})
+
+	tests := []struct {
+		name string
+		args []string
+		err  string
+	}{{
+		name: "unsupported signature type",
+		args: []string{
+			"--" + agentIssuerVerificationMethodFlagName, "did:example:issuer#key-1",
+			"--" + agentIssuerSignatureTypeFlagName, "BbsBlsSignature2020",
+		},
+		err: "issuer signature type BbsBlsSignature2020 not supported",
+	}, {
+		name: "invalid subject binding",
+		args: []string{
+			"--" + agentIssuerVerificationMethodFlagName, "did:example:issuer#key-1",
+			"--" + agentIssuerSubjectBindingFlagName, "invalid",
+		},
+		err: "failed to parse issuer subject binding",
+	}, {
+		name: "invalid issuance date tolerance",
+		args: []string{
+			"--" + agentIssuerVerificationMethodFlagName, "did:example:issuer#key-1",
+			"--" + agentIssuerIssuanceDateToleranceFlagName, "invalid",
+		},
+		err: "failed to parse issuer issuance date tolerance",
+	}, {
+		name: "invalid max validity",
+		args: []string{
+			"--" + agentIssuerVerificationMethodFlagName, "did:example:issuer#key-1",
+			"--" + agentIssuerMaxValidityFlagName, "invalid",
+		},
+		err: "failed to parse issuer max validity",
+	}, {
+		name: "invalid without credential id",
+		args: []string{
+			"--" + agentIssuerVerificationMethodFlagName, "did:example:issuer#key-1",
+			"--" + agentIssuerWithoutCredentialIDFlagName, "invalid",
+		},
+		err: "failed to parse issuer without credential id",
+	}, {
+		name: "invalid auto accept",
+		args: []string{
+			"--" + agentIssuerVerificationMethodFlagName, "did:example:issuer#key-1",
+			"--" + agentIssuerAutoAcceptFlagName, "invalid",
+		},
+		err: "failed to parse issuer auto accept",
+	}, {
+		name: "auto accept without verification method",
+		args: []string{"--" + agentIssuerAutoAcceptFlagName, "true"},
+		err:  "issuer verification method not provided",
+	}, {
+		name: "auto accept without allowed types",
+		args: []string{
+			"--" + agentIssuerVerificationMethodFlagName, "did:example:issuer#key-1",
+			"--" + agentIssuerSubjectBindingFlagName, "true",
+			"--" + agentIssuerAutoAcceptFlagName, "true",
+		},
+		err: "issuer auto accept requires the issuer allowed types and subject binding",
+	}, {
+		name: "auto accept without subject binding",
+		args: []string{
+			"--" + agentIssuerVerificationMethodFlagName, "did:example:issuer#key-1",
+			"--" + agentIssuerAllowedTypesFlagName, "UniversityDegreeCredential",
+			"--" + agentIssuerAutoAcceptFlagName, "true",
+		},
+		err: "issuer auto accept requires the issuer allowed types and subject binding",
+	}, {
+		name: "missing verification method",
+		args: []string{"--" + agentIssuerAllowedTypesFlagName, "UniversityDegreeCredential"},
+		err:  "issuer verification method not provided",
+	}}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			startCmd, err := Cmd(&mockServer{})
+			require.NoError(t, err)
+
+			startCmd.SetArgs(append(baseArgs, tc.args...))
+
+			err = startCmd.Execute()
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.err)
+		})
+	}
+}
+
+func TestIssuerParamOptions(t *testing.T) {
+	var param *issuerParam
+	require.Empty(t, param.options())
+	require.Empty(t, (&issuerParam{subjectBinding: true}).options())
+
+	param = &issuerParam{
+		verificationMethod: "did:example:issuer#key-1",
+		keyID:              "key-1",
+		signatureType:      "Ed25519Signature2018",
+		allowedTypes:       []string{"UniversityDegreeCredential"},
+		allowedClaims:      []string{"name"},
+		allowedFields:      []string{"evidence"},
+		subjectBinding:     true,
+		dateTolerance:      10 * time.Minute,
+		maxValidity:        24 * time.Hour,
+		noCredentialID:     true,
+	}
+	require.Len(t, param.options(), 1)
+
+	param.autoAccept = true
+	require.Len(t, param.options(), 2)
+}
+
+func TestGetInboundMiddlewareOpts(t *testing.T) {
+	httpOpts, wsOpts, poolOpts := getInboundMiddlewareOpts(nil)
+	require.Empty(t, httpOpts)
+	require.Empty(t, wsOpts)
+	require.Empty(t, poolOpts)
+
+	httpOpts, wsOpts, poolOpts = getInboundMiddlewareOpts(&inboundParam{})
+	require.Empty(t, httpOpts)
+	require.Empty(t, wsOpts)
+	require.Empty(t, poolOpts)
+
+	param := &inboundParam{
+		maxPayloadSize:  1024,
+		ipRateLimit:     1.5,
+		senderRateLimit: 1,
+		contentTypes:    []string{"application/didcomm-encrypted+json"},
+		corsOrigins:     []string{"https://example.com"},
+	}
+
+	httpOpts, wsOpts, poolOpts = getInboundMiddlewareOpts(param)
+	require.Len(t, httpOpts, 5)
+	require.Len(t, wsOpts, 2)
+	require.Len(t, poolOpts, 2)
+	require.Equal(t, 2, param.burst(param.ipRateLimit))
+
+	param.rateBurst = 5
+	require.Equal(t, 5, param.burst(param.ipRateLimit))
+}
+
 func TestStartAriesWithAutoAccept(t *testing.T) {
 	t.Run("start aries with auto accept success", func(t *testing.T) {
 		testHostURL := randomURL()
@@ -0,0 +1,173 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package actionmenu
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/actionmenu"
+)
+
+type (
+	// Menu is the menu of the actions the responder offers to the requester.
+	Menu = actionmenu.Menu
+	// MenuOption is the action of the menu.
+	MenuOption = actionmenu.MenuOption
+	// Form describes the parameters of the action the requester fills in before performing it.
+	Form = actionmenu.Form
+	// FormParam is the parameter of the form.
+	FormParam = actionmenu.FormParam
+	// Action contains helpful information about action.
+	Action = actionmenu.Action
+)
+
+// Provider contains dependencies for the action menu protocol and is typically created by using aries.Context().
+type Provider interface {
+	Service(id string) (interface{}, error)
+}
+
+// ProtocolService defines the action menu service.
+type ProtocolService interface {
+	service.DIDComm
+	PublishMenu(connectionID string, menu *actionmenu.Menu) error
+	PublishedMenu(connectionID string) (*actionmenu.Menu, error)
+	SendMenu(connectionID string, menu *actionmenu.Menu) error
+	RequestMenu(connectionID string) error
+	Menu(connectionID string) (*actionmenu.Menu, error)
+	Perform(connectionID, name string, params map[string]string) error
+	Actions() ([]actionmenu.Action, error)
+	ActionContinue(piID string, opt actionmenu.Opt) error
+	ActionStop(piID string, err error) error
+}
+
+// Client enable access to action menu API. The menu requests and the performs received from the connections
+// are delivered as the action events, the received menus are reported with the message events.
+type Client struct {
+	service.Event
+	service ProtocolService
+}
+
+// New return new instance of the action menu client.
+func New(ctx Provider) (*Client, error) {
+	raw, err := ctx.Service(actionmenu.ActionMenu)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create action menu service: %w", err)
+	}
+
+	svc, ok := raw.(ProtocolService)
+	if !ok {
+		return nil, errors.New("cast service to action menu service failed")
+	}
+
+	return &Client{
+		Event:   svc,
+		service: svc,
+	}, nil
+}
+
+// PublishMenu publishes the menu for the connection. The published menu is sent to the connection
+// when it requests the menu.
+func (c *Client) PublishMenu(connectionID string, menu *Menu) error {
+	if err := c.service.PublishMenu(connectionID, menu); err != nil {
+		return fmt.Errorf("action menu client - publish menu: %w", err)
+	}
+
+	return nil
+}
+
+// PublishedMenu returns the menu published for the connection.
+func (c *Client) PublishedMenu(connectionID string) (*Menu, error) {
+	menu, err := c.service.PublishedMenu(connectionID)
+	if err != nil {
+		return nil, fmt.Errorf("action menu client - published menu: %w", err)
+	}
+
+	return menu, nil
+}
+
+// SendMenu publishes the menu for the connection and sends it to the connection right away.
+func (c *Client) SendMenu(connectionID string, menu *Menu) error {
+	if err := c.service.SendMenu(connectionID, menu); err != nil {
+		return fmt.Errorf("action menu client - send menu: %w", err)
+	}
+
+	return nil
+}
+
+// RequestMenu requests the menu from the connection. The menu is reported with the message event
+// once it is received and can be read with Menu.
+func (c *Client) RequestMenu(connectionID string) error {
+	if err := c.service.RequestMenu(connectionID); err != nil {
+		return fmt.Errorf("action menu client - request menu: %w", err)
+	}
+
+	return nil
+}
+
+// Menu returns the last menu received from the connection.
+func (c *Client) Menu(connectionID string) (*Menu, error) {
+	menu, err := c.service.Menu(connectionID)
+	if err != nil {
+		return nil, fmt.Errorf("action menu client - menu: %w", err)
+	}
+
+	return menu, nil
+}
+
+// Perform asks the connection to perform the option of its menu with the form parameters.
+func (c *Client) Perform(connectionID, name string, params map[string]string) error {
+	if name == "" {
+		return errors.New("action menu client - perform: empty option name")
+	}
+
+	if err := c.service.Perform(connectionID, name, params); err != nil {
+		return fmt.Errorf("action menu client - perform: %w", err)
+	}
+
+	return nil
+}
+
+// Actions returns unfinished actions for the async usage.
+func (c *Client) Actions() ([]Action, error) {
+	return c.service.Actions()
+}
+
+// AcceptMenuRequest sends the menu in response to the menu request. The published menu of the connection
+// is sent if the menu is nil.
+// NOTE: For async usage.
+func (c *Client) AcceptMenuRequest(piID string, menu *Menu) error {
+	return c.service.ActionContinue(piID, withMenu(menu))
+}
+
+// DeclineMenuRequest ignores the menu request.
+// NOTE: For async usage.
+func (c *Client) DeclineMenuRequest(piID string) error {
+	return c.service.ActionStop(piID, nil)
+}
+
+// AcceptPerform is used when the action of the perform is done. The menu is sent in response if it's not nil,
+// ex. the menu with the updated options.
+// NOTE: For async usage.
+func (c *Client) AcceptPerform(piID string, menu *Menu) error {
+	return c.service.ActionContinue(piID, withMenu(menu))
+}
+
+// DeclinePerform ignores the perform.
+// NOTE: For async usage.
+func (c *Client) DeclinePerform(piID string) error {
+	return c.service.ActionStop(piID, nil)
+}
+
+func withMenu(menu *Menu) actionmenu.Opt {
+	if menu == nil {
+		return nil
+	}
+
+	return actionmenu.WithMenu(menu)
+}
@@ -0,0 +1,148 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package actionmenu
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/actionmenu"
+	mockactionmenu "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol/actionmenu"
+	mockprovider "github.com/hyperledger/aries-framework-go/pkg/mock/provider"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("test new client", func(t *testing.T) {
+		client, err := New(&mockprovider.Provider{ServiceValue: &mockactionmenu.MockActionMenuSvc{}})
+		require.NoError(t, err)
+		require.NotNil(t, client)
+	})
+
+	t.Run("test error from get service from context", func(t *testing.T) {
+		_, err := New(&mockprovider.Provider{ServiceErr: errors.New("service error")})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "service error")
+	})
+
+	t.Run("test error from cast service", func(t *testing.T) {
+		_, err := New(&mockprovider.Provider{ServiceValue: nil})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "cast service to action menu service failed")
+	})
+}
+
+func TestClient_Menus(t *testing.T) {
+	menu := &Menu{Title: "Issuer bot", Options: []MenuOption{{Name: "issue"}}}
+
+	t.Run("success", func(t *testing.T) {
+		var published, sent *Menu
+
+		client, err := New(&mockprovider.Provider{ServiceValue: &mockactionmenu.MockActionMenuSvc{
+			PublishMenuFunc: func(connectionID string, m *actionmenu.Menu) error {
+				require.Equal(t, "conn-1", connectionID)
+
+				published = m
+
+				return nil
+			},
+			SendMenuFunc: func(connectionID string, m *actionmenu.Menu) error {
+				sent = m
+
+				return nil
+			},
+			PerformFunc: func(connectionID, name string, params map[string]string) error {
+				require.Equal(t, "issue", name)
+				require.Equal(t, "Bob", params["name"])
+
+				return nil
+			},
+			MenuValue: menu,
+		}})
+		require.NoError(t, err)
+
+		require.NoError(t, client.PublishMenu("conn-1", menu))
+		require.Equal(t, menu, published)
+
+		require.NoError(t, client.SendMenu("conn-1", menu))
+		require.Equal(t, menu, sent)
+
+		require.NoError(t, client.RequestMenu("conn-1"))
+
+		received, err := client.Menu("conn-1")
+		require.NoError(t, err)
+		require.Equal(t, menu, received)
+
+		received, err = client.PublishedMenu("conn-1")
+		require.NoError(t, err)
+		require.Equal(t, menu, received)
+
+		require.NoError(t, client.Perform("conn-1", "issue", map[string]string{"name": "Bob"}))
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		serviceErr := errors.New("service error")
+
+		client, err := New(&mockprovider.Provider{ServiceValue: &mockactionmenu.MockActionMenuSvc{
+			PublishMenuFunc: func(string, *actionmenu.Menu) error { return serviceErr },
+			SendMenuFunc:    func(string, *actionmenu.Menu) error { return serviceErr },
+			PerformFunc:     func(string, string, map[string]string) error { return serviceErr },
+			RequestMenuErr:  serviceErr,
+			MenuErr:         serviceErr,
+		}})
+		require.NoError(t, err)
+
+		require.EqualError(t, client.PublishMenu("conn-1", menu), "action menu client - publish menu: service error")
+		require.EqualError(t, client.SendMenu("conn-1", menu), "action menu client - send menu: service error")
+		require.EqualError(t, client.RequestMenu("conn-1"), "action menu client - request menu: service error")
+		require.EqualError(t, client.Perform("conn-1", "issue", nil), "action menu client - perform: service error")
+		require.EqualError(t, client.Perform("conn-1", "", nil), "action menu client - perform: empty option name")
+
+		_, err = client.Menu("conn-1")
+		require.EqualError(t, err, "action menu client - menu: service error")
+
+		_, err = client.PublishedMenu("conn-1")
+		require.EqualError(t, err, "action menu client - published menu: service error")
+	})
+}
+
+func TestClient_Actions(t *testing.T) {
+	t.Run("accept and decline", func(t *testing.T) {
+		var opts []actionmenu.Opt
+
+		svc := &mockactionmenu.MockActionMenuSvc{
+			ActionsValue: []actionmenu.Action{{PIID: "piid-1"}},
+			ActionContinueFunc: func(piID string, opt actionmenu.Opt) error {
+				require.Equal(t, "piid-1", piID)
+
+				opts = append(opts, opt)
+
+				return nil
+			},
+		}
+
+		client, err := New(&mockprovider.Provider{ServiceValue: svc})
+		require.NoError(t, err)
+
+		actions, err := client.Actions()
+		require.NoError(t, err)
+		require.Len(t, actions, 1)
+
+		require.NoError(t, client.AcceptMenuRequest("piid-1", nil))
+		require.NoError(t, client.AcceptPerform("piid-1", &Menu{Title: "Issued"}))
+		require.Len(t, opts, 2)
+		require.Nil(t, opts[0])
+		require.NotNil(t, opts[1])
+
+		require.NoError(t, client.DeclineMenuRequest("piid-1"))
+		require.NoError(t, client.DeclinePerform("piid-1"))
+
+		svc.ActionStopErr = errors.New("stop error")
+		require.EqualError(t, client.DeclinePerform("piid-1"), "stop error")
+	})
+}
@@ -0,0 +1,72 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didrotate
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didrotate"
+)
+
+type provider interface {
+	Service(id string) (interface{}, error)
+}
+
+// msgEvent is the part of service.Event supported by the DID rotation service, it has no action events.
+type msgEvent interface {
+	RegisterMsgEvent(ch chan<- service.StateMsg) error
+	UnregisterMsgEvent(ch chan<- service.StateMsg) error
+}
+
+type protocolService interface {
+	msgEvent
+	RotateDID(connectionID, newDID string) (string, error)
+	Hangup(connectionID string) error
+}
+
+// Client enable access to DID rotation api.
+type Client struct {
+	msgEvent
+	didRotateSvc protocolService
+}
+
+// New return new instance of DID rotation client.
+func New(ctx provider) (*Client, error) {
+	svc, err := ctx.Service(didrotate.DIDRotate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create DID rotation service: %w", err)
+	}
+
+	didRotateSvc, ok := svc.(protocolService)
+	if !ok {
+		return nil, errors.New("cast service to DID rotation service failed")
+	}
+
+	return &Client{msgEvent: didRotateSvc, didRotateSvc: didRotateSvc}, nil
+}
+
+// RotateDID moves the connection to the new DID. The counterparty is notified and the connection keeps using
+// the old DID until it acknowledges the rotation. Returns the thread ID of the rotation.
+func (c *Client) RotateDID(connectionID, newDID string) (string, error) {
+	thID, err := c.didRotateSvc.RotateDID(connectionID, newDID)
+	if err != nil {
+		return "", fmt.Errorf("DID rotation client - rotate DID: %w", err)
+	}
+
+	return thID, nil
+}
+
+// Hangup ends the connection, the counterparty is notified and the connection is removed.
+func (c *Client) Hangup(connectionID string) error {
+	if err := c.didRotateSvc.Hangup(connectionID); err != nil {
+		return fmt.Errorf("DID rotation client - hangup: %w", err)
+	}
+
+	return nil
+}
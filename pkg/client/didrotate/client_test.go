@@ -0,0 +1,97 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didrotate
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	mockprovider "github.com/hyperledger/aries-framework-go/pkg/mock/provider"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("test new client", func(t *testing.T) {
+		client, err := New(&mockprovider.Provider{
+			ServiceValue: &mockDIDRotateSvc{},
+		})
+		require.NoError(t, err)
+		require.NotNil(t, client)
+	})
+
+	t.Run("test error from get service from context", func(t *testing.T) {
+		_, err := New(&mockprovider.Provider{ServiceErr: errors.New("service error")})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "service error")
+	})
+
+	t.Run("test error from cast service", func(t *testing.T) {
+		_, err := New(&mockprovider.Provider{ServiceValue: nil})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "cast service to DID rotation service failed")
+	})
+}
+
+func TestClient_RotateDID(t *testing.T) {
+	t.Run("rotate DID - success", func(t *testing.T) {
+		client, err := New(&mockprovider.Provider{
+			ServiceValue: &mockDIDRotateSvc{thID: "thread"},
+		})
+		require.NoError(t, err)
+
+		thID, err := client.RotateDID("connection", "did:peer:new")
+		require.NoError(t, err)
+		require.Equal(t, "thread", thID)
+	})
+
+	t.Run("rotate DID - service error", func(t *testing.T) {
+		client, err := New(&mockprovider.Provider{
+			ServiceValue: &mockDIDRotateSvc{rotateErr: errors.New("service error")},
+		})
+		require.NoError(t, err)
+
+		_, err = client.RotateDID("connection", "did:peer:new")
+		require.EqualError(t, err, "DID rotation client - rotate DID: service error")
+	})
+}
+
+func TestClient_Hangup(t *testing.T) {
+	t.Run("hangup - success", func(t *testing.T) {
+		client, err := New(&mockprovider.Provider{
+			ServiceValue: &mockDIDRotateSvc{},
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, client.Hangup("connection"))
+	})
+
+	t.Run("hangup - service error", func(t *testing.T) {
+		client, err := New(&mockprovider.Provider{
+			ServiceValue: &mockDIDRotateSvc{hangupErr: errors.New("service error")},
+		})
+		require.NoError(t, err)
+
+		require.EqualError(t, client.Hangup("connection"), "DID rotation client - hangup: service error")
+	})
+}
+
+type mockDIDRotateSvc struct {
+	service.Message
+	thID      string
+	rotateErr error
+	hangupErr error
+}
+
+func (m *mockDIDRotateSvc) RotateDID(string, string) (string, error) {
+	return m.thID, m.rotateErr
+}
+
+func (m *mockDIDRotateSvc) Hangup(string) error {
+	return m.hangupErr
+}
@@ -0,0 +1,166 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discoverfeatures
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/discoverfeatures"
+)
+
+type (
+	// Feature is a feature supported by the agent.
+	Feature = discoverfeatures.Feature
+	// FeatureQuery is a query of the features of the type.
+	FeatureQuery = discoverfeatures.FeatureQuery
+)
+
+type provider interface {
+	Service(id string) (interface{}, error)
+}
+
+type protocolService interface {
+	QueryProtocols(connectionID, query string, timeout time.Duration) ([]*Feature, error)
+	QueryFeatures(connectionID string, queries []*FeatureQuery, timeout time.Duration) ([]*Feature, error)
+	Register(features ...*Feature)
+	SetAllowList(patterns ...string)
+	LocalFeatures(queries ...*FeatureQuery) []*Feature
+}
+
+// Client enable access to discover features api. The disclosures of the connections are cached,
+// the same query is sent to the connection once until the cache of the connection is cleared.
+type Client struct {
+	discoverFeaturesSvc protocolService
+	cache               map[string]map[string][]*Feature
+	lock                sync.RWMutex
+}
+
+// New return new instance of discover features client.
+func New(ctx provider) (*Client, error) {
+	svc, err := ctx.Service(discoverfeatures.DiscoverFeatures)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create discover features service: %w", err)
+	}
+
+	discoverFeaturesSvc, ok := svc.(protocolService)
+	if !ok {
+		return nil, errors.New("cast service to discover features service failed")
+	}
+
+	return &Client{
+		discoverFeaturesSvc: discoverFeaturesSvc,
+		cache:               make(map[string]map[string][]*Feature),
+	}, nil
+}
+
+// Features returns the features of the connection matching the queries (discover features 2.0).
+func (c *Client) Features(connectionID string, queries ...*FeatureQuery) ([]*Feature, error) {
+	key := "v2"
+	for _, q := range queries {
+		key += " " + q.FeatureType + "=" + q.Match
+	}
+
+	return c.cached(connectionID, key, func() ([]*Feature, error) {
+		return c.discoverFeaturesSvc.QueryFeatures(connectionID, queries, 0)
+	})
+}
+
+// Protocols returns the protocols of the connection matching the query (discover features 1.0),
+// '*' matches any sequence of characters.
+func (c *Client) Protocols(connectionID, query string) ([]*Feature, error) {
+	return c.cached(connectionID, "v1 "+query, func() ([]*Feature, error) {
+		return c.discoverFeaturesSvc.QueryProtocols(connectionID, query, 0)
+	})
+}
+
+// SupportsFeature reports whether the connection supports the feature of the type.
+func (c *Client) SupportsFeature(connectionID, featureType, id string) (bool, error) {
+	features, err := c.Features(connectionID, &FeatureQuery{FeatureType: featureType, Match: id})
+	if err != nil {
+		return false, err
+	}
+
+	for _, f := range features {
+		if f.FeatureType == featureType && f.ID == id {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// SelectFeature returns the first of the preferred features of the type which is supported by the connection,
+// ex. the attachment format to use. Returns an empty string if none of them is supported.
+func (c *Client) SelectFeature(connectionID, featureType string, preferred ...string) (string, error) {
+	features, err := c.Features(connectionID, &FeatureQuery{FeatureType: featureType, Match: "*"})
+	if err != nil {
+		return "", err
+	}
+
+	for _, id := range preferred {
+		for _, f := range features {
+			if f.FeatureType == featureType && f.ID == id {
+				return id, nil
+			}
+		}
+	}
+
+	return "", nil
+}
+
+// ClearCache removes the cached disclosures of the connection.
+func (c *Client) ClearCache(connectionID string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	delete(c.cache, connectionID)
+}
+
+// RegisterFeatures registers the features of the agent which can't be discovered from its services,
+// ex. goal codes and attachment formats.
+func (c *Client) RegisterFeatures(features ...*Feature) {
+	c.discoverFeaturesSvc.Register(features...)
+}
+
+// SetAllowList sets the patterns of the feature IDs disclosed by the agent, '*' matches any sequence of characters.
+func (c *Client) SetAllowList(patterns ...string) {
+	c.discoverFeaturesSvc.SetAllowList(patterns...)
+}
+
+// LocalFeatures returns the features disclosed by the agent matching the queries.
+func (c *Client) LocalFeatures(queries ...*FeatureQuery) []*Feature {
+	return c.discoverFeaturesSvc.LocalFeatures(queries...)
+}
+
+func (c *Client) cached(connectionID, key string, query func() ([]*Feature, error)) ([]*Feature, error) {
+	c.lock.RLock()
+	features, ok := c.cache[connectionID][key]
+	c.lock.RUnlock()
+
+	if ok {
+		return features, nil
+	}
+
+	features, err := query()
+	if err != nil {
+		return nil, fmt.Errorf("discover features client - query: %w", err)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.cache[connectionID] == nil {
+		c.cache[connectionID] = make(map[string][]*Feature)
+	}
+
+	c.cache[connectionID][key] = features
+
+	return features, nil
+}
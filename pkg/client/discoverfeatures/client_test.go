@@ -0,0 +1,186 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discoverfeatures
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/discoverfeatures"
+	mockdiscoverfeatures "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol/discoverfeatures"
+	mockprovider "github.com/hyperledger/aries-framework-go/pkg/mock/provider"
+)
+
+const (
+	ldProofVC = "aries/ld-proof-vc@v1.0"
+	indyCred  = "hlindy/cred@v2.0"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("test new client", func(t *testing.T) {
+		client, err := New(&mockprovider.Provider{ServiceValue: &mockdiscoverfeatures.MockDiscoverFeaturesSvc{}})
+		require.NoError(t, err)
+		require.NotNil(t, client)
+	})
+
+	t.Run("test error from get service from context", func(t *testing.T) {
+		_, err := New(&mockprovider.Provider{ServiceErr: errors.New("service error")})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "service error")
+	})
+
+	t.Run("test error from cast service", func(t *testing.T) {
+		_, err := New(&mockprovider.Provider{ServiceValue: nil})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "cast service to discover features service failed")
+	})
+}
+
+func TestClient_Features(t *testing.T) {
+	t.Run("disclosure is cached per connection", func(t *testing.T) {
+		var calls int
+
+		client, err := New(&mockprovider.Provider{ServiceValue: &mockdiscoverfeatures.MockDiscoverFeaturesSvc{
+			QueryFeaturesFunc: func(connectionID string, queries []*FeatureQuery,
+				timeout time.Duration) ([]*Feature, error) {
+				calls++
+
+				require.Len(t, queries, 1)
+				require.Equal(t, discoverfeatures.FeatureTypeAttachmentFormat, queries[0].FeatureType)
+				require.Zero(t, timeout)
+
+				return []*Feature{{FeatureType: discoverfeatures.FeatureTypeAttachmentFormat, ID: ldProofVC}}, nil
+			},
+		}})
+		require.NoError(t, err)
+
+		query := &FeatureQuery{FeatureType: discoverfeatures.FeatureTypeAttachmentFormat, Match: "*"}
+
+		for i := 0; i < 2; i++ {
+			features, err := client.Features("conn-1", query)
+			require.NoError(t, err)
+			require.Len(t, features, 1)
+			require.Equal(t, ldProofVC, features[0].ID)
+		}
+
+		require.Equal(t, 1, calls)
+
+		_, err = client.Features("conn-2", query)
+		require.NoError(t, err)
+		require.Equal(t, 2, calls)
+
+		client.ClearCache("conn-1")
+
+		_, err = client.Features("conn-1", query)
+		require.NoError(t, err)
+		require.Equal(t, 3, calls)
+	})
+
+	t.Run("service error is not cached", func(t *testing.T) {
+		svc := &mockdiscoverfeatures.MockDiscoverFeaturesSvc{QueryErr: errors.New("service error")}
+
+		client, err := New(&mockprovider.Provider{ServiceValue: svc})
+		require.NoError(t, err)
+
+		_, err = client.Features("conn-1")
+		require.EqualError(t, err, "discover features client - query: service error")
+
+		svc.QueryErr = nil
+
+		_, err = client.Features("conn-1")
+		require.NoError(t, err)
+	})
+}
+
+func TestClient_Protocols(t *testing.T) {
+	var calls int
+
+	client, err := New(&mockprovider.Provider{ServiceValue: &mockdiscoverfeatures.MockDiscoverFeaturesSvc{
+		QueryProtocolsFunc: func(connectionID, query string, timeout time.Duration) ([]*Feature, error) {
+			calls++
+
+			require.Equal(t, "conn-1", connectionID)
+			require.Equal(t, "https://didcomm.org/*", query)
+
+			return []*Feature{{
+				FeatureType: discoverfeatures.FeatureTypeProtocol, ID: "https://didcomm.org/trust_ping/1.0",
+			}}, nil
+		},
+		QueryErr: errors.New("v2 is not used"),
+	}})
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		features, err := client.Protocols("conn-1", "https://didcomm.org/*")
+		require.NoError(t, err)
+		require.Len(t, features, 1)
+	}
+
+	require.Equal(t, 1, calls)
+}
+
+func TestClient_SelectFeature(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		client, err := New(&mockprovider.Provider{ServiceValue: &mockdiscoverfeatures.MockDiscoverFeaturesSvc{
+			QueryValue: []*Feature{
+				{FeatureType: discoverfeatures.FeatureTypeAttachmentFormat, ID: indyCred},
+				{FeatureType: discoverfeatures.FeatureTypeAttachmentFormat, ID: ldProofVC},
+			},
+		}})
+		require.NoError(t, err)
+
+		format, err := client.SelectFeature("conn-1", discoverfeatures.FeatureTypeAttachmentFormat,
+			"unknown", ldProofVC, indyCred)
+		require.NoError(t, err)
+		require.Equal(t, ldProofVC, format)
+
+		format, err = client.SelectFeature("conn-1", discoverfeatures.FeatureTypeAttachmentFormat, "unknown")
+		require.NoError(t, err)
+		require.Empty(t, format)
+
+		supported, err := client.SupportsFeature("conn-1", discoverfeatures.FeatureTypeAttachmentFormat, indyCred)
+		require.NoError(t, err)
+		require.True(t, supported)
+
+		supported, err = client.SupportsFeature("conn-1", discoverfeatures.FeatureTypeGoalCode, indyCred)
+		require.NoError(t, err)
+		require.False(t, supported)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		client, err := New(&mockprovider.Provider{ServiceValue: &mockdiscoverfeatures.MockDiscoverFeaturesSvc{
+			QueryErr: errors.New("service error"),
+		}})
+		require.NoError(t, err)
+
+		_, err = client.SelectFeature("conn-1", discoverfeatures.FeatureTypeAttachmentFormat, ldProofVC)
+		require.EqualError(t, err, "discover features client - query: service error")
+
+		_, err = client.SupportsFeature("conn-1", discoverfeatures.FeatureTypeAttachmentFormat, ldProofVC)
+		require.EqualError(t, err, "discover features client - query: service error")
+	})
+}
+
+func TestClient_LocalFeatures(t *testing.T) {
+	svc := &mockdiscoverfeatures.MockDiscoverFeaturesSvc{
+		LocalValue: []*Feature{{FeatureType: discoverfeatures.FeatureTypeGoalCode, ID: "aries.vc.issue"}},
+	}
+
+	client, err := New(&mockprovider.Provider{ServiceValue: svc})
+	require.NoError(t, err)
+
+	client.RegisterFeatures(&Feature{FeatureType: discoverfeatures.FeatureTypeAttachmentFormat, ID: ldProofVC})
+	require.Len(t, svc.Registered, 1)
+
+	client.SetAllowList("aries.*")
+	require.Equal(t, []string{"aries.*"}, svc.AllowList)
+
+	require.Len(t, client.LocalFeatures(), 1)
+}
@@ -8,9 +8,15 @@ package issuecredential
 
 import (
 	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
 
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/messenger"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/issuecredential"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
 )
 
 var (
@@ -45,6 +51,13 @@ type Provider interface {
 	Service(id string) (interface{}, error)
 }
 
+// connectionlessProvider is implemented by the providers supporting the connectionless messaging, e.g. aries.Context().
+type connectionlessProvider interface {
+	KMS() kms.KeyManager
+	StorageProvider() storage.Provider
+	ServiceEndpoint() string
+}
+
 // ProtocolService defines the issuecredential service.
 type ProtocolService interface {
 	service.DIDComm
@@ -56,7 +69,10 @@ type ProtocolService interface {
 // Client enable access to issuecredential API.
 type Client struct {
 	service.Event
-	service ProtocolService
+	service         ProtocolService
+	kms             kms.KeyManager
+	storeProvider   storage.Provider
+	serviceEndpoint string
 }
 
 // New return new instance of the issuecredential client.
@@ -71,10 +87,18 @@ func New(ctx Provider) (*Client, error) {
 		return nil, errors.New("cast service to issuecredential service failed")
 	}
 
-	return &Client{
+	client := &Client{
 		Event:   svc,
 		service: svc,
-	}, nil
+	}
+
+	if p, ok := ctx.(connectionlessProvider); ok {
+		client.kms = p.KMS()
+		client.storeProvider = p.StorageProvider()
+		client.serviceEndpoint = p.ServiceEndpoint()
+	}
+
+	return client, nil
 }
 
 // Actions returns unfinished actions for the async usage.
@@ -220,3 +244,70 @@ func WithIssueCredential(msg *IssueCredential) issuecredential.Opt {
 func WithFriendlyNames(names ...string) issuecredential.Opt {
 	return issuecredential.WithFriendlyNames(names...)
 }
+
+// CreateConnectionlessOffer is used by the Issuer to create an offer which does not need the connection,
+// e.g. to show it as a QR code. The offer carries the ~service decorator with the connectionless return route
+// the Holder replies to. It's up to the caller to deliver the returned offer, the Holder hands it over
+// to AcceptConnectionlessOffer.
+func (c *Client) CreateConnectionlessOffer(msg *OfferCredential) (service.DIDCommMsgMap, error) {
+	if msg == nil {
+		return nil, errEmptyOffer
+	}
+
+	if c.kms == nil {
+		return nil, messenger.ErrConnectionlessNotSupported
+	}
+
+	store, err := c.storeProvider.OpenStore(messenger.ConnectionlessStore)
+	if err != nil {
+		return nil, fmt.Errorf("open connectionless store: %w", err)
+	}
+
+	msg.Type = issuecredential.OfferCredentialMsgType
+
+	msgMap := service.NewDIDCommMsgMap(msg)
+	msgMap["@id"] = uuid.New().String()
+
+	// the message starts the connectionless thread
+	msgMap["~service"], err = messenger.NewServiceDecorator(c.kms, store, c.serviceEndpoint, msgMap.ID())
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = c.service.HandleOutbound(msgMap, "", ""); err != nil {
+		return nil, err
+	}
+
+	// the reply of the other side is threaded by the message ID
+	msgMap["~thread"] = map[string]interface{}{"thid": msgMap.ID()}
+
+	return msgMap, nil
+}
+
+// AcceptConnectionlessOffer is used by the Holder to handle the offer created with CreateConnectionlessOffer,
+// e.g. scanned as a QR code. The replies of the Holder are sent to the ~service decorator of the offer, the agent
+// doesn't reply to the ~service decorators of the messages it didn't accept this way. Returns the protocol instance ID.
+func (c *Client) AcceptConnectionlessOffer(msg service.DIDCommMsgMap) (string, error) {
+	if msg == nil {
+		return "", errEmptyOffer
+	}
+
+	if msg.Type() != issuecredential.OfferCredentialMsgType {
+		return "", fmt.Errorf("unexpected connectionless message type %s", msg.Type())
+	}
+
+	if c.kms == nil {
+		return "", messenger.ErrConnectionlessNotSupported
+	}
+
+	store, err := c.storeProvider.OpenStore(messenger.ConnectionlessStore)
+	if err != nil {
+		return "", fmt.Errorf("open connectionless store: %w", err)
+	}
+
+	if err = messenger.AcceptConnectionless(c.kms, store, msg); err != nil {
+		return "", err
+	}
+
+	return c.service.HandleInbound(msg, "", "")
+}
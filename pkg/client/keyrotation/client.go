@@ -0,0 +1,66 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package keyrotation
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/keyrotation"
+)
+
+type provider interface {
+	Service(id string) (interface{}, error)
+}
+
+type protocolService interface {
+	RotateKey(oldKey string, options ...keyrotation.RotateOpt) (string, error)
+	RetireKey(oldKey string) error
+}
+
+// Client enable access to key rotation api.
+type Client struct {
+	keyRotationSvc protocolService
+}
+
+// New return new instance of key rotation client.
+func New(ctx provider) (*Client, error) {
+	svc, err := ctx.Service(keyrotation.KeyRotation)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create key rotation service: %w", err)
+	}
+
+	keyRotationSvc, ok := svc.(protocolService)
+	if !ok {
+		return nil, errors.New("cast service to key rotation service failed")
+	}
+
+	return &Client{keyRotationSvc: keyRotationSvc}, nil
+}
+
+// RotateKey rotates the DIDComm recipient key (did:key) of the agent. The DID documents using the key,
+// the counterparties of their connections and the mediators get the new key. The old key stays usable
+// during the grace period. A rotation which failed part way is resumed by calling RotateKey again.
+// Returns the new recipient key.
+func (c *Client) RotateKey(oldKey string, gracePeriod time.Duration) (string, error) {
+	newKey, err := c.keyRotationSvc.RotateKey(oldKey, keyrotation.WithGracePeriod(gracePeriod))
+	if err != nil {
+		return "", fmt.Errorf("key rotation client - rotate key: %w", err)
+	}
+
+	return newKey, nil
+}
+
+// RetireKey retires the rotated key without waiting for the grace period to be over.
+func (c *Client) RetireKey(oldKey string) error {
+	if err := c.keyRotationSvc.RetireKey(oldKey); err != nil {
+		return fmt.Errorf("key rotation client - retire key: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,98 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package keyrotation
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/keyrotation"
+	mockprovider "github.com/hyperledger/aries-framework-go/pkg/mock/provider"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("test new client", func(t *testing.T) {
+		client, err := New(&mockprovider.Provider{
+			ServiceValue: &mockKeyRotationSvc{},
+		})
+		require.NoError(t, err)
+		require.NotNil(t, client)
+	})
+
+	t.Run("test error from get service from context", func(t *testing.T) {
+		_, err := New(&mockprovider.Provider{ServiceErr: errors.New("service error")})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "service error")
+	})
+
+	t.Run("test error from cast service", func(t *testing.T) {
+		_, err := New(&mockprovider.Provider{ServiceValue: nil})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "cast service to key rotation service failed")
+	})
+}
+
+func TestClient_RotateKey(t *testing.T) {
+	t.Run("rotate key - success", func(t *testing.T) {
+		client, err := New(&mockprovider.Provider{
+			ServiceValue: &mockKeyRotationSvc{newKey: "did:key:new"},
+		})
+		require.NoError(t, err)
+
+		newKey, err := client.RotateKey("did:key:old", time.Hour)
+		require.NoError(t, err)
+		require.Equal(t, "did:key:new", newKey)
+	})
+
+	t.Run("rotate key - service error", func(t *testing.T) {
+		client, err := New(&mockprovider.Provider{
+			ServiceValue: &mockKeyRotationSvc{rotateErr: errors.New("service error")},
+		})
+		require.NoError(t, err)
+
+		_, err = client.RotateKey("did:key:old", time.Hour)
+		require.EqualError(t, err, "key rotation client - rotate key: service error")
+	})
+}
+
+func TestClient_RetireKey(t *testing.T) {
+	t.Run("retire key - success", func(t *testing.T) {
+		client, err := New(&mockprovider.Provider{
+			ServiceValue: &mockKeyRotationSvc{},
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, client.RetireKey("did:key:old"))
+	})
+
+	t.Run("retire key - service error", func(t *testing.T) {
+		client, err := New(&mockprovider.Provider{
+			ServiceValue: &mockKeyRotationSvc{retireErr: keyrotation.ErrRotationNotFound},
+		})
+		require.NoError(t, err)
+
+		err = client.RetireKey("did:key:old")
+		require.True(t, errors.Is(err, keyrotation.ErrRotationNotFound))
+	})
+}
+
+type mockKeyRotationSvc struct {
+	newKey    string
+	rotateErr error
+	retireErr error
+}
+
+func (m *mockKeyRotationSvc) RotateKey(string, ...keyrotation.RotateOpt) (string, error) {
+	return m.newKey, m.rotateErr
+}
+
+func (m *mockKeyRotationSvc) RetireKey(string) error {
+	return m.retireErr
+}
@@ -43,6 +43,9 @@ type protocolService interface {
 
 	// Config returns the router's configuration.
 	Config(connID string) (*mediator.Config, error)
+
+	// GetKeys returns the recipient keys registered with the router.
+	GetKeys(connID string) ([]string, error)
 }
 
 // WithTimeout option is for definition timeout value waiting for responses received from the router.
@@ -109,3 +112,13 @@ func (c *Client) GetConfig(connID string) (*mediator.Config, error) {
 
 	return conf, nil
 }
+
+// GetKeys returns the recipient keys the agent registered with the router.
+func (c *Client) GetKeys(connID string) ([]string, error) {
+	keys, err := c.routeSvc.GetKeys(connID)
+	if err != nil {
+		return nil, fmt.Errorf("get router keys: %w", err)
+	}
+
+	return keys, nil
+}
@@ -170,3 +170,32 @@ func TestClient_GetConfig(t *testing.T) {
 		require.True(t, errors.Is(err, expected))
 	})
 }
+
+func TestClient_GetKeys(t *testing.T) {
+	t.Run("returns keys", func(t *testing.T) {
+		keys := []string{"key1", "key2"}
+
+		c, err := New(&mockprovider.Provider{
+			ServiceValue: &mockroute.MockMediatorSvc{Keys: keys},
+		})
+		require.NoError(t, err)
+
+		result, err := c.GetKeys("conn")
+		require.NoError(t, err)
+		require.Equal(t, keys, result)
+	})
+
+	t.Run("wraps keys error", func(t *testing.T) {
+		expected := errors.New("test")
+
+		c, err := New(&mockprovider.Provider{
+			ServiceValue: &mockroute.MockMediatorSvc{GetKeysErr: expected},
+		})
+		require.NoError(t, err)
+
+		_, err = c.GetKeys("conn")
+		require.Error(t, err)
+		require.True(t, errors.Is(err, expected))
+		require.Contains(t, err.Error(), "get router keys")
+	})
+}
@@ -33,6 +33,12 @@ type protocolService interface {
 	BatchPickup(connectionID string, size int) (int, error)
 
 	Noop(connectionID string) error
+
+	StatusRequestV2(connectionID, recipientKey string) (*messagepickup.StatusV2, error)
+
+	DeliveryRequest(connectionID string, limit int, recipientKey string) (int, error)
+
+	LiveDeliveryChange(connectionID string, liveDelivery bool) error
 }
 
 // New return new instance of messagepickup client.
@@ -78,3 +84,37 @@ func (r *Client) BatchPickup(connectionID string, size int) (int, error) {
 func (r *Client) Noop(connectionID string) error {
 	return r.messagepickupSvc.Noop(connectionID)
 }
+
+// StatusRequestV2 requests the status of the messages queued by the mediator using the message pickup 2.0,
+// only the messages for the recipient key are counted if it is provided.
+func (r *Client) StatusRequestV2(connectionID, recipientKey string) (*messagepickup.StatusV2, error) {
+	sts, err := r.messagepickupSvc.StatusRequestV2(connectionID, recipientKey)
+	if err != nil {
+		return nil, fmt.Errorf("message pickup client - status request v2: %w", err)
+	}
+
+	return sts, nil
+}
+
+// DeliveryRequest requests up to limit messages queued by the mediator using the message pickup 2.0.
+// The mediator removes only the messages acknowledged once they are handled, so the messages of the interrupted
+// delivery are delivered again. Returns the number of the handled messages.
+func (r *Client) DeliveryRequest(connectionID string, limit int, recipientKey string) (int, error) {
+	count, err := r.messagepickupSvc.DeliveryRequest(connectionID, limit, recipientKey)
+	if err != nil {
+		return -1, fmt.Errorf("message pickup client - delivery request: %w", err)
+	}
+
+	return count, nil
+}
+
+// LiveDeliveryChange turns on or off the live delivery mode, the mediator pushes the messages over the open
+// connection (ex. WebSocket) as soon as they arrive in the live delivery mode.
+func (r *Client) LiveDeliveryChange(connectionID string, liveDelivery bool) error {
+	err := r.messagepickupSvc.LiveDeliveryChange(connectionID, liveDelivery)
+	if err != nil {
+		return fmt.Errorf("message pickup client - live delivery change: %w", err)
+	}
+
+	return nil
+}
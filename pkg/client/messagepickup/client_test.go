@@ -13,6 +13,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/messagepickup"
 	mockpickup "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol/messagepickup"
 	mockprovider "github.com/hyperledger/aries-framework-go/pkg/mock/provider"
 )
@@ -113,3 +114,89 @@ func TestNoop(t *testing.T) {
 		require.Contains(t, err.Error(), "service error")
 	})
 }
+
+func TestStatusRequestV2(t *testing.T) {
+	t.Run("status request v2 - success", func(t *testing.T) {
+		client, err := New(&mockprovider.Provider{
+			ServiceValue: &mockpickup.MockMessagePickupSvc{
+				StatusV2Func: func(connectionID, recipientKey string) (*messagepickup.StatusV2, error) {
+					require.Equal(t, "connID", connectionID)
+					require.Equal(t, "key", recipientKey)
+
+					return &messagepickup.StatusV2{MessageCount: 2}, nil
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		sts, err := client.StatusRequestV2("connID", "key")
+		require.NoError(t, err)
+		require.Equal(t, 2, sts.MessageCount)
+	})
+
+	t.Run("status request v2 - service error", func(t *testing.T) {
+		client, err := New(&mockprovider.Provider{
+			ServiceValue: &mockpickup.MockMessagePickupSvc{
+				StatusV2Err: errors.New("service error"),
+			},
+		})
+		require.NoError(t, err)
+
+		_, err = client.StatusRequestV2("connID", "")
+		require.EqualError(t, err, "message pickup client - status request v2: service error")
+	})
+}
+
+func TestDeliveryRequest(t *testing.T) {
+	t.Run("delivery request - success", func(t *testing.T) {
+		client, err := New(&mockprovider.Provider{
+			ServiceValue: &mockpickup.MockMessagePickupSvc{
+				DeliveryFunc: func(connectionID string, limit int, recipientKey string) (int, error) {
+					require.Equal(t, 10, limit)
+
+					return 3, nil
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		count, err := client.DeliveryRequest("connID", 10, "")
+		require.NoError(t, err)
+		require.Equal(t, 3, count)
+	})
+
+	t.Run("delivery request - service error", func(t *testing.T) {
+		client, err := New(&mockprovider.Provider{
+			ServiceValue: &mockpickup.MockMessagePickupSvc{
+				DeliveryErr: errors.New("service error"),
+			},
+		})
+		require.NoError(t, err)
+
+		_, err = client.DeliveryRequest("connID", 10, "")
+		require.EqualError(t, err, "message pickup client - delivery request: service error")
+	})
+}
+
+func TestLiveDeliveryChange(t *testing.T) {
+	t.Run("live delivery change - success", func(t *testing.T) {
+		client, err := New(&mockprovider.Provider{
+			ServiceValue: &mockpickup.MockMessagePickupSvc{},
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, client.LiveDeliveryChange("connID", true))
+	})
+
+	t.Run("live delivery change - service error", func(t *testing.T) {
+		client, err := New(&mockprovider.Provider{
+			ServiceValue: &mockpickup.MockMessagePickupSvc{
+				LiveDeliveryErr: errors.New("service error"),
+			},
+		})
+		require.NoError(t, err)
+
+		err = client.LiveDeliveryChange("connID", true)
+		require.EqualError(t, err, "message pickup client - live delivery change: service error")
+	})
+}
@@ -75,6 +75,7 @@ type message struct {
 	GoalCode          string
 	RouterConnections []string
 	Service           []interface{}
+	MultiUse          bool
 }
 
 func (m *message) RouterConnection() string {
@@ -199,6 +200,7 @@ func (c *Client) CreateInvitation(protocols []string, opts ...MessageOption) (*I
 		GoalCode:  msg.GoalCode,
 		Service:   msg.Service,
 		Protocols: protocols,
+		MultiUse:  msg.MultiUse,
 	}
 
 	if len(inv.Service) == 0 {
@@ -263,6 +265,8 @@ func (c *Client) ActionStop(piID string, err error) error {
 }
 
 // AcceptRequest from another agent and return the ID of a new connection record.
+// If the request includes the public DID of an agent we already have a connection with, the existing connection
+// is reused (handshake-reuse) and its ID is returned instead, the attached request is sent over it.
 func (c *Client) AcceptRequest(r *Request, myLabel string, opts ...MessageOption) (string, error) {
 	msg := &message{}
 
@@ -283,6 +287,8 @@ func (c *Client) AcceptRequest(r *Request, myLabel string, opts ...MessageOption
 }
 
 // AcceptInvitation from another agent and return the ID of the new connection records.
+// If the invitation includes the public DID of an agent we already have a connection with, the existing connection
+// is reused (handshake-reuse) and its ID is returned instead.
 func (c *Client) AcceptInvitation(i *Invitation, myLabel string, opts ...MessageOption) (string, error) {
 	msg := &message{}
 
@@ -335,6 +341,16 @@ func WithRouterConnections(conn ...string) MessageOption {
 	}
 }
 
+// WithMultiUse allows the invitation to create many connections, each one with its own connection ID. Invitations are
+// single-use by default.
+func WithMultiUse() MessageOption {
+	return func(m *message) error {
+		m.MultiUse = true
+
+		return nil
+	}
+}
+
 // WithServices allows you to specify service entries to include in the request message.
 // Each entry must be either a valid DID (string) or a `service` object.
 func WithServices(svcs ...interface{}) MessageOption {
@@ -265,6 +265,20 @@ func TestCreateInvitation(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, []string{didexchange.PIURI}, inv.Protocols)
 	})
+	t.Run("single-use by default", func(t *testing.T) {
+		c, err := New(withTestProvider())
+		require.NoError(t, err)
+		inv, err := c.CreateInvitation(nil)
+		require.NoError(t, err)
+		require.False(t, inv.MultiUse)
+	})
+	t.Run("sets multi-use", func(t *testing.T) {
+		c, err := New(withTestProvider())
+		require.NoError(t, err)
+		inv, err := c.CreateInvitation(nil, WithMultiUse())
+		require.NoError(t, err)
+		require.True(t, inv.MultiUse)
+	})
 	t.Run("includes the diddoc Service block returned by provider", func(t *testing.T) {
 		expected := &did.Service{
 			ID:              uuid.New().String(),
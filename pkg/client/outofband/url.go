@@ -0,0 +1,443 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package outofband
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didexchange"
+)
+
+const (
+	// InvitationURLParam is the query parameter holding an encoded out-of-band invitation.
+	InvitationURLParam = "oob"
+	// LegacyInvitationURLParam is the query parameter holding an encoded DID exchange (connections) invitation.
+	LegacyInvitationURLParam = "c_i"
+	// DefaultQRBaseURL is the base URL of QR payloads when none is provided.
+	DefaultQRBaseURL = "didcomm://invite"
+
+	// invitation types of the connections protocol still produced by many agents.
+	connectionsInvitationMsgType       = "https://didcomm.org/connections/1.0/invitation"
+	legacyConnectionsInvitationMsgType = "did:sov:BzCbsNYhMrjHiqZDTUASHg;spec/connections/1.0/invitation"
+
+	defaultMaxRedirects = 5
+	defaultHTTPTimeout  = 10 * time.Second
+	maxInvitationSize   = 64 * 1024
+)
+
+var logger = log.New("aries-framework/client/outofband")
+
+// ErrNotInvitationURL is returned when a URL carries neither an `oob` nor a `c_i` query parameter.
+var ErrNotInvitationURL = errors.New("url does not contain an invitation")
+
+// ErrForbiddenAddress is returned when a shortened invitation URL (or one of its redirects) points to a loopback,
+// private or otherwise non-public address and the resolver was not configured with WithPrivateNetworks.
+var ErrForbiddenAddress = errors.New("address is not allowed")
+
+// nonPublicNetworks are the ranges not covered by the net.IP helpers that shortened URLs can't point to.
+var nonPublicNetworks = parseCIDRs( // nolint:gochecknoglobals
+	"0.0.0.0/8",      // "this" network
+	"10.0.0.0/8",     // private
+	"100.64.0.0/10",  // carrier-grade NAT
+	"172.16.0.0/12",  // private
+	"192.168.0.0/16", // private
+	"198.18.0.0/15",  // benchmarking
+	"fc00::/7",       // unique local
+)
+
+// DecodedInvitation holds the invitation decoded from an invitation URL, only one of the fields is set.
+type DecodedInvitation struct {
+	Invitation       *Invitation             `json:"invitation,omitempty"`
+	LegacyInvitation *didexchange.Invitation `json:"legacy_invitation,omitempty"`
+}
+
+// EncodeInvitationURL encodes the out-of-band invitation into the `oob` query parameter of baseURL.
+func EncodeInvitationURL(baseURL string, inv *Invitation) (string, error) {
+	if inv == nil {
+		return "", errors.New("invitation was not provided")
+	}
+
+	return encodeURL(baseURL, InvitationURLParam, inv, base64.URLEncoding)
+}
+
+// EncodeLegacyInvitationURL encodes the DID exchange invitation into the `c_i` query parameter of baseURL.
+func EncodeLegacyInvitationURL(baseURL string, inv *didexchange.Invitation) (string, error) {
+	if inv == nil {
+		return "", errors.New("invitation was not provided")
+	}
+
+	return encodeURL(baseURL, LegacyInvitationURLParam, inv, base64.URLEncoding)
+}
+
+// EncodeInvitationQR returns a compact payload for the out-of-band invitation to be rendered as a QR code.
+// The invitation is encoded without base64 padding and DefaultQRBaseURL is used if baseURL is empty.
+func EncodeInvitationQR(baseURL string, inv *Invitation) (string, error) {
+	if inv == nil {
+		return "", errors.New("invitation was not provided")
+	}
+
+	return encodeURL(qrBaseURL(baseURL), InvitationURLParam, inv, base64.RawURLEncoding)
+}
+
+// EncodeLegacyInvitationQR returns a compact payload for the DID exchange invitation to be rendered as a QR code.
+// The invitation is encoded without base64 padding and DefaultQRBaseURL is used if baseURL is empty.
+func EncodeLegacyInvitationQR(baseURL string, inv *didexchange.Invitation) (string, error) {
+	if inv == nil {
+		return "", errors.New("invitation was not provided")
+	}
+
+	return encodeURL(qrBaseURL(baseURL), LegacyInvitationURLParam, inv, base64.RawURLEncoding)
+}
+
+// DecodeInvitationURL decodes the invitation carried by the `oob` or `c_i` query parameter of the URL.
+// The parameter may be base64url encoded with or without padding, the @type of the invitation has to match
+// the parameter. ErrNotInvitationURL is returned if the URL has neither parameter, which is the case for
+// shortened URLs (see URLResolver).
+func DecodeInvitationURL(invitationURL string) (*DecodedInvitation, error) {
+	u, err := url.Parse(strings.TrimSpace(invitationURL))
+	if err != nil {
+		return nil, fmt.Errorf("parse invitation url: %w", err)
+	}
+
+	query := u.Query()
+
+	if v := query.Get(InvitationURLParam); v != "" {
+		inv := &Invitation{}
+
+		if err := decodeParam(v, inv); err != nil {
+			return nil, fmt.Errorf("decode %s parameter: %w", InvitationURLParam, err)
+		}
+
+		if inv.Type != InvitationMsgType {
+			return nil, fmt.Errorf("decode %s parameter: unsupported invitation type '%s'", InvitationURLParam, inv.Type)
+		}
+
+		return &DecodedInvitation{Invitation: inv}, nil
+	}
+
+	if v := query.Get(LegacyInvitationURLParam); v != "" {
+		inv := &didexchange.Invitation{}
+
+		if err := decodeParam(v, inv); err != nil {
+			return nil, fmt.Errorf("decode %s parameter: %w", LegacyInvitationURLParam, err)
+		}
+
+		if !isLegacyInvitationType(inv.Type) {
+			return nil, fmt.Errorf("decode %s parameter: unsupported invitation type '%s'", LegacyInvitationURLParam,
+				inv.Type)
+		}
+
+		return &DecodedInvitation{LegacyInvitation: inv}, nil
+	}
+
+	return nil, ErrNotInvitationURL
+}
+
+// HTTPClient is the HTTP client used by the URLResolver, *http.Client implements it.
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// URLResolver decodes invitation URLs and resolves shortened ones by following their redirects.
+type URLResolver struct {
+	client       HTTPClient
+	maxRedirects int
+	allowPrivate bool
+}
+
+// URLResolverOption configures the URLResolver.
+type URLResolverOption func(*URLResolver)
+
+// WithHTTPClient sets the HTTP client used to resolve shortened invitation URLs. The resolver only checks the
+// scheme of the URLs it requests, the client is responsible for the addresses it connects to and for any
+// redirect it follows on its own.
+func WithHTTPClient(client HTTPClient) URLResolverOption {
+	return func(r *URLResolver) {
+		r.client = client
+	}
+}
+
+// WithMaxRedirects sets how many redirects are followed while resolving a shortened invitation URL.
+func WithMaxRedirects(n int) URLResolverOption {
+	return func(r *URLResolver) {
+		r.maxRedirects = n
+	}
+}
+
+// WithPrivateNetworks allows the default HTTP client to connect to loopback, private and link-local addresses.
+func WithPrivateNetworks() URLResolverOption {
+	return func(r *URLResolver) {
+		r.allowPrivate = true
+	}
+}
+
+// NewURLResolver returns a new URLResolver. By default redirects are followed by the resolver itself so that
+// it can stop as soon as an invitation URL is reached, only http and https URLs are requested and the
+// connections to non-public addresses are refused (see WithPrivateNetworks).
+func NewURLResolver(opts ...URLResolverOption) *URLResolver {
+	r := &URLResolver{
+		maxRedirects: defaultMaxRedirects,
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	if r.client == nil {
+		r.client = defaultHTTPClient(r.allowPrivate)
+	}
+
+	return r
+}
+
+func defaultHTTPClient(allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: defaultHTTPTimeout}
+
+	if !allowPrivate {
+		// the address is checked once resolved so that a host can't be pointed to another address after a check
+		dialer.Control = func(_, address string, _ syscall.RawConn) error {
+			return checkAddress(address)
+		}
+	}
+
+	return &http.Client{
+		Timeout: defaultHTTPTimeout,
+		// no proxy, the dialer has to see the addresses of the shortened URLs
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: defaultHTTPTimeout,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// Resolve decodes the invitation from the URL. Shortened URLs are fetched and the invitation is taken either from
+// the URL they redirect to or from the JSON response body as described in:
+// https://github.com/hyperledger/aries-rfcs/tree/master/features/0434-outofband#url-shortening
+func (r *URLResolver) Resolve(invitationURL string) (*DecodedInvitation, error) {
+	current := strings.TrimSpace(invitationURL)
+
+	for i := 0; i <= r.maxRedirects; i++ {
+		decoded, err := DecodeInvitationURL(current)
+		if !errors.Is(err, ErrNotInvitationURL) {
+			return decoded, err
+		}
+
+		decoded, next, err := r.fetch(current)
+		if err != nil {
+			return nil, err
+		}
+
+		if decoded != nil {
+			return decoded, nil
+		}
+
+		current = next
+	}
+
+	return nil, fmt.Errorf("resolve invitation url: stopped after %d redirects", r.maxRedirects)
+}
+
+// fetch returns either the invitation in the response body or the URL the response redirects to.
+func (r *URLResolver) fetch(u string) (*DecodedInvitation, string, error) {
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("new request: %w", err)
+	}
+
+	if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+		return nil, "", fmt.Errorf("resolve invitation url: unsupported scheme '%s'", req.URL.Scheme)
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("resolve invitation url: %w", err)
+	}
+
+	defer func() {
+		if errClose := resp.Body.Close(); errClose != nil {
+			logger.Warnf("failed to close response body: %s", errClose)
+		}
+	}()
+
+	if resp.StatusCode >= http.StatusMultipleChoices && resp.StatusCode < http.StatusBadRequest {
+		location, errLoc := resp.Location()
+		if errLoc != nil {
+			return nil, "", fmt.Errorf("resolve invitation url: redirect without location: %w", errLoc)
+		}
+
+		return nil, location.String(), nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("resolve invitation url: unexpected status %d", resp.StatusCode)
+	}
+
+	// the client followed the redirects on its own
+	if resp.Request != nil && resp.Request.URL != nil && resp.Request.URL.String() != u {
+		if decoded, errDecode := DecodeInvitationURL(resp.Request.URL.String()); errDecode == nil {
+			return decoded, "", nil
+		}
+	}
+
+	decoded, err := decodeBody(resp.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("resolve invitation url: %w", err)
+	}
+
+	return decoded, "", nil
+}
+
+func decodeBody(body io.Reader) (*DecodedInvitation, error) {
+	raw, err := ioutil.ReadAll(io.LimitReader(body, maxInvitationSize))
+	if err != nil {
+		return nil, fmt.Errorf("read body: %w", err)
+	}
+
+	header := struct {
+		Type string `json:"@type"`
+	}{}
+
+	if err = json.Unmarshal(raw, &header); err != nil {
+		return nil, fmt.Errorf("unmarshal invitation: %w", err)
+	}
+
+	switch {
+	case header.Type == InvitationMsgType:
+		inv := &Invitation{}
+
+		if err = json.Unmarshal(raw, inv); err != nil {
+			return nil, fmt.Errorf("unmarshal invitation: %w", err)
+		}
+
+		return &DecodedInvitation{Invitation: inv}, nil
+	case isLegacyInvitationType(header.Type):
+		inv := &didexchange.Invitation{}
+
+		if err = json.Unmarshal(raw, inv); err != nil {
+			return nil, fmt.Errorf("unmarshal invitation: %w", err)
+		}
+
+		return &DecodedInvitation{LegacyInvitation: inv}, nil
+	default:
+		return nil, fmt.Errorf("unsupported invitation type '%s'", header.Type)
+	}
+}
+
+func checkAddress(address string) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("split address: %w", err)
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return fmt.Errorf("%w: %s is not an IP address", ErrForbiddenAddress, host)
+	}
+
+	if ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() || ip.IsUnspecified() {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, ip)
+	}
+
+	for _, n := range nonPublicNetworks {
+		if n.Contains(ip) {
+			return fmt.Errorf("%w: %s", ErrForbiddenAddress, ip)
+		}
+	}
+
+	return nil
+}
+
+func parseCIDRs(cidrs ...string) []*net.IPNet {
+	nets := make([]*net.IPNet, len(cidrs))
+
+	for i, cidr := range cidrs {
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+
+		nets[i] = n
+	}
+
+	return nets
+}
+
+func isLegacyInvitationType(msgType string) bool {
+	switch msgType {
+	case didexchange.InvitationMsgType, connectionsInvitationMsgType, legacyConnectionsInvitationMsgType:
+		return true
+	}
+
+	return false
+}
+
+func encodeURL(baseURL, param string, inv interface{}, enc *base64.Encoding) (string, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return "", fmt.Errorf("parse base url: %w", err)
+	}
+
+	raw, err := json.Marshal(inv)
+	if err != nil {
+		return "", fmt.Errorf("marshal invitation: %w", err)
+	}
+
+	query := u.Query()
+	query.Set(param, enc.EncodeToString(raw))
+	u.RawQuery = query.Encode()
+
+	return u.String(), nil
+}
+
+// decodeParam decodes the base64url value with or without padding. Values that went through a standard base64
+// encoder are accepted as well since a lot of agents produce them.
+func decodeParam(v string, inv interface{}) error {
+	// a '+' of the standard alphabet turns into a space once the query is unescaped
+	v = strings.ReplaceAll(strings.TrimRight(strings.TrimSpace(v), "="), " ", "+")
+
+	raw, err := base64.RawURLEncoding.DecodeString(v)
+	if err != nil {
+		var errStd error
+
+		raw, errStd = base64.RawStdEncoding.DecodeString(v)
+		if errStd != nil {
+			return fmt.Errorf("base64 decode: %w", err)
+		}
+	}
+
+	if err = json.Unmarshal(raw, inv); err != nil {
+		return fmt.Errorf("unmarshal invitation: %w", err)
+	}
+
+	return nil
+}
+
+func qrBaseURL(baseURL string) string {
+	if baseURL == "" {
+		return DefaultQRBaseURL
+	}
+
+	return baseURL
+}
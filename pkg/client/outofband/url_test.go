@@ -0,0 +1,388 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package outofband
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didexchange"
+)
+
+func TestEncodeDecodeInvitationURL(t *testing.T) {
+	t.Run("out-of-band invitation", func(t *testing.T) {
+		expected := newURLTestInvitation()
+
+		invURL, err := EncodeInvitationURL("https://example.com/ssi?foo=bar", expected)
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(invURL, "https://example.com/ssi?"))
+		require.Contains(t, invURL, "foo=bar")
+		require.Contains(t, invURL, InvitationURLParam+"=")
+
+		decoded, err := DecodeInvitationURL(invURL)
+		require.NoError(t, err)
+		require.Nil(t, decoded.LegacyInvitation)
+		require.Equal(t, expected, decoded.Invitation)
+	})
+
+	t.Run("legacy invitation", func(t *testing.T) {
+		expected := newURLTestLegacyInvitation()
+
+		invURL, err := EncodeLegacyInvitationURL("https://example.com/ssi", expected)
+		require.NoError(t, err)
+		require.Contains(t, invURL, LegacyInvitationURLParam+"=")
+
+		decoded, err := DecodeInvitationURL(invURL)
+		require.NoError(t, err)
+		require.Nil(t, decoded.Invitation)
+		require.Equal(t, expected, decoded.LegacyInvitation)
+	})
+
+	t.Run("QR payloads", func(t *testing.T) {
+		inv := newURLTestInvitation()
+
+		payload, err := EncodeInvitationQR("", inv)
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(payload, DefaultQRBaseURL+"?"))
+		require.NotContains(t, payload, "%3D")
+
+		decoded, err := DecodeInvitationURL(payload)
+		require.NoError(t, err)
+		require.Equal(t, inv, decoded.Invitation)
+
+		legacy := newURLTestLegacyInvitation()
+
+		payload, err = EncodeLegacyInvitationQR("https://example.com", legacy)
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(payload, "https://example.com?"))
+
+		decoded, err = DecodeInvitationURL(payload)
+		require.NoError(t, err)
+		require.Equal(t, legacy, decoded.LegacyInvitation)
+	})
+
+	t.Run("accepts base64url with and without padding", func(t *testing.T) {
+		inv := newURLTestInvitation()
+		// makes sure the encoded value needs padding
+		for i := 0; i < 3; i++ {
+			raw, err := json.Marshal(inv)
+			require.NoError(t, err)
+
+			if len(raw)%3 != 0 {
+				break
+			}
+
+			inv.Label += "x"
+		}
+
+		raw, err := json.Marshal(inv)
+		require.NoError(t, err)
+
+		for _, enc := range []*base64.Encoding{
+			base64.URLEncoding, base64.RawURLEncoding, base64.StdEncoding, base64.RawStdEncoding,
+		} {
+			// values are put into the URL as-is, the way most agents do it
+			decoded, err := DecodeInvitationURL("https://example.com?oob=" + enc.EncodeToString(raw))
+			require.NoError(t, err)
+			require.Equal(t, inv, decoded.Invitation)
+
+			decoded, err = DecodeInvitationURL("https://example.com?oob=" + url.QueryEscape(enc.EncodeToString(raw)))
+			require.NoError(t, err)
+			require.Equal(t, inv, decoded.Invitation)
+		}
+	})
+
+	t.Run("error: nil invitation", func(t *testing.T) {
+		_, err := EncodeInvitationURL("https://example.com", nil)
+		require.EqualError(t, err, "invitation was not provided")
+		_, err = EncodeLegacyInvitationURL("https://example.com", nil)
+		require.EqualError(t, err, "invitation was not provided")
+		_, err = EncodeInvitationQR("", nil)
+		require.EqualError(t, err, "invitation was not provided")
+		_, err = EncodeLegacyInvitationQR("", nil)
+		require.EqualError(t, err, "invitation was not provided")
+	})
+
+	t.Run("error: invalid base url", func(t *testing.T) {
+		_, err := EncodeInvitationURL("://example.com", newURLTestInvitation())
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "parse base url")
+	})
+
+	t.Run("error: not an invitation url", func(t *testing.T) {
+		_, err := DecodeInvitationURL("https://example.com?foo=bar")
+		require.True(t, errors.Is(err, ErrNotInvitationURL))
+	})
+
+	t.Run("error: invalid url", func(t *testing.T) {
+		_, err := DecodeInvitationURL("://example.com")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "parse invitation url")
+	})
+
+	t.Run("error: invalid encoding", func(t *testing.T) {
+		_, err := DecodeInvitationURL("https://example.com?oob=***")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "base64 decode")
+
+		_, err = DecodeInvitationURL("https://example.com?c_i=" + base64.URLEncoding.EncodeToString([]byte("{")))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal invitation")
+	})
+
+	t.Run("error: invitation type does not match the parameter", func(t *testing.T) {
+		legacy, err := json.Marshal(&didexchange.Invitation{
+			Type:  didexchange.InvitationMsgType,
+			ID:    uuid.New().String(),
+			Label: "legacy",
+		})
+		require.NoError(t, err)
+
+		_, err = DecodeInvitationURL("https://example.com?oob=" + base64.URLEncoding.EncodeToString(legacy))
+		require.EqualError(t, err, fmt.Sprintf("decode oob parameter: unsupported invitation type '%s'",
+			didexchange.InvitationMsgType))
+
+		oob, err := json.Marshal(newURLTestInvitation())
+		require.NoError(t, err)
+
+		_, err = DecodeInvitationURL("https://example.com?c_i=" + base64.URLEncoding.EncodeToString(oob))
+		require.EqualError(t, err, fmt.Sprintf("decode c_i parameter: unsupported invitation type '%s'",
+			InvitationMsgType))
+	})
+}
+
+func TestURLResolver_Resolve(t *testing.T) {
+	inv := newURLTestInvitation()
+
+	invURL, err := EncodeInvitationURL("https://example.com/ssi", inv)
+	require.NoError(t, err)
+
+	t.Run("decodes invitation url without a request", func(t *testing.T) {
+		resolver := NewURLResolver(WithHTTPClient(&stubHTTPClient{
+			do: func(*http.Request) (*http.Response, error) {
+				return nil, errors.New("unexpected request")
+			},
+		}))
+
+		decoded, err := resolver.Resolve(invURL)
+		require.NoError(t, err)
+		require.Equal(t, inv, decoded.Invitation)
+	})
+
+	t.Run("follows redirects of shortened url", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/short":
+				http.Redirect(w, r, "/intermediate", http.StatusFound)
+			case "/intermediate":
+				// the long url isn't served, the resolver must stop at it
+				http.Redirect(w, r, invURL, http.StatusMovedPermanently)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		defer srv.Close()
+
+		decoded, err := NewURLResolver(WithPrivateNetworks()).Resolve(srv.URL + "/short")
+		require.NoError(t, err)
+		require.Equal(t, inv, decoded.Invitation)
+	})
+
+	t.Run("client following redirects on its own", func(t *testing.T) {
+		var srvURL string
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/short" {
+				http.Redirect(w, r, srvURL+"/long?oob="+strings.Split(invURL, "oob=")[1],
+					http.StatusFound)
+
+				return
+			}
+
+			_, errWrite := w.Write([]byte("<html></html>"))
+			require.NoError(t, errWrite)
+		}))
+		defer srv.Close()
+
+		srvURL = srv.URL
+
+		decoded, err := NewURLResolver(WithHTTPClient(http.DefaultClient)).Resolve(srv.URL + "/short")
+		require.NoError(t, err)
+		require.Equal(t, inv, decoded.Invitation)
+	})
+
+	t.Run("invitation in the response body", func(t *testing.T) {
+		legacy := newURLTestLegacyInvitation()
+
+		for _, body := range []interface{}{inv, legacy} {
+			raw, err := json.Marshal(body)
+			require.NoError(t, err)
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, "application/json", r.Header.Get("Accept"))
+
+				_, errWrite := w.Write(raw)
+				require.NoError(t, errWrite)
+			}))
+
+			decoded, err := NewURLResolver(WithPrivateNetworks()).Resolve(srv.URL + "/short")
+			require.NoError(t, err)
+
+			if decoded.Invitation != nil {
+				require.Equal(t, inv, decoded.Invitation)
+			} else {
+				require.Equal(t, legacy, decoded.LegacyInvitation)
+			}
+
+			srv.Close()
+		}
+	})
+
+	t.Run("error: too many redirects", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Redirect(w, r, "/loop", http.StatusFound)
+		}))
+		defer srv.Close()
+
+		_, err := NewURLResolver(WithMaxRedirects(2), WithPrivateNetworks()).Resolve(srv.URL + "/short")
+		require.EqualError(t, err, "resolve invitation url: stopped after 2 redirects")
+	})
+
+	t.Run("error: non-public addresses are refused by default", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Redirect(w, r, invURL, http.StatusFound)
+		}))
+		defer srv.Close()
+
+		_, err := NewURLResolver().Resolve(srv.URL + "/short")
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrForbiddenAddress))
+	})
+
+	t.Run("error: redirect to an unsupported scheme", func(t *testing.T) {
+		resolver := NewURLResolver(WithHTTPClient(&stubHTTPClient{
+			do: func(*http.Request) (*http.Response, error) {
+				return &http.Response{
+					StatusCode: http.StatusFound,
+					Header:     http.Header{"Location": []string{"file:///etc/passwd"}},
+					Body:       ioutil.NopCloser(strings.NewReader("")),
+				}, nil
+			},
+		}))
+
+		_, err := resolver.Resolve("https://example.com/short")
+		require.EqualError(t, err, "resolve invitation url: unsupported scheme 'file'")
+	})
+
+	t.Run("error: unexpected responses", func(t *testing.T) {
+		tests := []struct {
+			name string
+			resp *http.Response
+			body string
+			err  string
+		}{{
+			name: "status",
+			resp: &http.Response{StatusCode: http.StatusNotFound},
+			err:  "unexpected status 404",
+		}, {
+			name: "redirect without location",
+			resp: &http.Response{StatusCode: http.StatusFound, Header: http.Header{}},
+			err:  "redirect without location",
+		}, {
+			name: "not json",
+			resp: &http.Response{StatusCode: http.StatusOK},
+			err:  "unmarshal invitation",
+		}, {
+			name: "unsupported type",
+			resp: &http.Response{StatusCode: http.StatusOK},
+			body: `{"@type":"foo"}`,
+			err:  "unsupported invitation type 'foo'",
+		}}
+
+		for _, test := range tests {
+			tc := test
+			t.Run(tc.name, func(t *testing.T) {
+				resolver := NewURLResolver(WithHTTPClient(&stubHTTPClient{
+					do: func(*http.Request) (*http.Response, error) {
+						tc.resp.Body = ioutil.NopCloser(strings.NewReader(tc.body))
+
+						return tc.resp, nil
+					},
+				}))
+
+				_, err := resolver.Resolve("https://example.com/short")
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.err)
+			})
+		}
+	})
+
+	t.Run("error: request fails", func(t *testing.T) {
+		resolver := NewURLResolver(WithHTTPClient(&stubHTTPClient{
+			do: func(*http.Request) (*http.Response, error) {
+				return nil, errors.New("test")
+			},
+		}))
+
+		_, err := resolver.Resolve("https://example.com/short")
+		require.EqualError(t, err, "resolve invitation url: test")
+	})
+}
+
+func TestCheckAddress(t *testing.T) {
+	for _, address := range []string{
+		"127.0.0.1:80", "[::1]:443", "10.1.2.3:80", "172.20.0.1:80", "192.168.1.1:80", "169.254.169.254:80",
+		"100.64.0.1:80", "0.0.0.0:80", "[fd00::1]:80", "[fe80::1]:80",
+	} {
+		err := checkAddress(address)
+		require.True(t, errors.Is(err, ErrForbiddenAddress), address)
+	}
+
+	require.NoError(t, checkAddress("93.184.216.34:443"))
+	require.NoError(t, checkAddress("[2606:2800:220:1:248:1893:25c8:1946]:443"))
+
+	require.Error(t, checkAddress("no port"))
+}
+
+type stubHTTPClient struct {
+	do func(*http.Request) (*http.Response, error)
+}
+
+func (s *stubHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	return s.do(req)
+}
+
+func newURLTestInvitation() *Invitation {
+	return &Invitation{
+		ID:        uuid.New().String(),
+		Type:      InvitationMsgType,
+		Label:     "Alice",
+		Service:   []interface{}{"did:example:123"},
+		Protocols: []string{didexchange.PIURI},
+	}
+}
+
+func newURLTestLegacyInvitation() *didexchange.Invitation {
+	return &didexchange.Invitation{
+		ID:              uuid.New().String(),
+		Type:            didexchange.InvitationMsgType,
+		Label:           "Bob",
+		RecipientKeys:   []string{fmt.Sprintf("key-%s", uuid.New().String())},
+		ServiceEndpoint: "https://example.com/endpoint",
+	}
+}
@@ -8,10 +8,16 @@ package presentproof
 
 import (
 	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
 
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/messenger"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/presentproof"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
 )
 
 type (
@@ -37,6 +43,13 @@ type Provider interface {
 	Service(id string) (interface{}, error)
 }
 
+// connectionlessProvider is implemented by the providers supporting the connectionless messaging, e.g. aries.Context().
+type connectionlessProvider interface {
+	KMS() kms.KeyManager
+	StorageProvider() storage.Provider
+	ServiceEndpoint() string
+}
+
 // ProtocolService defines the presentproof service.
 type ProtocolService interface {
 	service.DIDComm
@@ -49,7 +62,10 @@ type ProtocolService interface {
 // https://github.com/hyperledger/aries-rfcs/tree/master/features/0037-present-proof
 type Client struct {
 	service.Event
-	service ProtocolService
+	service         ProtocolService
+	kms             kms.KeyManager
+	storeProvider   storage.Provider
+	serviceEndpoint string
 }
 
 // New returns new instance of the presentproof client.
@@ -64,10 +80,18 @@ func New(ctx Provider) (*Client, error) {
 		return nil, errors.New("cast service to presentproof service failed")
 	}
 
-	return &Client{
+	client := &Client{
 		Event:   svc,
 		service: svc,
-	}, nil
+	}
+
+	if p, ok := ctx.(connectionlessProvider); ok {
+		client.kms = p.KMS()
+		client.storeProvider = p.StorageProvider()
+		client.serviceEndpoint = p.ServiceEndpoint()
+	}
+
+	return client, nil
 }
 
 // Actions returns pending actions that have yet to be executed or cancelled.
@@ -190,3 +214,71 @@ func WithRequestPresentation(msg *RequestPresentation) presentproof.Opt {
 func WithFriendlyNames(names ...string) presentproof.Opt {
 	return presentproof.WithFriendlyNames(names...)
 }
+
+// CreateConnectionlessRequestPresentation is used by the Verifier to create a request presentation which does not
+// need the connection, e.g. to show it as a QR code. The request carries the ~service decorator with the connectionless
+// return route the Prover replies to. It's up to the caller to deliver the returned request, the Prover hands it
+// over to AcceptConnectionlessRequestPresentation.
+func (c *Client) CreateConnectionlessRequestPresentation(msg *RequestPresentation) (service.DIDCommMsgMap, error) {
+	if msg == nil {
+		return nil, errEmptyRequestPresentation
+	}
+
+	if c.kms == nil {
+		return nil, messenger.ErrConnectionlessNotSupported
+	}
+
+	store, err := c.storeProvider.OpenStore(messenger.ConnectionlessStore)
+	if err != nil {
+		return nil, fmt.Errorf("open connectionless store: %w", err)
+	}
+
+	msg.Type = presentproof.RequestPresentationMsgType
+
+	msgMap := service.NewDIDCommMsgMap(msg)
+	msgMap["@id"] = uuid.New().String()
+
+	// the message starts the connectionless thread
+	msgMap["~service"], err = messenger.NewServiceDecorator(c.kms, store, c.serviceEndpoint, msgMap.ID())
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = c.service.HandleInbound(msgMap, "", ""); err != nil {
+		return nil, err
+	}
+
+	// the reply of the other side is threaded by the message ID
+	msgMap["~thread"] = map[string]interface{}{"thid": msgMap.ID()}
+
+	return msgMap, nil
+}
+
+// AcceptConnectionlessRequestPresentation is used by the Prover to handle the request presentation created with
+// CreateConnectionlessRequestPresentation, e.g. scanned as a QR code. The replies of the Prover are sent to
+// the ~service decorator of the request, the agent doesn't reply to the ~service decorators of the messages it didn't
+// accept this way. Returns the protocol instance ID.
+func (c *Client) AcceptConnectionlessRequestPresentation(msg service.DIDCommMsgMap) (string, error) {
+	if msg == nil {
+		return "", errEmptyRequestPresentation
+	}
+
+	if msg.Type() != presentproof.RequestPresentationMsgType {
+		return "", fmt.Errorf("unexpected connectionless message type %s", msg.Type())
+	}
+
+	if c.kms == nil {
+		return "", messenger.ErrConnectionlessNotSupported
+	}
+
+	store, err := c.storeProvider.OpenStore(messenger.ConnectionlessStore)
+	if err != nil {
+		return "", fmt.Errorf("open connectionless store: %w", err)
+	}
+
+	if err = messenger.AcceptConnectionless(c.kms, store, msg); err != nil {
+		return "", err
+	}
+
+	return c.service.HandleInbound(msg, "", "")
+}
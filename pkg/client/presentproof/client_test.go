@@ -14,9 +14,14 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/messenger"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/presentproof"
 	mocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/client/presentproof"
+	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
+	mockprovider "github.com/hyperledger/aries-framework-go/pkg/mock/provider"
 )
 
 const (
@@ -246,3 +251,159 @@ func TestClient_NegotiateProposePresentation(t *testing.T) {
 
 	require.NoError(t, client.NegotiateRequestPresentation("PIID", &ProposePresentation{}))
 }
+
+func TestClient_CreateConnectionlessRequestPresentation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("Success", func(t *testing.T) {
+		svc := mocks.NewMockProtocolService(ctrl)
+		svc.EXPECT().HandleInbound(gomock.Any(), "", "").
+			DoAndReturn(func(msg service.DIDCommMsg, _, _ string) (string, error) {
+				require.Equal(t, presentproof.RequestPresentationMsgType, msg.Type())
+				require.NotEmpty(t, msg.ID())
+
+				return msg.ID(), nil
+			})
+
+		client, err := New(&mockprovider.Provider{
+			ServiceValue:         svc,
+			KMSValue:             &mockkms.KeyManager{CrAndExportPubKeyValue: []byte("public key")},
+			StorageProviderValue: mem.NewProvider(),
+			ServiceEndpointValue: "https://example.com",
+		})
+		require.NoError(t, err)
+
+		msg, err := client.CreateConnectionlessRequestPresentation(&RequestPresentation{})
+		require.NoError(t, err)
+
+		thID, err := msg.ThreadID()
+		require.NoError(t, err)
+		require.Equal(t, msg.ID(), thID)
+
+		svcDecorator := struct {
+			Service *decorator.Service `json:"~service"`
+		}{}
+		require.NoError(t, msg.Decode(&svcDecorator))
+		require.Len(t, svcDecorator.Service.RecipientKeys, 1)
+		require.Equal(t, "https://example.com", svcDecorator.Service.ServiceEndpoint)
+	})
+
+	t.Run("Empty request", func(t *testing.T) {
+		client, err := New(&mockprovider.Provider{ServiceValue: mocks.NewMockProtocolService(ctrl)})
+		require.NoError(t, err)
+
+		_, err = client.CreateConnectionlessRequestPresentation(nil)
+		require.EqualError(t, err, errEmptyRequestPresentation.Error())
+	})
+
+	t.Run("Not supported", func(t *testing.T) {
+		provider := mocks.NewMockProvider(ctrl)
+		provider.EXPECT().Service(gomock.Any()).Return(mocks.NewMockProtocolService(ctrl), nil)
+
+		client, err := New(provider)
+		require.NoError(t, err)
+
+		_, err = client.CreateConnectionlessRequestPresentation(&RequestPresentation{})
+		require.True(t, errors.Is(err, messenger.ErrConnectionlessNotSupported))
+	})
+
+	t.Run("Create key error", func(t *testing.T) {
+		client, err := New(&mockprovider.Provider{
+			ServiceValue:         mocks.NewMockProtocolService(ctrl),
+			KMSValue:             &mockkms.KeyManager{CrAndExportPubKeyErr: errors.New("test error")},
+			StorageProviderValue: mem.NewProvider(),
+		})
+		require.NoError(t, err)
+
+		_, err = client.CreateConnectionlessRequestPresentation(&RequestPresentation{})
+		require.EqualError(t, err, "create connectionless key: test error")
+	})
+
+	t.Run("Service error", func(t *testing.T) {
+		svc := mocks.NewMockProtocolService(ctrl)
+		svc.EXPECT().HandleInbound(gomock.Any(), "", "").Return("", errors.New("test error"))
+
+		client, err := New(&mockprovider.Provider{
+			ServiceValue:         svc,
+			KMSValue:             &mockkms.KeyManager{CrAndExportPubKeyValue: []byte("public key")},
+			StorageProviderValue: mem.NewProvider(),
+		})
+		require.NoError(t, err)
+
+		_, err = client.CreateConnectionlessRequestPresentation(&RequestPresentation{})
+		require.EqualError(t, err, "test error")
+	})
+}
+
+func TestClient_AcceptConnectionlessRequestPresentation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	msg := service.NewDIDCommMsgMap(&RequestPresentation{Type: presentproof.RequestPresentationMsgType})
+	msg["@id"] = "msg-id"
+
+	t.Run("Success", func(t *testing.T) {
+		svc := mocks.NewMockProtocolService(ctrl)
+		svc.EXPECT().HandleInbound(msg, "", "").Return("piid", nil)
+
+		provider := &mockprovider.Provider{
+			ServiceValue:         svc,
+			KMSValue:             &mockkms.KeyManager{CrAndExportPubKeyValue: []byte("public key")},
+			StorageProviderValue: mem.NewProvider(),
+		}
+
+		client, err := New(provider)
+		require.NoError(t, err)
+
+		piID, err := client.AcceptConnectionlessRequestPresentation(msg)
+		require.NoError(t, err)
+		require.Equal(t, "piid", piID)
+
+		// the thread is accepted, so the messenger replies to the ~service decorator of its messages
+		store, err := provider.StorageProviderValue.OpenStore(messenger.ConnectionlessStore)
+		require.NoError(t, err)
+
+		_, err = store.Get("thread_msg-id")
+		require.NoError(t, err)
+	})
+
+	t.Run("Empty message", func(t *testing.T) {
+		client, err := New(&mockprovider.Provider{ServiceValue: mocks.NewMockProtocolService(ctrl)})
+		require.NoError(t, err)
+
+		_, err = client.AcceptConnectionlessRequestPresentation(nil)
+		require.EqualError(t, err, errEmptyRequestPresentation.Error())
+	})
+
+	t.Run("Unexpected message type", func(t *testing.T) {
+		client, err := New(&mockprovider.Provider{ServiceValue: mocks.NewMockProtocolService(ctrl)})
+		require.NoError(t, err)
+
+		_, err = client.AcceptConnectionlessRequestPresentation(service.DIDCommMsgMap{"@type": "unknown"})
+		require.EqualError(t, err, "unexpected connectionless message type unknown")
+	})
+
+	t.Run("Not supported", func(t *testing.T) {
+		provider := mocks.NewMockProvider(ctrl)
+		provider.EXPECT().Service(gomock.Any()).Return(mocks.NewMockProtocolService(ctrl), nil)
+
+		client, err := New(provider)
+		require.NoError(t, err)
+
+		_, err = client.AcceptConnectionlessRequestPresentation(msg)
+		require.True(t, errors.Is(err, messenger.ErrConnectionlessNotSupported))
+	})
+
+	t.Run("Create key error", func(t *testing.T) {
+		client, err := New(&mockprovider.Provider{
+			ServiceValue:         mocks.NewMockProtocolService(ctrl),
+			KMSValue:             &mockkms.KeyManager{CrAndExportPubKeyErr: errors.New("test error")},
+			StorageProviderValue: mem.NewProvider(),
+		})
+		require.NoError(t, err)
+
+		_, err = client.AcceptConnectionlessRequestPresentation(msg)
+		require.EqualError(t, err, "create connectionless key: test error")
+	})
+}
@@ -0,0 +1,54 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package trustping
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/trustping"
+)
+
+type provider interface {
+	Service(id string) (interface{}, error)
+}
+
+type protocolService interface {
+	Ping(connectionID string, responseRequested bool, timeout time.Duration) (time.Duration, error)
+}
+
+// Client enable access to trust ping api.
+type Client struct {
+	trustPingSvc protocolService
+}
+
+// New return new instance of trust ping client.
+func New(ctx provider) (*Client, error) {
+	svc, err := ctx.Service(trustping.TrustPing)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trust ping service: %w", err)
+	}
+
+	trustPingSvc, ok := svc.(protocolService)
+	if !ok {
+		return nil, errors.New("cast service to trust ping service failed")
+	}
+
+	return &Client{trustPingSvc: trustPingSvc}, nil
+}
+
+// Ping tests the connection by sending the ping to the counterparty. If the response is requested, waits for it
+// until the timeout and returns the round-trip time. Zero timeout means trustping.DefaultTimeout.
+func (c *Client) Ping(connectionID string, responseRequested bool, timeout time.Duration) (time.Duration, error) {
+	rtt, err := c.trustPingSvc.Ping(connectionID, responseRequested, timeout)
+	if err != nil {
+		return 0, fmt.Errorf("trust ping client - ping: %w", err)
+	}
+
+	return rtt, nil
+}
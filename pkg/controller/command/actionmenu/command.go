@@ -0,0 +1,375 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package actionmenu
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/hyperledger/aries-framework-go/pkg/client/actionmenu"
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/command"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/internal/cmdutil"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/webnotifier"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	protocol "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/actionmenu"
+	"github.com/hyperledger/aries-framework-go/pkg/internal/logutil"
+)
+
+var logger = log.New("aries-framework/controller/actionmenu")
+
+// Error codes.
+const (
+	// InvalidRequestErrorCode is typically a code for invalid requests.
+	InvalidRequestErrorCode = command.Code(iota + command.ActionMenu)
+	// ActionsErrorCode is for failures in actions command.
+	ActionsErrorCode
+	// PublishMenuErrorCode is for failures in publish menu command.
+	PublishMenuErrorCode
+	// SendMenuErrorCode is for failures in send menu command.
+	SendMenuErrorCode
+	// RequestMenuErrorCode is for failures in request menu command.
+	RequestMenuErrorCode
+	// GetMenuErrorCode is for failures in get menu command.
+	GetMenuErrorCode
+	// PerformErrorCode is for failures in perform command.
+	PerformErrorCode
+	// AcceptMenuRequestErrorCode is for failures in accept menu request command.
+	AcceptMenuRequestErrorCode
+	// DeclineMenuRequestErrorCode is for failures in decline menu request command.
+	DeclineMenuRequestErrorCode
+	// AcceptPerformErrorCode is for failures in accept perform command.
+	AcceptPerformErrorCode
+	// DeclinePerformErrorCode is for failures in decline perform command.
+	DeclinePerformErrorCode
+)
+
+// constants for the action menu controller.
+const (
+	// command name.
+	CommandName = "actionmenu"
+
+	// command methods.
+	Actions            = "Actions"
+	PublishMenu        = "PublishMenu"
+	SendMenu           = "SendMenu"
+	RequestMenu        = "RequestMenu"
+	GetMenu            = "GetMenu"
+	Perform            = "Perform"
+	AcceptMenuRequest  = "AcceptMenuRequest"
+	DeclineMenuRequest = "DeclineMenuRequest"
+	AcceptPerform      = "AcceptPerform"
+	DeclinePerform     = "DeclinePerform"
+
+	// error messages.
+	errEmptyConnectionID = "empty connectionID"
+	errEmptyMenu         = "empty menu"
+	errEmptyName         = "empty name"
+	errEmptyPIID         = "empty piid"
+
+	// log constants.
+	connectionID  = "connectionID"
+	successString = "success"
+
+	_actions = "_actions"
+	_states  = "_states"
+)
+
+// Command is controller command for action menu.
+type Command struct {
+	client *actionmenu.Client
+}
+
+// New returns new action menu controller command instance.
+func New(ctx actionmenu.Provider, notifier command.Notifier) (*Command, error) {
+	client, err := actionmenu.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create a client: %w", err)
+	}
+
+	// creates action channel
+	actions := make(chan service.DIDCommAction)
+	// registers action channel to listen for events
+	if err := client.RegisterActionEvent(actions); err != nil {
+		return nil, fmt.Errorf("register action event: %w", err)
+	}
+
+	// creates state channel
+	states := make(chan service.StateMsg)
+	// registers state channel to listen for events
+	if err := client.RegisterMsgEvent(states); err != nil {
+		return nil, fmt.Errorf("register msg event: %w", err)
+	}
+
+	obs := webnotifier.NewObserver(notifier)
+	obs.RegisterAction(protocol.ActionMenu+_actions, actions)
+	obs.RegisterStateMsg(protocol.ActionMenu+_states, states)
+
+	return &Command{client: client}, nil
+}
+
+// GetHandlers returns list of all commands supported by this controller command.
+func (c *Command) GetHandlers() []command.Handler {
+	return []command.Handler{
+		cmdutil.NewCommandHandler(CommandName, Actions, c.Actions),
+		cmdutil.NewCommandHandler(CommandName, PublishMenu, c.PublishMenu),
+		cmdutil.NewCommandHandler(CommandName, SendMenu, c.SendMenu),
+		cmdutil.NewCommandHandler(CommandName, RequestMenu, c.RequestMenu),
+		cmdutil.NewCommandHandler(CommandName, GetMenu, c.GetMenu),
+		cmdutil.NewCommandHandler(CommandName, Perform, c.Perform),
+		cmdutil.NewCommandHandler(CommandName, AcceptMenuRequest, c.AcceptMenuRequest),
+		cmdutil.NewCommandHandler(CommandName, DeclineMenuRequest, c.DeclineMenuRequest),
+		cmdutil.NewCommandHandler(CommandName, AcceptPerform, c.AcceptPerform),
+		cmdutil.NewCommandHandler(CommandName, DeclinePerform, c.DeclinePerform),
+	}
+}
+
+// Actions returns pending actions that have not yet to be executed or canceled.
+func (c *Command) Actions(rw io.Writer, _ io.Reader) command.Error {
+	result, err := c.client.Actions()
+	if err != nil {
+		logutil.LogError(logger, CommandName, Actions, err.Error())
+		return command.NewExecuteError(ActionsErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, &ActionsResponse{
+		Actions: result,
+	}, logger)
+
+	logutil.LogDebug(logger, CommandName, Actions, successString)
+
+	return nil
+}
+
+// PublishMenu publishes the menu for the connection, the menu is sent when the connection requests it.
+func (c *Command) PublishMenu(rw io.Writer, req io.Reader) command.Error {
+	var args MenuArgs
+
+	if err := decodeMenuArgs(PublishMenu, req, &args); err != nil {
+		return err
+	}
+
+	if err := c.client.PublishMenu(args.ConnectionID, args.Menu); err != nil {
+		logutil.LogError(logger, CommandName, PublishMenu, err.Error(),
+			logutil.CreateKeyValueString(connectionID, args.ConnectionID))
+		return command.NewExecuteError(PublishMenuErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, nil, logger)
+
+	logutil.LogDebug(logger, CommandName, PublishMenu, successString,
+		logutil.CreateKeyValueString(connectionID, args.ConnectionID))
+
+	return nil
+}
+
+// SendMenu publishes the menu for the connection and sends it to the connection.
+func (c *Command) SendMenu(rw io.Writer, req io.Reader) command.Error {
+	var args MenuArgs
+
+	if err := decodeMenuArgs(SendMenu, req, &args); err != nil {
+		return err
+	}
+
+	if err := c.client.SendMenu(args.ConnectionID, args.Menu); err != nil {
+		logutil.LogError(logger, CommandName, SendMenu, err.Error(),
+			logutil.CreateKeyValueString(connectionID, args.ConnectionID))
+		return command.NewExecuteError(SendMenuErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, nil, logger)
+
+	logutil.LogDebug(logger, CommandName, SendMenu, successString,
+		logutil.CreateKeyValueString(connectionID, args.ConnectionID))
+
+	return nil
+}
+
+// RequestMenu requests the menu from the connection.
+func (c *Command) RequestMenu(rw io.Writer, req io.Reader) command.Error {
+	var args ConnectionArgs
+
+	if err := decodeConnectionArgs(RequestMenu, req, &args); err != nil {
+		return err
+	}
+
+	if err := c.client.RequestMenu(args.ConnectionID); err != nil {
+		logutil.LogError(logger, CommandName, RequestMenu, err.Error(),
+			logutil.CreateKeyValueString(connectionID, args.ConnectionID))
+		return command.NewExecuteError(RequestMenuErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, nil, logger)
+
+	logutil.LogDebug(logger, CommandName, RequestMenu, successString,
+		logutil.CreateKeyValueString(connectionID, args.ConnectionID))
+
+	return nil
+}
+
+// GetMenu returns the last menu received from the connection.
+func (c *Command) GetMenu(rw io.Writer, req io.Reader) command.Error {
+	var args ConnectionArgs
+
+	if err := decodeConnectionArgs(GetMenu, req, &args); err != nil {
+		return err
+	}
+
+	menu, err := c.client.Menu(args.ConnectionID)
+	if err != nil {
+		logutil.LogError(logger, CommandName, GetMenu, err.Error(),
+			logutil.CreateKeyValueString(connectionID, args.ConnectionID))
+		return command.NewExecuteError(GetMenuErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, &MenuResponse{Menu: menu}, logger)
+
+	logutil.LogDebug(logger, CommandName, GetMenu, successString,
+		logutil.CreateKeyValueString(connectionID, args.ConnectionID))
+
+	return nil
+}
+
+// Perform asks the connection to perform the option of its menu.
+func (c *Command) Perform(rw io.Writer, req io.Reader) command.Error {
+	var args PerformArgs
+
+	if err := json.NewDecoder(req).Decode(&args); err != nil {
+		logutil.LogInfo(logger, CommandName, Perform, err.Error())
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	if args.ConnectionID == "" {
+		logutil.LogDebug(logger, CommandName, Perform, errEmptyConnectionID)
+		return command.NewValidationError(InvalidRequestErrorCode, errors.New(errEmptyConnectionID))
+	}
+
+	if args.Name == "" {
+		logutil.LogDebug(logger, CommandName, Perform, errEmptyName)
+		return command.NewValidationError(InvalidRequestErrorCode, errors.New(errEmptyName))
+	}
+
+	if err := c.client.Perform(args.ConnectionID, args.Name, args.Params); err != nil {
+		logutil.LogError(logger, CommandName, Perform, err.Error(),
+			logutil.CreateKeyValueString(connectionID, args.ConnectionID))
+		return command.NewExecuteError(PerformErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, nil, logger)
+
+	logutil.LogDebug(logger, CommandName, Perform, successString,
+		logutil.CreateKeyValueString(connectionID, args.ConnectionID))
+
+	return nil
+}
+
+// AcceptMenuRequest sends the menu in response to the menu request.
+func (c *Command) AcceptMenuRequest(rw io.Writer, req io.Reader) command.Error {
+	return c.accept(AcceptMenuRequest, AcceptMenuRequestErrorCode, c.client.AcceptMenuRequest, rw, req)
+}
+
+// DeclineMenuRequest ignores the menu request.
+func (c *Command) DeclineMenuRequest(rw io.Writer, req io.Reader) command.Error {
+	return c.decline(DeclineMenuRequest, DeclineMenuRequestErrorCode, c.client.DeclineMenuRequest, rw, req)
+}
+
+// AcceptPerform is used when the action of the perform is done, the menu is sent in response if it's provided.
+func (c *Command) AcceptPerform(rw io.Writer, req io.Reader) command.Error {
+	return c.accept(AcceptPerform, AcceptPerformErrorCode, c.client.AcceptPerform, rw, req)
+}
+
+// DeclinePerform ignores the perform.
+func (c *Command) DeclinePerform(rw io.Writer, req io.Reader) command.Error {
+	return c.decline(DeclinePerform, DeclinePerformErrorCode, c.client.DeclinePerform, rw, req)
+}
+
+func (c *Command) accept(method string, code command.Code, accept func(string, *actionmenu.Menu) error,
+	rw io.Writer, req io.Reader) command.Error {
+	var args AcceptArgs
+
+	if err := json.NewDecoder(req).Decode(&args); err != nil {
+		logutil.LogInfo(logger, CommandName, method, err.Error())
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	if args.PIID == "" {
+		logutil.LogDebug(logger, CommandName, method, errEmptyPIID)
+		return command.NewValidationError(InvalidRequestErrorCode, errors.New(errEmptyPIID))
+	}
+
+	if err := accept(args.PIID, args.Menu); err != nil {
+		logutil.LogError(logger, CommandName, method, err.Error())
+		return command.NewExecuteError(code, err)
+	}
+
+	command.WriteNillableResponse(rw, nil, logger)
+
+	logutil.LogDebug(logger, CommandName, method, successString)
+
+	return nil
+}
+
+func (c *Command) decline(method string, code command.Code, decline func(string) error,
+	rw io.Writer, req io.Reader) command.Error {
+	var args DeclineArgs
+
+	if err := json.NewDecoder(req).Decode(&args); err != nil {
+		logutil.LogInfo(logger, CommandName, method, err.Error())
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	if args.PIID == "" {
+		logutil.LogDebug(logger, CommandName, method, errEmptyPIID)
+		return command.NewValidationError(InvalidRequestErrorCode, errors.New(errEmptyPIID))
+	}
+
+	if err := decline(args.PIID); err != nil {
+		logutil.LogError(logger, CommandName, method, err.Error())
+		return command.NewExecuteError(code, err)
+	}
+
+	command.WriteNillableResponse(rw, nil, logger)
+
+	logutil.LogDebug(logger, CommandName, method, successString)
+
+	return nil
+}
+
+func decodeMenuArgs(method string, req io.Reader, args *MenuArgs) command.Error {
+	if err := json.NewDecoder(req).Decode(args); err != nil {
+		logutil.LogInfo(logger, CommandName, method, err.Error())
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	if args.ConnectionID == "" {
+		logutil.LogDebug(logger, CommandName, method, errEmptyConnectionID)
+		return command.NewValidationError(InvalidRequestErrorCode, errors.New(errEmptyConnectionID))
+	}
+
+	if args.Menu == nil {
+		logutil.LogDebug(logger, CommandName, method, errEmptyMenu)
+		return command.NewValidationError(InvalidRequestErrorCode, errors.New(errEmptyMenu))
+	}
+
+	return nil
+}
+
+func decodeConnectionArgs(method string, req io.Reader, args *ConnectionArgs) command.Error {
+	if err := json.NewDecoder(req).Decode(args); err != nil {
+		logutil.LogInfo(logger, CommandName, method, err.Error())
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	if args.ConnectionID == "" {
+		logutil.LogDebug(logger, CommandName, method, errEmptyConnectionID)
+		return command.NewValidationError(InvalidRequestErrorCode, errors.New(errEmptyConnectionID))
+	}
+
+	return nil
+}
@@ -0,0 +1,224 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package actionmenu
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/controller/command"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/actionmenu"
+	mocknotifier "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/controller/webnotifier"
+	mockactionmenu "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol/actionmenu"
+	mockprovider "github.com/hyperledger/aries-framework-go/pkg/mock/provider"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		cmd, err := New(&mockprovider.Provider{
+			ServiceValue: &mockactionmenu.MockActionMenuSvc{},
+		}, mocknotifier.NewMockNotifier(nil))
+		require.NoError(t, err)
+		require.NotNil(t, cmd)
+		require.Len(t, cmd.GetHandlers(), 10)
+	})
+
+	t.Run("client creation fails", func(t *testing.T) {
+		_, err := New(&mockprovider.Provider{
+			ServiceErr: errors.New("service error"),
+		}, mocknotifier.NewMockNotifier(nil))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "cannot create a client")
+	})
+}
+
+func TestCommand_Menus(t *testing.T) {
+	menu := &actionmenu.Menu{Title: "Issuer bot", Options: []actionmenu.MenuOption{{Name: "issue"}}}
+
+	t.Run("success", func(t *testing.T) {
+		cmd := newCommand(t, &mockactionmenu.MockActionMenuSvc{MenuValue: menu})
+
+		for _, exec := range []command.Exec{cmd.PublishMenu, cmd.SendMenu} {
+			var b bytes.Buffer
+
+			require.NoError(t, exec(&b, toReader(t, &MenuArgs{ConnectionID: "conn-1", Menu: menu})))
+		}
+
+		var b bytes.Buffer
+
+		require.NoError(t, cmd.RequestMenu(&b, toReader(t, &ConnectionArgs{ConnectionID: "conn-1"})))
+
+		b.Reset()
+		require.NoError(t, cmd.GetMenu(&b, toReader(t, &ConnectionArgs{ConnectionID: "conn-1"})))
+
+		resp := &MenuResponse{}
+		require.NoError(t, json.Unmarshal(b.Bytes(), resp))
+		require.Equal(t, menu, resp.Menu)
+
+		b.Reset()
+		require.NoError(t, cmd.Perform(&b, toReader(t, &PerformArgs{ConnectionID: "conn-1", Name: "issue"})))
+	})
+
+	t.Run("validation errors", func(t *testing.T) {
+		cmd := newCommand(t, &mockactionmenu.MockActionMenuSvc{})
+
+		tests := []struct {
+			exec command.Exec
+			req  string
+			err  string
+		}{
+			{exec: cmd.PublishMenu, req: `{`, err: "unexpected EOF"},
+			{exec: cmd.PublishMenu, req: `{}`, err: errEmptyConnectionID},
+			{exec: cmd.SendMenu, req: `{"connectionID":"conn-1"}`, err: errEmptyMenu},
+			{exec: cmd.RequestMenu, req: `{`, err: "unexpected EOF"},
+			{exec: cmd.GetMenu, req: `{}`, err: errEmptyConnectionID},
+			{exec: cmd.Perform, req: `{`, err: "unexpected EOF"},
+			{exec: cmd.Perform, req: `{}`, err: errEmptyConnectionID},
+			{exec: cmd.Perform, req: `{"connectionID":"conn-1"}`, err: errEmptyName},
+			{exec: cmd.AcceptMenuRequest, req: `{`, err: "unexpected EOF"},
+			{exec: cmd.AcceptPerform, req: `{}`, err: errEmptyPIID},
+			{exec: cmd.DeclineMenuRequest, req: `{`, err: "unexpected EOF"},
+			{exec: cmd.DeclinePerform, req: `{}`, err: errEmptyPIID},
+		}
+
+		for _, tc := range tests {
+			var b bytes.Buffer
+
+			err := tc.exec(&b, bytes.NewBufferString(tc.req))
+			require.Error(t, err)
+			require.Equal(t, InvalidRequestErrorCode, err.Code())
+			require.Equal(t, command.ValidationError, err.Type())
+			require.Contains(t, err.Error(), tc.err)
+		}
+	})
+
+	t.Run("execute errors", func(t *testing.T) {
+		serviceErr := errors.New("service error")
+
+		cmd := newCommand(t, &mockactionmenu.MockActionMenuSvc{
+			PublishMenuFunc: func(string, *actionmenu.Menu) error { return serviceErr },
+			SendMenuFunc:    func(string, *actionmenu.Menu) error { return serviceErr },
+			PerformFunc:     func(string, string, map[string]string) error { return serviceErr },
+			RequestMenuErr:  serviceErr,
+			MenuErr:         serviceErr,
+		})
+
+		menuArgs := &MenuArgs{ConnectionID: "conn-1", Menu: menu}
+		connArgs := &ConnectionArgs{ConnectionID: "conn-1"}
+
+		tests := []struct {
+			exec command.Exec
+			args interface{}
+			code command.Code
+		}{
+			{exec: cmd.PublishMenu, args: menuArgs, code: PublishMenuErrorCode},
+			{exec: cmd.SendMenu, args: menuArgs, code: SendMenuErrorCode},
+			{exec: cmd.RequestMenu, args: connArgs, code: RequestMenuErrorCode},
+			{exec: cmd.GetMenu, args: connArgs, code: GetMenuErrorCode},
+			{exec: cmd.Perform, args: &PerformArgs{ConnectionID: "conn-1", Name: "issue"}, code: PerformErrorCode},
+		}
+
+		for _, tc := range tests {
+			var b bytes.Buffer
+
+			err := tc.exec(&b, toReader(t, tc.args))
+			require.Error(t, err)
+			require.Equal(t, tc.code, err.Code())
+			require.Equal(t, command.ExecuteError, err.Type())
+			require.Contains(t, err.Error(), "service error")
+		}
+	})
+}
+
+func TestCommand_Actions(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		var continued []string
+
+		cmd := newCommand(t, &mockactionmenu.MockActionMenuSvc{
+			ActionsValue: []actionmenu.Action{{PIID: "piid-1", ConnectionID: "conn-1"}},
+			ActionContinueFunc: func(piID string, opt actionmenu.Opt) error {
+				continued = append(continued, piID)
+
+				return nil
+			},
+		})
+
+		var b bytes.Buffer
+
+		require.NoError(t, cmd.Actions(&b, nil))
+
+		resp := &ActionsResponse{}
+		require.NoError(t, json.Unmarshal(b.Bytes(), resp))
+		require.Len(t, resp.Actions, 1)
+		require.Equal(t, "conn-1", resp.Actions[0].ConnectionID)
+
+		require.NoError(t, cmd.AcceptMenuRequest(&b, toReader(t, &AcceptArgs{PIID: "piid-1"})))
+		require.NoError(t, cmd.AcceptPerform(&b, toReader(t, &AcceptArgs{
+			PIID: "piid-2", Menu: &actionmenu.Menu{Title: "Issued"},
+		})))
+		require.Equal(t, []string{"piid-1", "piid-2"}, continued)
+
+		require.NoError(t, cmd.DeclineMenuRequest(&b, toReader(t, &DeclineArgs{PIID: "piid-1"})))
+		require.NoError(t, cmd.DeclinePerform(&b, toReader(t, &DeclineArgs{PIID: "piid-2"})))
+	})
+
+	t.Run("execute errors", func(t *testing.T) {
+		serviceErr := errors.New("service error")
+
+		cmd := newCommand(t, &mockactionmenu.MockActionMenuSvc{
+			ActionsErr:         serviceErr,
+			ActionContinueFunc: func(string, actionmenu.Opt) error { return serviceErr },
+			ActionStopErr:      serviceErr,
+		})
+
+		var b bytes.Buffer
+
+		err := cmd.Actions(&b, nil)
+		require.Error(t, err)
+		require.Equal(t, ActionsErrorCode, err.Code())
+
+		tests := []struct {
+			exec command.Exec
+			args interface{}
+			code command.Code
+		}{
+			{exec: cmd.AcceptMenuRequest, args: &AcceptArgs{PIID: "piid-1"}, code: AcceptMenuRequestErrorCode},
+			{exec: cmd.AcceptPerform, args: &AcceptArgs{PIID: "piid-1"}, code: AcceptPerformErrorCode},
+			{exec: cmd.DeclineMenuRequest, args: &DeclineArgs{PIID: "piid-1"}, code: DeclineMenuRequestErrorCode},
+			{exec: cmd.DeclinePerform, args: &DeclineArgs{PIID: "piid-1"}, code: DeclinePerformErrorCode},
+		}
+
+		for _, tc := range tests {
+			err := tc.exec(&b, toReader(t, tc.args))
+			require.Error(t, err)
+			require.Equal(t, tc.code, err.Code())
+			require.Equal(t, command.ExecuteError, err.Type())
+		}
+	})
+}
+
+func newCommand(t *testing.T, svc *mockactionmenu.MockActionMenuSvc) *Command {
+	t.Helper()
+
+	cmd, err := New(&mockprovider.Provider{ServiceValue: svc}, mocknotifier.NewMockNotifier(nil))
+	require.NoError(t, err)
+
+	return cmd
+}
+
+func toReader(t *testing.T, v interface{}) *bytes.Buffer {
+	t.Helper()
+
+	src, err := json.Marshal(v)
+	require.NoError(t, err)
+
+	return bytes.NewBuffer(src)
+}
@@ -0,0 +1,80 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package actionmenu
+
+import (
+	"github.com/hyperledger/aries-framework-go/pkg/client/actionmenu"
+)
+
+// ActionsResponse model
+//
+// Represents Actions response message.
+//
+type ActionsResponse struct {
+	Actions []actionmenu.Action `json:"actions"`
+}
+
+// MenuArgs model
+//
+// This is used for publishing or sending the menu of the connection.
+//
+type MenuArgs struct {
+	// ConnectionID of the connection the menu is for.
+	ConnectionID string `json:"connectionID"`
+	// Menu to publish or send.
+	Menu *actionmenu.Menu `json:"menu"`
+}
+
+// ConnectionArgs model
+//
+// This is used for requesting or getting the menu of the connection.
+//
+type ConnectionArgs struct {
+	// ConnectionID of the connection.
+	ConnectionID string `json:"connectionID"`
+}
+
+// MenuResponse model
+//
+// Represents the menu of the connection.
+//
+type MenuResponse struct {
+	Menu *actionmenu.Menu `json:"menu"`
+}
+
+// PerformArgs model
+//
+// This is used for asking the connection to perform the option of its menu.
+//
+type PerformArgs struct {
+	// ConnectionID of the connection which offers the menu.
+	ConnectionID string `json:"connectionID"`
+	// Name of the menu option.
+	Name string `json:"name"`
+	// Params are the values of the option form.
+	Params map[string]string `json:"params,omitempty"`
+}
+
+// AcceptArgs model
+//
+// This is used for accepting the menu request or the perform.
+//
+type AcceptArgs struct {
+	// PIID Protocol instance ID
+	PIID string `json:"piid"`
+	// Menu to send in response, the published menu is sent in response to the menu request by default.
+	Menu *actionmenu.Menu `json:"menu,omitempty"`
+}
+
+// DeclineArgs model
+//
+// This is used for declining the menu request or the perform.
+//
+type DeclineArgs struct {
+	// PIID Protocol instance ID
+	PIID string `json:"piid"`
+}
@@ -64,6 +64,15 @@ const (
 
 	// Outofband error group for outofband command errors.
 	Outofband = 11000
+
+	// TrustPing error group for trust ping command errors.
+	TrustPing = 12000
+
+	// ActionMenu error group for action menu command errors.
+	ActionMenu = 13000
+
+	// Outbound error group for outbound queue command errors.
+	Outbound = 14000
 )
 
 // Error is the  interface for representing an command error condition, with the nil value representing no error.
@@ -0,0 +1,189 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package outbound
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/command"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/internal/cmdutil"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/dispatcher"
+	"github.com/hyperledger/aries-framework-go/pkg/internal/logutil"
+)
+
+var logger = log.New("aries-framework/controller/outbound")
+
+// Error codes.
+const (
+	// InvalidRequestErrorCode is typically a code for invalid requests.
+	InvalidRequestErrorCode = command.Code(iota + command.Outbound)
+	// DeadLettersErrorCode is for failures in dead letters command.
+	DeadLettersErrorCode
+	// ReplayDeadLetterErrorCode is for failures in replay dead letter command.
+	ReplayDeadLetterErrorCode
+	// RemoveDeadLetterErrorCode is for failures in remove dead letter command.
+	RemoveDeadLetterErrorCode
+)
+
+// constants for the outbound queue controller.
+const (
+	// command name.
+	CommandName = "outbound"
+
+	// command methods.
+	DeadLetters      = "DeadLetters"
+	ReplayDeadLetter = "ReplayDeadLetter"
+	RemoveDeadLetter = "RemoveDeadLetter"
+
+	// DeliveryTopic is the topic of the delivery events of the queued messages.
+	DeliveryTopic = "outbound_delivery"
+
+	// the queue drops the delivery events when the channel is full.
+	deliveryEventsBuffer = 100
+
+	// error messages.
+	errEmptyID = "empty id"
+
+	// log constants.
+	messageID     = "messageID"
+	successString = "success"
+)
+
+// errQueueDisabled is returned when the framework was created without the outbound queue.
+var errQueueDisabled = errors.New("outbound queue is not enabled")
+
+// provider contains dependencies for the outbound queue command and is typically created by using aries.Context().
+type provider interface {
+	OutboundQueue() *dispatcher.OutboundQueue
+}
+
+// Command is controller command for the outbound message queue.
+type Command struct {
+	queue *dispatcher.OutboundQueue
+}
+
+// New returns new outbound queue controller command instance. The delivery events of the queued messages
+// are sent to the notifier if the outbound queue is enabled.
+func New(ctx provider, notifier command.Notifier) (*Command, error) {
+	queue := ctx.OutboundQueue()
+	if queue == nil {
+		return &Command{}, nil
+	}
+
+	events := make(chan dispatcher.DeliveryEvent, deliveryEventsBuffer)
+
+	if err := queue.RegisterDeliveryEvent(events); err != nil {
+		return nil, fmt.Errorf("register delivery event: %w", err)
+	}
+
+	go func() {
+		for event := range events {
+			notifyDelivery(notifier, event)
+		}
+	}()
+
+	return &Command{queue: queue}, nil
+}
+
+// GetHandlers returns list of all commands supported by this controller command.
+func (c *Command) GetHandlers() []command.Handler {
+	return []command.Handler{
+		cmdutil.NewCommandHandler(CommandName, DeadLetters, c.DeadLetters),
+		cmdutil.NewCommandHandler(CommandName, ReplayDeadLetter, c.ReplayDeadLetter),
+		cmdutil.NewCommandHandler(CommandName, RemoveDeadLetter, c.RemoveDeadLetter),
+	}
+}
+
+// DeadLetters returns the messages which failed to be delivered.
+func (c *Command) DeadLetters(rw io.Writer, _ io.Reader) command.Error {
+	if c.queue == nil {
+		logutil.LogError(logger, CommandName, DeadLetters, errQueueDisabled.Error())
+
+		return command.NewExecuteError(DeadLettersErrorCode, errQueueDisabled)
+	}
+
+	deadLetters, err := c.queue.DeadLetters()
+	if err != nil {
+		logutil.LogError(logger, CommandName, DeadLetters, err.Error())
+
+		return command.NewExecuteError(DeadLettersErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, &DeadLettersResponse{DeadLetters: deadLetters}, logger)
+
+	logutil.LogDebug(logger, CommandName, DeadLetters, successString)
+
+	return nil
+}
+
+// ReplayDeadLetter moves the dead letter back to the queue to be delivered again.
+func (c *Command) ReplayDeadLetter(rw io.Writer, req io.Reader) command.Error {
+	return c.handleDeadLetter(ReplayDeadLetter, ReplayDeadLetterErrorCode, rw, req, func(id string) error {
+		return c.queue.Replay(id)
+	})
+}
+
+// RemoveDeadLetter removes the dead letter.
+func (c *Command) RemoveDeadLetter(rw io.Writer, req io.Reader) command.Error {
+	return c.handleDeadLetter(RemoveDeadLetter, RemoveDeadLetterErrorCode, rw, req, func(id string) error {
+		return c.queue.RemoveDeadLetter(id)
+	})
+}
+
+func (c *Command) handleDeadLetter(method string, code command.Code, rw io.Writer, req io.Reader,
+	handle func(id string) error) command.Error {
+	var args DeadLetterArgs
+
+	if err := json.NewDecoder(req).Decode(&args); err != nil {
+		logutil.LogInfo(logger, CommandName, method, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, fmt.Errorf("request decode : %w", err))
+	}
+
+	if args.ID == "" {
+		logutil.LogDebug(logger, CommandName, method, errEmptyID)
+
+		return command.NewValidationError(InvalidRequestErrorCode, errors.New(errEmptyID))
+	}
+
+	if c.queue == nil {
+		logutil.LogError(logger, CommandName, method, errQueueDisabled.Error())
+
+		return command.NewExecuteError(code, errQueueDisabled)
+	}
+
+	if err := handle(args.ID); err != nil {
+		logutil.LogError(logger, CommandName, method, err.Error(),
+			logutil.CreateKeyValueString(messageID, args.ID))
+
+		return command.NewExecuteError(code, err)
+	}
+
+	command.WriteNillableResponse(rw, nil, logger)
+
+	logutil.LogDebug(logger, CommandName, method, successString,
+		logutil.CreateKeyValueString(messageID, args.ID))
+
+	return nil
+}
+
+func notifyDelivery(notifier command.Notifier, event dispatcher.DeliveryEvent) {
+	msg, err := json.Marshal(event)
+	if err != nil {
+		logger.Errorf("marshal delivery event: %s", err)
+
+		return
+	}
+
+	if err := notifier.Notify(DeliveryTopic, msg); err != nil {
+		logger.Warnf("notify delivery event: %s", err)
+	}
+}
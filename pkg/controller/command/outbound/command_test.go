@@ -0,0 +1,194 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package outbound
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/command"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/dispatcher"
+	"github.com/hyperledger/aries-framework-go/pkg/framework/context"
+	mockdidcomm "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm"
+	mockpackager "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/packager"
+	mockdiddoc "github.com/hyperledger/aries-framework-go/pkg/mock/diddoc"
+	mockprovider "github.com/hyperledger/aries-framework-go/pkg/mock/provider"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		cmd, err := New(&mockprovider.Provider{OutboundQueueValue: newQueue(t)}, &notifier{})
+		require.NoError(t, err)
+		require.NotNil(t, cmd)
+		require.Len(t, cmd.GetHandlers(), 3)
+	})
+
+	t.Run("queue is not enabled", func(t *testing.T) {
+		cmd, err := New(&mockprovider.Provider{}, &notifier{})
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+
+		cmdErr := cmd.DeadLetters(&b, nil)
+		require.Error(t, cmdErr)
+		require.Equal(t, DeadLettersErrorCode, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), "outbound queue is not enabled")
+
+		cmdErr = cmd.ReplayDeadLetter(&b, toReader(t, &DeadLetterArgs{ID: "msg-1"}))
+		require.Error(t, cmdErr)
+		require.Equal(t, ReplayDeadLetterErrorCode, cmdErr.Code())
+		require.Equal(t, command.ExecuteError, cmdErr.Type())
+	})
+}
+
+func TestCommand_DeadLetters(t *testing.T) {
+	t.Run("failed message is dead lettered, replayed and removed", func(t *testing.T) {
+		queue := newQueue(t)
+		events := &notifier{events: make(chan []byte)}
+
+		cmd, err := New(&mockprovider.Provider{OutboundQueueValue: queue}, events)
+		require.NoError(t, err)
+
+		ctx, err := context.New(
+			context.WithPackager(&mockpackager.Packager{}),
+			context.WithOutboundTransports(
+				&mockdidcomm.MockOutboundTransport{SendErr: errors.New("send error"), AcceptValue: true}),
+		)
+		require.NoError(t, err)
+
+		o := dispatcher.NewOutbound(ctx, dispatcher.WithOutboundQueue(queue))
+
+		go func() {
+			require.NoError(t, o.Send("data", mockdiddoc.MockDIDKey(t), &service.Destination{ServiceEndpoint: "url"}))
+		}()
+
+		event := waitForStatus(t, events, dispatcher.DeliveryDeadLettered)
+		require.Equal(t, "url", event.ServiceEndpoint)
+
+		var b bytes.Buffer
+
+		require.NoError(t, cmd.DeadLetters(&b, nil))
+
+		resp := DeadLettersResponse{}
+		require.NoError(t, json.Unmarshal(b.Bytes(), &resp))
+		require.Len(t, resp.DeadLetters, 1)
+		require.Equal(t, event.MessageID, resp.DeadLetters[0].ID)
+		require.Equal(t, "send error", resp.DeadLetters[0].LastError)
+
+		b.Reset()
+		require.NoError(t, cmd.ReplayDeadLetter(&b, toReader(t, &DeadLetterArgs{ID: event.MessageID})))
+
+		// the destination is still down, the message is dead lettered again
+		waitForStatus(t, events, dispatcher.DeliveryDeadLettered)
+
+		b.Reset()
+		require.NoError(t, cmd.RemoveDeadLetter(&b, toReader(t, &DeadLetterArgs{ID: event.MessageID})))
+
+		b.Reset()
+		require.NoError(t, cmd.DeadLetters(&b, nil))
+		require.NoError(t, json.Unmarshal(b.Bytes(), &resp))
+		require.Empty(t, resp.DeadLetters)
+	})
+
+	t.Run("dead letter not found", func(t *testing.T) {
+		cmd, err := New(&mockprovider.Provider{OutboundQueueValue: newQueue(t)}, &notifier{})
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+
+		cmdErr := cmd.ReplayDeadLetter(&b, toReader(t, &DeadLetterArgs{ID: "msg-1"}))
+		require.Error(t, cmdErr)
+		require.Equal(t, ReplayDeadLetterErrorCode, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), dispatcher.ErrDeadLetterNotFound.Error())
+
+		cmdErr = cmd.RemoveDeadLetter(&b, toReader(t, &DeadLetterArgs{ID: "msg-1"}))
+		require.Error(t, cmdErr)
+		require.Equal(t, RemoveDeadLetterErrorCode, cmdErr.Code())
+	})
+
+	t.Run("validation errors", func(t *testing.T) {
+		cmd, err := New(&mockprovider.Provider{OutboundQueueValue: newQueue(t)}, &notifier{})
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+
+		cmdErr := cmd.ReplayDeadLetter(&b, bytes.NewBufferString("{"))
+		require.Error(t, cmdErr)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+		require.Equal(t, command.ValidationError, cmdErr.Type())
+
+		cmdErr = cmd.RemoveDeadLetter(&b, toReader(t, &DeadLetterArgs{}))
+		require.Error(t, cmdErr)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+		require.Contains(t, cmdErr.Error(), errEmptyID)
+	})
+}
+
+func newQueue(t *testing.T) *dispatcher.OutboundQueue {
+	t.Helper()
+
+	queue, err := dispatcher.NewOutboundQueue(mem.NewProvider(), dispatcher.WithMaxAge(time.Millisecond),
+		dispatcher.WithPollInterval(time.Millisecond), dispatcher.WithRetryInterval(time.Millisecond, time.Millisecond))
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		require.NoError(t, queue.Close())
+	})
+
+	return queue
+}
+
+func waitForStatus(t *testing.T, n *notifier, status dispatcher.DeliveryStatus) dispatcher.DeliveryEvent {
+	t.Helper()
+
+	for {
+		select {
+		case msg := <-n.events:
+			event := dispatcher.DeliveryEvent{}
+			require.NoError(t, json.Unmarshal(msg, &event))
+
+			if event.Status == status {
+				return event
+			}
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "timeout waiting for the delivery event", status)
+		}
+	}
+}
+
+func toReader(t *testing.T, v interface{}) *bytes.Buffer {
+	t.Helper()
+
+	src, err := json.Marshal(v)
+	require.NoError(t, err)
+
+	return bytes.NewBuffer(src)
+}
+
+// notifier passes the delivery events to the channel if it's set.
+type notifier struct {
+	events chan []byte
+}
+
+func (n *notifier) Notify(topic string, message []byte) error {
+	if topic != DeliveryTopic {
+		return errors.New("unexpected topic")
+	}
+
+	if n.events != nil {
+		n.events <- message
+	}
+
+	return nil
+}
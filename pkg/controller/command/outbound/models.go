@@ -0,0 +1,20 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package outbound
+
+import "github.com/hyperledger/aries-framework-go/pkg/didcomm/dispatcher"
+
+// DeadLettersResponse is response model for the dead letters of the outbound queue.
+type DeadLettersResponse struct {
+	DeadLetters []*dispatcher.QueuedMessage `json:"dead_letters"`
+}
+
+// DeadLetterArgs contains the ID of the dead letter to replay or remove.
+type DeadLetterArgs struct {
+	// ID of the dead letter.
+	ID string `json:"id"`
+}
@@ -41,6 +41,10 @@ const (
 	ActionsErrorCode
 	// ActionContinueErrorCode is for failures in action continue command.
 	ActionContinueErrorCode
+	// EncodeInvitationErrorCode is for failures in encode invitation command.
+	EncodeInvitationErrorCode
+	// DecodeInvitationErrorCode is for failures in decode invitation command.
+	DecodeInvitationErrorCode
 )
 
 // constants for out-of-band.
@@ -54,12 +58,16 @@ const (
 	ActionStop       = "ActionStop"
 	Actions          = "Actions"
 	ActionContinue   = "ActionContinue"
+	EncodeInvitation = "EncodeInvitation"
+	DecodeInvitation = "DecodeInvitation"
 
 	// error messages.
 	errOneAttachmentMustBeProvided = "at least one attachment must be provided"
 	errEmptyRequest                = "request was not provided"
 	errEmptyMyLabel                = "my_label was not provided"
 	errEmptyPIID                   = "piid was not provided"
+	errEmptyURL                    = "url was not provided"
+	errOneInvitation               = "exactly one of invitation or legacy_invitation must be provided"
 	// log constants.
 	successString = "success"
 
@@ -71,11 +79,23 @@ var logger = log.New("aries-framework/controller/outofband")
 
 // Command is controller command for outofband.
 type Command struct {
-	client *outofband.Client
+	client   *outofband.Client
+	resolver *outofband.URLResolver
+}
+
+// Option configures the outofband controller command.
+type Option func(c *Command)
+
+// WithURLResolver sets the resolver used by the DecodeInvitation command, by default the invitation URLs
+// are resolved with the default HTTP client.
+func WithURLResolver(resolver *outofband.URLResolver) Option {
+	return func(c *Command) {
+		c.resolver = resolver
+	}
 }
 
 // New returns new outofband controller command instance.
-func New(ctx outofband.Provider, notifier command.Notifier) (*Command, error) {
+func New(ctx outofband.Provider, notifier command.Notifier, opts ...Option) (*Command, error) {
 	client, err := outofband.New(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("cannot create a client: %w", err)
@@ -99,7 +119,13 @@ func New(ctx outofband.Provider, notifier command.Notifier) (*Command, error) {
 	obs.RegisterAction(protocol.Name+_actions, actions)
 	obs.RegisterStateMsg(protocol.Name+_states, states)
 
-	return &Command{client: client}, nil
+	cmd := &Command{client: client, resolver: outofband.NewURLResolver()}
+
+	for _, opt := range opts {
+		opt(cmd)
+	}
+
+	return cmd, nil
 }
 
 // GetHandlers returns list of all commands supported by this controller command.
@@ -112,6 +138,8 @@ func (c *Command) GetHandlers() []command.Handler {
 		cmdutil.NewCommandHandler(CommandName, Actions, c.Actions),
 		cmdutil.NewCommandHandler(CommandName, ActionContinue, c.ActionContinue),
 		cmdutil.NewCommandHandler(CommandName, ActionStop, c.ActionStop),
+		cmdutil.NewCommandHandler(CommandName, EncodeInvitation, c.EncodeInvitation),
+		cmdutil.NewCommandHandler(CommandName, DecodeInvitation, c.DecodeInvitation),
 	}
 }
 
@@ -161,12 +189,18 @@ func (c *Command) CreateInvitation(rw io.Writer, req io.Reader) command.Error {
 		return command.NewValidationError(InvalidRequestErrorCode, err)
 	}
 
-	invitation, err := c.client.CreateInvitation(args.Protocols, []outofband.MessageOption{
+	opts := []outofband.MessageOption{
 		outofband.WithGoal(args.Goal, args.GoalCode),
 		outofband.WithLabel(args.Label),
 		outofband.WithServices(args.Service...),
 		outofband.WithRouterConnections(args.RouterConnectionID),
-	}...)
+	}
+
+	if args.MultiUse {
+		opts = append(opts, outofband.WithMultiUse())
+	}
+
+	invitation, err := c.client.CreateInvitation(args.Protocols, opts...)
 	if err != nil {
 		logutil.LogError(logger, CommandName, CreateInvitation, err.Error())
 		return command.NewExecuteError(CreateInvitationErrorCode, err)
@@ -319,3 +353,79 @@ func (c *Command) ActionStop(rw io.Writer, req io.Reader) command.Error {
 
 	return nil
 }
+
+// EncodeInvitation encodes an out-of-band or a legacy DID exchange invitation into an invitation URL
+// (`?oob=` or `?c_i=`), or into a compact QR code payload.
+func (c *Command) EncodeInvitation(rw io.Writer, req io.Reader) command.Error {
+	var args EncodeInvitationArgs
+
+	if err := json.NewDecoder(req).Decode(&args); err != nil {
+		logutil.LogInfo(logger, CommandName, EncodeInvitation, err.Error())
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	if (args.Invitation == nil) == (args.LegacyInvitation == nil) {
+		logutil.LogDebug(logger, CommandName, EncodeInvitation, errOneInvitation)
+		return command.NewValidationError(InvalidRequestErrorCode, errors.New(errOneInvitation))
+	}
+
+	var (
+		invURL string
+		err    error
+	)
+
+	switch {
+	case args.Invitation != nil && args.QR:
+		invURL, err = outofband.EncodeInvitationQR(args.BaseURL, args.Invitation)
+	case args.Invitation != nil:
+		invURL, err = outofband.EncodeInvitationURL(args.BaseURL, args.Invitation)
+	case args.QR:
+		invURL, err = outofband.EncodeLegacyInvitationQR(args.BaseURL, args.LegacyInvitation)
+	default:
+		invURL, err = outofband.EncodeLegacyInvitationURL(args.BaseURL, args.LegacyInvitation)
+	}
+
+	if err != nil {
+		logutil.LogError(logger, CommandName, EncodeInvitation, err.Error())
+		return command.NewExecuteError(EncodeInvitationErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, &EncodeInvitationResponse{
+		URL: invURL,
+	}, logger)
+
+	logutil.LogDebug(logger, CommandName, EncodeInvitation, successString)
+
+	return nil
+}
+
+// DecodeInvitation decodes the invitation from an invitation URL (`?oob=` or `?c_i=`) or a QR code payload.
+// Shortened URLs are resolved by following their redirects.
+func (c *Command) DecodeInvitation(rw io.Writer, req io.Reader) command.Error {
+	var args DecodeInvitationArgs
+
+	if err := json.NewDecoder(req).Decode(&args); err != nil {
+		logutil.LogInfo(logger, CommandName, DecodeInvitation, err.Error())
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	if args.URL == "" {
+		logutil.LogDebug(logger, CommandName, DecodeInvitation, errEmptyURL)
+		return command.NewValidationError(InvalidRequestErrorCode, errors.New(errEmptyURL))
+	}
+
+	decoded, err := c.resolver.Resolve(args.URL)
+	if err != nil {
+		logutil.LogError(logger, CommandName, DecodeInvitation, err.Error())
+		return command.NewExecuteError(DecodeInvitationErrorCode, err)
+	}
+
+	command.WriteNillableResponse(rw, &DecodeInvitationResponse{
+		Invitation:       decoded.Invitation,
+		LegacyInvitation: decoded.LegacyInvitation,
+	}, logger)
+
+	logutil.LogDebug(logger, CommandName, DecodeInvitation, successString)
+
+	return nil
+}
@@ -10,6 +10,8 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"io/ioutil"
+	"net/http"
 	"testing"
 
 	"github.com/golang/mock/gomock"
@@ -18,6 +20,7 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/client/outofband"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/command"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didexchange"
 	protocol "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/outofband"
 	mocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/client/outofband"
 	mocknotifier "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/controller/webnotifier"
@@ -654,6 +657,161 @@ func TestCommand_ActionContinue(t *testing.T) {
 	})
 }
 
+func TestCommand_EncodeDecodeInvitation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := mocks.NewMockOobService(ctrl)
+	service.EXPECT().RegisterActionEvent(gomock.Any()).Return(nil).AnyTimes()
+	service.EXPECT().RegisterMsgEvent(gomock.Any()).Return(nil).AnyTimes()
+
+	provider := mocks.NewMockProvider(ctrl)
+	provider.EXPECT().Service(gomock.Any()).Return(service, nil).AnyTimes()
+
+	cmd, err := New(provider, mocknotifier.NewMockNotifier(nil))
+	require.NoError(t, err)
+	require.NotNil(t, cmd)
+
+	inv := &outofband.Invitation{
+		ID:        "inv-id",
+		Type:      outofband.InvitationMsgType,
+		Label:     label,
+		Service:   []interface{}{"did:example:123"},
+		Protocols: []string{didexchange.PIURI},
+	}
+	legacy := &didexchange.Invitation{
+		ID:              "legacy-id",
+		Type:            didexchange.InvitationMsgType,
+		Label:           label,
+		RecipientKeys:   []string{"key"},
+		ServiceEndpoint: "https://example.com/endpoint",
+	}
+
+	encode := func(t *testing.T, args *EncodeInvitationArgs) string {
+		t.Helper()
+
+		payload, err := json.Marshal(args)
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+		require.NoError(t, cmd.EncodeInvitation(&b, bytes.NewBuffer(payload)))
+
+		res := &EncodeInvitationResponse{}
+		require.NoError(t, json.Unmarshal(b.Bytes(), res))
+
+		return res.URL
+	}
+
+	decode := func(t *testing.T, invURL string) *DecodeInvitationResponse {
+		t.Helper()
+
+		payload, err := json.Marshal(&DecodeInvitationArgs{URL: invURL})
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+		require.NoError(t, cmd.DecodeInvitation(&b, bytes.NewBuffer(payload)))
+
+		res := &DecodeInvitationResponse{}
+		require.NoError(t, json.Unmarshal(b.Bytes(), res))
+
+		return res
+	}
+
+	t.Run("Out-of-band invitation", func(t *testing.T) {
+		invURL := encode(t, &EncodeInvitationArgs{BaseURL: "https://example.com", Invitation: inv})
+		require.Contains(t, invURL, "https://example.com?oob=")
+		require.Equal(t, &DecodeInvitationResponse{Invitation: inv}, decode(t, invURL))
+
+		invURL = encode(t, &EncodeInvitationArgs{Invitation: inv, QR: true})
+		require.Contains(t, invURL, outofband.DefaultQRBaseURL+"?oob=")
+		require.Equal(t, &DecodeInvitationResponse{Invitation: inv}, decode(t, invURL))
+	})
+
+	t.Run("Legacy invitation", func(t *testing.T) {
+		invURL := encode(t, &EncodeInvitationArgs{BaseURL: "https://example.com", LegacyInvitation: legacy})
+		require.Contains(t, invURL, "https://example.com?c_i=")
+		require.Equal(t, &DecodeInvitationResponse{LegacyInvitation: legacy}, decode(t, invURL))
+
+		invURL = encode(t, &EncodeInvitationArgs{LegacyInvitation: legacy, QR: true})
+		require.Contains(t, invURL, outofband.DefaultQRBaseURL+"?c_i=")
+		require.Equal(t, &DecodeInvitationResponse{LegacyInvitation: legacy}, decode(t, invURL))
+	})
+
+	t.Run("Shortened URL resolved with the configured resolver", func(t *testing.T) {
+		invURL := encode(t, &EncodeInvitationArgs{BaseURL: "https://example.com", Invitation: inv})
+
+		var requested string
+
+		resolver := outofband.NewURLResolver(outofband.WithHTTPClient(httpClientFunc(
+			func(req *http.Request) (*http.Response, error) {
+				requested = req.URL.String()
+
+				return &http.Response{
+					StatusCode: http.StatusFound,
+					Header:     http.Header{"Location": []string{invURL}},
+					Body:       ioutil.NopCloser(bytes.NewReader(nil)),
+					Request:    req,
+				}, nil
+			})))
+
+		resolverCmd, err := New(provider, mocknotifier.NewMockNotifier(nil), WithURLResolver(resolver))
+		require.NoError(t, err)
+
+		payload, err := json.Marshal(&DecodeInvitationArgs{URL: "https://sho.rt/abc"})
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+		require.NoError(t, resolverCmd.DecodeInvitation(&b, bytes.NewBuffer(payload)))
+		require.Equal(t, "https://sho.rt/abc", requested)
+
+		res := &DecodeInvitationResponse{}
+		require.NoError(t, json.Unmarshal(b.Bytes(), res))
+		require.Equal(t, &DecodeInvitationResponse{Invitation: inv}, res)
+	})
+
+	t.Run("Decode error", func(t *testing.T) {
+		var b bytes.Buffer
+
+		cmdErr := cmd.EncodeInvitation(&b, bytes.NewBufferString("}"))
+		require.Error(t, cmdErr)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+		require.Equal(t, command.ValidationError, cmdErr.Type())
+
+		cmdErr = cmd.DecodeInvitation(&b, bytes.NewBufferString("}"))
+		require.Error(t, cmdErr)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+		require.Equal(t, command.ValidationError, cmdErr.Type())
+	})
+
+	t.Run("Invalid arguments", func(t *testing.T) {
+		var b bytes.Buffer
+
+		cmdErr := cmd.EncodeInvitation(&b, bytes.NewBufferString("{}"))
+		require.Error(t, cmdErr)
+		require.Contains(t, cmdErr.Error(), errOneInvitation)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+
+		cmdErr = cmd.DecodeInvitation(&b, bytes.NewBufferString("{}"))
+		require.Error(t, cmdErr)
+		require.Contains(t, cmdErr.Error(), errEmptyURL)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+	})
+
+	t.Run("Execute errors", func(t *testing.T) {
+		var b bytes.Buffer
+
+		cmdErr := cmd.EncodeInvitation(&b, bytes.NewBufferString(`{"base_url":"://","invitation":{}}`))
+		require.Error(t, cmdErr)
+		require.Equal(t, EncodeInvitationErrorCode, cmdErr.Code())
+		require.Equal(t, command.ExecuteError, cmdErr.Type())
+
+		cmdErr = cmd.DecodeInvitation(&b, bytes.NewBufferString(`{"url":"https://example.com?oob=***"}`))
+		require.Error(t, cmdErr)
+		require.Equal(t, DecodeInvitationErrorCode, cmdErr.Code())
+		require.Equal(t, command.ExecuteError, cmdErr.Type())
+	})
+}
+
 func TestCommand_GetHandlers(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -666,7 +824,7 @@ func TestCommand_GetHandlers(t *testing.T) {
 	provider.EXPECT().Service(gomock.Any()).Return(service, nil)
 	cmd, err := New(provider, mocknotifier.NewMockNotifier(nil))
 	require.NoError(t, err)
-	require.Equal(t, 7, len(cmd.GetHandlers()))
+	require.Equal(t, 9, len(cmd.GetHandlers()))
 }
 
 func toProtocolActions(actions []outofband.Action) []protocol.Action {
@@ -677,3 +835,9 @@ func toProtocolActions(actions []outofband.Action) []protocol.Action {
 
 	return res
 }
+
+type httpClientFunc func(req *http.Request) (*http.Response, error)
+
+func (f httpClientFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
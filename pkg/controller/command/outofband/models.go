@@ -9,6 +9,7 @@ package outofband
 import (
 	"github.com/hyperledger/aries-framework-go/pkg/client/outofband"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didexchange"
 )
 
 // CreateRequestArgs model
@@ -45,6 +46,7 @@ type CreateInvitationArgs struct {
 	Service            []interface{} `json:"service"`
 	Protocols          []string      `json:"protocols"`
 	RouterConnectionID string        `json:"router_connection_id"`
+	MultiUse           bool          `json:"multi_use,omitempty"`
 }
 
 // CreateInvitationResponse model
@@ -132,3 +134,41 @@ type ActionContinueArgs struct {
 // Represents a ActionContinue response message
 //
 type ActionContinueResponse struct{}
+
+// EncodeInvitationArgs model
+//
+// This is used for encoding an invitation into an invitation URL, either Invitation or LegacyInvitation must be set.
+//
+type EncodeInvitationArgs struct {
+	// BaseURL the invitation is appended to, QR payloads use 'didcomm://invite' if empty
+	BaseURL          string                  `json:"base_url"`
+	Invitation       *outofband.Invitation   `json:"invitation,omitempty"`
+	LegacyInvitation *didexchange.Invitation `json:"legacy_invitation,omitempty"`
+	// QR produces a compact payload to be rendered as a QR code
+	QR bool `json:"qr,omitempty"`
+}
+
+// EncodeInvitationResponse model
+//
+// Represents a EncodeInvitation response message
+//
+type EncodeInvitationResponse struct {
+	URL string `json:"url"`
+}
+
+// DecodeInvitationArgs model
+//
+// This is used for decoding an invitation URL, shortened URLs are resolved
+//
+type DecodeInvitationArgs struct {
+	URL string `json:"url"`
+}
+
+// DecodeInvitationResponse model
+//
+// Represents a DecodeInvitation response message, only one of the invitations is set
+//
+type DecodeInvitationResponse struct {
+	Invitation       *outofband.Invitation   `json:"invitation,omitempty"`
+	LegacyInvitation *didexchange.Invitation `json:"legacy_invitation,omitempty"`
+}
@@ -0,0 +1,105 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package trustping
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/controller/command"
+	mocktrustping "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol/trustping"
+	mockprovider "github.com/hyperledger/aries-framework-go/pkg/mock/provider"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		cmd, err := New(&mockprovider.Provider{ServiceValue: &mocktrustping.MockTrustPingSvc{}})
+		require.NoError(t, err)
+		require.NotNil(t, cmd)
+		require.Len(t, cmd.GetHandlers(), 1)
+	})
+
+	t.Run("create client error", func(t *testing.T) {
+		cmd, err := New(&mockprovider.Provider{ServiceValue: nil})
+		require.EqualError(t, err, "cannot create a client: cast service to trust ping service failed")
+		require.Nil(t, cmd)
+	})
+}
+
+func TestCommand_Ping(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		cmd, err := New(&mockprovider.Provider{ServiceValue: &mocktrustping.MockTrustPingSvc{
+			PingFunc: func(connectionID string, responseRequested bool, timeout time.Duration) (time.Duration, error) {
+				require.Equal(t, "conn-1", connectionID)
+				require.True(t, responseRequested)
+				require.Equal(t, 5*time.Second, timeout)
+
+				return 1500 * time.Microsecond, nil
+			},
+		}})
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+
+		require.NoError(t, cmd.Ping(&b, bytes.NewBufferString(`{"connectionID":"conn-1","timeout":5000}`)))
+
+		resp := PingResponse{}
+		require.NoError(t, json.Unmarshal(b.Bytes(), &resp))
+		require.Equal(t, 1.5, resp.RoundTripTime)
+	})
+
+	t.Run("response not requested", func(t *testing.T) {
+		cmd, err := New(&mockprovider.Provider{ServiceValue: &mocktrustping.MockTrustPingSvc{
+			PingFunc: func(connectionID string, responseRequested bool, timeout time.Duration) (time.Duration, error) {
+				require.False(t, responseRequested)
+				require.Zero(t, timeout)
+
+				return 0, nil
+			},
+		}})
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+
+		require.NoError(t, cmd.Ping(&b, bytes.NewBufferString(`{"connectionID":"conn-1","response_requested":false}`)))
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		cmd, err := New(&mockprovider.Provider{ServiceValue: &mocktrustping.MockTrustPingSvc{}})
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+
+		cmdErr := cmd.Ping(&b, bytes.NewBufferString(`{`))
+		require.Error(t, cmdErr)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+		require.Equal(t, command.ValidationError, cmdErr.Type())
+
+		cmdErr = cmd.Ping(&b, bytes.NewBufferString(`{}`))
+		require.EqualError(t, cmdErr, "connectionID is mandatory")
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+	})
+
+	t.Run("ping error", func(t *testing.T) {
+		cmd, err := New(&mockprovider.Provider{ServiceValue: &mocktrustping.MockTrustPingSvc{
+			PingErr: errors.New("test error"),
+		}})
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+
+		cmdErr := cmd.Ping(&b, bytes.NewBufferString(`{"connectionID":"conn-1"}`))
+		require.EqualError(t, cmdErr, "trust ping client - ping: test error")
+		require.Equal(t, PingErrorCode, cmdErr.Code())
+		require.Equal(t, command.ExecuteError, cmdErr.Type())
+	})
+}
@@ -0,0 +1,23 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package trustping
+
+// PingArgs contains parameters for pinging the connection.
+type PingArgs struct {
+	// ConnectionID of the connection to ping.
+	ConnectionID string `json:"connectionID"`
+	// ResponseRequested asks the counterparty to respond, defaults to true.
+	ResponseRequested bool `json:"response_requested"`
+	// Timeout (in milliseconds) waiting for the response, defaults to 30 seconds.
+	Timeout int64 `json:"timeout,omitempty"`
+}
+
+// PingResponse is response of the ping.
+type PingResponse struct {
+	// RoundTripTime (in milliseconds) of the ping, zero if the response was not requested.
+	RoundTripTime float64 `json:"round_trip_time"`
+}
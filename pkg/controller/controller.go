@@ -9,26 +9,33 @@ package controller
 import (
 	"fmt"
 
+	outofbandclient "github.com/hyperledger/aries-framework-go/pkg/client/outofband"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/command"
+	actionmenucmd "github.com/hyperledger/aries-framework-go/pkg/controller/command/actionmenu"
 	didexchangecmd "github.com/hyperledger/aries-framework-go/pkg/controller/command/didexchange"
 	introducecmd "github.com/hyperledger/aries-framework-go/pkg/controller/command/introduce"
 	issuecredentialcmd "github.com/hyperledger/aries-framework-go/pkg/controller/command/issuecredential"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/command/kms"
 	routercmd "github.com/hyperledger/aries-framework-go/pkg/controller/command/mediator"
 	messagingcmd "github.com/hyperledger/aries-framework-go/pkg/controller/command/messaging"
+	outboundcmd "github.com/hyperledger/aries-framework-go/pkg/controller/command/outbound"
 	outofbandcmd "github.com/hyperledger/aries-framework-go/pkg/controller/command/outofband"
 	presentproofcmd "github.com/hyperledger/aries-framework-go/pkg/controller/command/presentproof"
+	trustpingcmd "github.com/hyperledger/aries-framework-go/pkg/controller/command/trustping"
 	vdrcmd "github.com/hyperledger/aries-framework-go/pkg/controller/command/vdr"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/command/verifiable"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/rest"
+	actionmenurest "github.com/hyperledger/aries-framework-go/pkg/controller/rest/actionmenu"
 	didexchangerest "github.com/hyperledger/aries-framework-go/pkg/controller/rest/didexchange"
 	introducerest "github.com/hyperledger/aries-framework-go/pkg/controller/rest/introduce"
 	issuecredentialrest "github.com/hyperledger/aries-framework-go/pkg/controller/rest/issuecredential"
 	kmsrest "github.com/hyperledger/aries-framework-go/pkg/controller/rest/kms"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/rest/mediator"
 	messagingrest "github.com/hyperledger/aries-framework-go/pkg/controller/rest/messaging"
+	outboundrest "github.com/hyperledger/aries-framework-go/pkg/controller/rest/outbound"
 	outofbandrest "github.com/hyperledger/aries-framework-go/pkg/controller/rest/outofband"
 	presentproofrest "github.com/hyperledger/aries-framework-go/pkg/controller/rest/presentproof"
+	trustpingrest "github.com/hyperledger/aries-framework-go/pkg/controller/rest/trustping"
 	vdrrest "github.com/hyperledger/aries-framework-go/pkg/controller/rest/vdr"
 	verifiablerest "github.com/hyperledger/aries-framework-go/pkg/controller/rest/verifiable"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/webnotifier"
@@ -41,6 +48,7 @@ type allOpts struct {
 	autoAccept   bool
 	msgHandler   command.MessageHandler
 	notifier     command.Notifier
+	urlResolver  *outofbandclient.URLResolver
 }
 
 const wsPath = "/ws"
@@ -83,6 +91,21 @@ func WithMessageHandler(handler command.MessageHandler) Opt {
 	}
 }
 
+// WithInvitationURLResolver is an option allowing for the resolver of the out-of-band invitation URLs to be set.
+func WithInvitationURLResolver(resolver *outofbandclient.URLResolver) Opt {
+	return func(opts *allOpts) {
+		opts.urlResolver = resolver
+	}
+}
+
+func (o *allOpts) outofbandOpts() []outofbandcmd.Option {
+	if o.urlResolver == nil {
+		return nil
+	}
+
+	return []outofbandcmd.Option{outofbandcmd.WithURLResolver(o.urlResolver)}
+}
+
 // GetRESTHandlers returns all REST handlers provided by controller.
 func GetRESTHandlers(ctx *context.Provider, opts ...Opt) ([]rest.Handler, error) { // nolint: funlen,gocyclo
 	restAPIOpts := &allOpts{}
@@ -146,11 +169,29 @@ func GetRESTHandlers(ctx *context.Provider, opts ...Opt) ([]rest.Handler, error)
 	}
 
 	// outofband REST operation
-	outofbandOp, err := outofbandrest.New(ctx, notifier)
+	outofbandOp, err := outofbandrest.New(ctx, notifier, restAPIOpts.outofbandOpts()...)
 	if err != nil {
 		return nil, fmt.Errorf("create outofband rest command : %w", err)
 	}
 
+	// trustping REST operation
+	trustpingOp, err := trustpingrest.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("create trustping rest command : %w", err)
+	}
+
+	// actionmenu REST operation
+	actionmenuOp, err := actionmenurest.New(ctx, notifier)
+	if err != nil {
+		return nil, fmt.Errorf("create actionmenu rest command : %w", err)
+	}
+
+	// outbound queue REST operation
+	outboundOp, err := outboundrest.New(ctx, notifier)
+	if err != nil {
+		return nil, fmt.Errorf("create outbound rest command : %w", err)
+	}
+
 	// kms command operation
 	kmscmd := kmsrest.New(ctx)
 
@@ -166,6 +207,9 @@ func GetRESTHandlers(ctx *context.Provider, opts ...Opt) ([]rest.Handler, error)
 	allHandlers = append(allHandlers, introduceOp.GetRESTHandlers()...)
 	allHandlers = append(allHandlers, outofbandOp.GetRESTHandlers()...)
 	allHandlers = append(allHandlers, kmscmd.GetRESTHandlers()...)
+	allHandlers = append(allHandlers, trustpingOp.GetRESTHandlers()...)
+	allHandlers = append(allHandlers, actionmenuOp.GetRESTHandlers()...)
+	allHandlers = append(allHandlers, outboundOp.GetRESTHandlers()...)
 
 	nhp, ok := notifier.(handlerProvider)
 	if ok {
@@ -242,11 +286,29 @@ func GetCommandHandlers(ctx *context.Provider, opts ...Opt) ([]command.Handler,
 	}
 
 	// outofband command operation
-	outofband, err := outofbandcmd.New(ctx, notifier)
+	outofband, err := outofbandcmd.New(ctx, notifier, cmdOpts.outofbandOpts()...)
 	if err != nil {
 		return nil, fmt.Errorf("create outofband command : %w", err)
 	}
 
+	// trustping command operation
+	trustping, err := trustpingcmd.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("create trustping command : %w", err)
+	}
+
+	// actionmenu command operation
+	actionmenu, err := actionmenucmd.New(ctx, notifier)
+	if err != nil {
+		return nil, fmt.Errorf("create actionmenu command : %w", err)
+	}
+
+	// outbound queue command operation
+	outbound, err := outboundcmd.New(ctx, notifier)
+	if err != nil {
+		return nil, fmt.Errorf("create outbound command : %w", err)
+	}
+
 	// kms command operation
 	kmscmd := kms.New(ctx)
 
@@ -261,6 +323,9 @@ func GetCommandHandlers(ctx *context.Provider, opts ...Opt) ([]command.Handler,
 	allHandlers = append(allHandlers, presentproof.GetHandlers()...)
 	allHandlers = append(allHandlers, introduce.GetHandlers()...)
 	allHandlers = append(allHandlers, outofband.GetHandlers()...)
+	allHandlers = append(allHandlers, trustping.GetHandlers()...)
+	allHandlers = append(allHandlers, actionmenu.GetHandlers()...)
+	allHandlers = append(allHandlers, outbound.GetHandlers()...)
 
 	return allHandlers, nil
 }
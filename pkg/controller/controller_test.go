@@ -12,6 +12,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/hyperledger/aries-framework-go/pkg/client/outofband"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/internal/mocks/webhook"
 	"github.com/hyperledger/aries-framework-go/pkg/framework/aries"
 	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api"
@@ -67,7 +68,8 @@ func TestGetCommandHandlers_Success(t *testing.T) {
 
 		handlers, err := GetCommandHandlers(ctx, WithMessageHandler(msghandler.NewMockMsgServiceProvider()),
 			WithAutoAccept(true), WithDefaultLabel("sample-label"),
-			WithWebhookURLs("sample-wh-url"), WithNotifier(webhook.NewMockWebhookNotifier()))
+			WithWebhookURLs("sample-wh-url"), WithNotifier(webhook.NewMockWebhookNotifier()),
+			WithInvitationURLResolver(outofband.NewURLResolver()))
 		require.NoError(t, err)
 		require.NotEmpty(t, handlers)
 	})
@@ -104,7 +106,7 @@ func TestGetRESTHandlers_Success(t *testing.T) {
 
 		handlers, err := GetRESTHandlers(ctx, WithMessageHandler(msghandler.NewMockMsgServiceProvider()),
 			WithAutoAccept(true), WithDefaultLabel("sample-label"),
-			WithWebhookURLs("sample-wh-url"))
+			WithWebhookURLs("sample-wh-url"), WithInvitationURLResolver(outofband.NewURLResolver()))
 		require.NoError(t, err)
 		require.NotEmpty(t, handlers)
 	})
@@ -0,0 +1,126 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package actionmenu
+
+import (
+	"github.com/hyperledger/aries-framework-go/pkg/controller/command/actionmenu"
+	protocol "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/actionmenu"
+)
+
+// actionMenuActionsRequest model
+//
+// Returns pending actions that have not yet to be executed or cancelled.
+//
+// swagger:parameters actionMenuActions
+type actionMenuActionsRequest struct{} // nolint: unused,deadcode
+
+// actionMenuActionsResponse model
+//
+// Represents Actions response message.
+//
+// swagger:response actionMenuActionsResponse
+type actionMenuActionsResponse struct { // nolint: unused,deadcode
+	// in: body
+	Body struct {
+		Actions []struct{ *protocol.Action } `json:"actions"`
+	}
+}
+
+// actionMenuPublishMenuRequest model
+//
+// This is used for publishing the menu for the connection.
+//
+// swagger:parameters actionMenuPublishMenu
+type actionMenuPublishMenuRequest struct { // nolint: unused,deadcode
+	// in: body
+	Params actionmenu.MenuArgs
+}
+
+// actionMenuSendMenuRequest model
+//
+// This is used for sending the menu to the connection.
+//
+// swagger:parameters actionMenuSendMenu
+type actionMenuSendMenuRequest struct { // nolint: unused,deadcode
+	// in: body
+	Params actionmenu.MenuArgs
+}
+
+// actionMenuRequestMenuRequest model
+//
+// This is used for requesting the menu from the connection.
+//
+// swagger:parameters actionMenuRequestMenu
+type actionMenuRequestMenuRequest struct { // nolint: unused,deadcode
+	// in: body
+	Params actionmenu.ConnectionArgs
+}
+
+// actionMenuGetMenuRequest model
+//
+// This is used for getting the menu received from the connection.
+//
+// swagger:parameters actionMenuGetMenu
+type actionMenuGetMenuRequest struct { // nolint: unused,deadcode
+	// ConnectionID of the connection
+	//
+	// in: path
+	// required: true
+	ConnectionID string `json:"connectionID"`
+}
+
+// actionMenuGetMenuResponse model
+//
+// Represents the menu received from the connection.
+//
+// swagger:response actionMenuGetMenuResponse
+type actionMenuGetMenuResponse struct { // nolint: unused,deadcode
+	// in: body
+	Params actionmenu.MenuResponse
+}
+
+// actionMenuPerformRequest model
+//
+// This is used for asking the connection to perform the option of its menu.
+//
+// swagger:parameters actionMenuPerform
+type actionMenuPerformRequest struct { // nolint: unused,deadcode
+	// in: body
+	Params actionmenu.PerformArgs
+}
+
+// actionMenuAcceptRequest model
+//
+// This is used for accepting the menu request or the perform.
+//
+// swagger:parameters actionMenuAcceptMenuRequest actionMenuAcceptPerform
+type actionMenuAcceptRequest struct { // nolint: unused,deadcode
+	// Protocol instance ID
+	//
+	// in: path
+	// required: true
+	PIID string `json:"piid"`
+
+	// in: body
+	Body struct {
+		// Menu to send in response
+		Menu *protocol.Menu `json:"menu,omitempty"`
+	}
+}
+
+// actionMenuDeclineRequest model
+//
+// This is used for declining the menu request or the perform.
+//
+// swagger:parameters actionMenuDeclineMenuRequest actionMenuDeclinePerform
+type actionMenuDeclineRequest struct { // nolint: unused,deadcode
+	// Protocol instance ID
+	//
+	// in: path
+	// required: true
+	PIID string `json:"piid"`
+}
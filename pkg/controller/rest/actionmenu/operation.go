@@ -0,0 +1,214 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package actionmenu
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	client "github.com/hyperledger/aries-framework-go/pkg/client/actionmenu"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/command"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/command/actionmenu"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/internal/cmdutil"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/rest"
+)
+
+// constants for the action menu operations.
+const (
+	OperationID        = "/actionmenu"
+	Actions            = OperationID + "/actions"
+	PublishMenu        = OperationID + "/publish-menu"
+	SendMenu           = OperationID + "/send-menu"
+	RequestMenu        = OperationID + "/request-menu"
+	GetMenu            = OperationID + "/{connectionID}/menu"
+	Perform            = OperationID + "/perform"
+	AcceptMenuRequest  = OperationID + "/{piid}/accept-menu-request"
+	DeclineMenuRequest = OperationID + "/{piid}/decline-menu-request"
+	AcceptPerform      = OperationID + "/{piid}/accept-perform"
+	DeclinePerform     = OperationID + "/{piid}/decline-perform"
+)
+
+// Operation is controller REST service controller for the action menu.
+type Operation struct {
+	command  *actionmenu.Command
+	handlers []rest.Handler
+}
+
+// New returns new action menu rest client protocol instance.
+func New(ctx client.Provider, notifier command.Notifier) (*Operation, error) {
+	cmd, err := actionmenu.New(ctx, notifier)
+	if err != nil {
+		return nil, fmt.Errorf("action menu command : %w", err)
+	}
+
+	o := &Operation{command: cmd}
+	o.registerHandler()
+
+	return o, nil
+}
+
+// GetRESTHandlers get all controller API handler available for this protocol service.
+func (c *Operation) GetRESTHandlers() []rest.Handler {
+	return c.handlers
+}
+
+// registerHandler register handlers to be exposed from this protocol service as REST API endpoints.
+func (c *Operation) registerHandler() {
+	c.handlers = []rest.Handler{
+		cmdutil.NewHTTPHandler(Actions, http.MethodGet, c.Actions),
+		cmdutil.NewHTTPHandler(PublishMenu, http.MethodPost, c.PublishMenu),
+		cmdutil.NewHTTPHandler(SendMenu, http.MethodPost, c.SendMenu),
+		cmdutil.NewHTTPHandler(RequestMenu, http.MethodPost, c.RequestMenu),
+		cmdutil.NewHTTPHandler(GetMenu, http.MethodGet, c.GetMenu),
+		cmdutil.NewHTTPHandler(Perform, http.MethodPost, c.Perform),
+		cmdutil.NewHTTPHandler(AcceptMenuRequest, http.MethodPost, c.AcceptMenuRequest),
+		cmdutil.NewHTTPHandler(DeclineMenuRequest, http.MethodPost, c.DeclineMenuRequest),
+		cmdutil.NewHTTPHandler(AcceptPerform, http.MethodPost, c.AcceptPerform),
+		cmdutil.NewHTTPHandler(DeclinePerform, http.MethodPost, c.DeclinePerform),
+	}
+}
+
+// Actions swagger:route GET /actionmenu/actions actionmenu actionMenuActions
+//
+// Returns pending actions that have not yet to be executed or cancelled.
+//
+// Responses:
+//    default: genericError
+//        200: actionMenuActionsResponse
+func (c *Operation) Actions(rw http.ResponseWriter, _ *http.Request) {
+	rest.Execute(c.command.Actions, rw, nil)
+}
+
+// PublishMenu swagger:route POST /actionmenu/publish-menu actionmenu actionMenuPublishMenu
+//
+// Publishes the menu for the connection, the menu is sent when the connection requests it.
+//
+// Responses:
+//    default: genericError
+func (c *Operation) PublishMenu(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.PublishMenu, rw, req.Body)
+}
+
+// SendMenu swagger:route POST /actionmenu/send-menu actionmenu actionMenuSendMenu
+//
+// Publishes the menu for the connection and sends it to the connection.
+//
+// Responses:
+//    default: genericError
+func (c *Operation) SendMenu(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.SendMenu, rw, req.Body)
+}
+
+// RequestMenu swagger:route POST /actionmenu/request-menu actionmenu actionMenuRequestMenu
+//
+// Requests the menu from the connection.
+//
+// Responses:
+//    default: genericError
+func (c *Operation) RequestMenu(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.RequestMenu, rw, req.Body)
+}
+
+// GetMenu swagger:route GET /actionmenu/{connectionID}/menu actionmenu actionMenuGetMenu
+//
+// Returns the last menu received from the connection.
+//
+// Responses:
+//    default: genericError
+//        200: actionMenuGetMenuResponse
+func (c *Operation) GetMenu(rw http.ResponseWriter, req *http.Request) {
+	payload := fmt.Sprintf(`{"connectionID":%q}`, mux.Vars(req)["connectionID"])
+	rest.Execute(c.command.GetMenu, rw, bytes.NewBufferString(payload))
+}
+
+// Perform swagger:route POST /actionmenu/perform actionmenu actionMenuPerform
+//
+// Asks the connection to perform the option of its menu.
+//
+// Responses:
+//    default: genericError
+func (c *Operation) Perform(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.Perform, rw, req.Body)
+}
+
+// AcceptMenuRequest swagger:route POST /actionmenu/{piid}/accept-menu-request actionmenu actionMenuAcceptMenuRequest
+//
+// Sends the menu in response to the menu request, the published menu is sent if the menu is not provided.
+//
+// Responses:
+//    default: genericError
+func (c *Operation) AcceptMenuRequest(rw http.ResponseWriter, req *http.Request) {
+	if ok, r := toAcceptArgs(rw, req); ok {
+		rest.Execute(c.command.AcceptMenuRequest, rw, r)
+	}
+}
+
+// DeclineMenuRequest swagger:route POST /actionmenu/{piid}/decline-menu-request actionmenu actionMenuDeclineMenuRequest
+//
+// Ignores the menu request.
+//
+// Responses:
+//    default: genericError
+func (c *Operation) DeclineMenuRequest(rw http.ResponseWriter, req *http.Request) {
+	payload := fmt.Sprintf(`{"piid":%q}`, mux.Vars(req)["piid"])
+	rest.Execute(c.command.DeclineMenuRequest, rw, bytes.NewBufferString(payload))
+}
+
+// AcceptPerform swagger:route POST /actionmenu/{piid}/accept-perform actionmenu actionMenuAcceptPerform
+//
+// Accepts the perform, the menu is sent in response if it's provided.
+//
+// Responses:
+//    default: genericError
+func (c *Operation) AcceptPerform(rw http.ResponseWriter, req *http.Request) {
+	if ok, r := toAcceptArgs(rw, req); ok {
+		rest.Execute(c.command.AcceptPerform, rw, r)
+	}
+}
+
+// DeclinePerform swagger:route POST /actionmenu/{piid}/decline-perform actionmenu actionMenuDeclinePerform
+//
+// Ignores the perform.
+//
+// Responses:
+//    default: genericError
+func (c *Operation) DeclinePerform(rw http.ResponseWriter, req *http.Request) {
+	payload := fmt.Sprintf(`{"piid":%q}`, mux.Vars(req)["piid"])
+	rest.Execute(c.command.DeclinePerform, rw, bytes.NewBufferString(payload))
+}
+
+// toAcceptArgs builds the accept command request from the optional request body and the piid of the path.
+func toAcceptArgs(rw http.ResponseWriter, req *http.Request) (bool, *bytes.Buffer) {
+	var args actionmenu.AcceptArgs
+
+	// the body is optional
+	if req.Body != nil {
+		err := json.NewDecoder(req.Body).Decode(&args)
+		if err != nil && !errors.Is(err, io.EOF) {
+			rest.SendHTTPStatusError(rw, http.StatusBadRequest, actionmenu.InvalidRequestErrorCode, err)
+
+			return false, nil
+		}
+	}
+
+	args.PIID = mux.Vars(req)["piid"]
+
+	src, err := json.Marshal(args)
+	if err != nil {
+		rest.SendHTTPStatusError(rw, http.StatusBadRequest, actionmenu.InvalidRequestErrorCode, err)
+
+		return false, nil
+	}
+
+	return true, bytes.NewBuffer(src)
+}
@@ -0,0 +1,187 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package actionmenu
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/controller/command/actionmenu"
+	protocol "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/actionmenu"
+	mocknotifier "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/controller/webnotifier"
+	mockactionmenu "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol/actionmenu"
+	mockprovider "github.com/hyperledger/aries-framework-go/pkg/mock/provider"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		op, err := New(&mockprovider.Provider{
+			ServiceValue: &mockactionmenu.MockActionMenuSvc{},
+		}, mocknotifier.NewMockNotifier(nil))
+		require.NoError(t, err)
+		require.Len(t, op.GetRESTHandlers(), 10)
+	})
+
+	t.Run("command creation fail", func(t *testing.T) {
+		_, err := New(&mockprovider.Provider{
+			ServiceErr: errors.New("service error"),
+		}, mocknotifier.NewMockNotifier(nil))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "action menu command")
+	})
+}
+
+func TestOperation_Menus(t *testing.T) {
+	menu := &protocol.Menu{Title: "Issuer bot", Options: []protocol.MenuOption{{Name: "issue"}}}
+
+	t.Run("success", func(t *testing.T) {
+		op := newOperation(t, &mockactionmenu.MockActionMenuSvc{MenuValue: menu})
+
+		menuArgs := toJSON(t, &actionmenu.MenuArgs{ConnectionID: "conn-1", Menu: menu})
+
+		_, code := sendRequest(t, op, PublishMenu, PublishMenu, menuArgs)
+		require.Equal(t, http.StatusOK, code)
+
+		_, code = sendRequest(t, op, SendMenu, SendMenu, menuArgs)
+		require.Equal(t, http.StatusOK, code)
+
+		_, code = sendRequest(t, op, RequestMenu, RequestMenu, `{"connectionID":"conn-1"}`)
+		require.Equal(t, http.StatusOK, code)
+
+		body, code := sendRequest(t, op, GetMenu, strings.Replace(GetMenu, "{connectionID}", "conn-1", 1), "")
+		require.Equal(t, http.StatusOK, code)
+
+		resp := actionmenu.MenuResponse{}
+		require.NoError(t, json.Unmarshal(body, &resp))
+		require.Equal(t, menu, resp.Menu)
+
+		_, code = sendRequest(t, op, Perform, Perform, `{"connectionID":"conn-1","name":"issue"}`)
+		require.Equal(t, http.StatusOK, code)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		op := newOperation(t, &mockactionmenu.MockActionMenuSvc{
+			RequestMenuErr: errors.New("test error"),
+		})
+
+		body, code := sendRequest(t, op, RequestMenu, RequestMenu, `{"connectionID":"conn-1"}`)
+		require.Equal(t, http.StatusInternalServerError, code)
+		require.Contains(t, string(body), "test error")
+	})
+
+	t.Run("validation error", func(t *testing.T) {
+		op := newOperation(t, &mockactionmenu.MockActionMenuSvc{})
+
+		body, code := sendRequest(t, op, SendMenu, SendMenu, `{}`)
+		require.Equal(t, http.StatusBadRequest, code)
+		require.Contains(t, string(body), "empty connectionID")
+	})
+}
+
+func TestOperation_Actions(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		var continued []string
+
+		op := newOperation(t, &mockactionmenu.MockActionMenuSvc{
+			ActionsValue: []protocol.Action{{PIID: "piid-1", ConnectionID: "conn-1"}},
+			ActionContinueFunc: func(piID string, opt protocol.Opt) error {
+				continued = append(continued, piID)
+
+				return nil
+			},
+		})
+
+		body, code := sendRequest(t, op, Actions, Actions, "")
+		require.Equal(t, http.StatusOK, code)
+
+		resp := actionmenu.ActionsResponse{}
+		require.NoError(t, json.Unmarshal(body, &resp))
+		require.Len(t, resp.Actions, 1)
+
+		_, code = sendRequest(t, op, AcceptMenuRequest, piidPath(AcceptMenuRequest, "piid-1"), "")
+		require.Equal(t, http.StatusOK, code)
+
+		_, code = sendRequest(t, op, AcceptPerform, piidPath(AcceptPerform, "piid-2"), `{"menu":{"title":"Issued"}}`)
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, []string{"piid-1", "piid-2"}, continued)
+
+		_, code = sendRequest(t, op, DeclineMenuRequest, piidPath(DeclineMenuRequest, "piid-1"), "")
+		require.Equal(t, http.StatusOK, code)
+
+		_, code = sendRequest(t, op, DeclinePerform, piidPath(DeclinePerform, "piid-2"), "")
+		require.Equal(t, http.StatusOK, code)
+	})
+
+	t.Run("invalid accept body", func(t *testing.T) {
+		op := newOperation(t, &mockactionmenu.MockActionMenuSvc{})
+
+		_, code := sendRequest(t, op, AcceptPerform, piidPath(AcceptPerform, "piid-1"), `{`)
+		require.Equal(t, http.StatusBadRequest, code)
+	})
+
+	t.Run("decline error", func(t *testing.T) {
+		op := newOperation(t, &mockactionmenu.MockActionMenuSvc{ActionStopErr: errors.New("test error")})
+
+		body, code := sendRequest(t, op, DeclinePerform, piidPath(DeclinePerform, "piid-1"), "")
+		require.Equal(t, http.StatusInternalServerError, code)
+		require.Contains(t, string(body), "test error")
+	})
+}
+
+func newOperation(t *testing.T, svc *mockactionmenu.MockActionMenuSvc) *Operation {
+	t.Helper()
+
+	op, err := New(&mockprovider.Provider{ServiceValue: svc}, mocknotifier.NewMockNotifier(nil))
+	require.NoError(t, err)
+
+	return op
+}
+
+func piidPath(path, piid string) string {
+	return strings.Replace(path, "{piid}", piid, 1)
+}
+
+func toJSON(t *testing.T, v interface{}) string {
+	t.Helper()
+
+	src, err := json.Marshal(v)
+	require.NoError(t, err)
+
+	return string(src)
+}
+
+func sendRequest(t *testing.T, op *Operation, route, path, body string) ([]byte, int) {
+	t.Helper()
+
+	router := mux.NewRouter()
+
+	for _, handler := range op.GetRESTHandlers() {
+		if handler.Path() == route {
+			router.HandleFunc(handler.Path(), handler.Handle()).Methods(handler.Method())
+
+			req, err := http.NewRequest(handler.Method(), path, bytes.NewBufferString(body))
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			return rr.Body.Bytes(), rr.Code
+		}
+	}
+
+	require.FailNow(t, "handler not found", route)
+
+	return nil, 0
+}
@@ -0,0 +1,32 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package outbound
+
+import "github.com/hyperledger/aries-framework-go/pkg/controller/command/outbound"
+
+// outboundDeadLetterRequest model
+//
+// This is used to replay or remove the dead letter.
+//
+// swagger:parameters outboundReplayDeadLetter outboundRemoveDeadLetter
+type outboundDeadLetterRequest struct { // nolint: unused,deadcode
+	// The ID of the dead letter
+	//
+	// in: path
+	// required: true
+	ID string `json:"id"`
+}
+
+// outboundDeadLettersResponse model
+//
+// Response containing the messages which failed to be delivered.
+//
+// swagger:response outboundDeadLettersResponse
+type outboundDeadLettersResponse struct { // nolint: unused,deadcode
+	// in: body
+	outbound.DeadLettersResponse
+}
@@ -0,0 +1,101 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package outbound
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/hyperledger/aries-framework-go/pkg/controller/command"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/command/outbound"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/internal/cmdutil"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/rest"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/dispatcher"
+)
+
+// constants for the outbound queue operations.
+const (
+	OperationID      = "/outbound"
+	DeadLetters      = OperationID + "/dead-letters"
+	ReplayDeadLetter = DeadLetters + "/{id}/replay"
+	RemoveDeadLetter = DeadLetters + "/{id}"
+)
+
+// provider contains dependencies for the outbound queue and is typically created by using aries.Context().
+type provider interface {
+	OutboundQueue() *dispatcher.OutboundQueue
+}
+
+// Operation contains basic common operations provided by controller REST API.
+type Operation struct {
+	handlers []rest.Handler
+	command  *outbound.Command
+}
+
+// New returns new outbound queue rest client instance.
+func New(ctx provider, notifier command.Notifier) (*Operation, error) {
+	cmd, err := outbound.New(ctx, notifier)
+	if err != nil {
+		return nil, fmt.Errorf("create outbound command : %w", err)
+	}
+
+	o := &Operation{command: cmd}
+
+	o.registerHandler()
+
+	return o, nil
+}
+
+// GetRESTHandlers get all controller API handler available for this service.
+func (o *Operation) GetRESTHandlers() []rest.Handler {
+	return o.handlers
+}
+
+// registerHandler register handlers to be exposed from this service as REST API endpoints.
+func (o *Operation) registerHandler() {
+	o.handlers = []rest.Handler{
+		cmdutil.NewHTTPHandler(DeadLetters, http.MethodGet, o.DeadLetters),
+		cmdutil.NewHTTPHandler(ReplayDeadLetter, http.MethodPost, o.ReplayDeadLetter),
+		cmdutil.NewHTTPHandler(RemoveDeadLetter, http.MethodDelete, o.RemoveDeadLetter),
+	}
+}
+
+// DeadLetters swagger:route GET /outbound/dead-letters outbound outboundDeadLetters
+//
+// Returns the messages which failed to be delivered.
+//
+// Responses:
+//    default: genericError
+//        200: outboundDeadLettersResponse
+func (o *Operation) DeadLetters(rw http.ResponseWriter, _ *http.Request) {
+	rest.Execute(o.command.DeadLetters, rw, nil)
+}
+
+// ReplayDeadLetter swagger:route POST /outbound/dead-letters/{id}/replay outbound outboundReplayDeadLetter
+//
+// Moves the dead letter back to the queue to be delivered again.
+//
+// Responses:
+//    default: genericError
+func (o *Operation) ReplayDeadLetter(rw http.ResponseWriter, req *http.Request) {
+	payload := fmt.Sprintf(`{"id":%q}`, mux.Vars(req)["id"])
+	rest.Execute(o.command.ReplayDeadLetter, rw, bytes.NewBufferString(payload))
+}
+
+// RemoveDeadLetter swagger:route DELETE /outbound/dead-letters/{id} outbound outboundRemoveDeadLetter
+//
+// Removes the dead letter.
+//
+// Responses:
+//    default: genericError
+func (o *Operation) RemoveDeadLetter(rw http.ResponseWriter, req *http.Request) {
+	payload := fmt.Sprintf(`{"id":%q}`, mux.Vars(req)["id"])
+	rest.Execute(o.command.RemoveDeadLetter, rw, bytes.NewBufferString(payload))
+}
@@ -0,0 +1,109 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package outbound
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/command/outbound"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/dispatcher"
+	mocknotifier "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/controller/webnotifier"
+	mockprovider "github.com/hyperledger/aries-framework-go/pkg/mock/provider"
+)
+
+func TestNew(t *testing.T) {
+	op, err := New(&mockprovider.Provider{}, mocknotifier.NewMockNotifier(nil))
+	require.NoError(t, err)
+	require.Len(t, op.GetRESTHandlers(), 3)
+}
+
+func TestOperation_DeadLetters(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		op := newOperation(t)
+
+		body, code := sendRequest(t, op, DeadLetters, DeadLetters)
+		require.Equal(t, http.StatusOK, code)
+
+		resp := outbound.DeadLettersResponse{}
+		require.NoError(t, json.Unmarshal(body, &resp))
+		require.Empty(t, resp.DeadLetters)
+	})
+
+	t.Run("queue is not enabled", func(t *testing.T) {
+		op, err := New(&mockprovider.Provider{}, mocknotifier.NewMockNotifier(nil))
+		require.NoError(t, err)
+
+		body, code := sendRequest(t, op, DeadLetters, DeadLetters)
+		require.Equal(t, http.StatusInternalServerError, code)
+		require.Contains(t, string(body), "outbound queue is not enabled")
+	})
+
+	t.Run("dead letter not found", func(t *testing.T) {
+		op := newOperation(t)
+
+		body, code := sendRequest(t, op, ReplayDeadLetter, idPath(ReplayDeadLetter, "msg-1"))
+		require.Equal(t, http.StatusInternalServerError, code)
+		require.Contains(t, string(body), dispatcher.ErrDeadLetterNotFound.Error())
+
+		body, code = sendRequest(t, op, RemoveDeadLetter, idPath(RemoveDeadLetter, "msg-1"))
+		require.Equal(t, http.StatusInternalServerError, code)
+		require.Contains(t, string(body), dispatcher.ErrDeadLetterNotFound.Error())
+	})
+}
+
+func newOperation(t *testing.T) *Operation {
+	t.Helper()
+
+	queue, err := dispatcher.NewOutboundQueue(mem.NewProvider())
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		require.NoError(t, queue.Close())
+	})
+
+	op, err := New(&mockprovider.Provider{OutboundQueueValue: queue}, mocknotifier.NewMockNotifier(nil))
+	require.NoError(t, err)
+
+	return op
+}
+
+func idPath(path, id string) string {
+	return strings.Replace(path, "{id}", id, 1)
+}
+
+func sendRequest(t *testing.T, op *Operation, route, path string) ([]byte, int) {
+	t.Helper()
+
+	router := mux.NewRouter()
+
+	for _, handler := range op.GetRESTHandlers() {
+		if handler.Path() == route {
+			router.HandleFunc(handler.Path(), handler.Handle()).Methods(handler.Method())
+
+			req, err := http.NewRequest(handler.Method(), path, bytes.NewBufferString(""))
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			return rr.Body.Bytes(), rr.Code
+		}
+	}
+
+	require.FailNow(t, "handler not found", route)
+
+	return nil, 0
+}
@@ -8,6 +8,7 @@ package outofband
 
 import (
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didexchange"
 	protocol "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/outofband"
 )
 
@@ -193,3 +194,58 @@ type outofbandActionStopResponse struct { // nolint: unused,deadcode
 	// in: body
 	Body struct{}
 }
+
+// outofbandEncodeInvitationRequest model
+//
+// This is used for operation to encode an invitation, either invitation or legacy_invitation must be provided.
+//
+// swagger:parameters outofbandEncodeInvitation
+type outofbandEncodeInvitationRequest struct { // nolint: unused,deadcode
+	// in: body
+	Body struct {
+		// BaseURL the invitation is appended to, QR payloads use 'didcomm://invite' if empty
+		BaseURL          string                            `json:"base_url"`
+		Invitation       struct{ *protocol.Invitation }    `json:"invitation"`
+		LegacyInvitation struct{ *didexchange.Invitation } `json:"legacy_invitation"`
+		// QR produces a compact payload to be rendered as a QR code
+		QR bool `json:"qr"`
+	}
+}
+
+// outofbandEncodeInvitationResponse model
+//
+// Represents a EncodeInvitation response message.
+//
+// swagger:response outofbandEncodeInvitationResponse
+type outofbandEncodeInvitationResponse struct { // nolint: unused,deadcode
+	// in: body
+	Body struct {
+		URL string `json:"url"`
+	}
+}
+
+// outofbandDecodeInvitationRequest model
+//
+// This is used for operation to decode an invitation URL.
+//
+// swagger:parameters outofbandDecodeInvitation
+type outofbandDecodeInvitationRequest struct { // nolint: unused,deadcode
+	// in: body
+	Body struct {
+		// required: true
+		URL string `json:"url"`
+	}
+}
+
+// outofbandDecodeInvitationResponse model
+//
+// Represents a DecodeInvitation response message, only one of the invitations is set.
+//
+// swagger:response outofbandDecodeInvitationResponse
+type outofbandDecodeInvitationResponse struct { // nolint: unused,deadcode
+	// in: body
+	Body struct {
+		Invitation       struct{ *protocol.Invitation }    `json:"invitation"`
+		LegacyInvitation struct{ *didexchange.Invitation } `json:"legacy_invitation"`
+	}
+}
@@ -30,6 +30,8 @@ const (
 	Actions          = OperationID + "/actions"
 	ActionContinue   = OperationID + "/{piid}/action-continue"
 	ActionStop       = OperationID + "/{piid}/action-stop"
+	EncodeInvitation = OperationID + "/encode-invitation"
+	DecodeInvitation = OperationID + "/decode-invitation"
 )
 
 // Operation is controller REST service controller for outofband.
@@ -39,8 +41,8 @@ type Operation struct {
 }
 
 // New returns new outofband rest client protocol instance.
-func New(ctx client.Provider, notifier command.Notifier) (*Operation, error) {
-	cmd, err := outofband.New(ctx, notifier)
+func New(ctx client.Provider, notifier command.Notifier, opts ...outofband.Option) (*Operation, error) {
+	cmd, err := outofband.New(ctx, notifier, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("outofband command : %w", err)
 	}
@@ -67,6 +69,8 @@ func (c *Operation) registerHandler() {
 		cmdutil.NewHTTPHandler(Actions, http.MethodGet, c.Actions),
 		cmdutil.NewHTTPHandler(ActionContinue, http.MethodPost, c.ActionContinue),
 		cmdutil.NewHTTPHandler(ActionStop, http.MethodPost, c.ActionStop),
+		cmdutil.NewHTTPHandler(EncodeInvitation, http.MethodPost, c.EncodeInvitation),
+		cmdutil.NewHTTPHandler(DecodeInvitation, http.MethodPost, c.DecodeInvitation),
 	}
 }
 
@@ -153,3 +157,25 @@ func (c *Operation) AcceptRequest(rw http.ResponseWriter, req *http.Request) {
 func (c *Operation) AcceptInvitation(rw http.ResponseWriter, req *http.Request) {
 	rest.Execute(c.command.AcceptInvitation, rw, req.Body)
 }
+
+// EncodeInvitation swagger:route POST /outofband/encode-invitation outofband outofbandEncodeInvitation
+//
+// Encodes an invitation into an invitation URL or a QR code payload.
+//
+// Responses:
+//    default: genericError
+//        200: outofbandEncodeInvitationResponse
+func (c *Operation) EncodeInvitation(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.EncodeInvitation, rw, req.Body)
+}
+
+// DecodeInvitation swagger:route POST /outofband/decode-invitation outofband outofbandDecodeInvitation
+//
+// Decodes an invitation from an invitation URL or a QR code payload, shortened URLs are resolved.
+//
+// Responses:
+//    default: genericError
+//        200: outofbandDecodeInvitationResponse
+func (c *Operation) DecodeInvitation(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(c.command.DecodeInvitation, rw, req.Body)
+}
@@ -209,6 +209,49 @@ func TestOperation_ActionStop(t *testing.T) {
 	require.Equal(t, http.StatusOK, code)
 }
 
+func TestOperation_EncodeDecodeInvitation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	operation, err := New(provider(ctrl), mocknotifier.NewMockNotifier(nil))
+	require.NoError(t, err)
+
+	b, code, err := sendRequestToHandler(
+		handlerLookup(t, operation, EncodeInvitation),
+		bytes.NewBufferString(`{
+			"base_url":"https://example.com",
+			"invitation":{"@id":"inv-id","@type":"`+client.InvitationMsgType+`","label":"label"}
+		}`),
+		EncodeInvitation,
+	)
+
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, code)
+
+	encoded := make(map[string]string)
+	require.NoError(t, json.Unmarshal(b.Bytes(), &encoded))
+	require.Contains(t, encoded["url"], "https://example.com?oob=")
+
+	payload, err := json.Marshal(encoded)
+	require.NoError(t, err)
+
+	b, code, err = sendRequestToHandler(
+		handlerLookup(t, operation, DecodeInvitation),
+		bytes.NewBuffer(payload),
+		DecodeInvitation,
+	)
+
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, code)
+
+	res := struct {
+		Invitation *client.Invitation `json:"invitation"`
+	}{}
+	require.NoError(t, json.Unmarshal(b.Bytes(), &res))
+	require.Equal(t, "inv-id", res.Invitation.ID)
+	require.Equal(t, label, res.Invitation.Label)
+}
+
 func handlerLookup(t *testing.T, op *Operation, lookup string) rest.Handler {
 	t.Helper()
 
@@ -0,0 +1,31 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package trustping
+
+import "github.com/hyperledger/aries-framework-go/pkg/controller/command/trustping"
+
+// pingRequest model
+//
+// This is used for pinging the connection.
+//
+// swagger:parameters pingRequest
+type pingRequest struct { // nolint: unused,deadcode
+	// Params for pinging the connection
+	//
+	// in: body
+	Params trustping.PingArgs
+}
+
+// pingResponse model
+//
+// Response containing the round-trip time of the ping.
+//
+// swagger:response pingResponse
+type pingResponse struct { // nolint: unused,deadcode
+	// in: body
+	Params trustping.PingResponse
+}
@@ -0,0 +1,91 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package trustping
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/controller/command/trustping"
+	mocktrustping "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol/trustping"
+	mockprovider "github.com/hyperledger/aries-framework-go/pkg/mock/provider"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		op, err := New(&mockprovider.Provider{ServiceValue: &mocktrustping.MockTrustPingSvc{}})
+		require.NoError(t, err)
+		require.Len(t, op.GetRESTHandlers(), 1)
+	})
+
+	t.Run("command creation fail", func(t *testing.T) {
+		_, err := New(&mockprovider.Provider{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "create trust ping command")
+	})
+}
+
+func TestOperation_Ping(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		op, err := New(&mockprovider.Provider{ServiceValue: &mocktrustping.MockTrustPingSvc{
+			PingValue: 2 * time.Millisecond,
+		}})
+		require.NoError(t, err)
+
+		body, code := sendRequest(t, op, `{"connectionID":"conn-1"}`)
+		require.Equal(t, http.StatusOK, code)
+
+		resp := trustping.PingResponse{}
+		require.NoError(t, json.Unmarshal(body, &resp))
+		require.Equal(t, float64(2), resp.RoundTripTime)
+	})
+
+	t.Run("ping error", func(t *testing.T) {
+		op, err := New(&mockprovider.Provider{ServiceValue: &mocktrustping.MockTrustPingSvc{
+			PingErr: errors.New("test error"),
+		}})
+		require.NoError(t, err)
+
+		body, code := sendRequest(t, op, `{"connectionID":"conn-1"}`)
+		require.Equal(t, http.StatusInternalServerError, code)
+		require.Contains(t, string(body), "test error")
+	})
+
+	t.Run("validation error", func(t *testing.T) {
+		op, err := New(&mockprovider.Provider{ServiceValue: &mocktrustping.MockTrustPingSvc{}})
+		require.NoError(t, err)
+
+		body, code := sendRequest(t, op, `{}`)
+		require.Equal(t, http.StatusBadRequest, code)
+		require.Contains(t, string(body), "connectionID is mandatory")
+	})
+}
+
+func sendRequest(t *testing.T, op *Operation, body string) ([]byte, int) {
+	t.Helper()
+
+	handler := op.GetRESTHandlers()[0]
+
+	req, err := http.NewRequest(handler.Method(), PingPath, bytes.NewBufferString(body))
+	require.NoError(t, err)
+
+	router := mux.NewRouter()
+	router.HandleFunc(handler.Path(), handler.Handle()).Methods(handler.Method())
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	return rr.Body.Bytes(), rr.Code
+}
@@ -66,6 +66,15 @@ type Crypto interface {
 	// 		signature in []byte
 	//		error in case of errors
 	SignMulti(messages [][]byte, kh interface{}) ([]byte, error)
+	// BlindSignMulti will verify the holder's commitment to the hidden messages in blindContext and create a blind
+	// signature of revealedMessages along with the hidden ones using a matching signing primitive found in kh key
+	// handle of a private key. The holder unblinds the signature to get a regular signature of all messageCount
+	// messages.
+	// returns:
+	// 		blind signature in []byte
+	//		error in case of errors
+	BlindSignMulti(revealedMessages [][]byte, revealedIndexes []int, messagesCount int,
+		blindContext, nonce []byte, kh interface{}) ([]byte, error)
 	// VerifyMulti will verify a signature of messages using a matching signing primitive found in kh key handle of a
 	// public key.
 	// returns:
@@ -0,0 +1,519 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package audit provides decorators of crypto.Crypto and kms.KeyManager which record the operations made with
+// private keys to the audit log and enforce per key usage policies. The decorators wrap the interfaces only,
+// so they work with any crypto and KMS implementation (tinkcrypto, webkms etc.).
+//
+// Crypto operations receive key handles instead of key IDs. Key IDs of the handles are known if the handles were
+// created or loaded with the audited KeyManager of the same Log. Remote KMS key URLs are used as key IDs as is.
+// Once any key usage policy is set, the operations with the key handles of unknown key IDs are denied, since
+// their policies can't be checked. The audited KeyManager remembers the key IDs of the last handles only
+// (see WithHandlesCacheSize), the handle has to be loaded with it again to be used after that.
+package audit
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bluele/gcache"
+	"github.com/google/uuid"
+
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+)
+
+const (
+	// StoreName is the name of the audit log store.
+	StoreName = "cryptoaudit"
+
+	recordKeyPrefix = "record_"
+	policyKeyPrefix = "policy_"
+	usageKeyPrefix  = "usage_"
+
+	recordTag = "record"
+	keyIDTag  = "keyid"
+	policyTag = "policy"
+
+	defaultHandlesCacheSize = 1000
+)
+
+var logger = log.New("aries-framework/crypto/audit")
+
+// ErrOperationDenied is returned when the key usage policy does not allow the operation.
+var ErrOperationDenied = errors.New("operation denied by key usage policy")
+
+// ErrPolicyNotFound is returned when the key has no usage policy.
+var ErrPolicyNotFound = errors.New("key usage policy not found")
+
+// Operation is an audited operation.
+type Operation string
+
+const (
+	// OperationSign is a Sign or SignMulti call.
+	OperationSign Operation = "sign"
+	// OperationDecrypt is a Decrypt call.
+	OperationDecrypt Operation = "decrypt"
+	// OperationUnwrapKey is an UnwrapKey call.
+	OperationUnwrapKey Operation = "unwrap"
+	// OperationCreate is a key creation in KMS.
+	OperationCreate Operation = "create"
+	// OperationRotate is a key rotation in KMS.
+	OperationRotate Operation = "rotate"
+	// OperationImport is a private key import to KMS.
+	OperationImport Operation = "import"
+)
+
+// Outcome is the outcome of the audited operation.
+type Outcome string
+
+const (
+	// OutcomeSuccess the operation succeeded.
+	OutcomeSuccess Outcome = "success"
+	// OutcomeFailure the operation failed.
+	OutcomeFailure Outcome = "failure"
+	// OutcomeDenied the operation was denied by the key usage policy.
+	OutcomeDenied Outcome = "denied"
+)
+
+// Record is an audit record of the operation.
+type Record struct {
+	ID        string    `json:"id"`
+	KeyID     string    `json:"keyID,omitempty"`
+	Operation Operation `json:"operation"`
+	// Caller is the package which called the operation.
+	Caller    string    `json:"caller,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+	Outcome   Outcome   `json:"outcome"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// Policy is the usage policy of the key. Policies are enforced for sign, decrypt and unwrap operations.
+type Policy struct {
+	KeyID string `json:"keyID"`
+	// AllowedOperations restricts the operations the key can be used for. All operations are allowed if empty.
+	AllowedOperations []Operation `json:"allowedOperations,omitempty"`
+	// RateLimit restricts the number of operations made with the key in a period of time.
+	RateLimit *RateLimit `json:"rateLimit,omitempty"`
+	// Expires is the time the key can't be used after.
+	Expires *time.Time `json:"expires,omitempty"`
+}
+
+// RateLimit is the maximum number of operations allowed in a period of time.
+type RateLimit struct {
+	Operations int           `json:"operations"`
+	Period     time.Duration `json:"period"`
+}
+
+type provider interface {
+	StorageProvider() storage.Provider
+}
+
+// Opt is an option of the audit Log.
+type Opt func(opts *Log)
+
+// WithHandlesCacheSize sets the number of key handles the audited KeyManager remembers key IDs for.
+func WithHandlesCacheSize(size int) Opt {
+	return func(opts *Log) {
+		opts.handlesCacheSize = size
+	}
+}
+
+// Log is the audit log of the operations made with private keys.
+type Log struct {
+	store            storage.Store
+	handles          gcache.Cache
+	handlesCacheSize int
+	usageLock        sync.Mutex
+	now              func() time.Time
+}
+
+// New returns new audit Log.
+func New(p provider, opts ...Opt) (*Log, error) {
+	store, err := p.StorageProvider().OpenStore(StoreName)
+	if err != nil {
+		return nil, fmt.Errorf("open audit store: %w", err)
+	}
+
+	err = p.StorageProvider().SetStoreConfig(StoreName,
+		storage.StoreConfiguration{TagNames: []string{recordTag, keyIDTag, policyTag}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set store configuration: %w", err)
+	}
+
+	l := &Log{
+		store:            store,
+		handlesCacheSize: defaultHandlesCacheSize,
+		now:              time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	l.handles = gcache.New(l.handlesCacheSize).LRU().Build()
+
+	return l, nil
+}
+
+// SetPolicy sets the usage policy of the key.
+func (l *Log) SetPolicy(policy *Policy) error {
+	if policy.KeyID == "" {
+		return errors.New("key ID is mandatory")
+	}
+
+	if policy.RateLimit != nil && (policy.RateLimit.Operations <= 0 || policy.RateLimit.Period <= 0) {
+		return errors.New("rate limit operations and period must be positive")
+	}
+
+	src, err := json.Marshal(policy)
+	if err != nil {
+		return fmt.Errorf("marshal policy: %w", err)
+	}
+
+	err = l.store.Put(policyKeyPrefix+policy.KeyID, src, storage.Tag{Name: policyTag})
+	if err != nil {
+		return fmt.Errorf("save policy: %w", err)
+	}
+
+	return nil
+}
+
+// GetPolicy returns the usage policy of the key.
+func (l *Log) GetPolicy(keyID string) (*Policy, error) {
+	src, err := l.store.Get(policyKeyPrefix + keyID)
+	if errors.Is(err, storage.ErrDataNotFound) {
+		return nil, ErrPolicyNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("get policy: %w", err)
+	}
+
+	policy := &Policy{}
+
+	if err = json.Unmarshal(src, policy); err != nil {
+		return nil, fmt.Errorf("unmarshal policy: %w", err)
+	}
+
+	return policy, nil
+}
+
+// RemovePolicy removes the usage policy of the key.
+func (l *Log) RemovePolicy(keyID string) error {
+	if err := l.store.Delete(policyKeyPrefix + keyID); err != nil {
+		return fmt.Errorf("remove policy: %w", err)
+	}
+
+	return nil
+}
+
+// Records returns the audit records of the key, or all the records if keyID is empty.
+func (l *Log) Records(keyID string) ([]*Record, error) {
+	query := recordTag
+	if keyID != "" {
+		query = keyIDTag + ":" + encodeTagValue(keyID)
+	}
+
+	iter, err := l.store.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("query records: %w", err)
+	}
+
+	defer storage.Close(iter, logger)
+
+	var records []*Record
+
+	more, err := iter.Next()
+	if err != nil {
+		return nil, fmt.Errorf("get next record: %w", err)
+	}
+
+	for more {
+		src, err := iter.Value()
+		if err != nil {
+			return nil, fmt.Errorf("get value from records: %w", err)
+		}
+
+		record := &Record{}
+
+		if err = json.Unmarshal(src, record); err != nil {
+			return nil, fmt.Errorf("unmarshal record: %w", err)
+		}
+
+		records = append(records, record)
+
+		more, err = iter.Next()
+		if err != nil {
+			return nil, fmt.Errorf("get next record: %w", err)
+		}
+	}
+
+	return records, nil
+}
+
+// audit enforces the usage policy of the key, runs the operation and records its outcome. The result of the
+// operation is dropped if it can't be recorded. keyID is empty if the key ID of the key handle is unknown.
+func (l *Log) audit(keyID string, op Operation, call func() error) error {
+	return l.auditAs(keyID, keyID, op, call)
+}
+
+// auditAs is audit which saves the record with recordKeyID, ex. the key ID the caller refers to the key handle of
+// unknown key ID with.
+func (l *Log) auditAs(keyID, recordKeyID string, op Operation, call func() error) error {
+	now := l.now()
+
+	err := l.checkPolicy(keyID, op, now)
+	if err != nil {
+		return l.record(recordKeyID, op, now, OutcomeDenied, err)
+	}
+
+	err = call()
+	if err != nil {
+		return l.record(recordKeyID, op, now, OutcomeFailure, err)
+	}
+
+	return l.record(recordKeyID, op, now, OutcomeSuccess, nil)
+}
+
+// record saves the record of the operation and returns the error of the operation, or the save error if any.
+func (l *Log) record(keyID string, op Operation, timestamp time.Time, outcome Outcome, opErr error) error {
+	record := &Record{
+		ID:        uuid.New().String(),
+		KeyID:     keyID,
+		Operation: op,
+		Caller:    caller(),
+		Timestamp: timestamp,
+		Outcome:   outcome,
+	}
+
+	if opErr != nil {
+		record.Error = opErr.Error()
+	}
+
+	if err := l.saveRecord(record); err != nil {
+		return fmt.Errorf("audit %s operation: %w", op, err)
+	}
+
+	return opErr
+}
+
+// recordResult records the outcome of the operation which is not subject to the key usage policies.
+func (l *Log) recordResult(keyID string, op Operation, opErr error) error {
+	if opErr != nil {
+		return l.record(keyID, op, l.now(), OutcomeFailure, opErr)
+	}
+
+	return l.record(keyID, op, l.now(), OutcomeSuccess, nil)
+}
+
+func (l *Log) checkPolicy(keyID string, op Operation, now time.Time) error {
+	if keyID == "" {
+		return l.checkUnknownKey(op)
+	}
+
+	policy, err := l.GetPolicy(keyID)
+	if errors.Is(err, ErrPolicyNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if policy.Expires != nil && !now.Before(*policy.Expires) {
+		return fmt.Errorf("%w: key %s expired at %s", ErrOperationDenied, keyID, policy.Expires.Format(time.RFC3339))
+	}
+
+	if len(policy.AllowedOperations) != 0 && !allowed(policy.AllowedOperations, op) {
+		return fmt.Errorf("%w: %s operation is not allowed for key %s", ErrOperationDenied, op, keyID)
+	}
+
+	if policy.RateLimit == nil {
+		return nil
+	}
+
+	ok, err := l.useRate(keyID, policy.RateLimit, now)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return fmt.Errorf("%w: rate limit of key %s exceeded", ErrOperationDenied, keyID)
+	}
+
+	return nil
+}
+
+// checkUnknownKey denies the operation made with the key handle of unknown key ID if any key has a usage policy.
+func (l *Log) checkUnknownKey(op Operation) error {
+	iter, err := l.store.Query(policyTag)
+	if err != nil {
+		return fmt.Errorf("query policies: %w", err)
+	}
+
+	defer storage.Close(iter, logger)
+
+	hasPolicies, err := iter.Next()
+	if err != nil {
+		return fmt.Errorf("get next policy: %w", err)
+	}
+
+	if hasPolicies {
+		return fmt.Errorf("%w: %s operation with a key handle of unknown key ID", ErrOperationDenied, op)
+	}
+
+	return nil
+}
+
+// useRate counts the operation against the rate limit of the key. Returns false if the limit is exceeded.
+// The usage is saved to the store, so the limit holds across restarts.
+func (l *Log) useRate(keyID string, limit *RateLimit, now time.Time) (bool, error) {
+	l.usageLock.Lock()
+	defer l.usageLock.Unlock()
+
+	var saved []time.Time
+
+	src, err := l.store.Get(usageKeyPrefix + keyID)
+
+	switch {
+	case errors.Is(err, storage.ErrDataNotFound):
+	case err != nil:
+		return false, fmt.Errorf("get key usage: %w", err)
+	default:
+		if err = json.Unmarshal(src, &saved); err != nil {
+			return false, fmt.Errorf("unmarshal key usage: %w", err)
+		}
+	}
+
+	since := now.Add(-limit.Period)
+
+	var usage []time.Time
+
+	for _, t := range saved {
+		if t.After(since) {
+			usage = append(usage, t)
+		}
+	}
+
+	ok := len(usage) < limit.Operations
+	if ok {
+		usage = append(usage, now)
+	}
+
+	src, err = json.Marshal(usage)
+	if err != nil {
+		return false, fmt.Errorf("marshal key usage: %w", err)
+	}
+
+	if err = l.store.Put(usageKeyPrefix+keyID, src); err != nil {
+		return false, fmt.Errorf("save key usage: %w", err)
+	}
+
+	return ok, nil
+}
+
+func (l *Log) saveRecord(record *Record) error {
+	src, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("marshal record: %w", err)
+	}
+
+	tags := []storage.Tag{{Name: recordTag}}
+
+	if record.KeyID != "" {
+		tags = append(tags, storage.Tag{Name: keyIDTag, Value: encodeTagValue(record.KeyID)})
+	}
+
+	return l.store.Put(recordKeyPrefix+record.ID, src, tags...)
+}
+
+// setKeyID remembers the key ID of the key handle.
+func (l *Log) setKeyID(kh interface{}, keyID string) {
+	if kh == nil || !reflect.TypeOf(kh).Comparable() {
+		return
+	}
+
+	if err := l.handles.Set(kh, keyID); err != nil {
+		logger.Warnf("failed to cache key ID %s of the key handle: %s", keyID, err)
+	}
+}
+
+// keyID returns the key ID of the key handle.
+func (l *Log) keyID(kh interface{}) string {
+	if kh == nil || !reflect.TypeOf(kh).Comparable() {
+		return ""
+	}
+
+	if keyID, err := l.handles.Get(kh); err == nil {
+		return fmt.Sprint(keyID)
+	}
+
+	// remote KMS uses key URLs as key handles
+	if keyURL, ok := kh.(string); ok {
+		return keyURL
+	}
+
+	return ""
+}
+
+func allowed(operations []Operation, op Operation) bool {
+	for _, o := range operations {
+		if o == op {
+			return true
+		}
+	}
+
+	return false
+}
+
+// tag values can't contain ':' used by the store queries, key IDs (ex. remote key URLs) can.
+func encodeTagValue(v string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(v))
+}
+
+var auditPkgPath = reflect.TypeOf(Log{}).PkgPath() //nolint:gochecknoglobals
+
+// caller returns the package which called the audited operation.
+func caller() string {
+	const maxDepth = 32
+
+	pc := make([]uintptr, maxDepth)
+	n := runtime.Callers(2, pc)
+	frames := runtime.CallersFrames(pc[:n])
+
+	for {
+		frame, more := frames.Next()
+
+		pkg := packageName(frame.Function)
+		if pkg != "" && pkg != auditPkgPath {
+			return pkg
+		}
+
+		if !more {
+			return ""
+		}
+	}
+}
+
+// packageName returns the package path of the function name, ex. "github.com/a/b.(*T).F" -> "github.com/a/b".
+func packageName(function string) string {
+	slash := strings.LastIndex(function, "/")
+
+	dot := strings.Index(function[slash+1:], ".")
+	if dot < 0 {
+		return ""
+	}
+
+	return function[:slash+1+dot]
+}
@@ -0,0 +1,311 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package audit
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	mockprovider "github.com/hyperledger/aries-framework-go/pkg/mock/provider"
+	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		l, err := New(&mockprovider.Provider{StorageProviderValue: mem.NewProvider()}, WithHandlesCacheSize(10))
+		require.NoError(t, err)
+		require.NotNil(t, l)
+	})
+
+	t.Run("open store error", func(t *testing.T) {
+		_, err := New(&mockprovider.Provider{StorageProviderValue: &mockstorage.MockStoreProvider{
+			ErrOpenStoreHandle: errors.New("test error"),
+		}})
+		require.EqualError(t, err, "open audit store: test error")
+	})
+
+	t.Run("set store config error", func(t *testing.T) {
+		_, err := New(&mockprovider.Provider{StorageProviderValue: &storeConfigErrProvider{
+			Provider: mem.NewProvider(),
+		}})
+		require.EqualError(t, err, "failed to set store configuration: test error")
+	})
+}
+
+func TestLog_Policy(t *testing.T) {
+	l := newLog(t)
+
+	_, err := l.GetPolicy("key1")
+	require.True(t, errors.Is(err, ErrPolicyNotFound))
+
+	require.EqualError(t, l.SetPolicy(&Policy{}), "key ID is mandatory")
+	require.EqualError(t, l.SetPolicy(&Policy{KeyID: "key1", RateLimit: &RateLimit{Operations: 1}}),
+		"rate limit operations and period must be positive")
+
+	policy := &Policy{
+		KeyID:             "key1",
+		AllowedOperations: []Operation{OperationSign},
+		RateLimit:         &RateLimit{Operations: 10, Period: time.Minute},
+	}
+
+	require.NoError(t, l.SetPolicy(policy))
+
+	result, err := l.GetPolicy("key1")
+	require.NoError(t, err)
+	require.Equal(t, policy, result)
+
+	require.NoError(t, l.RemovePolicy("key1"))
+
+	_, err = l.GetPolicy("key1")
+	require.True(t, errors.Is(err, ErrPolicyNotFound))
+
+	t.Run("store errors", func(t *testing.T) {
+		store := &mockstorage.MockStore{
+			Store:     make(map[string]mockstorage.DBEntry),
+			ErrPut:    errors.New("put error"),
+			ErrGet:    errors.New("get error"),
+			ErrDelete: errors.New("delete error"),
+		}
+
+		l, err := New(&mockprovider.Provider{StorageProviderValue: mockstorage.NewCustomMockStoreProvider(store)})
+		require.NoError(t, err)
+
+		require.EqualError(t, l.SetPolicy(&Policy{KeyID: "key1"}), "save policy: put error")
+
+		_, err = l.GetPolicy("key1")
+		require.EqualError(t, err, "get policy: get error")
+
+		require.EqualError(t, l.RemovePolicy("key1"), "remove policy: delete error")
+
+		store.ErrGet = nil
+		store.Store[policyKeyPrefix+"key1"] = mockstorage.DBEntry{Value: []byte("{")}
+
+		_, err = l.GetPolicy("key1")
+		require.Contains(t, err.Error(), "unmarshal policy")
+	})
+}
+
+func TestLog_Audit(t *testing.T) {
+	now := time.Now()
+
+	t.Run("records outcomes", func(t *testing.T) {
+		l := newLog(t)
+
+		require.NoError(t, l.audit("key1", OperationSign, func() error { return nil }))
+		require.EqualError(t, l.audit("key1", OperationDecrypt, func() error { return errors.New("test error") }),
+			"test error")
+		require.NoError(t, l.audit("key2", OperationUnwrapKey, func() error { return nil }))
+
+		records, err := l.Records("key1")
+		require.NoError(t, err)
+		require.Len(t, records, 2)
+
+		outcomes := map[Operation]*Record{}
+		for _, r := range records {
+			outcomes[r.Operation] = r
+		}
+
+		require.Equal(t, OutcomeSuccess, outcomes[OperationSign].Outcome)
+		require.Equal(t, OutcomeFailure, outcomes[OperationDecrypt].Outcome)
+		require.Equal(t, "test error", outcomes[OperationDecrypt].Error)
+		require.Equal(t, "github.com/hyperledger/aries-framework-go/pkg/crypto/audit", auditPkgPath)
+
+		records, err = l.Records("")
+		require.NoError(t, err)
+		require.Len(t, records, 3)
+	})
+
+	t.Run("allowed operations", func(t *testing.T) {
+		l := newLog(t)
+
+		require.NoError(t, l.SetPolicy(&Policy{KeyID: "key1", AllowedOperations: []Operation{OperationSign}}))
+
+		require.NoError(t, l.audit("key1", OperationSign, func() error { return nil }))
+
+		called := false
+		err := l.audit("key1", OperationDecrypt, func() error {
+			called = true
+
+			return nil
+		})
+		require.True(t, errors.Is(err, ErrOperationDenied))
+		require.Contains(t, err.Error(), "decrypt operation is not allowed for key key1")
+		require.False(t, called)
+
+		records, err := l.Records("key1")
+		require.NoError(t, err)
+		require.Len(t, records, 2)
+	})
+
+	t.Run("expiry", func(t *testing.T) {
+		l := newLog(t)
+		l.now = func() time.Time { return now }
+
+		expires := now.Add(-time.Second)
+		require.NoError(t, l.SetPolicy(&Policy{KeyID: "key1", Expires: &expires}))
+
+		err := l.audit("key1", OperationSign, func() error { return nil })
+		require.True(t, errors.Is(err, ErrOperationDenied))
+		require.Contains(t, err.Error(), "key key1 expired")
+
+		records, err := l.Records("key1")
+		require.NoError(t, err)
+		require.Len(t, records, 1)
+		require.Equal(t, OutcomeDenied, records[0].Outcome)
+	})
+
+	t.Run("rate limit", func(t *testing.T) {
+		l := newLog(t)
+		l.now = func() time.Time { return now }
+
+		require.NoError(t, l.SetPolicy(&Policy{
+			KeyID:     "key1",
+			RateLimit: &RateLimit{Operations: 2, Period: time.Minute},
+		}))
+
+		require.NoError(t, l.audit("key1", OperationSign, func() error { return nil }))
+		require.NoError(t, l.audit("key1", OperationSign, func() error { return nil }))
+
+		err := l.audit("key1", OperationSign, func() error { return nil })
+		require.True(t, errors.Is(err, ErrOperationDenied))
+		require.Contains(t, err.Error(), "rate limit of key key1 exceeded")
+
+		// other keys are not limited
+		require.NoError(t, l.audit("key2", OperationSign, func() error { return nil }))
+
+		l.now = func() time.Time { return now.Add(time.Minute) }
+
+		require.NoError(t, l.audit("key1", OperationSign, func() error { return nil }))
+	})
+
+	t.Run("rate limit usage survives restart", func(t *testing.T) {
+		provider := mem.NewProvider()
+
+		l, err := New(&mockprovider.Provider{StorageProviderValue: provider})
+		require.NoError(t, err)
+
+		l.now = func() time.Time { return now }
+
+		require.NoError(t, l.SetPolicy(&Policy{
+			KeyID:     "key1",
+			RateLimit: &RateLimit{Operations: 1, Period: time.Minute},
+		}))
+
+		require.NoError(t, l.audit("key1", OperationSign, func() error { return nil }))
+
+		l, err = New(&mockprovider.Provider{StorageProviderValue: provider})
+		require.NoError(t, err)
+
+		l.now = func() time.Time { return now }
+
+		err = l.audit("key1", OperationSign, func() error { return nil })
+		require.True(t, errors.Is(err, ErrOperationDenied))
+		require.Contains(t, err.Error(), "rate limit of key key1 exceeded")
+	})
+
+	t.Run("unknown key handles", func(t *testing.T) {
+		l := newLog(t)
+
+		// allowed without policies
+		require.NoError(t, l.audit("", OperationSign, func() error { return nil }))
+
+		require.NoError(t, l.SetPolicy(&Policy{KeyID: "key1", AllowedOperations: []Operation{OperationSign}}))
+
+		called := false
+		err := l.audit("", OperationSign, func() error {
+			called = true
+
+			return nil
+		})
+		require.True(t, errors.Is(err, ErrOperationDenied))
+		require.Contains(t, err.Error(), "sign operation with a key handle of unknown key ID")
+		require.False(t, called)
+	})
+
+	t.Run("key IDs with colons", func(t *testing.T) {
+		l := newLog(t)
+
+		keyURL := "https://kms.example.com/kms/keystores/ks1/keys/key1"
+
+		require.NoError(t, l.audit(keyURL, OperationSign, func() error { return nil }))
+
+		records, err := l.Records(keyURL)
+		require.NoError(t, err)
+		require.Len(t, records, 1)
+		require.Equal(t, keyURL, records[0].KeyID)
+	})
+
+	t.Run("fails if the record can't be saved", func(t *testing.T) {
+		l, err := New(&mockprovider.Provider{StorageProviderValue: &mockstorage.MockStoreProvider{
+			Store: &mockstorage.MockStore{
+				Store:  make(map[string]mockstorage.DBEntry),
+				ErrPut: errors.New("put error"),
+			},
+		}})
+		require.NoError(t, err)
+
+		err = l.audit("key1", OperationSign, func() error { return nil })
+		require.EqualError(t, err, "audit sign operation: put error")
+	})
+
+	t.Run("fails if the policy can't be read", func(t *testing.T) {
+		store := &mockstorage.MockStore{
+			Store:  make(map[string]mockstorage.DBEntry),
+			ErrGet: errors.New("get error"),
+		}
+
+		l, err := New(&mockprovider.Provider{StorageProviderValue: mockstorage.NewCustomMockStoreProvider(store)})
+		require.NoError(t, err)
+
+		err = l.audit("key1", OperationSign, func() error { return nil })
+		require.EqualError(t, err, "get policy: get error")
+	})
+
+	t.Run("fails if the key usage can't be saved", func(t *testing.T) {
+		l := newLog(t)
+
+		require.NoError(t, l.SetPolicy(&Policy{
+			KeyID:     "key1",
+			RateLimit: &RateLimit{Operations: 1, Period: time.Minute},
+		}))
+
+		require.NoError(t, l.store.Put(usageKeyPrefix+"key1", []byte("{")))
+
+		err := l.audit("key1", OperationSign, func() error { return nil })
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal key usage")
+	})
+}
+
+func TestPackageName(t *testing.T) {
+	require.Equal(t, "github.com/a/b", packageName("github.com/a/b.(*T).F"))
+	require.Equal(t, "github.com/a/b", packageName("github.com/a/b.F.func1"))
+	require.Equal(t, "main", packageName("main.main"))
+	require.Equal(t, "", packageName("github.com/a/b"))
+}
+
+func newLog(t *testing.T) *Log {
+	t.Helper()
+
+	l, err := New(&mockprovider.Provider{StorageProviderValue: mem.NewProvider()})
+	require.NoError(t, err)
+
+	return l
+}
+
+type storeConfigErrProvider struct {
+	storage.Provider
+}
+
+func (p *storeConfigErrProvider) SetStoreConfig(string, storage.StoreConfiguration) error {
+	return errors.New("test error")
+}
@@ -0,0 +1,128 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package audit
+
+import (
+	"github.com/hyperledger/aries-framework-go/pkg/crypto"
+)
+
+// auditedCrypto records sign, decrypt and unwrap operations of the wrapped Crypto. Operations with public keys are
+// passed through as is.
+type auditedCrypto struct {
+	crypto.Crypto
+	log *Log
+}
+
+// Crypto returns Crypto which records the operations made with private keys to the audit log and enforces key
+// usage policies.
+func (l *Log) Crypto(c crypto.Crypto) crypto.Crypto {
+	return &auditedCrypto{Crypto: c, log: l}
+}
+
+// Decrypt will decrypt cipher with aad and given nonce using a matching AEAD primitive in kh key handle of a
+// private key.
+func (c *auditedCrypto) Decrypt(cipher, aad, nonce []byte, kh interface{}) ([]byte, error) {
+	var plainText []byte
+
+	err := c.log.audit(c.log.keyID(kh), OperationDecrypt, func() error {
+		var err error
+
+		plainText, err = c.Crypto.Decrypt(cipher, aad, nonce, kh)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return plainText, nil
+}
+
+// Sign will sign msg using a matching signature primitive in kh key handle of a private key.
+func (c *auditedCrypto) Sign(msg []byte, kh interface{}) ([]byte, error) {
+	var signature []byte
+
+	err := c.log.audit(c.log.keyID(kh), OperationSign, func() error {
+		var err error
+
+		signature, err = c.Crypto.Sign(msg, kh)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return signature, nil
+}
+
+// UnwrapKey unwraps a key in recWK using recipient private key kh.
+func (c *auditedCrypto) UnwrapKey(recWK *crypto.RecipientWrappedKey, kh interface{},
+	opts ...crypto.WrapKeyOpts) ([]byte, error) {
+	// the recipient key ID only labels the record of unknown key handle, the policy is not checked with it
+	keyID := c.log.keyID(kh)
+	recordKeyID := keyID
+
+	if keyID == "" && recWK != nil {
+		recordKeyID = recWK.KID
+	}
+
+	var key []byte
+
+	err := c.log.auditAs(keyID, recordKeyID, OperationUnwrapKey, func() error {
+		var err error
+
+		key, err = c.Crypto.UnwrapKey(recWK, kh, opts...)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// SignMulti will create a signature of messages using a matching signing primitive found in kh key handle of a
+// private key.
+func (c *auditedCrypto) SignMulti(messages [][]byte, kh interface{}) ([]byte, error) {
+	var signature []byte
+
+	err := c.log.audit(c.log.keyID(kh), OperationSign, func() error {
+		var err error
+
+		signature, err = c.Crypto.SignMulti(messages, kh)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return signature, nil
+}
+
+// BlindSignMulti will create a blind signature of messages using a matching signing primitive found in kh key
+// handle of a private key.
+func (c *auditedCrypto) BlindSignMulti(revealedMessages [][]byte, revealedIndexes []int, messagesCount int,
+	blindContext, nonce []byte, kh interface{}) ([]byte, error) {
+	var signature []byte
+
+	err := c.log.audit(c.log.keyID(kh), OperationSign, func() error {
+		var err error
+
+		signature, err = c.Crypto.BlindSignMulti(revealedMessages, revealedIndexes, messagesCount, blindContext,
+			nonce, kh)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return signature, nil
+}
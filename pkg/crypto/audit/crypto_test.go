@@ -0,0 +1,223 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package audit
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/localkms"
+	mockcrypto "github.com/hyperledger/aries-framework-go/pkg/mock/crypto"
+	"github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock/noop"
+)
+
+func TestAuditedCrypto(t *testing.T) {
+	l := newLog(t)
+	km := l.KeyManager(newKMS(t))
+
+	tc, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	c := l.Crypto(tc)
+
+	t.Run("sign and verify", func(t *testing.T) {
+		kid, kh, err := km.Create(kms.ED25519Type)
+		require.NoError(t, err)
+
+		msg := []byte("test message")
+
+		sig, err := c.Sign(msg, kh)
+		require.NoError(t, err)
+
+		pubKH, err := km.PubKeyBytesToHandle(exportPubKey(t, km, kid), kms.ED25519Type)
+		require.NoError(t, err)
+
+		// verification is not audited
+		require.NoError(t, c.Verify(sig, msg, pubKH))
+
+		records, err := l.Records(kid)
+		require.NoError(t, err)
+		require.Len(t, records, 2)
+
+		ops := map[Operation]*Record{}
+		for _, r := range records {
+			ops[r.Operation] = r
+		}
+
+		require.Equal(t, OutcomeSuccess, ops[OperationCreate].Outcome)
+		require.Equal(t, OutcomeSuccess, ops[OperationSign].Outcome)
+		// frames of the audit package are skipped, the test function is called by the testing package
+		require.Equal(t, "testing", ops[OperationSign].Caller)
+	})
+
+	t.Run("sign denied by policy", func(t *testing.T) {
+		kid, kh, err := km.Create(kms.ED25519Type)
+		require.NoError(t, err)
+
+		require.NoError(t, l.SetPolicy(&Policy{KeyID: kid, AllowedOperations: []Operation{OperationDecrypt}}))
+
+		_, err = c.Sign([]byte("test message"), kh)
+		require.True(t, errors.Is(err, ErrOperationDenied))
+	})
+
+	t.Run("key handle loaded with Get", func(t *testing.T) {
+		kid, _, err := km.Create(kms.ED25519Type)
+		require.NoError(t, err)
+
+		kh, err := km.Get(kid)
+		require.NoError(t, err)
+
+		_, err = c.Sign([]byte("test message"), kh)
+		require.NoError(t, err)
+
+		records, err := l.Records(kid)
+		require.NoError(t, err)
+		require.Len(t, records, 2)
+	})
+
+	t.Run("decrypt", func(t *testing.T) {
+		kid, kh, err := km.Create(kms.AES256GCMType)
+		require.NoError(t, err)
+
+		cipherText, nonce, err := c.Encrypt([]byte("test message"), nil, kh)
+		require.NoError(t, err)
+
+		plainText, err := c.Decrypt(cipherText, nil, nonce, kh)
+		require.NoError(t, err)
+		require.Equal(t, []byte("test message"), plainText)
+
+		_, err = c.Decrypt([]byte("invalid"), nil, nonce, kh)
+		require.Error(t, err)
+
+		records, err := l.Records(kid)
+		require.NoError(t, err)
+		require.Len(t, records, 3)
+	})
+}
+
+func TestAuditedCrypto_MockCrypto(t *testing.T) {
+	l := newLog(t)
+
+	t.Run("success", func(t *testing.T) {
+		c := l.Crypto(&mockcrypto.Crypto{
+			SignValue:         []byte("signature"),
+			BBSSignValue:      []byte("bbs signature"),
+			BBSBlindSignValue: []byte("bbs blind signature"),
+			DecryptValue:      []byte("plain text"),
+			UnwrapValue:       []byte("cek"),
+		})
+
+		keyURL := "https://kms.example.com/kms/keystores/ks1/keys/key1"
+
+		sig, err := c.Sign(nil, keyURL)
+		require.NoError(t, err)
+		require.Equal(t, []byte("signature"), sig)
+
+		sig, err = c.SignMulti(nil, keyURL)
+		require.NoError(t, err)
+		require.Equal(t, []byte("bbs signature"), sig)
+
+		sig, err = c.BlindSignMulti(nil, nil, 1, nil, nil, keyURL)
+		require.NoError(t, err)
+		require.Equal(t, []byte("bbs blind signature"), sig)
+
+		plainText, err := c.Decrypt(nil, nil, nil, keyURL)
+		require.NoError(t, err)
+		require.Equal(t, []byte("plain text"), plainText)
+
+		cek, err := c.UnwrapKey(&crypto.RecipientWrappedKey{KID: "kid1"}, struct{}{})
+		require.NoError(t, err)
+		require.Equal(t, []byte("cek"), cek)
+
+		records, err := l.Records(keyURL)
+		require.NoError(t, err)
+		require.Len(t, records, 4)
+
+		// unknown key handles are audited with the recipient key ID
+		records, err = l.Records("kid1")
+		require.NoError(t, err)
+		require.Len(t, records, 1)
+		require.Equal(t, OperationUnwrapKey, records[0].Operation)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		c := l.Crypto(&mockcrypto.Crypto{
+			SignErr:         errors.New("sign error"),
+			BBSSignErr:      errors.New("bbs sign error"),
+			BBSBlindSignErr: errors.New("bbs blind sign error"),
+			DecryptErr:      errors.New("decrypt error"),
+			UnwrapError:     errors.New("unwrap error"),
+		})
+
+		_, err := c.Sign(nil, "key2")
+		require.EqualError(t, err, "sign error")
+
+		_, err = c.SignMulti(nil, "key2")
+		require.EqualError(t, err, "bbs sign error")
+
+		_, err = c.BlindSignMulti(nil, nil, 1, nil, nil, "key2")
+		require.EqualError(t, err, "bbs blind sign error")
+
+		_, err = c.Decrypt(nil, nil, nil, "key2")
+		require.EqualError(t, err, "decrypt error")
+
+		_, err = c.UnwrapKey(nil, "key2")
+		require.EqualError(t, err, "unwrap error")
+
+		records, err := l.Records("key2")
+		require.NoError(t, err)
+		require.Len(t, records, 5)
+
+		for _, r := range records {
+			require.Equal(t, OutcomeFailure, r.Outcome)
+		}
+	})
+
+	t.Run("unknown key handles are denied once policies are set", func(t *testing.T) {
+		l := newLog(t)
+		c := l.Crypto(&mockcrypto.Crypto{UnwrapValue: []byte("cek")})
+
+		// the recipient key ID doesn't pick the policy of the unknown key handle
+		require.NoError(t, l.SetPolicy(&Policy{KeyID: "kid1"}))
+
+		_, err := c.UnwrapKey(&crypto.RecipientWrappedKey{KID: "kid1"}, struct{}{})
+		require.True(t, errors.Is(err, ErrOperationDenied))
+
+		records, err := l.Records("kid1")
+		require.NoError(t, err)
+		require.Len(t, records, 1)
+		require.Equal(t, OutcomeDenied, records[0].Outcome)
+	})
+}
+
+func newKMS(t *testing.T) kms.KeyManager {
+	t.Helper()
+
+	km, err := localkms.New("local-lock://primary/test/", &protocol.MockProvider{
+		StoreProvider: mem.NewProvider(),
+		CustomLock:    &noop.NoLock{},
+	})
+	require.NoError(t, err)
+
+	return km
+}
+
+func exportPubKey(t *testing.T, km kms.KeyManager, kid string) []byte {
+	t.Helper()
+
+	pubKey, err := km.ExportPubKeyBytes(kid)
+	require.NoError(t, err)
+
+	return pubKey
+}
@@ -0,0 +1,87 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package audit
+
+import (
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+)
+
+// auditedKeyManager records key creation, rotation and import of the wrapped KeyManager and remembers key IDs of
+// the returned key handles for the audited Crypto.
+type auditedKeyManager struct {
+	kms.KeyManager
+	log *Log
+}
+
+// KeyManager returns KeyManager which records the operations creating private keys to the audit log.
+func (l *Log) KeyManager(km kms.KeyManager) kms.KeyManager {
+	return &auditedKeyManager{KeyManager: km, log: l}
+}
+
+// Create a new key/keyset/key handle for the type kt.
+func (k *auditedKeyManager) Create(kt kms.KeyType) (string, interface{}, error) {
+	keyID, kh, err := k.KeyManager.Create(kt)
+
+	if err = k.log.recordResult(keyID, OperationCreate, err); err != nil {
+		return "", nil, err
+	}
+
+	k.log.setKeyID(kh, keyID)
+
+	return keyID, kh, nil
+}
+
+// Get key handle for the given keyID.
+func (k *auditedKeyManager) Get(keyID string) (interface{}, error) {
+	kh, err := k.KeyManager.Get(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	k.log.setKeyID(kh, keyID)
+
+	return kh, nil
+}
+
+// Rotate a key referenced by keyID and return a new handle of a keyset including old key and
+// new key with type kt.
+func (k *auditedKeyManager) Rotate(kt kms.KeyType, keyID string) (string, interface{}, error) {
+	newKeyID, kh, err := k.KeyManager.Rotate(kt, keyID)
+
+	if err = k.log.recordResult(keyID, OperationRotate, err); err != nil {
+		return "", nil, err
+	}
+
+	k.log.setKeyID(kh, newKeyID)
+
+	return newKeyID, kh, nil
+}
+
+// CreateAndExportPubKeyBytes will create a key of type kt and export its public key in raw bytes.
+func (k *auditedKeyManager) CreateAndExportPubKeyBytes(kt kms.KeyType) (string, []byte, error) {
+	keyID, pubKey, err := k.KeyManager.CreateAndExportPubKeyBytes(kt)
+
+	if err = k.log.recordResult(keyID, OperationCreate, err); err != nil {
+		return "", nil, err
+	}
+
+	return keyID, pubKey, nil
+}
+
+// ImportPrivateKey will import privKey into the KMS storage for the given keyType.
+func (k *auditedKeyManager) ImportPrivateKey(privKey interface{}, kt kms.KeyType,
+	opts ...kms.PrivateKeyOpts) (string, interface{}, error) {
+	keyID, kh, err := k.KeyManager.ImportPrivateKey(privKey, kt, opts...)
+
+	if err = k.log.recordResult(keyID, OperationImport, err); err != nil {
+		return "", nil, err
+	}
+
+	k.log.setKeyID(kh, keyID)
+
+	return keyID, kh, nil
+}
@@ -0,0 +1,94 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package audit
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
+)
+
+func TestAuditedKeyManager(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		l := newLog(t)
+		km := l.KeyManager(newKMS(t))
+
+		kid, _, err := km.Create(kms.ED25519Type)
+		require.NoError(t, err)
+
+		newKID, _, err := km.Rotate(kms.ED25519Type, kid)
+		require.NoError(t, err)
+
+		pubKID, _, err := km.CreateAndExportPubKeyBytes(kms.ED25519Type)
+		require.NoError(t, err)
+
+		_, privKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		importedKID, _, err := km.ImportPrivateKey(privKey, kms.ED25519Type)
+		require.NoError(t, err)
+
+		for kid, ops := range map[string][]Operation{
+			kid:         {OperationCreate, OperationRotate},
+			pubKID:      {OperationCreate},
+			importedKID: {OperationImport},
+		} {
+			records, err := l.Records(kid)
+			require.NoError(t, err)
+			require.Len(t, records, len(ops))
+
+			for _, r := range records {
+				require.Contains(t, ops, r.Operation)
+				require.Equal(t, OutcomeSuccess, r.Outcome)
+			}
+		}
+
+		records, err := l.Records(newKID)
+		require.NoError(t, err)
+		require.Empty(t, records)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		l := newLog(t)
+		km := l.KeyManager(&mockkms.KeyManager{
+			CreateKeyErr:         errors.New("create error"),
+			GetKeyErr:            errors.New("get error"),
+			RotateKeyErr:         errors.New("rotate error"),
+			CrAndExportPubKeyErr: errors.New("create and export error"),
+			ImportPrivateKeyErr:  errors.New("import error"),
+		})
+
+		_, _, err := km.Create(kms.ED25519Type)
+		require.EqualError(t, err, "create error")
+
+		_, err = km.Get("key1")
+		require.EqualError(t, err, "get error")
+
+		_, _, err = km.Rotate(kms.ED25519Type, "key1")
+		require.EqualError(t, err, "rotate error")
+
+		_, _, err = km.CreateAndExportPubKeyBytes(kms.ED25519Type)
+		require.EqualError(t, err, "create and export error")
+
+		_, _, err = km.ImportPrivateKey(nil, kms.ED25519Type)
+		require.EqualError(t, err, "import error")
+
+		records, err := l.Records("")
+		require.NoError(t, err)
+		require.Len(t, records, 4)
+
+		for _, r := range records {
+			require.Equal(t, OutcomeFailure, r.Outcome)
+		}
+	})
+}
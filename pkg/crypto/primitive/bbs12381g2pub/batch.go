@@ -0,0 +1,179 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bbs12381g2pub
+
+import (
+	"errors"
+	"fmt"
+
+	bls12381 "github.com/kilic/bls12-381"
+)
+
+// SignatureBatchEntry is a BBS+ signature of the messages to be verified by BatchVerify.
+type SignatureBatchEntry struct {
+	Messages  [][]byte
+	Signature []byte
+	PublicKey []byte
+}
+
+// ProofBatchEntry is a BBS+ signature proof of the revealed messages to be verified by BatchVerifyProof.
+type ProofBatchEntry struct {
+	Messages  [][]byte
+	Proof     []byte
+	Nonce     []byte
+	PublicKey []byte
+}
+
+// BatchVerify verifies several BLS BBS12-381 signatures at once. Instead of two pairings per signature, a random
+// linear combination of the signatures equations is checked with one pairing per distinct public key plus one.
+// If the batch does not hold, the signatures are verified one by one to report the first invalid one.
+func (bbs *BBSG2Pub) BatchVerify(entries []*SignatureBatchEntry) error {
+	if len(entries) == 0 {
+		return errors.New("no signatures to verify")
+	}
+
+	signatures := make([]*Signature, len(entries))
+	messages := make([][]*SignatureMessage, len(entries))
+	pubKeys := make([]*PublicKeyWithGenerators, len(entries))
+
+	batch := newPairingBatch()
+
+	for i, entry := range entries {
+		signature, err := ParseSignature(entry.Signature)
+		if err != nil {
+			return fmt.Errorf("signature %d: parse signature: %w", i, err)
+		}
+
+		pubKey, err := UnmarshalPublicKey(entry.PublicKey)
+		if err != nil {
+			return fmt.Errorf("signature %d: parse public key: %w", i, err)
+		}
+
+		publicKeyWithGenerators, err := pubKey.ToPublicKeyWithGenerators(len(entry.Messages))
+		if err != nil {
+			return fmt.Errorf("signature %d: build generators from public key: %w", i, err)
+		}
+
+		signatures[i], messages[i], pubKeys[i] = signature, messagesToFr(entry.Messages), publicKeyWithGenerators
+
+		// e(A, w + e*g2) = e(B, g2) is checked as e(A, w) * e(e*A - B, g2) = 1.
+		q := g1.New()
+		g1.MulScalar(q, signature.A, frToRepr(signature.E))
+		g1.Sub(q, q, computeB(signature.S, messages[i], publicKeyWithGenerators))
+
+		batch.add(signature.A, publicKeyWithGenerators.w, q)
+	}
+
+	if batch.check() {
+		return nil
+	}
+
+	for i := range signatures {
+		if err := signatures[i].Verify(messages[i], pubKeys[i]); err != nil {
+			return fmt.Errorf("signature %d: %w", i, err)
+		}
+	}
+
+	return errors.New("invalid BLS12-381 signatures batch")
+}
+
+// BatchVerifyProof verifies several BBS+ signature proofs at once. The proofs of knowledge are verified one by one
+// while the pairing checks of all the proofs are combined into a single random linear combination check.
+// If the batch does not hold, the proofs are verified one by one to report the first invalid one.
+func (bbs *BBSG2Pub) BatchVerifyProof(entries []*ProofBatchEntry) error {
+	if len(entries) == 0 {
+		return errors.New("no signature proofs to verify")
+	}
+
+	proofs := make([]*verifiableProof, len(entries))
+
+	batch := newPairingBatch()
+
+	for i, entry := range entries {
+		vp, err := parseVerifiableProof(entry.Messages, entry.Proof, entry.Nonce, entry.PublicKey)
+		if err != nil {
+			return fmt.Errorf("signature proof %d: %w", i, err)
+		}
+
+		err = vp.proof.verifyVCProofs(vp.challenge, vp.pubKey, vp.revealedMessages, vp.messages)
+		if err != nil {
+			return fmt.Errorf("signature proof %d: %w", i, err)
+		}
+
+		proofs[i] = vp
+
+		// e(A', w) = e(Abar, g2) is checked as e(A', w) * e(-Abar, g2) = 1.
+		aBar := g1.New()
+		g1.Neg(aBar, vp.proof.aBar)
+
+		batch.add(vp.proof.aPrime, vp.pubKey.w, aBar)
+	}
+
+	if batch.check() {
+		return nil
+	}
+
+	for i, vp := range proofs {
+		if err := vp.verify(); err != nil {
+			return fmt.Errorf("signature proof %d: %w", i, err)
+		}
+	}
+
+	return errors.New("invalid BBS+ signature proofs batch")
+}
+
+// pairingBatch accumulates pairing equations of form e(P, w) * e(Q, g2) = 1, each one multiplied by
+// a random scalar so that the product of all of them can be checked at once.
+type pairingBatch struct {
+	keys     []*bls12381.PointG2
+	keysSums []*bls12381.PointG1
+	keysInd  map[string]int
+
+	g2Sum *bls12381.PointG1
+}
+
+func newPairingBatch() *pairingBatch {
+	return &pairingBatch{
+		keysInd: make(map[string]int),
+		g2Sum:   g1.Zero(),
+	}
+}
+
+func (pb *pairingBatch) add(p *bls12381.PointG1, w *bls12381.PointG2, q *bls12381.PointG1) {
+	r := frToRepr(createRandSignatureFr())
+
+	key := string(g2.ToCompressed(w))
+
+	ind, ok := pb.keysInd[key]
+	if !ok {
+		ind = len(pb.keys)
+		pb.keysInd[key] = ind
+
+		pb.keys = append(pb.keys, w)
+		pb.keysSums = append(pb.keysSums, g1.Zero())
+	}
+
+	rp := g1.New()
+	g1.MulScalar(rp, p, r)
+	g1.Add(pb.keysSums[ind], pb.keysSums[ind], rp)
+
+	rq := g1.New()
+	g1.MulScalar(rq, q, r)
+	g1.Add(pb.g2Sum, pb.g2Sum, rq)
+}
+
+func (pb *pairingBatch) check() bool {
+	engine := bls12381.NewEngine()
+
+	for i := range pb.keys {
+		engine.AddPair(pb.keysSums[i], pb.keys[i])
+	}
+
+	engine.AddPair(pb.g2Sum, g2.One())
+
+	return engine.Check()
+}
@@ -0,0 +1,164 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bbs12381g2pub_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	bbs "github.com/hyperledger/aries-framework-go/pkg/crypto/primitive/bbs12381g2pub"
+)
+
+func TestBBSG2Pub_BatchVerify(t *testing.T) {
+	bls := bbs.New()
+
+	entries := make([]*bbs.SignatureBatchEntry, 0)
+
+	// two entries per key to cover grouping of pairings by the public key
+	for i := 0; i < 3; i++ {
+		pubKey, privKey, err := generateKeyPairRandom()
+		require.NoError(t, err)
+
+		privKeyBytes, err := privKey.Marshal()
+		require.NoError(t, err)
+
+		pubKeyBytes, err := pubKey.Marshal()
+		require.NoError(t, err)
+
+		for j := 0; j <= i; j++ {
+			messagesBytes := [][]byte{[]byte(fmt.Sprintf("message%d-%d", i, j)), []byte("message")}
+
+			signatureBytes, err := bls.Sign(messagesBytes, privKeyBytes)
+			require.NoError(t, err)
+
+			entries = append(entries, &bbs.SignatureBatchEntry{
+				Messages:  messagesBytes,
+				Signature: signatureBytes,
+				PublicKey: pubKeyBytes,
+			})
+		}
+	}
+
+	t.Run("valid signatures", func(t *testing.T) {
+		require.NoError(t, bls.BatchVerify(entries))
+		require.NoError(t, bls.BatchVerify(entries[:1]))
+	})
+
+	t.Run("invalid signature", func(t *testing.T) {
+		invalidEntries := make([]*bbs.SignatureBatchEntry, len(entries))
+		copy(invalidEntries, entries)
+
+		invalidEntries[3] = &bbs.SignatureBatchEntry{
+			Messages:  [][]byte{[]byte("message"), []byte("message")},
+			Signature: entries[3].Signature,
+			PublicKey: entries[3].PublicKey,
+		}
+
+		err := bls.BatchVerify(invalidEntries)
+		require.EqualError(t, err, "signature 3: invalid BLS12-381 signature")
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		err := bls.BatchVerify(nil)
+		require.EqualError(t, err, "no signatures to verify")
+
+		err = bls.BatchVerify([]*bbs.SignatureBatchEntry{entries[0], {
+			Messages:  entries[1].Messages,
+			Signature: []byte("invalid"),
+			PublicKey: entries[1].PublicKey,
+		}})
+		require.EqualError(t, err, "signature 1: parse signature: invalid size of signature")
+
+		err = bls.BatchVerify([]*bbs.SignatureBatchEntry{{
+			Messages:  entries[0].Messages,
+			Signature: entries[0].Signature,
+			PublicKey: []byte("invalid"),
+		}})
+		require.EqualError(t, err, "signature 0: parse public key: invalid size of public key")
+	})
+}
+
+func TestBBSG2Pub_BatchVerifyProof(t *testing.T) {
+	bls := bbs.New()
+
+	nonce := []byte("nonce")
+	revealedIndexes := []int{0, 2}
+
+	entries := make([]*bbs.ProofBatchEntry, 0)
+
+	for i := 0; i < 3; i++ {
+		pubKey, privKey, err := generateKeyPairRandom()
+		require.NoError(t, err)
+
+		privKeyBytes, err := privKey.Marshal()
+		require.NoError(t, err)
+
+		pubKeyBytes, err := pubKey.Marshal()
+		require.NoError(t, err)
+
+		messagesBytes := [][]byte{
+			[]byte(fmt.Sprintf("message%d", i)),
+			[]byte("message2"),
+			[]byte("message3"),
+		}
+
+		signatureBytes, err := bls.Sign(messagesBytes, privKeyBytes)
+		require.NoError(t, err)
+
+		proofBytes, err := bls.DeriveProof(messagesBytes, signatureBytes, nonce, pubKeyBytes, revealedIndexes)
+		require.NoError(t, err)
+
+		entries = append(entries, &bbs.ProofBatchEntry{
+			Messages:  [][]byte{messagesBytes[0], messagesBytes[2]},
+			Proof:     proofBytes,
+			Nonce:     nonce,
+			PublicKey: pubKeyBytes,
+		})
+	}
+
+	t.Run("valid proofs", func(t *testing.T) {
+		require.NoError(t, bls.BatchVerifyProof(entries))
+	})
+
+	t.Run("invalid proof", func(t *testing.T) {
+		invalidEntries := []*bbs.ProofBatchEntry{entries[0], {
+			Messages:  entries[1].Messages,
+			Proof:     entries[1].Proof,
+			Nonce:     []byte("other nonce"),
+			PublicKey: entries[1].PublicKey,
+		}}
+
+		err := bls.BatchVerifyProof(invalidEntries)
+		require.EqualError(t, err, "signature proof 1: bad signature")
+
+		// proof of knowledge is valid but the signature was made by other key
+		invalidEntries = []*bbs.ProofBatchEntry{entries[0], {
+			Messages:  entries[1].Messages,
+			Proof:     entries[1].Proof,
+			Nonce:     nonce,
+			PublicKey: entries[2].PublicKey,
+		}}
+
+		err = bls.BatchVerifyProof(invalidEntries)
+		require.EqualError(t, err, "signature proof 1: bad signature")
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		err := bls.BatchVerifyProof(nil)
+		require.EqualError(t, err, "no signature proofs to verify")
+
+		err = bls.BatchVerifyProof([]*bbs.ProofBatchEntry{{
+			Messages:  entries[0].Messages,
+			Proof:     entries[0].Proof,
+			Nonce:     nonce,
+			PublicKey: []byte("invalid"),
+		}})
+		require.EqualError(t, err, "signature proof 0: parse public key: invalid size of public key")
+	})
+}
@@ -96,30 +96,48 @@ func (bbs *BBSG2Pub) Sign(messages [][]byte, privKeyBytes []byte) ([]byte, error
 
 // VerifyProof verifies BBS+ signature proof for one ore more revealed messages.
 func (bbs *BBSG2Pub) VerifyProof(messagesBytes [][]byte, proof, nonce, pubKeyBytes []byte) error {
+	vp, err := parseVerifiableProof(messagesBytes, proof, nonce, pubKeyBytes)
+	if err != nil {
+		return err
+	}
+
+	return vp.verify()
+}
+
+// verifiableProof is a BBS+ signature proof along with the data required for its verification.
+type verifiableProof struct {
+	proof            *PoKOfSignatureProof
+	challenge        *bls12381.Fr
+	pubKey           *PublicKeyWithGenerators
+	revealedMessages map[int]*SignatureMessage
+	messages         []*SignatureMessage
+}
+
+func parseVerifiableProof(messagesBytes [][]byte, proof, nonce, pubKeyBytes []byte) (*verifiableProof, error) {
 	payload, err := parsePoKPayload(proof)
 	if err != nil {
-		return fmt.Errorf("parse signature proof: %w", err)
+		return nil, fmt.Errorf("parse signature proof: %w", err)
 	}
 
 	signatureProof, err := ParseSignatureProof(proof[payload.lenInBytes():])
 	if err != nil {
-		return fmt.Errorf("parse signature proof: %w", err)
+		return nil, fmt.Errorf("parse signature proof: %w", err)
 	}
 
 	messages := messagesToFr(messagesBytes)
 
 	pubKey, err := UnmarshalPublicKey(pubKeyBytes)
 	if err != nil {
-		return fmt.Errorf("parse public key: %w", err)
+		return nil, fmt.Errorf("parse public key: %w", err)
 	}
 
 	publicKeyWithGenerators, err := pubKey.ToPublicKeyWithGenerators(payload.messagesCount)
 	if err != nil {
-		return fmt.Errorf("build generators from public key: %w", err)
+		return nil, fmt.Errorf("build generators from public key: %w", err)
 	}
 
 	if len(payload.revealed) > len(messages) {
-		return fmt.Errorf("payload revealed bigger from messages")
+		return nil, fmt.Errorf("payload revealed bigger from messages")
 	}
 
 	revealedMessages := make(map[int]*SignatureMessage)
@@ -133,7 +151,17 @@ func (bbs *BBSG2Pub) VerifyProof(messagesBytes [][]byte, proof, nonce, pubKeyByt
 	challengeBytes = append(challengeBytes, proofNonceBytes...)
 	proofChallenge := frFromOKM(challengeBytes)
 
-	return signatureProof.Verify(proofChallenge, publicKeyWithGenerators, revealedMessages, messages)
+	return &verifiableProof{
+		proof:            signatureProof,
+		challenge:        proofChallenge,
+		pubKey:           publicKeyWithGenerators,
+		revealedMessages: revealedMessages,
+		messages:         messages,
+	}, nil
+}
+
+func (vp *verifiableProof) verify() error {
+	return vp.proof.Verify(vp.challenge, vp.pubKey, vp.revealedMessages, vp.messages)
 }
 
 // DeriveProof derives a proof of BBS+ signature with some messages disclosed.
@@ -191,6 +219,114 @@ func (bbs *BBSG2Pub) DeriveProof(messages [][]byte, sigBytes, nonce, pubKeyBytes
 	return signatureProofBytes, nil
 }
 
+// BlindMessages commits to the hidden messages (e.g. a holder secret) which take hiddenIndexes positions among
+// messagesCount messages to be signed. It returns the blind signature context to be sent to the issuer along with
+// the signature blinding to be kept by the holder for unblinding the issued signature.
+func (bbs *BBSG2Pub) BlindMessages(hiddenMessages [][]byte, hiddenIndexes []int, messagesCount int,
+	nonce, pubKeyBytes []byte) ([]byte, []byte, error) {
+	if len(hiddenMessages) != len(hiddenIndexes) {
+		return nil, nil, errors.New("hidden messages and indexes count mismatch")
+	}
+
+	pubKey, err := UnmarshalPublicKey(pubKeyBytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parse public key: %w", err)
+	}
+
+	publicKeyWithGenerators, err := pubKey.ToPublicKeyWithGenerators(messagesCount)
+	if err != nil {
+		return nil, nil, fmt.Errorf("build generators from public key: %w", err)
+	}
+
+	hidden := make(map[int]*SignatureMessage, len(hiddenMessages))
+
+	for i, ind := range hiddenIndexes {
+		if _, ok := hidden[ind]; ok {
+			return nil, nil, fmt.Errorf("duplicated hidden message index %d", ind)
+		}
+
+		hidden[ind] = ParseSignatureMessage(hiddenMessages[i])
+	}
+
+	ctx, blinding, err := NewBlindSignatureContext(hidden, publicKeyWithGenerators, ParseProofNonce(nonce))
+	if err != nil {
+		return nil, nil, fmt.Errorf("create blind signature context: %w", err)
+	}
+
+	return ctx.ToBytes(), blinding.ToBytes(), nil
+}
+
+// BlindSign verifies the holder's commitment to the hidden messages in the blind signature context and signs it
+// along with the revealed messages using private key in compressed form. Messages that are not revealed are
+// expected to be committed to in the blind signature context.
+func (bbs *BBSG2Pub) BlindSign(revealedMessages [][]byte, revealedIndexes []int, messagesCount int,
+	blindContext, nonce, privKeyBytes []byte) ([]byte, error) {
+	if len(revealedMessages) != len(revealedIndexes) {
+		return nil, errors.New("revealed messages and indexes count mismatch")
+	}
+
+	privKey, err := UnmarshalPrivateKey(privKeyBytes)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal private key: %w", err)
+	}
+
+	ctx, err := ParseBlindSignatureContext(blindContext)
+	if err != nil {
+		return nil, fmt.Errorf("parse blind signature context: %w", err)
+	}
+
+	publicKeyWithGenerators, err := privKey.PublicKey().ToPublicKeyWithGenerators(messagesCount)
+	if err != nil {
+		return nil, fmt.Errorf("build generators from public key: %w", err)
+	}
+
+	revealed := make(map[int]*SignatureMessage, len(revealedMessages))
+
+	for i, ind := range revealedIndexes {
+		if ind < 0 || ind >= messagesCount {
+			return nil, fmt.Errorf("revealed message index %d is out of range", ind)
+		}
+
+		revealed[ind] = ParseSignatureMessage(revealedMessages[i])
+	}
+
+	hiddenIndexes := make([]int, 0, messagesCount-len(revealed))
+
+	for i := 0; i < messagesCount; i++ {
+		if _, ok := revealed[i]; !ok {
+			hiddenIndexes = append(hiddenIndexes, i)
+		}
+	}
+
+	err = ctx.Verify(hiddenIndexes, publicKeyWithGenerators, ParseProofNonce(nonce))
+	if err != nil {
+		return nil, fmt.Errorf("verify blind signature context: %w", err)
+	}
+
+	blindSignature, err := NewBlindSignature(ctx, revealed, privKey, publicKeyWithGenerators)
+	if err != nil {
+		return nil, fmt.Errorf("create blind signature: %w", err)
+	}
+
+	return blindSignature.ToBytes()
+}
+
+// UnblindSignature converts a blind signature into a regular BBS+ signature using the signature blinding
+// created by BlindMessages.
+func (bbs *BBSG2Pub) UnblindSignature(blindSigBytes, blindingBytes []byte) ([]byte, error) {
+	blindSignature, err := ParseBlindSignature(blindSigBytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse blind signature: %w", err)
+	}
+
+	blinding, err := ParseSignatureBlinding(blindingBytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse signature blinding: %w", err)
+	}
+
+	return blindSignature.ToUnblinded(blinding).ToBytes()
+}
+
 // SignWithKey signs the one or more messages using BBS+ key pair.
 func (bbs *BBSG2Pub) SignWithKey(messages [][]byte, privKey *PrivateKey) ([]byte, error) {
 	var err error
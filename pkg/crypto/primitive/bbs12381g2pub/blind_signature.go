@@ -0,0 +1,241 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bbs12381g2pub
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	bls12381 "github.com/kilic/bls12-381"
+)
+
+// SignatureBlinding is a blinding factor the holder uses to commit to hidden messages. It is required
+// to unblind the signature issued over the commitment.
+type SignatureBlinding struct {
+	FR *bls12381.Fr
+}
+
+// ParseSignatureBlinding parses a SignatureBlinding from bytes.
+func ParseSignatureBlinding(bytes []byte) (*SignatureBlinding, error) {
+	if len(bytes) != frCompressedSize {
+		return nil, errors.New("invalid size of signature blinding")
+	}
+
+	return &SignatureBlinding{FR: parseFr(bytes)}, nil
+}
+
+// ToBytes converts SignatureBlinding to bytes.
+func (b *SignatureBlinding) ToBytes() []byte {
+	return b.FR.RedToBytes()
+}
+
+// BlindSignatureContext is a commitment to the hidden messages along with a proof of knowledge of the committed
+// messages. It is created by the holder and sent to the issuer to get a blind signature.
+type BlindSignatureContext struct {
+	commitment *bls12381.PointG1
+	challenge  *bls12381.Fr
+	proof      *ProofG1
+}
+
+// NewBlindSignatureContext commits to the hidden messages (indexed by the messages position in the signature)
+// and creates a proof of knowledge of them bound to the nonce provided by the issuer.
+func NewBlindSignatureContext(hiddenMessages map[int]*SignatureMessage, pubKey *PublicKeyWithGenerators,
+	nonce *ProofNonce) (*BlindSignatureContext, *SignatureBlinding, error) {
+	if len(hiddenMessages) == 0 {
+		return nil, nil, errors.New("no hidden messages to commit to")
+	}
+
+	indexes := make([]int, 0, len(hiddenMessages))
+
+	for i := range hiddenMessages {
+		if i < 0 || i >= pubKey.messagesCount {
+			return nil, nil, fmt.Errorf("hidden message index %d is out of range", i)
+		}
+
+		indexes = append(indexes, i)
+	}
+
+	sort.Ints(indexes)
+
+	blinding := createRandSignatureFr()
+
+	cb := newCommitmentBuilder(len(indexes) + 1)
+	cb.add(pubKey.h0, blinding)
+
+	committing := NewProverCommittingG1()
+	committing.Commit(pubKey.h0)
+
+	secrets := make([]*bls12381.Fr, 0, len(indexes)+1)
+	secrets = append(secrets, blinding)
+
+	for _, i := range indexes {
+		cb.add(pubKey.h[i], hiddenMessages[i].FR)
+		committing.Commit(pubKey.h[i])
+
+		secrets = append(secrets, bls12381.NewFr().Set(hiddenMessages[i].FR))
+	}
+
+	commitment := cb.build()
+	committed := committing.Finish()
+
+	challenge := blindChallenge(commitment, committed.commitment, nonce)
+
+	return &BlindSignatureContext{
+		commitment: commitment,
+		challenge:  challenge,
+		proof:      committed.GenerateProof(challenge, secrets),
+	}, &SignatureBlinding{FR: blinding}, nil
+}
+
+// Verify verifies the proof of knowledge of committed messages placed at hiddenIndexes.
+func (bsc *BlindSignatureContext) Verify(hiddenIndexes []int, pubKey *PublicKeyWithGenerators,
+	nonce *ProofNonce) error {
+	indexes := make([]int, len(hiddenIndexes))
+	copy(indexes, hiddenIndexes)
+	sort.Ints(indexes)
+
+	bases := make([]*bls12381.PointG1, 0, len(indexes)+1)
+	bases = append(bases, pubKey.h0)
+
+	for _, i := range indexes {
+		if i < 0 || i >= pubKey.messagesCount {
+			return fmt.Errorf("hidden message index %d is out of range", i)
+		}
+
+		bases = append(bases, pubKey.h[i])
+	}
+
+	if len(bases) != len(bsc.proof.responses) {
+		return errors.New("hidden messages count does not match blind signature context")
+	}
+
+	contribution := bsc.proof.getChallengeContribution(bases, bsc.commitment, bsc.challenge)
+
+	challenge := blindChallenge(bsc.commitment, contribution, nonce)
+	if !challenge.Equal(bsc.challenge) {
+		return errors.New("invalid proof of hidden messages")
+	}
+
+	return nil
+}
+
+// ToBytes converts BlindSignatureContext to bytes.
+func (bsc *BlindSignatureContext) ToBytes() []byte {
+	bytes := make([]byte, 0)
+
+	bytes = append(bytes, g1.ToCompressed(bsc.commitment)...)
+	bytes = append(bytes, frToRepr(bsc.challenge).ToBytes()...)
+
+	return append(bytes, bsc.proof.ToBytes()...)
+}
+
+// ParseBlindSignatureContext parses a BlindSignatureContext from bytes.
+func ParseBlindSignatureContext(bytes []byte) (*BlindSignatureContext, error) {
+	if len(bytes) < g1CompressedSize+frCompressedSize {
+		return nil, errors.New("invalid size of blind signature context")
+	}
+
+	commitment, err := g1.FromCompressed(bytes[:g1CompressedSize])
+	if err != nil {
+		return nil, fmt.Errorf("parse G1 point: %w", err)
+	}
+
+	offset := g1CompressedSize
+	challenge := parseFr(bytes[offset : offset+frCompressedSize])
+	offset += frCompressedSize
+
+	proof, err := ParseProofG1(bytes[offset:])
+	if err != nil {
+		return nil, fmt.Errorf("parse G1 proof: %w", err)
+	}
+
+	return &BlindSignatureContext{
+		commitment: commitment,
+		challenge:  challenge,
+		proof:      proof,
+	}, nil
+}
+
+// BlindSignature is a BBS+ signature over a commitment to the hidden messages and the revealed messages.
+// It becomes a regular Signature once unblinded by the holder.
+type BlindSignature struct {
+	A *bls12381.PointG1
+	E *bls12381.Fr
+	S *bls12381.Fr
+}
+
+// NewBlindSignature signs the revealed messages along with the committed ones. The caller must verify
+// the BlindSignatureContext before.
+func NewBlindSignature(ctx *BlindSignatureContext, revealedMessages map[int]*SignatureMessage,
+	privKey *PrivateKey, pubKey *PublicKeyWithGenerators) (*BlindSignature, error) {
+	const basesOffset = 3
+
+	cb := newCommitmentBuilder(len(revealedMessages) + basesOffset)
+
+	e, s := createRandSignatureFr(), createRandSignatureFr()
+
+	cb.add(g1.One(), bls12381.NewFr().RedOne())
+	cb.add(ctx.commitment, bls12381.NewFr().RedOne())
+	cb.add(pubKey.h0, s)
+
+	for i, m := range revealedMessages {
+		if i < 0 || i >= pubKey.messagesCount {
+			return nil, fmt.Errorf("revealed message index %d is out of range", i)
+		}
+
+		cb.add(pubKey.h[i], m.FR)
+	}
+
+	exp := bls12381.NewFr().Set(privKey.FR)
+	exp.Add(exp, e)
+	exp.Inverse(exp)
+
+	sig := g1.New()
+	g1.MulScalar(sig, cb.build(), frToRepr(exp))
+
+	return &BlindSignature{
+		A: sig,
+		E: e,
+		S: s,
+	}, nil
+}
+
+// ParseBlindSignature parses a BlindSignature from bytes.
+func ParseBlindSignature(bytes []byte) (*BlindSignature, error) {
+	sig, err := ParseSignature(bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BlindSignature{A: sig.A, E: sig.E, S: sig.S}, nil
+}
+
+// ToBytes converts BlindSignature to bytes.
+func (bs *BlindSignature) ToBytes() ([]byte, error) {
+	return (&Signature{A: bs.A, E: bs.E, S: bs.S}).ToBytes()
+}
+
+// ToUnblinded converts BlindSignature into a regular Signature using the blinding the commitment was created with.
+func (bs *BlindSignature) ToUnblinded(blinding *SignatureBlinding) *Signature {
+	s := bls12381.NewFr().Set(bs.S)
+	s.Add(s, blinding.FR)
+
+	return &Signature{
+		A: bs.A,
+		E: bs.E,
+		S: s,
+	}
+}
+
+func blindChallenge(commitment, proofCommitment *bls12381.PointG1, nonce *ProofNonce) *bls12381.Fr {
+	challengeBytes := g1.ToUncompressed(commitment)
+	challengeBytes = append(challengeBytes, g1.ToUncompressed(proofCommitment)...)
+	challengeBytes = append(challengeBytes, nonce.ToBytes()...)
+
+	return frFromOKM(challengeBytes)
+}
@@ -0,0 +1,124 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bbs12381g2pub_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/primitive/bbs12381g2pub"
+)
+
+func TestBBSG2Pub_BlindSign(t *testing.T) {
+	pubKey, privKey, err := generateKeyPairRandom()
+	require.NoError(t, err)
+
+	privKeyBytes, err := privKey.Marshal()
+	require.NoError(t, err)
+
+	pubKeyBytes, err := pubKey.Marshal()
+	require.NoError(t, err)
+
+	bls := bbs12381g2pub.New()
+
+	holderSecret := []byte("holder secret")
+	messagesBytes := [][]byte{
+		[]byte("message1"),
+		[]byte("message2"),
+		[]byte("message3"),
+		holderSecret,
+	}
+	revealedIndexes := []int{0, 1, 2}
+	hiddenIndexes := []int{3}
+	nonce := []byte("issuer nonce")
+
+	blindContext, blinding, err := bls.BlindMessages([][]byte{holderSecret}, hiddenIndexes, len(messagesBytes),
+		nonce, pubKeyBytes)
+	require.NoError(t, err)
+	require.NotEmpty(t, blindContext)
+	require.NotEmpty(t, blinding)
+
+	blindSignature, err := bls.BlindSign(messagesBytes[:3], revealedIndexes, len(messagesBytes), blindContext,
+		nonce, privKeyBytes)
+	require.NoError(t, err)
+
+	// blind signature is not valid until unblinded by the holder
+	require.Error(t, bls.Verify(messagesBytes, blindSignature, pubKeyBytes))
+
+	signature, err := bls.UnblindSignature(blindSignature, blinding)
+	require.NoError(t, err)
+	require.NoError(t, bls.Verify(messagesBytes, signature, pubKeyBytes))
+
+	t.Run("holder secret stays hidden in derived proof", func(t *testing.T) {
+		proofNonce := []byte("verifier nonce")
+
+		proof, err := bls.DeriveProof(messagesBytes, signature, proofNonce, pubKeyBytes, []int{0, 2})
+		require.NoError(t, err)
+
+		require.NoError(t, bls.VerifyProof([][]byte{messagesBytes[0], messagesBytes[2]}, proof, proofNonce,
+			pubKeyBytes))
+	})
+
+	t.Run("invalid nonce", func(t *testing.T) {
+		_, err := bls.BlindSign(messagesBytes[:3], revealedIndexes, len(messagesBytes), blindContext,
+			[]byte("other nonce"), privKeyBytes)
+		require.EqualError(t, err, "verify blind signature context: invalid proof of hidden messages")
+	})
+
+	t.Run("hidden messages do not match commitment", func(t *testing.T) {
+		_, err := bls.BlindSign(messagesBytes[:2], revealedIndexes[:2], len(messagesBytes), blindContext,
+			nonce, privKeyBytes)
+		require.EqualError(t, err,
+			"verify blind signature context: hidden messages count does not match blind signature context")
+
+		_, err = bls.BlindSign([][]byte{messagesBytes[0], messagesBytes[1], messagesBytes[3]}, []int{0, 1, 3},
+			len(messagesBytes), blindContext, nonce, privKeyBytes)
+		require.EqualError(t, err, "verify blind signature context: invalid proof of hidden messages")
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		_, _, err := bls.BlindMessages([][]byte{holderSecret}, []int{3, 2}, len(messagesBytes), nonce, pubKeyBytes)
+		require.EqualError(t, err, "hidden messages and indexes count mismatch")
+
+		_, _, err = bls.BlindMessages([][]byte{holderSecret}, []int{4}, len(messagesBytes), nonce, pubKeyBytes)
+		require.EqualError(t, err, "create blind signature context: hidden message index 4 is out of range")
+
+		_, _, err = bls.BlindMessages(nil, nil, len(messagesBytes), nonce, pubKeyBytes)
+		require.EqualError(t, err, "create blind signature context: no hidden messages to commit to")
+
+		_, _, err = bls.BlindMessages([][]byte{holderSecret, holderSecret}, []int{3, 3}, len(messagesBytes),
+			nonce, pubKeyBytes)
+		require.EqualError(t, err, "duplicated hidden message index 3")
+
+		_, _, err = bls.BlindMessages([][]byte{holderSecret}, hiddenIndexes, len(messagesBytes), nonce,
+			[]byte("invalid"))
+		require.EqualError(t, err, "parse public key: invalid size of public key")
+
+		_, err = bls.BlindSign(messagesBytes[:3], revealedIndexes[:2], len(messagesBytes), blindContext,
+			nonce, privKeyBytes)
+		require.EqualError(t, err, "revealed messages and indexes count mismatch")
+
+		_, err = bls.BlindSign(messagesBytes[:3], []int{0, 1, 5}, len(messagesBytes), blindContext,
+			nonce, privKeyBytes)
+		require.EqualError(t, err, "revealed message index 5 is out of range")
+
+		_, err = bls.BlindSign(messagesBytes[:3], revealedIndexes, len(messagesBytes), blindContext,
+			nonce, []byte("invalid"))
+		require.EqualError(t, err, "unmarshal private key: invalid size of private key")
+
+		_, err = bls.BlindSign(messagesBytes[:3], revealedIndexes, len(messagesBytes), []byte("invalid"),
+			nonce, privKeyBytes)
+		require.EqualError(t, err, "parse blind signature context: invalid size of blind signature context")
+
+		_, err = bls.UnblindSignature([]byte("invalid"), blinding)
+		require.EqualError(t, err, "parse blind signature: invalid size of signature")
+
+		_, err = bls.UnblindSignature(blindSignature, []byte("invalid"))
+		require.EqualError(t, err, "parse signature blinding: invalid size of signature blinding")
+	})
+}
@@ -62,6 +62,11 @@ func (sp *PoKOfSignatureProof) Verify(challenge *bls12381.Fr, pubKey *PublicKeyW
 		return errors.New("bad signature")
 	}
 
+	return sp.verifyVCProofs(challenge, pubKey, revealedMessages, messages)
+}
+
+func (sp *PoKOfSignatureProof) verifyVCProofs(challenge *bls12381.Fr, pubKey *PublicKeyWithGenerators,
+	revealedMessages map[int]*SignatureMessage, messages []*SignatureMessage) error {
 	err := sp.verifyVC1Proof(challenge, pubKey)
 	if err != nil {
 		return err
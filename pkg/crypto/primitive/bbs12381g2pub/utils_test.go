@@ -27,6 +27,19 @@ func Test_pokPayload(t *testing.T) {
 	payloadParsed, err = parsePoKPayload([]byte{})
 	require.Error(t, err)
 	require.Nil(t, payloadParsed)
+
+	// parsing must not modify the input
+	payload = newPoKPayload(20, []int{0, 2, 17})
+
+	bytes, err = payload.toBytes()
+	require.NoError(t, err)
+
+	bytesCopy := append([]byte{}, bytes...)
+
+	payloadParsed, err = parsePoKPayload(bytes)
+	require.NoError(t, err)
+	require.Equal(t, payload, payloadParsed)
+	require.Equal(t, bytesCopy, bytes)
 }
 
 func Test_pokPayloadFail(t *testing.T) {
@@ -23,6 +23,7 @@ import (
 	"golang.org/x/crypto/chacha20poly1305"
 
 	cryptoapi "github.com/hyperledger/aries-framework-go/pkg/crypto"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/primitive/bbs12381g2pub"
 	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/primitive/bbs"
 )
 
@@ -301,6 +302,33 @@ func (t *Crypto) SignMulti(messages [][]byte, signerKH interface{}) ([]byte, err
 	return s, nil
 }
 
+// BlindSignMulti will verify the holder's commitment to hidden messages (created with
+// bbs12381g2pub.BBSG2Pub.BlindMessages) and create a BBS+ blind signature of it along with revealedMessages using
+// the signer's private key in signerKH handle. The holder unblinds the signature with
+// bbs12381g2pub.BBSG2Pub.UnblindSignature to get a regular BBS+ signature of all messages.
+// returns:
+// 		blind signature in []byte
+//		error in case of errors
+func (t *Crypto) BlindSignMulti(revealedMessages [][]byte, revealedIndexes []int, messagesCount int,
+	blindContext, nonce []byte, signerKH interface{}) ([]byte, error) {
+	keyHandle, ok := signerKH.(*keyset.Handle)
+	if !ok {
+		return nil, errBadKeyHandleFormat
+	}
+
+	signer, err := bbs.NewSigner(keyHandle)
+	if err != nil {
+		return nil, fmt.Errorf("create new BBS+ signer: %w", err)
+	}
+
+	s, err := signer.BlindSign(revealedMessages, revealedIndexes, messagesCount, blindContext, nonce)
+	if err != nil {
+		return nil, fmt.Errorf("BBS+ blind sign msg: %w", err)
+	}
+
+	return s, nil
+}
+
 // VerifyMulti will BBS+ verify a signature of messages against the signer's public key in signerPubKH handle.
 // returns:
 // 		error in case of errors or nil if signature verification was successful
@@ -346,6 +374,101 @@ func (t *Crypto) VerifyProof(revealedMessages [][]byte, proof, nonce []byte, sig
 	return err
 }
 
+// MultiSignature is a BBS+ signature of messages to be verified by VerifyMultiBatch.
+type MultiSignature struct {
+	Messages    [][]byte
+	Signature   []byte
+	SignerPubKH interface{}
+}
+
+// VerifyMultiBatch will BBS+ verify several signatures at once. It is cheaper than calling VerifyMulti for each
+// signature as the pairings of all the signatures are checked together (see bbs12381g2pub.BBSG2Pub.BatchVerify).
+// Signatures made with a key other than the primary key of the signer's public key handle are verified separately.
+// returns:
+// 		error in case of errors or nil if verification of all signatures was successful
+func (t *Crypto) VerifyMultiBatch(signatures []*MultiSignature) error {
+	entries := make([]*bbs12381g2pub.SignatureBatchEntry, 0, len(signatures))
+
+	for i, s := range signatures {
+		keyHandle, ok := s.SignerPubKH.(*keyset.Handle)
+		if !ok {
+			return errBadKeyHandleFormat
+		}
+
+		entry, err := bbs.NewSignatureBatchEntry(keyHandle, s.Messages, s.Signature)
+		if err != nil {
+			if err = t.VerifyMulti(s.Messages, s.Signature, keyHandle); err != nil {
+				return fmt.Errorf("signature %d: %w", i, err)
+			}
+
+			continue
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if len(entries) == 0 || bbs12381g2pub.New().BatchVerify(entries) == nil {
+		return nil
+	}
+
+	// find out the invalid signature
+	for i, s := range signatures {
+		if err := t.VerifyMulti(s.Messages, s.Signature, s.SignerPubKH); err != nil {
+			return fmt.Errorf("signature %d: %w", i, err)
+		}
+	}
+
+	return errors.New("BBS+ verify batch: invalid signatures")
+}
+
+// SignatureProof is a BBS+ signature proof of revealed messages to be verified by VerifyProofBatch.
+type SignatureProof struct {
+	RevealedMessages [][]byte
+	Proof            []byte
+	Nonce            []byte
+	SignerPubKH      interface{}
+}
+
+// VerifyProofBatch will verify several BBS+ signature proofs at once. It is cheaper than calling VerifyProof for
+// each proof as the pairings of all the proofs are checked together (see bbs12381g2pub.BBSG2Pub.BatchVerifyProof).
+// Proofs derived with a key other than the primary key of the signer's public key handle are verified separately.
+// returns:
+// 		error in case of errors or nil if verification of all signature proofs was successful
+func (t *Crypto) VerifyProofBatch(proofs []*SignatureProof) error {
+	entries := make([]*bbs12381g2pub.ProofBatchEntry, 0, len(proofs))
+
+	for i, p := range proofs {
+		keyHandle, ok := p.SignerPubKH.(*keyset.Handle)
+		if !ok {
+			return errBadKeyHandleFormat
+		}
+
+		entry, err := bbs.NewProofBatchEntry(keyHandle, p.RevealedMessages, p.Proof, p.Nonce)
+		if err != nil {
+			if err = t.VerifyProof(p.RevealedMessages, p.Proof, p.Nonce, keyHandle); err != nil {
+				return fmt.Errorf("signature proof %d: %w", i, err)
+			}
+
+			continue
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if len(entries) == 0 || bbs12381g2pub.New().BatchVerifyProof(entries) == nil {
+		return nil
+	}
+
+	// find out the invalid proof
+	for i, p := range proofs {
+		if err := t.VerifyProof(p.RevealedMessages, p.Proof, p.Nonce, p.SignerPubKH); err != nil {
+			return fmt.Errorf("signature proof %d: %w", i, err)
+		}
+	}
+
+	return errors.New("BBS+ verify batch: invalid signature proofs")
+}
+
 // DeriveProof will create a BBS+ signature proof for a list of revealed messages using BBS signature
 // (can be built using a Signer's SignMulti() call) and the signer's public key in signerPubKH handle.
 // returns:
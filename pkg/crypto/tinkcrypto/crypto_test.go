@@ -7,10 +7,12 @@ SPDX-License-Identifier: Apache-2.0
 package tinkcrypto
 
 import (
+	"bytes"
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"crypto/rand"
 	"math/big"
+	"strconv"
 	"testing"
 
 	"github.com/google/tink/go/aead"
@@ -25,10 +27,12 @@ import (
 	chacha "golang.org/x/crypto/chacha20poly1305"
 
 	"github.com/hyperledger/aries-framework-go/pkg/crypto"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/primitive/bbs12381g2pub"
 	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/primitive/bbs"
 	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/primitive/composite/ecdh"
 	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/primitive/composite/keyio"
 	ecdhpb "github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/primitive/proto/ecdh_aead_go_proto"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/localkms"
 )
 
 const testMessage = "test message"
@@ -727,3 +731,113 @@ func TestBBSCrypto_SignVerify_DeriveProofVerifyProof(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
+
+func TestBBSCrypto_BlindSignMulti(t *testing.T) {
+	c := Crypto{}
+	msg := [][]byte{[]byte(testMessage + "0"), []byte(testMessage + "1"), []byte(testMessage + "2")}
+	holderSecret := []byte("holder secret")
+	nonce := []byte("issuer nonce")
+
+	kh, err := keyset.NewHandle(bbs.BLS12381G2KeyTemplate())
+	require.NoError(t, err)
+
+	pubKH, err := kh.Public()
+	require.NoError(t, err)
+
+	pubKeyBuf := new(bytes.Buffer)
+	require.NoError(t, pubKH.WriteWithNoSecrets(localkms.NewWriter(pubKeyBuf)))
+
+	bls := bbs12381g2pub.New()
+
+	blindContext, blinding, err := bls.BlindMessages([][]byte{holderSecret}, []int{3}, 4, nonce, pubKeyBuf.Bytes())
+	require.NoError(t, err)
+
+	blindSignature, err := c.BlindSignMulti(msg, []int{0, 1, 2}, 4, blindContext, nonce, kh)
+	require.NoError(t, err)
+
+	signature, err := bls.UnblindSignature(blindSignature, blinding)
+	require.NoError(t, err)
+
+	require.NoError(t, c.VerifyMulti(append(msg, holderSecret), signature, pubKH))
+
+	_, err = c.BlindSignMulti(msg, []int{0, 1, 2}, 4, blindContext, nonce, "bad key type")
+	require.EqualError(t, err, errBadKeyHandleFormat.Error())
+
+	_, err = c.BlindSignMulti(msg, []int{0, 1, 2}, 4, blindContext, []byte("other nonce"), kh)
+	require.EqualError(t, err, "BBS+ blind sign msg: verify blind signature context: invalid proof of hidden messages")
+
+	_, err = c.BlindSignMulti(msg, []int{0, 1, 2}, 4, blindContext, nonce, pubKH)
+	require.Error(t, err)
+}
+
+func TestBBSCrypto_VerifyMultiBatch_VerifyProofBatch(t *testing.T) {
+	c := Crypto{}
+	nonce := []byte("nonce")
+	revealedIndexes := []int{0, 2}
+
+	var (
+		signatures []*MultiSignature
+		proofs     []*SignatureProof
+	)
+
+	for i := 0; i < 3; i++ {
+		msg := [][]byte{[]byte(testMessage + "0"), []byte(testMessage + strconv.Itoa(i)), []byte(testMessage + "2")}
+
+		kh, err := keyset.NewHandle(bbs.BLS12381G2KeyTemplate())
+		require.NoError(t, err)
+
+		pubKH, err := kh.Public()
+		require.NoError(t, err)
+
+		s, err := c.SignMulti(msg, kh)
+		require.NoError(t, err)
+
+		proof, err := c.DeriveProof(msg, s, nonce, revealedIndexes, pubKH)
+		require.NoError(t, err)
+
+		signatures = append(signatures, &MultiSignature{Messages: msg, Signature: s, SignerPubKH: pubKH})
+		proofs = append(proofs, &SignatureProof{
+			RevealedMessages: [][]byte{msg[0], msg[2]},
+			Proof:            proof,
+			Nonce:            nonce,
+			SignerPubKH:      pubKH,
+		})
+	}
+
+	t.Run("test with BBS+ signatures", func(t *testing.T) {
+		require.NoError(t, c.VerifyMultiBatch(signatures))
+
+		invalid := []*MultiSignature{signatures[0], {
+			Messages:    signatures[1].Messages,
+			Signature:   signatures[1].Signature,
+			SignerPubKH: signatures[2].SignerPubKH,
+		}}
+
+		err := c.VerifyMultiBatch(invalid)
+		require.EqualError(t, err, "signature 1: BBS+ verify msg: bbs_verifier_factory: invalid signature")
+
+		invalid[1] = &MultiSignature{SignerPubKH: "bad key type"}
+
+		err = c.VerifyMultiBatch(invalid)
+		require.EqualError(t, err, errBadKeyHandleFormat.Error())
+	})
+
+	t.Run("test with BBS+ proofs", func(t *testing.T) {
+		require.NoError(t, c.VerifyProofBatch(proofs))
+
+		invalid := []*SignatureProof{proofs[0], {
+			RevealedMessages: proofs[1].RevealedMessages,
+			Proof:            proofs[1].Proof,
+			Nonce:            []byte("other nonce"),
+			SignerPubKH:      proofs[1].SignerPubKH,
+		}}
+
+		err := c.VerifyProofBatch(invalid)
+		require.EqualError(t, err, "signature proof 1: verify proof msg: bbs_verifier_factory: invalid signature proof")
+
+		invalid[1] = &SignatureProof{SignerPubKH: "bad key type"}
+
+		err = c.VerifyProofBatch(invalid)
+		require.EqualError(t, err, errBadKeyHandleFormat.Error())
+	})
+}
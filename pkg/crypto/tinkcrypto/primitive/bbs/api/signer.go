@@ -14,4 +14,11 @@ type Signer interface {
 	// 		signature in []byte
 	//		error in case of errors
 	Sign(messages [][]byte) ([]byte, error)
+	// BlindSign will verify the holder's commitment to hidden messages found in blindContext and create a blind
+	// signature of the commitment and revealed messages using the signer's private key.
+	// returns:
+	// 		blind signature in []byte
+	//		error in case of errors
+	BlindSign(revealedMessages [][]byte, revealedIndexes []int, messagesCount int,
+		blindContext, nonce []byte) ([]byte, error)
 }
@@ -0,0 +1,94 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bbs
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/google/tink/go/core/cryptofmt"
+	"github.com/google/tink/go/core/primitiveset"
+	"github.com/google/tink/go/keyset"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/primitive/bbs12381g2pub"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/primitive/bbs/subtle"
+)
+
+var errNotPrimarySignature = errors.New("bbs_batch_entry: signature is not made with the primary key")
+
+// NewSignatureBatchEntry creates an entry for bbs12381g2pub.BBSG2Pub.BatchVerify of the signature made with
+// the primary key of the given public keyset handle. Signatures made with other keys of the keyset are
+// not supported and must be verified with the Verifier primitive.
+func NewSignatureBatchEntry(h *keyset.Handle, messages [][]byte,
+	signature []byte) (*bbs12381g2pub.SignatureBatchEntry, error) {
+	primary, pubKey, err := primaryPublicKey(h)
+	if err != nil {
+		return nil, err
+	}
+
+	signatureNoPrefix, err := trimPrimaryPrefix(primary, signature)
+	if err != nil {
+		return nil, err
+	}
+
+	return &bbs12381g2pub.SignatureBatchEntry{
+		Messages:  buildPrefixedMsgToSign(messages, primary),
+		Signature: signatureNoPrefix,
+		PublicKey: pubKey,
+	}, nil
+}
+
+// NewProofBatchEntry creates an entry for bbs12381g2pub.BBSG2Pub.BatchVerifyProof of the signature proof derived
+// with the primary key of the given public keyset handle. Proofs derived with other keys of the keyset are
+// not supported and must be verified with the Verifier primitive.
+func NewProofBatchEntry(h *keyset.Handle, messages [][]byte, proof,
+	nonce []byte) (*bbs12381g2pub.ProofBatchEntry, error) {
+	primary, pubKey, err := primaryPublicKey(h)
+	if err != nil {
+		return nil, err
+	}
+
+	proofNoPrefix, err := trimPrimaryPrefix(primary, proof)
+	if err != nil {
+		return nil, err
+	}
+
+	return &bbs12381g2pub.ProofBatchEntry{
+		Messages:  buildPrefixedMsgToSign(messages, primary),
+		Proof:     proofNoPrefix,
+		Nonce:     nonce,
+		PublicKey: pubKey,
+	}, nil
+}
+
+func primaryPublicKey(h *keyset.Handle) (*primitiveset.Entry, []byte, error) {
+	ps, err := h.Primitives()
+	if err != nil {
+		return nil, nil, fmt.Errorf("bbs_batch_entry: cannot obtain primitive set: %w", err)
+	}
+
+	verifier, ok := (ps.Primary.Primitive).(*subtle.BLS12381G2Verifier)
+	if !ok {
+		return nil, nil, errInvalidPrimitive
+	}
+
+	return ps.Primary, verifier.PublicKey(), nil
+}
+
+func trimPrimaryPrefix(primary *primitiveset.Entry, signature []byte) ([]byte, error) {
+	if primary.PrefixType == tinkpb.OutputPrefixType_RAW {
+		return signature, nil
+	}
+
+	if len(signature) < cryptofmt.NonRawPrefixSize || !bytes.HasPrefix(signature, []byte(primary.Prefix)) {
+		return nil, errNotPrimarySignature
+	}
+
+	return signature[cryptofmt.NonRawPrefixSize:], nil
+}
@@ -0,0 +1,89 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bbs
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/keyset"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+	"github.com/google/tink/go/testkeyset"
+	"github.com/google/tink/go/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/primitive/bbs12381g2pub"
+)
+
+func TestBatchEntries(t *testing.T) {
+	messages := [][]byte{[]byte("message1"), []byte("message2"), []byte("message3")}
+	nonce := []byte("nonce")
+
+	for _, prefix := range []tinkpb.OutputPrefixType{
+		tinkpb.OutputPrefixType_RAW,
+		tinkpb.OutputPrefixType_TINK,
+		tinkpb.OutputPrefixType_LEGACY,
+	} {
+		prefixType := prefix
+
+		t.Run(prefixType.String(), func(t *testing.T) {
+			khPriv := newPrivateKeysetHandle(t, prefixType)
+
+			khPub, err := khPriv.Public()
+			require.NoError(t, err)
+
+			signer, err := NewSigner(khPriv)
+			require.NoError(t, err)
+
+			verifier, err := NewVerifier(khPub)
+			require.NoError(t, err)
+
+			signature, err := signer.Sign(messages)
+			require.NoError(t, err)
+
+			proof, err := verifier.DeriveProof(messages, signature, nonce, []int{0, 2})
+			require.NoError(t, err)
+
+			sigEntry, err := NewSignatureBatchEntry(khPub, messages, signature)
+			require.NoError(t, err)
+
+			proofEntry, err := NewProofBatchEntry(khPub, [][]byte{messages[0], messages[2]}, proof, nonce)
+			require.NoError(t, err)
+
+			bbs := bbs12381g2pub.New()
+
+			require.NoError(t, bbs.BatchVerify([]*bbs12381g2pub.SignatureBatchEntry{sigEntry, sigEntry}))
+			require.NoError(t, bbs.BatchVerifyProof([]*bbs12381g2pub.ProofBatchEntry{proofEntry, proofEntry}))
+
+			if prefixType != tinkpb.OutputPrefixType_RAW {
+				_, err = NewSignatureBatchEntry(khPub, messages, signature[1:])
+				require.EqualError(t, err, errNotPrimarySignature.Error())
+
+				_, err = NewProofBatchEntry(khPub, messages, []byte("xx"), nonce)
+				require.EqualError(t, err, errNotPrimarySignature.Error())
+			}
+
+			// private keyset handle is not supported
+			_, err = NewSignatureBatchEntry(khPriv, messages, signature)
+			require.EqualError(t, err, errInvalidPrimitive.Error())
+		})
+	}
+}
+
+func newPrivateKeysetHandle(t *testing.T, prefix tinkpb.OutputPrefixType) *keyset.Handle {
+	serializedPrivKey, err := proto.Marshal(generatePrivateKeyProto(t))
+	require.NoError(t, err)
+
+	privKey := testutil.NewKey(
+		testutil.NewKeyData(bbsSignerKeyTypeURL, serializedPrivKey, tinkpb.KeyData_ASYMMETRIC_PRIVATE),
+		tinkpb.KeyStatusType_ENABLED, 5, prefix)
+
+	kh, err := testkeyset.NewHandle(testutil.NewKeyset(privKey.KeyId, []*tinkpb.Keyset_Key{privKey}))
+	require.NoError(t, err)
+
+	return kh
+}
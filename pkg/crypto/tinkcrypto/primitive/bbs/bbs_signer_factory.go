@@ -86,3 +86,31 @@ func (ws *wrappedSigner) Sign(messages [][]byte) ([]byte, error) {
 
 	return ret, nil
 }
+
+// BlindSign creates a blind signature of the holder's commitment and revealed messages and returns it concatenated
+// with the identifier of the primary primitive. Keys with LEGACY output prefix are not supported as the extra
+// message they sign would break the holder's commitment.
+func (ws *wrappedSigner) BlindSign(revealedMessages [][]byte, revealedIndexes []int, messagesCount int,
+	blindContext, nonce []byte) ([]byte, error) {
+	primary := ws.ps.Primary
+
+	signer, ok := (primary.Primitive).(bbsapi.Signer)
+	if !ok {
+		return nil, fmt.Errorf("bbs_signer_factory: not a BBS Signer primitive")
+	}
+
+	if primary.PrefixType == tinkpb.OutputPrefixType_LEGACY {
+		return nil, fmt.Errorf("bbs_signer_factory: blind signing is not supported for LEGACY output prefix")
+	}
+
+	signature, err := signer.BlindSign(revealedMessages, revealedIndexes, messagesCount, blindContext, nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]byte, 0, len(primary.Prefix)+len(signature))
+	ret = append(ret, primary.Prefix...)
+	ret = append(ret, signature...)
+
+	return ret, nil
+}
@@ -34,3 +34,14 @@ func NewBLS12381G2Signer(privateKey []byte) *BLS12381G2Signer {
 func (s *BLS12381G2Signer) Sign(messages [][]byte) ([]byte, error) {
 	return s.bbsPrimitive.Sign(messages, s.privateKeyBytes)
 }
+
+// BlindSign will verify the holder's commitment to hidden messages found in blindContext and create a blind
+// signature of the commitment and revealed messages using the signer's private key.
+// returns:
+// 		blind signature in []byte
+//		error in case of errors
+func (s *BLS12381G2Signer) BlindSign(revealedMessages [][]byte, revealedIndexes []int, messagesCount int,
+	blindContext, nonce []byte) ([]byte, error) {
+	return s.bbsPrimitive.BlindSign(revealedMessages, revealedIndexes, messagesCount, blindContext, nonce,
+		s.privateKeyBytes)
+}
@@ -26,6 +26,11 @@ func NewBLS12381G2Verifier(signerPublicKey []byte) *BLS12381G2Verifier {
 	}
 }
 
+// PublicKey returns the signer's public key bytes the verifier was created with.
+func (v *BLS12381G2Verifier) PublicKey() []byte {
+	return v.signerPubKeyBytes
+}
+
 // Verify will verify an aggregated signature of one or more messages against the signer's public key.
 // returns:
 // 		error in case of errors or nil if signature verification was successful
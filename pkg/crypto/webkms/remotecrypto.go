@@ -62,6 +62,14 @@ type signMultiReq struct {
 	Messages []string `json:"messages,omitempty"`
 }
 
+type blindSignMultiReq struct {
+	Messages        []string `json:"messages,omitempty"`
+	RevealedIndexes []int    `json:"revealedIndexes,omitempty"`
+	MessagesCount   int      `json:"messagesCount,omitempty"`
+	BlindContext    string   `json:"blindContext,omitempty"`
+	Nonce           string   `json:"nonce,omitempty"`
+}
+
 type deriveProofReq struct {
 	Messages        []string `json:"messages,omitempty"`
 	Signature       string   `json:"signature,omitempty"`
@@ -131,10 +139,11 @@ const (
 	unwrapURI     = "/unwrap"
 
 	// multi signatures/selective disclosure crypto (eg BBS+) endpoints.
-	signMultiURI   = "/signmulti"
-	verifyMultiURI = "/verifymulti"
-	deriveProofURI = "/deriveproof"
-	verifyProofURI = "/verifyproof"
+	signMultiURI      = "/signmulti"
+	blindSignMultiURI = "/blindsignmulti"
+	verifyMultiURI    = "/verifymulti"
+	deriveProofURI    = "/deriveproof"
+	verifyProofURI    = "/verifyproof"
 )
 
 // New creates a new remoteCrypto instance using http client connecting to keystoreURL.
@@ -680,6 +689,65 @@ func (r *RemoteCrypto) SignMulti(messages [][]byte, signerKeyURL interface{}) ([
 	return keyBytes, nil
 }
 
+// BlindSignMulti will verify the holder's commitment in blindContext and create a BBS+ blind signature of
+// revealedMessages along with the hidden ones using the signer's private key handle found at signerKeyURL.
+// returns:
+// 		blind signature in []byte
+//		error in case of errors
+func (r *RemoteCrypto) BlindSignMulti(revealedMessages [][]byte, revealedIndexes []int, messagesCount int,
+	blindContext, nonce []byte, signerKeyURL interface{}) ([]byte, error) {
+	startSign := time.Now()
+	destination := fmt.Sprintf("%s", signerKeyURL) + blindSignMultiURI
+
+	var encMessages []string
+	for _, msg := range revealedMessages {
+		encMessages = append(encMessages, base64.URLEncoding.EncodeToString(msg))
+	}
+
+	sReq := blindSignMultiReq{
+		Messages:        encMessages,
+		RevealedIndexes: revealedIndexes,
+		MessagesCount:   messagesCount,
+		BlindContext:    base64.URLEncoding.EncodeToString(blindContext),
+		Nonce:           base64.URLEncoding.EncodeToString(nonce),
+	}
+
+	httpReqBytes, err := r.marshalFunc(sReq)
+	if err != nil {
+		return nil, fmt.Errorf("marshal signature request for BBS+ BlindSign failed [%s, %w]", destination, err)
+	}
+
+	resp, err := r.postHTTPRequest(destination, httpReqBytes)
+	if err != nil {
+		return nil, fmt.Errorf("posting BBS+ BlindSign message failed [%s, %w]", destination, err)
+	}
+
+	// handle response
+	defer closeResponseBody(resp.Body, logger, "BBS+ BlindSign")
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read signature response for BBS+ BlindSign failed [%s, %w]", destination, err)
+	}
+
+	httpResp := &signResp{}
+
+	err = r.unmarshalFunc(respBody, httpResp)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal signature for BBS+ BlindSign failed [%s, %w]", destination, err)
+	}
+
+	signature, err := base64.URLEncoding.DecodeString(httpResp.Signature)
+	if err != nil {
+		return nil, err
+	}
+
+	// TODO switch to Debug once perf testing with remote server is done.
+	logger.Infof("overall BBS+ BlindSign duration: %s", time.Since(startSign))
+
+	return signature, nil
+}
+
 // VerifyMulti will BBS+ verify a signature of messages against the signer's public key handle found at signerKeyURL.
 // returns:
 // 		error in case of errors or nil if signature verification was successful
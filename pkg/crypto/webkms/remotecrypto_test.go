@@ -35,6 +35,7 @@ import (
 	"golang.org/x/crypto/chacha20poly1305"
 
 	"github.com/hyperledger/aries-framework-go/pkg/crypto"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/primitive/bbs12381g2pub"
 	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto"
 	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/primitive/bbs"
 	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/primitive/composite/ecdh"
@@ -1166,6 +1167,77 @@ func TestBBSSignVerify_DeriveProofVerifyProof(t *testing.T) {
 	})
 }
 
+func TestBBSBlindSignMulti(t *testing.T) {
+	kh, err := keyset.NewHandle(bbs.BLS12381G2KeyTemplate())
+	require.NoError(t, err)
+
+	hf := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err = processBBSPOSTRequest(w, r, kh)
+		require.NoError(t, err)
+	})
+
+	server, url, client := CreateMockHTTPServerAndClient(t, hf)
+
+	defer func() {
+		e := server.Close()
+		require.NoError(t, e)
+	}()
+
+	defaultKeystoreURL := fmt.Sprintf("%s/%s", strings.ReplaceAll(webkmsimpl.KeystoreEndpoint,
+		"{serverEndpoint}", url), defaultKeyStoreID)
+	defaultKeyURL := defaultKeystoreURL + "/keys/" + defaultKID
+	rCrypto := New(defaultKeystoreURL, client)
+	msg := [][]byte{[]byte("lorem ipsum"), []byte("dolor sit amet,")}
+	holderSecret := []byte("holder secret")
+	nonce := []byte("issuer nonce")
+
+	pubKH, err := kh.Public()
+	require.NoError(t, err)
+
+	pubKeyBuf := new(bytes.Buffer)
+	require.NoError(t, pubKH.WriteWithNoSecrets(localkms.NewWriter(pubKeyBuf)))
+
+	bls := bbs12381g2pub.New()
+
+	blindContext, blinding, err := bls.BlindMessages([][]byte{holderSecret}, []int{2}, 3, nonce, pubKeyBuf.Bytes())
+	require.NoError(t, err)
+
+	blindSignature, err := rCrypto.BlindSignMulti(msg, []int{0, 1}, 3, blindContext, nonce, defaultKeyURL)
+	require.NoError(t, err)
+
+	sig, err := bls.UnblindSignature(blindSignature, blinding)
+	require.NoError(t, err)
+
+	require.NoError(t, rCrypto.VerifyMulti(append(msg, holderSecret), sig, defaultKeyURL))
+
+	t.Run("BBS+ BlindSign Post request failure", func(t *testing.T) {
+		tmpCrypto := New(defaultKeystoreURL, &http.Client{})
+
+		_, err = tmpCrypto.BlindSignMulti(nil, nil, 0, nil, nil, defaultKeyURL)
+		require.EqualError(t, err, fmt.Errorf("posting BBS+ BlindSign message failed [%s, Post \"%s\": x509: "+
+			"certificate signed by unknown authority]", defaultKeyURL+blindSignMultiURI,
+			defaultKeyURL+blindSignMultiURI).Error())
+	})
+
+	t.Run("BBS+ BlindSign json marshal failure", func(t *testing.T) {
+		remoteCrypto2 := New(defaultKeystoreURL, client)
+
+		remoteCrypto2.marshalFunc = failingMarshal
+		_, err = remoteCrypto2.BlindSignMulti(msg, []int{0, 1}, 3, blindContext, nonce, defaultKeyURL)
+		require.EqualError(t, err, fmt.Errorf("marshal signature request for BBS+ BlindSign failed [%s, %w]",
+			defaultKeyURL+blindSignMultiURI, errFailingMarshal).Error())
+	})
+
+	t.Run("BBS+ BlindSign json unmarshal failure", func(t *testing.T) {
+		remoteCrypto2 := New(defaultKeystoreURL, client)
+
+		remoteCrypto2.unmarshalFunc = failingUnmarshal
+		_, err = remoteCrypto2.BlindSignMulti(msg, []int{0, 1}, 3, blindContext, nonce, defaultKeyURL)
+		require.EqualError(t, err, fmt.Errorf("unmarshal signature for BBS+ BlindSign failed [%s, %w]",
+			defaultKeyURL+blindSignMultiURI, errFailingUnmarshal).Error())
+	})
+}
+
 // nolint:gocyclo
 func processBBSPOSTRequest(w http.ResponseWriter, r *http.Request, sigKH *keyset.Handle) error {
 	if valid := validateHTTPMethod(w, r); !valid {
@@ -1188,6 +1260,13 @@ func processBBSPOSTRequest(w http.ResponseWriter, r *http.Request, sigKH *keyset
 		}
 	}
 
+	if strings.LastIndex(r.URL.Path, blindSignMultiURI) == len(r.URL.Path)-len(blindSignMultiURI) {
+		err = bbsBlindSignPOSTHandle(w, reqBody, sigKH)
+		if err != nil {
+			return err
+		}
+	}
+
 	if strings.LastIndex(r.URL.Path, verifyMultiURI) == len(r.URL.Path)-len(verifyMultiURI) {
 		err = bbsVerifyPOSTHandle(reqBody, sigKH)
 		if err != nil {
@@ -1261,6 +1340,54 @@ func bbsSignPOSTHandle(w http.ResponseWriter, reqBody []byte, sigKH *keyset.Hand
 	return nil
 }
 
+// nolint: interfacer // unnecessary for tests to set w io.Writer, this is a helper for tests only
+func bbsBlindSignPOSTHandle(w http.ResponseWriter, reqBody []byte, sigKH *keyset.Handle) error {
+	sigReq := &blindSignMultiReq{}
+
+	err := json.Unmarshal(reqBody, sigReq)
+	if err != nil {
+		return err
+	}
+
+	var messages [][]byte
+
+	for _, msg := range sigReq.Messages {
+		var msgRecord []byte
+
+		msgRecord, err = base64.URLEncoding.DecodeString(msg)
+		if err != nil {
+			return err
+		}
+
+		messages = append(messages, msgRecord)
+	}
+
+	blindContext, err := base64.URLEncoding.DecodeString(sigReq.BlindContext)
+	if err != nil {
+		return err
+	}
+
+	nonce, err := base64.URLEncoding.DecodeString(sigReq.Nonce)
+	if err != nil {
+		return err
+	}
+
+	s, err := (&tinkcrypto.Crypto{}).BlindSignMulti(messages, sigReq.RevealedIndexes, sigReq.MessagesCount,
+		blindContext, nonce, sigKH)
+	if err != nil {
+		return err
+	}
+
+	mResp, err := json.Marshal(&signResp{Signature: base64.URLEncoding.EncodeToString(s)})
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(mResp)
+
+	return err
+}
+
 func bbsVerifyPOSTHandle(reqBody []byte, sigKH *keyset.Handle) error {
 	verReq := &verifyMultiReq{}
 
@@ -0,0 +1,301 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package expiry
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+)
+
+var logger = log.New("aries-framework/expiry")
+
+const (
+	// ProblemCode is the code of the problem report sent when a protocol instance is abandoned
+	// because it has expired.
+	ProblemCode = "expired"
+
+	instanceTag     = "instance"
+	instanceDataKey = "instance_%s"
+	storeNameFormat = "%s_instances"
+
+	defaultInterval = time.Minute
+)
+
+// ErrExpired is returned when the message arrives after its ~timing.expires_time or when the protocol instance
+// has expired.
+var ErrExpired = errors.New("message has expired")
+
+// ExpiresTime returns the ~timing.expires_time of the message, or the expires_time header of the DIDComm V2 message.
+// The zero time is returned if it is not set.
+func ExpiresTime(msg service.DIDCommMsgMap) (time.Time, error) {
+	if msg.IsDIDCommV2() {
+		return msg.ExpiresTime(), nil
+	}
+
+	timing := struct {
+		Timing *decorator.Timing `json:"~timing,omitempty"`
+	}{}
+
+	err := msg.Decode(&timing)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("decode timing: %w", err)
+	}
+
+	if timing.Timing == nil {
+		return time.Time{}, nil
+	}
+
+	return timing.Timing.ExpiresTime, nil
+}
+
+// Check returns ErrExpired if the ~timing.expires_time of the message has passed.
+func Check(msg service.DIDCommMsgMap) error {
+	expiresTime, err := ExpiresTime(msg)
+	if err != nil {
+		return err
+	}
+
+	if !expiresTime.IsZero() && time.Now().After(expiresTime) {
+		return fmt.Errorf("expires_time %s: %w", expiresTime.Format(time.RFC3339), ErrExpired)
+	}
+
+	return nil
+}
+
+// Instance is the protocol instance tracked by the reaper.
+type Instance struct {
+	PIID     string                `json:"piid"`
+	Msg      service.DIDCommMsgMap `json:"msg,omitempty"`
+	MyDID    string                `json:"my_did,omitempty"`
+	TheirDID string                `json:"their_did,omitempty"`
+	// UpdatedTime is the time the last message of the instance was handled at.
+	UpdatedTime time.Time `json:"updated_time"`
+	// ExpiresTime is the ~timing.expires_time of the last message of the instance.
+	ExpiresTime time.Time `json:"expires_time,omitempty"`
+}
+
+func (i *Instance) expired(now time.Time, timeout time.Duration) bool {
+	if !i.ExpiresTime.IsZero() && now.After(i.ExpiresTime) {
+		return true
+	}
+
+	return timeout > 0 && now.Sub(i.UpdatedTime) > timeout
+}
+
+// AbandonFunc abandons the expired protocol instance.
+type AbandonFunc func(instance *Instance) error
+
+// Option configures the reaper.
+type Option func(r *Reaper)
+
+// WithTimeout sets the duration a protocol instance may stay without any message before it is abandoned.
+// By default, only the instances whose last message has expired are abandoned.
+func WithTimeout(timeout time.Duration) Option {
+	return func(r *Reaper) {
+		r.timeout = timeout
+	}
+}
+
+// WithInterval sets how often the reaper looks for the expired protocol instances.
+func WithInterval(interval time.Duration) Option {
+	return func(r *Reaper) {
+		r.interval = interval
+	}
+}
+
+// Reaper keeps track of the active protocol instances and abandons the ones which have expired either because
+// the ~timing.expires_time of their last message has passed or because they stayed idle longer than the timeout.
+type Reaper struct {
+	store    storage.Store
+	timeout  time.Duration
+	interval time.Duration
+	abandon  AbandonFunc
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewReaper returns the reaper of the protocol instances, the instances are kept in the store named
+// after the protocol.
+func NewReaper(prov storage.Provider, protocol string, abandon AbandonFunc, opts ...Option) (*Reaper, error) {
+	storeName := fmt.Sprintf(storeNameFormat, protocol)
+
+	store, err := prov.OpenStore(storeName)
+	if err != nil {
+		return nil, fmt.Errorf("open protocol instances store: %w", err)
+	}
+
+	err = prov.SetStoreConfig(storeName, storage.StoreConfiguration{TagNames: []string{instanceTag}})
+	if err != nil {
+		return nil, fmt.Errorf("set protocol instances store config: %w", err)
+	}
+
+	r := &Reaper{
+		store:    store,
+		interval: defaultInterval,
+		abandon:  abandon,
+		done:     make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r, nil
+}
+
+// Start runs the reaper in the background until Stop is called.
+func (r *Reaper) Start() {
+	go func() {
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				r.reap(time.Now())
+			case <-r.done:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the reaper. It is safe to call Stop more than once.
+func (r *Reaper) Stop() {
+	r.stopOnce.Do(func() {
+		close(r.done)
+	})
+}
+
+// Touch records the last message handled by the protocol instance.
+func (r *Reaper) Touch(piID string, msg service.DIDCommMsgMap, myDID, theirDID string) error {
+	expiresTime, err := ExpiresTime(msg)
+	if err != nil {
+		return err
+	}
+
+	src, err := json.Marshal(&Instance{
+		PIID:        piID,
+		Msg:         msg,
+		MyDID:       myDID,
+		TheirDID:    theirDID,
+		UpdatedTime: time.Now(),
+		ExpiresTime: expiresTime,
+	})
+	if err != nil {
+		return fmt.Errorf("marshal protocol instance: %w", err)
+	}
+
+	return r.store.Put(fmt.Sprintf(instanceDataKey, piID), src, storage.Tag{Name: instanceTag})
+}
+
+// Remove stops tracking the protocol instance, typically when it reaches its final state.
+func (r *Reaper) Remove(piID string) error {
+	err := r.store.Delete(fmt.Sprintf(instanceDataKey, piID))
+	if err != nil && !errors.Is(err, storage.ErrDataNotFound) {
+		return fmt.Errorf("remove protocol instance: %w", err)
+	}
+
+	return nil
+}
+
+// Check returns ErrExpired if the protocol instance has expired, the messages of such instance
+// should not be handled anymore.
+func (r *Reaper) Check(piID string) error {
+	src, err := r.store.Get(fmt.Sprintf(instanceDataKey, piID))
+	if errors.Is(err, storage.ErrDataNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("get protocol instance: %w", err)
+	}
+
+	instance := &Instance{}
+
+	err = json.Unmarshal(src, instance)
+	if err != nil {
+		return fmt.Errorf("unmarshal protocol instance: %w", err)
+	}
+
+	if instance.expired(time.Now(), r.timeout) {
+		return fmt.Errorf("protocol instance %s: %w", piID, ErrExpired)
+	}
+
+	return nil
+}
+
+// reap abandons the expired protocol instances.
+func (r *Reaper) reap(now time.Time) {
+	expired, err := r.expiredInstances(now)
+	if err != nil {
+		logger.Warnf("failed to query the expired protocol instances: %s", err)
+
+		return
+	}
+
+	for _, instance := range expired {
+		if err := r.Remove(instance.PIID); err != nil {
+			logger.Warnf("failed to remove the expired protocol instance %s: %s", instance.PIID, err)
+
+			continue
+		}
+
+		if err := r.abandon(instance); err != nil {
+			logger.Errorf("failed to abandon the expired protocol instance %s: %s", instance.PIID, err)
+		}
+	}
+}
+
+func (r *Reaper) expiredInstances(now time.Time) ([]*Instance, error) {
+	records, err := r.store.Query(instanceTag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query protocol instances: %w", err)
+	}
+
+	defer storage.Close(records, logger)
+
+	var expired []*Instance
+
+	more, err := records.Next()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get next record: %w", err)
+	}
+
+	for more {
+		value, err := records.Value()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get value from records: %w", err)
+		}
+
+		instance := &Instance{}
+
+		err = json.Unmarshal(value, instance)
+		if err != nil {
+			return nil, fmt.Errorf("unmarshal protocol instance: %w", err)
+		}
+
+		if instance.expired(now, r.timeout) {
+			expired = append(expired, instance)
+		}
+
+		more, err = records.Next()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get next record: %w", err)
+		}
+	}
+
+	return expired, nil
+}
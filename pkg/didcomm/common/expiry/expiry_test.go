@@ -0,0 +1,190 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package expiry
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	mockstore "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+)
+
+const protocol = "protocol"
+
+func TestCheck(t *testing.T) {
+	t.Run("no timing", func(t *testing.T) {
+		require.NoError(t, Check(service.DIDCommMsgMap{"@id": "msg-1"}))
+	})
+
+	t.Run("not expired", func(t *testing.T) {
+		require.NoError(t, Check(expiringMessage("msg-1", "", time.Now().Add(time.Minute))))
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		err := Check(expiringMessage("msg-1", "", time.Now().Add(-time.Minute)))
+		require.True(t, errors.Is(err, ErrExpired))
+		require.Contains(t, err.Error(), "expires_time")
+	})
+
+	t.Run("invalid timing", func(t *testing.T) {
+		err := Check(service.DIDCommMsgMap{"@id": "msg-1", "~timing": "invalid"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "decode timing")
+	})
+}
+
+func TestNewReaper(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		r, err := NewReaper(mem.NewProvider(), protocol, nil, WithTimeout(time.Hour), WithInterval(time.Second))
+		require.NoError(t, err)
+		require.Equal(t, time.Hour, r.timeout)
+		require.Equal(t, time.Second, r.interval)
+	})
+
+	t.Run("open store error", func(t *testing.T) {
+		_, err := NewReaper(&mockstore.MockStoreProvider{ErrOpenStoreHandle: errors.New("open error")}, protocol, nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "open protocol instances store: open error")
+	})
+}
+
+func TestReaper_Reap(t *testing.T) {
+	t.Run("abandons expired instances", func(t *testing.T) {
+		abandoned := make(chan *Instance, 2)
+
+		r, err := NewReaper(mem.NewProvider(), protocol, func(instance *Instance) error {
+			abandoned <- instance
+
+			return nil
+		}, WithInterval(10*time.Millisecond))
+		require.NoError(t, err)
+
+		require.NoError(t, r.Touch("piid-1", expiringMessage("msg-1", "piid-1", time.Now().Add(-time.Second)),
+			"my-did", "their-did"))
+		require.NoError(t, r.Touch("piid-2", expiringMessage("msg-2", "piid-2", time.Now().Add(time.Hour)), "", ""))
+		require.NoError(t, r.Touch("piid-3", service.DIDCommMsgMap{"@id": "msg-3"}, "", ""))
+
+		require.True(t, errors.Is(r.Check("piid-1"), ErrExpired))
+		require.NoError(t, r.Check("piid-2"))
+		require.NoError(t, r.Check("unknown"))
+
+		r.Start()
+		defer r.Stop()
+
+		select {
+		case instance := <-abandoned:
+			require.Equal(t, "piid-1", instance.PIID)
+			require.Equal(t, "msg-1", instance.Msg.ID())
+			require.Equal(t, "my-did", instance.MyDID)
+			require.Equal(t, "their-did", instance.TheirDID)
+		case <-time.After(time.Second):
+			t.Fatal("timeout")
+		}
+
+		// the abandoned instance is not tracked anymore
+		require.NoError(t, r.Check("piid-1"))
+
+		select {
+		case instance := <-abandoned:
+			t.Fatalf("unexpected abandoned instance %s", instance.PIID)
+		case <-time.After(50 * time.Millisecond):
+		}
+	})
+
+	t.Run("abandons idle instances", func(t *testing.T) {
+		var abandoned []string
+
+		r, err := NewReaper(mem.NewProvider(), protocol, func(instance *Instance) error {
+			abandoned = append(abandoned, instance.PIID)
+
+			return errors.New("abandon error")
+		}, WithTimeout(time.Minute))
+		require.NoError(t, err)
+
+		require.NoError(t, r.Touch("piid-1", service.DIDCommMsgMap{"@id": "msg-1"}, "", ""))
+
+		r.reap(time.Now())
+		require.Empty(t, abandoned)
+
+		r.reap(time.Now().Add(2 * time.Minute))
+		require.Equal(t, []string{"piid-1"}, abandoned)
+	})
+
+	t.Run("removed instances are not abandoned", func(t *testing.T) {
+		r, err := NewReaper(mem.NewProvider(), protocol, func(instance *Instance) error {
+			t.Fatalf("unexpected abandoned instance %s", instance.PIID)
+
+			return nil
+		}, WithTimeout(time.Minute))
+		require.NoError(t, err)
+
+		require.NoError(t, r.Touch("piid-1", service.DIDCommMsgMap{"@id": "msg-1"}, "", ""))
+		require.NoError(t, r.Remove("piid-1"))
+		require.NoError(t, r.Remove("piid-1"))
+
+		r.reap(time.Now().Add(2 * time.Minute))
+	})
+
+	t.Run("store errors", func(t *testing.T) {
+		store := &mockstore.MockStore{Store: make(map[string]mockstore.DBEntry)}
+
+		r, err := NewReaper(&mockstore.MockStoreProvider{Store: store}, protocol, nil)
+		require.NoError(t, err)
+
+		err = r.Touch("piid-1", service.DIDCommMsgMap{"@id": "msg-1", "~timing": "invalid"}, "", "")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "decode timing")
+
+		store.ErrPut = errors.New("put error")
+		require.EqualError(t, r.Touch("piid-1", service.DIDCommMsgMap{"@id": "msg-1"}, "", ""), "put error")
+
+		store.ErrGet = errors.New("get error")
+		err = r.Check("piid-1")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "get protocol instance: get error")
+
+		store.ErrDelete = errors.New("delete error")
+		err = r.Remove("piid-1")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "remove protocol instance: delete error")
+	})
+}
+
+func TestReaper_Stop(t *testing.T) {
+	r, err := NewReaper(mem.NewProvider(), protocol, func(*Instance) error { return nil })
+	require.NoError(t, err)
+
+	r.Start()
+	r.Stop()
+
+	// stop is idempotent
+	r.Stop()
+}
+
+func expiringMessage(id, thID string, expiresTime time.Time) service.DIDCommMsgMap {
+	msg := service.NewDIDCommMsgMap(struct {
+		ID     string            `json:"@id"`
+		Type   string            `json:"@type"`
+		Timing *decorator.Timing `json:"~timing"`
+	}{
+		ID:     id,
+		Type:   "type",
+		Timing: &decorator.Timing{ExpiresTime: expiresTime},
+	})
+
+	if thID != "" {
+		msg["~thread"] = map[string]interface{}{"thid": thID}
+	}
+
+	return msg
+}
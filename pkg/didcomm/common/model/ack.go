@@ -8,6 +8,19 @@ package model
 
 import "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
 
+const (
+	// AckMsgType is the generic acknowledgement message type
+	// https://github.com/hyperledger/aries-rfcs/tree/master/features/0015-acks
+	AckMsgType = "https://didcomm.org/notification/1.0/ack"
+
+	// AckStatusOK the message was processed successfully.
+	AckStatusOK = "OK"
+	// AckStatusPending the message was received but has not been processed yet.
+	AckStatusPending = "PENDING"
+	// AckStatusFail the message was received but could not be processed.
+	AckStatusFail = "FAIL"
+)
+
 // Ack acknowledgement struct.
 type Ack struct {
 	Type   string            `json:"@type,omitempty"`
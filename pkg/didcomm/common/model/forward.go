@@ -6,6 +6,8 @@ SPDX-License-Identifier: Apache-2.0
 
 package model
 
+import "encoding/json"
+
 // Forward route forward message.
 // nolint lll - url in the next line is long
 // https://github.com/hyperledger/aries-rfcs/blob/master/concepts/0094-cross-domain-messaging/README.md#corerouting10forward
@@ -15,3 +17,31 @@ type Forward struct {
 	To   string    `json:"to,omitempty"`
 	Msg  *Envelope `json:"msg,omitempty"`
 }
+
+// ForwardV2 route forward message of DIDComm V2.
+// https://identity.foundation/didcomm-messaging/spec/#messages
+type ForwardV2 struct {
+	ID          string         `json:"id,omitempty"`
+	Type        string         `json:"type,omitempty"`
+	To          []string       `json:"to,omitempty"`
+	Body        ForwardV2Body  `json:"body"`
+	Attachments []AttachmentV2 `json:"attachments,omitempty"`
+}
+
+// ForwardV2Body is the body of the DIDComm V2 forward message.
+type ForwardV2Body struct {
+	// Next is the DID or the key (DID URL) of the next hop.
+	Next string `json:"next"`
+}
+
+// AttachmentV2 is a DIDComm V2 attachment.
+type AttachmentV2 struct {
+	ID        string           `json:"id,omitempty"`
+	MediaType string           `json:"media_type,omitempty"`
+	Data      AttachmentV2Data `json:"data"`
+}
+
+// AttachmentV2Data is the data of the DIDComm V2 attachment.
+type AttachmentV2Data struct {
+	JSON json.RawMessage `json:"json,omitempty"`
+}
@@ -6,15 +6,40 @@ SPDX-License-Identifier: Apache-2.0
 
 package model
 
-// ProblemReport problem report definition
-// TODO: need to provide full ProblemReport structure https://github.com/hyperledger/aries-framework-go/issues/912
+import (
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+)
+
+// ProblemReportMsgType is the generic problem report message type
+// https://github.com/hyperledger/aries-rfcs/tree/master/features/0035-report-problem
+const ProblemReportMsgType = "https://didcomm.org/report-problem/1.0/problem-report"
+
+// ProblemReport problem report definition.
 type ProblemReport struct {
-	Type        string `json:"@type"`
-	ID          string `json:"@id"`
-	Description Code   `json:"description"`
+	Type        string            `json:"@type"`
+	ID          string            `json:"@id"`
+	Thread      *decorator.Thread `json:"~thread,omitempty"`
+	Description Code              `json:"description"`
+	// ProblemItems lists the parameters or data that caused the problem, e.g. [{"did": "did:sov:C805sNYhMrjHiqZDTUASHg"}].
+	ProblemItems []map[string]string `json:"problem_items,omitempty"`
+	// WhoRetries is one of "me", "you", "both" or "none".
+	WhoRetries string `json:"who_retries,omitempty"`
+	FixHint    string `json:"fix_hint,omitempty"`
+	// Impact is one of "message", "thread" or "connection".
+	Impact string `json:"impact,omitempty"`
+	// Where is the party and the place the problem occurred at, e.g. "you - agency".
+	Where       string     `json:"where,omitempty"`
+	NoticedTime *time.Time `json:"noticed_time,omitempty"`
+	TrackingURI string     `json:"tracking_uri,omitempty"`
+	// EscalationURI is where the problem may be reported to a human, e.g. "mailto:admin@faber.edu".
+	EscalationURI string `json:"escalation_uri,omitempty"`
 }
 
 // Code represents a problem report code.
 type Code struct {
 	Code string `json:"code"`
+	// En is the human readable description of the problem in English.
+	En string `json:"en,omitempty"`
 }
@@ -6,5 +6,9 @@ SPDX-License-Identifier: Apache-2.0
 
 package service
 
-// ForwardMsgType defines the route forward message type.
-const ForwardMsgType = "https://didcomm.org/routing/1.0/forward"
+const (
+	// ForwardMsgType defines the route forward message type.
+	ForwardMsgType = "https://didcomm.org/routing/1.0/forward"
+	// ForwardV2MsgType defines the DIDComm V2 route forward message type.
+	ForwardV2MsgType = "https://didcomm.org/routing/2.0/forward"
+)
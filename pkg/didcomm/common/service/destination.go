@@ -8,21 +8,25 @@ package service
 
 import (
 	"fmt"
+	"strings"
 
 	diddoc "github.com/hyperledger/aries-framework-go/pkg/doc/did"
 	vdrapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
 )
 
 // Destination provides the recipientKeys, routingKeys, and serviceEndpoint for an outbound message.
+// The keys of a DIDComm V2 destination are DID URLs referencing the keyAgreement verification methods.
 type Destination struct {
 	RecipientKeys        []string
 	ServiceEndpoint      string
 	RoutingKeys          []string
 	TransportReturnRoute string
+	DIDCommVersion       Version
 }
 
 const (
-	didCommServiceType = "did-communication"
+	didCommServiceType   = "did-communication"
+	didCommV2ServiceType = "DIDCommMessaging"
 )
 
 // GetDestination constructs a Destination struct based on the given DID and parameters
@@ -38,9 +42,14 @@ func GetDestination(did string, vdr vdrapi.Registry) (*Destination, error) {
 
 // CreateDestination makes a DIDComm Destination object from a DID Doc as per the DIDComm service conventions:
 // https://github.com/hyperledger/aries-rfcs/blob/master/features/0067-didcomm-diddoc-conventions/README.md.
+// The DIDComm V2 service (DIDCommMessaging) is used if the DID doc has no DIDComm V1 service.
 func CreateDestination(didDoc *diddoc.Doc) (*Destination, error) {
 	didCommService, ok := diddoc.LookupService(didDoc, didCommServiceType)
 	if !ok {
+		if v2Service, ok := diddoc.LookupService(didDoc, didCommV2ServiceType); ok {
+			return createDestinationV2(didDoc, v2Service)
+		}
+
 		return nil, fmt.Errorf("create destination: missing DID doc service")
 	}
 
@@ -61,3 +70,34 @@ func CreateDestination(didDoc *diddoc.Doc) (*Destination, error) {
 		RoutingKeys:     didCommService.RoutingKeys,
 	}, nil
 }
+
+// createDestinationV2 makes a DIDComm V2 Destination, the recipient keys are the keyAgreement verification methods
+// of the DID doc: https://identity.foundation/didcomm-messaging/spec/#did-document-service-endpoint.
+func createDestinationV2(didDoc *diddoc.Doc, didCommService *diddoc.Service) (*Destination, error) {
+	if didCommService.ServiceEndpoint == "" {
+		return nil, fmt.Errorf("create destination: no service endpoint on didcomm v2 service block in diddoc: %+v",
+			didDoc)
+	}
+
+	var recipientKeys []string
+
+	for _, ka := range didDoc.KeyAgreement {
+		kid := ka.VerificationMethod.ID
+		if strings.HasPrefix(kid, "#") {
+			kid = didDoc.ID + kid
+		}
+
+		recipientKeys = append(recipientKeys, kid)
+	}
+
+	if len(recipientKeys) == 0 {
+		return nil, fmt.Errorf("create destination: no key agreements in diddoc: %+v", didDoc)
+	}
+
+	return &Destination{
+		RecipientKeys:   recipientKeys,
+		ServiceEndpoint: didCommService.ServiceEndpoint,
+		RoutingKeys:     didCommService.RoutingKeys,
+		DIDCommVersion:  V2,
+	}, nil
+}
@@ -100,6 +100,43 @@ func TestPrepareDestination(t *testing.T) {
 		require.Nil(t, dest)
 	})
 
+	t.Run("successfully prepared DIDComm V2 destination", func(t *testing.T) {
+		didDoc := createDIDDocV2()
+
+		dest, err := CreateDestination(didDoc)
+		require.NoError(t, err)
+		require.Equal(t, V2, dest.DIDCommVersion)
+		require.Equal(t, "https://localhost:8090", dest.ServiceEndpoint)
+		require.Equal(t, []string{"did:example:alice#key-x25519-1", "did:example:alice#key-x25519-2"},
+			dest.RecipientKeys)
+		require.Equal(t, []string{"did:example:mediator#key-x25519-1"}, dest.RoutingKeys)
+	})
+
+	t.Run("DIDComm V1 service is preferred", func(t *testing.T) {
+		didDoc := createDIDDocV2()
+		didDoc.Service = append(didDoc.Service, mockdiddoc.GetMockDIDDoc(t).Service...)
+
+		dest, err := CreateDestination(didDoc)
+		require.NoError(t, err)
+		require.Empty(t, dest.DIDCommVersion)
+		require.Equal(t, didDoc.Service[1].RecipientKeys, dest.RecipientKeys)
+	})
+
+	t.Run("DIDComm V2 destination errors", func(t *testing.T) {
+		didDoc := createDIDDocV2()
+		didDoc.Service[0].ServiceEndpoint = ""
+
+		_, err := CreateDestination(didDoc)
+		require.EqualError(t, err, fmt.Sprintf("create destination: no service endpoint on didcomm v2 service "+
+			"block in diddoc: %+v", didDoc))
+
+		didDoc = createDIDDocV2()
+		didDoc.KeyAgreement = nil
+
+		_, err = CreateDestination(didDoc)
+		require.EqualError(t, err, fmt.Sprintf("create destination: no key agreements in diddoc: %+v", didDoc))
+	})
+
 	t.Run("error while getting recipient keys from did doc", func(t *testing.T) {
 		didDoc := mockdiddoc.GetMockDIDDoc(t)
 		didDoc.Service[0].RecipientKeys = []string{}
@@ -161,3 +198,32 @@ func createDIDDocWithKey(pub string) *did.Doc {
 
 	return didDoc
 }
+
+func createDIDDocV2() *did.Doc {
+	const id = "did:example:alice"
+
+	pubKey, _ := generateKeyPair()
+
+	return &did.Doc{
+		Context: []string{did.Context},
+		ID:      id,
+		KeyAgreement: []did.Verification{
+			{
+				VerificationMethod: *did.NewVerificationMethodFromBytes(id+"#key-x25519-1",
+					"X25519KeyAgreementKey2019", id, base58.Decode(pubKey)),
+				Relationship: did.KeyAgreement,
+			},
+			{
+				VerificationMethod: *did.NewVerificationMethodFromBytes("#key-x25519-2",
+					"X25519KeyAgreementKey2019", id, base58.Decode(pubKey)),
+				Relationship: did.KeyAgreement,
+			},
+		},
+		Service: []did.Service{{
+			ID:              id + "#didcomm-1",
+			Type:            "DIDCommMessaging",
+			ServiceEndpoint: "https://localhost:8090",
+			RoutingKeys:     []string{"did:example:mediator#key-x25519-1"},
+		}},
+	}
+}
@@ -25,6 +25,22 @@ const (
 	jsonThreadID       = "thid"
 	jsonParentThreadID = "pthid"
 	jsonMetadata       = "_internal_metadata"
+
+	jsonIDV2          = "id"
+	jsonTypeV2        = "type"
+	jsonFromV2        = "from"
+	jsonToV2          = "to"
+	jsonCreatedTimeV2 = "created_time"
+	jsonExpiresTimeV2 = "expires_time"
+)
+
+// Version represents the DIDComm protocol version of a message.
+type Version string
+
+// DIDComm versions.
+const (
+	V1 Version = "v1"
+	V2 Version = "v2"
 )
 
 // Metadata may contain additional payload for the protocol. It might be populated by the client/protocol
@@ -91,15 +107,41 @@ func NewDIDCommMsgMap(v interface{}) DIDCommMsgMap {
 	return msg
 }
 
+// IsDIDCommV2 returns true if the message is a DIDComm V2 plaintext message, ie it has the "type" header instead of
+// the "@type" one.
+func (m DIDCommMsgMap) IsDIDCommV2() bool {
+	if m == nil || m[jsonType] != nil {
+		return false
+	}
+
+	_, ok := m[jsonTypeV2].(string)
+
+	return ok
+}
+
+// Version returns the DIDComm version of the message.
+func (m DIDCommMsgMap) Version() Version {
+	if m.IsDIDCommV2() {
+		return V2
+	}
+
+	return V1
+}
+
 // ThreadID returns msg ~thread.thid if there is no ~thread.thid returns msg @id
 // message is invalid if ~thread.thid exist and @id is absent.
+// For DIDComm V2 messages, the thid header is used instead of ~thread.thid.
 func (m DIDCommMsgMap) ThreadID() (string, error) {
 	if m == nil {
 		return "", ErrInvalidMessage
 	}
 
 	msgID := m.ID()
+
 	thread, ok := m[jsonThread].(map[string]interface{})
+	if m.IsDIDCommV2() {
+		thread, ok = m, true
+	}
 
 	if ok && thread[jsonThreadID] != nil {
 		var thID string
@@ -141,20 +183,19 @@ func (m DIDCommMsgMap) Metadata() map[string]interface{} {
 
 // Type returns the message type.
 func (m DIDCommMsgMap) Type() string {
-	if m == nil || m[jsonType] == nil {
-		return ""
+	if m.IsDIDCommV2() {
+		return m.stringValue(jsonTypeV2)
 	}
 
-	res, ok := m[jsonType].(string)
-	if !ok {
-		return ""
-	}
-
-	return res
+	return m.stringValue(jsonType)
 }
 
 // ParentThreadID returns the message parent threadID.
 func (m DIDCommMsgMap) ParentThreadID() string {
+	if m.IsDIDCommV2() {
+		return m.stringValue(jsonParentThreadID)
+	}
+
 	if m == nil || m[jsonThread] == nil {
 		return ""
 	}
@@ -170,16 +211,11 @@ func (m DIDCommMsgMap) ParentThreadID() string {
 
 // ID returns the message id.
 func (m DIDCommMsgMap) ID() string {
-	if m == nil || m[jsonID] == nil {
-		return ""
-	}
-
-	res, ok := m[jsonID].(string)
-	if !ok {
-		return ""
+	if m.IsDIDCommV2() {
+		return m.stringValue(jsonIDV2)
 	}
 
-	return res
+	return m.stringValue(jsonID)
 }
 
 // SetID sets the message id.
@@ -188,11 +224,88 @@ func (m DIDCommMsgMap) SetID(id string) error {
 		return ErrNilMessage
 	}
 
+	if m.IsDIDCommV2() {
+		m[jsonIDV2] = id
+
+		return nil
+	}
+
 	m[jsonID] = id
 
 	return nil
 }
 
+// From returns the sender DID of the DIDComm V2 message.
+func (m DIDCommMsgMap) From() string {
+	if !m.IsDIDCommV2() {
+		return ""
+	}
+
+	return m.stringValue(jsonFromV2)
+}
+
+// To returns the recipient DIDs of the DIDComm V2 message.
+func (m DIDCommMsgMap) To() []string {
+	if !m.IsDIDCommV2() {
+		return nil
+	}
+
+	to, ok := m[jsonToV2].([]interface{})
+	if !ok {
+		return nil
+	}
+
+	var res []string
+
+	for _, v := range to {
+		if did, ok := v.(string); ok {
+			res = append(res, did)
+		}
+	}
+
+	return res
+}
+
+// CreatedTime returns the created_time header of the DIDComm V2 message, the zero time is returned if it is not set.
+func (m DIDCommMsgMap) CreatedTime() time.Time {
+	return m.unixTime(jsonCreatedTimeV2)
+}
+
+// ExpiresTime returns the expires_time header of the DIDComm V2 message, the zero time is returned if it is not set.
+func (m DIDCommMsgMap) ExpiresTime() time.Time {
+	return m.unixTime(jsonExpiresTimeV2)
+}
+
+func (m DIDCommMsgMap) unixTime(key string) time.Time {
+	if !m.IsDIDCommV2() {
+		return time.Time{}
+	}
+
+	switch v := m[key].(type) {
+	case float64:
+		return time.Unix(int64(v), 0)
+	case int64:
+		return time.Unix(v, 0)
+	case int:
+		return time.Unix(int64(v), 0)
+	}
+
+	return time.Time{}
+}
+
+func (m DIDCommMsgMap) stringValue(key string) string {
+	if m == nil || m[key] == nil {
+		return ""
+	}
+
+	res, ok := m[key].(string)
+	if !ok {
+		return ""
+	}
+
+	return res
+}
+
 // Decode converts message to  struct.
 func (m DIDCommMsgMap) Decode(v interface{}) error {
 	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
@@ -302,3 +302,48 @@ func TestDIDCommMsgMap_ToJsonDIDDoc(t *testing.T) {
 	require.NoError(t, err)
 	require.NotEmpty(t, req.Connection.Doc)
 }
+
+func TestDIDCommMsgMap_V2(t *testing.T) {
+	t.Run("V1 message", func(t *testing.T) {
+		msg := DIDCommMsgMap{jsonID: "ID", jsonType: "type"}
+
+		require.False(t, msg.IsDIDCommV2())
+		require.Equal(t, V1, msg.Version())
+		require.Empty(t, msg.From())
+		require.Empty(t, msg.To())
+		require.True(t, msg.ExpiresTime().IsZero())
+		require.False(t, DIDCommMsgMap(nil).IsDIDCommV2())
+	})
+
+	t.Run("V2 message", func(t *testing.T) {
+		msg := DIDCommMsgMap{}
+		require.NoError(t, json.Unmarshal([]byte(`{
+			"id": "ID",
+			"type": "https://didcomm.org/basicmessage/2.0/message",
+			"from": "did:example:alice",
+			"to": ["did:example:bob"],
+			"thid": "thread-ID",
+			"pthid": "parent-thread-ID",
+			"created_time": 1516269022,
+			"expires_time": 1516385931
+		}`), &msg))
+
+		require.True(t, msg.IsDIDCommV2())
+		require.Equal(t, V2, msg.Version())
+		require.Equal(t, "ID", msg.ID())
+		require.Equal(t, "https://didcomm.org/basicmessage/2.0/message", msg.Type())
+		require.Equal(t, "did:example:alice", msg.From())
+		require.Equal(t, []string{"did:example:bob"}, msg.To())
+		require.Equal(t, "parent-thread-ID", msg.ParentThreadID())
+		require.Equal(t, time.Unix(1516269022, 0), msg.CreatedTime())
+		require.Equal(t, time.Unix(1516385931, 0), msg.ExpiresTime())
+
+		thID, err := msg.ThreadID()
+		require.NoError(t, err)
+		require.Equal(t, "thread-ID", thID)
+
+		require.NoError(t, msg.SetID("new-ID"))
+		require.Equal(t, "new-ID", msg.ID())
+		require.Nil(t, msg[jsonID])
+	})
+}
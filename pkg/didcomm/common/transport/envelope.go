@@ -8,12 +8,17 @@ package transport
 
 // Envelope holds message data and metadata for inbound and outbound messaging.
 type Envelope struct {
-	Message []byte
-	FromKey []byte
+	// MediaType selects the packer of an outbound message, the primary packer is used if it is empty.
+	MediaType string
+	Message   []byte
+	FromKey   []byte
 	// ToKeys stores keys for an outbound message packing
 	ToKeys []string
 	// ToKey holds the key that was used to decrypt an inbound message
 	ToKey   []byte
 	FromDID string
 	ToDID   string
+	// Signer is the kid (DID URL) of the signing key: an outbound message is signed with it before being packed
+	// (sign then encrypt), for an inbound message it holds the verified signer of the signed (JWS) envelope.
+	Signer string
 }
@@ -0,0 +1,21 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package transport
+
+// Media types of the DIDComm envelopes, they are found in the `typ` header of the envelopes.
+const (
+	// MediaTypeRFC0019EncryptedEnvelope is the legacy envelope media type of Aries RFC 0019.
+	MediaTypeRFC0019EncryptedEnvelope = "JWM/1.0"
+	// MediaTypeV1EncryptedEnvelope is the DIDComm V1 JWE envelope media type of Aries RFC 0334.
+	MediaTypeV1EncryptedEnvelope = "didcomm-envelope-enc"
+	// MediaTypeV2EncryptedEnvelope is the DIDComm V2 JWE envelope media type.
+	MediaTypeV2EncryptedEnvelope = "application/didcomm-encrypted+json"
+	// MediaTypeV2SignedEnvelope is the DIDComm V2 JWS envelope media type.
+	MediaTypeV2SignedEnvelope = "application/didcomm-signed+json"
+	// MediaTypeV2Plaintext is the DIDComm V2 plaintext message media type.
+	MediaTypeV2Plaintext = "application/didcomm-plain+json"
+)
@@ -18,6 +18,18 @@ type ProtocolService interface {
 	Name() string
 }
 
+// Acknowledger is implemented by the protocol services which acknowledge the messages with the ~please_ack
+// decorator themselves (e.g. with the ack of the protocol), so no generic ack is sent for those messages.
+type Acknowledger interface {
+	Acknowledges(msgType string) bool
+}
+
+// Discloser is implemented by the protocol and message services which disclose the message types they handle,
+// the protocols of those message types are advertised with the discover features protocol.
+type Discloser interface {
+	MessageTypes() []string
+}
+
 // MessageService is service for handling generic messages
 // matching accept criteria based on message header.
 type MessageService interface {
@@ -8,6 +8,7 @@ package dispatcher
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -19,7 +20,6 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/transport"
 	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
-	"github.com/hyperledger/aries-framework-go/pkg/kms"
 	"github.com/hyperledger/aries-framework-go/pkg/vdr/fingerprint"
 )
 
@@ -29,27 +29,66 @@ type provider interface {
 	OutboundTransports() []transport.OutboundTransport
 	TransportReturnRoute() string
 	VDRegistry() vdr.Registry
-	KMS() kms.KeyManager
 }
 
+const returnRouteV2 = "return_route"
+
+// ForwardPacking is the packing algorithm of the forward messages wrapping the outbound messages for the mediators.
+type ForwardPacking int
+
+const (
+	// AnoncryptForward packs the forward messages anonymously, this is the default.
+	AnoncryptForward ForwardPacking = iota
+	// AuthcryptForward packs the forward messages with the sender key of the wrapped message.
+	AuthcryptForward
+)
+
 // OutboundDispatcher dispatch msgs to destination.
 type OutboundDispatcher struct {
 	outboundTransports   []transport.OutboundTransport
 	packager             commontransport.Packager
 	transportReturnRoute string
 	vdRegistry           vdr.Registry
-	kms                  kms.KeyManager
+	queue                *OutboundQueue
+	forwardPacking       ForwardPacking
+}
+
+// OutboundOption configures the outbound dispatcher.
+type OutboundOption func(o *OutboundDispatcher)
+
+// WithOutboundQueue queues the messages the outbound transports failed to send for the retries. The messages
+// forwarded without packing aren't queued, the callers handle the failures themselves.
+func WithOutboundQueue(queue *OutboundQueue) OutboundOption {
+	return func(o *OutboundDispatcher) {
+		o.queue = queue
+	}
+}
+
+// WithForwardPacking sets the packing algorithm of the forward messages (anoncrypt by default).
+func WithForwardPacking(packing ForwardPacking) OutboundOption {
+	return func(o *OutboundDispatcher) {
+		o.forwardPacking = packing
+	}
 }
 
 // NewOutbound return new dispatcher outbound instance.
-func NewOutbound(prov provider) *OutboundDispatcher {
-	return &OutboundDispatcher{
+func NewOutbound(prov provider, opts ...OutboundOption) *OutboundDispatcher {
+	o := &OutboundDispatcher{
 		outboundTransports:   prov.OutboundTransports(),
 		packager:             prov.Packager(),
 		transportReturnRoute: prov.TransportReturnRoute(),
 		vdRegistry:           prov.VDRegistry(),
-		kms:                  prov.KMS(),
 	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.queue != nil {
+		o.queue.start(o.sendPacked)
+	}
+
+	return o
 }
 
 // SendToDID sends a message from myDID to the agent who owns theirDID.
@@ -94,19 +133,22 @@ func (o *OutboundDispatcher) Send(msg interface{}, senderVerKey string, des *ser
 			return fmt.Errorf("outboundDispatcher.Send: failed marshal to bytes: %w", err)
 		}
 
+		des = withDIDCommVersion(req, des)
+
 		// update the outbound message with transport return route option [all or thread]
 		req, err = o.addTransportRouteOptions(req, des)
 		if err != nil {
 			return fmt.Errorf("outboundDispatcher.Send: failed to add transport route options : %w", err)
 		}
 
-		sender, err := fingerprint.PubKeyFromDIDKey(senderVerKey)
+		sender, err := senderKey(senderVerKey, des)
 		if err != nil {
 			return fmt.Errorf("outboundDispatcher.Send: failed to extract pubKeyBytes from senderVerKey: %w", err)
 		}
 
-		packedMsg, err := o.packager.PackMessage(
-			&commontransport.Envelope{Message: req, FromKey: sender, ToKeys: des.RecipientKeys})
+		packedMsg, err := o.packager.PackMessage(&commontransport.Envelope{
+			MediaType: mediaType(des), Message: req, FromKey: sender, ToKeys: des.RecipientKeys,
+		})
 		if err != nil {
 			return fmt.Errorf("outboundDispatcher.Send: failed to pack msg: %w", err)
 		}
@@ -114,12 +156,16 @@ func (o *OutboundDispatcher) Send(msg interface{}, senderVerKey string, des *ser
 		// set the return route option
 		des.TransportReturnRoute = o.transportReturnRoute
 
-		packedMsg, err = o.createForwardMessage(packedMsg, des)
+		packedMsg, err = o.createForwardMessage(packedMsg, sender, des)
 		if err != nil {
 			return fmt.Errorf("outboundDispatcher.Send: failed to create forward msg : %w", err)
 		}
 
 		_, err = v.Send(packedMsg, des)
+		if err != nil && o.queue != nil {
+			return o.queueMessage(packedMsg, des, err)
+		}
+
 		if err != nil {
 			return fmt.Errorf("outboundDispatcher.Send: failed to send msg using outbound transport: %w", err)
 		}
@@ -130,6 +176,35 @@ func (o *OutboundDispatcher) Send(msg interface{}, senderVerKey string, des *ser
 	return fmt.Errorf("outboundDispatcher.Send: no transport found for destination: %+v", des)
 }
 
+func (o *OutboundDispatcher) queueMessage(packedMsg []byte, des *service.Destination, sendErr error) error {
+	if err := o.queue.enqueue(packedMsg, des, sendErr); err != nil {
+		return fmt.Errorf("outboundDispatcher.Send: failed to send msg using outbound transport: %v, "+
+			"failed to queue msg: %w", sendErr, err)
+	}
+
+	return nil
+}
+
+// sendPacked sends the packed message to the destination, it's used to retry the queued messages.
+func (o *OutboundDispatcher) sendPacked(packedMsg []byte, des *service.Destination) error {
+	keys := des.RecipientKeys
+	if len(des.RoutingKeys) != 0 {
+		keys = des.RoutingKeys
+	}
+
+	for _, v := range o.outboundTransports {
+		if !v.AcceptRecipient(keys) && !v.Accept(des.ServiceEndpoint) {
+			continue
+		}
+
+		_, err := v.Send(packedMsg, des)
+
+		return err
+	}
+
+	return fmt.Errorf("no transport found for destination: %+v", des)
+}
+
 // Forward forwards the message without packing to the destination.
 func (o *OutboundDispatcher) Forward(msg interface{}, des *service.Destination) error {
 	for _, v := range o.outboundTransports {
@@ -155,22 +230,105 @@ func (o *OutboundDispatcher) Forward(msg interface{}, des *service.Destination)
 	return fmt.Errorf("outboundDispatcher.Forward: no transport found for serviceEndpoint: %s", des.ServiceEndpoint)
 }
 
-func (o *OutboundDispatcher) createForwardMessage(msg []byte, des *service.Destination) ([]byte, error) {
+// senderKey returns the sender key of the envelope: the public key of the did:key for DIDComm V1 or the kid
+// (DID URL) for DIDComm V2.
+func senderKey(senderVerKey string, des *service.Destination) ([]byte, error) {
+	if des.DIDCommVersion == service.V2 && !strings.HasPrefix(senderVerKey, "did:key:") {
+		return []byte(senderVerKey), nil
+	}
+
+	return fingerprint.PubKeyFromDIDKey(senderVerKey)
+}
+
+// withDIDCommVersion returns the destination with the DIDComm version of the message. The destinations of an unknown
+// version which recipient keys are key agreement DID URLs get the DIDComm V2 messages in DIDComm V2 envelopes.
+func withDIDCommVersion(msg []byte, des *service.Destination) *service.Destination {
+	if des.DIDCommVersion != "" || len(des.RecipientKeys) == 0 {
+		return des
+	}
+
+	for _, key := range des.RecipientKeys {
+		if strings.HasPrefix(key, "did:key:") || !strings.Contains(key, "#") {
+			return des
+		}
+	}
+
+	didCommMsg, err := service.ParseDIDCommMsgMap(msg)
+	if err != nil || !didCommMsg.IsDIDCommV2() {
+		return des
+	}
+
+	v2 := *des
+	v2.DIDCommVersion = service.V2
+
+	return &v2
+}
+
+// mediaType returns the media type of the envelopes sent to the destination, empty for the default one.
+func mediaType(des *service.Destination) string {
+	if des.DIDCommVersion == service.V2 {
+		return commontransport.MediaTypeV2EncryptedEnvelope
+	}
+
+	return ""
+}
+
+// createForwardMessage wraps the packed message in a forward message for each routing key in order, the first routing
+// key being the mediator closest to the recipient and the last one the first hop.
+func (o *OutboundDispatcher) createForwardMessage(msg, senderKey []byte, des *service.Destination) ([]byte, error) {
 	if len(des.RoutingKeys) == 0 {
 		return msg, nil
 	}
 
+	var fromKey []byte
+	if o.forwardPacking == AuthcryptForward {
+		fromKey = senderKey
+	}
+
+	to := des.RecipientKeys[0]
+
+	for _, routingKey := range des.RoutingKeys {
+		var (
+			req []byte
+			err error
+		)
+
+		if des.DIDCommVersion == service.V2 {
+			req, err = createForwardV2(msg, to, routingKey)
+		} else {
+			req, err = createForward(msg, to)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		msg, err = o.packager.PackMessage(&commontransport.Envelope{
+			MediaType: mediaType(des), Message: req, FromKey: fromKey, ToKeys: []string{routingKey},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to pack forward msg: %w", err)
+		}
+
+		to = routingKey
+	}
+
+	return msg, nil
+}
+
+func createForward(msg []byte, to string) ([]byte, error) {
 	env := &model.Envelope{}
 
 	err := json.Unmarshal(msg, env)
 	if err != nil {
 		return nil, fmt.Errorf("unmarshal envelope : %w", err)
 	}
+
 	// create forward message
 	forward := &model.Forward{
 		Type: service.ForwardMsgType,
 		ID:   uuid.New().String(),
-		To:   des.RecipientKeys[0],
+		To:   to,
 		Msg:  env,
 	}
 
@@ -180,22 +338,33 @@ func (o *OutboundDispatcher) createForwardMessage(msg []byte, des *service.Desti
 		return nil, fmt.Errorf("failed marshal to bytes: %w", err)
 	}
 
-	// create key set
-	_, senderVerKey, err := o.kms.CreateAndExportPubKeyBytes(kms.ED25519Type)
-	if err != nil {
-		return nil, fmt.Errorf("failed Create and export SigningKey: %w", err)
+	return req, nil
+}
+
+// createForwardV2 creates the DIDComm V2 forward message, the envelope is attached to it.
+func createForwardV2(msg []byte, next, routingKey string) ([]byte, error) {
+	if !json.Valid(msg) {
+		return nil, errors.New("unmarshal envelope : invalid JSON envelope")
 	}
 
-	// pack above message using auth crypt
-	// TODO https://github.com/hyperledger/aries-framework-go/issues/1112 Configurable packing
-	//  algorithm(auth/anon crypt) for Forward(router) message
-	packedMsg, err := o.packager.PackMessage(
-		&commontransport.Envelope{Message: req, FromKey: senderVerKey, ToKeys: des.RoutingKeys})
+	forward := &model.ForwardV2{
+		ID:   uuid.New().String(),
+		Type: service.ForwardV2MsgType,
+		To:   []string{strings.Split(routingKey, "#")[0]},
+		Body: model.ForwardV2Body{Next: next},
+		Attachments: []model.AttachmentV2{{
+			ID:        uuid.New().String(),
+			MediaType: commontransport.MediaTypeV2EncryptedEnvelope,
+			Data:      model.AttachmentV2Data{JSON: msg},
+		}},
+	}
+
+	req, err := json.Marshal(forward)
 	if err != nil {
-		return nil, fmt.Errorf("failed to pack forward msg: %w", err)
+		return nil, fmt.Errorf("failed marshal to bytes: %w", err)
 	}
 
-	return packedMsg, nil
+	return req, nil
 }
 
 func (o *OutboundDispatcher) addTransportRouteOptions(req []byte, des *service.Destination) ([]byte, error) {
@@ -207,7 +376,14 @@ func (o *OutboundDispatcher) addTransportRouteOptions(req []byte, des *service.D
 	if o.transportReturnRoute == decorator.TransportReturnRouteAll ||
 		o.transportReturnRoute == decorator.TransportReturnRouteThread {
 		// create the decorator with the option set in the framework
-		transportDec := &decorator.Transport{ReturnRoute: &decorator.ReturnRoute{Value: o.transportReturnRoute}}
+		var transportDec interface{} = &decorator.Transport{
+			ReturnRoute: &decorator.ReturnRoute{Value: o.transportReturnRoute},
+		}
+
+		// DIDComm V2 messages have the return_route header instead of the ~transport decorator
+		if des.DIDCommVersion == service.V2 {
+			transportDec = map[string]string{returnRouteV2: o.transportReturnRoute}
+		}
 
 		transportDecJSON, jsonErr := json.Marshal(transportDec)
 		if jsonErr != nil {
@@ -21,11 +21,9 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/transport"
 	vdrapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
-	"github.com/hyperledger/aries-framework-go/pkg/kms"
 	mockdidcomm "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm"
 	mockpackager "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/packager"
 	mockdiddoc "github.com/hyperledger/aries-framework-go/pkg/mock/diddoc"
-	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
 	mockvdr "github.com/hyperledger/aries-framework-go/pkg/mock/vdr"
 )
 
@@ -83,22 +81,113 @@ func TestOutboundDispatcher_Send(t *testing.T) {
 		}))
 	})
 
-	t.Run("test send with forward message - create key failure", func(t *testing.T) {
+	t.Run("test send with forward message - nested forwards", func(t *testing.T) {
+		packager := &recordingPackager{}
 		o := NewOutbound(&mockProvider{
-			packagerValue:           &mockpackager.Packager{PackValue: createPackedMsgForForward(t)},
+			packagerValue:           packager,
 			outboundTransportsValue: []transport.OutboundTransport{&mockdidcomm.MockOutboundTransport{AcceptValue: true}},
-			kms: &mockkms.KeyManager{
-				CrAndExportPubKeyErr: errors.New("create and export key error"),
-			},
 		})
 
-		err := o.Send("data", mockdiddoc.MockDIDKey(t), &service.Destination{
+		senderKey := mockdiddoc.MockDIDKey(t)
+
+		require.NoError(t, o.Send(map[string]string{"@id": "123"}, senderKey, &service.Destination{
+			ServiceEndpoint: "url",
+			RecipientKeys:   []string{"abc"},
+			RoutingKeys:     []string{"relay", "mediator"},
+		}))
+
+		require.Len(t, packager.envelopes, 3)
+		require.NotEmpty(t, packager.envelopes[0].FromKey)
+		require.Equal(t, []string{"abc"}, packager.envelopes[0].ToKeys)
+
+		for i, expected := range []struct{ to, routingKey string }{{"abc", "relay"}, {"relay", "mediator"}} {
+			env := packager.envelopes[i+1]
+			require.Empty(t, env.FromKey)
+			require.Equal(t, []string{expected.routingKey}, env.ToKeys)
+
+			forward := &model.Forward{}
+			require.NoError(t, json.Unmarshal(env.Message, forward))
+			require.Equal(t, service.ForwardMsgType, forward.Type)
+			require.Equal(t, expected.to, forward.To)
+		}
+	})
+
+	t.Run("test send with forward message - authcrypt forwards", func(t *testing.T) {
+		packager := &recordingPackager{}
+		o := NewOutbound(&mockProvider{
+			packagerValue:           packager,
+			outboundTransportsValue: []transport.OutboundTransport{&mockdidcomm.MockOutboundTransport{AcceptValue: true}},
+		}, WithForwardPacking(AuthcryptForward))
+
+		require.NoError(t, o.Send(map[string]string{"@id": "123"}, mockdiddoc.MockDIDKey(t), &service.Destination{
 			ServiceEndpoint: "url",
 			RecipientKeys:   []string{"abc"},
 			RoutingKeys:     []string{"xyz"},
+		}))
+
+		require.Len(t, packager.envelopes, 2)
+		require.NotEmpty(t, packager.envelopes[1].FromKey)
+		require.Equal(t, packager.envelopes[0].FromKey, packager.envelopes[1].FromKey)
+	})
+
+	t.Run("test send DIDComm V2 message with forward message", func(t *testing.T) {
+		packager := &recordingPackager{}
+		o := NewOutbound(&mockProvider{
+			packagerValue:           packager,
+			outboundTransportsValue: []transport.OutboundTransport{&mockdidcomm.MockOutboundTransport{AcceptValue: true}},
+		})
+
+		require.NoError(t, o.Send(map[string]string{"id": "123", "type": "msg-type"}, "did:example:alice#key-1",
+			&service.Destination{
+				ServiceEndpoint: "url",
+				RecipientKeys:   []string{"did:example:bob#key-1"},
+				RoutingKeys:     []string{"did:example:mediator#key-1"},
+				DIDCommVersion:  service.V2,
+			}))
+
+		require.Len(t, packager.envelopes, 2)
+		require.Equal(t, commontransport.MediaTypeV2EncryptedEnvelope, packager.envelopes[0].MediaType)
+		require.Equal(t, []byte("did:example:alice#key-1"), packager.envelopes[0].FromKey)
+
+		env := packager.envelopes[1]
+		require.Equal(t, commontransport.MediaTypeV2EncryptedEnvelope, env.MediaType)
+		require.Empty(t, env.FromKey)
+		require.Equal(t, []string{"did:example:mediator#key-1"}, env.ToKeys)
+
+		forward := &model.ForwardV2{}
+		require.NoError(t, json.Unmarshal(env.Message, forward))
+		require.Equal(t, service.ForwardV2MsgType, forward.Type)
+		require.Equal(t, []string{"did:example:mediator"}, forward.To)
+		require.Equal(t, "did:example:bob#key-1", forward.Body.Next)
+		require.Len(t, forward.Attachments, 1)
+		require.JSONEq(t, string(packager.envelopes[0].Message), string(forward.Attachments[0].Data.JSON))
+	})
+
+	t.Run("test send DIDComm V2 message to destination of unknown version", func(t *testing.T) {
+		packager := &recordingPackager{}
+		o := NewOutbound(&mockProvider{
+			packagerValue:           packager,
+			outboundTransportsValue: []transport.OutboundTransport{&mockdidcomm.MockOutboundTransport{AcceptValue: true}},
 		})
-		require.EqualError(t, err, "outboundDispatcher.Send: failed to create forward msg : failed Create "+
-			"and export SigningKey: create and export key error")
+
+		des := &service.Destination{
+			ServiceEndpoint: "url",
+			RecipientKeys:   []string{"did:example:bob#key-1"},
+		}
+
+		require.NoError(t, o.Send(map[string]string{"id": "123", "type": "msg-type"}, "did:example:alice#key-1", des))
+		require.Equal(t, commontransport.MediaTypeV2EncryptedEnvelope, packager.envelopes[0].MediaType)
+		require.Equal(t, []byte("did:example:alice#key-1"), packager.envelopes[0].FromKey)
+		require.Empty(t, des.DIDCommVersion)
+
+		// DIDComm V1 messages keep the default envelope
+		require.NoError(t, o.Send(map[string]string{"@id": "123", "@type": "msg-type"}, mockdiddoc.MockDIDKey(t), des))
+		require.Empty(t, packager.envelopes[1].MediaType)
+
+		// did:key recipients are DIDComm V1 destinations
+		require.NoError(t, o.Send(map[string]string{"id": "123", "type": "msg-type"}, mockdiddoc.MockDIDKey(t),
+			&service.Destination{ServiceEndpoint: "url", RecipientKeys: []string{mockdiddoc.MockDIDKey(t)}}))
+		require.Empty(t, packager.envelopes[2].MediaType)
 	})
 
 	t.Run("test send with forward message - packer error", func(t *testing.T) {
@@ -107,7 +196,7 @@ func TestOutboundDispatcher_Send(t *testing.T) {
 			outboundTransportsValue: []transport.OutboundTransport{&mockdidcomm.MockOutboundTransport{AcceptValue: true}},
 		})
 
-		_, err := o.createForwardMessage(createPackedMsgForForward(t), &service.Destination{
+		_, err := o.createForwardMessage(createPackedMsgForForward(t), nil, &service.Destination{
 			ServiceEndpoint: "url",
 			RecipientKeys:   []string{"abc"},
 			RoutingKeys:     []string{"xyz"},
@@ -122,7 +211,7 @@ func TestOutboundDispatcher_Send(t *testing.T) {
 			outboundTransportsValue: []transport.OutboundTransport{},
 		})
 
-		_, err := o.createForwardMessage([]byte("invalid json"), &service.Destination{
+		_, err := o.createForwardMessage([]byte("invalid json"), nil, &service.Destination{
 			ServiceEndpoint: "url",
 			RecipientKeys:   []string{"abc"},
 			RoutingKeys:     []string{"xyz"},
@@ -249,6 +338,29 @@ func TestOutboundDispatcherTransportReturnRoute(t *testing.T) {
 		require.NoError(t, o.Send(req, mockdiddoc.MockDIDKey(t), &service.Destination{ServiceEndpoint: "url"}))
 	})
 
+	t.Run("transport route option - DIDComm V2 message", func(t *testing.T) {
+		packager := &recordingPackager{}
+		o := NewOutbound(&mockProvider{
+			packagerValue:           packager,
+			outboundTransportsValue: []transport.OutboundTransport{&mockdidcomm.MockOutboundTransport{AcceptValue: true}},
+			transportReturnRoute:    decorator.TransportReturnRouteAll,
+		})
+
+		require.NoError(t, o.Send(map[string]string{"id": "123", "type": "msg-type"}, "did:example:alice#key-1",
+			&service.Destination{
+				ServiceEndpoint: "url",
+				RecipientKeys:   []string{"did:example:bob#key-1"},
+				DIDCommVersion:  service.V2,
+			}))
+
+		require.Len(t, packager.envelopes, 1)
+
+		msg := service.DIDCommMsgMap{}
+		require.NoError(t, json.Unmarshal(packager.envelopes[0].Message, &msg))
+		require.Equal(t, decorator.TransportReturnRouteAll, msg["return_route"])
+		require.Nil(t, msg["~transport"])
+	})
+
 	t.Run("transport route option - forward message", func(t *testing.T) {
 		transportReturnRoute := "thread"
 		o := NewOutbound(&mockProvider{
@@ -311,7 +423,6 @@ type mockProvider struct {
 	outboundTransportsValue []transport.OutboundTransport
 	transportReturnRoute    string
 	vdr                     vdrapi.Registry
-	kms                     kms.KeyManager
 }
 
 func (p *mockProvider) Packager() commontransport.Packager {
@@ -330,14 +441,6 @@ func (p *mockProvider) VDRegistry() vdrapi.Registry {
 	return p.vdr
 }
 
-func (p *mockProvider) KMS() kms.KeyManager {
-	if p.kms != nil {
-		return p.kms
-	}
-
-	return &mockkms.KeyManager{}
-}
-
 // mockOutboundTransport mock outbound transport.
 type mockOutboundTransport struct {
 	expectedRequest string
@@ -375,3 +478,18 @@ func (m *mockPackager) PackMessage(e *commontransport.Envelope) ([]byte, error)
 func (m *mockPackager) UnpackMessage(encMessage []byte) (*commontransport.Envelope, error) {
 	return nil, nil
 }
+
+// recordingPackager records the envelopes it packs, the message is returned as is.
+type recordingPackager struct {
+	envelopes []*commontransport.Envelope
+}
+
+func (m *recordingPackager) PackMessage(e *commontransport.Envelope) ([]byte, error) {
+	m.envelopes = append(m.envelopes, e)
+
+	return e.Message, nil
+}
+
+func (m *recordingPackager) UnpackMessage(encMessage []byte) (*commontransport.Envelope, error) {
+	return nil, nil
+}
@@ -0,0 +1,528 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package dispatcher
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/google/uuid"
+
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+)
+
+var logger = log.New("aries-framework/dispatcher")
+
+const (
+	// OutboundQueueStore is the store of the queued outbound messages.
+	OutboundQueueStore = "outbound_queue"
+
+	// DeadLetterStore is the store of the outbound messages which failed to be delivered.
+	DeadLetterStore = "outbound_dead_letter"
+
+	queuedTag     = "queued"
+	deadLetterTag = "dead_letter"
+
+	defaultInitialInterval = time.Second
+	defaultMaxInterval     = 5 * time.Minute
+	defaultMaxAge          = 24 * time.Hour
+	defaultPollInterval    = time.Second
+)
+
+// ErrDeadLetterNotFound dead letter not found error.
+var ErrDeadLetterNotFound = errors.New("dead letter not found")
+
+// DeliveryStatus is the delivery status of the queued outbound message.
+type DeliveryStatus string
+
+// delivery statuses of the queued outbound messages.
+const (
+	// DeliveryQueued the transport failed to send the message, it's queued for the retries.
+	DeliveryQueued DeliveryStatus = "queued"
+
+	// DeliveryRetrying the retry failed, the message stays in the queue.
+	DeliveryRetrying DeliveryStatus = "retrying"
+
+	// DeliveryDelivered the queued message was delivered.
+	DeliveryDelivered DeliveryStatus = "delivered"
+
+	// DeliveryDeadLettered the message exceeded the max age and was moved to the dead letters.
+	DeliveryDeadLettered DeliveryStatus = "dead_lettered"
+)
+
+// DeliveryEvent reports the delivery status of the queued outbound message.
+type DeliveryEvent struct {
+	MessageID       string         `json:"message_id"`
+	Status          DeliveryStatus `json:"status"`
+	ServiceEndpoint string         `json:"service_endpoint"`
+	Attempts        int            `json:"attempts"`
+	Error           string         `json:"error,omitempty"`
+}
+
+// QueuedMessage is the packed outbound message waiting for the delivery.
+type QueuedMessage struct {
+	ID              string               `json:"id"`
+	Message         []byte               `json:"message"`
+	Destination     *service.Destination `json:"destination"`
+	Attempts        int                  `json:"attempts"`
+	CreatedTime     time.Time            `json:"created_time"`
+	LastAttemptTime time.Time            `json:"last_attempt_time"`
+	LastError       string               `json:"last_error,omitempty"`
+}
+
+// QueueOption configures the outbound queue.
+type QueueOption func(q *OutboundQueue)
+
+// WithRetryInterval sets the initial and the max intervals of the exponential backoff between the retries.
+func WithRetryInterval(initial, max time.Duration) QueueOption {
+	return func(q *OutboundQueue) {
+		q.initialInterval = initial
+		q.maxInterval = max
+	}
+}
+
+// WithMaxAge sets the age after which the undelivered messages are moved to the dead letters.
+func WithMaxAge(maxAge time.Duration) QueueOption {
+	return func(q *OutboundQueue) {
+		q.maxAge = maxAge
+	}
+}
+
+// WithPollInterval sets how often the queue looks for the messages to retry.
+func WithPollInterval(interval time.Duration) QueueOption {
+	return func(q *OutboundQueue) {
+		q.pollInterval = interval
+	}
+}
+
+// OutboundQueue persists the packed messages the outbound transports failed to send and retries them with
+// the exponential backoff per destination. The messages older than the max age are moved to the dead letters,
+// which can be replayed later.
+type OutboundQueue struct {
+	queueStore      storage.Store
+	deadLetterStore storage.Store
+	initialInterval time.Duration
+	maxInterval     time.Duration
+	maxAge          time.Duration
+	pollInterval    time.Duration
+	send            func([]byte, *service.Destination) error
+	// destinations holds the backoff of the destinations which failed, the state isn't persisted so the queued
+	// messages are retried right away once the agent restarts.
+	destinations map[string]*destinationState
+	processLock  sync.Mutex
+	events       []chan<- DeliveryEvent
+	eventsLock   sync.RWMutex
+	stop         chan struct{}
+	stopOnce     sync.Once
+}
+
+type destinationState struct {
+	backoff *backoff.ExponentialBackOff
+	next    time.Time
+}
+
+// NewOutboundQueue returns new outbound queue instance.
+func NewOutboundQueue(prov storage.Provider, opts ...QueueOption) (*OutboundQueue, error) {
+	queueStore, err := prov.OpenStore(OutboundQueueStore)
+	if err != nil {
+		return nil, fmt.Errorf("open outbound queue store: %w", err)
+	}
+
+	err = prov.SetStoreConfig(OutboundQueueStore, storage.StoreConfiguration{TagNames: []string{queuedTag}})
+	if err != nil {
+		return nil, fmt.Errorf("set outbound queue store configuration: %w", err)
+	}
+
+	deadLetterStore, err := prov.OpenStore(DeadLetterStore)
+	if err != nil {
+		return nil, fmt.Errorf("open dead letter store: %w", err)
+	}
+
+	err = prov.SetStoreConfig(DeadLetterStore, storage.StoreConfiguration{TagNames: []string{deadLetterTag}})
+	if err != nil {
+		return nil, fmt.Errorf("set dead letter store configuration: %w", err)
+	}
+
+	q := &OutboundQueue{
+		queueStore:      queueStore,
+		deadLetterStore: deadLetterStore,
+		initialInterval: defaultInitialInterval,
+		maxInterval:     defaultMaxInterval,
+		maxAge:          defaultMaxAge,
+		pollInterval:    defaultPollInterval,
+		destinations:    make(map[string]*destinationState),
+		stop:            make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(q)
+	}
+
+	return q, nil
+}
+
+// RegisterDeliveryEvent registers the channel for the delivery events of the queued messages. The queue doesn't
+// wait for the channel, the events are dropped if it is full, so the channel should be buffered.
+func (q *OutboundQueue) RegisterDeliveryEvent(ch chan<- DeliveryEvent) error {
+	if ch == nil {
+		return service.ErrNilChannel
+	}
+
+	q.eventsLock.Lock()
+	q.events = append(q.events, ch)
+	q.eventsLock.Unlock()
+
+	return nil
+}
+
+// UnregisterDeliveryEvent unregisters the channel of the delivery events.
+func (q *OutboundQueue) UnregisterDeliveryEvent(ch chan<- DeliveryEvent) error {
+	q.eventsLock.Lock()
+	defer q.eventsLock.Unlock()
+
+	for i := 0; i < len(q.events); i++ {
+		if q.events[i] == ch {
+			q.events = append(q.events[:i], q.events[i+1:]...)
+			i--
+		}
+	}
+
+	return nil
+}
+
+// DeadLetters returns the messages which failed to be delivered, the oldest first.
+func (q *OutboundQueue) DeadLetters() ([]*QueuedMessage, error) {
+	return queryMessages(q.deadLetterStore, deadLetterTag)
+}
+
+// Replay moves the dead letter back to the queue, the max age of the message starts over.
+func (q *OutboundQueue) Replay(id string) error {
+	q.processLock.Lock()
+	defer q.processLock.Unlock()
+
+	msg, err := getMessage(q.deadLetterStore, id)
+	if err != nil {
+		return err
+	}
+
+	msg.CreatedTime = time.Now()
+
+	err = putMessage(q.queueStore, queuedTag, msg)
+	if err != nil {
+		return fmt.Errorf("queue message: %w", err)
+	}
+
+	// the destination is retried on the next poll
+	delete(q.destinations, msg.Destination.ServiceEndpoint)
+
+	return q.deadLetterStore.Delete(id)
+}
+
+// RemoveDeadLetter removes the dead letter.
+func (q *OutboundQueue) RemoveDeadLetter(id string) error {
+	_, err := getMessage(q.deadLetterStore, id)
+	if err != nil {
+		return err
+	}
+
+	return q.deadLetterStore.Delete(id)
+}
+
+// Close stops retrying the queued messages.
+func (q *OutboundQueue) Close() error {
+	q.stopOnce.Do(func() {
+		close(q.stop)
+	})
+
+	return nil
+}
+
+// start starts retrying the queued messages with the given send function.
+func (q *OutboundQueue) start(send func([]byte, *service.Destination) error) {
+	q.send = send
+
+	go func() {
+		ticker := time.NewTicker(q.pollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-q.stop:
+				return
+			case <-ticker.C:
+				q.notify(q.process()...)
+			}
+		}
+	}()
+}
+
+// enqueue queues the message the transport failed to send.
+func (q *OutboundQueue) enqueue(packedMsg []byte, des *service.Destination, sendErr error) error {
+	dest := *des
+
+	msg := &QueuedMessage{
+		ID:              uuid.New().String(),
+		Message:         packedMsg,
+		Destination:     &dest,
+		Attempts:        1,
+		CreatedTime:     time.Now(),
+		LastAttemptTime: time.Now(),
+		LastError:       sendErr.Error(),
+	}
+
+	q.processLock.Lock()
+
+	q.failed(dest.ServiceEndpoint)
+
+	err := putMessage(q.queueStore, queuedTag, msg)
+
+	q.processLock.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	logger.Debugf("queued outbound message id=%s for endpoint %s : %s", msg.ID, dest.ServiceEndpoint, sendErr)
+
+	q.notify(toEvent(msg, DeliveryQueued))
+
+	return nil
+}
+
+// process retries the queued messages which are due and returns the delivery events. The messages are sent without
+// holding the process lock, so the transports don't stall the queue.
+func (q *OutboundQueue) process() []DeliveryEvent {
+	due, events := q.dueMessages()
+
+	// the remaining messages to the endpoint wait for the next attempt once a message to the endpoint failed
+	failed := make(map[string]bool)
+
+	for _, msg := range due {
+		endpoint := msg.Destination.ServiceEndpoint
+		if failed[endpoint] {
+			continue
+		}
+
+		msg.Attempts++
+		msg.LastAttemptTime = time.Now()
+
+		err := q.send(msg.Message, msg.Destination)
+		if err != nil {
+			failed[endpoint] = true
+		}
+
+		events = append(events, q.attempted(msg, err))
+	}
+
+	return events
+}
+
+// dueMessages returns the queued messages which are due for the retry, the expired messages are moved to the dead
+// letters and reported by the returned events.
+func (q *OutboundQueue) dueMessages() ([]*QueuedMessage, []DeliveryEvent) {
+	q.processLock.Lock()
+	defer q.processLock.Unlock()
+
+	msgs, err := queryMessages(q.queueStore, queuedTag)
+	if err != nil {
+		logger.Errorf("failed to get the queued messages: %s", err)
+
+		return nil, nil
+	}
+
+	var (
+		due    []*QueuedMessage
+		events []DeliveryEvent
+	)
+
+	for _, msg := range msgs {
+		if time.Since(msg.CreatedTime) > q.maxAge {
+			err = q.deadLetter(msg)
+			if err != nil {
+				logger.Errorf("failed to move the message id=%s to the dead letters: %s", msg.ID, err)
+
+				continue
+			}
+
+			events = append(events, toEvent(msg, DeliveryDeadLettered))
+
+			continue
+		}
+
+		if state, ok := q.destinations[msg.Destination.ServiceEndpoint]; ok && time.Now().Before(state.next) {
+			continue
+		}
+
+		due = append(due, msg)
+	}
+
+	return due, events
+}
+
+// attempted updates the queue with the result of the retry of the message and returns its delivery event.
+func (q *OutboundQueue) attempted(msg *QueuedMessage, sendErr error) DeliveryEvent {
+	q.processLock.Lock()
+	defer q.processLock.Unlock()
+
+	endpoint := msg.Destination.ServiceEndpoint
+
+	if sendErr == nil {
+		delete(q.destinations, endpoint)
+
+		err := q.queueStore.Delete(msg.ID)
+		if err != nil {
+			logger.Errorf("failed to remove the delivered message id=%s: %s", msg.ID, err)
+		}
+
+		return toEvent(msg, DeliveryDelivered)
+	}
+
+	msg.LastError = sendErr.Error()
+
+	q.failed(endpoint)
+
+	err := putMessage(q.queueStore, queuedTag, msg)
+	if err != nil {
+		logger.Errorf("failed to update the queued message id=%s: %s", msg.ID, err)
+	}
+
+	return toEvent(msg, DeliveryRetrying)
+}
+
+// failed schedules the next attempt to the endpoint according to the backoff.
+func (q *OutboundQueue) failed(endpoint string) {
+	state, ok := q.destinations[endpoint]
+	if !ok {
+		b := backoff.NewExponentialBackOff()
+		b.InitialInterval = q.initialInterval
+		b.MaxInterval = q.maxInterval
+		// the max age of the messages limits the retries
+		b.MaxElapsedTime = 0
+		b.Reset()
+
+		state = &destinationState{backoff: b}
+		q.destinations[endpoint] = state
+	}
+
+	state.next = time.Now().Add(state.backoff.NextBackOff())
+}
+
+func (q *OutboundQueue) deadLetter(msg *QueuedMessage) error {
+	err := putMessage(q.deadLetterStore, deadLetterTag, msg)
+	if err != nil {
+		return err
+	}
+
+	return q.queueStore.Delete(msg.ID)
+}
+
+// notify sends the events to the registered channels without blocking, the event is dropped if the channel is full.
+func (q *OutboundQueue) notify(events ...DeliveryEvent) {
+	q.eventsLock.RLock()
+	handlers := append(q.events[:0:0], q.events...)
+	q.eventsLock.RUnlock()
+
+	for _, event := range events {
+		for _, handler := range handlers {
+			select {
+			case handler <- event:
+			default:
+				logger.Warnf("dropping the delivery event of the message id=%s: the channel is full", event.MessageID)
+			}
+		}
+	}
+}
+
+func toEvent(msg *QueuedMessage, status DeliveryStatus) DeliveryEvent {
+	return DeliveryEvent{
+		MessageID:       msg.ID,
+		Status:          status,
+		ServiceEndpoint: msg.Destination.ServiceEndpoint,
+		Attempts:        msg.Attempts,
+		Error:           msg.LastError,
+	}
+}
+
+func getMessage(store storage.Store, id string) (*QueuedMessage, error) {
+	src, err := store.Get(id)
+	if errors.Is(err, storage.ErrDataNotFound) {
+		return nil, ErrDeadLetterNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("get message: %w", err)
+	}
+
+	msg := &QueuedMessage{}
+
+	err = json.Unmarshal(src, msg)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal message: %w", err)
+	}
+
+	return msg, nil
+}
+
+func putMessage(store storage.Store, tag string, msg *QueuedMessage) error {
+	src, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("marshal message: %w", err)
+	}
+
+	return store.Put(msg.ID, src, storage.Tag{Name: tag})
+}
+
+// queryMessages returns the messages of the store, the oldest first.
+func queryMessages(store storage.Store, tag string) ([]*QueuedMessage, error) {
+	records, err := store.Query(tag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query messages: %w", err)
+	}
+
+	defer storage.Close(records, logger)
+
+	var msgs []*QueuedMessage
+
+	more, err := records.Next()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get next record: %w", err)
+	}
+
+	for more {
+		value, err := records.Value()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get value from records: %w", err)
+		}
+
+		msg := &QueuedMessage{}
+
+		err = json.Unmarshal(value, msg)
+		if err != nil {
+			return nil, fmt.Errorf("unmarshal message: %w", err)
+		}
+
+		msgs = append(msgs, msg)
+
+		more, err = records.Next()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get next record: %w", err)
+		}
+	}
+
+	sort.SliceStable(msgs, func(i, j int) bool {
+		return msgs[i].CreatedTime.Before(msgs[j].CreatedTime)
+	})
+
+	return msgs, nil
+}
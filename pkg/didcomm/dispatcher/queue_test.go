@@ -0,0 +1,305 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package dispatcher
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/transport"
+	mockpackager "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/packager"
+	mockdiddoc "github.com/hyperledger/aries-framework-go/pkg/mock/diddoc"
+	mockstore "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+)
+
+func TestNewOutboundQueue(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		q, err := NewOutboundQueue(mem.NewProvider(), WithMaxAge(time.Hour),
+			WithRetryInterval(time.Millisecond, time.Second), WithPollInterval(time.Millisecond))
+		require.NoError(t, err)
+		require.Equal(t, time.Hour, q.maxAge)
+		require.Equal(t, time.Millisecond, q.initialInterval)
+		require.Equal(t, time.Second, q.maxInterval)
+		require.Equal(t, time.Millisecond, q.pollInterval)
+	})
+
+	t.Run("open store error", func(t *testing.T) {
+		_, err := NewOutboundQueue(&mockstore.MockStoreProvider{ErrOpenStoreHandle: errors.New("open error")})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "open outbound queue store: open error")
+	})
+}
+
+func TestOutboundQueue(t *testing.T) {
+	t.Run("queued message is delivered once the destination is up", func(t *testing.T) {
+		outbound := &flakyTransport{fail: true}
+		q, o := newQueuedOutbound(t, outbound, WithMaxAge(time.Hour))
+
+		events := make(chan DeliveryEvent, 100)
+		require.NoError(t, q.RegisterDeliveryEvent(events))
+
+		go func() {
+			require.NoError(t, o.Send("data", mockdiddoc.MockDIDKey(t), &service.Destination{ServiceEndpoint: "url"}))
+		}()
+
+		event := nextEvent(t, events)
+		require.Equal(t, DeliveryQueued, event.Status)
+		require.Equal(t, "url", event.ServiceEndpoint)
+		require.Equal(t, 1, event.Attempts)
+		require.Equal(t, "send error", event.Error)
+
+		event = nextEvent(t, events)
+		require.Equal(t, DeliveryRetrying, event.Status)
+		require.Equal(t, 2, event.Attempts)
+
+		outbound.setFail(false)
+
+		for event.Status == DeliveryRetrying {
+			event = nextEvent(t, events)
+		}
+
+		require.Equal(t, DeliveryDelivered, event.Status)
+
+		msgs, err := queryMessages(q.queueStore, queuedTag)
+		require.NoError(t, err)
+		require.Empty(t, msgs)
+
+		require.NoError(t, q.UnregisterDeliveryEvent(events))
+	})
+
+	t.Run("expired message is moved to the dead letters and replayed", func(t *testing.T) {
+		outbound := &flakyTransport{fail: true}
+		q, o := newQueuedOutbound(t, outbound, WithMaxAge(50*time.Millisecond))
+
+		events := make(chan DeliveryEvent, 100)
+		require.NoError(t, q.RegisterDeliveryEvent(events))
+
+		go func() {
+			require.NoError(t, o.Send("data", mockdiddoc.MockDIDKey(t), &service.Destination{ServiceEndpoint: "url"}))
+		}()
+
+		var event DeliveryEvent
+		for event.Status != DeliveryDeadLettered {
+			event = nextEvent(t, events)
+		}
+
+		deadLetters, err := q.DeadLetters()
+		require.NoError(t, err)
+		require.Len(t, deadLetters, 1)
+		require.Equal(t, event.MessageID, deadLetters[0].ID)
+		require.Equal(t, "url", deadLetters[0].Destination.ServiceEndpoint)
+		require.Equal(t, "send error", deadLetters[0].LastError)
+
+		q.processLock.Lock()
+		q.maxAge = time.Hour
+		q.processLock.Unlock()
+
+		outbound.setFail(false)
+
+		require.NoError(t, q.Replay(event.MessageID))
+
+		for event.Status != DeliveryDelivered {
+			event = nextEvent(t, events)
+		}
+
+		deadLetters, err = q.DeadLetters()
+		require.NoError(t, err)
+		require.Empty(t, deadLetters)
+
+		require.True(t, errors.Is(q.Replay(event.MessageID), ErrDeadLetterNotFound))
+	})
+
+	t.Run("remove dead letter", func(t *testing.T) {
+		q, err := NewOutboundQueue(mem.NewProvider())
+		require.NoError(t, err)
+
+		msg := &QueuedMessage{ID: "msg-1", Destination: &service.Destination{ServiceEndpoint: "url"}}
+		require.NoError(t, q.deadLetter(msg))
+
+		require.NoError(t, q.RemoveDeadLetter("msg-1"))
+		require.True(t, errors.Is(q.RemoveDeadLetter("msg-1"), ErrDeadLetterNotFound))
+	})
+
+	t.Run("backoff per destination", func(t *testing.T) {
+		q, err := NewOutboundQueue(mem.NewProvider(), WithRetryInterval(time.Hour, time.Hour))
+		require.NoError(t, err)
+
+		var sent []string
+
+		q.send = func(_ []byte, des *service.Destination) error {
+			sent = append(sent, des.ServiceEndpoint)
+
+			return nil
+		}
+
+		require.NoError(t, q.enqueue([]byte("msg-1"), &service.Destination{ServiceEndpoint: "down"}, errors.New("down")))
+		require.NoError(t, q.enqueue([]byte("msg-2"), &service.Destination{ServiceEndpoint: "up"}, errors.New("down")))
+
+		// the destination which recovered is retried once its backoff passes
+		q.destinations["up"].next = time.Now()
+
+		events := q.process()
+		require.Len(t, events, 1)
+		require.Equal(t, DeliveryDelivered, events[0].Status)
+		require.Equal(t, []string{"up"}, sent)
+	})
+
+	t.Run("messages to the failed destination wait for the next attempt", func(t *testing.T) {
+		q, err := NewOutboundQueue(mem.NewProvider(), WithRetryInterval(time.Hour, time.Hour))
+		require.NoError(t, err)
+
+		var sent int
+
+		q.send = func([]byte, *service.Destination) error {
+			sent++
+
+			return errors.New("down")
+		}
+
+		require.NoError(t, q.enqueue([]byte("msg-1"), &service.Destination{ServiceEndpoint: "down"}, errors.New("down")))
+		require.NoError(t, q.enqueue([]byte("msg-2"), &service.Destination{ServiceEndpoint: "down"}, errors.New("down")))
+
+		q.destinations["down"].next = time.Now()
+
+		events := q.process()
+		require.Len(t, events, 1)
+		require.Equal(t, DeliveryRetrying, events[0].Status)
+		require.Equal(t, 1, sent)
+	})
+
+	t.Run("send does not hold the queue", func(t *testing.T) {
+		q, err := NewOutboundQueue(mem.NewProvider())
+		require.NoError(t, err)
+
+		sending, release := make(chan struct{}), make(chan struct{})
+
+		q.send = func([]byte, *service.Destination) error {
+			close(sending)
+			<-release
+
+			return nil
+		}
+
+		require.NoError(t, q.enqueue([]byte("msg-1"), &service.Destination{ServiceEndpoint: "url"}, errors.New("down")))
+		delete(q.destinations, "url")
+
+		done := make(chan []DeliveryEvent)
+
+		go func() {
+			done <- q.process()
+		}()
+
+		<-sending
+
+		// the queue accepts the new messages while the retry is being sent
+		require.NoError(t, q.enqueue([]byte("msg-2"), &service.Destination{ServiceEndpoint: "other"}, errors.New("down")))
+
+		close(release)
+
+		events := <-done
+		require.Len(t, events, 1)
+		require.Equal(t, DeliveryDelivered, events[0].Status)
+	})
+
+	t.Run("notify does not wait for the channels", func(t *testing.T) {
+		q, err := NewOutboundQueue(mem.NewProvider())
+		require.NoError(t, err)
+
+		events := make(chan DeliveryEvent)
+		require.NoError(t, q.RegisterDeliveryEvent(events))
+
+		q.notify(DeliveryEvent{MessageID: "msg-1", Status: DeliveryQueued})
+
+		select {
+		case <-events:
+			require.Fail(t, "the event should be dropped")
+		default:
+		}
+	})
+
+	t.Run("register nil channel", func(t *testing.T) {
+		q, err := NewOutboundQueue(mem.NewProvider())
+		require.NoError(t, err)
+		require.True(t, errors.Is(q.RegisterDeliveryEvent(nil), service.ErrNilChannel))
+	})
+}
+
+func newQueuedOutbound(t *testing.T, outbound transport.OutboundTransport,
+	opts ...QueueOption) (*OutboundQueue, *OutboundDispatcher) {
+	t.Helper()
+
+	q, err := NewOutboundQueue(mem.NewProvider(),
+		append([]QueueOption{WithPollInterval(time.Millisecond), WithRetryInterval(time.Millisecond, 10*time.Millisecond)},
+			opts...)...)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		require.NoError(t, q.Close())
+	})
+
+	o := NewOutbound(&mockProvider{
+		packagerValue:           &mockpackager.Packager{},
+		outboundTransportsValue: []transport.OutboundTransport{outbound},
+	}, WithOutboundQueue(q))
+
+	return q, o
+}
+
+func nextEvent(t *testing.T, events chan DeliveryEvent) DeliveryEvent {
+	t.Helper()
+
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "timeout waiting for the delivery event")
+	}
+
+	return DeliveryEvent{}
+}
+
+// flakyTransport fails to send the messages until it's told otherwise.
+type flakyTransport struct {
+	mu   sync.Mutex
+	fail bool
+}
+
+func (f *flakyTransport) setFail(fail bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.fail = fail
+}
+
+func (f *flakyTransport) Start(transport.Provider) error {
+	return nil
+}
+
+func (f *flakyTransport) Send([]byte, *service.Destination) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.fail {
+		return "", errors.New("send error")
+	}
+
+	return "", nil
+}
+
+func (f *flakyTransport) AcceptRecipient([]string) bool {
+	return false
+}
+
+func (f *flakyTransport) Accept(string) bool {
+	return true
+}
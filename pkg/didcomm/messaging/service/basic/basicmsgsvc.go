@@ -95,6 +95,11 @@ func (m *MessageService) Accept(msgType string, purpose []string) bool {
 	return msgType == MessageRequestType
 }
 
+// MessageTypes returns the message types handled by the basic message service.
+func (m *MessageService) MessageTypes() []string {
+	return []string{MessageRequestType}
+}
+
 // HandleInbound for basic message service.
 func (m *MessageService) HandleInbound(msg service.DIDCommMsg, myDID, theirDID string) (string, error) {
 	basicMsg := Message{}
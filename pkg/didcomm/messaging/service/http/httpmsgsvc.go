@@ -134,6 +134,11 @@ func (m *OverDIDComm) Accept(msgType string, purpose []string) bool {
 	return false
 }
 
+// MessageTypes returns the message types handled by the HTTP over DIDComm message service.
+func (m *OverDIDComm) MessageTypes() []string {
+	return []string{OverDIDCommMsgRequestType}
+}
+
 // HandleInbound for HTTP over DIDComm message service.
 func (m *OverDIDComm) HandleInbound(msg service.DIDCommMsg, myDID, theirDID string) (string, error) {
 	svcMsg := httpOverDIDCommMsg{}
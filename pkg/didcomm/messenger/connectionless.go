@@ -0,0 +1,198 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package messenger
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/hyperledger/aries-framework-go/pkg/vdr/fingerprint"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+)
+
+const (
+	// ConnectionlessStore is the name of the store keeping the connectionless threads of the agent.
+	ConnectionlessStore = "connectionless_store"
+
+	jsonService                 = "~service"
+	connectionlessThreadDataKey = "thread_%s"
+)
+
+// connectionlessThread is the record of the thread the agent takes part in without the connection, the key is
+// created for the thread and used by the agent as its return route.
+type connectionlessThread struct {
+	KeyID  string `json:"key_id"`
+	DIDKey string `json:"did_key"`
+	// Started is true if the thread was started by the agent.
+	Started bool `json:"started,omitempty"`
+}
+
+var (
+	// ErrConnectionlessNotSupported is returned when the messenger was created without the KMS and the service
+	// endpoint needed to attach the ~service decorator to the connectionless replies.
+	ErrConnectionlessNotSupported = errors.New("connectionless messaging is not supported")
+	// ErrUnknownConnectionlessThread is returned when the message asks for the reply to its ~service decorator on
+	// the thread the agent neither started nor accepted without the connection.
+	ErrUnknownConnectionlessThread = errors.New("unknown connectionless thread")
+)
+
+// connectionlessProvider is implemented by the providers supporting the connectionless messaging.
+type connectionlessProvider interface {
+	KMS() kms.KeyManager
+	StorageProvider() storage.Provider
+	ServiceEndpoint() string
+}
+
+// NewServiceDecorator starts the connectionless thread and creates its ~service decorator, so the recipient of
+// the message can reply to the given service endpoint without the connection. Each thread gets a new key, the key
+// is kept in the given store along with the thread ID. The replies are sent to the ~service decorator of the other
+// side only on such threads.
+// NOTE: the key is not registered with the router, the agent should be reachable at the service endpoint.
+func NewServiceDecorator(km kms.KeyManager, store storage.Store, serviceEndpoint,
+	thID string) (*decorator.Service, error) {
+	didKey, err := saveConnectionlessThread(km, store, thID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return &decorator.Service{
+		RecipientKeys:   []string{didKey},
+		ServiceEndpoint: serviceEndpoint,
+	}, nil
+}
+
+// AcceptConnectionless accepts the thread of the connectionless message the other side started, e.g. the message
+// received as a QR code, so the agent replies to its ~service decorator. Only the messages the user chose to respond
+// to should be accepted, the ~service decorators of the messages on other threads are ignored.
+func AcceptConnectionless(km kms.KeyManager, store storage.Store, msg service.DIDCommMsgMap) error {
+	thID, err := msg.ThreadID()
+	if err != nil {
+		return fmt.Errorf("get threadID: %w", err)
+	}
+
+	_, err = getConnectionlessThread(store, thID)
+	if err == nil {
+		return nil
+	}
+
+	if !errors.Is(err, storage.ErrDataNotFound) {
+		return err
+	}
+
+	_, err = saveConnectionlessThread(km, store, thID, false)
+
+	return err
+}
+
+// saveConnectionlessThread creates the key of the connectionless thread and saves the thread, the did:key of the key
+// is returned.
+func saveConnectionlessThread(km kms.KeyManager, store storage.Store, thID string, started bool) (string, error) {
+	if thID == "" {
+		return "", errors.New("connectionless thread ID is required")
+	}
+
+	kid, pubKey, err := km.CreateAndExportPubKeyBytes(kms.ED25519Type)
+	if err != nil {
+		return "", fmt.Errorf("create connectionless key: %w", err)
+	}
+
+	didKey, _ := fingerprint.CreateDIDKey(pubKey)
+
+	src, err := json.Marshal(connectionlessThread{KeyID: kid, DIDKey: didKey, Started: started})
+	if err != nil {
+		return "", fmt.Errorf("marshal connectionless thread: %w", err)
+	}
+
+	if err = store.Put(fmt.Sprintf(connectionlessThreadDataKey, thID), src); err != nil {
+		return "", fmt.Errorf("save connectionless thread: %w", err)
+	}
+
+	return didKey, nil
+}
+
+func getConnectionlessThread(store storage.Store, thID string) (*connectionlessThread, error) {
+	src, err := store.Get(fmt.Sprintf(connectionlessThreadDataKey, thID))
+	if err != nil {
+		return nil, err
+	}
+
+	thread := &connectionlessThread{}
+
+	if err = json.Unmarshal(src, thread); err != nil {
+		return nil, fmt.Errorf("unmarshal connectionless thread: %w", err)
+	}
+
+	return thread, nil
+}
+
+// StartedConnectionless checks whether the connectionless thread was started by the agent.
+func (m *Messenger) StartedConnectionless(thID string) (bool, error) {
+	if m.connectionless == nil {
+		return false, nil
+	}
+
+	thread, err := getConnectionlessThread(m.connectionless, thID)
+	if errors.Is(err, storage.ErrDataNotFound) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("get connectionless thread: %w", err)
+	}
+
+	return thread.Started, nil
+}
+
+// serviceDecorator returns the ~service decorator of the message, nil is returned if it is absent.
+func serviceDecorator(msg service.DIDCommMsgMap) (*decorator.Service, error) {
+	svc := struct {
+		Service *decorator.Service `json:"~service,omitempty"`
+	}{}
+
+	if err := msg.Decode(&svc); err != nil {
+		return nil, fmt.Errorf("decode service decorator: %w", err)
+	}
+
+	return svc.Service, nil
+}
+
+// replyConnectionless sends the reply to the return route given by the ~service decorator of the inbound message,
+// the reply carries the ~service decorator with our own return route of the thread. The thread has to be started
+// or accepted by the agent, so the ~service decorators of the unsolicited messages are not used.
+func (m *Messenger) replyConnectionless(thID string, out service.DIDCommMsgMap, theirs *decorator.Service) error {
+	if len(theirs.RecipientKeys) == 0 || theirs.ServiceEndpoint == "" {
+		return errors.New("service decorator: recipient keys and service endpoint are required")
+	}
+
+	if m.connectionless == nil {
+		return ErrConnectionlessNotSupported
+	}
+
+	thread, err := getConnectionlessThread(m.connectionless, thID)
+	if errors.Is(err, storage.ErrDataNotFound) {
+		return fmt.Errorf("%w: %s", ErrUnknownConnectionlessThread, thID)
+	}
+
+	if err != nil {
+		return fmt.Errorf("get connectionless thread: %w", err)
+	}
+
+	out[jsonService] = &decorator.Service{
+		RecipientKeys:   []string{thread.DIDKey},
+		ServiceEndpoint: m.serviceEndpoint,
+	}
+
+	return m.dispatcher.Send(out, thread.DIDKey, &service.Destination{
+		RecipientKeys:   theirs.RecipientKeys,
+		RoutingKeys:     theirs.RoutingKeys,
+		ServiceEndpoint: theirs.ServiceEndpoint,
+	})
+}
@@ -0,0 +1,319 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package messenger
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/dispatcher"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	mockdispatcher "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/dispatcher"
+	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
+	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/vdr/fingerprint"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+)
+
+const endpoint = "https://example.com/didcomm"
+
+type connectionlessProviderMock struct {
+	outbound dispatcher.Outbound
+	kms      kms.KeyManager
+	store    storage.Provider
+}
+
+func (p *connectionlessProviderMock) OutboundDispatcher() dispatcher.Outbound {
+	return p.outbound
+}
+
+func (p *connectionlessProviderMock) StorageProvider() storage.Provider {
+	if p.store == nil {
+		p.store = mem.NewProvider()
+	}
+
+	return p.store
+}
+
+func (p *connectionlessProviderMock) KMS() kms.KeyManager {
+	return p.kms
+}
+
+func (p *connectionlessProviderMock) ServiceEndpoint() string {
+	return endpoint
+}
+
+// providerMock doesn't support the connectionless messaging.
+type providerMock struct{}
+
+func (p *providerMock) OutboundDispatcher() dispatcher.Outbound {
+	return nil
+}
+
+func (p *providerMock) StorageProvider() storage.Provider {
+	return mem.NewProvider()
+}
+
+func newStore() storage.Store {
+	return &mockstorage.MockStore{Store: map[string]mockstorage.DBEntry{}}
+}
+
+func TestNewServiceDecorator(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		pubKey, _, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		svc, err := NewServiceDecorator(&mockkms.KeyManager{CrAndExportPubKeyValue: pubKey}, newStore(), endpoint, ID)
+		require.NoError(t, err)
+
+		didKey, _ := fingerprint.CreateDIDKey(pubKey)
+		require.Equal(t, &decorator.Service{RecipientKeys: []string{didKey}, ServiceEndpoint: endpoint}, svc)
+	})
+
+	t.Run("new key for each thread", func(t *testing.T) {
+		store := newStore()
+
+		first, err := NewServiceDecorator(&mockkms.KeyManager{CrAndExportPubKeyValue: newPubKey(t)}, store,
+			endpoint, "thread-1")
+		require.NoError(t, err)
+
+		second, err := NewServiceDecorator(&mockkms.KeyManager{CrAndExportPubKeyValue: newPubKey(t)}, store,
+			endpoint, "thread-2")
+		require.NoError(t, err)
+		require.NotEqual(t, first.RecipientKeys, second.RecipientKeys)
+	})
+
+	t.Run("thread ID is required", func(t *testing.T) {
+		_, err := NewServiceDecorator(&mockkms.KeyManager{}, newStore(), endpoint, "")
+		require.EqualError(t, err, "connectionless thread ID is required")
+	})
+
+	t.Run("create key error", func(t *testing.T) {
+		_, err := NewServiceDecorator(&mockkms.KeyManager{CrAndExportPubKeyErr: errors.New(errMsg)},
+			newStore(), endpoint, ID)
+		require.EqualError(t, err, "create connectionless key: "+errMsg)
+	})
+
+	t.Run("store error", func(t *testing.T) {
+		_, err := NewServiceDecorator(&mockkms.KeyManager{CrAndExportPubKeyValue: newPubKey(t)},
+			&mockstorage.MockStore{Store: map[string]mockstorage.DBEntry{}, ErrPut: errors.New(errMsg)}, endpoint, ID)
+		require.EqualError(t, err, "save connectionless thread: "+errMsg)
+	})
+}
+
+func TestAcceptConnectionless(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		store := newStore()
+		msg := service.DIDCommMsgMap{jsonID: ID}
+
+		require.NoError(t, AcceptConnectionless(&mockkms.KeyManager{CrAndExportPubKeyValue: newPubKey(t)}, store, msg))
+
+		thread, err := getConnectionlessThread(store, ID)
+		require.NoError(t, err)
+		require.False(t, thread.Started)
+
+		// the accepted thread keeps its key
+		require.NoError(t, AcceptConnectionless(&mockkms.KeyManager{
+			CrAndExportPubKeyErr: errors.New("unexpected key creation"),
+		}, store, msg))
+	})
+
+	t.Run("thread ID error", func(t *testing.T) {
+		err := AcceptConnectionless(&mockkms.KeyManager{}, newStore(), service.DIDCommMsgMap{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "get threadID")
+	})
+
+	t.Run("store error", func(t *testing.T) {
+		err := AcceptConnectionless(&mockkms.KeyManager{}, &mockstorage.MockStore{ErrGet: errors.New(errMsg)},
+			service.DIDCommMsgMap{jsonID: ID})
+		require.EqualError(t, err, errMsg)
+	})
+}
+
+func TestMessenger_Connectionless(t *testing.T) {
+	theirs := &decorator.Service{
+		RecipientKeys:   []string{"did:key:their"},
+		RoutingKeys:     []string{"did:key:router"},
+		ServiceEndpoint: "https://their.example.com",
+	}
+
+	t.Run("send is delivered by the caller", func(t *testing.T) {
+		msgr, err := NewMessenger(&connectionlessProviderMock{
+			outbound: &mockdispatcher.MockOutbound{
+				ValidateSendToDID: func(interface{}, string, string) error {
+					return errors.New("unexpected send")
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		msg := service.DIDCommMsgMap{jsonID: ID, jsonService: theirs}
+		require.NoError(t, msgr.Send(msg, "", ""))
+
+		thID, err := msg.ThreadID()
+		require.NoError(t, err)
+		require.Equal(t, ID, thID)
+	})
+
+	t.Run("reply to the service decorator on the started thread", func(t *testing.T) {
+		var (
+			sent   service.DIDCommMsgMap
+			sender string
+			dest   *service.Destination
+		)
+
+		provider := &connectionlessProviderMock{
+			outbound: &mockdispatcher.MockOutbound{
+				ValidateSend: func(msg interface{}, senderVerKey string, des *service.Destination) error {
+					sent, sender, dest = msg.(service.DIDCommMsgMap), senderVerKey, des
+
+					return nil
+				},
+			},
+		}
+
+		msgr, err := NewMessenger(provider)
+		require.NoError(t, err)
+
+		store, err := provider.StorageProvider().OpenStore(ConnectionlessStore)
+		require.NoError(t, err)
+
+		ours, err := NewServiceDecorator(&mockkms.KeyManager{CrAndExportPubKeyValue: newPubKey(t)}, store,
+			endpoint, ID)
+		require.NoError(t, err)
+
+		started, err := msgr.StartedConnectionless(ID)
+		require.NoError(t, err)
+		require.True(t, started)
+
+		in := service.NewDIDCommMsgMap(struct {
+			ID      string             `json:"@id"`
+			Thread  *decorator.Thread  `json:"~thread"`
+			Service *decorator.Service `json:"~service"`
+		}{ID: "reply-id", Thread: &decorator.Thread{ID: ID}, Service: theirs})
+
+		require.NoError(t, msgr.ReplyToMsg(in, service.DIDCommMsgMap{}, "", ""))
+
+		thID, err := sent.ThreadID()
+		require.NoError(t, err)
+		require.Equal(t, ID, thID)
+		require.Equal(t, ours.RecipientKeys[0], sender)
+		require.Equal(t, ours, sent[jsonService])
+		require.Equal(t, &service.Destination{
+			RecipientKeys:   theirs.RecipientKeys,
+			RoutingKeys:     theirs.RoutingKeys,
+			ServiceEndpoint: theirs.ServiceEndpoint,
+		}, dest)
+	})
+
+	t.Run("reply to the service decorator on the accepted thread", func(t *testing.T) {
+		var sender string
+
+		provider := &connectionlessProviderMock{
+			outbound: &mockdispatcher.MockOutbound{
+				ValidateSend: func(msg interface{}, senderVerKey string, des *service.Destination) error {
+					sender = senderVerKey
+
+					return nil
+				},
+			},
+		}
+
+		msgr, err := NewMessenger(provider)
+		require.NoError(t, err)
+
+		store, err := provider.StorageProvider().OpenStore(ConnectionlessStore)
+		require.NoError(t, err)
+
+		pubKey := newPubKey(t)
+		in := service.DIDCommMsgMap{jsonID: ID, jsonService: theirs}
+
+		require.NoError(t, AcceptConnectionless(&mockkms.KeyManager{CrAndExportPubKeyValue: pubKey}, store, in))
+
+		started, err := msgr.StartedConnectionless(ID)
+		require.NoError(t, err)
+		require.False(t, started)
+
+		require.NoError(t, msgr.ReplyToMsg(in, service.DIDCommMsgMap{}, "", ""))
+
+		didKey, _ := fingerprint.CreateDIDKey(pubKey)
+		require.Equal(t, didKey, sender)
+	})
+
+	t.Run("service decorator on the unknown thread is ignored", func(t *testing.T) {
+		msgr, err := NewMessenger(&connectionlessProviderMock{
+			outbound: &mockdispatcher.MockOutbound{
+				ValidateSend: func(interface{}, string, *service.Destination) error {
+					return errors.New("unexpected send")
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		err = msgr.ReplyToMsg(service.DIDCommMsgMap{jsonID: ID, jsonService: theirs}, service.DIDCommMsgMap{}, "", "")
+		require.True(t, errors.Is(err, ErrUnknownConnectionlessThread))
+
+		started, err := msgr.StartedConnectionless(ID)
+		require.NoError(t, err)
+		require.False(t, started)
+	})
+
+	t.Run("not supported", func(t *testing.T) {
+		msgr, err := NewMessenger(&providerMock{})
+		require.NoError(t, err)
+
+		err = msgr.ReplyToMsg(service.DIDCommMsgMap{jsonID: ID, jsonService: theirs}, service.DIDCommMsgMap{}, "", "")
+		require.True(t, errors.Is(err, ErrConnectionlessNotSupported))
+
+		started, err := msgr.StartedConnectionless(ID)
+		require.NoError(t, err)
+		require.False(t, started)
+	})
+
+	t.Run("invalid service decorator", func(t *testing.T) {
+		msgr, err := NewMessenger(&connectionlessProviderMock{})
+		require.NoError(t, err)
+
+		err = msgr.ReplyToMsg(service.DIDCommMsgMap{jsonID: ID, jsonService: "invalid"}, service.DIDCommMsgMap{}, "", "")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "decode service decorator")
+
+		err = msgr.ReplyToMsg(service.DIDCommMsgMap{jsonID: ID, jsonService: &decorator.Service{}},
+			service.DIDCommMsgMap{}, "", "")
+		require.EqualError(t, err, "service decorator: recipient keys and service endpoint are required")
+	})
+
+	t.Run("store error", func(t *testing.T) {
+		msgr, err := NewMessenger(&connectionlessProviderMock{
+			store: mockstorage.NewCustomMockStoreProvider(&mockstorage.MockStore{ErrGet: errors.New(errMsg)}),
+		})
+		require.NoError(t, err)
+
+		err = msgr.ReplyToMsg(service.DIDCommMsgMap{jsonID: ID, jsonService: theirs}, service.DIDCommMsgMap{}, "", "")
+		require.EqualError(t, err, "get connectionless thread: "+errMsg)
+
+		_, err = msgr.StartedConnectionless(ID)
+		require.EqualError(t, err, "get connectionless thread: "+errMsg)
+	})
+}
+
+func newPubKey(t *testing.T) []byte {
+	t.Helper()
+
+	pubKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	return pubKey
+}
@@ -24,6 +24,7 @@ const (
 	MessengerStore = "messenger_store"
 
 	jsonID             = "@id"
+	jsonIDV2           = "id"
 	jsonThread         = "~thread"
 	jsonThreadID       = "thid"
 	jsonParentThreadID = "pthid"
@@ -45,23 +46,37 @@ type Provider interface {
 
 // Messenger describes the messenger structure.
 type Messenger struct {
-	store      storage.Store
-	dispatcher dispatcher.Outbound
+	store           storage.Store
+	dispatcher      dispatcher.Outbound
+	connectionless  storage.Store
+	serviceEndpoint string
 }
 
 var logger = log.New("aries-framework/pkg/didcomm/messenger")
 
 // NewMessenger returns a new instance of the Messenger.
+// The connectionless messaging is supported if the provider also supplies the KMS and the service endpoint.
 func NewMessenger(ctx Provider) (*Messenger, error) {
 	store, err := ctx.StorageProvider().OpenStore(MessengerStore)
 	if err != nil {
 		return nil, fmt.Errorf("open store: %w", err)
 	}
 
-	return &Messenger{
+	m := &Messenger{
 		store:      store,
 		dispatcher: ctx.OutboundDispatcher(),
-	}, nil
+	}
+
+	if p, ok := ctx.(connectionlessProvider); ok {
+		m.connectionless, err = p.StorageProvider().OpenStore(ConnectionlessStore)
+		if err != nil {
+			return nil, fmt.Errorf("open connectionless store: %w", err)
+		}
+
+		m.serviceEndpoint = p.ServiceEndpoint()
+	}
+
+	return m, nil
 }
 
 // HandleInbound handles all inbound messages.
@@ -91,12 +106,18 @@ func (m *Messenger) HandleInbound(msg service.DIDCommMsgMap, myDID, theirDID str
 // Send sends the message by starting a new thread.
 // Do not provide a message with ~thread decorator. It will be removed.
 // Use ReplyTo function instead. It will keep ~thread decorator automatically.
+// The connectionless message, which has the ~service decorator and no DIDs, is not sent,
+// it's up to the caller to deliver it, e.g. as a QR code.
 func (m *Messenger) Send(msg service.DIDCommMsgMap, myDID, theirDID string) error {
 	// fills missing fields
 	fillIfMissing(msg)
 
-	msg[jsonThread] = map[string]interface{}{
-		jsonThreadID: msg.ID(),
+	setThread(msg, msg.ID(), "")
+
+	if myDID == "" && theirDID == "" {
+		if _, ok := msg[jsonService]; ok {
+			return nil
+		}
 	}
 
 	return m.dispatcher.SendToDID(msg, myDID, theirDID)
@@ -110,7 +131,7 @@ func (m *Messenger) SendToDestination(msg service.DIDCommMsgMap, sender string,
 	// fills missing fields
 	fillIfMissing(msg)
 
-	delete(msg, jsonThread)
+	setThread(msg, "", "")
 
 	return m.dispatcher.Send(msg, sender, destination)
 }
@@ -127,17 +148,8 @@ func (m *Messenger) ReplyTo(msgID string, msg service.DIDCommMsgMap) error {
 		return fmt.Errorf("get record: %w", err)
 	}
 
-	// sets threadID
-	thread := map[string]interface{}{
-		jsonThreadID: rec.ThreadID,
-	}
-
-	// sets parent threadID
-	if rec.ParentThreadID != "" {
-		thread[jsonParentThreadID] = rec.ParentThreadID
-	}
-
-	msg[jsonThread] = thread
+	// sets threadID and parent threadID
+	setThread(msg, rec.ThreadID, rec.ParentThreadID)
 
 	return m.dispatcher.SendToDID(msg, rec.MyDID, rec.TheirDID)
 }
@@ -154,17 +166,20 @@ func (m *Messenger) ReplyToMsg(in, out service.DIDCommMsgMap, myDID, theirDID st
 		return fmt.Errorf("get threadID: %w", err)
 	}
 
-	// sets threadID
-	thread := map[string]interface{}{
-		jsonThreadID: thID,
-	}
+	// sets threadID and parent threadID
+	setThread(out, thID, in.ParentThreadID())
 
-	// sets parent threadID
-	if in.ParentThreadID() != "" {
-		thread[jsonParentThreadID] = in.ParentThreadID()
-	}
+	// replies to the connectionless message using its ~service decorator, if the agent took part in the thread
+	if theirDID == "" {
+		theirs, errService := serviceDecorator(in)
+		if errService != nil {
+			return errService
+		}
 
-	out[jsonThread] = thread
+		if theirs != nil {
+			return m.replyConnectionless(thID, out, theirs)
+		}
+	}
 
 	return m.dispatcher.SendToDID(out, myDID, theirDID)
 }
@@ -182,7 +197,7 @@ func (m *Messenger) ReplyToNested(msg service.DIDCommMsgMap, opts *service.Neste
 	}
 
 	// sets parent threadID
-	msg[jsonThread] = map[string]interface{}{jsonParentThreadID: opts.ThreadID}
+	setThread(msg, "", opts.ThreadID)
 
 	return m.dispatcher.SendToDID(msg, opts.MyDID, opts.TheirDID)
 }
@@ -191,7 +206,42 @@ func (m *Messenger) ReplyToNested(msg service.DIDCommMsgMap, opts *service.Neste
 func fillIfMissing(msg service.DIDCommMsgMap) {
 	// if ID is empty we will create a new one
 	if msg.ID() == "" {
-		msg[jsonID] = uuid.New().String()
+		if msg.IsDIDCommV2() {
+			msg[jsonIDV2] = uuid.New().String()
+		} else {
+			msg[jsonID] = uuid.New().String()
+		}
+	}
+}
+
+// setThread sets the ~thread decorator of the message, or the thid and pthid headers of the DIDComm V2 message.
+// Empty IDs are not set, the thread is removed if both of them are empty.
+func setThread(msg service.DIDCommMsgMap, thID, pthID string) {
+	thread := map[string]interface{}{}
+
+	if msg.IsDIDCommV2() {
+		thread = msg
+	}
+
+	delete(thread, jsonThreadID)
+	delete(thread, jsonParentThreadID)
+
+	if thID != "" {
+		thread[jsonThreadID] = thID
+	}
+
+	if pthID != "" {
+		thread[jsonParentThreadID] = pthID
+	}
+
+	if msg.IsDIDCommV2() {
+		return
+	}
+
+	delete(msg, jsonThread)
+
+	if len(thread) > 0 {
+		msg[jsonThread] = thread
 	}
 }
 
@@ -368,6 +368,37 @@ func TestMessenger_ReplyToMsg(t *testing.T) {
 		}, service.DIDCommMsgMap{}, "", ""))
 	})
 
+	t.Run("success DIDComm V2", func(t *testing.T) {
+		outbound := dispatcherMocks.NewMockOutbound(ctrl)
+		outbound.EXPECT().SendToDID(gomock.Any(), gomock.Any(), gomock.Any()).
+			Do(func(msg service.DIDCommMsgMap, myDID, theirDID string) error {
+				require.NotEmpty(t, msg["id"])
+				require.Equal(t, "thID", msg["thid"])
+				require.Equal(t, "pthID", msg["pthid"])
+				require.Nil(t, msg[jsonThread])
+				require.Nil(t, msg[jsonID])
+
+				return nil
+			})
+
+		storageProvider := storageMocks.NewMockProvider(ctrl)
+		storageProvider.EXPECT().OpenStore(gomock.Any()).Return(nil, nil)
+
+		provider := messengerMocks.NewMockProvider(ctrl)
+		provider.EXPECT().StorageProvider().Return(storageProvider)
+		provider.EXPECT().OutboundDispatcher().Return(outbound)
+
+		msgr, err := NewMessenger(provider)
+		require.NoError(t, err)
+		require.NotNil(t, msgr)
+		require.NoError(t, msgr.ReplyToMsg(service.DIDCommMsgMap{
+			"id":    "id",
+			"type":  "https://didcomm.org/basicmessage/2.0/message",
+			"thid":  "thID",
+			"pthid": "pthID",
+		}, service.DIDCommMsgMap{"type": "https://didcomm.org/basicmessage/2.0/message"}, myDID, theirDID))
+	})
+
 	t.Run("success msg without id", func(t *testing.T) {
 		outbound := dispatcherMocks.NewMockOutbound(ctrl)
 		outbound.EXPECT().SendToDID(gomock.Any(), gomock.Any(), gomock.Any()).
@@ -8,9 +8,11 @@ package packager_test
 
 import (
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"testing"
 
+	"github.com/btcsuite/btcutil/base58"
 	"github.com/stretchr/testify/require"
 
 	cryptoapi "github.com/hyperledger/aries-framework-go/pkg/crypto"
@@ -18,14 +20,18 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/transport"
 	. "github.com/hyperledger/aries-framework-go/pkg/didcomm/packager"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/packer"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/packer/anoncrypt"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/packer/authcrypt"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/packer/jws"
 	legacy "github.com/hyperledger/aries-framework-go/pkg/didcomm/packer/legacy/authcrypt"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/jose"
 	vdrapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
 	"github.com/hyperledger/aries-framework-go/pkg/kms"
 	"github.com/hyperledger/aries-framework-go/pkg/kms/localkms"
 	"github.com/hyperledger/aries-framework-go/pkg/mock/didcomm"
 	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	mockvdr "github.com/hyperledger/aries-framework-go/pkg/mock/vdr"
 	"github.com/hyperledger/aries-framework-go/pkg/secretlock"
 	"github.com/hyperledger/aries-framework-go/pkg/secretlock/noop"
 	"github.com/hyperledger/aries-framework-go/pkg/store/wrapper/prefix"
@@ -341,6 +347,402 @@ func TestBaseKMSInPackager_UnpackMessage(t *testing.T) {
 		require.Equal(t, unpackedMsg.Message, []byte("msg2"))
 	})
 
+	t.Run("test Pack/Unpack success without sender key", func(t *testing.T) {
+		customKMS, err := localkms.New(localKeyURI,
+			newMockKMSProvider(mockstorage.NewMockStoreProvider()))
+		require.NoError(t, err)
+
+		mockedProviders := &mockProvider{
+			storage: mockstorage.NewMockStoreProvider(),
+			kms:     customKMS,
+			crypto:  cryptoSvc,
+		}
+
+		authPacker, err := authcrypt.New(mockedProviders, jose.A256GCM)
+		require.NoError(t, err)
+
+		anonPacker, err := anoncrypt.New(mockedProviders, jose.A256GCM)
+		require.NoError(t, err)
+
+		legacyPacker := legacy.New(mockedProviders)
+
+		// JWE authcrypt primary packer falls back to anoncrypt
+		mockedProviders.primaryPacker = authPacker
+		mockedProviders.packers = []packer.Packer{anonPacker, legacyPacker}
+
+		packager, err := New(mockedProviders)
+		require.NoError(t, err)
+
+		_, toKey, err := customKMS.CreateAndExportPubKeyBytes(kms.NISTP256ECDHKWType)
+		require.NoError(t, err)
+
+		didKey, _ := fingerprint.CreateDIDKey(toKey)
+
+		packMsg, err := packager.PackMessage(&transport.Envelope{
+			Message: []byte("msg1"),
+			ToKeys:  []string{didKey},
+		})
+		require.NoError(t, err)
+
+		unpackedMsg, err := packager.UnpackMessage(packMsg)
+		require.NoError(t, err)
+		require.Equal(t, []byte("msg1"), unpackedMsg.Message)
+
+		// legacy primary packer packs with Anoncrypt
+		mockedProviders.primaryPacker = legacyPacker
+
+		packager, err = New(mockedProviders)
+		require.NoError(t, err)
+
+		_, toKey, err = customKMS.CreateAndExportPubKeyBytes(kms.ED25519Type)
+		require.NoError(t, err)
+
+		didKey, _ = fingerprint.CreateDIDKey(toKey)
+
+		packMsg, err = packager.PackMessage(&transport.Envelope{
+			Message: []byte("msg2"),
+			ToKeys:  []string{didKey},
+		})
+		require.NoError(t, err)
+
+		unpackedMsg, err = packager.UnpackMessage(packMsg)
+		require.NoError(t, err)
+		require.Equal(t, []byte("msg2"), unpackedMsg.Message)
+		require.Empty(t, unpackedMsg.FromKey)
+	})
+
+	t.Run("test Pack/Unpack DIDComm V2 with key agreement kid", func(t *testing.T) {
+		customKMS, err := localkms.New(localKeyURI,
+			newMockKMSProvider(mockstorage.NewMockStoreProvider()))
+		require.NoError(t, err)
+
+		kmsKID, marshalledKey, err := customKMS.CreateAndExportPubKeyBytes(kms.X25519ECDHKWType)
+		require.NoError(t, err)
+
+		pubKey := &cryptoapi.PublicKey{}
+		require.NoError(t, json.Unmarshal(marshalledKey, pubKey))
+
+		kid := "did:example:bob#" + kmsKID
+
+		mockedProviders := &mockProvider{
+			storage: mockstorage.NewMockStoreProvider(),
+			kms:     customKMS,
+			crypto:  cryptoSvc,
+			vdr: &mockvdr.MockVDRegistry{ResolveValue: &did.Doc{
+				ID: "did:example:bob",
+				KeyAgreement: []did.Verification{{
+					VerificationMethod: *did.NewVerificationMethodFromBytes(kid, "X25519KeyAgreementKey2019",
+						"did:example:bob", pubKey.X),
+					Relationship: did.KeyAgreement,
+				}},
+			}},
+		}
+
+		anonPacker, err := anoncrypt.New(mockedProviders, jose.XC20P,
+			anoncrypt.WithMediaType(transport.MediaTypeV2EncryptedEnvelope))
+		require.NoError(t, err)
+
+		mockedProviders.primaryPacker = legacy.New(mockedProviders)
+		mockedProviders.packers = []packer.Packer{anonPacker}
+
+		packager, err := New(mockedProviders)
+		require.NoError(t, err)
+
+		packMsg, err := packager.PackMessage(&transport.Envelope{
+			MediaType: transport.MediaTypeV2EncryptedEnvelope,
+			Message:   []byte(`{"id":"1","type":"https://didcomm.org/basicmessage/2.0/message"}`),
+			ToKeys:    []string{kid},
+		})
+		require.NoError(t, err)
+
+		unpackedMsg, err := packager.UnpackMessage(packMsg)
+		require.NoError(t, err)
+		require.Contains(t, string(unpackedMsg.Message), "basicmessage/2.0")
+
+		_, err = packager.PackMessage(&transport.Envelope{
+			MediaType: transport.MediaTypeV2EncryptedEnvelope,
+			Message:   []byte("msg"),
+			ToKeys:    []string{"did:example:bob#unknown"},
+		})
+		require.EqualError(t, err, "packMessage: resolve key agreement [did:example:bob#unknown]: "+
+			"key agreement not found in DID doc")
+
+		_, err = packager.PackMessage(&transport.Envelope{
+			MediaType: transport.MediaTypeV2SignedEnvelope,
+			Message:   []byte("msg"),
+			ToKeys:    []string{kid},
+		})
+		require.EqualError(t, err, "packMessage: no packer found for media type "+
+			transport.MediaTypeV2SignedEnvelope)
+	})
+
+	t.Run("test Pack/Unpack DIDComm V2 signed and sign then encrypt", func(t *testing.T) {
+		customKMS, err := localkms.New(localKeyURI,
+			newMockKMSProvider(mockstorage.NewMockStoreProvider()))
+		require.NoError(t, err)
+
+		kmsKID, marshalledKey, err := customKMS.CreateAndExportPubKeyBytes(kms.X25519ECDHKWType)
+		require.NoError(t, err)
+
+		pubKey := &cryptoapi.PublicKey{}
+		require.NoError(t, json.Unmarshal(marshalledKey, pubKey))
+
+		signKID, signKey, err := customKMS.CreateAndExportPubKeyBytes(kms.ED25519Type)
+		require.NoError(t, err)
+
+		kid := "did:example:bob#" + kmsKID
+		signer := "did:example:alice#" + signKID
+
+		mockedProviders := &mockProvider{
+			storage: mockstorage.NewMockStoreProvider(),
+			kms:     customKMS,
+			crypto:  cryptoSvc,
+			vdr: &mockvdr.MockVDRegistry{
+				ResolveFunc: func(didID string, _ ...vdrapi.ResolveOption) (*did.DocResolution, error) {
+					if didID == "did:example:alice" {
+						return &did.DocResolution{DIDDocument: &did.Doc{
+							ID: didID,
+							Authentication: []did.Verification{{
+								VerificationMethod: *did.NewVerificationMethodFromBytes(signer,
+									"Ed25519VerificationKey2018", didID, signKey),
+								Relationship: did.Authentication,
+							}},
+						}}, nil
+					}
+
+					return &did.DocResolution{DIDDocument: &did.Doc{
+						ID: didID,
+						KeyAgreement: []did.Verification{{
+							VerificationMethod: *did.NewVerificationMethodFromBytes(kid, "X25519KeyAgreementKey2019",
+								didID, pubKey.X),
+							Relationship: did.KeyAgreement,
+						}},
+					}}, nil
+				},
+			},
+		}
+
+		anonPacker, err := anoncrypt.New(mockedProviders, jose.XC20P,
+			anoncrypt.WithMediaType(transport.MediaTypeV2EncryptedEnvelope))
+		require.NoError(t, err)
+
+		jwsPacker, err := jws.New(mockedProviders, mockedProviders.vdr)
+		require.NoError(t, err)
+
+		mockedProviders.primaryPacker = legacy.New(mockedProviders)
+		mockedProviders.packers = []packer.Packer{anonPacker, jwsPacker}
+
+		packager, err := New(mockedProviders)
+		require.NoError(t, err)
+
+		msg := []byte(`{"id":"1","type":"https://didcomm.org/basicmessage/2.0/message"}`)
+
+		packMsg, err := packager.PackMessage(&transport.Envelope{
+			MediaType: transport.MediaTypeV2SignedEnvelope,
+			Message:   msg,
+			FromKey:   []byte(signer),
+		})
+		require.NoError(t, err)
+
+		unpackedMsg, err := packager.UnpackMessage(packMsg)
+		require.NoError(t, err)
+		require.Equal(t, msg, unpackedMsg.Message)
+		require.Equal(t, signer, unpackedMsg.Signer)
+		require.Equal(t, signKey, unpackedMsg.FromKey)
+
+		packMsg, err = packager.PackMessage(&transport.Envelope{
+			MediaType: transport.MediaTypeV2EncryptedEnvelope,
+			Message:   msg,
+			ToKeys:    []string{kid},
+			Signer:    signer,
+		})
+		require.NoError(t, err)
+		require.NotContains(t, string(packMsg), "signatures")
+
+		unpackedMsg, err = packager.UnpackMessage(packMsg)
+		require.NoError(t, err)
+		require.Equal(t, msg, unpackedMsg.Message)
+		require.Equal(t, signer, unpackedMsg.Signer)
+		require.Equal(t, signKey, unpackedMsg.FromKey)
+
+		_, err = packager.PackMessage(&transport.Envelope{
+			MediaType: transport.MediaTypeV2EncryptedEnvelope,
+			Message:   msg,
+			ToKeys:    []string{kid},
+			Signer:    "did:example:alice#unknown",
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "packMessage: failed to sign")
+	})
+
+	t.Run("test Unpack DIDComm V2 sign then authcrypt with a signer other than the sender", func(t *testing.T) {
+		customKMS, err := localkms.New(localKeyURI,
+			newMockKMSProvider(mockstorage.NewMockStoreProvider()))
+		require.NoError(t, err)
+
+		keyAgreement := func(kmsKID string, marshalledKey []byte) func(didID string) did.Verification {
+			pubKey := &cryptoapi.PublicKey{}
+			require.NoError(t, json.Unmarshal(marshalledKey, pubKey))
+
+			return func(didID string) did.Verification {
+				return did.Verification{
+					VerificationMethod: *did.NewVerificationMethodFromBytes(didID+"#"+kmsKID,
+						"X25519KeyAgreementKey2019", didID, pubKey.X),
+					Relationship: did.KeyAgreement,
+				}
+			}
+		}
+
+		recKID, recKey, err := customKMS.CreateAndExportPubKeyBytes(kms.X25519ECDHKWType)
+		require.NoError(t, err)
+
+		senderKID, senderKey, err := customKMS.CreateAndExportPubKeyBytes(kms.X25519ECDHKWType)
+		require.NoError(t, err)
+
+		signKID, signKey, err := customKMS.CreateAndExportPubKeyBytes(kms.ED25519Type)
+		require.NoError(t, err)
+
+		recKeyAgreement := keyAgreement(recKID, recKey)
+		senderKeyAgreement := keyAgreement(senderKID, senderKey)
+
+		// every DID shares the keys, only the DIDs of the sender and the signer differ
+		mockedProviders := &mockProvider{
+			storage: mockstorage.NewMockStoreProvider(),
+			kms:     customKMS,
+			crypto:  cryptoSvc,
+			vdr: &mockvdr.MockVDRegistry{
+				ResolveFunc: func(didID string, _ ...vdrapi.ResolveOption) (*did.DocResolution, error) {
+					return &did.DocResolution{DIDDocument: &did.Doc{
+						ID:           didID,
+						KeyAgreement: []did.Verification{recKeyAgreement(didID), senderKeyAgreement(didID)},
+						Authentication: []did.Verification{{
+							VerificationMethod: *did.NewVerificationMethodFromBytes(didID+"#"+signKID,
+								"Ed25519VerificationKey2018", didID, signKey),
+							Relationship: did.Authentication,
+						}},
+					}}, nil
+				},
+			},
+		}
+
+		authPacker, err := authcrypt.New(mockedProviders, jose.XC20P,
+			authcrypt.WithMediaType(transport.MediaTypeV2EncryptedEnvelope))
+		require.NoError(t, err)
+
+		jwsPacker, err := jws.New(mockedProviders, mockedProviders.vdr)
+		require.NoError(t, err)
+
+		mockedProviders.primaryPacker = legacy.New(mockedProviders)
+		mockedProviders.packers = []packer.Packer{authPacker, jwsPacker}
+
+		packager, err := New(mockedProviders)
+		require.NoError(t, err)
+
+		msg := []byte(`{"id":"1","type":"https://didcomm.org/basicmessage/2.0/message"}`)
+
+		packMsg, err := packager.PackMessage(&transport.Envelope{
+			MediaType: transport.MediaTypeV2EncryptedEnvelope,
+			Message:   msg,
+			FromKey:   []byte("did:example:alice#" + senderKID),
+			ToKeys:    []string{"did:example:bob#" + recKID},
+			Signer:    "did:example:alice#" + signKID,
+		})
+		require.NoError(t, err)
+
+		unpackedMsg, err := packager.UnpackMessage(packMsg)
+		require.NoError(t, err)
+		require.Equal(t, msg, unpackedMsg.Message)
+		require.Equal(t, "did:example:alice#"+signKID, unpackedMsg.Signer)
+
+		packMsg, err = packager.PackMessage(&transport.Envelope{
+			MediaType: transport.MediaTypeV2EncryptedEnvelope,
+			Message:   msg,
+			FromKey:   []byte("did:example:alice#" + senderKID),
+			ToKeys:    []string{"did:example:bob#" + recKID},
+			Signer:    "did:example:carol#" + signKID,
+		})
+		require.NoError(t, err)
+
+		_, err = packager.UnpackMessage(packMsg)
+		require.EqualError(t, err, fmt.Sprintf("unpack signed envelope: signer did:example:carol#%s does not "+
+			"match the authcrypt sender did:example:alice#%s", signKID, senderKID))
+	})
+
+	t.Run("test Unpack DIDComm V1 sign then authcrypt with a signer key other than the sender key", func(t *testing.T) {
+		customKMS, err := localkms.New(localKeyURI,
+			newMockKMSProvider(mockstorage.NewMockStoreProvider()))
+		require.NoError(t, err)
+
+		senderKID, senderKey, err := customKMS.CreateAndExportPubKeyBytes(kms.ED25519Type)
+		require.NoError(t, err)
+
+		otherKID, otherKey, err := customKMS.CreateAndExportPubKeyBytes(kms.ED25519Type)
+		require.NoError(t, err)
+
+		_, toKey, err := customKMS.CreateAndExportPubKeyBytes(kms.ED25519Type)
+		require.NoError(t, err)
+
+		toDIDKey, _ := fingerprint.CreateDIDKey(toKey)
+
+		mockedProviders := &mockProvider{
+			storage: mockstorage.NewMockStoreProvider(),
+			kms:     customKMS,
+			crypto:  cryptoSvc,
+			vdr: &mockvdr.MockVDRegistry{
+				ResolveFunc: func(didID string, _ ...vdrapi.ResolveOption) (*did.DocResolution, error) {
+					return &did.DocResolution{DIDDocument: &did.Doc{
+						ID: didID,
+						Authentication: []did.Verification{{
+							VerificationMethod: *did.NewVerificationMethodFromBytes(didID+"#"+senderKID,
+								"Ed25519VerificationKey2018", didID, senderKey),
+							Relationship: did.Authentication,
+						}, {
+							VerificationMethod: *did.NewVerificationMethodFromBytes(didID+"#"+otherKID,
+								"Ed25519VerificationKey2018", didID, otherKey),
+							Relationship: did.Authentication,
+						}},
+					}}, nil
+				},
+			},
+		}
+
+		jwsPacker, err := jws.New(mockedProviders, mockedProviders.vdr)
+		require.NoError(t, err)
+
+		mockedProviders.primaryPacker = legacy.New(mockedProviders)
+		mockedProviders.packers = []packer.Packer{mockedProviders.primaryPacker, jwsPacker}
+
+		packager, err := New(mockedProviders)
+		require.NoError(t, err)
+
+		msg := []byte(`{"@id":"1","@type":"https://didcomm.org/basicmessage/1.0/message"}`)
+
+		packMsg, err := packager.PackMessage(&transport.Envelope{
+			Message: msg,
+			FromKey: senderKey,
+			ToKeys:  []string{toDIDKey},
+			Signer:  "did:example:alice#" + senderKID,
+		})
+		require.NoError(t, err)
+
+		unpackedMsg, err := packager.UnpackMessage(packMsg)
+		require.NoError(t, err)
+		require.Equal(t, msg, unpackedMsg.Message)
+		require.Equal(t, senderKey, unpackedMsg.FromKey)
+
+		packMsg, err = packager.PackMessage(&transport.Envelope{
+			Message: msg,
+			FromKey: senderKey,
+			ToKeys:  []string{toDIDKey},
+			Signer:  "did:example:alice#" + otherKID,
+		})
+		require.NoError(t, err)
+
+		_, err = packager.UnpackMessage(packMsg)
+		require.EqualError(t, err, fmt.Sprintf("unpack signed envelope: signer did:example:alice#%s does not "+
+			"match the authcrypt sender key %s", otherKID, base58.Encode(senderKey)))
+	})
+
 	t.Run("test success - dids not found", func(t *testing.T) {
 		customKMS, err := localkms.New(localKeyURI,
 			newMockKMSProvider(mockstorage.NewMockStoreProvider()))
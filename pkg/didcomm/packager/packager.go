@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package packager
 
 import (
+	"bytes"
+	"crypto/ecdsa"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
@@ -15,23 +17,37 @@ import (
 
 	"github.com/btcsuite/btcutil/base58"
 
+	cryptoapi "github.com/hyperledger/aries-framework-go/pkg/crypto"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/transport"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/packer"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/packer/authcrypt"
-	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
+	diddoc "github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	vdrapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
 	"github.com/hyperledger/aries-framework-go/pkg/store/did"
+	"github.com/hyperledger/aries-framework-go/pkg/store/wrapper/prefix"
 	"github.com/hyperledger/aries-framework-go/pkg/vdr/fingerprint"
 	"github.com/hyperledger/aries-framework-go/spi/storage"
 )
 
-const authSuffix = "-authcrypt"
+const (
+	authSuffix = "-authcrypt"
+
+	didKeyPrefix = "did:key:"
+)
+
+// jwkCurves maps the JWK curves of the key agreement keys to the curves of the JWE recipient keys.
+var jwkCurves = map[string]string{ // nolint:gochecknoglobals
+	"P-256": "NIST_P256",
+	"P-384": "NIST_P384",
+	"P-521": "NIST_P521",
+}
 
 // Provider contains dependencies for the base packager and is typically created by using aries.Context().
 type Provider interface {
 	Packers() []packer.Packer
 	PrimaryPacker() packer.Packer
 	StorageProvider() storage.Provider
-	VDRegistry() vdr.Registry
+	VDRegistry() vdrapi.Registry
 }
 
 // Creator method to create new packager service.
@@ -42,6 +58,9 @@ type Packager struct {
 	primaryPacker   packer.Packer
 	packers         map[string]packer.Packer
 	connectionStore *did.ConnectionStore
+	vdrRegistry     vdrapi.Registry
+	// senderKeys is the store the authcrypt packers look the sender keys up in.
+	senderKeys storage.Store
 }
 
 // PackerCreator holds a creator function for a Packer and the name of the Packer's encoding method.
@@ -57,10 +76,22 @@ func New(ctx Provider) (*Packager, error) {
 		return nil, fmt.Errorf("failed to create new packager: %w", err)
 	}
 
+	senderKeys, err := ctx.StorageProvider().OpenStore(authcrypt.ThirdPartyKeysDB)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sender keys store: %w", err)
+	}
+
+	senderKeys, err = prefix.NewPrefixStoreWrapper(senderKeys, prefix.StorageKIDPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to wrap sender keys store: %w", err)
+	}
+
 	basePackager := Packager{
 		primaryPacker:   nil,
 		packers:         map[string]packer.Packer{},
 		connectionStore: didConnStore,
+		vdrRegistry:     ctx.VDRegistry(),
+		senderKeys:      senderKeys,
 	}
 
 	for _, packerType := range ctx.Packers() {
@@ -93,40 +124,187 @@ func (bp *Packager) addPacker(pack packer.Packer) {
 }
 
 // PackMessage Pack a message for one or more recipients.
+// The recipients keys are either did:key identifiers or DID URLs referencing the DID doc keyAgreement verification
+// methods (DIDComm V2).
 func (bp *Packager) PackMessage(messageEnvelope *transport.Envelope) ([]byte, error) {
 	if messageEnvelope == nil {
 		return nil, errors.New("packMessage: envelope argument is nil")
 	}
 
+	p, err := bp.selectPacker(messageEnvelope.MediaType, messageEnvelope.FromKey)
+	if err != nil {
+		return nil, fmt.Errorf("packMessage: %w", err)
+	}
+
+	// signed envelopes are not encrypted, the signer defaults to the sender key
+	if messageEnvelope.MediaType == transport.MediaTypeV2SignedEnvelope {
+		signer := messageEnvelope.Signer
+		if signer == "" {
+			signer = string(messageEnvelope.FromKey)
+		}
+
+		bytes, err := p.Pack(messageEnvelope.Message, []byte(signer), nil)
+		if err != nil {
+			return nil, fmt.Errorf("packMessage: failed to sign: %w", err)
+		}
+
+		return bytes, nil
+	}
+
+	message := messageEnvelope.Message
+
+	// sign then encrypt
+	if messageEnvelope.Signer != "" {
+		message, err = bp.sign(message, messageEnvelope.Signer)
+		if err != nil {
+			return nil, fmt.Errorf("packMessage: %w", err)
+		}
+	}
+
 	var recipients [][]byte
 
-	for _, didKey := range messageEnvelope.ToKeys {
+	for _, key := range messageEnvelope.ToKeys {
+		recKey, err := bp.recipientKey(key)
+		if err != nil {
+			return nil, fmt.Errorf("packMessage: %w", err)
+		}
+
+		recipients = append(recipients, recKey)
+	}
+
+	bytes, err := p.Pack(message, messageEnvelope.FromKey, recipients)
+	if err != nil {
+		return nil, fmt.Errorf("packMessage: failed to pack: %w", err)
+	}
+
+	return bytes, nil
+}
+
+// sign wraps the message in a signed envelope, it's packed afterwards by the packer of the envelope media type.
+func (bp *Packager) sign(message []byte, signer string) ([]byte, error) {
+	p, ok := bp.packers[transport.MediaTypeV2SignedEnvelope]
+	if !ok {
+		return nil, fmt.Errorf("no packer found for media type %s", transport.MediaTypeV2SignedEnvelope)
+	}
+
+	signed, err := p.Pack(message, []byte(signer), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign: %w", err)
+	}
+
+	return signed, nil
+}
+
+// selectPacker returns the packer of the media type, the primary packer is used if the media type is empty.
+// Messages without a sender (eg. forward messages) are packed anonymously with the packer sharing the encoding type,
+// if there is such a packer.
+func (bp *Packager) selectPacker(mediaType string, fromKey []byte) (packer.Packer, error) {
+	if mediaType == "" {
+		if len(fromKey) > 0 {
+			return bp.primaryPacker, nil
+		}
+
+		if p, ok := bp.packers[bp.primaryPacker.EncodingType()]; ok {
+			return p, nil
+		}
+
+		return bp.primaryPacker, nil
+	}
+
+	if len(fromKey) > 0 {
+		if p, ok := bp.packers[mediaType+authSuffix]; ok {
+			return p, nil
+		}
+	}
+
+	if p, ok := bp.packers[mediaType]; ok {
+		return p, nil
+	}
+
+	return nil, fmt.Errorf("no packer found for media type %s", mediaType)
+}
+
+// recipientKey returns the public key bytes of the recipient key as expected by the packers.
+func (bp *Packager) recipientKey(key string) ([]byte, error) {
+	if strings.HasPrefix(key, didKeyPrefix) || !strings.Contains(key, "#") {
 		// TODO https://github.com/hyperledger/aries-framework-go/issues/749 It is possible to have
 		//  different key schemes in an interop situation
 		// there is no guarantee that each recipient is using the same key types
 		// for now this package uses Ed25519 signing keys. Other key schemes should have their own
 		// envelope implementations.
-		verKeyBytes, err := fingerprint.PubKeyFromDIDKey(didKey)
+		verKeyBytes, err := fingerprint.PubKeyFromDIDKey(key)
 		if err != nil {
-			return nil, fmt.Errorf("packMessage: failed to parse public key bytes from did:key verKey: %w", err)
+			return nil, fmt.Errorf("failed to parse public key bytes from did:key verKey: %w", err)
 		}
 
-		// create 32 byte key
-		recipients = append(recipients, verKeyBytes)
+		return verKeyBytes, nil
 	}
 
-	// TODO find a way to dynamically select a packer based on FromKey, recipients and their types.
-	//      https://github.com/hyperledger/aries-framework-go/issues/1112 Configurable packing
-	bytes, err := bp.primaryPacker.Pack(messageEnvelope.Message, messageEnvelope.FromKey, recipients)
+	pubKey, err := bp.resolveKeyAgreement(key)
 	if err != nil {
-		return nil, fmt.Errorf("packMessage: failed to pack: %w", err)
+		return nil, fmt.Errorf("resolve key agreement [%s]: %w", key, err)
 	}
 
-	return bytes, nil
+	return json.Marshal(pubKey)
+}
+
+// resolveKeyAgreement resolves the keyAgreement verification method referenced by the kid DID URL.
+func (bp *Packager) resolveKeyAgreement(kid string) (*cryptoapi.PublicKey, error) {
+	if bp.vdrRegistry == nil {
+		return nil, errors.New("VDR registry is not set")
+	}
+
+	didID := strings.Split(kid, "#")[0]
+
+	docResolution, err := bp.vdrRegistry.Resolve(didID)
+	if err != nil {
+		return nil, fmt.Errorf("resolve DID: %w", err)
+	}
+
+	for i := range docResolution.DIDDocument.KeyAgreement {
+		vm := &docResolution.DIDDocument.KeyAgreement[i].VerificationMethod
+
+		if vm.ID == kid || didID+vm.ID == kid {
+			return keyAgreementPublicKey(kid, vm)
+		}
+	}
+
+	return nil, errors.New("key agreement not found in DID doc")
+}
+
+func keyAgreementPublicKey(kid string, vm *diddoc.VerificationMethod) (*cryptoapi.PublicKey, error) {
+	jwk := vm.JSONWebKey()
+
+	switch {
+	case vm.Type == "X25519KeyAgreementKey2019":
+		return &cryptoapi.PublicKey{KID: kid, X: vm.Value, Curve: "CURVE25519", Type: "OKP"}, nil
+	case jwk != nil && jwk.Crv == "X25519":
+		x, ok := jwk.Key.([]byte)
+		if !ok {
+			return nil, errors.New("invalid X25519 JWK")
+		}
+
+		return &cryptoapi.PublicKey{KID: kid, X: x, Curve: "CURVE25519", Type: "OKP"}, nil
+	case jwk != nil && jwkCurves[jwk.Crv] != "":
+		ecKey, ok := jwk.Key.(*ecdsa.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("invalid %s JWK", jwk.Crv)
+		}
+
+		return &cryptoapi.PublicKey{
+			KID: kid, X: ecKey.X.Bytes(), Y: ecKey.Y.Bytes(), Curve: jwkCurves[jwk.Crv], Type: "EC",
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported key agreement verification method type %s", vm.Type)
 }
 
 type envelopeStub struct {
 	Protected string `json:"protected,omitempty"`
+	// Signatures holds the signatures of a JWS in the general JSON serialization.
+	Signatures []struct {
+		Protected string `json:"protected,omitempty"`
+	} `json:"signatures,omitempty"`
 }
 
 type headerStub struct {
@@ -135,12 +313,25 @@ type headerStub struct {
 }
 
 func getEncodingType(encMessage []byte) (string, error) {
+	prot, err := protectedHeader(encMessage)
+	if err != nil {
+		return "", err
+	}
+
+	return encodingType(prot), nil
+}
+
+func protectedHeader(encMessage []byte) (*headerStub, error) {
 	env := &envelopeStub{}
 
 	if strings.HasPrefix(string(encMessage), "{") { // full serialized
 		err := json.Unmarshal(encMessage, env)
 		if err != nil {
-			return "", fmt.Errorf("parse envelope: %w", err)
+			return nil, fmt.Errorf("parse envelope: %w", err)
+		}
+
+		if env.Protected == "" && len(env.Signatures) > 0 {
+			env.Protected = env.Signatures[0].Protected
 		}
 	} else { // compact serialized
 		env.Protected = strings.Split(string(encMessage), ".")[0]
@@ -157,16 +348,20 @@ func getEncodingType(encMessage []byte) (string, error) {
 	case err2 == nil:
 		protBytes = protBytes2
 	default:
-		return "", fmt.Errorf("decode header: %w", err1)
+		return nil, fmt.Errorf("decode header: %w", err1)
 	}
 
 	prot := &headerStub{}
 
 	err := json.Unmarshal(protBytes, prot)
 	if err != nil {
-		return "", fmt.Errorf("parse header: %w", err)
+		return nil, fmt.Errorf("parse header: %w", err)
 	}
 
+	return prot, nil
+}
+
+func encodingType(prot *headerStub) string {
 	packerID := prot.Type
 
 	if prot.SKID != "" {
@@ -175,37 +370,77 @@ func getEncodingType(encMessage []byte) (string, error) {
 		packerID += authSuffix
 	}
 
-	return packerID, nil
+	return packerID
+}
+
+// addSenderKey stores the key agreement key of the DIDComm V2 sender referenced by the skid DID URL, so the authcrypt
+// packer finds it when decrypting the envelope.
+func (bp *Packager) addSenderKey(skid string) error {
+	if _, err := bp.senderKeys.Get(skid); err == nil {
+		return nil
+	}
+
+	pubKey, err := bp.resolveKeyAgreement(skid)
+	if err != nil {
+		return fmt.Errorf("resolve sender key agreement [%s]: %w", skid, err)
+	}
+
+	src, err := json.Marshal(pubKey)
+	if err != nil {
+		return fmt.Errorf("marshal sender key: %w", err)
+	}
+
+	return bp.senderKeys.Put(skid, src)
 }
 
 // UnpackMessage Unpack a message.
 func (bp *Packager) UnpackMessage(encMessage []byte) (*transport.Envelope, error) {
-	encType, err := getEncodingType(encMessage)
+	prot, err := protectedHeader(encMessage)
 	if err != nil {
 		return nil, fmt.Errorf("getEncodingType: %w", err)
 	}
 
+	encType := encodingType(prot)
+
 	p, ok := bp.packers[encType]
 	if !ok {
 		return nil, fmt.Errorf("message Type not recognized")
 	}
 
+	if encType == transport.MediaTypeV2EncryptedEnvelope+authSuffix && strings.Contains(prot.SKID, "#") {
+		err = bp.addSenderKey(prot.SKID)
+		if err != nil {
+			return nil, fmt.Errorf("unpack: %w", err)
+		}
+	}
+
 	envelope, err := p.Unpack(encMessage)
 	if err != nil {
 		return nil, fmt.Errorf("unpack: %w", err)
 	}
 
+	// the encrypted envelope may hold a signed envelope (sign then encrypt)
+	if encType != transport.MediaTypeV2SignedEnvelope {
+		err = bp.unpackSigned(envelope, authcryptSender(encType, prot.SKID))
+		if err != nil {
+			return nil, fmt.Errorf("unpack signed envelope: %w", err)
+		}
+	}
+
+	// the sender of DIDComm V2 authcrypt envelopes is the skid DID URL
+	if len(envelope.FromKey) == 0 && encType == transport.MediaTypeV2EncryptedEnvelope+authSuffix {
+		envelope.FromKey = []byte(prot.SKID)
+	}
+
 	//	ignore error - agents can communicate without using DIDs - for example, in DIDExchange
-	theirDID, err := bp.connectionStore.GetDID(base58.Encode(envelope.FromKey))
-	if errors.Is(err, did.ErrNotFound) {
-	} else if err != nil {
+	theirDID, err := bp.getDID(envelope.FromKey)
+	if err != nil {
 		return nil, fmt.Errorf("failed to get their did: %w", err)
 	}
 
 	// ignore error - at beginning of DIDExchange, you might be about to generate a DID
-	myDID, err := bp.connectionStore.GetDID(base58.Encode(envelope.ToKey))
-	if errors.Is(err, did.ErrNotFound) {
-	} else if err != nil {
+	myDID, err := bp.getDID(envelope.ToKey)
+	if err != nil {
 		return nil, fmt.Errorf("failed to get my did: %w", err)
 	}
 
@@ -214,3 +449,68 @@ func (bp *Packager) UnpackMessage(encMessage []byte) (*transport.Envelope, error
 
 	return envelope, nil
 }
+
+// getDID returns the DID of the key, empty if the key is not known.
+func (bp *Packager) getDID(key []byte) (string, error) {
+	if len(key) == 0 {
+		return "", nil
+	}
+
+	didID, err := bp.connectionStore.GetDID(base58.Encode(key))
+	if errors.Is(err, did.ErrNotFound) {
+		return "", nil
+	}
+
+	return didID, err
+}
+
+// authcryptSender returns the sender DID URL of DIDComm V2 authcrypt envelopes, empty for the other envelopes.
+func authcryptSender(encType, skid string) string {
+	if encType != transport.MediaTypeV2EncryptedEnvelope+authSuffix || !strings.Contains(skid, "#") {
+		return ""
+	}
+
+	return skid
+}
+
+// unpackSigned verifies the signed envelope nested in the envelope message, if any, and replaces the message with the
+// signed payload. The signer key is the sender key of anonymously encrypted envelopes, the signer of authcrypt
+// envelopes must be the sender DID or, if the sender is known by its key only (e.g. DIDComm V1), the sender key.
+func (bp *Packager) unpackSigned(envelope *transport.Envelope, sender string) error {
+	if encType, err := getEncodingType(envelope.Message); err != nil || encType != transport.MediaTypeV2SignedEnvelope {
+		return nil // nolint:nilerr
+	}
+
+	p, ok := bp.packers[transport.MediaTypeV2SignedEnvelope]
+	if !ok {
+		return fmt.Errorf("no packer found for media type %s", transport.MediaTypeV2SignedEnvelope)
+	}
+
+	signed, err := p.Unpack(envelope.Message)
+	if err != nil {
+		return err
+	}
+
+	if sender != "" && didOf(signed.Signer) != didOf(sender) {
+		return fmt.Errorf("signer %s does not match the authcrypt sender %s", signed.Signer, sender)
+	}
+
+	if sender == "" && len(envelope.FromKey) > 0 && !bytes.Equal(envelope.FromKey, signed.FromKey) {
+		return fmt.Errorf("signer %s does not match the authcrypt sender key %s", signed.Signer,
+			base58.Encode(envelope.FromKey))
+	}
+
+	envelope.Message = signed.Message
+	envelope.Signer = signed.Signer
+
+	if len(envelope.FromKey) == 0 {
+		envelope.FromKey = signed.FromKey
+	}
+
+	return nil
+}
+
+// didOf returns the DID of the DID URL.
+func didOf(didURL string) string {
+	return strings.Split(didURL, "#")[0]
+}
@@ -36,16 +36,28 @@ const encodingType = "didcomm-envelope-enc"
 
 var logger = log.New("aries-framework/pkg/didcomm/packer/anoncrypt")
 
+// Option configures the packer.
+type Option func(p *Packer)
+
+// WithMediaType sets the media type of the envelopes, ie the `typ` header. By default, the packer outputs DIDComm V1
+// envelopes ("didcomm-envelope-enc"), use transport.MediaTypeV2EncryptedEnvelope for DIDComm V2 ones.
+func WithMediaType(mediaType string) Option {
+	return func(p *Packer) {
+		p.mediaType = mediaType
+	}
+}
+
 // Packer represents an Anoncrypt Pack/Unpacker that outputs/reads Aries envelopes.
 type Packer struct {
 	kms           kms.KeyManager
 	encAlg        jose.EncAlg
+	mediaType     string
 	cryptoService cryptoapi.Crypto
 }
 
 // New will create an Packer instance to 'AnonCrypt' payloads for a given list of recipients.
 // The returned Packer contains all the information required to pack and unpack payloads.
-func New(ctx packer.Provider, encAlg jose.EncAlg) (*Packer, error) {
+func New(ctx packer.Provider, encAlg jose.EncAlg, opts ...Option) (*Packer, error) {
 	k := ctx.KMS()
 	if k == nil {
 		return nil, errors.New("anoncrypt: failed to create packer because KMS is empty")
@@ -56,11 +68,18 @@ func New(ctx packer.Provider, encAlg jose.EncAlg) (*Packer, error) {
 		return nil, errors.New("anoncrypt: failed to create packer because crypto service is empty")
 	}
 
-	return &Packer{
-		kms:           k,
+	p := &Packer{
+		kms:           packer.NewDIDURLKeyManager(k),
 		encAlg:        encAlg,
+		mediaType:     encodingType,
 		cryptoService: c,
-	}, nil
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p, nil
 }
 
 // Pack will encode the payload argument
@@ -76,7 +95,7 @@ func (p *Packer) Pack(payload, _ []byte, recipientsPubKeys [][]byte) ([]byte, er
 		return nil, fmt.Errorf("anoncrypt Pack: failed to convert recipient keys: %w", err)
 	}
 
-	jweEncrypter, err := jose.NewJWEEncrypt(p.encAlg, encodingType, "", nil, recECKeys, p.cryptoService)
+	jweEncrypter, err := jose.NewJWEEncrypt(p.encAlg, p.mediaType, "", nil, recECKeys, p.cryptoService)
 	if err != nil {
 		return nil, fmt.Errorf("anoncrypt Pack: failed to new JWEEncrypt instance: %w", err)
 	}
@@ -88,7 +107,8 @@ func (p *Packer) Pack(payload, _ []byte, recipientsPubKeys [][]byte) ([]byte, er
 
 	var s string
 
-	if len(recipientsPubKeys) == 1 {
+	// DIDComm V2 envelopes always use the JSON serialization
+	if len(recipientsPubKeys) == 1 && p.mediaType == encodingType {
 		s, err = jwe.CompactSerialize(json.Marshal)
 	} else {
 		s, err = jwe.FullSerialize(json.Marshal)
@@ -228,5 +248,5 @@ func exportPubKeyBytes(keyHandle *keyset.Handle) ([]byte, error) {
 
 // EncodingType for didcomm.
 func (p *Packer) EncodingType() string {
-	return encodingType
+	return p.mediaType
 }
@@ -10,6 +10,7 @@ import (
 	"bytes"
 	"crypto/ecdsa"
 	"crypto/ed25519"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"math/big"
@@ -117,6 +118,35 @@ func TestAnoncryptPackerSuccess(t *testing.T) {
 	}
 }
 
+func TestAnoncryptPackerDIDCommV2(t *testing.T) {
+	k := createKMS(t)
+	_, recipientsKeys, _ := createRecipientsByKeyType(t, k, 1, kms.X25519ECDHKWType)
+
+	cryptoSvc, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	anonPacker, err := New(newMockProvider(k, cryptoSvc), afgjose.XC20P,
+		WithMediaType(transport.MediaTypeV2EncryptedEnvelope))
+	require.NoError(t, err)
+	require.Equal(t, transport.MediaTypeV2EncryptedEnvelope, anonPacker.EncodingType())
+
+	origMsg := []byte("secret message")
+	ct, err := anonPacker.Pack(origMsg, nil, recipientsKeys)
+	require.NoError(t, err)
+
+	// DIDComm V2 envelopes use the JSON serialization even for a single recipient
+	jwe := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(ct, &jwe))
+
+	protected, err := base64.RawURLEncoding.DecodeString(jwe["protected"].(string))
+	require.NoError(t, err)
+	require.Contains(t, string(protected), `"typ":"application/didcomm-encrypted+json"`)
+
+	msg, err := anonPacker.Unpack(ct)
+	require.NoError(t, err)
+	require.Equal(t, origMsg, msg.Message)
+}
+
 func TestAnoncryptPackerSuccessWithDifferentCurvesSuccess(t *testing.T) {
 	k := createKMS(t)
 	_, recipientsKey1, keyHandles1 := createRecipients(t, k, 1)
@@ -42,10 +42,22 @@ const (
 
 var logger = log.New("aries-framework/pkg/didcomm/packer/authcrypt")
 
+// Option configures the packer.
+type Option func(p *Packer)
+
+// WithMediaType sets the media type of the envelopes, ie the `typ` header. By default, the packer outputs DIDComm V1
+// envelopes ("didcomm-envelope-enc"), use transport.MediaTypeV2EncryptedEnvelope for DIDComm V2 ones.
+func WithMediaType(mediaType string) Option {
+	return func(p *Packer) {
+		p.mediaType = mediaType
+	}
+}
+
 // Packer represents an Authcrypt Pack/Unpacker that outputs/reads Aries envelopes.
 type Packer struct {
 	kms           kms.KeyManager
 	encAlg        jose.EncAlg
+	mediaType     string
 	thirdPartyKS  storage.Store
 	cryptoService cryptoapi.Crypto
 }
@@ -55,7 +67,7 @@ type Packer struct {
 // pre-populated with the sender key required by a recipient to Unpack a JWE envelope. It is not needed by the sender
 // (as the sender packs the envelope with its own key).
 // The returned Packer contains all the information required to pack and unpack payloads.
-func New(ctx packer.Provider, encAlg jose.EncAlg) (*Packer, error) {
+func New(ctx packer.Provider, encAlg jose.EncAlg, opts ...Option) (*Packer, error) {
 	k := ctx.KMS()
 	if k == nil {
 		return nil, errors.New("authcrypt: failed to create packer because KMS is empty")
@@ -81,12 +93,19 @@ func New(ctx packer.Provider, encAlg jose.EncAlg) (*Packer, error) {
 		return nil, fmt.Errorf("authcrypt: failed to wrap key store: %w", err)
 	}
 
-	return &Packer{
-		kms:           k,
+	p := &Packer{
+		kms:           packer.NewDIDURLKeyManager(k),
 		encAlg:        encAlg,
+		mediaType:     encodingType,
 		thirdPartyKS:  store,
 		cryptoService: c,
-	}, nil
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p, nil
 }
 
 // Pack will encode the payload argument
@@ -110,7 +129,7 @@ func (p *Packer) Pack(payload, senderID []byte, recipientsPubKeys [][]byte) ([]b
 		return nil, fmt.Errorf("authcrypt Pack: failed to get sender key from KMS: %w", err)
 	}
 
-	jweEncrypter, err := jose.NewJWEEncrypt(p.encAlg, encodingType, string(senderID), kh.(*keyset.Handle), recECKeys,
+	jweEncrypter, err := jose.NewJWEEncrypt(p.encAlg, p.mediaType, string(senderID), kh.(*keyset.Handle), recECKeys,
 		p.cryptoService)
 	if err != nil {
 		return nil, fmt.Errorf("authcrypt Pack: failed to new JWEEncrypt instance: %w", err)
@@ -123,7 +142,8 @@ func (p *Packer) Pack(payload, senderID []byte, recipientsPubKeys [][]byte) ([]b
 
 	var s string
 
-	if len(recipientsPubKeys) == 1 {
+	// DIDComm V2 envelopes always use the JSON serialization
+	if len(recipientsPubKeys) == 1 && p.mediaType == encodingType {
 		s, err = jwe.CompactSerialize(json.Marshal)
 	} else {
 		s, err = jwe.FullSerialize(json.Marshal)
@@ -269,5 +289,5 @@ func exportPubKeyBytes(keyHandle *keyset.Handle) ([]byte, error) {
 
 // EncodingType for didcomm.
 func (p *Packer) EncodingType() string {
-	return encodingType
+	return p.mediaType
 }
@@ -10,6 +10,7 @@ import (
 	"bytes"
 	"crypto/ecdsa"
 	"crypto/ed25519"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -132,6 +133,44 @@ func TestAuthryptPackerSuccess(t *testing.T) {
 	}
 }
 
+func TestAuthcryptPackerDIDCommV2(t *testing.T) {
+	k := createKMS(t)
+
+	skid, senderKey, _ := createAndMarshalKeyByKeyType(t, k, kms.X25519ECDHKWType)
+	_, recipientsKeys, _ := createRecipientsByKeyType(t, k, 1, kms.X25519ECDHKWType)
+
+	thirdPartyKeyStore := make(map[string]mockstorage.DBEntry)
+	mockStoreProvider := &mockstorage.MockStoreProvider{Store: &mockstorage.MockStore{
+		Store: thirdPartyKeyStore,
+	}}
+
+	cryptoSvc, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	authPacker, err := New(newMockProvider(mockStoreProvider, k, cryptoSvc), afgjose.XC20P,
+		WithMediaType(transport.MediaTypeV2EncryptedEnvelope))
+	require.NoError(t, err)
+	require.Equal(t, transport.MediaTypeV2EncryptedEnvelope, authPacker.EncodingType())
+
+	thirdPartyKeyStore[prefix.StorageKIDPrefix+skid] = mockstorage.DBEntry{Value: senderKey}
+
+	origMsg := []byte("secret message")
+	ct, err := authPacker.Pack(origMsg, []byte(skid), recipientsKeys)
+	require.NoError(t, err)
+
+	// DIDComm V2 envelopes use the JSON serialization even for a single recipient
+	jwe := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(ct, &jwe))
+
+	protected, err := base64.RawURLEncoding.DecodeString(jwe["protected"].(string))
+	require.NoError(t, err)
+	require.Contains(t, string(protected), `"typ":"application/didcomm-encrypted+json"`)
+
+	msg, err := authPacker.Unpack(ct)
+	require.NoError(t, err)
+	require.Equal(t, origMsg, msg.Message)
+}
+
 func TestAuthryptPackerUsingKeysWithDifferentCurvesSuccess(t *testing.T) {
 	k := createKMS(t)
 	_, recipientsKey1, keyHandles1 := createRecipients(t, k, 1)
@@ -0,0 +1,248 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jws
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	cryptoapi "github.com/hyperledger/aries-framework-go/pkg/crypto"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/transport"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/packer"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/jose"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/verifier"
+	vdrapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+)
+
+// Package jws includes a Packer implementation to build and parse DIDComm V2 signed envelopes (JWS). Signed envelopes
+// are not encrypted, they give a non-repudiable proof of the sender identity that can be checked by any third party
+// resolving the sender DID. A signed envelope may be nested in an authcrypt or anoncrypt envelope (sign then encrypt).
+
+const (
+	encodingType = transport.MediaTypeV2SignedEnvelope
+
+	ed25519VerificationKey2018 = "Ed25519VerificationKey2018"
+)
+
+// Packer represents a JWS Pack/Unpacker that outputs/reads DIDComm V2 signed envelopes.
+type Packer struct {
+	kms           kms.KeyManager
+	cryptoService cryptoapi.Crypto
+	vdrRegistry   vdrapi.Registry
+}
+
+// New will create a Packer instance to sign payloads with the sender key. The sender key is a DID URL referencing a
+// verification method of the sender DID doc, the DID URL fragment being the ID of the signing key in the KMS.
+// The VDR registry resolves the DID docs of the signers to select the signature algorithm and verify the signatures.
+func New(ctx packer.Provider, vdrRegistry vdrapi.Registry) (*Packer, error) {
+	k := ctx.KMS()
+	if k == nil {
+		return nil, errors.New("jws: failed to create packer because KMS is empty")
+	}
+
+	c := ctx.Crypto()
+	if c == nil {
+		return nil, errors.New("jws: failed to create packer because crypto service is empty")
+	}
+
+	if vdrRegistry == nil {
+		return nil, errors.New("jws: failed to create packer because VDR registry is empty")
+	}
+
+	return &Packer{
+		kms:           packer.NewDIDURLKeyManager(k),
+		cryptoService: c,
+		vdrRegistry:   vdrRegistry,
+	}, nil
+}
+
+// Pack will sign the payload argument with the sender key (a DID URL) and return the JWS in the general JSON
+// serialization. Signed envelopes are not encrypted, the recipients keys are not used.
+func (p *Packer) Pack(payload, senderKey []byte, _ [][]byte) ([]byte, error) {
+	kid := string(senderKey)
+	if kid == "" {
+		return nil, errors.New("jws Pack: empty sender key")
+	}
+
+	_, sigVerifier, err := p.resolveSigningKey(kid)
+	if err != nil {
+		return nil, fmt.Errorf("jws Pack: %w", err)
+	}
+
+	kh, err := p.kms.Get(kid)
+	if err != nil {
+		return nil, fmt.Errorf("jws Pack: failed to get sender key from KMS: %w", err)
+	}
+
+	signer := &kmsSigner{
+		crypto: p.cryptoService,
+		kh:     kh,
+		headers: jose.Headers{
+			jose.HeaderAlgorithm: sigVerifier.Algorithm(),
+			jose.HeaderKeyID:     kid,
+		},
+	}
+
+	jws, err := jose.NewJWS(jose.Headers{jose.HeaderType: encodingType}, nil, payload, signer)
+	if err != nil {
+		return nil, fmt.Errorf("jws Pack: %w", err)
+	}
+
+	s, err := jws.SerializeJSON(false)
+	if err != nil {
+		return nil, fmt.Errorf("jws Pack: failed to serialize JWS: %w", err)
+	}
+
+	return []byte(s), nil
+}
+
+// Unpack will verify the signatures of the JWS envelope against the keys of the signers DID docs and return the
+// payload. The envelope holds the verified signer (kid of the first signature) and its public key as the sender key.
+// The envelope is rejected if the signer DID is not the `from` DID of the payload.
+func (p *Packer) Unpack(envelope []byte) (*transport.Envelope, error) {
+	var (
+		signer    string
+		signerKey *verifier.PublicKey
+	)
+
+	jws, err := jose.ParseJWS(string(envelope), jose.SignatureVerifierFunc(
+		func(joseHeaders jose.Headers, _, signingInput, signature []byte) error {
+			kid, ok := joseHeaders.KeyID()
+			if !ok {
+				return errors.New("missing kid header")
+			}
+
+			pubKey, sigVerifier, e := p.resolveSigningKey(kid)
+			if e != nil {
+				return e
+			}
+
+			if alg, _ := joseHeaders.Algorithm(); alg != sigVerifier.Algorithm() {
+				return fmt.Errorf("alg %s does not match the key of %s", alg, kid)
+			}
+
+			if e = sigVerifier.Verify(pubKey, signingInput, signature); e != nil {
+				return fmt.Errorf("verify signature of %s: %w", kid, e)
+			}
+
+			if signer == "" {
+				signer, signerKey = kid, pubKey
+			}
+
+			return nil
+		}))
+	if err != nil {
+		return nil, fmt.Errorf("jws Unpack: %w", err)
+	}
+
+	if err = checkSender(jws.Payload, signer); err != nil {
+		return nil, fmt.Errorf("jws Unpack: %w", err)
+	}
+
+	fromKey := signerKey.Value
+	if signerKey.JWK != nil {
+		fromKey, err = signerKey.JWK.PublicKeyBytes()
+		if err != nil {
+			return nil, fmt.Errorf("jws Unpack: failed to get signer public key bytes: %w", err)
+		}
+	}
+
+	return &transport.Envelope{
+		Message: jws.Payload,
+		FromKey: fromKey,
+		Signer:  signer,
+	}, nil
+}
+
+// EncodingType for didcomm.
+func (p *Packer) EncodingType() string {
+	return encodingType
+}
+
+// checkSender checks the signer kid belongs to the sender DID given by the `from` field of the payload, if any.
+func checkSender(payload []byte, signer string) error {
+	msg := struct {
+		From string `json:"from,omitempty"`
+	}{}
+
+	if err := json.Unmarshal(payload, &msg); err != nil {
+		return fmt.Errorf("unmarshal payload: %w", err)
+	}
+
+	if msg.From != "" && didOf(msg.From) != didOf(signer) {
+		return fmt.Errorf("signer %s does not match the sender %s", signer, msg.From)
+	}
+
+	return nil
+}
+
+// didOf returns the DID of the DID URL, e.g. the DID of the kid of a signature.
+func didOf(didURL string) string {
+	return strings.Split(didURL, "#")[0]
+}
+
+// resolveSigningKey resolves the verification method referenced by the kid DID URL and returns its public key and
+// the matching signature verifier.
+func (p *Packer) resolveSigningKey(kid string) (*verifier.PublicKey, verifier.SignatureVerifier, error) {
+	didID := didOf(kid)
+
+	docResolution, err := p.vdrRegistry.Resolve(didID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("resolve DID %s: %w", didID, err)
+	}
+
+	vm := findVerificationMethod(docResolution.DIDDocument, kid)
+	if vm == nil {
+		return nil, nil, fmt.Errorf("authentication verification method %s not found in DID doc", kid)
+	}
+
+	pubKey := &verifier.PublicKey{Type: vm.Type, Value: vm.Value, JWK: vm.JSONWebKey()}
+
+	switch {
+	case vm.Type == ed25519VerificationKey2018, pubKey.JWK != nil && pubKey.JWK.Crv == "Ed25519":
+		return pubKey, verifier.NewEd25519SignatureVerifier(), nil
+	case pubKey.JWK != nil && pubKey.JWK.Crv == "P-256":
+		return pubKey, verifier.NewECDSAES256SignatureVerifier(), nil
+	case pubKey.JWK != nil && pubKey.JWK.Crv == "P-384":
+		return pubKey, verifier.NewECDSAES384SignatureVerifier(), nil
+	case pubKey.JWK != nil && pubKey.JWK.Crv == "P-521":
+		return pubKey, verifier.NewECDSAES521SignatureVerifier(), nil
+	}
+
+	return nil, nil, fmt.Errorf("unsupported verification method type %s", vm.Type)
+}
+
+// findVerificationMethod looks for the kid in the authentication verification methods of the DID doc, the keys
+// of the other verification relationships, e.g. key agreement, can't sign the envelopes.
+func findVerificationMethod(doc *did.Doc, kid string) *did.VerificationMethod {
+	for i := range doc.Authentication {
+		if vm := &doc.Authentication[i].VerificationMethod; vm.ID == kid || doc.ID+vm.ID == kid {
+			return vm
+		}
+	}
+
+	return nil
+}
+
+// kmsSigner signs the JWS with the key handle of the KMS.
+type kmsSigner struct {
+	crypto  cryptoapi.Crypto
+	kh      interface{}
+	headers jose.Headers
+}
+
+func (s *kmsSigner) Sign(data []byte) ([]byte, error) {
+	return s.crypto.Sign(data, s.kh)
+}
+
+func (s *kmsSigner) Headers() jose.Headers {
+	return s.headers
+}
@@ -0,0 +1,264 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jws
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/transport"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/jose"
+	vdrapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/localkms"
+	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
+	mockprovider "github.com/hyperledger/aries-framework-go/pkg/mock/provider"
+	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	mockvdr "github.com/hyperledger/aries-framework-go/pkg/mock/vdr"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock/noop"
+)
+
+const aliceDID = "did:example:alice"
+
+func TestNew(t *testing.T) {
+	cryptoSvc, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	_, err = New(&mockprovider.Provider{CryptoValue: cryptoSvc}, &mockvdr.MockVDRegistry{})
+	require.EqualError(t, err, "jws: failed to create packer because KMS is empty")
+
+	_, err = New(&mockprovider.Provider{KMSValue: createKMS(t)}, &mockvdr.MockVDRegistry{})
+	require.EqualError(t, err, "jws: failed to create packer because crypto service is empty")
+
+	_, err = New(&mockprovider.Provider{KMSValue: createKMS(t), CryptoValue: cryptoSvc}, nil)
+	require.EqualError(t, err, "jws: failed to create packer because VDR registry is empty")
+
+	p, err := New(&mockprovider.Provider{KMSValue: createKMS(t), CryptoValue: cryptoSvc}, &mockvdr.MockVDRegistry{})
+	require.NoError(t, err)
+	require.Equal(t, transport.MediaTypeV2SignedEnvelope, p.EncodingType())
+}
+
+func TestPackUnpack(t *testing.T) {
+	payload := []byte(`{"id":"1","type":"https://didcomm.org/basicmessage/2.0/message"}`)
+
+	t.Run("success - Ed25519 key", func(t *testing.T) {
+		k := createKMS(t)
+
+		kmsKID, pubKey, err := k.CreateAndExportPubKeyBytes(kms.ED25519Type)
+		require.NoError(t, err)
+
+		kid := aliceDID + "#" + kmsKID
+		p := newPacker(t, k, did.NewVerificationMethodFromBytes(kid, ed25519VerificationKey2018, aliceDID, pubKey))
+
+		envelope, err := p.Pack(payload, []byte(kid), nil)
+		require.NoError(t, err)
+
+		jws := map[string]interface{}{}
+		require.NoError(t, json.Unmarshal(envelope, &jws))
+		require.Len(t, jws["signatures"], 1)
+
+		unpacked, err := p.Unpack(envelope)
+		require.NoError(t, err)
+		require.Equal(t, payload, unpacked.Message)
+		require.Equal(t, kid, unpacked.Signer)
+		require.Equal(t, pubKey, unpacked.FromKey)
+	})
+
+	t.Run("success - P-256 JWK", func(t *testing.T) {
+		k := createKMS(t)
+
+		kmsKID, pubKey, err := k.CreateAndExportPubKeyBytes(kms.ECDSAP256TypeIEEEP1363)
+		require.NoError(t, err)
+
+		x, y := elliptic.Unmarshal(elliptic.P256(), pubKey)
+		require.NotNil(t, x)
+
+		jwk, err := jose.JWKFromPublicKey(&ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y})
+		require.NoError(t, err)
+
+		kid := aliceDID + "#" + kmsKID
+		vm, err := did.NewVerificationMethodFromJWK(kid, "JsonWebKey2020", aliceDID, jwk)
+		require.NoError(t, err)
+
+		p := newPacker(t, k, vm)
+
+		envelope, err := p.Pack(payload, []byte(kid), nil)
+		require.NoError(t, err)
+
+		unpacked, err := p.Unpack(envelope)
+		require.NoError(t, err)
+		require.Equal(t, payload, unpacked.Message)
+		require.Equal(t, kid, unpacked.Signer)
+	})
+
+	t.Run("fail - tampered payload", func(t *testing.T) {
+		k := createKMS(t)
+
+		kmsKID, pubKey, err := k.CreateAndExportPubKeyBytes(kms.ED25519Type)
+		require.NoError(t, err)
+
+		kid := aliceDID + "#" + kmsKID
+		p := newPacker(t, k, did.NewVerificationMethodFromBytes(kid, ed25519VerificationKey2018, aliceDID, pubKey))
+
+		envelope, err := p.Pack(payload, []byte(kid), nil)
+		require.NoError(t, err)
+
+		jws := map[string]interface{}{}
+		require.NoError(t, json.Unmarshal(envelope, &jws))
+
+		jws["payload"] = strings.ToUpper(jws["payload"].(string))
+
+		tampered, err := json.Marshal(jws)
+		require.NoError(t, err)
+
+		_, err = p.Unpack(tampered)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "verify signature of "+kid)
+	})
+
+	t.Run("fail - signer is not the sender", func(t *testing.T) {
+		k := createKMS(t)
+
+		kmsKID, pubKey, err := k.CreateAndExportPubKeyBytes(kms.ED25519Type)
+		require.NoError(t, err)
+
+		kid := aliceDID + "#" + kmsKID
+		p := newPacker(t, k, did.NewVerificationMethodFromBytes(kid, ed25519VerificationKey2018, aliceDID, pubKey))
+
+		envelope, err := p.Pack([]byte(`{"id":"1","from":"`+aliceDID+`"}`), []byte(kid), nil)
+		require.NoError(t, err)
+
+		unpacked, err := p.Unpack(envelope)
+		require.NoError(t, err)
+		require.Equal(t, kid, unpacked.Signer)
+
+		envelope, err = p.Pack([]byte(`{"id":"1","from":"did:example:mallory"}`), []byte(kid), nil)
+		require.NoError(t, err)
+
+		_, err = p.Unpack(envelope)
+		require.EqualError(t, err, "jws Unpack: signer "+kid+" does not match the sender did:example:mallory")
+
+		envelope, err = p.Pack([]byte("not a JSON message"), []byte(kid), nil)
+		require.NoError(t, err)
+
+		_, err = p.Unpack(envelope)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "jws Unpack: unmarshal payload")
+	})
+
+	t.Run("fail - signing key not found", func(t *testing.T) {
+		k := createKMS(t)
+
+		_, pubKey, err := k.CreateAndExportPubKeyBytes(kms.ED25519Type)
+		require.NoError(t, err)
+
+		kid := aliceDID + "#unknown"
+		p := newPacker(t, k, did.NewVerificationMethodFromBytes(kid, ed25519VerificationKey2018, aliceDID, pubKey))
+
+		_, err = p.Pack(payload, []byte(kid), nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "jws Pack: failed to get sender key from KMS")
+
+		_, err = p.Pack(payload, []byte(aliceDID+"#other"), nil)
+		require.EqualError(t, err, "jws Pack: authentication verification method did:example:alice#other not found in DID doc")
+
+		_, err = p.Pack(payload, nil, nil)
+		require.EqualError(t, err, "jws Pack: empty sender key")
+	})
+
+	t.Run("fail - key is not an authentication key", func(t *testing.T) {
+		k := createKMS(t)
+
+		kmsKID, pubKey, err := k.CreateAndExportPubKeyBytes(kms.ED25519Type)
+		require.NoError(t, err)
+
+		kid := aliceDID + "#" + kmsKID
+		signer := newPacker(t, k, did.NewVerificationMethodFromBytes(kid, ed25519VerificationKey2018, aliceDID, pubKey))
+
+		envelope, err := signer.Pack(payload, []byte(kid), nil)
+		require.NoError(t, err)
+
+		// the key of the envelope is in the DID doc, but not as the authentication key
+		p := newPacker(t, k, nil)
+		p.vdrRegistry = &mockvdr.MockVDRegistry{
+			ResolveFunc: func(didID string, _ ...vdrapi.ResolveOption) (*did.DocResolution, error) {
+				return &did.DocResolution{DIDDocument: &did.Doc{
+					ID: aliceDID,
+					VerificationMethod: []did.VerificationMethod{
+						*did.NewVerificationMethodFromBytes(kid, ed25519VerificationKey2018, aliceDID, pubKey),
+					},
+				}}, nil
+			},
+		}
+
+		_, err = p.Unpack(envelope)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "authentication verification method "+kid+" not found in DID doc")
+	})
+
+	t.Run("fail - unsupported verification method and resolve error", func(t *testing.T) {
+		k := createKMS(t)
+		kid := aliceDID + "#key-1"
+
+		p := newPacker(t, k, did.NewVerificationMethodFromBytes(kid, "RsaVerificationKey2018", aliceDID, []byte{1}))
+
+		_, err := p.Pack(payload, []byte(kid), nil)
+		require.EqualError(t, err, "jws Pack: unsupported verification method type RsaVerificationKey2018")
+
+		p.vdrRegistry = &mockvdr.MockVDRegistry{ResolveErr: errors.New("resolve error")}
+
+		_, err = p.Pack(payload, []byte(kid), nil)
+		require.EqualError(t, err, "jws Pack: resolve DID did:example:alice: resolve error")
+	})
+
+	t.Run("fail - invalid envelope", func(t *testing.T) {
+		p := newPacker(t, createKMS(t), nil)
+
+		_, err := p.Unpack([]byte(`{"payload":"abc"}`))
+		require.EqualError(t, err, "jws Unpack: JWS JSON has no signatures")
+	})
+}
+
+func newPacker(t *testing.T, k kms.KeyManager, vm *did.VerificationMethod) *Packer {
+	t.Helper()
+
+	cryptoSvc, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	doc := &did.Doc{ID: aliceDID}
+	if vm != nil {
+		doc.Authentication = []did.Verification{*did.NewReferencedVerification(vm, did.Authentication)}
+	}
+
+	p, err := New(&mockprovider.Provider{KMSValue: k, CryptoValue: cryptoSvc}, &mockvdr.MockVDRegistry{
+		ResolveFunc: func(didID string, _ ...vdrapi.ResolveOption) (*did.DocResolution, error) {
+			return &did.DocResolution{DIDDocument: doc}, nil
+		},
+	})
+	require.NoError(t, err)
+
+	return p
+}
+
+func createKMS(t *testing.T) *localkms.LocalKMS {
+	t.Helper()
+
+	p := mockkms.NewProviderForKMS(mockstorage.NewMockStoreProvider(), &noop.NoLock{})
+
+	k, err := localkms.New("local-lock://test/key/uri", p)
+	require.NoError(t, err)
+
+	return k
+}
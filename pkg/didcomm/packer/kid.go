@@ -0,0 +1,38 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package packer
+
+import (
+	"strings"
+
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+)
+
+// didURLKeyManager looks up the keys referenced by DID URL kids.
+type didURLKeyManager struct {
+	kms.KeyManager
+}
+
+// NewDIDURLKeyManager wraps the key manager to look up the keys referenced by the DIDComm V2 kids. These kids are DID
+// URLs referencing the keyAgreement verification methods of the DID docs, their fragment is the KMS key ID.
+func NewDIDURLKeyManager(km kms.KeyManager) kms.KeyManager {
+	return &didURLKeyManager{KeyManager: km}
+}
+
+// Get returns the key handle of the kid.
+func (k *didURLKeyManager) Get(kid string) (interface{}, error) {
+	return k.KeyManager.Get(KMSKeyID(kid))
+}
+
+// KMSKeyID returns the KMS key ID of the kid, ie the fragment of the DID URL kids.
+func KMSKeyID(kid string) string {
+	if i := strings.Index(kid, "#"); i >= 0 {
+		return kid[i+1:]
+	}
+
+	return kid
+}
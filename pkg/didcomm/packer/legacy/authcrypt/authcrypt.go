@@ -15,13 +15,19 @@ import (
 )
 
 // Packer represents an Authcrypt Pack/Unpacker that outputs/reads legacy Aries envelopes.
+// It also supports Anoncrypt, which is used when the sender key is not provided.
 type Packer struct {
 	randSource io.Reader
 	kms        kms.KeyManager
 }
 
-// encodingType is the `typ` string identifier in a message that identifies the format as being legacy.
-const encodingType string = "JWM/1.0"
+const (
+	// encodingType is the `typ` string identifier in a message that identifies the format as being legacy.
+	encodingType string = "JWM/1.0"
+
+	authcryptAlg = "Authcrypt"
+	anoncryptAlg = "Anoncrypt"
+)
 
 // New will create a Packer that encrypts messages using the legacy Aries format.
 // Note: legacy Packer does not support XChacha20Poly1035 (XC20P), only Chacha20Poly1035 (C20P).
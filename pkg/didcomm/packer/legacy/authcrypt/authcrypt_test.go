@@ -364,6 +364,28 @@ func TestDecrypt(t *testing.T) {
 		require.Equal(t, recKey, env.ToKey)
 	})
 
+	t.Run("Success: anoncrypt pack then unpack", func(t *testing.T) {
+		recKMS, _ := newKMS(t)
+		rec1Key := createKey(t, recKMS)
+
+		otherKMS, _ := newKMS(t)
+		rec2Key := createKey(t, otherKMS)
+
+		sendPacker := newWithKMSAndCrypto(t, testingKMS)
+		recPacker := newWithKMSAndCrypto(t, recKMS)
+
+		msgIn := []byte("Bright vixens jump; dozy fowl quack.")
+
+		enc, e := sendPacker.Pack(msgIn, nil, [][]byte{rec2Key, rec1Key})
+		require.NoError(t, e)
+
+		env, e := recPacker.Unpack(enc)
+		require.NoError(t, e)
+		require.Equal(t, msgIn, env.Message)
+		require.Empty(t, env.FromKey)
+		require.Equal(t, rec1Key, env.ToKey)
+	})
+
 	t.Run("Success: pack and unpack, different packers, including fail recipient who wasn't sent the message", func(t *testing.T) { // nolint: lll
 		rec1KMS, _ := newKMS(t)
 		rec1Key := createKey(t, rec1KMS)
@@ -530,12 +552,20 @@ func TestUnpackComponents(t *testing.T) {
 			"message type JSON not supported")
 	})
 
-	t.Run("Fail: anoncrypt not supported", func(t *testing.T) {
+	t.Run("Fail: anoncrypt bad CEK", func(t *testing.T) {
 		unpackComponentFailureTest(t,
 			`{"enc": "xchacha20poly1305_ietf", "typ": "JWM/1.0", "alg": "Anoncrypt", "recipients": [{"encrypted_key": "DaZGim_WCyntSdziFgnQanpQlR_tVHzHznGbW-yhTYDVgGuc5nr6J5svu7dQbBg3", "header": {"kid": "Ak528pLhb6DNFrGWY6HjMUjpNV613h2qtAJ47j1FYe8v", "sender": "wZ4cC42eDMeLApmJvJC4INbuKINzdZZECGHpWDgsrmBURPJN_bWOkUV3E6oORN4ILAf_xEuWefS4b_goRycCogkZvTyS1HgvBtx2YO1A2q-a7tp__08Ky4qtSiY=", "iv": "A818WMvddPrZ8mmYqp2iuu8gqoZZC2Hx"}}]}`, // nolint: lll
 			`"iv": "oDZpVO648Po3UcoW", "ciphertext": "pLrFQ6dND0aB4saHjSklcNTDAvpFPmIvebCis7S6UupzhhPOHwhp6o97_EphsWbwqqHl0HTiT7W9kUqrvd8jcWgx5EATtkx5o3PSyHfsfm9jl0tmKsqu6VG0RML_OokZiFv76ZUZuGMrHKxkCHGytILhlpSwajg=", "tag": "6GigdWnW59aC9Y8jhy76rA=="}`,                                                                                                                                                                                        //nolint: lll
 			recKeyPub, recKeyPriv,
-			"message format Anoncrypt not supported")
+			"failed to decrypt CEK")
+	})
+
+	t.Run("Fail: unsupported alg", func(t *testing.T) {
+		unpackComponentFailureTest(t,
+			`{"enc": "xchacha20poly1305_ietf", "typ": "JWM/1.0", "alg": "Plaintext", "recipients": []}`,
+			`"iv": "oDZpVO648Po3UcoW", "ciphertext": "pLrFQ6dND0aB4saHjSklcNTDAvpFPmIvebCis7S6UupzhhPOHwhp6o97_EphsWbwqqHl0HTiT7W9kUqrvd8jcWgx5EATtkx5o3PSyHfsfm9jl0tmKsqu6VG0RML_OokZiFv76ZUZuGMrHKxkCHGytILhlpSwajg=", "tag": "6GigdWnW59aC9Y8jhy76rA=="}`, // nolint: lll
+			recKeyPub, recKeyPriv,
+			"message format Plaintext not supported")
 	})
 
 	t.Run("Fail: no recipients in header", func(t *testing.T) {
@@ -667,7 +697,7 @@ func Test_getCEK(t *testing.T) {
 		},
 	}
 
-	_, err := getCEK(recs, &k)
+	_, err := getCEK(recs, &k, false)
 	require.EqualError(t, err, "getCEK: no key accessible none of the recipient keys were found in kms")
 }
 
@@ -24,6 +24,7 @@ import (
 
 // Pack will encode the payload argument
 // Using the protocol defined by Aries RFC 0019.
+// The payload is packed with Anoncrypt if the sender key is empty.
 func (p *Packer) Pack(payload, sender []byte, recipientPubKeys [][]byte) ([]byte, error) {
 	var err error
 
@@ -53,10 +54,15 @@ func (p *Packer) Pack(payload, sender []byte, recipientPubKeys [][]byte) ([]byte
 		return nil, fmt.Errorf("pack: failed to build recipients: %w", err)
 	}
 
+	alg := authcryptAlg
+	if len(sender) == 0 {
+		alg = anoncryptAlg
+	}
+
 	header := protected{
 		Enc:        "chacha20poly1305_ietf",
 		Typ:        encodingType,
-		Alg:        "Authcrypt",
+		Alg:        alg,
 		Recipients: recipients,
 	}
 
@@ -104,7 +110,17 @@ func (p *Packer) buildRecipients(cek *[chacha.KeySize]byte, senderKey []byte, re
 	encodedRecipients := make([]recipient, len(recPubKeys))
 
 	for i, recKey := range recPubKeys {
-		rec, err := p.buildRecipient(cek, senderKey, recKey)
+		var (
+			rec *recipient
+			err error
+		)
+
+		if len(senderKey) == 0 {
+			rec, err = p.buildAnonRecipient(cek, recKey)
+		} else {
+			rec, err = p.buildRecipient(cek, senderKey, recKey)
+		}
+
 		if err != nil {
 			return nil, fmt.Errorf("buildRecipients: failed to build recipient: %w", err)
 		}
@@ -161,6 +177,32 @@ func (p *Packer) buildRecipient(cek *[chacha.KeySize]byte, senderKey, recKey []b
 	}, nil
 }
 
+// buildAnonRecipient encodes the necessary data for the recipient to decrypt the message
+// sealing the CEK, the sender is not disclosed.
+func (p *Packer) buildAnonRecipient(cek *[chacha.KeySize]byte, recKey []byte) (*recipient, error) {
+	recEncKey, err := cryptoutil.PublicEd25519toCurve25519(recKey)
+	if err != nil {
+		return nil, fmt.Errorf("buildAnonRecipient: failed to convert public Ed25519 to Curve25519: %w", err)
+	}
+
+	box, err := newCryptoBox(p.kms)
+	if err != nil {
+		return nil, fmt.Errorf("buildAnonRecipient: failed to create new CryptoBox: %w", err)
+	}
+
+	encCEK, err := box.Seal(cek[:], recEncKey, p.randSource)
+	if err != nil {
+		return nil, fmt.Errorf("buildAnonRecipient: failed to encrypt cek: %w", err)
+	}
+
+	return &recipient{
+		EncryptedKey: base64.URLEncoding.EncodeToString(encCEK),
+		Header: recipientHeader{
+			KID: base58.Encode(recKey), // recKey is the Ed25519 recipient pk in b58 encoding
+		},
+	}, nil
+}
+
 func newCryptoBox(manager kms.KeyManager) (kms.CryptoBox, error) {
 	switch manager.(type) {
 	case *localkms.LocalKMS:
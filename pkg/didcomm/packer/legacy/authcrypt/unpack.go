@@ -47,12 +47,11 @@ func (p *Packer) Unpack(envelope []byte) (*transport.Envelope, error) {
 		return nil, fmt.Errorf("message type %s not supported", protectedData.Typ)
 	}
 
-	if protectedData.Alg != "Authcrypt" {
-		// TODO https://github.com/hyperledger/aries-framework-go/issues/41 change this when anoncrypt is introduced
+	if protectedData.Alg != authcryptAlg && protectedData.Alg != anoncryptAlg {
 		return nil, fmt.Errorf("message format %s not supported", protectedData.Alg)
 	}
 
-	keys, err := getCEK(protectedData.Recipients, p.kms)
+	keys, err := getCEK(protectedData.Recipients, p.kms, protectedData.Alg == anoncryptAlg)
 	if err != nil {
 		return nil, err
 	}
@@ -74,7 +73,7 @@ type keys struct {
 	myKey    []byte
 }
 
-func getCEK(recipients []recipient, km kms.KeyManager) (*keys, error) {
+func getCEK(recipients []recipient, km kms.KeyManager, anoncrypt bool) (*keys, error) {
 	var candidateKeys []string
 
 	for _, candidate := range recipients {
@@ -89,6 +88,10 @@ func getCEK(recipients []recipient, km kms.KeyManager) (*keys, error) {
 	recip := recipients[recKeyIdx]
 	recKey := base58.Decode(recip.Header.KID)
 
+	if anoncrypt {
+		return getAnonCEK(recip, recKey, km)
+	}
+
 	senderPub, senderPubCurve, err := decodeSender(recip.Header.Sender, recKey, km)
 	if err != nil {
 		return nil, err
@@ -125,6 +128,33 @@ func getCEK(recipients []recipient, km kms.KeyManager) (*keys, error) {
 	}, nil
 }
 
+// getAnonCEK opens the sealed CEK, the sender of the Anoncrypt message is unknown.
+func getAnonCEK(recip recipient, recKey []byte, km kms.KeyManager) (*keys, error) {
+	encCEK, err := base64.URLEncoding.DecodeString(recip.EncryptedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := newCryptoBox(km)
+	if err != nil {
+		return nil, err
+	}
+
+	cekSlice, err := b.SealOpen(encCEK, recKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt CEK: %w", err)
+	}
+
+	var cek [chacha.KeySize]byte
+
+	copy(cek[:], cekSlice)
+
+	return &keys{
+		cek:   &cek,
+		myKey: recKey,
+	}, nil
+}
+
 func findVerKey(km kms.KeyManager, candidateKeys []string) (int, error) {
 	for i, key := range candidateKeys {
 		recKID, err := localkms.CreateKID(base58.Decode(key), kms.ED25519Type)
@@ -0,0 +1,61 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package actionmenu
+
+import "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+
+// Menu is the menu of the actions the responder offers to the requester.
+type Menu struct {
+	Type        string            `json:"@type,omitempty"`
+	ID          string            `json:"@id,omitempty"`
+	Title       string            `json:"title,omitempty"`
+	Description string            `json:"description,omitempty"`
+	ErrorMsg    string            `json:"errormsg,omitempty"`
+	Options     []MenuOption      `json:"options"`
+	Thread      *decorator.Thread `json:"~thread,omitempty"`
+}
+
+// MenuOption is the action of the menu.
+type MenuOption struct {
+	Name        string `json:"name"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Disabled    bool   `json:"disabled,omitempty"`
+	Form        *Form  `json:"form,omitempty"`
+}
+
+// Form describes the parameters of the action the requester fills in before performing it.
+type Form struct {
+	Description string      `json:"description,omitempty"`
+	Params      []FormParam `json:"params,omitempty"`
+	SubmitLabel string      `json:"submit-label,omitempty"`
+}
+
+// FormParam is the parameter of the form.
+type FormParam struct {
+	Name        string `json:"name"`
+	Title       string `json:"title,omitempty"`
+	Default     string `json:"default,omitempty"`
+	Description string `json:"description,omitempty"`
+	Type        string `json:"type,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+// MenuRequest requests the menu from the responder.
+type MenuRequest struct {
+	Type string `json:"@type,omitempty"`
+	ID   string `json:"@id,omitempty"`
+}
+
+// Perform asks the responder to perform the action of the menu.
+type Perform struct {
+	Type   string            `json:"@type,omitempty"`
+	ID     string            `json:"@id,omitempty"`
+	Name   string            `json:"name"`
+	Params map[string]string `json:"params,omitempty"`
+	Thread *decorator.Thread `json:"~thread,omitempty"`
+}
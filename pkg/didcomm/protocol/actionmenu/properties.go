@@ -0,0 +1,56 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package actionmenu
+
+const (
+	connectionIDPropKey = "connectionID"
+	myDIDPropKey        = "myDID"
+	theirDIDPropKey     = "theirDID"
+	piidPropKey         = "piid"
+)
+
+type eventProps struct {
+	connectionID string
+	myDID        string
+	theirDID     string
+	piid         string
+}
+
+func newEventProps(action *Action) *eventProps {
+	return &eventProps{
+		connectionID: action.ConnectionID,
+		myDID:        action.MyDID,
+		theirDID:     action.TheirDID,
+		piid:         action.PIID,
+	}
+}
+
+func (e *eventProps) ConnectionID() string {
+	return e.connectionID
+}
+
+func (e *eventProps) MyDID() string {
+	return e.myDID
+}
+
+func (e *eventProps) TheirDID() string {
+	return e.theirDID
+}
+
+func (e *eventProps) PIID() string {
+	return e.piid
+}
+
+// All implements EventProperties interface.
+func (e *eventProps) All() map[string]interface{} {
+	return map[string]interface{}{
+		connectionIDPropKey: e.connectionID,
+		myDIDPropKey:        e.myDID,
+		theirDIDPropKey:     e.theirDID,
+		piidPropKey:         e.piid,
+	}
+}
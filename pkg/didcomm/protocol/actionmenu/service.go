@@ -0,0 +1,530 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package actionmenu
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/dispatcher"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+)
+
+const (
+	// ActionMenu defines the protocol name.
+	ActionMenu = "actionmenu"
+	// Spec defines the protocol spec.
+	Spec = "https://didcomm.org/action-menu/1.0/"
+	// MenuMsgType defines the action menu menu message type.
+	MenuMsgType = Spec + "menu"
+	// MenuRequestMsgType defines the action menu menu-request message type.
+	MenuRequestMsgType = Spec + "menu-request"
+	// PerformMsgType defines the action menu perform message type.
+	PerformMsgType = Spec + "perform"
+)
+
+// States of the inbound messages reported by the message events.
+const (
+	StateMenuReceived  = "menu-received"
+	StateMenuRequested = "menu-requested"
+	StatePerformed     = "performed"
+)
+
+const (
+	// Namespace is namespace of action menu store name.
+	Namespace = "actionmenu"
+
+	publishedMenuKey       = "published_%s"
+	receivedMenuKey        = "received_%s"
+	transitionalPayloadKey = "transitionalPayload_%s"
+)
+
+// ErrMenuNotFound is returned when there is no menu for the connection.
+var ErrMenuNotFound = errors.New("menu not found")
+
+var logger = log.New("aries-framework/actionmenu")
+
+type provider interface {
+	OutboundDispatcher() dispatcher.Outbound
+	StorageProvider() storage.Provider
+	ProtocolStateStorageProvider() storage.Provider
+}
+
+type connections interface {
+	GetConnectionRecord(string) (*connection.Record, error)
+	GetConnectionIDByDIDs(myDID, theirDID string) (string, error)
+}
+
+// Action contains helpful information about action.
+type Action struct {
+	// Protocol instance ID, the ID of the menu request or the perform message
+	PIID         string
+	Msg          service.DIDCommMsgMap
+	ConnectionID string
+	MyDID        string
+	TheirDID     string
+}
+
+// Opt describes option signature for the Continue function.
+type Opt func(opts *continueOpts)
+
+type continueOpts struct {
+	menu *Menu
+}
+
+// WithMenu sets the menu to send in response to the menu request or the perform.
+// The published menu of the connection is sent in response to the menu request by default.
+func WithMenu(menu *Menu) Opt {
+	return func(opts *continueOpts) {
+		opts.menu = menu
+	}
+}
+
+// Service for the action menu protocol. The menus received from the connections are saved and reported with
+// the message events. The menu requests and the performs trigger the action events, the published menu of
+// the connection is sent automatically in response to the menu request if nobody listens to the action events.
+type Service struct {
+	service.Action
+	service.Message
+	outbound         dispatcher.Outbound
+	connectionLookup connections
+	store            storage.Store
+	actionStore      storage.Store
+}
+
+// New returns the action menu service.
+func New(prov provider) (*Service, error) {
+	store, err := prov.StorageProvider().OpenStore(Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("open action menu store: %w", err)
+	}
+
+	actionStore, err := prov.ProtocolStateStorageProvider().OpenStore(Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("open action menu protocol state store: %w", err)
+	}
+
+	err = prov.ProtocolStateStorageProvider().SetStoreConfig(Namespace,
+		storage.StoreConfiguration{TagNames: []string{transitionalPayloadKey}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set store config in protocol state store: %w", err)
+	}
+
+	connectionLookup, err := connection.NewLookup(prov)
+	if err != nil {
+		return nil, fmt.Errorf("new connection lookup: %w", err)
+	}
+
+	return &Service{
+		outbound:         prov.OutboundDispatcher(),
+		connectionLookup: connectionLookup,
+		store:            store,
+		actionStore:      actionStore,
+	}, nil
+}
+
+// HandleInbound handles inbound action menu messages.
+func (s *Service) HandleInbound(msg service.DIDCommMsg, myDID, theirDID string) (string, error) {
+	if !s.Accept(msg.Type()) {
+		return "", fmt.Errorf("unsupported message type %s", msg.Type())
+	}
+
+	connectionID, err := s.connectionLookup.GetConnectionIDByDIDs(myDID, theirDID)
+	if err != nil {
+		return "", fmt.Errorf("get connection ID by DIDs: %w", err)
+	}
+
+	action := &Action{
+		PIID:         msg.ID(),
+		Msg:          msg.Clone(),
+		ConnectionID: connectionID,
+		MyDID:        myDID,
+		TheirDID:     theirDID,
+	}
+
+	switch msg.Type() {
+	case MenuMsgType:
+		return "", s.handleMenu(action)
+	case MenuRequestMsgType:
+		return "", s.handleMenuRequest(action)
+	default:
+		return "", s.handlePerform(action)
+	}
+}
+
+// HandleOutbound adherence to dispatcher.ProtocolService.
+func (s *Service) HandleOutbound(_ service.DIDCommMsg, _, _ string) (string, error) {
+	return "", errors.New("not implemented")
+}
+
+// Accept checks whether the service can handle the message type.
+func (s *Service) Accept(msgType string) bool {
+	switch msgType {
+	case MenuMsgType, MenuRequestMsgType, PerformMsgType:
+		return true
+	}
+
+	return false
+}
+
+// MessageTypes returns the message types handled by the service.
+func (s *Service) MessageTypes() []string {
+	return []string{
+		MenuMsgType, MenuRequestMsgType, PerformMsgType,
+	}
+}
+
+// Name of the service.
+func (s *Service) Name() string {
+	return ActionMenu
+}
+
+// PublishMenu saves the menu of the connection, which is sent in response to the menu requests.
+func (s *Service) PublishMenu(connectionID string, menu *Menu) error {
+	if _, err := s.connectionLookup.GetConnectionRecord(connectionID); err != nil {
+		return fmt.Errorf("get connection record: %w", err)
+	}
+
+	return s.saveMenu(publishedMenuKey, connectionID, menu)
+}
+
+// PublishedMenu returns the menu published for the connection.
+func (s *Service) PublishedMenu(connectionID string) (*Menu, error) {
+	return s.getMenu(publishedMenuKey, connectionID)
+}
+
+// SendMenu publishes the menu of the connection and sends it to the connection.
+func (s *Service) SendMenu(connectionID string, menu *Menu) error {
+	conn, err := s.connectionLookup.GetConnectionRecord(connectionID)
+	if err != nil {
+		return fmt.Errorf("get connection record: %w", err)
+	}
+
+	if err = s.saveMenu(publishedMenuKey, connectionID, menu); err != nil {
+		return err
+	}
+
+	return s.sendMenu(menu, "", conn.MyDID, conn.TheirDID)
+}
+
+// RequestMenu requests the menu from the connection.
+func (s *Service) RequestMenu(connectionID string) error {
+	conn, err := s.connectionLookup.GetConnectionRecord(connectionID)
+	if err != nil {
+		return fmt.Errorf("get connection record: %w", err)
+	}
+
+	err = s.outbound.SendToDID(&MenuRequest{
+		Type: MenuRequestMsgType,
+		ID:   uuid.New().String(),
+	}, conn.MyDID, conn.TheirDID)
+	if err != nil {
+		return fmt.Errorf("send menu request: %w", err)
+	}
+
+	return nil
+}
+
+// Menu returns the last menu received from the connection.
+func (s *Service) Menu(connectionID string) (*Menu, error) {
+	return s.getMenu(receivedMenuKey, connectionID)
+}
+
+// Perform asks the connection to perform the action of its menu.
+func (s *Service) Perform(connectionID, name string, params map[string]string) error {
+	conn, err := s.connectionLookup.GetConnectionRecord(connectionID)
+	if err != nil {
+		return fmt.Errorf("get connection record: %w", err)
+	}
+
+	perform := &Perform{
+		Type:   PerformMsgType,
+		ID:     uuid.New().String(),
+		Name:   name,
+		Params: params,
+	}
+
+	menu, err := s.Menu(connectionID)
+
+	switch {
+	case err == nil:
+		perform.Thread = &decorator.Thread{ID: menu.ID}
+	case !errors.Is(err, ErrMenuNotFound):
+		return err
+	}
+
+	if err = s.outbound.SendToDID(perform, conn.MyDID, conn.TheirDID); err != nil {
+		return fmt.Errorf("send perform: %w", err)
+	}
+
+	return nil
+}
+
+// ActionContinue allows proceeding with the action by the piID.
+func (s *Service) ActionContinue(piID string, opt Opt) error {
+	action, err := s.getTransitionalPayload(piID)
+	if err != nil {
+		return fmt.Errorf("get transitional payload: %w", err)
+	}
+
+	opts := &continueOpts{}
+
+	if opt != nil {
+		opt(opts)
+	}
+
+	return s.continueAction(action, opts)
+}
+
+// ActionStop allows stopping the action by the piID. Nothing is sent to the connection.
+func (s *Service) ActionStop(piID string, _ error) error {
+	if _, err := s.getTransitionalPayload(piID); err != nil {
+		return fmt.Errorf("get transitional payload: %w", err)
+	}
+
+	if err := s.deleteTransitionalPayload(piID); err != nil {
+		return fmt.Errorf("delete transitional payload: %w", err)
+	}
+
+	return nil
+}
+
+// Actions returns actions for the async usage.
+func (s *Service) Actions() ([]Action, error) {
+	records, err := s.actionStore.Query(transitionalPayloadKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the store: %w", err)
+	}
+
+	defer storage.Close(records, logger)
+
+	var actions []Action
+
+	more, err := records.Next()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get next record: %w", err)
+	}
+
+	for more {
+		value, errValue := records.Value()
+		if errValue != nil {
+			return nil, fmt.Errorf("failed to get value: %w", errValue)
+		}
+
+		var action Action
+		if errUnmarshal := json.Unmarshal(value, &action); errUnmarshal != nil {
+			return nil, fmt.Errorf("unmarshal: %w", errUnmarshal)
+		}
+
+		actions = append(actions, action)
+
+		more, err = records.Next()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get next record: %w", err)
+		}
+	}
+
+	return actions, nil
+}
+
+func (s *Service) handleMenu(action *Action) error {
+	menu := &Menu{}
+
+	if err := action.Msg.Decode(menu); err != nil {
+		return fmt.Errorf("decode menu: %w", err)
+	}
+
+	if err := s.saveMenu(receivedMenuKey, action.ConnectionID, menu); err != nil {
+		return err
+	}
+
+	s.sendMsgEvents(action, StateMenuReceived)
+
+	return nil
+}
+
+func (s *Service) handleMenuRequest(action *Action) error {
+	s.sendMsgEvents(action, StateMenuRequested)
+
+	events := s.ActionEvent()
+	if events == nil {
+		return s.continueAction(action, &continueOpts{})
+	}
+
+	return s.triggerActionEvent(events, action)
+}
+
+func (s *Service) handlePerform(action *Action) error {
+	perform := &Perform{}
+
+	if err := action.Msg.Decode(perform); err != nil {
+		return fmt.Errorf("decode perform: %w", err)
+	}
+
+	s.sendMsgEvents(action, StatePerformed)
+
+	events := s.ActionEvent()
+	if events == nil {
+		return fmt.Errorf("no clients registered to handle action events for %s protocol", ActionMenu)
+	}
+
+	return s.triggerActionEvent(events, action)
+}
+
+func (s *Service) triggerActionEvent(events chan<- service.DIDCommAction, action *Action) error {
+	if err := s.saveTransitionalPayload(action); err != nil {
+		return fmt.Errorf("save transitional payload: %w", err)
+	}
+
+	events <- service.DIDCommAction{
+		ProtocolName: ActionMenu,
+		Message:      action.Msg.Clone(),
+		Continue: func(args interface{}) {
+			opts := &continueOpts{}
+
+			if fn, ok := args.(Opt); ok {
+				fn(opts)
+			}
+
+			if err := s.continueAction(action, opts); err != nil {
+				logger.Errorf("continue action %s: %s", action.PIID, err)
+			}
+		},
+		Stop: func(error) {
+			if err := s.deleteTransitionalPayload(action.PIID); err != nil {
+				logger.Errorf("delete transitional payload: %s", err)
+			}
+		},
+		Properties: newEventProps(action),
+	}
+
+	return nil
+}
+
+// continueAction sends the menu in response to the menu request or the perform.
+func (s *Service) continueAction(action *Action, opts *continueOpts) error {
+	if err := s.deleteTransitionalPayload(action.PIID); err != nil {
+		return fmt.Errorf("delete transitional payload: %w", err)
+	}
+
+	menu := opts.menu
+
+	if menu == nil && action.Msg.Type() == MenuRequestMsgType {
+		var err error
+
+		menu, err = s.PublishedMenu(action.ConnectionID)
+		if err != nil {
+			return fmt.Errorf("menu request: %w", err)
+		}
+	}
+
+	if menu == nil {
+		return nil
+	}
+
+	return s.sendMenu(menu, action.PIID, action.MyDID, action.TheirDID)
+}
+
+func (s *Service) sendMenu(menu *Menu, thID, myDID, theirDID string) error {
+	msg := *menu
+	msg.Type = MenuMsgType
+	msg.Thread = nil
+
+	if msg.ID == "" {
+		msg.ID = uuid.New().String()
+	}
+
+	if thID != "" {
+		msg.Thread = &decorator.Thread{ID: thID}
+	}
+
+	if err := s.outbound.SendToDID(&msg, myDID, theirDID); err != nil {
+		return fmt.Errorf("send menu: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) sendMsgEvents(action *Action, stateID string) {
+	for _, handler := range s.MsgEvents() {
+		handler <- service.StateMsg{
+			ProtocolName: ActionMenu,
+			Type:         service.PostState,
+			Msg:          action.Msg.Clone(),
+			StateID:      stateID,
+			Properties:   newEventProps(action),
+		}
+	}
+}
+
+func (s *Service) saveMenu(keyFormat, connectionID string, menu *Menu) error {
+	src, err := json.Marshal(menu)
+	if err != nil {
+		return fmt.Errorf("marshal menu: %w", err)
+	}
+
+	if err = s.store.Put(fmt.Sprintf(keyFormat, connectionID), src); err != nil {
+		return fmt.Errorf("save menu: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) getMenu(keyFormat, connectionID string) (*Menu, error) {
+	src, err := s.store.Get(fmt.Sprintf(keyFormat, connectionID))
+	if errors.Is(err, storage.ErrDataNotFound) {
+		return nil, ErrMenuNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("get menu: %w", err)
+	}
+
+	menu := &Menu{}
+
+	if err = json.Unmarshal(src, menu); err != nil {
+		return nil, fmt.Errorf("unmarshal menu: %w", err)
+	}
+
+	return menu, nil
+}
+
+func (s *Service) saveTransitionalPayload(action *Action) error {
+	src, err := json.Marshal(action)
+	if err != nil {
+		return fmt.Errorf("marshal transitional payload: %w", err)
+	}
+
+	return s.actionStore.Put(fmt.Sprintf(transitionalPayloadKey, action.PIID), src,
+		storage.Tag{Name: transitionalPayloadKey})
+}
+
+func (s *Service) getTransitionalPayload(id string) (*Action, error) {
+	src, err := s.actionStore.Get(fmt.Sprintf(transitionalPayloadKey, id))
+	if err != nil {
+		return nil, fmt.Errorf("store get: %w", err)
+	}
+
+	action := &Action{}
+
+	if err = json.Unmarshal(src, action); err != nil {
+		return nil, fmt.Errorf("unmarshal transitional payload: %w", err)
+	}
+
+	return action, nil
+}
+
+func (s *Service) deleteTransitionalPayload(id string) error {
+	return s.actionStore.Delete(fmt.Sprintf(transitionalPayloadKey, id))
+}
@@ -0,0 +1,407 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package actionmenu
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	mockdispatcher "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/dispatcher"
+	mockprovider "github.com/hyperledger/aries-framework-go/pkg/mock/provider"
+	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+)
+
+const (
+	aliceDID = "did:example:alice"
+	bobDID   = "did:example:bob"
+	connID   = "conn-1"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		svc, err := New(newProvider(&mockdispatcher.MockOutbound{}))
+		require.NoError(t, err)
+		require.Equal(t, ActionMenu, svc.Name())
+		require.True(t, svc.Accept(MenuMsgType))
+		require.True(t, svc.Accept(MenuRequestMsgType))
+		require.True(t, svc.Accept(PerformMsgType))
+		require.False(t, svc.Accept("unknown"))
+	})
+
+	t.Run("open store error", func(t *testing.T) {
+		_, err := New(&mockprovider.Provider{
+			StorageProviderValue: &mockstorage.MockStoreProvider{
+				ErrOpenStoreHandle: errors.New("test error"),
+			},
+			ProtocolStateStorageProviderValue: mem.NewProvider(),
+		})
+		require.EqualError(t, err, "open action menu store: test error")
+
+		_, err = New(&mockprovider.Provider{
+			StorageProviderValue: mem.NewProvider(),
+			ProtocolStateStorageProviderValue: &mockstorage.MockStoreProvider{
+				ErrOpenStoreHandle: errors.New("test error"),
+			},
+		})
+		require.EqualError(t, err, "open action menu protocol state store: test error")
+	})
+}
+
+func TestService_MenuRoundTrip(t *testing.T) {
+	t.Run("published menu is sent in response to the menu request", func(t *testing.T) {
+		alice, bob := newConnectedServices(t)
+
+		states := make(chan service.StateMsg, 1)
+		require.NoError(t, bob.RegisterMsgEvent(states))
+
+		require.NoError(t, alice.PublishMenu(connID, testMenu()))
+		require.NoError(t, bob.RequestMenu(connID))
+
+		select {
+		case state := <-states:
+			require.Equal(t, StateMenuReceived, state.StateID)
+			require.Equal(t, connID, state.Properties.All()["connectionID"])
+		case <-time.After(time.Second):
+			require.Fail(t, "menu was not received")
+		}
+
+		menu, err := bob.Menu(connID)
+		require.NoError(t, err)
+		require.Equal(t, "Issuer bot", menu.Title)
+		require.Len(t, menu.Options, 2)
+		require.NotEmpty(t, menu.Thread.ID)
+
+		published, err := alice.PublishedMenu(connID)
+		require.NoError(t, err)
+		require.Equal(t, testMenu(), published)
+	})
+
+	t.Run("perform triggers the action event, the new menu is sent in response", func(t *testing.T) {
+		alice, bob := newConnectedServices(t)
+
+		actions := make(chan service.DIDCommAction, 1)
+		require.NoError(t, alice.RegisterActionEvent(actions))
+
+		states := make(chan service.StateMsg, 2)
+		require.NoError(t, bob.RegisterMsgEvent(states))
+
+		require.NoError(t, alice.SendMenu(connID, testMenu()))
+		require.Equal(t, StateMenuReceived, (<-states).StateID)
+
+		received, err := bob.Menu(connID)
+		require.NoError(t, err)
+
+		require.NoError(t, bob.Perform(connID, "issue", map[string]string{"name": "Bob"}))
+
+		action := <-actions
+		require.Equal(t, ActionMenu, action.ProtocolName)
+		require.Equal(t, PerformMsgType, action.Message.Type())
+
+		perform := &Perform{}
+		require.NoError(t, action.Message.Decode(perform))
+		require.Equal(t, "issue", perform.Name)
+		require.Equal(t, "Bob", perform.Params["name"])
+		require.Equal(t, received.ID, perform.Thread.ID)
+
+		pending, err := alice.Actions()
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+		require.Equal(t, connID, pending[0].ConnectionID)
+
+		action.Continue(WithMenu(&Menu{Title: "Issued", Options: []MenuOption{}}))
+
+		state := <-states
+		require.Equal(t, StateMenuReceived, state.StateID)
+
+		menu, err := bob.Menu(connID)
+		require.NoError(t, err)
+		require.Equal(t, "Issued", menu.Title)
+		require.Equal(t, perform.ID, menu.Thread.ID)
+
+		pending, err = alice.Actions()
+		require.NoError(t, err)
+		require.Empty(t, pending)
+	})
+}
+
+func TestService_Actions(t *testing.T) {
+	t.Run("menu request is continued with the menu", func(t *testing.T) {
+		var sent *Menu
+
+		svc, err := New(newProvider(&mockdispatcher.MockOutbound{
+			ValidateSendToDID: func(msg interface{}, myDID, theirDID string) error {
+				sent = msg.(*Menu)
+
+				return nil
+			},
+		}))
+		require.NoError(t, err)
+
+		saveConnection(t, svc, aliceDID, bobDID)
+
+		actions := make(chan service.DIDCommAction, 1)
+		require.NoError(t, svc.RegisterActionEvent(actions))
+
+		_, err = svc.HandleInbound(service.NewDIDCommMsgMap(&MenuRequest{
+			Type: MenuRequestMsgType, ID: "request-1",
+		}), aliceDID, bobDID)
+		require.NoError(t, err)
+
+		action := <-actions
+		require.Equal(t, "request-1", action.Properties.All()["piid"])
+
+		require.NoError(t, svc.ActionContinue("request-1", WithMenu(testMenu())))
+		require.NotNil(t, sent)
+		require.Equal(t, MenuMsgType, sent.Type)
+		require.Equal(t, "request-1", sent.Thread.ID)
+
+		err = svc.ActionContinue("request-1", nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "get transitional payload")
+	})
+
+	t.Run("menu request without the published menu", func(t *testing.T) {
+		svc, err := New(newProvider(&mockdispatcher.MockOutbound{}))
+		require.NoError(t, err)
+
+		saveConnection(t, svc, aliceDID, bobDID)
+
+		_, err = svc.HandleInbound(service.NewDIDCommMsgMap(&MenuRequest{
+			Type: MenuRequestMsgType, ID: "request-1",
+		}), aliceDID, bobDID)
+		require.True(t, errors.Is(err, ErrMenuNotFound))
+	})
+
+	t.Run("action is stopped", func(t *testing.T) {
+		svc, err := New(newProvider(&mockdispatcher.MockOutbound{
+			ValidateSendToDID: func(msg interface{}, myDID, theirDID string) error {
+				return errors.New("unexpected message")
+			},
+		}))
+		require.NoError(t, err)
+
+		saveConnection(t, svc, aliceDID, bobDID)
+
+		actions := make(chan service.DIDCommAction, 2)
+		require.NoError(t, svc.RegisterActionEvent(actions))
+
+		for _, id := range []string{"perform-1", "perform-2"} {
+			_, err = svc.HandleInbound(service.NewDIDCommMsgMap(&Perform{
+				Type: PerformMsgType, ID: id, Name: "issue",
+			}), aliceDID, bobDID)
+			require.NoError(t, err)
+		}
+
+		(<-actions).Stop(errors.New("declined"))
+		require.NoError(t, svc.ActionStop("perform-2", nil))
+
+		pending, err := svc.Actions()
+		require.NoError(t, err)
+		require.Empty(t, pending)
+
+		err = svc.ActionStop("perform-2", nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "get transitional payload")
+
+		// continuing the perform without the menu sends nothing
+		_, err = svc.HandleInbound(service.NewDIDCommMsgMap(&Perform{
+			Type: PerformMsgType, ID: "perform-3", Name: "issue",
+		}), aliceDID, bobDID)
+		require.NoError(t, err)
+		require.NoError(t, svc.ActionContinue("perform-3", nil))
+	})
+
+	t.Run("perform without action listeners", func(t *testing.T) {
+		svc, err := New(newProvider(&mockdispatcher.MockOutbound{}))
+		require.NoError(t, err)
+
+		saveConnection(t, svc, aliceDID, bobDID)
+
+		_, err = svc.HandleInbound(service.NewDIDCommMsgMap(&Perform{
+			Type: PerformMsgType, ID: "perform-1", Name: "issue",
+		}), aliceDID, bobDID)
+		require.EqualError(t, err, "no clients registered to handle action events for actionmenu protocol")
+	})
+}
+
+func TestService_HandleInbound(t *testing.T) {
+	t.Run("unknown connection", func(t *testing.T) {
+		svc, err := New(newProvider(&mockdispatcher.MockOutbound{}))
+		require.NoError(t, err)
+
+		_, err = svc.HandleInbound(service.NewDIDCommMsgMap(&Menu{Type: MenuMsgType}), aliceDID, bobDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "get connection ID by DIDs")
+	})
+
+	t.Run("invalid messages", func(t *testing.T) {
+		svc, err := New(newProvider(&mockdispatcher.MockOutbound{}))
+		require.NoError(t, err)
+
+		saveConnection(t, svc, aliceDID, bobDID)
+
+		_, err = svc.HandleInbound(service.DIDCommMsgMap{"@type": MenuMsgType, "options": "invalid"},
+			aliceDID, bobDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "decode menu")
+
+		_, err = svc.HandleInbound(service.DIDCommMsgMap{"@type": PerformMsgType, "params": "invalid"},
+			aliceDID, bobDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "decode perform")
+	})
+
+	t.Run("unsupported message type", func(t *testing.T) {
+		svc, err := New(newProvider(&mockdispatcher.MockOutbound{}))
+		require.NoError(t, err)
+
+		_, err = svc.HandleInbound(service.DIDCommMsgMap{"@type": "unknown"}, aliceDID, bobDID)
+		require.EqualError(t, err, "unsupported message type unknown")
+
+		_, err = svc.HandleOutbound(nil, aliceDID, bobDID)
+		require.EqualError(t, err, "not implemented")
+	})
+}
+
+func TestService_Outbound(t *testing.T) {
+	t.Run("connection not found", func(t *testing.T) {
+		svc, err := New(newProvider(&mockdispatcher.MockOutbound{}))
+		require.NoError(t, err)
+
+		for _, err = range []error{
+			svc.PublishMenu(connID, testMenu()),
+			svc.SendMenu(connID, testMenu()),
+			svc.RequestMenu(connID),
+			svc.Perform(connID, "issue", nil),
+		} {
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "get connection record")
+		}
+
+		_, err = svc.Menu(connID)
+		require.True(t, errors.Is(err, ErrMenuNotFound))
+
+		_, err = svc.PublishedMenu(connID)
+		require.True(t, errors.Is(err, ErrMenuNotFound))
+	})
+
+	t.Run("send error", func(t *testing.T) {
+		svc, err := New(newProvider(&mockdispatcher.MockOutbound{SendErr: errors.New("test error")}))
+		require.NoError(t, err)
+
+		saveConnection(t, svc, aliceDID, bobDID)
+
+		require.EqualError(t, svc.SendMenu(connID, testMenu()), "send menu: test error")
+		require.EqualError(t, svc.RequestMenu(connID), "send menu request: test error")
+		require.EqualError(t, svc.Perform(connID, "issue", nil), "send perform: test error")
+	})
+
+	t.Run("perform without the menu is not threaded", func(t *testing.T) {
+		var sent *Perform
+
+		svc, err := New(newProvider(&mockdispatcher.MockOutbound{
+			ValidateSendToDID: func(msg interface{}, myDID, theirDID string) error {
+				sent = msg.(*Perform)
+
+				return nil
+			},
+		}))
+		require.NoError(t, err)
+
+		saveConnection(t, svc, aliceDID, bobDID)
+
+		require.NoError(t, svc.Perform(connID, "issue", nil))
+		require.NotNil(t, sent)
+		require.Nil(t, sent.Thread)
+	})
+}
+
+func testMenu() *Menu {
+	return &Menu{
+		Title:       "Issuer bot",
+		Description: "Credentials you can get",
+		Options: []MenuOption{
+			{
+				Name:  "issue",
+				Title: "Issue a credential",
+				Form: &Form{
+					Params:      []FormParam{{Name: "name", Title: "Your name", Required: true}},
+					SubmitLabel: "Issue",
+				},
+			},
+			{Name: "revoke", Title: "Revoke the credential", Disabled: true},
+		},
+	}
+}
+
+// newConnectedServices returns two services connected with connID, which deliver the messages to each other.
+func newConnectedServices(t *testing.T) (*Service, *Service) {
+	t.Helper()
+
+	var alice, bob *Service
+
+	deliver := func(to **Service) func(msg interface{}, myDID, theirDID string) error {
+		return func(msg interface{}, myDID, theirDID string) error {
+			src, err := json.Marshal(msg)
+			require.NoError(t, err)
+
+			didCommMsg, err := service.ParseDIDCommMsgMap(src)
+			require.NoError(t, err)
+
+			go func() {
+				_, err := (*to).HandleInbound(didCommMsg, theirDID, myDID)
+				require.NoError(t, err)
+			}()
+
+			return nil
+		}
+	}
+
+	var err error
+
+	alice, err = New(newProvider(&mockdispatcher.MockOutbound{ValidateSendToDID: deliver(&bob)}))
+	require.NoError(t, err)
+
+	bob, err = New(newProvider(&mockdispatcher.MockOutbound{ValidateSendToDID: deliver(&alice)}))
+	require.NoError(t, err)
+
+	saveConnection(t, alice, aliceDID, bobDID)
+	saveConnection(t, bob, bobDID, aliceDID)
+
+	return alice, bob
+}
+
+func newProvider(outbound *mockdispatcher.MockOutbound) *mockprovider.Provider {
+	return &mockprovider.Provider{
+		StorageProviderValue:              mem.NewProvider(),
+		ProtocolStateStorageProviderValue: mem.NewProvider(),
+		OutboundDispatcherValue:           outbound,
+	}
+}
+
+func saveConnection(t *testing.T, svc *Service, myDID, theirDID string) {
+	t.Helper()
+
+	lookup, ok := svc.connectionLookup.(*connection.Lookup)
+	require.True(t, ok)
+
+	recorder := &connection.Recorder{Lookup: lookup}
+
+	require.NoError(t, recorder.SaveConnectionRecord(&connection.Record{
+		ConnectionID: connID,
+		State:        connection.StateNameCompleted,
+		MyDID:        myDID,
+		TheirDID:     theirDID,
+	}))
+}
@@ -23,6 +23,12 @@ const (
 
 	// TransportReturnRouteThread return route option thread.
 	TransportReturnRouteThread = "thread"
+
+	// PleaseAckOnReceipt asks for the ack as soon as the message is received.
+	PleaseAckOnReceipt = "RECEIPT"
+
+	// PleaseAckOnOutcome asks for the ack once the message has been processed.
+	PleaseAckOnOutcome = "OUTCOME"
 )
 
 // Thread thread data.
@@ -33,11 +39,51 @@ type Thread struct {
 	ReceivedOrders map[string]int `json:"received_orders,omitempty"`
 }
 
-// Timing keeps expiration time.
+// Timing keeps expiration time and the time the message was received and sent
+// https://github.com/hyperledger/aries-rfcs/tree/master/features/0032-message-timing
 type Timing struct {
+	InTime      time.Time `json:"in_time,omitempty"`
+	OutTime     time.Time `json:"out_time,omitempty"`
 	ExpiresTime time.Time `json:"expires_time,omitempty"`
 }
 
+// PleaseAck asks the recipient to acknowledge the message
+// https://github.com/hyperledger/aries-rfcs/tree/master/features/0317-please-ack
+type PleaseAck struct {
+	// On lists when the ack is expected, either PleaseAckOnReceipt or PleaseAckOnOutcome.
+	// The ack is expected on receipt if it is empty.
+	On []string `json:"on,omitempty"`
+}
+
+// OnReceipt checks whether the ack is expected as soon as the message is received.
+func (p *PleaseAck) OnReceipt() bool {
+	return p != nil && (len(p.On) == 0 || contains(p.On, PleaseAckOnReceipt))
+}
+
+// OnOutcome checks whether the ack is expected once the message has been processed.
+func (p *PleaseAck) OnOutcome() bool {
+	return p != nil && contains(p.On, PleaseAckOnOutcome)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Service is the ~service decorator, it carries the ephemeral return route of the connectionless messages,
+// so the recipient can reply without the connection
+// https://github.com/hyperledger/aries-rfcs/tree/master/features/0056-service-decorator
+type Service struct {
+	RecipientKeys   []string `json:"recipientKeys"`
+	RoutingKeys     []string `json:"routingKeys,omitempty"`
+	ServiceEndpoint string   `json:"serviceEndpoint"`
+}
+
 // Transport transport decorator
 // https://github.com/hyperledger/aries-rfcs/tree/master/features/0092-transport-return-route
 type Transport struct {
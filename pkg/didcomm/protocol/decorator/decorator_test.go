@@ -60,3 +60,20 @@ type testStruct struct {
 	FirstName string
 	LastName  string
 }
+
+func TestPleaseAck(t *testing.T) {
+	var absent *PleaseAck
+	require.False(t, absent.OnReceipt())
+	require.False(t, absent.OnOutcome())
+
+	require.True(t, (&PleaseAck{}).OnReceipt())
+	require.False(t, (&PleaseAck{}).OnOutcome())
+
+	outcome := &PleaseAck{On: []string{PleaseAckOnOutcome}}
+	require.False(t, outcome.OnReceipt())
+	require.True(t, outcome.OnOutcome())
+
+	both := &PleaseAck{On: []string{PleaseAckOnReceipt, PleaseAckOnOutcome}}
+	require.True(t, both.OnReceipt())
+	require.True(t, both.OnOutcome())
+}
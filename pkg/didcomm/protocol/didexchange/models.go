@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package didexchange
 
 import (
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/model"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
 )
@@ -30,6 +31,11 @@ type OOBInvitation struct {
 	// - a string with a valid DID
 	// - a valid `did.Service`
 	Target interface{}
+	// MultiUse allows the invitation to create many connections, a single-use invitation is rejected once a
+	// connection request referenced it.
+	MultiUse bool
+	// Used is set once a connection request referenced the invitation.
+	Used bool
 }
 
 // Invitation model
@@ -98,3 +104,12 @@ type Connection struct {
 	DID    string   `json:"did,omitempty"`
 	DIDDoc *did.Doc `json:"did_doc,omitempty"`
 }
+
+// ProblemReport defines a2a DID exchange problem report, it is sent when the exchange is abandoned
+// https://github.com/hyperledger/aries-rfcs/tree/master/features/0023-did-exchange#errors
+type ProblemReport struct {
+	Type        string            `json:"@type,omitempty"`
+	ID          string            `json:"@id,omitempty"`
+	Description model.Code        `json:"description"`
+	Thread      *decorator.Thread `json:"~thread,omitempty"`
+}
@@ -10,11 +10,14 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sync"
 
 	"github.com/google/uuid"
 
 	"github.com/hyperledger/aries-framework-go/pkg/common/log"
 	"github.com/hyperledger/aries-framework-go/pkg/crypto"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/expiry"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/model"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/dispatcher"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
@@ -43,6 +46,8 @@ const (
 	ResponseMsgType = PIURI + "/response"
 	// AckMsgType defines the did-exchange ack message type.
 	AckMsgType = PIURI + "/ack"
+	// ProblemReportMsgType defines the did-exchange problem report message type.
+	ProblemReportMsgType = PIURI + "/problem_report"
 	// oobMsgType is the internal message type for the oob invitation that the didexchange service receives.
 	oobMsgType             = "oob-invitation"
 	routerConnsMetadataKey = "routerConnections"
@@ -86,6 +91,8 @@ type Service struct {
 	ctx             *context
 	callbackChannel chan *message
 	connectionStore *connectionStore
+	reaper          *expiry.Reaper
+	invitationLock  sync.Mutex
 }
 
 type context struct {
@@ -109,8 +116,26 @@ type opts interface {
 	RouterConnections() []string
 }
 
+// ServiceOption configures the didexchange service.
+type ServiceOption func(opts *serviceOptions)
+
+type serviceOptions struct {
+	expiry     bool
+	expiryOpts []expiry.Option
+}
+
+// WithExpiry enables the reaper which abandons the exchanges that have expired either because
+// the ~timing.expires_time of their last message has passed or because they stayed idle longer than
+// the timeout provided with the options.
+func WithExpiry(opts ...expiry.Option) ServiceOption {
+	return func(o *serviceOptions) {
+		o.expiry = true
+		o.expiryOpts = opts
+	}
+}
+
 // New return didexchange service.
-func New(prov provider) (*Service, error) {
+func New(prov provider, opts ...ServiceOption) (*Service, error) {
 	connRecorder, err := newConnectionStore(prov)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize connection store : %w", err)
@@ -142,6 +167,22 @@ func New(prov provider) (*Service, error) {
 		connectionStore: connRecorder,
 	}
 
+	svcOpts := &serviceOptions{}
+
+	for _, opt := range opts {
+		opt(svcOpts)
+	}
+
+	if svcOpts.expiry {
+		svc.reaper, err = expiry.NewReaper(prov.StorageProvider(), DIDExchange, svc.abandonExpired,
+			svcOpts.expiryOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("new reaper: %w", err)
+		}
+
+		svc.reaper.Start()
+	}
+
 	// start the listener
 	go svc.startInternalListener()
 
@@ -172,6 +213,10 @@ func (s *Service) HandleInbound(msg service.DIDCommMsg, _, _ string) (string, er
 		return "", err
 	}
 
+	if msg.Type() == ProblemReportMsgType {
+		return s.handleProblemReport(thID, msg)
+	}
+
 	// valid state transition and get the next state
 	next, err := s.nextState(msg.Type(), thID)
 	if err != nil {
@@ -184,6 +229,12 @@ func (s *Service) HandleInbound(msg service.DIDCommMsg, _, _ string) (string, er
 		return "", fmt.Errorf("failed to fetch connection record : %w", err)
 	}
 
+	if s.reaper != nil {
+		if err = s.reaper.Check(connRecord.ConnectionID); err != nil {
+			return "", err
+		}
+	}
+
 	internalMsg := &message{
 		Options:       &options{routerConnections: retrievingRouterConnections(msg)},
 		Msg:           msg.Clone(),
@@ -219,6 +270,15 @@ func (s *Service) Name() string {
 	return DIDExchange
 }
 
+// Close stops the reaper of the expired exchanges, if the expiry is enabled.
+func (s *Service) Close() error {
+	if s.reaper != nil {
+		s.reaper.Stop()
+	}
+
+	return nil
+}
+
 func findNamespace(msgType string) string {
 	namespace := theirNSPrefix
 	if msgType == InvitationMsgType || msgType == ResponseMsgType || msgType == oobMsgType {
@@ -233,7 +293,15 @@ func (s *Service) Accept(msgType string) bool {
 	return msgType == InvitationMsgType ||
 		msgType == RequestMsgType ||
 		msgType == ResponseMsgType ||
-		msgType == AckMsgType
+		msgType == AckMsgType ||
+		msgType == ProblemReportMsgType
+}
+
+// MessageTypes returns the message types handled by the service.
+func (s *Service) MessageTypes() []string {
+	return []string{
+		InvitationMsgType, RequestMsgType, ResponseMsgType, AckMsgType, ProblemReportMsgType,
+	}
 }
 
 // HandleOutbound handles outbound didexchange messages.
@@ -316,6 +384,10 @@ func (s *Service) handle(msg *message, aEvent chan<- service.DIDCommAction) erro
 
 		logger.Debugf("updated connection record %+v", connectionRecord)
 
+		if err = s.trackRecord(msg.Msg, connectionRecord); err != nil {
+			return fmt.Errorf("track connection record: %w", err)
+		}
+
 		if err = action(); err != nil {
 			return fmt.Errorf("failed to execute state action '%s': %w", next.Name(), err)
 		}
@@ -536,25 +608,109 @@ func (s *Service) abandon(thID string, msg service.DIDCommMsg, processErr error)
 		return fmt.Errorf("unable to update the state to abandoned: %w", err)
 	}
 
+	return s.abandonRecord(connRec, msg, processErr)
+}
+
+func (s *Service) abandonRecord(connRec *connection.Record, msg service.DIDCommMsg, processErr error) error {
 	connRec.State = (&abandoned{}).Name()
 
-	err = s.update(msg.Type(), connRec)
+	err := s.update(msg.Type(), connRec)
 	if err != nil {
 		return fmt.Errorf("unable to update the state to abandoned: %w", err)
 	}
 
+	if s.reaper != nil {
+		if err = s.reaper.Remove(connRec.ConnectionID); err != nil {
+			return fmt.Errorf("unable to stop tracking the connection: %w", err)
+		}
+	}
+
 	// send the message event
 	s.sendMsgEvents(&service.StateMsg{
 		ProtocolName: DIDExchange,
 		Type:         service.PostState,
 		Msg:          msg,
 		StateID:      StateIDAbandoned,
-		Properties:   createErrorEventProperties(connRec.ConnectionID, "", processErr),
+		Properties:   createErrorEventProperties(connRec.ConnectionID, connRec.InvitationID, processErr),
 	})
 
 	return nil
 }
 
+// handleProblemReport abandons the exchange the problem report was sent for.
+func (s *Service) handleProblemReport(thID string, msg service.DIDCommMsg) (string, error) {
+	report := &ProblemReport{}
+
+	err := msg.Decode(report)
+	if err != nil {
+		return "", fmt.Errorf("decode problem report: %w", err)
+	}
+
+	var connRec *connection.Record
+
+	// the problem report might be related to the exchange we started or to the one we were invited to
+	for _, ns := range []string{myNSPrefix, theirNSPrefix} {
+		nsThID, errKey := connection.CreateNamespaceKey(ns, thID)
+		if errKey != nil {
+			return "", errKey
+		}
+
+		connRec, err = s.connectionStore.GetConnectionRecordByNSThreadID(nsThID)
+		if err == nil {
+			break
+		}
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch connection record : %w", err)
+	}
+
+	err = s.abandonRecord(connRec, msg, fmt.Errorf("problem report: %s", report.Description.Code))
+	if err != nil {
+		return "", err
+	}
+
+	return connRec.ConnectionID, nil
+}
+
+// trackRecord keeps the reaper informed about the activity of the exchange.
+func (s *Service) trackRecord(msg service.DIDCommMsgMap, connRec *connection.Record) error {
+	if s.reaper == nil {
+		return nil
+	}
+
+	if connRec.State == StateIDCompleted || connRec.State == StateIDAbandoned {
+		return s.reaper.Remove(connRec.ConnectionID)
+	}
+
+	return s.reaper.Touch(connRec.ConnectionID, msg, connRec.MyDID, connRec.TheirDID)
+}
+
+// abandonExpired abandons the exchange and notifies another agent with the problem report.
+func (s *Service) abandonExpired(instance *expiry.Instance) error {
+	connRec, err := s.connectionStore.GetConnectionRecord(instance.PIID)
+	if err != nil {
+		return fmt.Errorf("get connection record: %w", err)
+	}
+
+	err = s.abandonRecord(connRec, instance.Msg, expiry.ErrExpired)
+	if err != nil {
+		return err
+	}
+
+	// another agent can be addressed only when both DIDs are known
+	if connRec.MyDID == "" || connRec.TheirDID == "" {
+		return nil
+	}
+
+	return s.ctx.outboundDispatcher.SendToDID(&ProblemReport{
+		Type:        ProblemReportMsgType,
+		ID:          uuid.New().String(),
+		Description: model.Code{Code: expiry.ProblemCode},
+		Thread:      &decorator.Thread{ID: connRec.ThreadID},
+	}, connRec.MyDID, connRec.TheirDID)
+}
+
 func (s *Service) processCallback(msg *message) {
 	// pass the callback data to internal channel. This is created to unblock consumer go routine and wrap the callback
 	// channel internally.
@@ -715,6 +871,10 @@ func (s *Service) requestMsgRecord(msg service.DIDCommMsg) (*connection.Record,
 		return nil, fmt.Errorf("missing parent thread ID on didexchange request with @id=%s", request.ID)
 	}
 
+	if err = s.useOOBInvitation(invitationID); err != nil {
+		return nil, err
+	}
+
 	connRecord := &connection.Record{
 		TheirLabel:   request.Label,
 		ConnectionID: generateRandomID(),
@@ -732,6 +892,40 @@ func (s *Service) requestMsgRecord(msg service.DIDCommMsg) (*connection.Record,
 	return connRecord, nil
 }
 
+// useOOBInvitation marks the out-of-band invitation referenced by a request as used, a single-use invitation can't be
+// referenced by another request. The legacy invitations and the public DIDs are not checked.
+func (s *Service) useOOBInvitation(invitationID string) error {
+	s.invitationLock.Lock()
+	defer s.invitationLock.Unlock()
+
+	var invitation OOBInvitation
+
+	err := s.connectionStore.GetInvitation(invitationID, &invitation)
+	if errors.Is(err, storage.ErrDataNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to load oob invitation: %w", err)
+	}
+
+	if invitation.Type != oobMsgType || invitation.MultiUse {
+		return nil
+	}
+
+	if invitation.Used {
+		return fmt.Errorf("oob invitation %s has already been used", invitationID)
+	}
+
+	invitation.Used = true
+
+	if err = s.connectionStore.SaveInvitation(invitationID, &invitation); err != nil {
+		return fmt.Errorf("failed to save oob invitation: %w", err)
+	}
+
+	return nil
+}
+
 func (s *Service) responseMsgRecord(payload service.DIDCommMsg) (*connection.Record, error) {
 	return s.fetchConnectionRecord(myNSPrefix, payload)
 }
@@ -21,6 +21,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/expiry"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/model"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
@@ -29,6 +30,7 @@ import (
 	vdrapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
 	"github.com/hyperledger/aries-framework-go/pkg/kms"
 	"github.com/hyperledger/aries-framework-go/pkg/kms/localkms"
+	mockdispatcher "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/dispatcher"
 	"github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol"
 	mockroute "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol/mediator"
 	mockdiddoc "github.com/hyperledger/aries-framework-go/pkg/mock/diddoc"
@@ -525,6 +527,7 @@ func TestService_Accept(t *testing.T) {
 	require.Equal(t, true, s.Accept("https://didcomm.org/didexchange/1.0/request"))
 	require.Equal(t, true, s.Accept("https://didcomm.org/didexchange/1.0/response"))
 	require.Equal(t, true, s.Accept("https://didcomm.org/didexchange/1.0/ack"))
+	require.Equal(t, true, s.Accept("https://didcomm.org/didexchange/1.0/problem_report"))
 	require.Equal(t, false, s.Accept("unsupported msg type"))
 }
 
@@ -1222,6 +1225,43 @@ func TestRequestRecord(t *testing.T) {
 		_, err = svc.requestMsgRecord(didcommMsg)
 		require.Error(t, err)
 	})
+
+	t.Run("single-use and multi-use oob invitations", func(t *testing.T) {
+		svc, err := New(&protocol.MockProvider{
+			ServiceMap: map[string]interface{}{
+				mediator.Coordination: &mockroute.MockMediatorSvc{},
+			},
+		})
+		require.NoError(t, err)
+
+		singleUse := &OOBInvitation{ID: uuid.New().String(), ThreadID: uuid.New().String(), Target: "did:example:1"}
+		require.NoError(t, svc.SaveInvitation(singleUse))
+
+		multiUse := &OOBInvitation{
+			ID: uuid.New().String(), ThreadID: uuid.New().String(), Target: "did:example:1", MultiUse: true,
+		}
+		require.NoError(t, svc.SaveInvitation(multiUse))
+
+		_, err = svc.requestMsgRecord(generateRequestMsgPayload(t, &protocol.MockProvider{},
+			randomString(), singleUse.ThreadID))
+		require.NoError(t, err)
+
+		_, err = svc.requestMsgRecord(generateRequestMsgPayload(t, &protocol.MockProvider{},
+			randomString(), singleUse.ThreadID))
+		require.EqualError(t, err, fmt.Sprintf("oob invitation %s has already been used", singleUse.ThreadID))
+
+		var connIDs []string
+
+		for i := 0; i < 2; i++ {
+			conn, errRecord := svc.requestMsgRecord(generateRequestMsgPayload(t, &protocol.MockProvider{},
+				randomString(), multiUse.ThreadID))
+			require.NoError(t, errRecord)
+
+			connIDs = append(connIDs, conn.ConnectionID)
+		}
+
+		require.NotEqual(t, connIDs[0], connIDs[1])
+	})
 }
 
 func TestAcceptExchangeRequest(t *testing.T) {
@@ -2033,3 +2073,131 @@ func newPeerDID(t *testing.T) *did.Doc {
 
 	return doc
 }
+
+func TestService_Expiry(t *testing.T) {
+	t.Run("abandons expired exchange", func(t *testing.T) {
+		sent := make(chan *ProblemReport, 1)
+
+		prov := testProvider()
+		prov.CustomOutbound = &mockdispatcher.MockOutbound{
+			ValidateSendToDID: func(msg interface{}, myDID, theirDID string) error {
+				require.Equal(t, "my-did", myDID)
+				require.Equal(t, "their-did", theirDID)
+
+				report, ok := msg.(*ProblemReport)
+				require.True(t, ok)
+
+				sent <- report
+
+				return nil
+			},
+		}
+
+		svc, err := New(prov, WithExpiry(expiry.WithTimeout(10*time.Millisecond),
+			expiry.WithInterval(10*time.Millisecond)))
+		require.NoError(t, err)
+
+		defer func() {
+			// the reaper is stopped and closing again is a no-op
+			require.NoError(t, svc.Close())
+			require.NoError(t, svc.Close())
+		}()
+
+		events := make(chan service.StateMsg, 10)
+		require.NoError(t, svc.RegisterMsgEvent(events))
+
+		connRec := &connection.Record{
+			ConnectionID: "connection-id",
+			ThreadID:     "thread-id",
+			State:        StateIDResponded,
+			Namespace:    theirNSPrefix,
+			MyDID:        "my-did",
+			TheirDID:     "their-did",
+		}
+
+		require.NoError(t, svc.connectionStore.saveConnectionRecord(connRec))
+		require.NoError(t, svc.trackRecord(service.NewDIDCommMsgMap(&Response{
+			ID:     "response-id",
+			Type:   ResponseMsgType,
+			Thread: &decorator.Thread{ID: "thread-id"},
+		}), connRec))
+
+		select {
+		case event := <-events:
+			require.Equal(t, StateIDAbandoned, event.StateID)
+
+			props, ok := event.Properties.(*didExchangeEventError)
+			require.True(t, ok)
+			require.Equal(t, "connection-id", props.ConnectionID())
+			require.True(t, errors.Is(props.err, expiry.ErrExpired))
+		case <-time.After(time.Second):
+			t.Fatal("timeout")
+		}
+
+		select {
+		case report := <-sent:
+			require.Equal(t, ProblemReportMsgType, report.Type)
+			require.Equal(t, expiry.ProblemCode, report.Description.Code)
+			require.Equal(t, "thread-id", report.Thread.ID)
+		case <-time.After(time.Second):
+			t.Fatal("timeout")
+		}
+
+		record, err := svc.connectionStore.GetConnectionRecord("connection-id")
+		require.NoError(t, err)
+		require.Equal(t, StateIDAbandoned, record.State)
+	})
+
+	t.Run("completed exchange is not tracked", func(t *testing.T) {
+		svc, err := New(testProvider(), WithExpiry())
+		require.NoError(t, err)
+
+		connRec := &connection.Record{ConnectionID: "connection-id", State: StateIDCompleted}
+
+		require.NoError(t, svc.trackRecord(service.NewDIDCommMsgMap(&model.Ack{ID: "ack-id"}), connRec))
+		require.NoError(t, svc.reaper.Check("connection-id"))
+	})
+
+	t.Run("handles problem report", func(t *testing.T) {
+		svc, err := New(testProvider())
+		require.NoError(t, err)
+
+		events := make(chan service.StateMsg, 10)
+		require.NoError(t, svc.RegisterMsgEvent(events))
+
+		require.NoError(t, svc.connectionStore.saveConnectionRecordWithMapping(&connection.Record{
+			ConnectionID: "connection-id",
+			ThreadID:     "thread-id",
+			State:        StateIDRequested,
+			Namespace:    myNSPrefix,
+		}))
+
+		connID, err := svc.HandleInbound(service.NewDIDCommMsgMap(&ProblemReport{
+			ID:          "report-id",
+			Type:        ProblemReportMsgType,
+			Description: model.Code{Code: expiry.ProblemCode},
+			Thread:      &decorator.Thread{ID: "thread-id"},
+		}), "", "")
+		require.NoError(t, err)
+		require.Equal(t, "connection-id", connID)
+
+		select {
+		case event := <-events:
+			require.Equal(t, StateIDAbandoned, event.StateID)
+
+			props, ok := event.Properties.(*didExchangeEventError)
+			require.True(t, ok)
+			require.EqualError(t, props, "problem report: expired")
+		case <-time.After(time.Second):
+			t.Fatal("timeout")
+		}
+
+		_, err = svc.HandleInbound(service.NewDIDCommMsgMap(&ProblemReport{
+			ID:     "report-id",
+			Type:   ProblemReportMsgType,
+			Thread: &decorator.Thread{ID: "unknown-thread-id"},
+		}), "", "")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to fetch connection record")
+	})
+}
@@ -0,0 +1,68 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didrotate
+
+import (
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/model"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+)
+
+// Rotate moves the connection of the sender to a new DID. The new DID is signed with a key the counterparty
+// already knows for the old DID. The DID document is attached if the counterparty can't resolve the DID
+// (e.g. a peer DID).
+type Rotate struct {
+	Type           string                `json:"@type,omitempty"`
+	ID             string                `json:"@id,omitempty"`
+	ToDID          string                `json:"to_did,omitempty"`
+	ToDIDSignature *Signature            `json:"to_did~sig,omitempty"`
+	ToDIDDoc       *decorator.Attachment `json:"to_did_doc~attach,omitempty"`
+}
+
+// Signature holds the SignedRotation signed with the old key.
+type Signature struct {
+	Type       string `json:"@type,omitempty"`
+	Signature  string `json:"signature,omitempty"`
+	SignedData string `json:"sig_data,omitempty"`
+	SignVerKey string `json:"signer,omitempty"`
+}
+
+// SignedRotation is the signed data of the rotate message. It binds the new DID to the old one and to the thread
+// of the rotation so that the signature can't be replayed for another DID or in another rotation.
+type SignedRotation struct {
+	ThreadID string `json:"thid"`
+	FromDID  string `json:"from_did"`
+	ToDID    string `json:"to_did"`
+}
+
+// Ack is sent to the new DID once the counterparty moved the connection to it.
+type Ack struct {
+	Type   string            `json:"@type,omitempty"`
+	ID     string            `json:"@id,omitempty"`
+	Status string            `json:"status,omitempty"`
+	Thread *decorator.Thread `json:"~thread,omitempty"`
+}
+
+// ProblemReport is sent to the old DID when the counterparty can't move the connection to the new DID.
+type ProblemReport struct {
+	Type        string            `json:"@type,omitempty"`
+	ID          string            `json:"@id,omitempty"`
+	Description model.Code        `json:"description"`
+	Thread      *decorator.Thread `json:"~thread,omitempty"`
+}
+
+// Hangup notifies the counterparty that the sender ended the connection and won't use its DID anymore.
+type Hangup struct {
+	Type string `json:"@type,omitempty"`
+	ID   string `json:"@id,omitempty"`
+}
+
+// rotation is a rotation waiting for the counterparty's ack.
+type rotation struct {
+	ConnectionID string `json:"connection_id,omitempty"`
+	OldDID       string `json:"old_did,omitempty"`
+	NewDID       string `json:"new_did,omitempty"`
+}
@@ -0,0 +1,805 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didrotate
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/model"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/dispatcher"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/suite"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/suite/ed25519signature2018"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/verifier"
+	vdrapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/localkms"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+	didstore "github.com/hyperledger/aries-framework-go/pkg/store/did"
+	"github.com/hyperledger/aries-framework-go/pkg/vdr"
+	"github.com/hyperledger/aries-framework-go/pkg/vdr/fingerprint"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+)
+
+const (
+	// DIDRotate defines the protocol name.
+	DIDRotate = "didrotate"
+	// Spec defines the protocol spec.
+	Spec = "https://didcomm.org/did-rotate/1.0/"
+	// RotateMsgType defines the DID rotation rotate message type.
+	RotateMsgType = Spec + "rotate"
+	// AckMsgType defines the DID rotation ack message type.
+	AckMsgType = Spec + "ack"
+	// ProblemReportMsgType defines the DID rotation problem-report message type.
+	ProblemReportMsgType = Spec + "problem-report"
+	// HangupMsgType defines the DID rotation hangup message type.
+	HangupMsgType = Spec + "hangup"
+)
+
+// states of the DID rotation sent as post-state events.
+const (
+	// StateRotated is the state of the connection moved to the new DID.
+	StateRotated = "rotated"
+	// StateFailed is the state of the rotation rejected by the counterparty.
+	StateFailed = "failed"
+	// StateHungUp is the state of the connection ended by the counterparty.
+	StateHungUp = "hungup"
+)
+
+// problem codes of the problem-report message.
+const (
+	CodeUnresolvableDID  = "e.did.unresolvable"
+	CodeInvalidSignature = "e.did.signature"
+	CodeUnsupportedDID   = "e.did.unsupported"
+)
+
+const (
+	// Namespace is namespace of DID rotation store name.
+	Namespace = "didrotate"
+
+	signatureType = "https://didcomm.org/signature/1.0/ed25519Sha512_single"
+	ackStatusOK   = "OK"
+	peerDIDMethod = "peer"
+
+	rotationKeyPrefix = "rotation_"
+)
+
+// ErrRotationNotFound is returned when an ack or a problem-report does not belong to a pending rotation.
+var ErrRotationNotFound = errors.New("DID rotation not found")
+
+var logger = log.New("aries-framework/didrotate")
+
+type provider interface {
+	OutboundDispatcher() dispatcher.Outbound
+	StorageProvider() storage.Provider
+	ProtocolStateStorageProvider() storage.Provider
+	KMS() kms.KeyManager
+	Crypto() crypto.Crypto
+	VDRegistry() vdrapi.Registry
+}
+
+// Service moves existing connections to new DIDs. The rotating party signs the new DID with a key of the old DID
+// and keeps using the old DID until the counterparty acknowledges the rotation. Either party can hang up
+// the connection.
+//
+// The lock of the service guards the connections and the rotations only, the messages are sent and the events
+// are raised without holding it so that the event consumers can rotate or hang up connections.
+type Service struct {
+	service.Message
+	outbound     dispatcher.Outbound
+	kms          kms.KeyManager
+	crypto       crypto.Crypto
+	vdRegistry   vdrapi.Registry
+	connections  *connection.Recorder
+	didConnStore *didstore.ConnectionStore
+	store        storage.Store
+	lock         sync.Mutex
+}
+
+// New returns the DID rotation service.
+func New(prov provider) (*Service, error) {
+	store, err := prov.ProtocolStateStorageProvider().OpenStore(Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("open DID rotation store: %w", err)
+	}
+
+	connRecorder, err := connection.NewRecorder(prov)
+	if err != nil {
+		return nil, fmt.Errorf("new connection recorder: %w", err)
+	}
+
+	didConnStore, err := didstore.NewConnectionStore(prov)
+	if err != nil {
+		return nil, fmt.Errorf("new did connection store: %w", err)
+	}
+
+	return &Service{
+		outbound:     prov.OutboundDispatcher(),
+		kms:          prov.KMS(),
+		crypto:       prov.Crypto(),
+		vdRegistry:   prov.VDRegistry(),
+		connections:  connRecorder,
+		didConnStore: didConnStore,
+		store:        store,
+	}, nil
+}
+
+// followUp is a message sent or an event raised once a message is handled, it runs without holding the lock.
+type followUp func() error
+
+// HandleInbound handles inbound DID rotation messages.
+func (s *Service) HandleInbound(msg service.DIDCommMsg, myDID, theirDID string) (string, error) {
+	if !s.Accept(msg.Type()) {
+		return "", fmt.Errorf("unsupported message type %s", msg.Type())
+	}
+
+	followUps, err := s.handle(msg, myDID, theirDID)
+
+	for _, fn := range followUps {
+		if fErr := fn(); fErr != nil && err == nil {
+			err = fErr
+		}
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("handle %s: %w", msg.Type(), err)
+	}
+
+	return msg.ID(), nil
+}
+
+func (s *Service) handle(msg service.DIDCommMsg, myDID, theirDID string) ([]followUp, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	switch msg.Type() {
+	case RotateMsgType:
+		return s.handleRotate(msg, myDID, theirDID)
+	case AckMsgType:
+		return s.handleAck(msg, theirDID)
+	case ProblemReportMsgType:
+		return s.handleProblemReport(msg, theirDID)
+	default:
+		return s.handleHangup(msg, myDID, theirDID)
+	}
+}
+
+// HandleOutbound adherence to dispatcher.ProtocolService.
+func (s *Service) HandleOutbound(_ service.DIDCommMsg, _, _ string) (string, error) {
+	return "", errors.New("not implemented")
+}
+
+// Accept checks whether the service can handle the message type.
+func (s *Service) Accept(msgType string) bool {
+	switch msgType {
+	case RotateMsgType, AckMsgType, ProblemReportMsgType, HangupMsgType:
+		return true
+	}
+
+	return false
+}
+
+// MessageTypes returns the message types handled by the service.
+func (s *Service) MessageTypes() []string {
+	return []string{
+		RotateMsgType, AckMsgType, ProblemReportMsgType, HangupMsgType,
+	}
+}
+
+// Name of the service.
+func (s *Service) Name() string {
+	return DIDRotate
+}
+
+// RotateDID asks the counterparty of the connection to move it to the new DID. The new DID has to be resolvable
+// by the agent and have a DIDComm service, its document is attached for peer DIDs. The connection keeps using
+// the old DID until the counterparty acknowledges the rotation, a StateRotated or StateFailed event follows.
+// Returns the thread ID of the rotation.
+func (s *Service) RotateDID(connectionID, newDID string) (string, error) {
+	msg, signKey, theirDID, err := s.startRotation(connectionID, newDID)
+	if err != nil {
+		return "", err
+	}
+
+	if err = s.send(msg, signKey, theirDID); err != nil {
+		return "", err
+	}
+
+	return msg.ID, nil
+}
+
+// startRotation saves the rotation of the connection to the new DID, it returns the rotate message, the key
+// signing it and the DID of the counterparty.
+func (s *Service) startRotation(connectionID, newDID string) (*Rotate, string, string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	record, err := s.completedConnection(connectionID)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	if newDID == record.MyDID {
+		return nil, "", "", fmt.Errorf("connection %s already uses DID %s", connectionID, newDID)
+	}
+
+	newDoc, err := s.resolve(newDID)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	if _, err = service.CreateDestination(newDoc); err != nil {
+		return nil, "", "", fmt.Errorf("DID %s has no DIDComm service: %w", newDID, err)
+	}
+
+	signKey, err := s.senderKey(record.MyDID)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	msg, err := s.newRotate(newDoc, record.MyDID, signKey)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	// the ack comes to the new DID, it's mapped to the connection by its keys
+	if err = s.didConnStore.SaveDIDFromDoc(newDoc); err != nil {
+		return nil, "", "", fmt.Errorf("save DID %s keys: %w", newDID, err)
+	}
+
+	err = s.saveRotation(msg.ID, &rotation{ConnectionID: connectionID, OldDID: record.MyDID, NewDID: newDID})
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	return msg, signKey, record.TheirDID, nil
+}
+
+// Hangup notifies the counterparty that the connection is over and removes the connection.
+func (s *Service) Hangup(connectionID string) error {
+	record, err := s.completedConnection(connectionID)
+	if err != nil {
+		return err
+	}
+
+	senderKey, err := s.senderKey(record.MyDID)
+	if err != nil {
+		return err
+	}
+
+	if err = s.send(&Hangup{Type: HangupMsgType, ID: uuid.New().String()}, senderKey, record.TheirDID); err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if err = s.connections.RemoveConnection(connectionID); err != nil {
+		return fmt.Errorf("remove connection: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) handleRotate(msg service.DIDCommMsg, myDID, theirDID string) ([]followUp, error) {
+	rotate := &Rotate{}
+
+	if err := msg.Decode(rotate); err != nil {
+		return nil, fmt.Errorf("rotate message unmarshal: %w", err)
+	}
+
+	connID, err := s.connections.GetConnectionIDByDIDs(myDID, theirDID)
+	if err != nil {
+		return nil, fmt.Errorf("get connection ID: %w", err)
+	}
+
+	record, err := s.completedConnection(connID)
+	if err != nil {
+		return nil, err
+	}
+
+	code, err := s.rotateTheirDID(record, rotate)
+	if err != nil {
+		cause := err
+
+		// the sender keeps the old DID, so it's told on the old one why the rotation failed
+		return []followUp{func() error {
+			s.sendProblemReport(record, rotate.ID, code, cause)
+
+			return nil
+		}}, err
+	}
+
+	followUps := []followUp{s.notification(StateRotated, msg, record.ConnectionID, nil)}
+
+	ack := &Ack{
+		Type:   AckMsgType,
+		ID:     uuid.New().String(),
+		Status: ackStatusOK,
+		Thread: &decorator.Thread{ID: rotate.ID},
+	}
+
+	senderKey, err := s.senderKey(record.MyDID)
+	if err != nil {
+		return followUps, err
+	}
+
+	return append(followUps, func() error {
+		return s.send(ack, senderKey, record.TheirDID)
+	}), nil
+}
+
+// rotateTheirDID verifies the rotation and moves the connection to the new DID of the counterparty.
+// Returns the problem code if the rotation is rejected.
+func (s *Service) rotateTheirDID(record *connection.Record, rotate *Rotate) (string, error) {
+	if rotate.ToDID == "" || rotate.ToDIDSignature == nil {
+		return CodeInvalidSignature, errors.New("missing new DID or its signature")
+	}
+
+	current, err := s.resolve(record.TheirDID)
+	if err != nil {
+		return CodeUnresolvableDID, err
+	}
+
+	// only a key of the old DID can sign the new one
+	dest, err := service.CreateDestination(current)
+	if err != nil {
+		return CodeUnresolvableDID, err
+	}
+
+	if !contains(dest.RecipientKeys, rotate.ToDIDSignature.SignVerKey) {
+		return CodeInvalidSignature, fmt.Errorf("signer %s is not a key of DID %s",
+			rotate.ToDIDSignature.SignVerKey, record.TheirDID)
+	}
+
+	signed, err := verifySignature(rotate.ToDIDSignature)
+	if err != nil {
+		return CodeInvalidSignature, err
+	}
+
+	if err = checkSignedRotation(signed, rotate, record.TheirDID); err != nil {
+		return CodeInvalidSignature, err
+	}
+
+	newDoc, err := s.theirNewDoc(rotate)
+	if err != nil {
+		return CodeUnresolvableDID, err
+	}
+
+	newDest, err := service.CreateDestination(newDoc)
+	if err != nil {
+		return CodeUnsupportedDID, fmt.Errorf("DID %s has no DIDComm service: %w", rotate.ToDID, err)
+	}
+
+	if err = s.didConnStore.SaveDIDFromDoc(newDoc); err != nil {
+		return CodeUnsupportedDID, fmt.Errorf("save DID %s keys: %w", rotate.ToDID, err)
+	}
+
+	updated := *record
+	updated.TheirDID = rotate.ToDID
+	updated.RecipientKeys = newDest.RecipientKeys
+	updated.RoutingKeys = newDest.RoutingKeys
+	updated.ServiceEndPoint = newDest.ServiceEndpoint
+
+	if err = s.connections.UpdateConnectionDIDs(&updated); err != nil {
+		return CodeUnsupportedDID, fmt.Errorf("update connection: %w", err)
+	}
+
+	*record = updated
+
+	return "", nil
+}
+
+// theirNewDoc returns the attached document of a peer DID, other DIDs are resolved.
+func (s *Service) theirNewDoc(rotate *Rotate) (*did.Doc, error) {
+	method, err := vdr.GetDidMethod(rotate.ToDID)
+	if err != nil {
+		return nil, err
+	}
+
+	if method != peerDIDMethod || rotate.ToDIDDoc == nil {
+		return s.resolve(rotate.ToDID)
+	}
+
+	docBytes, err := rotate.ToDIDDoc.Data.Fetch()
+	if err != nil {
+		return nil, fmt.Errorf("fetch DID document attachment: %w", err)
+	}
+
+	doc, err := did.ParseDocument(docBytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse DID document: %w", err)
+	}
+
+	if doc.ID != rotate.ToDID {
+		return nil, fmt.Errorf("attached DID document %s does not match the new DID %s", doc.ID, rotate.ToDID)
+	}
+
+	if _, err = s.vdRegistry.Create(method, doc, vdrapi.WithOption("store", true)); err != nil {
+		return nil, fmt.Errorf("store DID document %s: %w", doc.ID, err)
+	}
+
+	return doc, nil
+}
+
+func (s *Service) handleAck(msg service.DIDCommMsg, theirDID string) ([]followUp, error) {
+	thID, err := msg.ThreadID()
+	if err != nil {
+		return nil, fmt.Errorf("ack thread ID: %w", err)
+	}
+
+	r, record, err := s.pendingRotation(thID, theirDID)
+	if err != nil {
+		return nil, fmt.Errorf("ack: %w", err)
+	}
+
+	record.MyDID = r.NewDID
+
+	if err = s.connections.UpdateConnectionDIDs(record); err != nil {
+		return nil, fmt.Errorf("update connection: %w", err)
+	}
+
+	if err = s.deleteRotation(thID); err != nil {
+		return nil, err
+	}
+
+	return []followUp{s.notification(StateRotated, msg, record.ConnectionID, nil)}, nil
+}
+
+// pendingRotation returns the rotation of the thread and its connection, only the counterparty of the connection
+// can answer the rotation.
+func (s *Service) pendingRotation(thID, theirDID string) (*rotation, *connection.Record, error) {
+	r, err := s.getRotation(thID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	record, err := s.completedConnection(r.ConnectionID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if theirDID != record.TheirDID {
+		return nil, nil, fmt.Errorf("sender %s is not the counterparty of connection %s", theirDID, record.ConnectionID)
+	}
+
+	return r, record, nil
+}
+
+func (s *Service) handleProblemReport(msg service.DIDCommMsg, theirDID string) ([]followUp, error) {
+	report := &ProblemReport{}
+
+	if err := msg.Decode(report); err != nil {
+		return nil, fmt.Errorf("problem-report message unmarshal: %w", err)
+	}
+
+	thID, err := msg.ThreadID()
+	if err != nil {
+		return nil, fmt.Errorf("problem-report thread ID: %w", err)
+	}
+
+	r, _, err := s.pendingRotation(thID, theirDID)
+	if err != nil {
+		return nil, fmt.Errorf("problem-report: %w", err)
+	}
+
+	if err = s.deleteRotation(thID); err != nil {
+		return nil, err
+	}
+
+	return []followUp{s.notification(StateFailed, msg, r.ConnectionID,
+		fmt.Errorf("rotation to %s rejected: %s %s", r.NewDID, report.Description.Code, report.Description.En))}, nil
+}
+
+func (s *Service) handleHangup(msg service.DIDCommMsg, myDID, theirDID string) ([]followUp, error) {
+	connID, err := s.connections.GetConnectionIDByDIDs(myDID, theirDID)
+	if err != nil {
+		return nil, fmt.Errorf("get connection ID: %w", err)
+	}
+
+	if err = s.connections.RemoveConnection(connID); err != nil {
+		return nil, fmt.Errorf("remove connection: %w", err)
+	}
+
+	return []followUp{s.notification(StateHungUp, msg, connID, nil)}, nil
+}
+
+func (s *Service) newRotate(newDoc *did.Doc, oldDID, signKey string) (*Rotate, error) {
+	pubKey, err := fingerprint.PubKeyFromDIDKey(signKey)
+	if err != nil {
+		return nil, fmt.Errorf("parse key %s: %w", signKey, err)
+	}
+
+	kid, err := localkms.CreateKID(pubKey, kms.ED25519Type)
+	if err != nil {
+		return nil, fmt.Errorf("create KID: %w", err)
+	}
+
+	kh, err := s.kms.Get(kid)
+	if err != nil {
+		return nil, fmt.Errorf("get key handle: %w", err)
+	}
+
+	msgID := uuid.New().String()
+
+	signedData, err := json.Marshal(&SignedRotation{ThreadID: msgID, FromDID: oldDID, ToDID: newDoc.ID})
+	if err != nil {
+		return nil, fmt.Errorf("marshal signed rotation: %w", err)
+	}
+
+	signature, err := s.crypto.Sign(signedData, kh)
+	if err != nil {
+		return nil, fmt.Errorf("sign DID: %w", err)
+	}
+
+	msg := &Rotate{
+		Type:  RotateMsgType,
+		ID:    msgID,
+		ToDID: newDoc.ID,
+		ToDIDSignature: &Signature{
+			Type:       signatureType,
+			Signature:  base64.URLEncoding.EncodeToString(signature),
+			SignedData: base64.URLEncoding.EncodeToString(signedData),
+			SignVerKey: signKey,
+		},
+	}
+
+	method, err := vdr.GetDidMethod(newDoc.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// the counterparty can't resolve a peer DID
+	if method == peerDIDMethod {
+		docBytes, err := newDoc.JSONBytes()
+		if err != nil {
+			return nil, fmt.Errorf("marshal DID document: %w", err)
+		}
+
+		msg.ToDIDDoc = &decorator.Attachment{
+			ID:       uuid.New().String(),
+			MimeType: "application/json",
+			Data:     decorator.AttachmentData{Base64: base64.StdEncoding.EncodeToString(docBytes)},
+		}
+	}
+
+	return msg, nil
+}
+
+func (s *Service) sendProblemReport(record *connection.Record, thID, code string, cause error) {
+	senderKey, err := s.senderKey(record.MyDID)
+	if err != nil {
+		logger.Errorf("DID rotation: problem-report for connection %s: %s", record.ConnectionID, err)
+
+		return
+	}
+
+	report := &ProblemReport{
+		Type:        ProblemReportMsgType,
+		ID:          uuid.New().String(),
+		Description: model.Code{Code: code, En: cause.Error()},
+		Thread:      &decorator.Thread{ID: thID},
+	}
+
+	if err = s.send(report, senderKey, record.TheirDID); err != nil {
+		logger.Errorf("DID rotation: problem-report for connection %s: %s", record.ConnectionID, err)
+	}
+}
+
+func (s *Service) send(msg interface{}, senderKey, theirDID string) error {
+	dest, err := service.GetDestination(theirDID, s.vdRegistry)
+	if err != nil {
+		return fmt.Errorf("get destination: %w", err)
+	}
+
+	if err = s.outbound.Send(msg, senderKey, dest); err != nil {
+		return fmt.Errorf("send message: %w", err)
+	}
+
+	return nil
+}
+
+// senderKey returns the DIDComm key of the DID, the counterparty knows the DID by it.
+func (s *Service) senderKey(didID string) (string, error) {
+	doc, err := s.resolve(didID)
+	if err != nil {
+		return "", err
+	}
+
+	dest, err := service.CreateDestination(doc)
+	if err != nil {
+		return "", fmt.Errorf("DID %s has no DIDComm service: %w", didID, err)
+	}
+
+	return dest.RecipientKeys[0], nil
+}
+
+func (s *Service) completedConnection(connectionID string) (*connection.Record, error) {
+	record, err := s.connections.GetConnectionRecord(connectionID)
+	if err != nil {
+		return nil, fmt.Errorf("get connection record: %w", err)
+	}
+
+	if record.State != connection.StateNameCompleted {
+		return nil, fmt.Errorf("connection %s is not completed", connectionID)
+	}
+
+	return record, nil
+}
+
+func (s *Service) resolve(didID string) (*did.Doc, error) {
+	docResolution, err := s.vdRegistry.Resolve(didID)
+	if err != nil {
+		return nil, fmt.Errorf("resolve DID %s: %w", didID, err)
+	}
+
+	return docResolution.DIDDocument, nil
+}
+
+// notification returns the follow-up raising the post-state event.
+func (s *Service) notification(stateID string, msg service.DIDCommMsg, connectionID string, err error) followUp {
+	return func() error {
+		s.notify(stateID, msg, connectionID, err)
+
+		return nil
+	}
+}
+
+func (s *Service) notify(stateID string, msg service.DIDCommMsg, connectionID string, err error) {
+	stateMsg := service.StateMsg{
+		ProtocolName: DIDRotate,
+		Type:         service.PostState,
+		StateID:      stateID,
+		Msg:          msg,
+		Properties:   &eventProps{connectionID: connectionID, err: err},
+	}
+
+	for _, handler := range s.MsgEvents() {
+		handler <- stateMsg
+	}
+}
+
+func (s *Service) getRotation(thID string) (*rotation, error) {
+	src, err := s.store.Get(rotationKeyPrefix + thID)
+	if errors.Is(err, storage.ErrDataNotFound) {
+		return nil, ErrRotationNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("get DID rotation: %w", err)
+	}
+
+	r := &rotation{}
+
+	if err = json.Unmarshal(src, r); err != nil {
+		return nil, fmt.Errorf("unmarshal DID rotation: %w", err)
+	}
+
+	return r, nil
+}
+
+func (s *Service) saveRotation(thID string, r *rotation) error {
+	src, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("marshal DID rotation: %w", err)
+	}
+
+	if err = s.store.Put(rotationKeyPrefix+thID, src); err != nil {
+		return fmt.Errorf("save DID rotation: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) deleteRotation(thID string) error {
+	if err := s.store.Delete(rotationKeyPrefix + thID); err != nil {
+		return fmt.Errorf("delete DID rotation: %w", err)
+	}
+
+	return nil
+}
+
+// verifySignature verifies the signature and returns the signed rotation.
+func verifySignature(signature *Signature) (*SignedRotation, error) {
+	signedData, err := base64.URLEncoding.DecodeString(signature.SignedData)
+	if err != nil {
+		return nil, fmt.Errorf("decode signature data: %w", err)
+	}
+
+	sig, err := base64.URLEncoding.DecodeString(signature.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("decode signature: %w", err)
+	}
+
+	pubKey, err := fingerprint.PubKeyFromDIDKey(signature.SignVerKey)
+	if err != nil {
+		return nil, fmt.Errorf("parse signer %s: %w", signature.SignVerKey, err)
+	}
+
+	signatureSuite := ed25519signature2018.New(suite.WithVerifier(ed25519signature2018.NewPublicKeyVerifier()))
+
+	err = signatureSuite.Verify(&verifier.PublicKey{
+		Type:  kms.ED25519,
+		Value: pubKey,
+	}, signedData, sig)
+	if err != nil {
+		return nil, fmt.Errorf("verify signature: %w", err)
+	}
+
+	signed := &SignedRotation{}
+
+	if err = json.Unmarshal(signedData, signed); err != nil {
+		return nil, fmt.Errorf("unmarshal signed rotation: %w", err)
+	}
+
+	return signed, nil
+}
+
+// checkSignedRotation checks that the signed rotation is the one of the rotate message from the current DID.
+func checkSignedRotation(signed *SignedRotation, rotate *Rotate, theirDID string) error {
+	if signed.ThreadID != rotate.ID {
+		return fmt.Errorf("signed thread %s does not match the rotation thread %s", signed.ThreadID, rotate.ID)
+	}
+
+	if signed.FromDID != theirDID {
+		return fmt.Errorf("signed old DID %s does not match the DID %s", signed.FromDID, theirDID)
+	}
+
+	if signed.ToDID != rotate.ToDID {
+		return fmt.Errorf("signed DID %s does not match the new DID %s", signed.ToDID, rotate.ToDID)
+	}
+
+	return nil
+}
+
+type eventProps struct {
+	connectionID string
+	err          error
+}
+
+// ConnectionID of the rotated or hung up connection.
+func (e *eventProps) ConnectionID() string {
+	return e.connectionID
+}
+
+// Error is non-nil if the counterparty rejected the rotation.
+func (e *eventProps) Error() error {
+	return e.err
+}
+
+// All implements EventProperties interface.
+func (e *eventProps) All() map[string]interface{} {
+	props := map[string]interface{}{
+		"connectionID": e.connectionID,
+	}
+
+	if e.err != nil {
+		props["error"] = e.err.Error()
+	}
+
+	return props
+}
+
+func contains(keys []string, key string) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,627 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didrotate
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	vdrapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/localkms"
+	mockdispatcher "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/dispatcher"
+	"github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol"
+	mockprovider "github.com/hyperledger/aries-framework-go/pkg/mock/provider"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock/noop"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+	didstore "github.com/hyperledger/aries-framework-go/pkg/store/did"
+	"github.com/hyperledger/aries-framework-go/pkg/vdr"
+	"github.com/hyperledger/aries-framework-go/pkg/vdr/peer"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+)
+
+func TestService_New(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		a := newAgent(t, "alice")
+
+		require.Equal(t, DIDRotate, a.svc.Name())
+
+		for _, msgType := range []string{RotateMsgType, AckMsgType, ProblemReportMsgType, HangupMsgType} {
+			require.True(t, a.svc.Accept(msgType))
+		}
+
+		require.False(t, a.svc.Accept("unsupported"))
+
+		_, err := a.svc.HandleOutbound(nil, "", "")
+		require.EqualError(t, err, "not implemented")
+	})
+
+	t.Run("open store error", func(t *testing.T) {
+		_, err := New(&mockprovider.Provider{
+			ProtocolStateStorageProviderValue: &mockStorageProvider{errOpen: errors.New("open error")},
+		})
+		require.EqualError(t, err, "open DID rotation store: open error")
+	})
+}
+
+func TestService_RotateDID(t *testing.T) {
+	alice, bob := newAgent(t, "alice"), newAgent(t, "bob")
+	connect(t, alice, bob)
+
+	oldKey := recipientKey(t, alice, alice.did)
+	newDID := alice.newDID(t)
+
+	thID, err := alice.svc.RotateDID(alice.connID(), newDID)
+	require.NoError(t, err)
+
+	// alice keeps the old DID until bob acknowledges the rotation
+	require.Equal(t, alice.did, alice.connection(t).MyDID)
+
+	aliceDID, err := alice.didConnStore.GetDID(recipientKey(t, alice, newDID))
+	require.NoError(t, err)
+	require.Equal(t, newDID, aliceDID)
+
+	// the new DID is signed with the old key and the peer DID document is attached
+	rotateMsg := alice.sent(t, oldKey)
+	require.Equal(t, RotateMsgType, rotateMsg.Type())
+	require.Equal(t, thID, rotateMsg.ID())
+
+	rotate := &Rotate{}
+	require.NoError(t, rotateMsg.Decode(rotate))
+	require.Equal(t, newDID, rotate.ToDID)
+	require.NotNil(t, rotate.ToDIDDoc)
+
+	signedData, err := base64.URLEncoding.DecodeString(rotate.ToDIDSignature.SignedData)
+	require.NoError(t, err)
+
+	signed := &SignedRotation{}
+	require.NoError(t, json.Unmarshal(signedData, signed))
+	require.Equal(t, &SignedRotation{ThreadID: thID, FromDID: alice.did, ToDID: newDID}, signed)
+
+	_, err = bob.svc.HandleInbound(rotateMsg, bob.did, alice.did)
+	require.NoError(t, err)
+
+	record := bob.connection(t)
+	require.Equal(t, newDID, record.TheirDID)
+	require.Equal(t, resolve(t, alice, newDID).Service[0].RecipientKeys, record.RecipientKeys)
+
+	bob.requireConnectionDIDs(t, bob.did, newDID)
+
+	_, err = bob.recorder.GetConnectionIDByDIDs(bob.did, alice.did)
+	require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+	aliceDID, err = bob.didConnStore.GetDID(recipientKey(t, bob, newDID))
+	require.NoError(t, err)
+	require.Equal(t, newDID, aliceDID)
+
+	bob.requireEvent(t, StateRotated, nil)
+
+	// bob acknowledges the rotation on the new DID
+	ackMsg := bob.sent(t, recipientKey(t, bob, bob.did))
+	require.Equal(t, AckMsgType, ackMsg.Type())
+
+	ackThID, err := ackMsg.ThreadID()
+	require.NoError(t, err)
+	require.Equal(t, thID, ackThID)
+
+	_, err = alice.svc.HandleInbound(ackMsg, newDID, bob.did)
+	require.NoError(t, err)
+
+	require.Equal(t, newDID, alice.connection(t).MyDID)
+	alice.requireConnectionDIDs(t, newDID, bob.did)
+
+	_, err = alice.recorder.GetConnectionIDByDIDs(alice.did, bob.did)
+	require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+	alice.requireEvent(t, StateRotated, nil)
+
+	// the rotation is done
+	_, err = alice.svc.HandleInbound(ackMsg, newDID, bob.did)
+	require.True(t, errors.Is(err, ErrRotationNotFound))
+
+	// a replay of the rotation isn't signed by a key of the current DID
+	_, err = bob.svc.HandleInbound(rotateMsg, bob.did, newDID)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "signer "+oldKey+" is not a key of DID "+newDID)
+}
+
+func TestService_RotateDID_Rejected(t *testing.T) {
+	alice, bob := newAgent(t, "alice"), newAgent(t, "bob")
+	connect(t, alice, bob)
+
+	oldKey := recipientKey(t, alice, alice.did)
+
+	_, err := alice.svc.RotateDID(alice.connID(), alice.newDID(t))
+	require.NoError(t, err)
+
+	rotate := &Rotate{}
+	require.NoError(t, alice.sent(t, oldKey).Decode(rotate))
+
+	rotate.ToDIDSignature.Signature = base64.URLEncoding.EncodeToString([]byte("invalid"))
+
+	_, err = bob.svc.HandleInbound(service.NewDIDCommMsgMap(rotate), bob.did, alice.did)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "verify signature")
+
+	// bob keeps the old DID and tells alice why
+	require.Equal(t, alice.did, bob.connection(t).TheirDID)
+	bob.requireConnectionDIDs(t, bob.did, alice.did)
+
+	reportMsg := bob.sent(t, recipientKey(t, bob, bob.did))
+	require.Equal(t, ProblemReportMsgType, reportMsg.Type())
+
+	report := &ProblemReport{}
+	require.NoError(t, reportMsg.Decode(report))
+	require.Equal(t, CodeInvalidSignature, report.Description.Code)
+	require.Equal(t, rotate.ID, report.Thread.ID)
+
+	_, err = alice.svc.HandleInbound(reportMsg, alice.did, bob.did)
+	require.NoError(t, err)
+
+	require.Equal(t, alice.did, alice.connection(t).MyDID)
+	alice.requireEvent(t, StateFailed, errors.New("rotation to "+rotate.ToDID+" rejected: "+
+		CodeInvalidSignature+" "+report.Description.En))
+
+	_, err = alice.svc.HandleInbound(reportMsg, alice.did, bob.did)
+	require.True(t, errors.Is(err, ErrRotationNotFound))
+}
+
+func TestService_Hangup(t *testing.T) {
+	alice, bob := newAgent(t, "alice"), newAgent(t, "bob")
+	connect(t, alice, bob)
+
+	require.NoError(t, alice.svc.Hangup(alice.connID()))
+
+	_, err := alice.recorder.GetConnectionRecord(alice.connID())
+	require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+	hangupMsg := alice.sent(t, recipientKey(t, alice, alice.did))
+	require.Equal(t, HangupMsgType, hangupMsg.Type())
+
+	_, err = bob.svc.HandleInbound(hangupMsg, bob.did, alice.did)
+	require.NoError(t, err)
+
+	_, err = bob.recorder.GetConnectionRecord(bob.connID())
+	require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+	bob.requireEvent(t, StateHungUp, nil)
+
+	_, err = bob.svc.HandleInbound(hangupMsg, bob.did, alice.did)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "get connection ID")
+
+	require.Error(t, alice.svc.Hangup(alice.connID()))
+}
+
+func TestService_EventConsumer(t *testing.T) {
+	alice, bob := newAgent(t, "alice"), newAgent(t, "bob")
+	connect(t, alice, bob)
+
+	newDID := alice.newDID(t)
+
+	_, err := alice.svc.RotateDID(alice.connID(), newDID)
+	require.NoError(t, err)
+
+	_, err = bob.svc.HandleInbound(alice.sent(t, recipientKey(t, alice, alice.did)), bob.did, alice.did)
+	require.NoError(t, err)
+
+	// the consumer hangs up once the rotation is acknowledged, the event is delivered to the other consumer after that
+	events, others := make(chan service.StateMsg), make(chan service.StateMsg)
+	require.NoError(t, alice.svc.RegisterMsgEvent(events))
+	require.NoError(t, alice.svc.RegisterMsgEvent(others))
+
+	done := make(chan error)
+
+	go func() {
+		<-events
+
+		err := alice.svc.Hangup(alice.connID())
+
+		<-others
+
+		done <- err
+	}()
+
+	ackMsg := bob.sent(t, recipientKey(t, bob, bob.did))
+
+	go func() {
+		_, err := alice.svc.HandleInbound(ackMsg, newDID, bob.did)
+		done <- err
+	}()
+
+	for i := 0; i < 2; i++ {
+		select {
+		case err = <-done:
+			require.NoError(t, err)
+		case <-time.After(time.Second):
+			require.Fail(t, "the event consumer is blocked")
+		}
+	}
+
+	hangupMsg := alice.sent(t, recipientKey(t, alice, newDID))
+	require.Equal(t, HangupMsgType, hangupMsg.Type())
+}
+
+func TestService_RotateDID_Errors(t *testing.T) {
+	alice, bob := newAgent(t, "alice"), newAgent(t, "bob")
+	connect(t, alice, bob)
+
+	t.Run("unknown connection", func(t *testing.T) {
+		_, err := alice.svc.RotateDID("unknown", alice.newDID(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "get connection record")
+	})
+
+	t.Run("connection is not completed", func(t *testing.T) {
+		require.NoError(t, alice.recorder.SaveConnectionRecord(&connection.Record{
+			ConnectionID: "invited",
+			State:        "invited",
+		}))
+
+		_, err := alice.svc.RotateDID("invited", alice.newDID(t))
+		require.EqualError(t, err, "connection invited is not completed")
+	})
+
+	t.Run("same DID", func(t *testing.T) {
+		_, err := alice.svc.RotateDID(alice.connID(), alice.did)
+		require.EqualError(t, err, "connection "+alice.connID()+" already uses DID "+alice.did)
+	})
+
+	t.Run("unresolvable DID", func(t *testing.T) {
+		_, err := alice.svc.RotateDID(alice.connID(), "did:peer:unknown")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "resolve DID did:peer:unknown")
+	})
+
+	t.Run("DID without DIDComm service", func(t *testing.T) {
+		docResolution, err := alice.provider.VDRegistryValue.Create(peer.DIDMethod, &did.Doc{})
+		require.NoError(t, err)
+
+		_, err = alice.svc.RotateDID(alice.connID(), docResolution.DIDDocument.ID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "has no DIDComm service")
+	})
+
+	t.Run("send error", func(t *testing.T) {
+		alice.provider.OutboundDispatcherValue.(*mockdispatcher.MockOutbound).ValidateSend = func(
+			interface{}, string, *service.Destination) error {
+			return errors.New("send error")
+		}
+		defer func() {
+			alice.provider.OutboundDispatcherValue.(*mockdispatcher.MockOutbound).ValidateSend = alice.record
+		}()
+
+		_, err := alice.svc.RotateDID(alice.connID(), alice.newDID(t))
+		require.EqualError(t, err, "send message: send error")
+	})
+}
+
+func TestService_HandleInbound_Errors(t *testing.T) {
+	alice, bob := newAgent(t, "alice"), newAgent(t, "bob")
+	connect(t, alice, bob)
+
+	oldKey := recipientKey(t, alice, alice.did)
+	newDID := alice.newDID(t)
+
+	_, err := alice.svc.RotateDID(alice.connID(), newDID)
+	require.NoError(t, err)
+
+	rotate := &Rotate{}
+	require.NoError(t, alice.sent(t, oldKey).Decode(rotate))
+
+	handleRotate := func(t *testing.T, r *Rotate) error {
+		t.Helper()
+
+		_, err := bob.svc.HandleInbound(service.NewDIDCommMsgMap(r), bob.did, alice.did)
+		require.Error(t, err)
+		require.Equal(t, alice.did, bob.connection(t).TheirDID)
+
+		return err
+	}
+
+	t.Run("unsupported message", func(t *testing.T) {
+		_, err = bob.svc.HandleInbound(service.NewDIDCommMsgMap(struct {
+			Type string `json:"@type"`
+		}{Type: "unsupported"}), bob.did, alice.did)
+		require.EqualError(t, err, "unsupported message type unsupported")
+	})
+
+	t.Run("unknown connection", func(t *testing.T) {
+		_, err = bob.svc.HandleInbound(service.NewDIDCommMsgMap(rotate), bob.did, "")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "get connection ID")
+	})
+
+	t.Run("missing signature", func(t *testing.T) {
+		err = handleRotate(t, &Rotate{Type: RotateMsgType, ID: "id", ToDID: newDID})
+		require.EqualError(t, err, "handle "+RotateMsgType+": missing new DID or its signature")
+	})
+
+	t.Run("signed DID does not match", func(t *testing.T) {
+		r := *rotate
+		r.ToDID = bob.did
+
+		err = handleRotate(t, &r)
+		require.Contains(t, err.Error(), "does not match the new DID "+bob.did)
+	})
+
+	t.Run("signature of another rotation", func(t *testing.T) {
+		r := *rotate
+		r.ID = "other-thread"
+
+		err = handleRotate(t, &r)
+		require.Contains(t, err.Error(), "does not match the rotation thread other-thread")
+	})
+
+	t.Run("signature from another DID", func(t *testing.T) {
+		err = checkSignedRotation(&SignedRotation{ThreadID: rotate.ID, FromDID: bob.did, ToDID: newDID}, rotate,
+			alice.did)
+		require.EqualError(t, err, "signed old DID "+bob.did+" does not match the DID "+alice.did)
+	})
+
+	t.Run("signed by other DID", func(t *testing.T) {
+		r := *rotate
+		signature := *rotate.ToDIDSignature
+		signature.SignVerKey = recipientKey(t, bob, bob.did)
+		r.ToDIDSignature = &signature
+
+		err = handleRotate(t, &r)
+		require.Contains(t, err.Error(), "is not a key of DID "+alice.did)
+	})
+
+	t.Run("attached document of other DID", func(t *testing.T) {
+		docBytes, errMarshal := resolve(t, alice, alice.did).JSONBytes()
+		require.NoError(t, errMarshal)
+
+		r := *rotate
+		attachment := *rotate.ToDIDDoc
+		attachment.Data.Base64 = base64.StdEncoding.EncodeToString(docBytes)
+		r.ToDIDDoc = &attachment
+
+		err = handleRotate(t, &r)
+		require.Contains(t, err.Error(), "does not match the new DID "+newDID)
+
+		report := &ProblemReport{}
+		require.NoError(t, bob.sent(t, recipientKey(t, bob, bob.did)).Decode(report))
+		require.Equal(t, CodeUnresolvableDID, report.Description.Code)
+	})
+
+	t.Run("ack of unknown rotation", func(t *testing.T) {
+		_, err = alice.svc.HandleInbound(service.NewDIDCommMsgMap(&Ack{
+			Type:   AckMsgType,
+			ID:     "id",
+			Thread: &decorator.Thread{ID: "unknown"},
+		}), newDID, bob.did)
+		require.True(t, errors.Is(err, ErrRotationNotFound))
+	})
+
+	t.Run("ack of other party", func(t *testing.T) {
+		_, err = alice.svc.HandleInbound(service.NewDIDCommMsgMap(&Ack{
+			Type:   AckMsgType,
+			ID:     "id",
+			Thread: &decorator.Thread{ID: rotate.ID},
+		}), newDID, "did:peer:other")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "is not the counterparty of connection")
+		require.Equal(t, alice.did, alice.connection(t).MyDID)
+	})
+
+	t.Run("problem-report of other party", func(t *testing.T) {
+		_, err = alice.svc.HandleInbound(service.NewDIDCommMsgMap(&ProblemReport{
+			Type:   ProblemReportMsgType,
+			ID:     "id",
+			Thread: &decorator.Thread{ID: rotate.ID},
+		}), alice.did, "did:peer:other")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "is not the counterparty of connection")
+
+		// the rotation is still pending
+		_, err = alice.svc.getRotation(rotate.ID)
+		require.NoError(t, err)
+	})
+}
+
+type agent struct {
+	svc          *Service
+	provider     *mockprovider.Provider
+	didConnStore *didstore.ConnectionStore
+	recorder     *connection.Recorder
+	messages     []*sentMessage
+	events       chan service.StateMsg
+	did          string
+	label        string
+}
+
+type sentMessage struct {
+	msg       interface{}
+	senderKey string
+}
+
+func newAgent(t *testing.T, label string) *agent {
+	t.Helper()
+
+	a := &agent{label: label, events: make(chan service.StateMsg, 10)}
+
+	storeProvider := mem.NewProvider()
+
+	km, err := localkms.New("local-lock://primary/test/", &protocol.MockProvider{
+		StoreProvider: storeProvider,
+		CustomLock:    &noop.NoLock{},
+	})
+	require.NoError(t, err)
+
+	cr, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	peerVDR, err := peer.New(storeProvider)
+	require.NoError(t, err)
+
+	a.provider = &mockprovider.Provider{
+		StorageProviderValue:              storeProvider,
+		ProtocolStateStorageProviderValue: storeProvider,
+		KMSValue:                          km,
+		CryptoValue:                       cr,
+		OutboundDispatcherValue:           &mockdispatcher.MockOutbound{ValidateSend: a.record},
+	}
+
+	a.provider.VDRegistryValue = vdr.New(a.provider, vdr.WithVDR(peerVDR))
+
+	a.svc, err = New(a.provider)
+	require.NoError(t, err)
+
+	require.NoError(t, a.svc.RegisterMsgEvent(a.events))
+
+	a.didConnStore, err = didstore.NewConnectionStore(a.provider)
+	require.NoError(t, err)
+
+	a.recorder, err = connection.NewRecorder(a.provider)
+	require.NoError(t, err)
+
+	a.did = a.newDID(t)
+
+	return a
+}
+
+func (a *agent) record(msg interface{}, senderVerKey string, _ *service.Destination) error {
+	a.messages = append(a.messages, &sentMessage{msg: msg, senderKey: senderVerKey})
+
+	return nil
+}
+
+// newDID creates a peer DID with a DIDComm service.
+func (a *agent) newDID(t *testing.T) string {
+	t.Helper()
+
+	docResolution, err := a.provider.VDRegistryValue.Create(peer.DIDMethod, &did.Doc{
+		Service: []did.Service{{
+			Type:            vdrapi.DIDCommServiceType,
+			ServiceEndpoint: "https://" + a.label + ".example.com",
+		}},
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, a.didConnStore.SaveDIDFromDoc(docResolution.DIDDocument))
+
+	return docResolution.DIDDocument.ID
+}
+
+// sent returns the last message the agent sent and checks its sender key.
+func (a *agent) sent(t *testing.T, senderKey string) service.DIDCommMsg {
+	t.Helper()
+
+	require.NotEmpty(t, a.messages)
+
+	sent := a.messages[len(a.messages)-1]
+	require.Equal(t, senderKey, sent.senderKey)
+
+	src, err := json.Marshal(sent.msg)
+	require.NoError(t, err)
+
+	msg, err := service.ParseDIDCommMsgMap(src)
+	require.NoError(t, err)
+
+	return msg
+}
+
+func (a *agent) requireEvent(t *testing.T, stateID string, expectedErr error) {
+	t.Helper()
+
+	select {
+	case e := <-a.events:
+		require.Equal(t, DIDRotate, e.ProtocolName)
+		require.Equal(t, service.PostState, e.Type)
+		require.Equal(t, stateID, e.StateID)
+
+		props, ok := e.Properties.(*eventProps)
+		require.True(t, ok)
+		require.Equal(t, a.connID(), props.ConnectionID())
+		require.Equal(t, expectedErr, props.Error())
+		require.Equal(t, a.connID(), props.All()["connectionID"])
+	case <-time.After(time.Second):
+		require.Fail(t, "no event")
+	}
+}
+
+func (a *agent) requireConnectionDIDs(t *testing.T, myDID, theirDID string) {
+	t.Helper()
+
+	connID, err := a.recorder.GetConnectionIDByDIDs(myDID, theirDID)
+	require.NoError(t, err)
+	require.Equal(t, a.connID(), connID)
+}
+
+func (a *agent) connID() string {
+	return a.label + "-connection"
+}
+
+func (a *agent) connection(t *testing.T) *connection.Record {
+	t.Helper()
+
+	record, err := a.recorder.GetConnectionRecord(a.connID())
+	require.NoError(t, err)
+
+	return record
+}
+
+// connect stores the DID documents of the counterparty and the completed connection records.
+func connect(t *testing.T, a, b *agent) {
+	t.Helper()
+
+	for _, pair := range [][]*agent{{a, b}, {b, a}} {
+		me, they := pair[0], pair[1]
+
+		theirDoc := resolve(t, they, they.did)
+
+		_, err := me.provider.VDRegistryValue.Create(peer.DIDMethod, theirDoc, vdrapi.WithOption("store", true))
+		require.NoError(t, err)
+
+		require.NoError(t, me.didConnStore.SaveDIDFromDoc(theirDoc))
+
+		require.NoError(t, me.recorder.SaveConnectionRecord(&connection.Record{
+			ConnectionID:  me.connID(),
+			State:         connection.StateNameCompleted,
+			ThreadID:      "thread",
+			MyDID:         me.did,
+			TheirDID:      they.did,
+			TheirLabel:    they.label,
+			RecipientKeys: theirDoc.Service[0].RecipientKeys,
+		}))
+	}
+}
+
+func recipientKey(t *testing.T, a *agent, didID string) string {
+	t.Helper()
+
+	return resolve(t, a, didID).Service[0].RecipientKeys[0]
+}
+
+func resolve(t *testing.T, a *agent, didID string) *did.Doc {
+	t.Helper()
+
+	docResolution, err := a.provider.VDRegistryValue.Resolve(didID)
+	require.NoError(t, err)
+
+	return docResolution.DIDDocument
+}
+
+type mockStorageProvider struct {
+	storage.Provider
+	errOpen error
+}
+
+func (p *mockStorageProvider) OpenStore(string) (storage.Store, error) {
+	return nil, p.errOpen
+}
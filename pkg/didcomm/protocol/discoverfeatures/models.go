@@ -0,0 +1,61 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discoverfeatures
+
+import "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+
+// Query is the discover features 1.0 query for the protocols supported by the counterparty.
+type Query struct {
+	Type string `json:"@type,omitempty"`
+	ID   string `json:"@id,omitempty"`
+	// Query is the protocol identifier to match, '*' matches any sequence of characters.
+	Query   string `json:"query,omitempty"`
+	Comment string `json:"comment,omitempty"`
+}
+
+// Disclose is the discover features 1.0 response to the Query.
+type Disclose struct {
+	Type      string                `json:"@type,omitempty"`
+	ID        string                `json:"@id,omitempty"`
+	Protocols []*ProtocolDescriptor `json:"protocols"`
+	Thread    *decorator.Thread     `json:"~thread,omitempty"`
+}
+
+// ProtocolDescriptor describes the protocol supported by the agent.
+type ProtocolDescriptor struct {
+	PID   string   `json:"pid"`
+	Roles []string `json:"roles,omitempty"`
+}
+
+// Queries is the discover features 2.0 query for the features supported by the counterparty.
+type Queries struct {
+	Type    string          `json:"@type,omitempty"`
+	ID      string          `json:"@id,omitempty"`
+	Queries []*FeatureQuery `json:"queries"`
+}
+
+// FeatureQuery is a query of the features of the type.
+type FeatureQuery struct {
+	FeatureType string `json:"feature-type"`
+	// Match is the feature identifier to match, '*' matches any sequence of characters.
+	Match string `json:"match"`
+}
+
+// DiscloseV2 is the discover features 2.0 response to the Queries.
+type DiscloseV2 struct {
+	Type        string            `json:"@type,omitempty"`
+	ID          string            `json:"@id,omitempty"`
+	Disclosures []*Feature        `json:"disclosures"`
+	Thread      *decorator.Thread `json:"~thread,omitempty"`
+}
+
+// Feature is a feature supported by the agent.
+type Feature struct {
+	FeatureType string   `json:"feature-type"`
+	ID          string   `json:"id"`
+	Roles       []string `json:"roles,omitempty"`
+}
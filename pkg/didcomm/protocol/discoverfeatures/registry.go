@@ -0,0 +1,151 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discoverfeatures
+
+import (
+	"strings"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/dispatcher"
+)
+
+// Feature types.
+const (
+	// FeatureTypeProtocol is a DIDComm protocol, the ID is the protocol identifier
+	// (ex. https://didcomm.org/trust_ping/1.0).
+	FeatureTypeProtocol = "protocol"
+	// FeatureTypeGoalCode is a goal code, the ID is the code (ex. aries.vc.issue).
+	FeatureTypeGoalCode = "goal-code"
+	// FeatureTypeAttachmentFormat is an attachment format, the ID is the format (ex. aries/ld-proof-vc@v1.0).
+	FeatureTypeAttachmentFormat = "attachment-format"
+)
+
+// localFeatures returns the features of the agent: the protocols handled by the services and the registered
+// features, except the ones hidden by the allow-list.
+func (s *Service) localFeatures() []*Feature {
+	s.lock.RLock()
+	registered := append([]*Feature(nil), s.features...)
+	allowList := s.allowList
+	s.lock.RUnlock()
+
+	var features []*Feature
+
+	seen := map[string]struct{}{}
+
+	add := func(f *Feature) {
+		key := f.FeatureType + " " + f.ID
+		if _, ok := seen[key]; ok || !allowed(allowList, f) {
+			return
+		}
+
+		seen[key] = struct{}{}
+
+		features = append(features, f)
+	}
+
+	for _, msgType := range s.messageTypes() {
+		add(&Feature{FeatureType: FeatureTypeProtocol, ID: protocolID(msgType)})
+	}
+
+	for _, f := range registered {
+		add(f)
+	}
+
+	return features
+}
+
+// disclose returns the local features matching any of the queries.
+func (s *Service) disclose(queries []*FeatureQuery) []*Feature {
+	var result []*Feature
+
+	for _, f := range s.localFeatures() {
+		for _, q := range queries {
+			if q != nil && q.FeatureType == f.FeatureType && match(q.Match, f.ID) {
+				result = append(result, f)
+
+				break
+			}
+		}
+	}
+
+	return result
+}
+
+// messageTypes returns the message types disclosed by the protocol and message services of the agent
+// (see dispatcher.Discloser).
+func (s *Service) messageTypes() []string {
+	var services []interface{}
+
+	for _, svc := range s.services() {
+		services = append(services, svc)
+	}
+
+	if s.msgServices != nil {
+		for _, svc := range s.msgServices.Services() {
+			services = append(services, svc)
+		}
+	}
+
+	var msgTypes []string
+
+	for _, svc := range services {
+		if d, ok := svc.(dispatcher.Discloser); ok {
+			msgTypes = append(msgTypes, d.MessageTypes()...)
+		}
+	}
+
+	return msgTypes
+}
+
+func allowed(allowList []string, f *Feature) bool {
+	if len(allowList) == 0 {
+		return true
+	}
+
+	for _, pattern := range allowList {
+		if match(pattern, f.ID) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// protocolID returns the protocol identifier of the message type,
+// ex. https://didcomm.org/trust_ping/1.0/ping -> https://didcomm.org/trust_ping/1.0.
+func protocolID(msgType string) string {
+	i := strings.LastIndex(msgType, "/")
+	if i < 0 {
+		return msgType
+	}
+
+	return msgType[:i]
+}
+
+// match reports whether the value matches the pattern, '*' in the pattern matches any sequence of characters.
+func match(pattern, value string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == value
+	}
+
+	if !strings.HasPrefix(value, parts[0]) {
+		return false
+	}
+
+	value = value[len(parts[0]):]
+
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(value, part)
+		if i < 0 {
+			return false
+		}
+
+		value = value[i+len(part):]
+	}
+
+	return strings.HasSuffix(value, parts[len(parts)-1])
+}
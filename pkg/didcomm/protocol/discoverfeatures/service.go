@@ -0,0 +1,317 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discoverfeatures
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/dispatcher"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+)
+
+const (
+	// DiscoverFeatures defines the protocol name.
+	DiscoverFeatures = "discoverfeatures"
+	// SpecV1 defines the discover features 1.0 protocol spec.
+	SpecV1 = "https://didcomm.org/discover-features/1.0/"
+	// QueryMsgTypeV1 defines the discover features 1.0 query message type.
+	QueryMsgTypeV1 = SpecV1 + "query"
+	// DiscloseMsgTypeV1 defines the discover features 1.0 disclose message type.
+	DiscloseMsgTypeV1 = SpecV1 + "disclose"
+	// SpecV2 defines the discover features 2.0 protocol spec.
+	SpecV2 = "https://didcomm.org/discover-features/2.0/"
+	// QueriesMsgTypeV2 defines the discover features 2.0 queries message type.
+	QueriesMsgTypeV2 = SpecV2 + "queries"
+	// DiscloseMsgTypeV2 defines the discover features 2.0 disclose message type.
+	DiscloseMsgTypeV2 = SpecV2 + "disclose"
+)
+
+// DefaultTimeout is the default time to wait for the disclosure.
+const DefaultTimeout = 30 * time.Second
+
+var logger = log.New("aries-framework/discoverfeatures")
+
+// ErrTimeout is returned when the disclosure is not received in time.
+var ErrTimeout = errors.New("timeout waiting for disclosure")
+
+// Provider contains dependencies for the discover features protocol.
+type Provider interface {
+	OutboundDispatcher() dispatcher.Outbound
+	StorageProvider() storage.Provider
+	ProtocolStateStorageProvider() storage.Provider
+	AllServices() []dispatcher.ProtocolService
+	MessageServiceProvider() api.MessageServiceProvider
+}
+
+type connections interface {
+	GetConnectionRecord(string) (*connection.Record, error)
+}
+
+// Service for the discover features protocol. It discloses the protocols handled by the protocol and message
+// services of the agent and the features registered with Register, and responds to the queries automatically.
+type Service struct {
+	outbound         dispatcher.Outbound
+	connectionLookup connections
+	services         func() []dispatcher.ProtocolService
+	msgServices      api.MessageServiceProvider
+	features         []*Feature
+	allowList        []string
+	lock             sync.RWMutex
+	pending          map[string]chan []*Feature
+	pendingLock      sync.Mutex
+}
+
+// New returns the discover features service.
+func New(prov Provider) (*Service, error) {
+	connectionLookup, err := connection.NewLookup(prov)
+	if err != nil {
+		return nil, fmt.Errorf("new connection lookup: %w", err)
+	}
+
+	return &Service{
+		outbound:         prov.OutboundDispatcher(),
+		connectionLookup: connectionLookup,
+		// services are resolved on every query since the services registered after this one are disclosed too
+		services:    prov.AllServices,
+		msgServices: prov.MessageServiceProvider(),
+		pending:     make(map[string]chan []*Feature),
+	}, nil
+}
+
+// HandleInbound handles inbound discover features messages.
+func (s *Service) HandleInbound(msg service.DIDCommMsg, myDID, theirDID string) (string, error) {
+	switch msg.Type() {
+	case QueryMsgTypeV1:
+		return "", s.handleQuery(msg, myDID, theirDID)
+	case QueriesMsgTypeV2:
+		return "", s.handleQueries(msg, myDID, theirDID)
+	case DiscloseMsgTypeV1, DiscloseMsgTypeV2:
+		return "", s.handleDisclose(msg)
+	}
+
+	return "", fmt.Errorf("unsupported message type %s", msg.Type())
+}
+
+// HandleOutbound adherence to dispatcher.ProtocolService.
+func (s *Service) HandleOutbound(_ service.DIDCommMsg, _, _ string) (string, error) {
+	return "", errors.New("not implemented")
+}
+
+// Accept checks whether the service can handle the message type.
+func (s *Service) Accept(msgType string) bool {
+	switch msgType {
+	case QueryMsgTypeV1, DiscloseMsgTypeV1, QueriesMsgTypeV2, DiscloseMsgTypeV2:
+		return true
+	}
+
+	return false
+}
+
+// MessageTypes returns the message types handled by the service.
+func (s *Service) MessageTypes() []string {
+	return []string{
+		QueryMsgTypeV1, DiscloseMsgTypeV1, QueriesMsgTypeV2, DiscloseMsgTypeV2,
+	}
+}
+
+// Name of the service.
+func (s *Service) Name() string {
+	return DiscoverFeatures
+}
+
+// Register registers the features of the agent which can't be discovered from its services,
+// ex. goal codes and attachment formats.
+func (s *Service) Register(features ...*Feature) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.features = append(s.features, features...)
+}
+
+// SetAllowList sets the patterns of the feature IDs to disclose, '*' matches any sequence of characters.
+// The features not matching any of the patterns are hidden. All the features are disclosed if the list is empty.
+func (s *Service) SetAllowList(patterns ...string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.allowList = patterns
+}
+
+// LocalFeatures returns the features of the agent matching the queries.
+func (s *Service) LocalFeatures(queries ...*FeatureQuery) []*Feature {
+	return s.disclose(queries)
+}
+
+// QueryProtocols queries the protocols supported by the connection with discover features 1.0.
+// The query is the protocol identifier to match, '*' matches any sequence of characters.
+func (s *Service) QueryProtocols(connectionID, query string, timeout time.Duration) ([]*Feature, error) {
+	id := uuid.New().String()
+
+	return s.query(connectionID, id, &Query{
+		Type:  QueryMsgTypeV1,
+		ID:    id,
+		Query: query,
+	}, timeout)
+}
+
+// QueryFeatures queries the features supported by the connection with discover features 2.0.
+func (s *Service) QueryFeatures(connectionID string, queries []*FeatureQuery,
+	timeout time.Duration) ([]*Feature, error) {
+	id := uuid.New().String()
+
+	return s.query(connectionID, id, &Queries{
+		Type:    QueriesMsgTypeV2,
+		ID:      id,
+		Queries: queries,
+	}, timeout)
+}
+
+func (s *Service) query(connectionID, id string, msg interface{}, timeout time.Duration) ([]*Feature, error) {
+	conn, err := s.connectionLookup.GetConnectionRecord(connectionID)
+	if err != nil {
+		return nil, fmt.Errorf("get connection record: %w", err)
+	}
+
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	disclosureCh := make(chan []*Feature, 1)
+
+	s.setPending(id, disclosureCh)
+	defer s.setPending(id, nil)
+
+	if err = s.outbound.SendToDID(msg, conn.MyDID, conn.TheirDID); err != nil {
+		return nil, fmt.Errorf("send query: %w", err)
+	}
+
+	select {
+	case features := <-disclosureCh:
+		return features, nil
+	case <-time.After(timeout):
+		return nil, ErrTimeout
+	}
+}
+
+func (s *Service) handleQuery(msg service.DIDCommMsg, myDID, theirDID string) error {
+	query := &Query{}
+
+	if err := msg.Decode(query); err != nil {
+		return fmt.Errorf("decode query: %w", err)
+	}
+
+	disclose := &Disclose{
+		Type:      DiscloseMsgTypeV1,
+		ID:        uuid.New().String(),
+		Protocols: []*ProtocolDescriptor{},
+		Thread:    &decorator.Thread{ID: query.ID},
+	}
+
+	for _, f := range s.disclose([]*FeatureQuery{{FeatureType: FeatureTypeProtocol, Match: query.Query}}) {
+		disclose.Protocols = append(disclose.Protocols, &ProtocolDescriptor{PID: f.ID, Roles: f.Roles})
+	}
+
+	if err := s.outbound.SendToDID(disclose, myDID, theirDID); err != nil {
+		return fmt.Errorf("send disclose: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) handleQueries(msg service.DIDCommMsg, myDID, theirDID string) error {
+	queries := &Queries{}
+
+	if err := msg.Decode(queries); err != nil {
+		return fmt.Errorf("decode queries: %w", err)
+	}
+
+	disclose := &DiscloseV2{
+		Type:        DiscloseMsgTypeV2,
+		ID:          uuid.New().String(),
+		Disclosures: s.disclose(queries.Queries),
+		Thread:      &decorator.Thread{ID: queries.ID},
+	}
+
+	if disclose.Disclosures == nil {
+		disclose.Disclosures = []*Feature{}
+	}
+
+	if err := s.outbound.SendToDID(disclose, myDID, theirDID); err != nil {
+		return fmt.Errorf("send disclose: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) handleDisclose(msg service.DIDCommMsg) error {
+	thID, err := msg.ThreadID()
+	if err != nil {
+		return fmt.Errorf("disclose thread ID: %w", err)
+	}
+
+	var features []*Feature
+
+	if msg.Type() == DiscloseMsgTypeV1 {
+		disclose := &Disclose{}
+
+		if err = msg.Decode(disclose); err != nil {
+			return fmt.Errorf("decode disclose: %w", err)
+		}
+
+		for _, p := range disclose.Protocols {
+			features = append(features, &Feature{FeatureType: FeatureTypeProtocol, ID: p.PID, Roles: p.Roles})
+		}
+	} else {
+		disclose := &DiscloseV2{}
+
+		if err = msg.Decode(disclose); err != nil {
+			return fmt.Errorf("decode disclose: %w", err)
+		}
+
+		features = disclose.Disclosures
+	}
+
+	s.pendingLock.Lock()
+	disclosureCh := s.pending[thID]
+	s.pendingLock.Unlock()
+
+	if disclosureCh == nil {
+		logger.Debugf("disclose %s received for unknown query %s", msg.ID(), thID)
+
+		return nil
+	}
+
+	select {
+	case disclosureCh <- features:
+	default:
+	}
+
+	return nil
+}
+
+func (s *Service) setPending(id string, disclosureCh chan []*Feature) {
+	s.pendingLock.Lock()
+	defer s.pendingLock.Unlock()
+
+	if disclosureCh == nil {
+		delete(s.pending, id)
+
+		return
+	}
+
+	s.pending[id] = disclosureCh
+}
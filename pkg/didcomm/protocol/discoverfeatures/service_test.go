@@ -0,0 +1,466 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discoverfeatures
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/dispatcher"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/messaging/service/basic"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api"
+	mockdispatcher "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/dispatcher"
+	"github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/msghandler"
+	mockdidexchange "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol/didexchange"
+	mocktrustping "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol/trustping"
+	mockprovider "github.com/hyperledger/aries-framework-go/pkg/mock/provider"
+	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+)
+
+const (
+	aliceDID = "did:example:alice"
+	bobDID   = "did:example:bob"
+	connID   = "conn-1"
+
+	trustPingProtocol = "https://didcomm.org/trust_ping/1.0"
+	basicMsgProtocol  = "https://didcomm.org/basicmessage/1.0"
+	discoverV1        = "https://didcomm.org/discover-features/1.0"
+	discoverV2        = "https://didcomm.org/discover-features/2.0"
+	customProtocol    = "https://example.com/custom/1.0"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		svc, err := New(newProvider(t, &mockdispatcher.MockOutbound{}))
+		require.NoError(t, err)
+		require.Equal(t, DiscoverFeatures, svc.Name())
+		require.True(t, svc.Accept(QueryMsgTypeV1))
+		require.True(t, svc.Accept(DiscloseMsgTypeV1))
+		require.True(t, svc.Accept(QueriesMsgTypeV2))
+		require.True(t, svc.Accept(DiscloseMsgTypeV2))
+		require.False(t, svc.Accept("unknown"))
+	})
+
+	t.Run("connection lookup error", func(t *testing.T) {
+		_, err := New(&testProvider{Provider: &mockprovider.Provider{
+			StorageProviderValue: &mockstorage.MockStoreProvider{
+				ErrOpenStoreHandle: errors.New("test error"),
+			},
+			ProtocolStateStorageProviderValue: mem.NewProvider(),
+		}})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "new connection lookup")
+	})
+}
+
+func TestService_LocalFeatures(t *testing.T) {
+	t.Run("protocols of the services", func(t *testing.T) {
+		svc, err := New(newProvider(t, &mockdispatcher.MockOutbound{}))
+		require.NoError(t, err)
+
+		features := svc.LocalFeatures(&FeatureQuery{FeatureType: FeatureTypeProtocol, Match: "*"})
+		require.Equal(t, []string{trustPingProtocol, discoverV1, discoverV2, basicMsgProtocol}, ids(features))
+	})
+
+	t.Run("registered features", func(t *testing.T) {
+		svc, err := New(newProvider(t, &mockdispatcher.MockOutbound{}))
+		require.NoError(t, err)
+
+		svc.Register(
+			&Feature{FeatureType: FeatureTypeGoalCode, ID: "aries.vc.issue"},
+			&Feature{FeatureType: FeatureTypeAttachmentFormat, ID: "aries/ld-proof-vc@v1.0"},
+			&Feature{FeatureType: FeatureTypeAttachmentFormat, ID: "hlindy/cred@v2.0"},
+			&Feature{FeatureType: FeatureTypeAttachmentFormat, ID: "hlindy/cred@v2.0"},
+		)
+
+		features := svc.LocalFeatures(&FeatureQuery{FeatureType: FeatureTypeAttachmentFormat, Match: "*"})
+		require.Equal(t, []string{"aries/ld-proof-vc@v1.0", "hlindy/cred@v2.0"}, ids(features))
+
+		features = svc.LocalFeatures(
+			&FeatureQuery{FeatureType: FeatureTypeGoalCode, Match: "aries.*"},
+			&FeatureQuery{FeatureType: FeatureTypeProtocol, Match: "*/trust_ping/*"},
+		)
+		require.Equal(t, []string{trustPingProtocol, "aries.vc.issue"}, ids(features))
+
+		require.Empty(t, svc.LocalFeatures())
+		require.Empty(t, svc.LocalFeatures(nil))
+	})
+
+	t.Run("allow-list", func(t *testing.T) {
+		svc, err := New(newProvider(t, &mockdispatcher.MockOutbound{}))
+		require.NoError(t, err)
+
+		svc.Register(&Feature{FeatureType: FeatureTypeGoalCode, ID: "aries.vc.issue"})
+		svc.SetAllowList("https://didcomm.org/discover-features/*", "aries.*")
+
+		features := svc.LocalFeatures(
+			&FeatureQuery{FeatureType: FeatureTypeProtocol, Match: "*"},
+			&FeatureQuery{FeatureType: FeatureTypeGoalCode, Match: "*"},
+		)
+		require.Equal(t, []string{discoverV1, discoverV2, "aries.vc.issue"}, ids(features))
+
+		svc.SetAllowList()
+
+		features = svc.LocalFeatures(&FeatureQuery{FeatureType: FeatureTypeProtocol, Match: "*"})
+		require.Len(t, features, 4)
+	})
+
+	t.Run("services registered later", func(t *testing.T) {
+		prov := newProvider(t, &mockdispatcher.MockOutbound{})
+
+		svc, err := New(prov)
+		require.NoError(t, err)
+
+		// the services which do not disclose their message types are skipped
+		prov.services = append(prov.services, &mockdidexchange.MockDIDExchangeSvc{})
+		msgServices, ok := prov.msgServices.(*msghandler.MockMsgSvcProvider)
+		require.True(t, ok)
+		require.NoError(t, msgServices.Register(&customMsgService{}))
+
+		features := svc.LocalFeatures(&FeatureQuery{FeatureType: FeatureTypeProtocol, Match: "*"})
+		require.Equal(t, []string{trustPingProtocol, discoverV1, discoverV2, basicMsgProtocol, customProtocol},
+			ids(features))
+	})
+
+	t.Run("without message services", func(t *testing.T) {
+		prov := newProvider(t, &mockdispatcher.MockOutbound{})
+		prov.msgServices = nil
+
+		svc, err := New(prov)
+		require.NoError(t, err)
+
+		features := svc.LocalFeatures(&FeatureQuery{FeatureType: FeatureTypeProtocol, Match: "*"})
+		require.Equal(t, []string{trustPingProtocol, discoverV1, discoverV2}, ids(features))
+	})
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		value   string
+		match   bool
+	}{
+		{pattern: "*", value: "", match: true},
+		{pattern: "*", value: "anything", match: true},
+		{pattern: "abc", value: "abc", match: true},
+		{pattern: "abc", value: "abcd", match: false},
+		{pattern: "ab*", value: "abcd", match: true},
+		{pattern: "*cd", value: "abcd", match: true},
+		{pattern: "a*c*e", value: "abcde", match: true},
+		{pattern: "a*c*e", value: "abde", match: false},
+		{pattern: "a*b*b", value: "ab", match: false},
+		{pattern: "", value: "", match: true},
+		{pattern: "", value: "a", match: false},
+	}
+
+	for _, tc := range tests {
+		require.Equal(t, tc.match, match(tc.pattern, tc.value), "%q %q", tc.pattern, tc.value)
+	}
+}
+
+func TestService_QueryProtocols(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		alice, bob := newConnectedServices(t)
+
+		features, err := alice.QueryProtocols(connID, "https://didcomm.org/discover-features/*", time.Second)
+		require.NoError(t, err)
+		require.Equal(t, []string{discoverV1, discoverV2}, ids(features))
+		require.Equal(t, FeatureTypeProtocol, features[0].FeatureType)
+
+		features, err = bob.QueryProtocols(connID, "unknown", 0)
+		require.NoError(t, err)
+		require.Empty(t, features)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		svc, err := New(newProvider(t, &mockdispatcher.MockOutbound{}))
+		require.NoError(t, err)
+
+		saveConnection(t, svc, aliceDID, bobDID)
+
+		_, err = svc.QueryProtocols(connID, "*", time.Millisecond)
+		require.True(t, errors.Is(err, ErrTimeout))
+		require.Empty(t, svc.pending)
+	})
+
+	t.Run("connection not found", func(t *testing.T) {
+		svc, err := New(newProvider(t, &mockdispatcher.MockOutbound{}))
+		require.NoError(t, err)
+
+		_, err = svc.QueryProtocols(connID, "*", time.Second)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "get connection record")
+	})
+
+	t.Run("send error", func(t *testing.T) {
+		svc, err := New(newProvider(t, &mockdispatcher.MockOutbound{SendErr: errors.New("test error")}))
+		require.NoError(t, err)
+
+		saveConnection(t, svc, aliceDID, bobDID)
+
+		_, err = svc.QueryProtocols(connID, "*", time.Second)
+		require.EqualError(t, err, "send query: test error")
+	})
+}
+
+func TestService_QueryFeatures(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		alice, bob := newConnectedServices(t)
+
+		bob.Register(
+			&Feature{FeatureType: FeatureTypeAttachmentFormat, ID: "aries/ld-proof-vc@v1.0"},
+			&Feature{FeatureType: FeatureTypeGoalCode, ID: "aries.vc.issue"},
+		)
+
+		features, err := alice.QueryFeatures(connID, []*FeatureQuery{
+			{FeatureType: FeatureTypeProtocol, Match: "*/trust_ping/*"},
+			{FeatureType: FeatureTypeAttachmentFormat, Match: "*"},
+		}, time.Second)
+		require.NoError(t, err)
+		require.Equal(t, []string{trustPingProtocol, "aries/ld-proof-vc@v1.0"}, ids(features))
+
+		features, err = bob.QueryFeatures(connID, []*FeatureQuery{
+			{FeatureType: FeatureTypeGoalCode, Match: "*"},
+		}, time.Second)
+		require.NoError(t, err)
+		require.Empty(t, features)
+	})
+
+	t.Run("send error", func(t *testing.T) {
+		svc, err := New(newProvider(t, &mockdispatcher.MockOutbound{SendErr: errors.New("test error")}))
+		require.NoError(t, err)
+
+		saveConnection(t, svc, aliceDID, bobDID)
+
+		_, err = svc.QueryFeatures(connID, nil, time.Second)
+		require.EqualError(t, err, "send query: test error")
+	})
+}
+
+func TestService_HandleInbound(t *testing.T) {
+	t.Run("disclose is sent to the querier", func(t *testing.T) {
+		var disclose *DiscloseV2
+
+		svc, err := New(newProvider(t, &mockdispatcher.MockOutbound{
+			ValidateSendToDID: func(msg interface{}, myDID, theirDID string) error {
+				require.Equal(t, aliceDID, myDID)
+				require.Equal(t, bobDID, theirDID)
+
+				disclose = msg.(*DiscloseV2)
+
+				return nil
+			},
+		}))
+		require.NoError(t, err)
+
+		_, err = svc.HandleInbound(service.NewDIDCommMsgMap(&Queries{
+			Type: QueriesMsgTypeV2, ID: "queries-1",
+		}), aliceDID, bobDID)
+		require.NoError(t, err)
+		require.NotNil(t, disclose)
+		require.Equal(t, DiscloseMsgTypeV2, disclose.Type)
+		require.Equal(t, "queries-1", disclose.Thread.ID)
+		require.NotNil(t, disclose.Disclosures)
+		require.Empty(t, disclose.Disclosures)
+	})
+
+	t.Run("disclose send error", func(t *testing.T) {
+		svc, err := New(newProvider(t, &mockdispatcher.MockOutbound{SendErr: errors.New("test error")}))
+		require.NoError(t, err)
+
+		_, err = svc.HandleInbound(service.NewDIDCommMsgMap(&Query{Type: QueryMsgTypeV1, ID: "query-1"}),
+			aliceDID, bobDID)
+		require.EqualError(t, err, "send disclose: test error")
+
+		_, err = svc.HandleInbound(service.NewDIDCommMsgMap(&Queries{Type: QueriesMsgTypeV2, ID: "queries-1"}),
+			aliceDID, bobDID)
+		require.EqualError(t, err, "send disclose: test error")
+	})
+
+	t.Run("invalid messages", func(t *testing.T) {
+		svc, err := New(newProvider(t, &mockdispatcher.MockOutbound{}))
+		require.NoError(t, err)
+
+		_, err = svc.HandleInbound(service.DIDCommMsgMap{"@type": QueryMsgTypeV1, "query": []string{"invalid"}},
+			aliceDID, bobDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "decode query")
+
+		_, err = svc.HandleInbound(service.DIDCommMsgMap{"@type": QueriesMsgTypeV2, "queries": "invalid"},
+			aliceDID, bobDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "decode queries")
+
+		_, err = svc.HandleInbound(service.DIDCommMsgMap{
+			"@type": DiscloseMsgTypeV1, "@id": "disclose-1",
+			"~thread": map[string]interface{}{"thid": "query-1"}, "protocols": "invalid",
+		}, aliceDID, bobDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "decode disclose")
+
+		_, err = svc.HandleInbound(service.DIDCommMsgMap{
+			"@type": DiscloseMsgTypeV2, "@id": "disclose-1",
+			"~thread": map[string]interface{}{"thid": "query-1"}, "disclosures": "invalid",
+		}, aliceDID, bobDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "decode disclose")
+
+		_, err = svc.HandleInbound(service.DIDCommMsgMap{"@type": DiscloseMsgTypeV2}, aliceDID, bobDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "disclose thread ID")
+	})
+
+	t.Run("disclose for unknown query", func(t *testing.T) {
+		svc, err := New(newProvider(t, &mockdispatcher.MockOutbound{}))
+		require.NoError(t, err)
+
+		_, err = svc.HandleInbound(service.NewDIDCommMsgMap(&DiscloseV2{
+			Type: DiscloseMsgTypeV2, ID: "disclose-1", Thread: &decorator.Thread{ID: "query-1"},
+		}), aliceDID, bobDID)
+		require.NoError(t, err)
+	})
+
+	t.Run("unsupported message type", func(t *testing.T) {
+		svc, err := New(newProvider(t, &mockdispatcher.MockOutbound{}))
+		require.NoError(t, err)
+
+		_, err = svc.HandleInbound(service.DIDCommMsgMap{"@type": "unknown"}, aliceDID, bobDID)
+		require.EqualError(t, err, "unsupported message type unknown")
+
+		_, err = svc.HandleOutbound(nil, aliceDID, bobDID)
+		require.EqualError(t, err, "not implemented")
+	})
+}
+
+// newConnectedServices returns two services connected with connID, which deliver the messages to each other.
+func newConnectedServices(t *testing.T) (*Service, *Service) {
+	t.Helper()
+
+	var alice, bob *Service
+
+	deliver := func(to **Service) func(msg interface{}, myDID, theirDID string) error {
+		return func(msg interface{}, myDID, theirDID string) error {
+			src, err := json.Marshal(msg)
+			require.NoError(t, err)
+
+			didCommMsg, err := service.ParseDIDCommMsgMap(src)
+			require.NoError(t, err)
+
+			go func() {
+				_, err := (*to).HandleInbound(didCommMsg, theirDID, myDID)
+				require.NoError(t, err)
+			}()
+
+			return nil
+		}
+	}
+
+	var err error
+
+	alice, err = New(newProvider(t, &mockdispatcher.MockOutbound{ValidateSendToDID: deliver(&bob)}))
+	require.NoError(t, err)
+
+	bob, err = New(newProvider(t, &mockdispatcher.MockOutbound{ValidateSendToDID: deliver(&alice)}))
+	require.NoError(t, err)
+
+	saveConnection(t, alice, aliceDID, bobDID)
+	saveConnection(t, bob, bobDID, aliceDID)
+
+	return alice, bob
+}
+
+type testProvider struct {
+	*mockprovider.Provider
+	services    []dispatcher.ProtocolService
+	msgServices api.MessageServiceProvider
+}
+
+func (p *testProvider) AllServices() []dispatcher.ProtocolService {
+	return p.services
+}
+
+func (p *testProvider) MessageServiceProvider() api.MessageServiceProvider {
+	return p.msgServices
+}
+
+// newProvider returns the provider with the trust ping and discover features protocol services
+// and the basic message service.
+func newProvider(t *testing.T, outbound *mockdispatcher.MockOutbound) *testProvider {
+	t.Helper()
+
+	msgServices := msghandler.NewMockMsgServiceProvider()
+
+	basicMsgSvc, err := basic.NewMessageService("basic", func(basic.Message, string, string) error {
+		return nil
+	})
+	require.NoError(t, err)
+	require.NoError(t, msgServices.Register(basicMsgSvc))
+
+	return &testProvider{
+		Provider: &mockprovider.Provider{
+			StorageProviderValue:              mem.NewProvider(),
+			ProtocolStateStorageProviderValue: mem.NewProvider(),
+			OutboundDispatcherValue:           outbound,
+		},
+		// the discover features service discloses itself
+		services:    []dispatcher.ProtocolService{&mocktrustping.MockTrustPingSvc{}, &Service{}},
+		msgServices: msgServices,
+	}
+}
+
+func saveConnection(t *testing.T, svc *Service, myDID, theirDID string) {
+	t.Helper()
+
+	lookup, ok := svc.connectionLookup.(*connection.Lookup)
+	require.True(t, ok)
+
+	recorder := &connection.Recorder{Lookup: lookup}
+
+	require.NoError(t, recorder.SaveConnectionRecord(&connection.Record{
+		ConnectionID: connID,
+		State:        connection.StateNameCompleted,
+		MyDID:        myDID,
+		TheirDID:     theirDID,
+	}))
+}
+
+func ids(features []*Feature) []string {
+	var result []string
+
+	for _, f := range features {
+		result = append(result, f.ID)
+	}
+
+	return result
+}
+
+// customMsgService is the message service registered by the user.
+type customMsgService struct{}
+
+func (m *customMsgService) HandleInbound(service.DIDCommMsg, string, string) (string, error) {
+	return "", nil
+}
+
+func (m *customMsgService) Accept(msgType string, purpose []string) bool {
+	return msgType == customProtocol+"/message" && len(purpose) > 0
+}
+
+func (m *customMsgService) Name() string {
+	return "custom"
+}
+
+func (m *customMsgService) MessageTypes() []string {
+	return []string{customProtocol + "/message"}
+}
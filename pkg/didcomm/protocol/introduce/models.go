@@ -69,15 +69,16 @@ type PleaseIntroduceTo struct {
 }
 
 // Request is not part of any state machine, it can be sent at any time,
-// and when it is received, the recipient can choose whether or not to honor it in their own way
-// TODO: need to clarify about decorator ~please_ack and problem_report
-// 		 should Request contain those fields? What type it should be for each field?
+// and when it is received, the recipient can choose whether or not to honor it in their own way.
+// The request is acknowledged with the generic ack if it has the ~please_ack decorator, on receipt
+// or once the proposals are sent to the introducees.
 type Request struct {
-	Type              string             `json:"@type,omitempty"`
-	ID                string             `json:"@id,omitempty"`
-	PleaseIntroduceTo *PleaseIntroduceTo `json:"please_introduce_to,omitempty"`
-	NWise             bool               `json:"nwise,omitempty"`
-	Timing            *decorator.Timing  `json:"~timing,omitempty"`
+	Type              string               `json:"@type,omitempty"`
+	ID                string               `json:"@id,omitempty"`
+	PleaseIntroduceTo *PleaseIntroduceTo   `json:"please_introduce_to,omitempty"`
+	NWise             bool                 `json:"nwise,omitempty"`
+	Timing            *decorator.Timing    `json:"~timing,omitempty"`
+	PleaseAck         *decorator.PleaseAck `json:"~please_ack,omitempty"`
 }
 
 // Response message that introducee usually sends in response to an introduction proposal.
@@ -16,6 +16,7 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/hyperledger/aries-framework-go/pkg/common/log"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/expiry"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/model"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
@@ -109,6 +110,7 @@ type Service struct {
 	callbacks chan *metaData
 	oobEvent  chan service.StateMsg
 	messenger service.Messenger
+	reaper    *expiry.Reaper
 }
 
 // Provider contains dependencies for the DID exchange protocol and is typically created by using aries.Context().
@@ -118,8 +120,26 @@ type Provider interface {
 	Service(id string) (interface{}, error)
 }
 
+// ServiceOption configures the introduce service.
+type ServiceOption func(opts *serviceOptions)
+
+type serviceOptions struct {
+	expiry     bool
+	expiryOpts []expiry.Option
+}
+
+// WithExpiry enables the reaper which abandons the protocol instances that have expired
+// either because the ~timing.expires_time of their last message has passed or because they stayed idle
+// longer than the timeout provided with the options.
+func WithExpiry(opts ...expiry.Option) ServiceOption {
+	return func(o *serviceOptions) {
+		o.expiry = true
+		o.expiryOpts = opts
+	}
+}
+
 // New returns introduce service.
-func New(p Provider) (*Service, error) {
+func New(p Provider, opts ...ServiceOption) (*Service, error) {
 	store, err := p.StorageProvider().OpenStore(Introduce)
 	if err != nil {
 		return nil, err
@@ -152,6 +172,21 @@ func New(p Provider) (*Service, error) {
 		return nil, fmt.Errorf("oob register msg event: %w", err)
 	}
 
+	svcOpts := &serviceOptions{}
+
+	for _, opt := range opts {
+		opt(svcOpts)
+	}
+
+	if svcOpts.expiry {
+		svc.reaper, err = expiry.NewReaper(p.StorageProvider(), Introduce, svc.abandonExpired, svcOpts.expiryOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("new reaper: %w", err)
+		}
+
+		svc.reaper.Start()
+	}
+
 	// start the listener
 	go svc.startInternalListener()
 
@@ -225,6 +260,12 @@ func (s *Service) doHandle(msg service.DIDCommMsg, outbound bool) (*metaData, er
 		return nil, fmt.Errorf("piID: %w", err)
 	}
 
+	if s.reaper != nil && !outbound {
+		if err = s.reaper.Check(piID); err != nil {
+			return nil, err
+		}
+	}
+
 	stateName, err := s.currentStateName(piID)
 	if err != nil {
 		return nil, fmt.Errorf("currentStateName: %w", err)
@@ -303,6 +344,10 @@ func (s *Service) HandleInbound(msg service.DIDCommMsg, myDID, theirDID string)
 			return "", fmt.Errorf("save transitional payload: %w", err)
 		}
 
+		if err = s.trackInstance(md); err != nil {
+			return "", fmt.Errorf("track instance: %w", err)
+		}
+
 		aEvent <- s.newDIDCommActionMsg(md)
 
 		return md.PIID, nil
@@ -650,6 +695,10 @@ func (s *Service) handle(md *metaData) error {
 		return fmt.Errorf("failed to persist state %s: %w", stateName, err)
 	}
 
+	if err := s.trackInstance(md); err != nil {
+		return fmt.Errorf("track instance: %w", err)
+	}
+
 	for _, action := range actions {
 		if err := action(); err != nil {
 			return err
@@ -659,6 +708,60 @@ func (s *Service) handle(md *metaData) error {
 	return nil
 }
 
+// trackInstance keeps the reaper informed about the activity of the protocol instance.
+func (s *Service) trackInstance(md *metaData) error {
+	if s.reaper == nil {
+		return nil
+	}
+
+	if md.state.Name() == stateNameDone {
+		return s.reaper.Remove(md.PIID)
+	}
+
+	return s.reaper.Touch(md.PIID, md.Msg, md.MyDID, md.TheirDID)
+}
+
+// abandonExpired abandons the protocol instance and notifies the participants with the problem report.
+func (s *Service) abandonExpired(instance *expiry.Instance) error {
+	err := s.deleteTransitionalPayload(instance.PIID)
+	if err != nil && !errors.Is(err, storage.ErrDataNotFound) {
+		return fmt.Errorf("delete transitional payload: %w", err)
+	}
+
+	participants, err := s.getParticipants(instance.PIID)
+	if err != nil {
+		return fmt.Errorf("get participants: %w", err)
+	}
+
+	// no one responded yet, notifies the agent the last message was exchanged with
+	if len(participants) == 0 {
+		thID, errThread := instance.Msg.ThreadID()
+		if errThread != nil {
+			return fmt.Errorf("threadID: %w", errThread)
+		}
+
+		participants = []*participant{{MyDID: instance.MyDID, TheirDID: instance.TheirDID, ThreadID: thID}}
+	}
+
+	return s.handle(&metaData{
+		transitionalPayload: transitionalPayload{
+			StateName: stateNameAbandoning,
+			Action: Action{
+				PIID:     instance.PIID,
+				Msg:      instance.Msg,
+				MyDID:    instance.MyDID,
+				TheirDID: instance.TheirDID,
+			},
+		},
+		state:        &abandoning{Code: expiry.ProblemCode},
+		msgClone:     instance.Msg.Clone(),
+		participants: participants,
+		inbound:      true,
+		saveMetadata: s.saveMetadata,
+		err:          expiry.ErrExpired,
+	})
+}
+
 func contextOOBMessage(msg service.DIDCommMsg) map[string]interface{} {
 	var oobMsg map[string]interface{}
 
@@ -851,6 +954,15 @@ func (s *Service) Name() string {
 	return Introduce
 }
 
+// Close stops the reaper of the expired protocol instances, if the expiry is enabled.
+func (s *Service) Close() error {
+	if s.reaper != nil {
+		s.reaper.Stop()
+	}
+
+	return nil
+}
+
 // Accept msg checks the msg type.
 func (s *Service) Accept(msgType string) bool {
 	switch msgType {
@@ -860,3 +972,10 @@ func (s *Service) Accept(msgType string) bool {
 
 	return false
 }
+
+// MessageTypes returns the message types handled by the service.
+func (s *Service) MessageTypes() []string {
+	return []string{
+		ProposalMsgType, RequestMsgType, ResponseMsgType, AckMsgType, ProblemReportMsgType,
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/expiry"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/model"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/messenger"
@@ -1858,3 +1859,110 @@ func TestOOBMessageReceived(t *testing.T) {
 		require.True(t, ignored)
 	})
 }
+
+func TestService_Expiry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	oobService := serviceMocks.NewMockEvent(ctrl)
+	oobService.EXPECT().RegisterMsgEvent(gomock.Any()).Return(nil).AnyTimes()
+
+	messengerMock := serviceMocks.NewMockMessenger(ctrl)
+
+	provider := introduceMocks.NewMockProvider(ctrl)
+	provider.EXPECT().StorageProvider().Return(mem.NewProvider()).AnyTimes()
+	provider.EXPECT().Service(outofband.Name).Return(oobService, nil).AnyTimes()
+	provider.EXPECT().Messenger().Return(messengerMock).AnyTimes()
+
+	t.Run("Abandons expired proposal", func(t *testing.T) {
+		svc, err := introduce.New(provider, introduce.WithExpiry(expiry.WithInterval(10*time.Millisecond)))
+		require.NoError(t, err)
+
+		defer func() {
+			// the reaper is stopped and closing again is a no-op
+			require.NoError(t, svc.Close())
+			require.NoError(t, svc.Close())
+		}()
+
+		events := make(chan service.StateMsg, 10)
+		require.NoError(t, svc.RegisterMsgEvent(events))
+
+		proposal := service.NewDIDCommMsgMap(introduce.Proposal{
+			ID:     "proposal-id",
+			Type:   introduce.ProposalMsgType,
+			To:     &introduce.To{Name: Carol},
+			Timing: &decorator.Timing{ExpiresTime: time.Now().Add(20 * time.Millisecond)},
+		})
+
+		messengerMock.EXPECT().Send(gomock.Any(), Alice, Bob).Return(nil)
+
+		done := make(chan struct{})
+
+		messengerMock.EXPECT().ReplyToNested(gomock.Any(), gomock.Any()).
+			Do(func(msg service.DIDCommMsgMap, opts *service.NestedReplyOpts) error {
+				defer close(done)
+
+				r := &model.ProblemReport{}
+				require.NoError(t, msg.Decode(r))
+				require.Equal(t, expiry.ProblemCode, r.Description.Code)
+				require.Equal(t, "proposal-id", opts.ThreadID)
+				require.Equal(t, Alice, opts.MyDID)
+				require.Equal(t, Bob, opts.TheirDID)
+
+				return nil
+			})
+
+		piid, err := svc.HandleOutbound(proposal, Alice, Bob)
+		require.NoError(t, err)
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("timeout")
+		}
+
+		for {
+			select {
+			case event := <-events:
+				if event.StateID != "abandoning" || event.Type != service.PostState {
+					continue
+				}
+
+				props, ok := event.Properties.(props)
+				require.True(t, ok)
+				require.Equal(t, piid, props.PIID())
+				require.True(t, errors.Is(event.Properties.All()["error"].(error), expiry.ErrExpired))
+
+				return
+			case <-time.After(time.Second):
+				t.Fatal("timeout")
+			}
+		}
+	})
+
+	t.Run("Rejects the response to expired proposal", func(t *testing.T) {
+		svc, err := introduce.New(provider, introduce.WithExpiry(expiry.WithInterval(time.Hour)))
+		require.NoError(t, err)
+
+		require.NoError(t, svc.RegisterActionEvent(make(chan<- service.DIDCommAction)))
+
+		proposal := service.NewDIDCommMsgMap(introduce.Proposal{
+			ID:     "expired-proposal-id",
+			Type:   introduce.ProposalMsgType,
+			To:     &introduce.To{Name: Carol},
+			Timing: &decorator.Timing{ExpiresTime: time.Now().Add(-time.Second)},
+		})
+
+		messengerMock.EXPECT().Send(gomock.Any(), Alice, Bob).Return(nil)
+
+		_, err = svc.HandleOutbound(proposal, Alice, Bob)
+		require.NoError(t, err)
+
+		_, err = svc.HandleInbound(service.NewDIDCommMsgMap(introduce.Response{
+			ID:     "response-id",
+			Type:   introduce.ResponseMsgType,
+			Thread: &decorator.Thread{ID: "expired-proposal-id"},
+		}), Alice, Bob)
+		require.True(t, errors.Is(err, expiry.ErrExpired))
+	})
+}
@@ -230,10 +230,36 @@ func sendProposals(messenger service.Messenger, md *metaData) error {
 	return nil
 }
 
+// ackRequestOutcome acknowledges the request once the proposals are sent if the request asks for it
+// with the ~please_ack decorator. The declined request is reported with the problem-report instead.
+func ackRequestOutcome(messenger service.Messenger, md *metaData) error {
+	request := Request{}
+
+	if err := md.Msg.Decode(&request); err != nil {
+		return fmt.Errorf("decode request: %w", err)
+	}
+
+	if !request.PleaseAck.OnOutcome() {
+		return nil
+	}
+
+	ack := service.NewDIDCommMsgMap(model.Ack{Type: model.AckMsgType, Status: model.AckStatusOK})
+
+	if err := messenger.ReplyToMsg(md.Msg, ack, md.MyDID, md.TheirDID); err != nil {
+		return fmt.Errorf("send ack: %w", err)
+	}
+
+	return nil
+}
+
 func (s *arranging) ExecuteInbound(messenger service.Messenger, md *metaData) (state, stateAction, error) {
 	if md.Msg.Type() == RequestMsgType {
 		return &noOp{}, func() error {
-			return sendProposals(messenger, md)
+			if err := sendProposals(messenger, md); err != nil {
+				return err
+			}
+
+			return ackRequestOutcome(messenger, md)
 		}, nil
 	}
 
@@ -15,6 +15,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/model"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
 	serviceMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/didcomm/common/service"
@@ -164,6 +165,76 @@ func TestArranging_ExecuteOutbound(t *testing.T) {
 	require.Equal(t, &noOp{}, followup)
 }
 
+func TestArranging_ExecuteInbound_Request(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	newRequest := func(pleaseAck *decorator.PleaseAck) service.DIDCommMsgMap {
+		msg := service.NewDIDCommMsgMap(Request{
+			ID:        uuid.New().String(),
+			Type:      RequestMsgType,
+			PleaseAck: pleaseAck,
+		})
+		msg.Metadata()[metaRecipients] = []interface{}{&Recipient{MyDID: "my_did", TheirDID: "their_did"}}
+
+		return msg
+	}
+
+	t.Run("acknowledges outcome of the request", func(t *testing.T) {
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+		messenger.EXPECT().Send(gomock.Any(), "my_did", "their_did").Return(nil)
+		messenger.EXPECT().ReplyToMsg(gomock.Any(), gomock.Any(), "my_did", "their_did").
+			DoAndReturn(func(_, ack service.DIDCommMsgMap, _, _ string) error {
+				require.Equal(t, model.AckMsgType, ack.Type())
+				require.Equal(t, model.AckStatusOK, ack["status"])
+
+				return nil
+			})
+
+		followup, action, err := (&arranging{}).ExecuteInbound(messenger, &metaData{
+			transitionalPayload: transitionalPayload{Action: Action{
+				Msg:      newRequest(&decorator.PleaseAck{On: []string{decorator.PleaseAckOnOutcome}}),
+				MyDID:    "my_did",
+				TheirDID: "their_did",
+			}},
+			saveMetadata: func(_ service.DIDCommMsgMap, _ string) error { return nil },
+		})
+		require.NoError(t, err)
+		require.NoError(t, action())
+		require.Equal(t, &noOp{}, followup)
+	})
+
+	t.Run("outcome is not acknowledged if not asked", func(t *testing.T) {
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+		messenger.EXPECT().Send(gomock.Any(), "my_did", "their_did").Return(nil).Times(2)
+
+		for _, pleaseAck := range []*decorator.PleaseAck{nil, {On: []string{decorator.PleaseAckOnReceipt}}} {
+			_, action, err := (&arranging{}).ExecuteInbound(messenger, &metaData{
+				transitionalPayload: transitionalPayload{Action: Action{Msg: newRequest(pleaseAck)}},
+				saveMetadata:        func(_ service.DIDCommMsgMap, _ string) error { return nil },
+			})
+			require.NoError(t, err)
+			require.NoError(t, action())
+		}
+	})
+
+	t.Run("send ack error", func(t *testing.T) {
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+		messenger.EXPECT().Send(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+		messenger.EXPECT().ReplyToMsg(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(errors.New("reply error"))
+
+		_, action, err := (&arranging{}).ExecuteInbound(messenger, &metaData{
+			transitionalPayload: transitionalPayload{
+				Action: Action{Msg: newRequest(&decorator.PleaseAck{On: []string{decorator.PleaseAckOnOutcome}})},
+			},
+			saveMetadata: func(_ service.DIDCommMsgMap, _ string) error { return nil },
+		})
+		require.NoError(t, err)
+		require.EqualError(t, action(), "send ack: reply error")
+	})
+}
+
 func TestDelivering_CanTransitionTo(t *testing.T) {
 	st := &delivering{}
 	require.Equal(t, stateNameDelivering, st.Name())
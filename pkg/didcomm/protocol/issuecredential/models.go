@@ -26,6 +26,8 @@ type ProposeCredential struct {
 	// FiltersAttach is an array of attachments that further define the credential being proposed.
 	// This might be used to clarify which formats or format versions are wanted.
 	FiltersAttach []decorator.Attachment `json:"filters~attach,omitempty"`
+	// Timing is an optional ~timing decorator, the message is not accepted after its expires_time.
+	Timing *decorator.Timing `json:"~timing,omitempty"`
 }
 
 // Format contains the the value of the attachment @id and the verifiable credential format of the attachment.
@@ -36,7 +38,7 @@ type Format struct {
 
 // OfferCredential is a message sent by the Issuer to the potential Holder,
 // describing the credential they intend to offer and possibly the price they expect to be paid.
-// TODO: Need to add ~payment_request and ~timing.expires_time decorators [Issue #1297].
+// TODO: Need to add ~payment_request decorator [Issue #1297].
 type OfferCredential struct {
 	Type string `json:"@type,omitempty"`
 	// Comment is an optional field that provides human readable information about this Credential Offer,
@@ -51,6 +53,8 @@ type OfferCredential struct {
 	// OffersAttach is a slice of attachments that further define the credential being offered.
 	// This might be used to clarify which formats or format versions will be issued.
 	OffersAttach []decorator.Attachment `json:"offers~attach,omitempty"`
+	// Timing is an optional ~timing decorator, the message is not accepted after its expires_time.
+	Timing *decorator.Timing `json:"~timing,omitempty"`
 }
 
 // RequestCredential is a message sent by the potential Holder to the Issuer,
@@ -70,11 +74,12 @@ type RequestCredential struct {
 	Formats []Format `json:"formats,omitempty"`
 	// RequestsAttach is a slice of attachments defining the requested formats for the credential
 	RequestsAttach []decorator.Attachment `json:"requests~attach,omitempty"`
+	// Timing is an optional ~timing decorator, the message is not accepted after its expires_time.
+	Timing *decorator.Timing `json:"~timing,omitempty"`
 }
 
 // IssueCredential contains as attached payload the credentials being issued and is
 // sent in response to a valid Request Credential message.
-// TODO: Need to add ~please-ack decorator [Issue #1299].
 type IssueCredential struct {
 	Type string `json:"@type,omitempty"`
 	// Comment is an optional field that provides human readable information about this Credential Offer,
@@ -86,6 +91,10 @@ type IssueCredential struct {
 	Formats []Format `json:"formats,omitempty"`
 	// CredentialsAttach is a slice of attachments containing the issued credentials.
 	CredentialsAttach []decorator.Attachment `json:"credentials~attach,omitempty"`
+	// Timing is an optional ~timing decorator, the message is not accepted after its expires_time.
+	Timing *decorator.Timing `json:"~timing,omitempty"`
+	// PleaseAck is an optional ~please_ack decorator, the holder always acknowledges the issued credential.
+	PleaseAck *decorator.PleaseAck `json:"~please_ack,omitempty"`
 }
 
 // PreviewCredential is used to construct a preview of the data for the credential that is to be issued.
@@ -14,6 +14,7 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/hyperledger/aries-framework-go/pkg/common/log"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/expiry"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	"github.com/hyperledger/aries-framework-go/spi/storage"
 )
@@ -172,6 +173,37 @@ type Provider interface {
 	StorageProvider() storage.Provider
 }
 
+// ServiceOption configures the issuecredential service.
+type ServiceOption func(opts *serviceOptions)
+
+type serviceOptions struct {
+	expiry     bool
+	expiryOpts []expiry.Option
+	autoAccept AutoAccept
+}
+
+// AutoAccept decides on the inbound messages which raise action events, it returns the option the protocol
+// continues with and true if the message is accepted without raising the action event.
+type AutoAccept func(msg service.DIDCommMsg, myDID, theirDID string) (Opt, bool)
+
+// WithAutoAccept continues the protocol instances whose inbound messages are accepted by the given function,
+// the action event is raised only for the other messages.
+func WithAutoAccept(accept AutoAccept) ServiceOption {
+	return func(o *serviceOptions) {
+		o.autoAccept = accept
+	}
+}
+
+// WithExpiry enables the reaper which abandons the protocol instances that have expired
+// either because the ~timing.expires_time of their last message has passed or because they stayed idle
+// longer than the timeout provided with the options.
+func WithExpiry(opts ...expiry.Option) ServiceOption {
+	return func(o *serviceOptions) {
+		o.expiry = true
+		o.expiryOpts = opts
+	}
+}
+
 // Service for the issuecredential protocol.
 type Service struct {
 	service.Action
@@ -180,10 +212,12 @@ type Service struct {
 	callbacks  chan *metaData
 	messenger  service.Messenger
 	middleware Handler
+	reaper     *expiry.Reaper
+	autoAccept AutoAccept
 }
 
 // New returns the issuecredential service.
-func New(p Provider) (*Service, error) {
+func New(p Provider, opts ...ServiceOption) (*Service, error) {
 	store, err := p.StorageProvider().OpenStore(Name)
 	if err != nil {
 		return nil, err
@@ -201,6 +235,23 @@ func New(p Provider) (*Service, error) {
 		middleware: initialHandler,
 	}
 
+	svcOpts := &serviceOptions{}
+
+	for _, opt := range opts {
+		opt(svcOpts)
+	}
+
+	svc.autoAccept = svcOpts.autoAccept
+
+	if svcOpts.expiry {
+		svc.reaper, err = expiry.NewReaper(p.StorageProvider(), Name, svc.abandonExpired, svcOpts.expiryOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("new reaper: %w", err)
+		}
+
+		svc.reaper.Start()
+	}
+
 	// start the listener
 	go svc.startInternalListener()
 
@@ -226,6 +277,10 @@ func (s *Service) HandleInbound(msg service.DIDCommMsg, myDID, theirDID string)
 		return "", errors.New("no clients are registered to handle the message")
 	}
 
+	if err := s.checkExpiry(msg); err != nil {
+		return "", err
+	}
+
 	md, err := s.doHandle(msg, false)
 	if err != nil {
 		return "", fmt.Errorf("doHandle: %w", err)
@@ -243,6 +298,24 @@ func (s *Service) HandleInbound(msg service.DIDCommMsg, myDID, theirDID string)
 			return "", fmt.Errorf("save transitional payload: %w", err)
 		}
 
+		if err = s.trackInstance(md); err != nil {
+			return "", fmt.Errorf("track instance: %w", err)
+		}
+
+		if opt, ok := s.accept(msg, myDID, theirDID); ok {
+			if opt != nil {
+				opt(md)
+			}
+
+			if err = s.deleteTransitionalPayload(md.PIID); err != nil {
+				return "", fmt.Errorf("delete transitional payload: %w", err)
+			}
+
+			s.processCallback(md)
+
+			return "", nil
+		}
+
 		aEvent <- s.newDIDCommActionMsg(md)
 
 		return "", nil
@@ -256,6 +329,16 @@ func (s *Service) HandleInbound(msg service.DIDCommMsg, myDID, theirDID string)
 	return msg.ThreadID()
 }
 
+// accept returns the option the protocol continues with if the inbound message is accepted without
+// raising the action event.
+func (s *Service) accept(msg service.DIDCommMsg, myDID, theirDID string) (Opt, bool) {
+	if s.autoAccept == nil {
+		return nil, false
+	}
+
+	return s.autoAccept(msg, myDID, theirDID)
+}
+
 // HandleOutbound handles outbound message (issuecredential protocol).
 func (s *Service) HandleOutbound(msg service.DIDCommMsg, myDID, theirDID string) (string, error) {
 	md, err := s.doHandle(msg, true)
@@ -380,6 +463,10 @@ func (s *Service) handle(md *metaData) error {
 		return fmt.Errorf("failed to persist state %s: %w", stateName, err)
 	}
 
+	if err := s.trackInstance(md); err != nil {
+		return fmt.Errorf("track instance: %w", err)
+	}
+
 	for _, action := range actions {
 		if err := action(s.messenger); err != nil {
 			return fmt.Errorf("action %s: %w", stateName, err)
@@ -389,6 +476,63 @@ func (s *Service) handle(md *metaData) error {
 	return nil
 }
 
+// checkExpiry rejects the message if the protocol instance it belongs to has expired.
+func (s *Service) checkExpiry(msg service.DIDCommMsg) error {
+	if s.reaper == nil {
+		return nil
+	}
+
+	piID, err := getPIID(msg)
+	if errors.Is(err, service.ErrThreadIDNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("piID: %w", err)
+	}
+
+	return s.reaper.Check(piID)
+}
+
+// trackInstance keeps the reaper informed about the activity of the protocol instance.
+func (s *Service) trackInstance(md *metaData) error {
+	if s.reaper == nil {
+		return nil
+	}
+
+	if md.state.Name() == stateNameDone {
+		return s.reaper.Remove(md.PIID)
+	}
+
+	return s.reaper.Touch(md.PIID, md.Msg, md.MyDID, md.TheirDID)
+}
+
+// abandonExpired abandons the protocol instance and notifies another agent with the problem report.
+func (s *Service) abandonExpired(instance *expiry.Instance) error {
+	err := s.deleteTransitionalPayload(instance.PIID)
+	if err != nil && !errors.Is(err, storage.ErrDataNotFound) {
+		return fmt.Errorf("delete transitional payload: %w", err)
+	}
+
+	s.processCallback(&metaData{
+		transitionalPayload: transitionalPayload{
+			StateName: stateNameAbandoning,
+			Action: Action{
+				PIID:     instance.PIID,
+				Msg:      instance.Msg,
+				MyDID:    instance.MyDID,
+				TheirDID: instance.TheirDID,
+			},
+		},
+		state:      &abandoning{Code: expiry.ProblemCode},
+		msgClone:   instance.Msg.Clone(),
+		inbound:    true,
+		properties: map[string]interface{}{errorPropKey: expiry.ErrExpired},
+	})
+
+	return nil
+}
+
 func getPIID(msg service.DIDCommMsg) (string, error) {
 	if pthID := msg.ParentThreadID(); pthID != "" {
 		return pthID, nil
@@ -685,6 +829,21 @@ func (s *Service) Name() string {
 	return Name
 }
 
+// Close stops the reaper of the expired protocol instances, if the expiry is enabled.
+func (s *Service) Close() error {
+	if s.reaper != nil {
+		s.reaper.Stop()
+	}
+
+	return nil
+}
+
+// Acknowledges checks whether the ~please_ack decorator of the message type is handled by the protocol,
+// the issued credential is acknowledged by the holder with the ack of the protocol.
+func (s *Service) Acknowledges(msgType string) bool {
+	return msgType == IssueCredentialMsgType
+}
+
 // Accept msg checks the msg type.
 func (s *Service) Accept(msgType string) bool {
 	switch msgType {
@@ -695,3 +854,11 @@ func (s *Service) Accept(msgType string) bool {
 
 	return false
 }
+
+// MessageTypes returns the message types handled by the service.
+func (s *Service) MessageTypes() []string {
+	return []string{
+		ProposeCredentialMsgType, OfferCredentialMsgType, RequestCredentialMsgType, IssueCredentialMsgType,
+		AckMsgType, ProblemReportMsgType,
+	}
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/expiry"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/model"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
@@ -793,6 +794,107 @@ func TestService_HandleInbound(t *testing.T) {
 		}
 	})
 
+	t.Run("Receive Request Credential Auto Accept", func(t *testing.T) {
+		done := make(chan struct{})
+
+		messenger.EXPECT().ReplyToMsg(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Do(func(_, msg service.DIDCommMsgMap, _, _ string) error {
+				defer close(done)
+
+				r := &IssueCredential{}
+				require.NoError(t, msg.Decode(r))
+				require.Equal(t, IssueCredentialMsgType, r.Type)
+
+				return nil
+			})
+
+		store.EXPECT().Get(gomock.Any()).Return(nil, storage.ErrDataNotFound)
+		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+		store.EXPECT().Delete(gomock.Any()).Return(nil)
+		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ string, name []byte) error {
+			require.Equal(t, "credential-issued", string(name))
+
+			return nil
+		})
+
+		svc, err := New(provider, WithAutoAccept(func(msg service.DIDCommMsg, myDID, theirDID string) (Opt, bool) {
+			require.Equal(t, RequestCredentialMsgType, msg.Type())
+			require.Equal(t, Alice, myDID)
+			require.Equal(t, Bob, theirDID)
+
+			return WithIssueCredential(&IssueCredential{}), true
+		}))
+		require.NoError(t, err)
+
+		ch := make(chan service.DIDCommAction, 1)
+		require.NoError(t, svc.RegisterActionEvent(ch))
+
+		msg := service.NewDIDCommMsgMap(RequestCredential{
+			Type: RequestCredentialMsgType,
+		})
+
+		require.NoError(t, msg.SetID(uuid.New().String()))
+
+		_, err = svc.HandleInbound(msg, Alice, Bob)
+		require.NoError(t, err)
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Error("timeout")
+		}
+
+		require.Empty(t, ch)
+	})
+
+	t.Run("Receive Request Credential not Auto Accepted", func(t *testing.T) {
+		store.EXPECT().Get(gomock.Any()).Return(nil, storage.ErrDataNotFound)
+		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+
+		svc, err := New(provider, WithAutoAccept(func(service.DIDCommMsg, string, string) (Opt, bool) {
+			return nil, false
+		}))
+		require.NoError(t, err)
+
+		ch := make(chan service.DIDCommAction, 1)
+		require.NoError(t, svc.RegisterActionEvent(ch))
+
+		msg := service.NewDIDCommMsgMap(RequestCredential{
+			Type: RequestCredentialMsgType,
+		})
+
+		require.NoError(t, msg.SetID(uuid.New().String()))
+
+		_, err = svc.HandleInbound(msg, Alice, Bob)
+		require.NoError(t, err)
+
+		action := <-ch
+		require.Equal(t, RequestCredentialMsgType, action.Message.Type())
+	})
+
+	t.Run("Receive Request Credential Auto Accept delete error", func(t *testing.T) {
+		store.EXPECT().Get(gomock.Any()).Return(nil, storage.ErrDataNotFound)
+		store.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+		store.EXPECT().Delete(gomock.Any()).Return(errors.New(errMsg))
+
+		svc, err := New(provider, WithAutoAccept(func(service.DIDCommMsg, string, string) (Opt, bool) {
+			return nil, true
+		}))
+		require.NoError(t, err)
+
+		require.NoError(t, svc.RegisterActionEvent(make(chan service.DIDCommAction)))
+
+		msg := service.NewDIDCommMsgMap(RequestCredential{
+			Type: RequestCredentialMsgType,
+		})
+
+		require.NoError(t, msg.SetID(uuid.New().String()))
+
+		_, err = svc.HandleInbound(msg, Alice, Bob)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "delete transitional payload")
+	})
+
 	t.Run("Receive Problem Report (continue)", func(t *testing.T) {
 		done := make(chan struct{})
 
@@ -1475,6 +1577,12 @@ func TestService_Accept(t *testing.T) {
 	require.False(t, (*Service).Accept(nil, "unknown"))
 }
 
+func TestService_Acknowledges(t *testing.T) {
+	require.True(t, (*Service).Acknowledges(nil, IssueCredentialMsgType))
+	require.False(t, (*Service).Acknowledges(nil, RequestCredentialMsgType))
+	require.False(t, (*Service).Acknowledges(nil, "unknown"))
+}
+
 func TestService_canTriggerActionEvents(t *testing.T) {
 	require.True(t, canTriggerActionEvents(service.NewDIDCommMsgMap(ProposeCredential{
 		Type: ProposeCredentialMsgType,
@@ -1494,3 +1602,114 @@ func TestService_canTriggerActionEvents(t *testing.T) {
 
 	require.False(t, canTriggerActionEvents(service.NewDIDCommMsgMap(struct{}{})))
 }
+
+func TestService_Expiry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	messenger := serviceMocks.NewMockMessenger(ctrl)
+
+	provider := issuecredentialMocks.NewMockProvider(ctrl)
+	provider.EXPECT().Messenger().Return(messenger).AnyTimes()
+	provider.EXPECT().StorageProvider().Return(mem.NewProvider()).AnyTimes()
+
+	t.Run("Abandons expired offer", func(t *testing.T) {
+		svc, err := New(provider, WithExpiry(expiry.WithInterval(10*time.Millisecond)))
+		require.NoError(t, err)
+
+		defer func() {
+			// the reaper is stopped and closing again is a no-op
+			require.NoError(t, svc.Close())
+			require.NoError(t, svc.Close())
+		}()
+
+		events := make(chan service.StateMsg, 10)
+		require.NoError(t, svc.RegisterMsgEvent(events))
+
+		msg := service.NewDIDCommMsgMap(OfferCredential{
+			Type:   OfferCredentialMsgType,
+			Timing: &decorator.Timing{ExpiresTime: time.Now().Add(20 * time.Millisecond)},
+		})
+
+		messenger.EXPECT().Send(msg, Alice, Bob).Return(nil)
+
+		done := make(chan struct{})
+
+		messenger.EXPECT().ReplyToNested(gomock.Any(), gomock.Any()).
+			Do(func(msg service.DIDCommMsgMap, opts *service.NestedReplyOpts) error {
+				defer close(done)
+
+				r := &model.ProblemReport{}
+				require.NoError(t, msg.Decode(r))
+				require.Equal(t, expiry.ProblemCode, r.Description.Code)
+				require.Equal(t, Alice, opts.MyDID)
+				require.Equal(t, Bob, opts.TheirDID)
+
+				return nil
+			})
+
+		piid, err := svc.HandleOutbound(msg, Alice, Bob)
+		require.NoError(t, err)
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("timeout")
+		}
+
+		for {
+			select {
+			case event := <-events:
+				if event.StateID != stateNameAbandoning || event.Type != service.PostState {
+					continue
+				}
+
+				require.Equal(t, piid, event.Properties.All()[piidPropKey])
+				require.Equal(t, expiry.ErrExpired, event.Properties.All()[errorPropKey])
+
+				return
+			case <-time.After(time.Second):
+				t.Fatal("timeout")
+			}
+		}
+	})
+
+	t.Run("Rejects the reply to expired offer", func(t *testing.T) {
+		svc, err := New(provider, WithExpiry(expiry.WithInterval(time.Hour)))
+		require.NoError(t, err)
+
+		require.NoError(t, svc.RegisterActionEvent(make(chan<- service.DIDCommAction)))
+
+		msg := service.NewDIDCommMsgMap(OfferCredential{
+			Type:   OfferCredentialMsgType,
+			Timing: &decorator.Timing{ExpiresTime: time.Now().Add(-time.Second)},
+		})
+
+		messenger.EXPECT().Send(msg, Alice, Bob).Return(nil)
+
+		piid, err := svc.HandleOutbound(msg, Alice, Bob)
+		require.NoError(t, err)
+
+		reply := service.NewDIDCommMsgMap(RequestCredential{Type: RequestCredentialMsgType})
+		require.NoError(t, reply.SetID(uuid.New().String()))
+		reply["~thread"] = map[string]interface{}{"thid": piid}
+
+		_, err = svc.HandleInbound(reply, Alice, Bob)
+		require.True(t, errors.Is(err, expiry.ErrExpired))
+	})
+
+	t.Run("Reaper error", func(t *testing.T) {
+		storeProvider := storageMocks.NewMockProvider(ctrl)
+		storeProvider.EXPECT().OpenStore(Name).Return(nil, nil)
+		storeProvider.EXPECT().SetStoreConfig(Name, gomock.Any()).Return(nil)
+		storeProvider.EXPECT().OpenStore(gomock.Any()).Return(nil, errors.New("open error"))
+
+		prov := issuecredentialMocks.NewMockProvider(ctrl)
+		prov.EXPECT().Messenger().Return(messenger)
+		prov.EXPECT().StorageProvider().Return(storeProvider).AnyTimes()
+
+		_, err := New(prov, WithExpiry())
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "new reaper: open protocol instances store: open error")
+	})
+}
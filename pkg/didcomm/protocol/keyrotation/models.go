@@ -0,0 +1,37 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package keyrotation
+
+import "time"
+
+// Rotate notifies the counterparty about the updated DID document after one of its DIDComm keys was rotated
+// or retired. The document is signed with a key the counterparty already knows for the DID.
+type Rotate struct {
+	Type         string        `json:"@type,omitempty"`
+	ID           string        `json:"@id,omitempty"`
+	DID          string        `json:"did,omitempty"`
+	DocSignature *DocSignature `json:"did_doc~sig,omitempty"`
+}
+
+// DocSignature holds the signed DID document.
+type DocSignature struct {
+	Type       string `json:"@type,omitempty"`
+	Signature  string `json:"signature,omitempty"`
+	SignedData string `json:"sig_data,omitempty"`
+	SignVerKey string `json:"signer,omitempty"`
+}
+
+// rotation is a pending rotation of the key. It is saved before the mediators and the DID documents are updated,
+// so an interrupted rotation or retirement is resumed from it. The old key stays in the DID documents until RetireAt.
+type rotation struct {
+	OldKey   string    `json:"old_key,omitempty"`
+	NewKey   string    `json:"new_key,omitempty"`
+	KeyID    string    `json:"key_id,omitempty"`
+	DIDs     []string  `json:"dids,omitempty"`
+	RetireAt time.Time `json:"retire_at,omitempty"`
+	State    string    `json:"state,omitempty"`
+}
@@ -0,0 +1,918 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package keyrotation
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/dispatcher"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/mediator"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/suite"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/suite/ed25519signature2018"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/verifier"
+	vdrapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/localkms"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+	didstore "github.com/hyperledger/aries-framework-go/pkg/store/did"
+	"github.com/hyperledger/aries-framework-go/pkg/vdr/fingerprint"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+)
+
+const (
+	// KeyRotation defines the protocol name.
+	KeyRotation = "keyrotation"
+	// Spec defines the protocol spec.
+	Spec = "https://didcomm.org/key-rotation/1.0/"
+	// RotateMsgType defines the key rotation rotate message type.
+	RotateMsgType = Spec + "rotate"
+)
+
+const (
+	// Namespace is namespace of key rotation store name.
+	Namespace = "keyrotation"
+
+	// DefaultGracePeriod is the time the rotated key stays in the DID documents and the mediator keylist.
+	DefaultGracePeriod = 72 * time.Hour
+
+	signatureType              = "https://didcomm.org/signature/1.0/ed25519Sha512_single"
+	ed25519VerificationKey2018 = "Ed25519VerificationKey2018"
+
+	rotationKeyPrefix = "rotation_"
+	rotationTag       = "rotation"
+
+	// states of the rotation record.
+	stateCreated    = "created"
+	stateRegistered = "registered"
+	stateRotated    = "rotated"
+	stateRetiring   = "retiring"
+
+	// retireRetryInterval is the delay before the next attempt of the scheduled retirement which failed.
+	retireRetryInterval = time.Minute
+)
+
+// ErrRotationNotFound is returned when there is no pending rotation of the key.
+var ErrRotationNotFound = errors.New("key rotation not found")
+
+var errRotationBusy = errors.New("key rotation is being updated")
+
+var logger = log.New("aries-framework/keyrotation")
+
+type provider interface {
+	OutboundDispatcher() dispatcher.Outbound
+	StorageProvider() storage.Provider
+	ProtocolStateStorageProvider() storage.Provider
+	KMS() kms.KeyManager
+	Crypto() crypto.Crypto
+	VDRegistry() vdrapi.Registry
+	Service(id string) (interface{}, error)
+}
+
+// RotateOpt is an option of the key rotation.
+type RotateOpt func(opts *rotateOpts)
+
+type rotateOpts struct {
+	gracePeriod time.Duration
+}
+
+// WithGracePeriod sets the time the old key stays usable after the rotation. DefaultGracePeriod is used by default.
+func WithGracePeriod(gracePeriod time.Duration) RotateOpt {
+	return func(opts *rotateOpts) {
+		opts.gracePeriod = gracePeriod
+	}
+}
+
+// Service rotates DIDComm keys of the agent. Rotation replaces the key in the DID documents of the agent,
+// registers the new key with the mediators and notifies the counterparties with a signed DID document.
+// The old key is retired once the grace period is over.
+//
+// The lock only guards the rotation records and the timers, the mediators and the counterparties are called
+// without it. The key being rotated or retired is marked busy meanwhile.
+type Service struct {
+	outbound     dispatcher.Outbound
+	kms          kms.KeyManager
+	crypto       crypto.Crypto
+	vdRegistry   vdrapi.Registry
+	connections  *connection.Recorder
+	didConnStore *didstore.ConnectionStore
+	routeSvc     mediator.ProtocolService
+	store        storage.Store
+	timers       map[string]*time.Timer
+	busy         map[string]struct{}
+	lock         sync.Mutex
+}
+
+// New returns the key rotation service.
+func New(prov provider) (*Service, error) {
+	store, err := prov.StorageProvider().OpenStore(Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("open key rotation store: %w", err)
+	}
+
+	err = prov.StorageProvider().SetStoreConfig(Namespace, storage.StoreConfiguration{TagNames: []string{rotationTag}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set store configuration: %w", err)
+	}
+
+	connRecorder, err := connection.NewRecorder(prov)
+	if err != nil {
+		return nil, fmt.Errorf("new connection recorder: %w", err)
+	}
+
+	didConnStore, err := didstore.NewConnectionStore(prov)
+	if err != nil {
+		return nil, fmt.Errorf("new did connection store: %w", err)
+	}
+
+	s, err := prov.Service(mediator.Coordination)
+	if err != nil {
+		return nil, err
+	}
+
+	routeSvc, ok := s.(mediator.ProtocolService)
+	if !ok {
+		return nil, errors.New("cast service to Route Service failed")
+	}
+
+	svc := &Service{
+		outbound:     prov.OutboundDispatcher(),
+		kms:          prov.KMS(),
+		crypto:       prov.Crypto(),
+		vdRegistry:   prov.VDRegistry(),
+		connections:  connRecorder,
+		didConnStore: didConnStore,
+		routeSvc:     routeSvc,
+		store:        store,
+		timers:       make(map[string]*time.Timer),
+		busy:         make(map[string]struct{}),
+	}
+
+	// rotations started before the restart are retired on schedule
+	if err = svc.scheduleRetirements(); err != nil {
+		return nil, fmt.Errorf("schedule key retirements: %w", err)
+	}
+
+	return svc, nil
+}
+
+// HandleInbound handles inbound key rotation messages.
+func (s *Service) HandleInbound(msg service.DIDCommMsg, myDID, theirDID string) (string, error) {
+	if msg.Type() != RotateMsgType {
+		return "", fmt.Errorf("unsupported message type %s", msg.Type())
+	}
+
+	if err := s.handleRotate(msg, myDID, theirDID); err != nil {
+		return "", fmt.Errorf("handle rotate: %w", err)
+	}
+
+	return msg.ID(), nil
+}
+
+// HandleOutbound adherence to dispatcher.ProtocolService.
+func (s *Service) HandleOutbound(_ service.DIDCommMsg, _, _ string) (string, error) {
+	return "", errors.New("not implemented")
+}
+
+// Accept checks whether the service can handle the message type.
+func (s *Service) Accept(msgType string) bool {
+	return msgType == RotateMsgType
+}
+
+// MessageTypes returns the message types handled by the service.
+func (s *Service) MessageTypes() []string {
+	return []string{RotateMsgType}
+}
+
+// Name of the service.
+func (s *Service) Name() string {
+	return KeyRotation
+}
+
+// RotateKey replaces the DIDComm recipient key (did:key) with a new one in every DID document of the agent
+// used by a connection. The new key is registered with the mediators and the counterparties get the updated
+// DID documents signed with the old key. The old key stays usable until the grace period is over.
+// A rotation which failed part way is resumed with the same new key by calling RotateKey again.
+// Returns the new recipient key.
+func (s *Service) RotateKey(oldKey string, options ...RotateOpt) (string, error) {
+	opts := &rotateOpts{gracePeriod: DefaultGracePeriod}
+
+	for _, opt := range options {
+		opt(opts)
+	}
+
+	r, err := s.startRotation(oldKey)
+	if err != nil {
+		return "", err
+	}
+
+	defer s.release(oldKey)
+
+	if r.State == stateCreated {
+		// the new key is registered with the mediators before anyone knows it
+		if err = s.updateRouterKey(r.NewKey, mediator.AddKeyToRouter); err != nil {
+			return "", err
+		}
+
+		if err = s.setState(r, stateRegistered); err != nil {
+			return "", err
+		}
+	}
+
+	docs, err := s.addKeys(r)
+	if err != nil {
+		return "", err
+	}
+
+	r.RetireAt = time.Now().Add(opts.gracePeriod)
+
+	if err = s.setState(r, stateRotated); err != nil {
+		return "", err
+	}
+
+	for _, doc := range docs {
+		s.notify(doc, oldKey)
+	}
+
+	s.lock.Lock()
+	s.scheduleRetirement(r.OldKey, time.Until(r.RetireAt))
+	s.lock.Unlock()
+
+	return r.NewKey, nil
+}
+
+// RetireKey removes the rotated key from the DID documents and the mediator keylist before the grace period
+// is over and notifies the counterparties with the updated DID documents signed with the new key.
+// A retirement which failed part way is resumed by calling RetireKey again.
+func (s *Service) RetireKey(oldKey string) error {
+	r, err := s.startRetirement(oldKey)
+	if err != nil {
+		return err
+	}
+
+	defer s.release(oldKey)
+
+	docs, err := s.removeKeys(r)
+	if err != nil {
+		return err
+	}
+
+	for _, doc := range docs {
+		s.notify(doc, r.NewKey)
+	}
+
+	if err = s.updateRouterKey(oldKey, mediator.RemoveKeyFromRouter); err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if err = s.store.Delete(rotationKeyPrefix + oldKey); err != nil {
+		return fmt.Errorf("delete key rotation: %w", err)
+	}
+
+	// the scheduled retirement stays in place until the key is retired, it retries a failed retirement
+	if timer, ok := s.timers[oldKey]; ok {
+		timer.Stop()
+		delete(s.timers, oldKey)
+	}
+
+	return nil
+}
+
+// startRotation returns the rotation record of the key, a new one is created and saved along with the new key
+// unless the previous rotation of the key was interrupted. The key is marked busy.
+func (s *Service) startRotation(oldKey string) (*rotation, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.busy[oldKey]; ok {
+		return nil, fmt.Errorf("key %s: %w", oldKey, errRotationBusy)
+	}
+
+	r, err := s.getRotation(oldKey)
+	if err == nil && (r.State == stateRotated || r.State == stateRetiring) {
+		return nil, fmt.Errorf("key %s is already being rotated", oldKey)
+	}
+
+	if errors.Is(err, ErrRotationNotFound) {
+		r, err = s.newRotation(oldKey)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.busy[oldKey] = struct{}{}
+
+	return r, nil
+}
+
+func (s *Service) newRotation(oldKey string) (*rotation, error) {
+	if _, err := fingerprint.PubKeyFromDIDKey(oldKey); err != nil {
+		return nil, fmt.Errorf("parse key %s: %w", oldKey, err)
+	}
+
+	docs, err := s.docsWithKey(oldKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(docs) == 0 {
+		return nil, fmt.Errorf("key %s is not used by any DID document of the connections", oldKey)
+	}
+
+	kid, pubKey, err := s.kms.CreateAndExportPubKeyBytes(kms.ED25519Type)
+	if err != nil {
+		return nil, fmt.Errorf("create key: %w", err)
+	}
+
+	newKey, _ := fingerprint.CreateDIDKey(pubKey)
+
+	r := &rotation{
+		OldKey: oldKey,
+		NewKey: newKey,
+		KeyID:  kid,
+		State:  stateCreated,
+	}
+
+	for _, doc := range docs {
+		r.DIDs = append(r.DIDs, doc.ID)
+	}
+
+	if err = s.saveRotation(r); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// startRetirement returns the rotation record of the key marked as being retired. The key is marked busy.
+func (s *Service) startRetirement(oldKey string) (*rotation, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.busy[oldKey]; ok {
+		return nil, fmt.Errorf("key %s: %w", oldKey, errRotationBusy)
+	}
+
+	r, err := s.getRotation(oldKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.State != stateRotated && r.State != stateRetiring {
+		return nil, fmt.Errorf("rotation of key %s is not complete, call RotateKey to resume it", oldKey)
+	}
+
+	r.State = stateRetiring
+
+	if err = s.saveRotation(r); err != nil {
+		return nil, err
+	}
+
+	s.busy[oldKey] = struct{}{}
+
+	return r, nil
+}
+
+func (s *Service) release(oldKey string) {
+	s.lock.Lock()
+	delete(s.busy, oldKey)
+	s.lock.Unlock()
+}
+
+func (s *Service) setState(r *rotation, state string) error {
+	r.State = state
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.saveRotation(r)
+}
+
+// addKeys adds the new key to the DID documents of the rotation, the documents updated before the rotation
+// was interrupted are left as they are. Returns the DID documents.
+func (s *Service) addKeys(r *rotation) ([]*did.Doc, error) {
+	oldPubKey, err := fingerprint.PubKeyFromDIDKey(r.OldKey)
+	if err != nil {
+		return nil, fmt.Errorf("parse key %s: %w", r.OldKey, err)
+	}
+
+	pubKey, err := fingerprint.PubKeyFromDIDKey(r.NewKey)
+	if err != nil {
+		return nil, fmt.Errorf("parse key %s: %w", r.NewKey, err)
+	}
+
+	docs := make([]*did.Doc, 0, len(r.DIDs))
+
+	for _, didID := range r.DIDs {
+		docResolution, err := s.vdRegistry.Resolve(didID)
+		if err != nil {
+			return nil, fmt.Errorf("resolve DID %s: %w", didID, err)
+		}
+
+		doc := docResolution.DIDDocument
+
+		if !hasRecipientKey(doc, r.NewKey) {
+			vm := did.NewVerificationMethodFromBytes("#"+r.KeyID, ed25519VerificationKey2018, doc.ID, pubKey)
+
+			addKey(doc, oldPubKey, vm, r.OldKey, r.NewKey)
+
+			if err = s.updateDoc(doc); err != nil {
+				return nil, err
+			}
+		}
+
+		docs = append(docs, doc)
+	}
+
+	return docs, nil
+}
+
+// removeKeys removes the old key from the DID documents of the rotation, the documents updated before
+// the retirement was interrupted are left as they are. Returns the DID documents.
+func (s *Service) removeKeys(r *rotation) ([]*did.Doc, error) {
+	oldPubKey, err := fingerprint.PubKeyFromDIDKey(r.OldKey)
+	if err != nil {
+		return nil, fmt.Errorf("parse key %s: %w", r.OldKey, err)
+	}
+
+	docs := make([]*did.Doc, 0, len(r.DIDs))
+
+	for _, didID := range r.DIDs {
+		docResolution, err := s.vdRegistry.Resolve(didID)
+		if err != nil {
+			return nil, fmt.Errorf("resolve DID %s: %w", didID, err)
+		}
+
+		doc := docResolution.DIDDocument
+
+		if hasRecipientKey(doc, r.OldKey) {
+			removeKey(doc, oldPubKey, r.OldKey)
+
+			if err = s.updateDoc(doc); err != nil {
+				return nil, err
+			}
+		}
+
+		docs = append(docs, doc)
+	}
+
+	return docs, nil
+}
+
+func (s *Service) handleRotate(msg service.DIDCommMsg, myDID, theirDID string) error {
+	rotate := &Rotate{}
+
+	if err := msg.Decode(rotate); err != nil {
+		return fmt.Errorf("rotate message unmarshal: %w", err)
+	}
+
+	if theirDID == "" || rotate.DID != theirDID {
+		return fmt.Errorf("DID %s of the rotated document does not match the sender DID %s", rotate.DID, theirDID)
+	}
+
+	if rotate.DocSignature == nil {
+		return errors.New("missing DID document signature")
+	}
+
+	docResolution, err := s.vdRegistry.Resolve(theirDID)
+	if err != nil {
+		return fmt.Errorf("resolve DID %s: %w", theirDID, err)
+	}
+
+	current := docResolution.DIDDocument
+
+	// only a key the DID already has can sign the updated document
+	dest, err := service.CreateDestination(current)
+	if err != nil {
+		return err
+	}
+
+	if !contains(dest.RecipientKeys, rotate.DocSignature.SignVerKey) {
+		return fmt.Errorf("signer %s is not a key of DID %s", rotate.DocSignature.SignVerKey, theirDID)
+	}
+
+	doc, err := verifyDocSignature(rotate.DocSignature)
+	if err != nil {
+		return err
+	}
+
+	if doc.ID != theirDID {
+		return fmt.Errorf("signed DID document %s does not match the sender DID %s", doc.ID, theirDID)
+	}
+
+	if current.Updated != nil && (doc.Updated == nil || !doc.Updated.After(*current.Updated)) {
+		return errors.New("signed DID document is not newer than the stored one")
+	}
+
+	if err = s.updateDoc(doc); err != nil {
+		return err
+	}
+
+	return s.updateConnection(myDID, theirDID, doc)
+}
+
+// docsWithKey returns DID documents of the completed connections having the key in the DIDComm service.
+func (s *Service) docsWithKey(key string) ([]*did.Doc, error) {
+	records, err := s.connections.QueryConnectionRecords()
+	if err != nil {
+		return nil, fmt.Errorf("query connection records: %w", err)
+	}
+
+	var docs []*did.Doc
+
+	checked := make(map[string]struct{})
+
+	for _, record := range records {
+		if record.State != connection.StateNameCompleted {
+			continue
+		}
+
+		if _, ok := checked[record.MyDID]; ok {
+			continue
+		}
+
+		checked[record.MyDID] = struct{}{}
+
+		docResolution, err := s.vdRegistry.Resolve(record.MyDID)
+		if err != nil {
+			return nil, fmt.Errorf("resolve DID %s: %w", record.MyDID, err)
+		}
+
+		dest, err := service.CreateDestination(docResolution.DIDDocument)
+		if err != nil {
+			continue
+		}
+
+		if contains(dest.RecipientKeys, key) {
+			docs = append(docs, docResolution.DIDDocument)
+		}
+	}
+
+	return docs, nil
+}
+
+func (s *Service) updateDoc(doc *did.Doc) error {
+	if err := s.vdRegistry.Update(doc); err != nil {
+		return fmt.Errorf("update DID document %s: %w", doc.ID, err)
+	}
+
+	if err := s.didConnStore.SaveDIDFromDoc(doc); err != nil {
+		return fmt.Errorf("save DID %s keys: %w", doc.ID, err)
+	}
+
+	return nil
+}
+
+func (s *Service) updateConnection(myDID, theirDID string, doc *did.Doc) error {
+	connID, err := s.connections.GetConnectionIDByDIDs(myDID, theirDID)
+	if err != nil {
+		return fmt.Errorf("get connection ID: %w", err)
+	}
+
+	record, err := s.connections.GetConnectionRecord(connID)
+	if err != nil {
+		return fmt.Errorf("get connection record: %w", err)
+	}
+
+	dest, err := service.CreateDestination(doc)
+	if err != nil {
+		return err
+	}
+
+	record.RecipientKeys = dest.RecipientKeys
+	record.RoutingKeys = dest.RoutingKeys
+	record.ServiceEndPoint = dest.ServiceEndpoint
+
+	if err = s.connections.SaveConnectionRecord(record); err != nil {
+		return fmt.Errorf("save connection record: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) updateRouterKey(key string, update func(mediator.ProtocolService, string, string) error) error {
+	routerConnections, err := s.routeSvc.GetConnections()
+	if err != nil {
+		return fmt.Errorf("get router connections: %w", err)
+	}
+
+	for _, connID := range routerConnections {
+		if err = update(s.routeSvc, connID, key); err != nil {
+			return fmt.Errorf("update router key: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// notify sends the signed DID document to the counterparties of the DID. The document is already updated
+// at this point, so a counterparty which can't be reached does not fail the rotation.
+func (s *Service) notify(doc *did.Doc, signKey string) {
+	msg, err := s.newRotate(doc, signKey)
+	if err != nil {
+		logger.Errorf("key rotation: create rotate message for %s: %s", doc.ID, err)
+
+		return
+	}
+
+	records, err := s.connections.QueryConnectionRecords()
+	if err != nil {
+		logger.Errorf("key rotation: query connection records: %s", err)
+
+		return
+	}
+
+	for _, record := range records {
+		if record.MyDID != doc.ID || record.State != connection.StateNameCompleted {
+			continue
+		}
+
+		dest, err := service.GetDestination(record.TheirDID, s.vdRegistry)
+		if err != nil {
+			logger.Errorf("key rotation: connection %s: %s", record.ConnectionID, err)
+
+			continue
+		}
+
+		// the sign key is sent as the sender key, so the counterparty finds the DID by the key it already knows
+		if err = s.outbound.Send(msg, signKey, dest); err != nil {
+			logger.Errorf("key rotation: notify connection %s: %s", record.ConnectionID, err)
+		}
+	}
+}
+
+func (s *Service) newRotate(doc *did.Doc, signKey string) (*Rotate, error) {
+	docBytes, err := doc.JSONBytes()
+	if err != nil {
+		return nil, fmt.Errorf("marshal DID document: %w", err)
+	}
+
+	pubKey, err := fingerprint.PubKeyFromDIDKey(signKey)
+	if err != nil {
+		return nil, fmt.Errorf("parse key %s: %w", signKey, err)
+	}
+
+	kid, err := localkms.CreateKID(pubKey, kms.ED25519Type)
+	if err != nil {
+		return nil, fmt.Errorf("create KID: %w", err)
+	}
+
+	kh, err := s.kms.Get(kid)
+	if err != nil {
+		return nil, fmt.Errorf("get key handle: %w", err)
+	}
+
+	signature, err := s.crypto.Sign(docBytes, kh)
+	if err != nil {
+		return nil, fmt.Errorf("sign DID document: %w", err)
+	}
+
+	return &Rotate{
+		Type: RotateMsgType,
+		ID:   uuid.New().String(),
+		DID:  doc.ID,
+		DocSignature: &DocSignature{
+			Type:       signatureType,
+			Signature:  base64.URLEncoding.EncodeToString(signature),
+			SignedData: base64.URLEncoding.EncodeToString(docBytes),
+			SignVerKey: signKey,
+		},
+	}, nil
+}
+
+func (s *Service) getRotation(oldKey string) (*rotation, error) {
+	src, err := s.store.Get(rotationKeyPrefix + oldKey)
+	if errors.Is(err, storage.ErrDataNotFound) {
+		return nil, ErrRotationNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("get key rotation: %w", err)
+	}
+
+	r := &rotation{}
+
+	if err = json.Unmarshal(src, r); err != nil {
+		return nil, fmt.Errorf("unmarshal key rotation: %w", err)
+	}
+
+	return r, nil
+}
+
+func (s *Service) saveRotation(r *rotation) error {
+	src, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("marshal key rotation: %w", err)
+	}
+
+	if err = s.store.Put(rotationKeyPrefix+r.OldKey, src, storage.Tag{Name: rotationTag}); err != nil {
+		return fmt.Errorf("save key rotation: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) scheduleRetirements() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	records, err := s.store.Query(rotationTag)
+	if err != nil {
+		return fmt.Errorf("query key rotations: %w", err)
+	}
+
+	defer storage.Close(records, logger)
+
+	more, err := records.Next()
+	if err != nil {
+		return fmt.Errorf("get next record: %w", err)
+	}
+
+	for more {
+		value, err := records.Value()
+		if err != nil {
+			return fmt.Errorf("get value from records: %w", err)
+		}
+
+		r := &rotation{}
+
+		if err = json.Unmarshal(value, r); err != nil {
+			return fmt.Errorf("unmarshal key rotation: %w", err)
+		}
+
+		// the interrupted rotations wait for RotateKey to resume them
+		if r.State == stateRotated || r.State == stateRetiring {
+			s.scheduleRetirement(r.OldKey, time.Until(r.RetireAt))
+		}
+
+		more, err = records.Next()
+		if err != nil {
+			return fmt.Errorf("get next record: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) scheduleRetirement(oldKey string, after time.Duration) {
+	s.timers[oldKey] = time.AfterFunc(after, func() {
+		err := s.RetireKey(oldKey)
+		if err == nil || errors.Is(err, ErrRotationNotFound) {
+			return
+		}
+
+		logger.Errorf("key rotation: retire key %s: %s", oldKey, err)
+
+		s.lock.Lock()
+		s.scheduleRetirement(oldKey, retireRetryInterval)
+		s.lock.Unlock()
+	})
+}
+
+func verifyDocSignature(docSignature *DocSignature) (*did.Doc, error) {
+	docBytes, err := base64.URLEncoding.DecodeString(docSignature.SignedData)
+	if err != nil {
+		return nil, fmt.Errorf("decode signature data: %w", err)
+	}
+
+	signature, err := base64.URLEncoding.DecodeString(docSignature.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("decode signature: %w", err)
+	}
+
+	pubKey, err := fingerprint.PubKeyFromDIDKey(docSignature.SignVerKey)
+	if err != nil {
+		return nil, fmt.Errorf("parse signer %s: %w", docSignature.SignVerKey, err)
+	}
+
+	signatureSuite := ed25519signature2018.New(suite.WithVerifier(ed25519signature2018.NewPublicKeyVerifier()))
+
+	err = signatureSuite.Verify(&verifier.PublicKey{
+		Type:  kms.ED25519,
+		Value: pubKey,
+	}, docBytes, signature)
+	if err != nil {
+		return nil, fmt.Errorf("verify signature: %w", err)
+	}
+
+	doc, err := did.ParseDocument(docBytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse DID document: %w", err)
+	}
+
+	return doc, nil
+}
+
+// addKey adds the new key next to the old one: to the verification methods, to the verification relationships
+// of the old key and to the recipient keys of the DIDComm services. The new key goes first in the recipient keys,
+// so it is used as the sender key from now on.
+func addKey(doc *did.Doc, oldPubKey []byte, vm *did.VerificationMethod, oldKey, newKey string) {
+	doc.VerificationMethod = append(doc.VerificationMethod, *vm)
+	doc.Authentication = addVerification(doc.Authentication, oldPubKey, vm)
+	doc.AssertionMethod = addVerification(doc.AssertionMethod, oldPubKey, vm)
+
+	for i := range doc.Service {
+		if contains(doc.Service[i].RecipientKeys, oldKey) {
+			doc.Service[i].RecipientKeys = append([]string{newKey}, doc.Service[i].RecipientKeys...)
+		}
+	}
+
+	updated := time.Now()
+	doc.Updated = &updated
+}
+
+func addVerification(verifications []did.Verification, oldPubKey []byte,
+	vm *did.VerificationMethod) []did.Verification {
+	for _, v := range verifications {
+		if bytes.Equal(v.VerificationMethod.Value, oldPubKey) {
+			return append(verifications, did.Verification{
+				VerificationMethod: *vm,
+				Relationship:       v.Relationship,
+				Embedded:           v.Embedded,
+			})
+		}
+	}
+
+	return verifications
+}
+
+// removeKey removes all occurrences of the old key from the DID document.
+func removeKey(doc *did.Doc, oldPubKey []byte, oldKey string) {
+	var methods []did.VerificationMethod
+
+	for _, vm := range doc.VerificationMethod {
+		if !bytes.Equal(vm.Value, oldPubKey) {
+			methods = append(methods, vm)
+		}
+	}
+
+	doc.VerificationMethod = methods
+	doc.Authentication = removeVerification(doc.Authentication, oldPubKey)
+	doc.AssertionMethod = removeVerification(doc.AssertionMethod, oldPubKey)
+
+	for i := range doc.Service {
+		var keys []string
+
+		for _, key := range doc.Service[i].RecipientKeys {
+			if key != oldKey {
+				keys = append(keys, key)
+			}
+		}
+
+		doc.Service[i].RecipientKeys = keys
+	}
+
+	updated := time.Now()
+	doc.Updated = &updated
+}
+
+func removeVerification(verifications []did.Verification, oldPubKey []byte) []did.Verification {
+	var result []did.Verification
+
+	for _, v := range verifications {
+		if !bytes.Equal(v.VerificationMethod.Value, oldPubKey) {
+			result = append(result, v)
+		}
+	}
+
+	return result
+}
+
+func hasRecipientKey(doc *did.Doc, key string) bool {
+	for i := range doc.Service {
+		if contains(doc.Service[i].RecipientKeys, key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func contains(keys []string, key string) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+
+	return false
+}
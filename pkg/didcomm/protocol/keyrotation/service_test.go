@@ -0,0 +1,492 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package keyrotation
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/mediator"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	vdrapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/localkms"
+	mockdispatcher "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/dispatcher"
+	"github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol"
+	mockmediator "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol/mediator"
+	mockprovider "github.com/hyperledger/aries-framework-go/pkg/mock/provider"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock/noop"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+	didstore "github.com/hyperledger/aries-framework-go/pkg/store/did"
+	"github.com/hyperledger/aries-framework-go/pkg/vdr"
+	"github.com/hyperledger/aries-framework-go/pkg/vdr/peer"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+)
+
+func TestService_New(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		a := newAgent(t, "alice")
+
+		require.Equal(t, KeyRotation, a.svc.Name())
+		require.True(t, a.svc.Accept(RotateMsgType))
+		require.False(t, a.svc.Accept("unsupported"))
+
+		_, err := a.svc.HandleOutbound(nil, "", "")
+		require.EqualError(t, err, "not implemented")
+	})
+
+	t.Run("open store error", func(t *testing.T) {
+		_, err := New(&mockprovider.Provider{
+			StorageProviderValue: &mockStorageProvider{errOpen: errors.New("open error")},
+		})
+		require.EqualError(t, err, "open key rotation store: open error")
+	})
+
+	t.Run("route service errors", func(t *testing.T) {
+		_, err := New(&mockprovider.Provider{
+			StorageProviderValue:              mem.NewProvider(),
+			ProtocolStateStorageProviderValue: mem.NewProvider(),
+			ServiceErr:                        errors.New("service error"),
+		})
+		require.EqualError(t, err, "service error")
+
+		_, err = New(&mockprovider.Provider{
+			StorageProviderValue:              mem.NewProvider(),
+			ProtocolStateStorageProviderValue: mem.NewProvider(),
+			ServiceValue:                      struct{}{},
+		})
+		require.EqualError(t, err, "cast service to Route Service failed")
+	})
+}
+
+func TestService_RotateKey(t *testing.T) {
+	alice, bob := newAgent(t, "alice"), newAgent(t, "bob")
+	connect(t, alice, bob)
+
+	oldKey := recipientKey(t, alice, alice.did)
+
+	newKey, err := alice.svc.RotateKey(oldKey, WithGracePeriod(time.Hour))
+	require.NoError(t, err)
+	require.NotEqual(t, oldKey, newKey)
+
+	// both keys are usable during the grace period, the new one is used as the sender key
+	doc := resolve(t, alice, alice.did)
+	require.Equal(t, []string{newKey, oldKey}, doc.Service[0].RecipientKeys)
+	require.Len(t, doc.VerificationMethod, 2)
+	require.Len(t, doc.Authentication, 2)
+
+	require.Equal(t, []string{oldKey, newKey}, alice.routerKeys)
+
+	aliceDID, err := alice.didConnStore.GetDID(newKey)
+	require.NoError(t, err)
+	require.Equal(t, alice.did, aliceDID)
+
+	// bob gets the document signed with the old key
+	rotateMsg := alice.sent(t, oldKey)
+
+	_, err = bob.svc.HandleInbound(rotateMsg, bob.did, alice.did)
+	require.NoError(t, err)
+
+	require.Equal(t, []string{newKey, oldKey}, resolve(t, bob, alice.did).Service[0].RecipientKeys)
+	require.Equal(t, []string{newKey, oldKey}, bob.connection(t).RecipientKeys)
+
+	aliceDID, err = bob.didConnStore.GetDID(newKey)
+	require.NoError(t, err)
+	require.Equal(t, alice.did, aliceDID)
+
+	_, err = alice.svc.RotateKey(oldKey)
+	require.EqualError(t, err, "key "+oldKey+" is already being rotated")
+
+	// retired before the grace period is over, the document is signed with the new key
+	require.NoError(t, alice.svc.RetireKey(oldKey))
+
+	doc = resolve(t, alice, alice.did)
+	require.Equal(t, []string{newKey}, doc.Service[0].RecipientKeys)
+	require.Len(t, doc.VerificationMethod, 1)
+	require.Len(t, doc.Authentication, 1)
+	require.Equal(t, []string{newKey}, alice.routerKeys)
+
+	_, err = bob.svc.HandleInbound(alice.sent(t, newKey), bob.did, alice.did)
+	require.NoError(t, err)
+
+	require.Equal(t, []string{newKey}, resolve(t, bob, alice.did).Service[0].RecipientKeys)
+	require.Equal(t, []string{newKey}, bob.connection(t).RecipientKeys)
+
+	// replay of the rotation does not roll back the document
+	_, err = bob.svc.HandleInbound(rotateMsg, bob.did, alice.did)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "signer "+oldKey+" is not a key of DID "+alice.did)
+
+	err = alice.svc.RetireKey(oldKey)
+	require.True(t, errors.Is(err, ErrRotationNotFound))
+}
+
+func TestService_RotateKey_GracePeriod(t *testing.T) {
+	alice, bob := newAgent(t, "alice"), newAgent(t, "bob")
+	connect(t, alice, bob)
+
+	oldKey := recipientKey(t, alice, alice.did)
+
+	t.Run("pending rotation is retired on restart", func(t *testing.T) {
+		_, err := alice.svc.RotateKey(oldKey, WithGracePeriod(time.Hour))
+		require.NoError(t, err)
+
+		// stop the timer as if the agent was stopped
+		alice.svc.timers[oldKey].Stop()
+
+		r, err := alice.svc.getRotation(oldKey)
+		require.NoError(t, err)
+
+		r.RetireAt = time.Now()
+		require.NoError(t, alice.svc.saveRotation(r))
+
+		alice.svc, err = New(alice.provider)
+		require.NoError(t, err)
+
+		require.Eventually(t, func() bool {
+			_, err = alice.svc.getRotation(oldKey)
+
+			return errors.Is(err, ErrRotationNotFound)
+		}, time.Second, 10*time.Millisecond)
+
+		alice.svc.lock.Lock()
+		defer alice.svc.lock.Unlock()
+
+		require.Len(t, resolve(t, alice, alice.did).Service[0].RecipientKeys, 1)
+		require.NotContains(t, resolve(t, alice, alice.did).Service[0].RecipientKeys, oldKey)
+	})
+}
+
+func TestService_RotateKey_Errors(t *testing.T) {
+	alice, bob := newAgent(t, "alice"), newAgent(t, "bob")
+	connect(t, alice, bob)
+
+	t.Run("invalid key", func(t *testing.T) {
+		_, err := alice.svc.RotateKey("invalid")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "parse key invalid")
+	})
+
+	t.Run("key is not used", func(t *testing.T) {
+		_, err := alice.svc.RotateKey(recipientKey(t, bob, bob.did))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "is not used by any DID document of the connections")
+	})
+
+	t.Run("router error", func(t *testing.T) {
+		oldKey := recipientKey(t, alice, alice.did)
+
+		alice.router.AddKeyErr = errors.New("router error")
+
+		_, err := alice.svc.RotateKey(oldKey)
+		require.EqualError(t, err, "update router key: addKey: router error")
+
+		alice.router.AddKeyErr = nil
+
+		r, err := alice.svc.getRotation(oldKey)
+		require.NoError(t, err)
+		require.Equal(t, stateCreated, r.State)
+
+		err = alice.svc.RetireKey(oldKey)
+		require.EqualError(t, err, "rotation of key "+oldKey+" is not complete, call RotateKey to resume it")
+
+		// the retry resumes the rotation with the key created by the failed one
+		newKey, err := alice.svc.RotateKey(oldKey)
+		require.NoError(t, err)
+		require.Equal(t, r.NewKey, newKey)
+		require.Equal(t, []string{newKey, oldKey}, resolve(t, alice, alice.did).Service[0].RecipientKeys)
+
+		require.NoError(t, alice.svc.RetireKey(oldKey))
+		require.Equal(t, []string{newKey}, resolve(t, alice, alice.did).Service[0].RecipientKeys)
+	})
+
+	t.Run("rotation is busy while the router is updated", func(t *testing.T) {
+		oldKey := recipientKey(t, alice, alice.did)
+
+		adding, done := make(chan struct{}), make(chan struct{})
+
+		addKey := alice.router.AddKeyFunc
+		alice.router.AddKeyFunc = func(key string) error {
+			close(adding)
+			<-done
+
+			return addKey(key)
+		}
+
+		defer func() { alice.router.AddKeyFunc = addKey }()
+
+		rotated := make(chan error)
+
+		go func() {
+			_, err := alice.svc.RotateKey(oldKey)
+			rotated <- err
+		}()
+
+		<-adding
+
+		// the service lock is not held by the rotation waiting for the router
+		_, err := alice.svc.RotateKey(oldKey)
+		require.True(t, errors.Is(err, errRotationBusy))
+
+		err = alice.svc.RetireKey(oldKey)
+		require.EqualError(t, err, "key "+oldKey+": "+errRotationBusy.Error())
+
+		close(done)
+		require.NoError(t, <-rotated)
+		require.NoError(t, alice.svc.RetireKey(oldKey))
+	})
+
+	t.Run("retire unknown key", func(t *testing.T) {
+		err := alice.svc.RetireKey("unknown")
+		require.True(t, errors.Is(err, ErrRotationNotFound))
+	})
+}
+
+func TestService_HandleInbound_Errors(t *testing.T) {
+	alice, bob := newAgent(t, "alice"), newAgent(t, "bob")
+	connect(t, alice, bob)
+
+	oldKey := recipientKey(t, alice, alice.did)
+
+	_, err := alice.svc.RotateKey(oldKey, WithGracePeriod(time.Hour))
+	require.NoError(t, err)
+
+	rotateMsg := alice.sent(t, oldKey)
+
+	rotate := &Rotate{}
+	require.NoError(t, rotateMsg.Decode(rotate))
+
+	t.Run("unsupported message", func(t *testing.T) {
+		_, err = bob.svc.HandleInbound(service.NewDIDCommMsgMap(struct {
+			Type string `json:"@type"`
+		}{Type: "unsupported"}), bob.did, alice.did)
+		require.EqualError(t, err, "unsupported message type unsupported")
+	})
+
+	t.Run("unknown sender", func(t *testing.T) {
+		_, err = bob.svc.HandleInbound(rotateMsg, bob.did, "")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "does not match the sender DID")
+	})
+
+	t.Run("missing signature", func(t *testing.T) {
+		_, err = bob.svc.HandleInbound(service.NewDIDCommMsgMap(&Rotate{
+			Type: RotateMsgType,
+			ID:   "id",
+			DID:  alice.did,
+		}), bob.did, alice.did)
+		require.EqualError(t, err, "handle rotate: missing DID document signature")
+	})
+
+	t.Run("invalid signature", func(t *testing.T) {
+		signature := *rotate.DocSignature
+		signature.Signature = base64.URLEncoding.EncodeToString([]byte("invalid"))
+
+		_, err = bob.svc.HandleInbound(service.NewDIDCommMsgMap(&Rotate{
+			Type:         RotateMsgType,
+			ID:           "id",
+			DID:          alice.did,
+			DocSignature: &signature,
+		}), bob.did, alice.did)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "verify signature")
+	})
+
+	t.Run("document of other DID", func(t *testing.T) {
+		// signed by alice's key, but the sender is bob
+		_, err = alice.svc.HandleInbound(service.NewDIDCommMsgMap(&Rotate{
+			Type:         RotateMsgType,
+			ID:           "id",
+			DID:          bob.did,
+			DocSignature: rotate.DocSignature,
+		}), alice.did, bob.did)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "is not a key of DID "+bob.did)
+	})
+}
+
+type agent struct {
+	svc          *Service
+	provider     *mockprovider.Provider
+	router       *mockmediator.MockMediatorSvc
+	didConnStore *didstore.ConnectionStore
+	recorder     *connection.Recorder
+	routerKeys   []string
+	messages     []*sentMessage
+	did          string
+	label        string
+}
+
+type sentMessage struct {
+	msg       interface{}
+	senderKey string
+}
+
+func newAgent(t *testing.T, label string) *agent {
+	t.Helper()
+
+	a := &agent{label: label}
+
+	storeProvider := mem.NewProvider()
+
+	km, err := localkms.New("local-lock://primary/test/", &protocol.MockProvider{
+		StoreProvider: storeProvider,
+		CustomLock:    &noop.NoLock{},
+	})
+	require.NoError(t, err)
+
+	cr, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	peerVDR, err := peer.New(storeProvider)
+	require.NoError(t, err)
+
+	a.router = &mockmediator.MockMediatorSvc{
+		Connections: []string{"router"},
+		AddKeyFunc: func(key string) error {
+			a.routerKeys = append(a.routerKeys, key)
+
+			return nil
+		},
+		RemoveKeyFunc: func(key string) error {
+			for i, k := range a.routerKeys {
+				if k == key {
+					a.routerKeys = append(a.routerKeys[:i], a.routerKeys[i+1:]...)
+				}
+			}
+
+			return nil
+		},
+	}
+
+	a.provider = &mockprovider.Provider{
+		StorageProviderValue:              storeProvider,
+		ProtocolStateStorageProviderValue: storeProvider,
+		KMSValue:                          km,
+		CryptoValue:                       cr,
+		ServiceMap:                        map[string]interface{}{mediator.Coordination: a.router},
+		OutboundDispatcherValue: &mockdispatcher.MockOutbound{
+			ValidateSend: func(msg interface{}, senderVerKey string, _ *service.Destination) error {
+				a.messages = append(a.messages, &sentMessage{msg: msg, senderKey: senderVerKey})
+
+				return nil
+			},
+		},
+	}
+
+	a.provider.VDRegistryValue = vdr.New(a.provider, vdr.WithVDR(peerVDR))
+
+	a.svc, err = New(a.provider)
+	require.NoError(t, err)
+
+	a.didConnStore, err = didstore.NewConnectionStore(a.provider)
+	require.NoError(t, err)
+
+	a.recorder, err = connection.NewRecorder(a.provider)
+	require.NoError(t, err)
+
+	docResolution, err := a.provider.VDRegistryValue.Create(peer.DIDMethod, &did.Doc{
+		Service: []did.Service{{
+			Type:            vdrapi.DIDCommServiceType,
+			ServiceEndpoint: "https://" + label + ".example.com",
+		}},
+	})
+	require.NoError(t, err)
+
+	a.did = docResolution.DIDDocument.ID
+
+	// the key is registered with the router as by the DID exchange
+	a.routerKeys = docResolution.DIDDocument.Service[0].RecipientKeys
+
+	require.NoError(t, a.didConnStore.SaveDIDFromDoc(docResolution.DIDDocument))
+
+	return a
+}
+
+// sent returns the last message the agent sent and checks its sender key.
+func (a *agent) sent(t *testing.T, senderKey string) service.DIDCommMsg {
+	t.Helper()
+
+	require.NotEmpty(t, a.messages)
+
+	sent := a.messages[len(a.messages)-1]
+	require.Equal(t, senderKey, sent.senderKey)
+
+	src, err := json.Marshal(sent.msg)
+	require.NoError(t, err)
+
+	msg, err := service.ParseDIDCommMsgMap(src)
+	require.NoError(t, err)
+
+	return msg
+}
+
+func (a *agent) connection(t *testing.T) *connection.Record {
+	t.Helper()
+
+	record, err := a.recorder.GetConnectionRecord(a.label + "-connection")
+	require.NoError(t, err)
+
+	return record
+}
+
+// connect stores the DID documents of the counterparty and the completed connection records.
+func connect(t *testing.T, a, b *agent) {
+	t.Helper()
+
+	for _, pair := range [][]*agent{{a, b}, {b, a}} {
+		me, they := pair[0], pair[1]
+
+		theirDoc := resolve(t, they, they.did)
+
+		_, err := me.provider.VDRegistryValue.Create(peer.DIDMethod, theirDoc, vdrapi.WithOption("store", true))
+		require.NoError(t, err)
+
+		require.NoError(t, me.didConnStore.SaveDIDFromDoc(theirDoc))
+
+		require.NoError(t, me.recorder.SaveConnectionRecord(&connection.Record{
+			ConnectionID:  me.label + "-connection",
+			State:         connection.StateNameCompleted,
+			ThreadID:      "thread",
+			MyDID:         me.did,
+			TheirDID:      they.did,
+			TheirLabel:    they.label,
+			RecipientKeys: theirDoc.Service[0].RecipientKeys,
+		}))
+	}
+}
+
+func recipientKey(t *testing.T, a *agent, didID string) string {
+	t.Helper()
+
+	return resolve(t, a, didID).Service[0].RecipientKeys[0]
+}
+
+func resolve(t *testing.T, a *agent, didID string) *did.Doc {
+	t.Helper()
+
+	docResolution, err := a.provider.VDRegistryValue.Resolve(didID)
+	require.NoError(t, err)
+
+	return docResolution.DIDDocument
+}
+
+type mockStorageProvider struct {
+	storage.Provider
+	errOpen error
+}
+
+func (p *mockStorageProvider) OpenStore(string) (storage.Store, error) {
+	return nil, p.errOpen
+}
@@ -11,6 +11,9 @@ type ProtocolService interface {
 	// AddKey adds agents recKey to the router
 	AddKey(connID, recKey string) error
 
+	// RemoveKey removes agents recKey from the router
+	RemoveKey(connID, recKey string) error
+
 	// Config gives back the router configuration
 	Config(connID string) (*Config, error)
 
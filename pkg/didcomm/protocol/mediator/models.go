@@ -55,3 +55,47 @@ type UpdateResponse struct {
 	Action       string `json:"action,omitempty"`
 	Result       string `json:"result,omitempty"`
 }
+
+// Deny route deny message.
+// https://github.com/hyperledger/aries-rfcs/tree/master/features/0211-route-coordination#mediation-deny
+type Deny struct {
+	Type           string   `json:"@type,omitempty"`
+	ID             string   `json:"@id,omitempty"`
+	MediatorTerms  []string `json:"mediator_terms,omitempty"`
+	RecipientTerms []string `json:"recipient_terms,omitempty"`
+}
+
+// KeylistQuery route keylist query message.
+// https://github.com/hyperledger/aries-rfcs/tree/master/features/0211-route-coordination#key-list-query
+type KeylistQuery struct {
+	Type     string    `json:"@type,omitempty"`
+	ID       string    `json:"@id,omitempty"`
+	Paginate *Paginate `json:"paginate,omitempty"`
+}
+
+// Paginate keylist query pagination parameters, zero limit means all the keys starting from the offset.
+type Paginate struct {
+	Limit  int `json:"limit,omitempty"`
+	Offset int `json:"offset,omitempty"`
+}
+
+// Keylist route keylist message.
+// https://github.com/hyperledger/aries-rfcs/tree/master/features/0211-route-coordination#key-list
+type Keylist struct {
+	Type       string       `json:"@type,omitempty"`
+	ID         string       `json:"@id,omitempty"`
+	Keys       []KeylistKey `json:"keys,omitempty"`
+	Pagination *Pagination  `json:"pagination,omitempty"`
+}
+
+// KeylistKey route keylist key.
+type KeylistKey struct {
+	RecipientKey string `json:"recipient_key,omitempty"`
+}
+
+// Pagination keylist pagination information.
+type Pagination struct {
+	Count     int `json:"count"`
+	Offset    int `json:"offset"`
+	Remaining int `json:"remaining"`
+}
@@ -0,0 +1,137 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mediator
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+)
+
+// Policy decides on the mediation requests and limits the registrations granted by the mediator.
+type Policy interface {
+	// Approve returns an error if the mediation is denied to the connection.
+	Approve(record *connection.Record) error
+	// MaxKeys returns the maximum number of the recipient keys a client can register, zero means no limit.
+	MaxKeys() int
+	// IdleTimeout returns the duration after which an idle registration expires, zero means it never expires.
+	IdleTimeout() time.Duration
+}
+
+// PolicyOption configures the mediator policy.
+type PolicyOption func(p *policy)
+
+// WithAllowedConnections grants the mediation only to the given connections and the connections allowed by label.
+func WithAllowedConnections(connectionIDs ...string) PolicyOption {
+	return func(p *policy) {
+		addAll(p.allowedConnections, connectionIDs)
+	}
+}
+
+// WithDeniedConnections denies the mediation to the given connections.
+func WithDeniedConnections(connectionIDs ...string) PolicyOption {
+	return func(p *policy) {
+		addAll(p.deniedConnections, connectionIDs)
+	}
+}
+
+// WithAllowedLabels grants the mediation only to the connections with the given labels and the connections
+// allowed by ID.
+func WithAllowedLabels(labels ...string) PolicyOption {
+	return func(p *policy) {
+		addAll(p.allowedLabels, labels)
+	}
+}
+
+// WithDeniedLabels denies the mediation to the connections with the given labels.
+func WithDeniedLabels(labels ...string) PolicyOption {
+	return func(p *policy) {
+		addAll(p.deniedLabels, labels)
+	}
+}
+
+// WithMaxKeys limits the number of the recipient keys a client can register.
+func WithMaxKeys(maxKeys int) PolicyOption {
+	return func(p *policy) {
+		p.maxKeys = maxKeys
+	}
+}
+
+// WithIdleTimeout expires the registrations of the clients which were idle for the given duration.
+func WithIdleTimeout(timeout time.Duration) PolicyOption {
+	return func(p *policy) {
+		p.idleTimeout = timeout
+	}
+}
+
+type policy struct {
+	allowedConnections map[string]struct{}
+	deniedConnections  map[string]struct{}
+	allowedLabels      map[string]struct{}
+	deniedLabels       map[string]struct{}
+	maxKeys            int
+	idleTimeout        time.Duration
+}
+
+// NewPolicy returns the mediator policy. The mediation is granted to every connection, the number of keys
+// is not limited and the registrations never expire unless the options say otherwise.
+func NewPolicy(opts ...PolicyOption) Policy {
+	p := &policy{
+		allowedConnections: make(map[string]struct{}),
+		deniedConnections:  make(map[string]struct{}),
+		allowedLabels:      make(map[string]struct{}),
+		deniedLabels:       make(map[string]struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// Approve denies the connections which are denied either by ID or by label, and the connections which are
+// allowed neither by ID nor by label if any of the allow lists is set.
+func (p *policy) Approve(record *connection.Record) error {
+	if _, ok := p.deniedConnections[record.ConnectionID]; ok {
+		return fmt.Errorf("connection %s: %w", record.ConnectionID, ErrMediationDenied)
+	}
+
+	if _, ok := p.deniedLabels[record.TheirLabel]; ok {
+		return fmt.Errorf("label %s: %w", record.TheirLabel, ErrMediationDenied)
+	}
+
+	if len(p.allowedConnections) == 0 && len(p.allowedLabels) == 0 {
+		return nil
+	}
+
+	_, connAllowed := p.allowedConnections[record.ConnectionID]
+	_, labelAllowed := p.allowedLabels[record.TheirLabel]
+
+	if !connAllowed && !labelAllowed {
+		return fmt.Errorf("connection %s is not allowed: %w", record.ConnectionID, ErrMediationDenied)
+	}
+
+	return nil
+}
+
+// MaxKeys returns the maximum number of the recipient keys a client can register.
+func (p *policy) MaxKeys() int {
+	return p.maxKeys
+}
+
+// IdleTimeout returns the duration after which an idle registration expires.
+func (p *policy) IdleTimeout() time.Duration {
+	return p.idleTimeout
+}
+
+func addAll(set map[string]struct{}, values []string) {
+	for _, v := range values {
+		set[v] = struct{}{}
+	}
+}
@@ -0,0 +1,89 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mediator
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+)
+
+func TestPolicy(t *testing.T) {
+	t.Run("default policy", func(t *testing.T) {
+		p := NewPolicy()
+		require.NoError(t, p.Approve(&connection.Record{ConnectionID: "conn", TheirLabel: "alice"}))
+		require.NoError(t, p.Approve(&connection.Record{}))
+		require.Zero(t, p.MaxKeys())
+		require.Zero(t, p.IdleTimeout())
+	})
+
+	t.Run("limits", func(t *testing.T) {
+		p := NewPolicy(WithMaxKeys(5), WithIdleTimeout(time.Hour))
+		require.Equal(t, 5, p.MaxKeys())
+		require.Equal(t, time.Hour, p.IdleTimeout())
+	})
+
+	t.Run("approve", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			opts   []PolicyOption
+			record *connection.Record
+			denied bool
+		}{{
+			name:   "denied connection",
+			opts:   []PolicyOption{WithDeniedConnections("conn-1")},
+			record: &connection.Record{ConnectionID: "conn-1"},
+			denied: true,
+		}, {
+			name:   "denied label",
+			opts:   []PolicyOption{WithDeniedLabels("mallory")},
+			record: &connection.Record{ConnectionID: "conn-1", TheirLabel: "mallory"},
+			denied: true,
+		}, {
+			name:   "denied label wins over allowed connection",
+			opts:   []PolicyOption{WithAllowedConnections("conn-1"), WithDeniedLabels("mallory")},
+			record: &connection.Record{ConnectionID: "conn-1", TheirLabel: "mallory"},
+			denied: true,
+		}, {
+			name:   "not denied",
+			opts:   []PolicyOption{WithDeniedConnections("conn-1"), WithDeniedLabels("mallory")},
+			record: &connection.Record{ConnectionID: "conn-2", TheirLabel: "alice"},
+		}, {
+			name:   "allowed connection",
+			opts:   []PolicyOption{WithAllowedConnections("conn-1"), WithAllowedLabels("alice")},
+			record: &connection.Record{ConnectionID: "conn-1", TheirLabel: "bob"},
+		}, {
+			name:   "allowed label",
+			opts:   []PolicyOption{WithAllowedConnections("conn-1"), WithAllowedLabels("alice")},
+			record: &connection.Record{ConnectionID: "conn-2", TheirLabel: "alice"},
+		}, {
+			name:   "not allowed",
+			opts:   []PolicyOption{WithAllowedConnections("conn-1"), WithAllowedLabels("alice")},
+			record: &connection.Record{ConnectionID: "conn-2", TheirLabel: "bob"},
+			denied: true,
+		}}
+
+		for _, tc := range tests {
+			tc := tc
+			t.Run(tc.name, func(t *testing.T) {
+				err := NewPolicy(tc.opts...).Approve(tc.record)
+				if tc.denied {
+					require.Error(t, err)
+					require.True(t, errors.Is(err, ErrMediationDenied))
+
+					return
+				}
+
+				require.NoError(t, err)
+			})
+		}
+	})
+}
@@ -0,0 +1,294 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mediator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+)
+
+const (
+	// data key to store the registration of the client.
+	registrationDataKey = "registration_%s"
+
+	// registrationTag tags the registrations of the clients.
+	registrationTag = "registration"
+
+	// touchInterval throttles the updates of the registration activity caused by the forwarded messages.
+	touchInterval = time.Minute
+)
+
+var (
+	// errRegistrationExpired registration expired error.
+	errRegistrationExpired = errors.New("registration expired")
+	// errMediationNotGranted mediation not granted error.
+	errMediationNotGranted = errors.New("mediation not granted")
+)
+
+// registration holds the state of the mediation granted to the client. The registrations are created once
+// the mediation is granted, the clients without the registration can't update or query their keys.
+type registration struct {
+	TheirDID    string    `json:"their_did"`
+	Keys        []string  `json:"keys,omitempty"`
+	GrantedTime time.Time `json:"granted_time,omitempty"`
+	LastActive  time.Time `json:"last_active"`
+}
+
+func (r *registration) hasKey(recKey string) bool {
+	for _, key := range r.Keys {
+		if key == recKey {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *registration) addKey(recKey string) {
+	if !r.hasKey(recKey) {
+		r.Keys = append(r.Keys, recKey)
+	}
+}
+
+func (r *registration) removeKey(recKey string) {
+	for i, key := range r.Keys {
+		if key == recKey {
+			r.Keys = append(r.Keys[:i], r.Keys[i+1:]...)
+
+			return
+		}
+	}
+}
+
+// expired checks whether the registration was idle for longer than the policy allows.
+func (s *Service) expired(reg *registration) bool {
+	timeout := s.policy.IdleTimeout()
+
+	return timeout > 0 && time.Since(reg.LastActive) > timeout
+}
+
+// grantRegistration saves the registration of the client the mediation is granted to, the expired registrations
+// of the other clients are removed on the way.
+func (s *Service) grantRegistration(theirDID string) error {
+	s.registrationLock.Lock()
+	defer s.registrationLock.Unlock()
+
+	err := s.expireRegistrations()
+	if err != nil {
+		return fmt.Errorf("expire registrations: %w", err)
+	}
+
+	reg, err := s.loadRegistration(theirDID)
+	if err != nil {
+		return err
+	}
+
+	reg.GrantedTime = time.Now()
+	reg.LastActive = reg.GrantedTime
+
+	return s.putRegistration(reg)
+}
+
+// registeredKeys returns the keys registered by theirDID.
+func (s *Service) registeredKeys(theirDID string) ([]string, error) {
+	s.registrationLock.Lock()
+	defer s.registrationLock.Unlock()
+
+	reg, err := s.grantedRegistration(theirDID)
+	if err != nil {
+		return nil, err
+	}
+
+	reg.LastActive = time.Now()
+
+	err = s.putRegistration(reg)
+	if err != nil {
+		return nil, err
+	}
+
+	return reg.Keys, nil
+}
+
+// touchRegistration records the activity of the registration of theirDID, the expired registration is removed
+// and the error is returned. The keys registered without the registration are left as they are.
+func (s *Service) touchRegistration(theirDID string) error {
+	s.registrationLock.Lock()
+	defer s.registrationLock.Unlock()
+
+	reg, err := s.getRegistration(theirDID)
+	if errors.Is(err, storage.ErrDataNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if s.expired(reg) {
+		err = s.removeRegistration(reg)
+		if err != nil {
+			return err
+		}
+
+		return errRegistrationExpired
+	}
+
+	if time.Since(reg.LastActive) < touchInterval {
+		return nil
+	}
+
+	reg.LastActive = time.Now()
+
+	return s.putRegistration(reg)
+}
+
+// loadRegistration returns the registration of theirDID, a new registration is returned if there is none or if
+// the previous one has expired.
+func (s *Service) loadRegistration(theirDID string) (*registration, error) {
+	reg, err := s.getRegistration(theirDID)
+	if errors.Is(err, storage.ErrDataNotFound) {
+		return &registration{TheirDID: theirDID, LastActive: time.Now()}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if s.expired(reg) {
+		err = s.removeRegistration(reg)
+		if err != nil {
+			return nil, err
+		}
+
+		return &registration{TheirDID: theirDID, LastActive: time.Now()}, nil
+	}
+
+	return reg, nil
+}
+
+// grantedRegistration returns the registration of theirDID the mediation was granted to. The error is returned
+// if the mediation wasn't granted or if the registration has expired, the expired registration is removed.
+func (s *Service) grantedRegistration(theirDID string) (*registration, error) {
+	reg, err := s.getRegistration(theirDID)
+	if errors.Is(err, storage.ErrDataNotFound) {
+		return nil, errMediationNotGranted
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if s.expired(reg) {
+		err = s.removeRegistration(reg)
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, errRegistrationExpired
+	}
+
+	if reg.GrantedTime.IsZero() {
+		return nil, errMediationNotGranted
+	}
+
+	return reg, nil
+}
+
+// expireRegistrations removes the registrations which were idle for longer than the policy allows.
+func (s *Service) expireRegistrations() error {
+	if s.policy.IdleTimeout() <= 0 {
+		return nil
+	}
+
+	records, err := s.routeStore.Query(registrationTag)
+	if err != nil {
+		return fmt.Errorf("failed to query route store: %w", err)
+	}
+
+	defer storage.Close(records, logger)
+
+	var expired []*registration
+
+	more, err := records.Next()
+	if err != nil {
+		return fmt.Errorf("failed to get next record: %w", err)
+	}
+
+	for more {
+		value, err := records.Value()
+		if err != nil {
+			return fmt.Errorf("failed to get value from records: %w", err)
+		}
+
+		reg := &registration{}
+
+		err = json.Unmarshal(value, reg)
+		if err != nil {
+			return fmt.Errorf("unmarshal registration: %w", err)
+		}
+
+		if s.expired(reg) {
+			expired = append(expired, reg)
+		}
+
+		more, err = records.Next()
+		if err != nil {
+			return fmt.Errorf("failed to get next record: %w", err)
+		}
+	}
+
+	for _, reg := range expired {
+		err = s.removeRegistration(reg)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) getRegistration(theirDID string) (*registration, error) {
+	src, err := s.routeStore.Get(fmt.Sprintf(registrationDataKey, theirDID))
+	if err != nil {
+		return nil, err
+	}
+
+	reg := &registration{}
+
+	err = json.Unmarshal(src, reg)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal registration: %w", err)
+	}
+
+	return reg, nil
+}
+
+func (s *Service) putRegistration(reg *registration) error {
+	src, err := json.Marshal(reg)
+	if err != nil {
+		return fmt.Errorf("marshal registration: %w", err)
+	}
+
+	return s.routeStore.Put(fmt.Sprintf(registrationDataKey, reg.TheirDID), src, storage.Tag{Name: registrationTag})
+}
+
+// removeRegistration removes the registration along with the route keys of the client.
+func (s *Service) removeRegistration(reg *registration) error {
+	logger.Infof("removing the expired registration of theirDID=%s", reg.TheirDID)
+
+	for _, key := range reg.Keys {
+		if result := s.removeRouteKey(key, reg.TheirDID); result == serverError {
+			return fmt.Errorf("failed to remove the route key %s", key)
+		}
+	}
+
+	return s.routeStore.Delete(fmt.Sprintf(registrationDataKey, reg.TheirDID))
+}
@@ -51,6 +51,15 @@ const (
 
 	// KeyListUpdateResponseMsgType defines the route coordination key list update message response type.
 	KeylistUpdateResponseMsgType = CoordinationSpec + "keylist_update_response"
+
+	// DenyMsgType defines the route coordination request deny message type.
+	DenyMsgType = CoordinationSpec + "mediate-deny"
+
+	// KeylistQueryMsgType defines the route coordination key list query message type.
+	KeylistQueryMsgType = CoordinationSpec + "keylist_query"
+
+	// KeylistMsgType defines the route coordination key list message type.
+	KeylistMsgType = CoordinationSpec + "keylist"
 )
 
 // constants for key list update processing
@@ -65,6 +74,12 @@ const (
 	// server error while storing the key.
 	serverError = "server_error"
 
+	// client error, ex. the key is registered by other agent.
+	clientError = "client_error"
+
+	// key was not registered, nothing to remove.
+	noChange = "no_change"
+
 	// key save success.
 	success = "success"
 )
@@ -77,6 +92,8 @@ const (
 	routeConfigDataKey = "route_config_%s"
 
 	routeGrantKey = "grant_%s"
+
+	routeDenyKey = "deny_%s"
 )
 
 const (
@@ -89,6 +106,9 @@ var ErrConnectionNotFound = errors.New("connection not found")
 // ErrRouterNotRegistered router not registered error.
 var ErrRouterNotRegistered = errors.New("router not registered")
 
+// ErrMediationDenied mediation denied error.
+var ErrMediationDenied = errors.New("mediation denied")
+
 // provider contains dependencies for the Routing protocol and is typically created by using aries.Context().
 type provider interface {
 	OutboundDispatcher() dispatcher.Outbound
@@ -108,6 +128,16 @@ type ClientOptions struct {
 	Timeout time.Duration
 }
 
+// ServiceOption configures the route coordination service.
+type ServiceOption func(s *Service)
+
+// WithPolicy sets the policy the mediator applies to the mediation requests and the registrations.
+func WithPolicy(policy Policy) ServiceOption {
+	return func(s *Service) {
+		s.policy = policy
+	}
+}
+
 // Options is a container for route protocol options.
 type Options struct {
 	ServiceEndpoint string
@@ -140,19 +170,23 @@ type Service struct {
 	vdRegistry           vdr.Registry
 	keylistUpdateMap     map[string]chan *KeylistUpdateResponse
 	keylistUpdateMapLock sync.RWMutex
+	keylistMap           map[string]chan *Keylist
+	keylistMapLock       sync.RWMutex
 	callbacks            chan *callback
 	messagePickupSvc     messagepickup.ProtocolService
+	policy               Policy
+	registrationLock     sync.Mutex
 }
 
 // New return route coordination service.
-func New(prov provider) (*Service, error) {
+func New(prov provider, opts ...ServiceOption) (*Service, error) {
 	store, err := prov.StorageProvider().OpenStore(Coordination)
 	if err != nil {
 		return nil, fmt.Errorf("open route coordination store : %w", err)
 	}
 
 	err = prov.StorageProvider().SetStoreConfig(Coordination,
-		storage.StoreConfiguration{TagNames: []string{routeConnIDDataKey}})
+		storage.StoreConfiguration{TagNames: []string{routeConnIDDataKey, registrationTag}})
 	if err != nil {
 		return nil, fmt.Errorf("failed to set store configuration: %w", err)
 	}
@@ -180,8 +214,14 @@ func New(prov provider) (*Service, error) {
 		vdRegistry:       prov.VDRegistry(),
 		connectionLookup: connectionLookup,
 		keylistUpdateMap: make(map[string]chan *KeylistUpdateResponse),
+		keylistMap:       make(map[string]chan *Keylist),
 		callbacks:        make(chan *callback),
 		messagePickupSvc: messagePickupSvc,
+		policy:           NewPolicy(),
+	}
+
+	for _, opt := range opts {
+		opt(s)
 	}
 
 	go s.listenForCallbacks()
@@ -213,6 +253,14 @@ func (s *Service) listenForCallbacks() {
 
 func (s *Service) handleUserRejection(c *callback) {
 	logger.Infof("user aborted response action for msgID=%s", c.msg.ID())
+
+	if c.msg.Type() != RequestMsgType {
+		return
+	}
+
+	if err := s.sendDeny(c); err != nil {
+		logger.Errorf("failed to send mediate deny for msgID=%s : %s", c.msg.ID(), err)
+	}
 }
 
 func triggersActionEvent(msgType string) bool {
@@ -275,6 +323,12 @@ func (s *Service) HandleInbound(msg service.DIDCommMsg, myDID, theirDID string)
 		switch msg.Type() {
 		case GrantMsgType:
 			err = s.saveGrant(msg)
+		case DenyMsgType:
+			err = s.saveDeny(msg)
+		case KeylistQueryMsgType:
+			err = s.handleKeylistQuery(msg, myDID, theirDID)
+		case KeylistMsgType:
+			err = s.handleKeylist(msg)
 		case KeylistUpdateMsgType:
 			err = s.handleKeylistUpdate(msg, myDID, theirDID)
 		case KeylistUpdateResponseMsgType:
@@ -330,13 +384,22 @@ func (s *Service) HandleOutbound(msg service.DIDCommMsg, myDID, theirDID string)
 // Accept checks whether the service can handle the message type.
 func (s *Service) Accept(msgType string) bool {
 	switch msgType {
-	case RequestMsgType, GrantMsgType, KeylistUpdateMsgType, KeylistUpdateResponseMsgType, service.ForwardMsgType:
+	case RequestMsgType, GrantMsgType, DenyMsgType, KeylistUpdateMsgType, KeylistUpdateResponseMsgType,
+		KeylistQueryMsgType, KeylistMsgType, service.ForwardMsgType:
 		return true
 	}
 
 	return false
 }
 
+// MessageTypes returns the message types handled by the service.
+func (s *Service) MessageTypes() []string {
+	return []string{
+		RequestMsgType, GrantMsgType, DenyMsgType, KeylistUpdateMsgType, KeylistUpdateResponseMsgType,
+		KeylistQueryMsgType, KeylistMsgType, service.ForwardMsgType,
+	}
+}
+
 // Name of the service.
 func (s *Service) Name() string {
 	return Coordination
@@ -351,6 +414,13 @@ func (s *Service) handleInboundRequest(c *callback) error {
 		return fmt.Errorf("handleInboundRequest: route request message unmarshal : %w", err)
 	}
 
+	err = s.policy.Approve(s.connectionRecord(c.myDID, c.theirDID))
+	if err != nil {
+		logger.Infof("denying mediation to theirDID=%s : %s", c.theirDID, err)
+
+		return s.sendDeny(c)
+	}
+
 	grant, err := outboundGrant(
 		c.msg.ID(),
 		c.options,
@@ -371,9 +441,41 @@ func (s *Service) handleInboundRequest(c *callback) error {
 		return fmt.Errorf("handleInboundRequest: failed to handle inbound request : %w", err)
 	}
 
+	err = s.grantRegistration(c.theirDID)
+	if err != nil {
+		return fmt.Errorf("handleInboundRequest: failed to save registration : %w", err)
+	}
+
 	return s.outbound.SendToDID(grant, c.myDID, c.theirDID)
 }
 
+func (s *Service) sendDeny(c *callback) error {
+	return s.outbound.SendToDID(&Deny{
+		Type: DenyMsgType,
+		ID:   c.msg.ID(),
+	}, c.myDID, c.theirDID)
+}
+
+// connectionRecord returns the record of the connection the mediation is requested over, the policy decides
+// on the DIDs only if the record can't be found.
+func (s *Service) connectionRecord(myDID, theirDID string) *connection.Record {
+	connID, err := s.connectionLookup.GetConnectionIDByDIDs(myDID, theirDID)
+	if err == nil {
+		var record *connection.Record
+
+		record, err = s.connectionLookup.GetConnectionRecord(connID)
+		if err == nil && record != nil {
+			return record
+		}
+	}
+
+	if err != nil {
+		logger.Debugf("connection lookup for myDID=%s theirDID=%s : %s", myDID, theirDID, err)
+	}
+
+	return &connection.Record{MyDID: myDID, TheirDID: theirDID}
+}
+
 func outboundGrant(
 	msgID string, opts *Options,
 	defaultEndpoint string, defaultKey func() (string, error)) (*Grant, error) {
@@ -409,37 +511,9 @@ func (s *Service) handleKeylistUpdate(msg service.DIDCommMsg, myDID, theirDID st
 		return fmt.Errorf("route key list update message unmarshal : %w", err)
 	}
 
-	var updates []UpdateResponse
-
-	// update the db
-	for _, v := range keyUpdate.Updates {
-		if v.Action == add {
-			val := theirDID
-			result := success
-
-			err = s.routeStore.Put(dataKey(v.RecipientKey), []byte(val))
-			if err != nil {
-				logger.Errorf("failed to add the route key to store : %s", err)
-
-				result = serverError
-			}
-
-			// construct the response doc
-			updates = append(updates, UpdateResponse{
-				RecipientKey: v.RecipientKey,
-				Action:       v.Action,
-				Result:       result,
-			})
-		} else if v.Action == remove {
-			// TODO remove from the store
-
-			// construct the response doc
-			updates = append(updates, UpdateResponse{
-				RecipientKey: v.RecipientKey,
-				Action:       v.Action,
-				Result:       serverError,
-			})
-		}
+	updates, err := s.updateRouteKeys(theirDID, keyUpdate.Updates)
+	if err != nil {
+		return fmt.Errorf("route key list update : %w", err)
 	}
 
 	// send the key update response
@@ -452,6 +526,100 @@ func (s *Service) handleKeylistUpdate(msg service.DIDCommMsg, myDID, theirDID st
 	return s.outbound.SendToDID(updateResponse, myDID, theirDID)
 }
 
+// updateRouteKeys applies the keylist updates to the registration of theirDID and returns the update results.
+func (s *Service) updateRouteKeys(theirDID string, keyUpdates []Update) ([]UpdateResponse, error) {
+	s.registrationLock.Lock()
+	defer s.registrationLock.Unlock()
+
+	reg, err := s.grantedRegistration(theirDID)
+	if err != nil {
+		return nil, err
+	}
+
+	var updates []UpdateResponse
+
+	for _, v := range keyUpdates {
+		var result string
+
+		switch v.Action {
+		case add:
+			result = s.addRouteKey(reg, v.RecipientKey)
+		case remove:
+			result = s.removeRouteKey(v.RecipientKey, theirDID)
+			if result == success || result == noChange {
+				reg.removeKey(v.RecipientKey)
+			}
+		default:
+			continue
+		}
+
+		// construct the response doc
+		updates = append(updates, UpdateResponse{
+			RecipientKey: v.RecipientKey,
+			Action:       v.Action,
+			Result:       result,
+		})
+	}
+
+	reg.LastActive = time.Now()
+
+	err = s.putRegistration(reg)
+	if err != nil {
+		return nil, err
+	}
+
+	return updates, nil
+}
+
+// addRouteKey adds the route key to the registration and returns the keylist update result.
+func (s *Service) addRouteKey(reg *registration, recKey string) string {
+	// the key which is registered already doesn't count towards the limit
+	if maxKeys := s.policy.MaxKeys(); maxKeys > 0 && !reg.hasKey(recKey) && len(reg.Keys) >= maxKeys {
+		logger.Warnf("theirDID=%s reached the limit of %d keys", reg.TheirDID, maxKeys)
+
+		return clientError
+	}
+
+	err := s.routeStore.Put(dataKey(recKey), []byte(reg.TheirDID))
+	if err != nil {
+		logger.Errorf("failed to add the route key to store : %s", err)
+
+		return serverError
+	}
+
+	reg.addKey(recKey)
+
+	return success
+}
+
+// removeRouteKey removes the route key registered by theirDID and returns the keylist update result.
+func (s *Service) removeRouteKey(recKey, theirDID string) string {
+	val, err := s.routeStore.Get(dataKey(recKey))
+	if errors.Is(err, storage.ErrDataNotFound) {
+		return noChange
+	}
+
+	if err != nil {
+		logger.Errorf("failed to get the route key from store : %s", err)
+
+		return serverError
+	}
+
+	// agents can't remove the keys registered by the others
+	if string(val) != theirDID {
+		return clientError
+	}
+
+	err = s.routeStore.Delete(dataKey(recKey))
+	if err != nil {
+		logger.Errorf("failed to remove the route key from store : %s", err)
+
+		return serverError
+	}
+
+	return success
+}
+
 func (s *Service) handleKeylistUpdateResponse(msg service.DIDCommMsg) error {
 	// unmarshal the payload
 	respMsg := &KeylistUpdateResponse{}
@@ -472,6 +640,83 @@ func (s *Service) handleKeylistUpdateResponse(msg service.DIDCommMsg) error {
 	return nil
 }
 
+func (s *Service) handleKeylistQuery(msg service.DIDCommMsg, myDID, theirDID string) error {
+	query := &KeylistQuery{}
+
+	err := msg.Decode(query)
+	if err != nil {
+		return fmt.Errorf("route keylist query message unmarshal : %w", err)
+	}
+
+	keys, err := s.registeredKeys(theirDID)
+	if err != nil {
+		return fmt.Errorf("route keylist query : %w", err)
+	}
+
+	keylist := &Keylist{
+		Type: KeylistMsgType,
+		ID:   msg.ID(),
+	}
+
+	keylist.Keys, keylist.Pagination = paginate(keys, query.Paginate)
+
+	return s.outbound.SendToDID(keylist, myDID, theirDID)
+}
+
+// paginate returns the page of the keys, the pagination is set only if it was requested.
+func paginate(keys []string, p *Paginate) ([]KeylistKey, *Pagination) {
+	offset, end := 0, len(keys)
+
+	if p != nil {
+		if p.Offset > 0 {
+			offset = p.Offset
+		}
+
+		if offset > end {
+			offset = end
+		}
+
+		if p.Limit > 0 && offset+p.Limit < end {
+			end = offset + p.Limit
+		}
+	}
+
+	page := make([]KeylistKey, 0, end-offset)
+
+	for _, key := range keys[offset:end] {
+		page = append(page, KeylistKey{RecipientKey: key})
+	}
+
+	if p == nil {
+		return page, nil
+	}
+
+	return page, &Pagination{
+		Count:     len(page),
+		Offset:    offset,
+		Remaining: len(keys) - end,
+	}
+}
+
+func (s *Service) handleKeylist(msg service.DIDCommMsg) error {
+	keylist := &Keylist{}
+
+	err := msg.Decode(keylist)
+	if err != nil {
+		return fmt.Errorf("route keylist message unmarshal : %w", err)
+	}
+
+	if keylistCh := s.getKeylistCh(keylist.ID); keylistCh != nil {
+		select {
+		case keylistCh <- keylist:
+		default:
+			logger.Warnf("ignoring duplicate keylist msgID=%s", keylist.ID)
+		}
+	}
+
+	return nil
+}
+
 func (s *Service) handleForward(msg service.DIDCommMsg) error {
 	// unmarshal the payload
 	forward := &model.Forward{}
@@ -488,6 +733,11 @@ func (s *Service) handleForward(msg service.DIDCommMsg) error {
 		return fmt.Errorf("route key fetch : %w", err)
 	}
 
+	err = s.touchRegistration(string(theirDID))
+	if err != nil {
+		return fmt.Errorf("route registration : %w", err)
+	}
+
 	dest, err := service.GetDestination(string(theirDID), s.vdRegistry)
 	if err != nil {
 		return fmt.Errorf("get destination : %w", err)
@@ -495,7 +745,7 @@ func (s *Service) handleForward(msg service.DIDCommMsg) error {
 
 	err = s.outbound.Forward(forward.Msg, dest)
 	if err != nil && s.messagePickupSvc != nil {
-		return s.messagePickupSvc.AddMessage(forward.Msg, string(theirDID))
+		return s.messagePickupSvc.AddMessageForRecipient(forward.Msg, string(theirDID), forward.To)
 	}
 
 	return err
@@ -571,10 +821,22 @@ func (s *Service) getGrant(id string, timeout time.Duration) (*Grant, error) {
 
 	err = backoff.Retry(func() error {
 		src, err = s.routeStore.Get(fmt.Sprintf(routeGrantKey, id))
+		if !errors.Is(err, storage.ErrDataNotFound) {
+			return err
+		}
+
+		// the router denies the request instead of granting it
+		if _, denyErr := s.routeStore.Get(fmt.Sprintf(routeDenyKey, id)); denyErr == nil {
+			return backoff.Permanent(ErrMediationDenied)
+		}
 
 		return err
 	}, backoff.WithMaxRetries(backoff.NewConstantBackOff(time.Second), uint64(timeout/time.Second)))
 
+	if errors.Is(err, ErrMediationDenied) {
+		return nil, err
+	}
+
 	if err != nil {
 		return nil, fmt.Errorf("store: %w", err)
 	}
@@ -598,6 +860,15 @@ func (s *Service) saveGrant(grant service.DIDCommMsg) error {
 	return s.routeStore.Put(fmt.Sprintf(routeGrantKey, grant.ID()), src)
 }
 
+func (s *Service) saveDeny(deny service.DIDCommMsg) error {
+	src, err := json.Marshal(deny)
+	if err != nil {
+		return fmt.Errorf("marshal deny: %w", err)
+	}
+
+	return s.routeStore.Put(fmt.Sprintf(routeDenyKey, deny.ID()), src)
+}
+
 // Unregister unregisters the agent with the router.
 func (s *Service) Unregister(connID string) error {
 	// check if router is already registered
@@ -652,6 +923,16 @@ func (s *Service) GetConnections() ([]string, error) {
 // TODO https://github.com/hyperledger/aries-framework-go/issues/1105 Support to Add multiple
 //  recKeys to the Router
 func (s *Service) AddKey(connID, recKey string) error {
+	return s.updateKey(connID, recKey, add)
+}
+
+// RemoveKey removes a recKey of the agent from the registered router, ex. once the key was rotated. This method
+// blocks until a response is received from the router or it times out.
+func (s *Service) RemoveKey(connID, recKey string) error {
+	return s.updateKey(connID, recKey, remove)
+}
+
+func (s *Service) updateKey(connID, recKey, action string) error {
 	// check if router is already registered
 	err := s.ensureConnectionExists(connID)
 	if err != nil {
@@ -677,7 +958,7 @@ func (s *Service) AddKey(connID, recKey string) error {
 		Updates: []Update{
 			{
 				RecipientKey: recKey,
-				Action:       add,
+				Action:       action,
 			},
 		},
 	}
@@ -688,7 +969,7 @@ func (s *Service) AddKey(connID, recKey string) error {
 
 	select {
 	case keyUpdateResp := <-keyUpdateCh:
-		if err := processKeylistUpdateResp(recKey, keyUpdateResp); err != nil {
+		if err := processKeylistUpdateResp(recKey, action, keyUpdateResp); err != nil {
 			return err
 		}
 	case <-time.After(updateTimeout):
@@ -701,6 +982,70 @@ func (s *Service) AddKey(connID, recKey string) error {
 	return nil
 }
 
+// GetKeys returns the recipient keys the agent registered with the router. This method blocks until
+// the responses are received from the router or it times out.
+func (s *Service) GetKeys(connID string) ([]string, error) {
+	// check if router is already registered
+	err := s.ensureConnectionExists(connID)
+	if err != nil {
+		return nil, fmt.Errorf("ensure connection exists: %w", err)
+	}
+
+	conn, err := s.getConnection(connID)
+	if err != nil {
+		return nil, fmt.Errorf("get connection: %w", err)
+	}
+
+	var (
+		keys []string
+		page *Paginate
+	)
+
+	for {
+		keylist, err := s.queryKeylist(conn, page)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, key := range keylist.Keys {
+			keys = append(keys, key.RecipientKey)
+		}
+
+		// the router may return the keys by pages
+		if keylist.Pagination == nil || keylist.Pagination.Remaining <= 0 || len(keylist.Keys) == 0 {
+			return keys, nil
+		}
+
+		page = &Paginate{Offset: keylist.Pagination.Offset + len(keylist.Keys)}
+	}
+}
+
+func (s *Service) queryKeylist(conn *connection.Record, page *Paginate) (*Keylist, error) {
+	msgID := uuid.New().String()
+
+	keylistCh := make(chan *Keylist, 1)
+	s.setKeylistCh(msgID, keylistCh)
+
+	defer s.setKeylistCh(msgID, nil)
+
+	query := &KeylistQuery{
+		ID:       msgID,
+		Type:     KeylistQueryMsgType,
+		Paginate: page,
+	}
+
+	if err := s.outbound.SendToDID(query, conn.MyDID, conn.TheirDID); err != nil {
+		return nil, fmt.Errorf("send keylist query: %w", err)
+	}
+
+	select {
+	case keylist := <-keylistCh:
+		return keylist, nil
+	case <-time.After(updateTimeout):
+		return nil, errors.New("timeout waiting for keylist from the router")
+	}
+}
+
 // Config fetches the router config - endpoint and routingKeys.
 func (s *Service) Config(connID string) (*Config, error) {
 	// check if router is already registered
@@ -711,9 +1056,14 @@ func (s *Service) Config(connID string) (*Config, error) {
 	return s.getRouterConfig(connID)
 }
 
-func processKeylistUpdateResp(recKey string, keyUpdateResp *KeylistUpdateResponse) error {
+func processKeylistUpdateResp(recKey, action string, keyUpdateResp *KeylistUpdateResponse) error {
 	for _, result := range keyUpdateResp.Updated {
-		if result.RecipientKey == recKey && result.Action == add && result.Result != success {
+		if result.RecipientKey != recKey || result.Action != action {
+			continue
+		}
+
+		// removing a key which is not registered with the router leaves it in the expected state
+		if result.Result != success && !(action == remove && result.Result == noChange) {
 			return errors.New("failed to update the recipient key with the router")
 		}
 	}
@@ -739,6 +1089,24 @@ func (s *Service) setKeyUpdateResponseCh(msgID string, keyUpdateCh chan *Keylist
 	}
 }
 
+func (s *Service) getKeylistCh(msgID string) chan *Keylist {
+	s.keylistMapLock.RLock()
+	defer s.keylistMapLock.RUnlock()
+
+	return s.keylistMap[msgID]
+}
+
+func (s *Service) setKeylistCh(msgID string, keylistCh chan *Keylist) {
+	s.keylistMapLock.Lock()
+	defer s.keylistMapLock.Unlock()
+
+	if keylistCh == nil {
+		delete(s.keylistMap, msgID)
+	} else {
+		s.keylistMap[msgID] = keylistCh
+	}
+}
+
 func (s *Service) ensureConnectionExists(connID string) error {
 	_, err := s.routeStore.Get(fmt.Sprintf(routeConnIDDataKey, connID))
 	if errors.Is(err, storage.ErrDataNotFound) {
@@ -30,6 +30,7 @@ import (
 	mockstore "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
 	mockvdr "github.com/hyperledger/aries-framework-go/pkg/mock/vdr"
 	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
 )
 
 const (
@@ -393,8 +394,8 @@ func TestEvents(t *testing.T) {
 		}
 	})
 
-	t.Run("stopping inbound request event does not dispatch outbound grant", func(t *testing.T) {
-		dispatched := make(chan struct{})
+	t.Run("stopping inbound request event dispatches outbound deny", func(t *testing.T) {
+		dispatched := make(chan interface{})
 		svc, err := New(&mockprovider.Provider{
 			ServiceMap: map[string]interface{}{
 				messagepickup.MessagePickup: &mockmessagep.MockMessagePickupSvc{},
@@ -404,7 +405,7 @@ func TestEvents(t *testing.T) {
 			KMSValue:                          &mockkms.KeyManager{},
 			OutboundDispatcherValue: &mockdispatcher.MockOutbound{
 				ValidateSendToDID: func(msg interface{}, myDID, theirDID string) error {
-					dispatched <- struct{}{}
+					dispatched <- msg
 					return nil
 				},
 			},
@@ -427,9 +428,13 @@ func TestEvents(t *testing.T) {
 		}
 
 		select {
-		case <-dispatched:
-			require.Fail(t, "stopping the protocol flow should not result in an outbound message dispatch")
+		case msg := <-dispatched:
+			deny, ok := msg.(*Deny)
+			require.True(t, ok)
+			require.Equal(t, DenyMsgType, deny.Type)
+			require.Equal(t, "123", deny.ID)
 		case <-time.After(time.Second):
+			require.Fail(t, "timeout")
 		}
 	})
 
@@ -614,7 +619,7 @@ func TestServiceUpdateKeyListMsg(t *testing.T) {
 	t.Run("test service handle request msg - verify outbound message", func(t *testing.T) {
 		update := make(map[string]updateResult)
 		update["ABC"] = updateResult{action: add, result: success}
-		update["XYZ"] = updateResult{action: remove, result: serverError}
+		update["XYZ"] = updateResult{action: remove, result: noChange}
 		update[""] = updateResult{action: add, result: success}
 
 		svc, err := New(&mockprovider.Provider{
@@ -645,6 +650,7 @@ func TestServiceUpdateKeyListMsg(t *testing.T) {
 			},
 		})
 		require.NoError(t, err)
+		require.NoError(t, svc.grantRegistration(THEIRDID))
 
 		msgID := randomID()
 
@@ -661,6 +667,58 @@ func TestServiceUpdateKeyListMsg(t *testing.T) {
 	})
 }
 
+func TestServiceKeylistUpdateRemove(t *testing.T) {
+	s := map[string]mockstore.DBEntry{
+		dataKey("ABC"): {Value: []byte(THEIRDID)},
+		dataKey("DEF"): {Value: []byte("did:example:other")},
+	}
+
+	update := map[string]updateResult{
+		"ABC": {action: remove, result: success},
+		"DEF": {action: remove, result: clientError},
+		"XYZ": {action: remove, result: noChange},
+	}
+
+	svc, err := New(&mockprovider.Provider{
+		ServiceMap: map[string]interface{}{
+			messagepickup.MessagePickup: &mockmessagep.MockMessagePickupSvc{},
+		},
+		StorageProviderValue:              &mockstore.MockStoreProvider{Store: &mockstore.MockStore{Store: s}},
+		ProtocolStateStorageProviderValue: mockstore.NewMockStoreProvider(),
+		KMSValue:                          &mockkms.KeyManager{},
+		OutboundDispatcherValue: &mockdispatcher.MockOutbound{
+			ValidateSendToDID: func(msg interface{}, myDID, theirDID string) error {
+				updateRes, ok := msg.(*KeylistUpdateResponse)
+				require.True(t, ok)
+				require.Len(t, updateRes.Updated, len(update))
+
+				for _, v := range updateRes.Updated {
+					require.Equal(t, update[v.RecipientKey].action, v.Action)
+					require.Equal(t, update[v.RecipientKey].result, v.Result)
+				}
+
+				return nil
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, svc.grantRegistration(THEIRDID))
+
+	var updates []Update
+	for k, v := range update {
+		updates = append(updates, Update{
+			RecipientKey: k,
+			Action:       v.action,
+		})
+	}
+
+	err = svc.handleKeylistUpdate(generateKeyUpdateListMsgPayload(t, randomID(), updates), MYDID, THEIRDID)
+	require.NoError(t, err)
+
+	require.NotContains(t, s, dataKey("ABC"))
+	require.Contains(t, s, dataKey("DEF"))
+}
+
 func TestServiceKeylistUpdateResponseMsg(t *testing.T) {
 	t.Run("test service handle inbound key list update response msg - success", func(t *testing.T) {
 		svc, err := New(&mockprovider.Provider{
@@ -1225,6 +1283,67 @@ func TestKeylistUpdate(t *testing.T) {
 		require.Contains(t, err.Error(), "failed to update the recipient key with the router")
 	})
 
+	t.Run("test keylist remove - success", func(t *testing.T) {
+		keyUpdateMsg := make(chan KeylistUpdate)
+		recKey := "ojaosdjoajs123jkas"
+
+		s := make(map[string]mockstore.DBEntry)
+		svc, err := New(&mockprovider.Provider{
+			ServiceMap: map[string]interface{}{
+				messagepickup.MessagePickup: &mockmessagep.MockMessagePickupSvc{},
+			},
+			StorageProviderValue:              &mockstore.MockStoreProvider{Store: &mockstore.MockStore{Store: s}},
+			ProtocolStateStorageProviderValue: mockstore.NewMockStoreProvider(),
+			KMSValue:                          &mockkms.KeyManager{},
+			OutboundDispatcherValue: &mockdispatcher.MockOutbound{
+				ValidateSendToDID: func(msg interface{}, myDID, theirDID string) error {
+					request, ok := msg.(*KeylistUpdate)
+					require.True(t, ok)
+
+					keyUpdateMsg <- *request
+					return nil
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, svc.saveRouterConnectionID("conn"))
+
+		connRec := &connection.Record{
+			ConnectionID: "conn", MyDID: MYDID, TheirDID: THEIRDID, State: "complete",
+		}
+		connBytes, err := json.Marshal(connRec)
+		require.NoError(t, err)
+		s["conn_conn"] = mockstore.DBEntry{Value: connBytes}
+
+		for _, result := range []string{success, noChange, serverError} {
+			res := result
+
+			go func() {
+				updateMsg := <-keyUpdateMsg
+
+				require.Equal(t, remove, updateMsg.Updates[0].Action)
+
+				updates := []UpdateResponse{
+					{
+						RecipientKey: updateMsg.Updates[0].RecipientKey,
+						Action:       updateMsg.Updates[0].Action,
+						Result:       res,
+					},
+				}
+				require.NoError(t, svc.handleKeylistUpdateResponse(generateKeylistUpdateResponseMsgPayload(
+					t, updateMsg.ID, updates)))
+			}()
+
+			err = svc.RemoveKey("conn", recKey)
+			if res == serverError {
+				require.EqualError(t, err, "failed to update the recipient key with the router")
+			} else {
+				require.NoError(t, err)
+			}
+		}
+	})
+
 	t.Run("test keylist update - timeout error", func(t *testing.T) {
 		s := make(map[string]mockstore.DBEntry)
 		svc, err := New(&mockprovider.Provider{
@@ -1430,6 +1549,372 @@ func TestGetConnections(t *testing.T) {
 	})
 }
 
+func TestMediationPolicy(t *testing.T) {
+	t.Run("mediation denied by label", func(t *testing.T) {
+		client, _ := newMediatorPair(t, NewPolicy(WithDeniedLabels("mallory")), "mallory")
+
+		err := client.Register("conn")
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrMediationDenied))
+
+		_, err = client.Config("conn")
+		require.True(t, errors.Is(err, ErrRouterNotRegistered))
+	})
+
+	t.Run("mediation granted to allowed connection with limited keys", func(t *testing.T) {
+		client, mediator := newMediatorPair(t, NewPolicy(WithAllowedConnections("mediator-conn"), WithMaxKeys(1)), "")
+
+		require.NoError(t, client.Register("conn"))
+		require.NoError(t, client.AddKey("conn", "key-1"))
+		require.NoError(t, client.AddKey("conn", "key-1"))
+
+		err := client.AddKey("conn", "key-2")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to update the recipient key with the router")
+
+		keys, err := client.GetKeys("conn")
+		require.NoError(t, err)
+		require.Equal(t, []string{"key-1"}, keys)
+
+		require.NoError(t, client.RemoveKey("conn", "key-1"))
+		require.NoError(t, client.AddKey("conn", "key-2"))
+
+		keys, err = mediator.registeredKeys(MYDID)
+		require.NoError(t, err)
+		require.Equal(t, []string{"key-2"}, keys)
+	})
+}
+
+func TestKeylistQuery(t *testing.T) {
+	t.Run("keys are returned by pages", func(t *testing.T) {
+		allKeys := []string{"key-1", "key-2", "key-3"}
+
+		var svc *Service
+
+		s := make(map[string]mockstore.DBEntry)
+		svc, err := New(&mockprovider.Provider{
+			ServiceMap: map[string]interface{}{
+				messagepickup.MessagePickup: &mockmessagep.MockMessagePickupSvc{},
+			},
+			StorageProviderValue:              &mockstore.MockStoreProvider{Store: &mockstore.MockStore{Store: s}},
+			ProtocolStateStorageProviderValue: mockstore.NewMockStoreProvider(),
+			KMSValue:                          &mockkms.KeyManager{},
+			OutboundDispatcherValue: &mockdispatcher.MockOutbound{
+				ValidateSendToDID: func(msg interface{}, myDID, theirDID string) error {
+					query, ok := msg.(*KeylistQuery)
+					require.True(t, ok)
+
+					// the router returns two keys per page
+					page := query.Paginate
+					if page == nil {
+						page = &Paginate{}
+					}
+
+					keylist := &Keylist{ID: query.ID, Type: KeylistMsgType}
+					keylist.Keys, keylist.Pagination = paginate(allKeys, &Paginate{Offset: page.Offset, Limit: 2})
+
+					go func() {
+						require.NoError(t, svc.handleKeylist(service.NewDIDCommMsgMap(keylist)))
+					}()
+
+					return nil
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, svc.saveRouterConnectionID("conn"))
+		saveConnection(t, s, &connection.Record{
+			ConnectionID: "conn", MyDID: MYDID, TheirDID: THEIRDID, State: "complete",
+		})
+
+		keys, err := svc.GetKeys("conn")
+		require.NoError(t, err)
+		require.Equal(t, allKeys, keys)
+	})
+
+	t.Run("router not registered", func(t *testing.T) {
+		svc, err := New(&mockprovider.Provider{
+			ServiceMap: map[string]interface{}{
+				messagepickup.MessagePickup: &mockmessagep.MockMessagePickupSvc{},
+			},
+			StorageProviderValue:              mockstore.NewMockStoreProvider(),
+			ProtocolStateStorageProviderValue: mockstore.NewMockStoreProvider(),
+		})
+		require.NoError(t, err)
+
+		_, err = svc.GetKeys("conn")
+		require.True(t, errors.Is(err, ErrRouterNotRegistered))
+	})
+
+	t.Run("send keylist query error", func(t *testing.T) {
+		s := make(map[string]mockstore.DBEntry)
+		svc, err := New(&mockprovider.Provider{
+			ServiceMap: map[string]interface{}{
+				messagepickup.MessagePickup: &mockmessagep.MockMessagePickupSvc{},
+			},
+			StorageProviderValue:              &mockstore.MockStoreProvider{Store: &mockstore.MockStore{Store: s}},
+			ProtocolStateStorageProviderValue: mockstore.NewMockStoreProvider(),
+			OutboundDispatcherValue: &mockdispatcher.MockOutbound{
+				ValidateSendToDID: func(msg interface{}, myDID, theirDID string) error {
+					return errors.New("send error")
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, svc.saveRouterConnectionID("conn"))
+		saveConnection(t, s, &connection.Record{
+			ConnectionID: "conn", MyDID: MYDID, TheirDID: THEIRDID, State: "complete",
+		})
+
+		_, err = svc.GetKeys("conn")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "send keylist query: send error")
+	})
+
+	t.Run("invalid messages", func(t *testing.T) {
+		svc, err := New(&mockprovider.Provider{
+			ServiceMap: map[string]interface{}{
+				messagepickup.MessagePickup: &mockmessagep.MockMessagePickupSvc{},
+			},
+			StorageProviderValue:              mockstore.NewMockStoreProvider(),
+			ProtocolStateStorageProviderValue: mockstore.NewMockStoreProvider(),
+		})
+		require.NoError(t, err)
+
+		msg := &service.DIDCommMsgMap{"@id": map[int]int{}}
+
+		err = svc.handleKeylistQuery(msg, MYDID, THEIRDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "route keylist query message unmarshal")
+
+		err = svc.handleKeylist(msg)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "route keylist message unmarshal")
+	})
+}
+
+func TestPaginate(t *testing.T) {
+	keys := []string{"key-1", "key-2", "key-3"}
+
+	tests := []struct {
+		name       string
+		paginate   *Paginate
+		keys       []KeylistKey
+		pagination *Pagination
+	}{{
+		name: "all keys",
+		keys: []KeylistKey{{RecipientKey: "key-1"}, {RecipientKey: "key-2"}, {RecipientKey: "key-3"}},
+	}, {
+		name:       "first page",
+		paginate:   &Paginate{Limit: 2},
+		keys:       []KeylistKey{{RecipientKey: "key-1"}, {RecipientKey: "key-2"}},
+		pagination: &Pagination{Count: 2, Remaining: 1},
+	}, {
+		name:       "last page",
+		paginate:   &Paginate{Limit: 2, Offset: 2},
+		keys:       []KeylistKey{{RecipientKey: "key-3"}},
+		pagination: &Pagination{Count: 1, Offset: 2},
+	}, {
+		name:       "offset out of range",
+		paginate:   &Paginate{Offset: 5},
+		keys:       []KeylistKey{},
+		pagination: &Pagination{Offset: 3},
+	}}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			page, pagination := paginate(keys, tc.paginate)
+			require.Equal(t, tc.keys, page)
+			require.Equal(t, tc.pagination, pagination)
+		})
+	}
+}
+
+func TestRegistrationExpiry(t *testing.T) {
+	svc, err := New(&mockprovider.Provider{
+		ServiceMap: map[string]interface{}{
+			messagepickup.MessagePickup: &mockmessagep.MockMessagePickupSvc{},
+		},
+		StorageProviderValue:              mockstore.NewMockStoreProvider(),
+		ProtocolStateStorageProviderValue: mockstore.NewMockStoreProvider(),
+	}, WithPolicy(NewPolicy(WithIdleTimeout(10*time.Millisecond))))
+	require.NoError(t, err)
+
+	add := []Update{{RecipientKey: "key-1", Action: add}}
+
+	t.Run("forward to the expired registration", func(t *testing.T) {
+		require.NoError(t, svc.grantRegistration("did:example:1"))
+
+		_, err = svc.updateRouteKeys("did:example:1", add)
+		require.NoError(t, err)
+
+		time.Sleep(20 * time.Millisecond)
+
+		err = svc.handleForward(generateForwardMsgPayload(t, randomID(), "key-1", nil))
+		require.Error(t, err)
+		require.True(t, errors.Is(err, errRegistrationExpired))
+
+		_, err = svc.routeStore.Get(dataKey("key-1"))
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+	})
+
+	t.Run("expired registrations are removed on grant", func(t *testing.T) {
+		require.NoError(t, svc.grantRegistration("did:example:1"))
+
+		_, err = svc.updateRouteKeys("did:example:1", add)
+		require.NoError(t, err)
+
+		time.Sleep(20 * time.Millisecond)
+
+		require.NoError(t, svc.grantRegistration("did:example:2"))
+
+		_, err = svc.routeStore.Get(dataKey("key-1"))
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+		_, err = svc.getRegistration("did:example:1")
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+		reg, err := svc.getRegistration("did:example:2")
+		require.NoError(t, err)
+		require.False(t, reg.GrantedTime.IsZero())
+	})
+
+	t.Run("keys of the expired registration are dropped on query", func(t *testing.T) {
+		require.NoError(t, svc.grantRegistration("did:example:1"))
+
+		_, err = svc.updateRouteKeys("did:example:1", add)
+		require.NoError(t, err)
+
+		time.Sleep(20 * time.Millisecond)
+
+		_, err = svc.registeredKeys("did:example:1")
+		require.True(t, errors.Is(err, errRegistrationExpired))
+
+		_, err = svc.routeStore.Get(dataKey("key-1"))
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+		_, err = svc.updateRouteKeys("did:example:1", add)
+		require.True(t, errors.Is(err, errMediationNotGranted))
+	})
+}
+
+func TestKeylistWithoutGrant(t *testing.T) {
+	svc, err := New(&mockprovider.Provider{
+		ServiceMap: map[string]interface{}{
+			messagepickup.MessagePickup: &mockmessagep.MockMessagePickupSvc{},
+		},
+		StorageProviderValue:              mockstore.NewMockStoreProvider(),
+		ProtocolStateStorageProviderValue: mockstore.NewMockStoreProvider(),
+		OutboundDispatcherValue: &mockdispatcher.MockOutbound{
+			ValidateSendToDID: func(msg interface{}, myDID, theirDID string) error {
+				return errors.New("unexpected response")
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	t.Run("keylist update", func(t *testing.T) {
+		err = svc.handleKeylistUpdate(generateKeyUpdateListMsgPayload(t, randomID(), []Update{{
+			RecipientKey: "key-1",
+			Action:       add,
+		}}), MYDID, THEIRDID)
+		require.True(t, errors.Is(err, errMediationNotGranted))
+
+		_, err = svc.routeStore.Get(dataKey("key-1"))
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+		_, err = svc.getRegistration(THEIRDID)
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+	})
+
+	t.Run("keylist query", func(t *testing.T) {
+		msg := service.NewDIDCommMsgMap(&KeylistQuery{Type: KeylistQueryMsgType, ID: randomID()})
+
+		err = svc.handleKeylistQuery(msg, MYDID, THEIRDID)
+		require.True(t, errors.Is(err, errMediationNotGranted))
+	})
+}
+
+// newMediatorPair creates the client and the mediator services which deliver the messages to each other.
+func newMediatorPair(t *testing.T, policy Policy, label string) (*Service, *Service) {
+	t.Helper()
+
+	var client, mediator *Service
+
+	deliver := func(to func() *Service) func(msg interface{}, myDID, theirDID string) error {
+		return func(msg interface{}, myDID, theirDID string) error {
+			_, err := to().HandleInbound(service.NewDIDCommMsgMap(msg), theirDID, myDID)
+
+			return err
+		}
+	}
+
+	s := make(map[string]mockstore.DBEntry)
+	client, err := New(&mockprovider.Provider{
+		ServiceMap: map[string]interface{}{
+			messagepickup.MessagePickup: &mockmessagep.MockMessagePickupSvc{},
+		},
+		StorageProviderValue:              &mockstore.MockStoreProvider{Store: &mockstore.MockStore{Store: s}},
+		ProtocolStateStorageProviderValue: mockstore.NewMockStoreProvider(),
+		OutboundDispatcherValue: &mockdispatcher.MockOutbound{
+			ValidateSendToDID: deliver(func() *Service { return mediator }),
+		},
+	})
+	require.NoError(t, err)
+
+	saveConnection(t, s, &connection.Record{
+		ConnectionID: "conn", MyDID: MYDID, TheirDID: THEIRDID, State: "complete",
+	})
+
+	mediator, err = New(&mockprovider.Provider{
+		ServiceMap: map[string]interface{}{
+			messagepickup.MessagePickup: &mockmessagep.MockMessagePickupSvc{},
+		},
+		StorageProviderValue:              mockstore.NewMockStoreProvider(),
+		ProtocolStateStorageProviderValue: mockstore.NewMockStoreProvider(),
+		KMSValue:                          &mockkms.KeyManager{},
+		OutboundDispatcherValue: &mockdispatcher.MockOutbound{
+			ValidateSendToDID: deliver(func() *Service { return client }),
+		},
+	}, WithPolicy(policy))
+	require.NoError(t, err)
+
+	mediator.connectionLookup = &connectionsStub{
+		getConnIDByDIDs: func(myDID, theirDID string) (string, error) {
+			require.Equal(t, THEIRDID, myDID)
+			require.Equal(t, MYDID, theirDID)
+
+			return "mediator-conn", nil
+		},
+		getConnRecord: func(id string) (*connection.Record, error) {
+			return &connection.Record{ConnectionID: id, TheirLabel: label}, nil
+		},
+	}
+
+	events := make(chan service.DIDCommAction)
+	require.NoError(t, mediator.RegisterActionEvent(events))
+
+	go func() {
+		for e := range events {
+			e.Continue(&Options{})
+		}
+	}()
+
+	return client, mediator
+}
+
+func saveConnection(t *testing.T, s map[string]mockstore.DBEntry, record *connection.Record) {
+	t.Helper()
+
+	src, err := json.Marshal(record)
+	require.NoError(t, err)
+
+	s["conn_"+record.ConnectionID] = mockstore.DBEntry{Value: src}
+}
+
 func generateRequestMsgPayload(t *testing.T, id string) service.DIDCommMsg {
 	requestBytes, err := json.Marshal(&Request{
 		Type: RequestMsgType,
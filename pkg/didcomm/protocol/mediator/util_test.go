@@ -72,6 +72,27 @@ func TestAddKeyToRouter(t *testing.T) {
 	})
 }
 
+func TestRemoveKeyFromRouter(t *testing.T) {
+	t.Run("test remove key from router - success", func(t *testing.T) {
+		err := RemoveKeyFromRouter(&mockRouteSvc{}, "conn", ENDPOINT)
+		require.NoError(t, err)
+	})
+
+	t.Run("test remove key from router - router not registered", func(t *testing.T) {
+		err := RemoveKeyFromRouter(&mockRouteSvc{
+			RemoveKeyErr: ErrRouterNotRegistered,
+		}, "conn", ENDPOINT)
+		require.NoError(t, err)
+	})
+
+	t.Run("test remove key from router - router error", func(t *testing.T) {
+		err := RemoveKeyFromRouter(&mockRouteSvc{
+			RemoveKeyErr: errors.New("router error"),
+		}, "conn", ENDPOINT)
+		require.EqualError(t, err, "removeKey: router error")
+	})
+}
+
 type mockRouteSvc struct {
 	Connections    []string
 	ConnectionsErr error
@@ -79,6 +100,7 @@ type mockRouteSvc struct {
 	RoutingKeys    []string
 	ConfigErr      error
 	AddKeyErr      error
+	RemoveKeyErr   error
 }
 
 // AddKey adds agents recKey to the router.
@@ -86,6 +108,11 @@ func (m *mockRouteSvc) AddKey(connID, recKey string) error {
 	return m.AddKeyErr
 }
 
+// RemoveKey removes agents recKey from the router.
+func (m *mockRouteSvc) RemoveKey(connID, recKey string) error {
+	return m.RemoveKeyErr
+}
+
 // AddKey adds agents recKey to the router.
 func (m *mockRouteSvc) GetConnections() ([]string, error) {
 	return m.Connections, m.ConnectionsErr
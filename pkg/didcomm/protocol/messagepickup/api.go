@@ -13,4 +13,5 @@ import (
 // ProtocolService service interface for message pickup.
 type ProtocolService interface {
 	AddMessage(message *model.Envelope, theirDID string) error
+	AddMessageForRecipient(message *model.Envelope, theirDID, recipientKey string) error
 }
@@ -0,0 +1,250 @@
+/*
+Copyright Scoir Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package messagepickup
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+)
+
+// inboxTag tags the message records of the inbox.
+const inboxTag = "inbox"
+
+// inbox holds the state of the messages stored for the DID. The messages are stored as the separate records
+// tagged with the DID, so adding or removing a message doesn't rewrite the whole inbox.
+type inbox struct {
+	DID               string    `json:"DID"`
+	MessageCount      int       `json:"message_count"`
+	LastAddedTime     time.Time `json:"last_added_time,omitempty"`
+	LastDeliveredTime time.Time `json:"last_delivered_time,omitempty"`
+	LastRemovedTime   time.Time `json:"last_removed_time,omitempty"`
+	TotalSize         int       `json:"total_size,omitempty"`
+	// Messages are set only by the inboxes stored by the previous versions, they are moved to the separate
+	// records once the inbox is read.
+	Messages json.RawMessage `json:"messages,omitempty"`
+}
+
+// inboxMessage is the stored message of the inbox.
+type inboxMessage struct {
+	Message
+	RecipientKey string `json:"recipient_key,omitempty"`
+	Size         int    `json:"size"`
+}
+
+// DecodeMessages Messages.
+func (r *inbox) DecodeMessages() ([]*Message, error) {
+	var out []*Message
+
+	var err error
+
+	if r.Messages != nil {
+		err = json.Unmarshal(r.Messages, &out)
+	}
+
+	return out, err
+}
+
+func (s *Service) createInbox(theirDID string) (*inbox, error) {
+	msgs, err := s.getInbox(theirDID)
+	if err != nil && errors.Is(err, storage.ErrDataNotFound) {
+		msgs = &inbox{DID: theirDID}
+
+		msgBytes, e := json.Marshal(msgs)
+		if e != nil {
+			return nil, e
+		}
+
+		e = s.msgStore.Put(theirDID, msgBytes)
+		if e != nil {
+			return nil, e
+		}
+
+		return msgs, nil
+	}
+
+	return msgs, err
+}
+
+func (s *Service) getInbox(theirDID string) (*inbox, error) {
+	msgs := &inbox{DID: theirDID}
+
+	b, err := s.msgStore.Get(theirDID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(b, msgs)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(msgs.Messages) != 0 {
+		err = s.migrateInbox(msgs)
+		if err != nil {
+			return nil, fmt.Errorf("migrate inbox: %w", err)
+		}
+	}
+
+	return msgs, nil
+}
+
+func (s *Service) putInbox(theirDID string, o *inbox) error {
+	b, err := json.Marshal(o)
+	if err != nil {
+		return err
+	}
+
+	return s.msgStore.Put(theirDID, b)
+}
+
+// migrateInbox moves the messages of the inbox stored by the previous versions to the separate records.
+func (s *Service) migrateInbox(ibx *inbox) error {
+	msgs, err := ibx.DecodeMessages()
+	if err != nil {
+		return fmt.Errorf("decode messages: %w", err)
+	}
+
+	for _, msg := range msgs {
+		if msg.ID == "" {
+			msg.ID = uuid.New().String()
+		}
+
+		src, err := json.Marshal(msg.Message)
+		if err != nil {
+			return fmt.Errorf("marshal message: %w", err)
+		}
+
+		err = s.putMessage(ibx.DID, &inboxMessage{Message: *msg, Size: len(src)})
+		if err != nil {
+			return err
+		}
+	}
+
+	ibx.Messages = nil
+
+	return s.putInbox(ibx.DID, ibx)
+}
+
+func (s *Service) putMessage(theirDID string, msg *inboxMessage) error {
+	src, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("marshal message: %w", err)
+	}
+
+	return s.msgStore.Put(messageKey(theirDID, msg.ID), src, storage.Tag{Name: inboxTag, Value: inboxTagValue(theirDID)})
+}
+
+func (s *Service) getMessage(theirDID, id string) (*inboxMessage, error) {
+	src, err := s.msgStore.Get(messageKey(theirDID, id))
+	if err != nil {
+		return nil, err
+	}
+
+	msg := &inboxMessage{}
+
+	err = json.Unmarshal(src, msg)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal message: %w", err)
+	}
+
+	return msg, nil
+}
+
+// getMessages returns the messages of the inbox, the oldest first. Only the messages for the recipient key
+// are returned if it is provided.
+func (s *Service) getMessages(theirDID, recipientKey string) ([]*inboxMessage, error) {
+	records, err := s.msgStore.Query(fmt.Sprintf("%s:%s", inboxTag, inboxTagValue(theirDID)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query inbox: %w", err)
+	}
+
+	defer storage.Close(records, logger)
+
+	var msgs []*inboxMessage
+
+	more, err := records.Next()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get next record: %w", err)
+	}
+
+	for more {
+		value, err := records.Value()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get value from records: %w", err)
+		}
+
+		msg := &inboxMessage{}
+
+		err = json.Unmarshal(value, msg)
+		if err != nil {
+			return nil, fmt.Errorf("unmarshal message: %w", err)
+		}
+
+		if recipientKey == "" || msg.RecipientKey == recipientKey {
+			msgs = append(msgs, msg)
+		}
+
+		more, err = records.Next()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get next record: %w", err)
+		}
+	}
+
+	sort.SliceStable(msgs, func(i, j int) bool {
+		if msgs[i].AddedTime.Equal(msgs[j].AddedTime) {
+			return msgs[i].ID < msgs[j].ID
+		}
+
+		return msgs[i].AddedTime.Before(msgs[j].AddedTime)
+	})
+
+	return msgs, nil
+}
+
+// removeMessages deletes the messages and updates the state of the inbox, the caller stores the inbox.
+func (s *Service) removeMessages(ibx *inbox, msgs []*inboxMessage) error {
+	for _, msg := range msgs {
+		err := s.msgStore.Delete(messageKey(ibx.DID, msg.ID))
+		if err != nil {
+			return fmt.Errorf("delete message: %w", err)
+		}
+
+		ibx.MessageCount--
+		ibx.TotalSize -= msg.Size
+	}
+
+	if ibx.MessageCount < 0 {
+		ibx.MessageCount = 0
+	}
+
+	if ibx.TotalSize < 0 {
+		ibx.TotalSize = 0
+	}
+
+	if len(msgs) != 0 {
+		ibx.LastRemovedTime = time.Now()
+	}
+
+	return nil
+}
+
+func messageKey(theirDID, id string) string {
+	return fmt.Sprintf("msg_%s_%s", theirDID, id)
+}
+
+// inboxTagValue encodes the DID since the tag values can't contain the colons.
+func inboxTagValue(theirDID string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(theirDID))
+}
@@ -66,3 +66,66 @@ type Noop struct {
 	Type string `json:"@type,omitempty"`
 	ID   string `json:"@id,omitempty"`
 }
+
+// StatusRequestV2 sent by the recipient to the mediator to request a status message.
+// https://github.com/hyperledger/aries-rfcs/tree/master/features/0685-pickup-v2#status-request
+type StatusRequestV2 struct {
+	Type         string            `json:"@type,omitempty"`
+	ID           string            `json:"@id,omitempty"`
+	RecipientKey string            `json:"recipient_key,omitempty"`
+	Thread       *decorator.Thread `json:"~thread,omitempty"`
+}
+
+// StatusV2 details about the messages queued for the recipient.
+// https://github.com/hyperledger/aries-rfcs/tree/master/features/0685-pickup-v2#status
+type StatusV2 struct {
+	Type                 string            `json:"@type,omitempty"`
+	ID                   string            `json:"@id,omitempty"`
+	RecipientKey         string            `json:"recipient_key,omitempty"`
+	MessageCount         int               `json:"message_count"`
+	LongestWaitedSeconds int               `json:"longest_waited_seconds,omitempty"`
+	NewestReceivedTime   time.Time         `json:"newest_received_time,omitempty"`
+	OldestReceivedTime   time.Time         `json:"oldest_received_time,omitempty"`
+	TotalBytes           int               `json:"total_bytes,omitempty"`
+	LiveDelivery         bool              `json:"live_delivery"`
+	Thread               *decorator.Thread `json:"~thread,omitempty"`
+}
+
+// DeliveryRequest a request to have up to limit waiting messages sent inside a delivery message.
+// https://github.com/hyperledger/aries-rfcs/tree/master/features/0685-pickup-v2#delivery-request
+type DeliveryRequest struct {
+	Type         string            `json:"@type,omitempty"`
+	ID           string            `json:"@id,omitempty"`
+	Limit        int               `json:"limit"`
+	RecipientKey string            `json:"recipient_key,omitempty"`
+	Thread       *decorator.Thread `json:"~thread,omitempty"`
+}
+
+// Delivery a message that contains the waiting messages as the attachments, the ID of the attachment is the ID
+// of the message used for the acknowledgement.
+// https://github.com/hyperledger/aries-rfcs/tree/master/features/0685-pickup-v2#message-delivery
+type Delivery struct {
+	Type         string                 `json:"@type,omitempty"`
+	ID           string                 `json:"@id,omitempty"`
+	RecipientKey string                 `json:"recipient_key,omitempty"`
+	Attachments  []decorator.Attachment `json:"~attach"`
+	Thread       *decorator.Thread      `json:"~thread,omitempty"`
+}
+
+// MessagesReceived acknowledges the delivered messages, the mediator removes only these messages from the queue.
+// https://github.com/hyperledger/aries-rfcs/tree/master/features/0685-pickup-v2#messages-received
+type MessagesReceived struct {
+	Type          string            `json:"@type,omitempty"`
+	ID            string            `json:"@id,omitempty"`
+	MessageIDList []string          `json:"message_id_list"`
+	Thread        *decorator.Thread `json:"~thread,omitempty"`
+}
+
+// LiveDeliveryChange turns on or off the live delivery of the messages over the open connection.
+// https://github.com/hyperledger/aries-rfcs/tree/master/features/0685-pickup-v2#live-mode
+type LiveDeliveryChange struct {
+	Type         string            `json:"@type,omitempty"`
+	ID           string            `json:"@id,omitempty"`
+	LiveDelivery bool              `json:"live_delivery"`
+	Thread       *decorator.Thread `json:"~thread,omitempty"`
+}
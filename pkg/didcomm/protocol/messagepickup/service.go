@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package messagepickup
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"sync"
@@ -41,6 +42,21 @@ const (
 	BatchMsgType = Spec + "batch"
 	// NoopMsgType defines the protocol request-credential message type.
 	NoopMsgType = Spec + "noop"
+
+	// SpecV2 defines the protocol spec of the message pickup 2.0.
+	SpecV2 = "https://didcomm.org/messagepickup/2.0/"
+	// StatusRequestMsgTypeV2 defines the message pickup 2.0 status-request message type.
+	StatusRequestMsgTypeV2 = SpecV2 + "status-request"
+	// StatusMsgTypeV2 defines the message pickup 2.0 status message type.
+	StatusMsgTypeV2 = SpecV2 + "status"
+	// DeliveryRequestMsgTypeV2 defines the message pickup 2.0 delivery-request message type.
+	DeliveryRequestMsgTypeV2 = SpecV2 + "delivery-request"
+	// DeliveryMsgTypeV2 defines the message pickup 2.0 delivery message type.
+	DeliveryMsgTypeV2 = SpecV2 + "delivery"
+	// MessagesReceivedMsgTypeV2 defines the message pickup 2.0 messages-received message type.
+	MessagesReceivedMsgTypeV2 = SpecV2 + "messages-received"
+	// LiveDeliveryChangeMsgTypeV2 defines the message pickup 2.0 live-delivery-change message type.
+	LiveDeliveryChangeMsgTypeV2 = SpecV2 + "live-delivery-change"
 )
 
 const (
@@ -79,7 +95,14 @@ type Service struct {
 	batchMapLock     sync.RWMutex
 	statusMap        map[string]chan Status
 	statusMapLock    sync.RWMutex
-	inboxLock        *lockbox
+	// responseMap holds the channels waiting for the message pickup 2.0 responses by the thread ID,
+	// the delivery request is answered either by the delivery or by the status.
+	responseMap     map[string]chan service.DIDCommMsg
+	responseMapLock sync.RWMutex
+	// liveMap holds myDID of the connections in the live delivery mode by theirDID.
+	liveMap     map[string]string
+	liveMapLock sync.RWMutex
+	inboxLock   *lockbox
 }
 
 // New returns the messagepickup service.
@@ -102,6 +125,8 @@ func New(prov provider, tp transport.Provider) (*Service, error) {
 		msgHandler:       tp.InboundMessageHandler(),
 		batchMap:         make(map[string]chan Batch),
 		statusMap:        make(map[string]chan Status),
+		responseMap:      make(map[string]chan service.DIDCommMsg),
+		liveMap:          make(map[string]string),
 		inboxLock:        newLockBox(),
 	}
 
@@ -125,6 +150,16 @@ func (s *Service) HandleInbound(msg service.DIDCommMsg, myDID, theirDID string)
 			err = s.handleBatch(msg)
 		case NoopMsgType:
 			err = s.handleNoop(msg)
+		case StatusRequestMsgTypeV2:
+			err = s.handleStatusRequestV2(msg, myDID, theirDID)
+		case DeliveryRequestMsgTypeV2:
+			err = s.handleDeliveryRequest(msg, myDID, theirDID)
+		case MessagesReceivedMsgTypeV2:
+			err = s.handleMessagesReceived(msg, myDID, theirDID)
+		case LiveDeliveryChangeMsgTypeV2:
+			err = s.handleLiveDeliveryChange(msg, myDID, theirDID)
+		case StatusMsgTypeV2, DeliveryMsgTypeV2:
+			err = s.handleResponseV2(msg, myDID, theirDID)
 		}
 
 		if err != nil {
@@ -143,13 +178,24 @@ func (s *Service) HandleOutbound(_ service.DIDCommMsg, _, _ string) (string, err
 // Accept checks whether the service can handle the message type.
 func (s *Service) Accept(msgType string) bool {
 	switch msgType {
-	case BatchPickupMsgType, BatchMsgType, StatusRequestMsgType, StatusMsgType, NoopMsgType:
+	case BatchPickupMsgType, BatchMsgType, StatusRequestMsgType, StatusMsgType, NoopMsgType,
+		StatusRequestMsgTypeV2, StatusMsgTypeV2, DeliveryRequestMsgTypeV2, DeliveryMsgTypeV2,
+		MessagesReceivedMsgTypeV2, LiveDeliveryChangeMsgTypeV2:
 		return true
 	}
 
 	return false
 }
 
+// MessageTypes returns the message types handled by the service.
+func (s *Service) MessageTypes() []string {
+	return []string{
+		BatchPickupMsgType, BatchMsgType, StatusRequestMsgType, StatusMsgType, NoopMsgType,
+		StatusRequestMsgTypeV2, StatusMsgTypeV2, DeliveryRequestMsgTypeV2, DeliveryMsgTypeV2,
+		MessagesReceivedMsgTypeV2, LiveDeliveryChangeMsgTypeV2,
+	}
+}
+
 // Name of the service.
 func (s *Service) Name() string {
 	return MessagePickup
@@ -227,22 +273,19 @@ func (s *Service) handleBatchPickup(msg service.DIDCommMsg, myDID, theirDID stri
 		return fmt.Errorf("batch pickup get inbox: %w", err)
 	}
 
-	msgs, err := outbox.DecodeMessages()
+	stored, err := s.getMessages(theirDID, "")
 	if err != nil {
-		return fmt.Errorf("batch pickup decode : %w", err)
+		return fmt.Errorf("batch pickup get messages: %w", err)
 	}
 
-	end := len(msgs)
-	if request.BatchSize < end {
-		end = request.BatchSize
-	}
+	stored = limitMessages(stored, request.BatchSize)
 
 	outbox.LastDeliveredTime = time.Now()
-	outbox.LastRemovedTime = time.Now()
 
-	err = outbox.EncodeMessages(msgs[end:])
+	// the messages of the batch are removed right away, unlike the messages of the delivery
+	err = s.removeMessages(outbox, stored)
 	if err != nil {
-		return fmt.Errorf("batch pickup encode: %w", err)
+		return fmt.Errorf("batch pickup remove messages: %w", err)
 	}
 
 	err = s.putInbox(theirDID, outbox)
@@ -250,7 +293,10 @@ func (s *Service) handleBatchPickup(msg service.DIDCommMsg, myDID, theirDID stri
 		return fmt.Errorf("batch pick up put inbox: %w", err)
 	}
 
-	msgs = msgs[0:end]
+	msgs := make([]*Message, len(stored))
+	for i := range stored {
+		msgs[i] = &stored[i].Message
+	}
 
 	batch := &Batch{
 		Type:     BatchMsgType,
@@ -293,126 +339,368 @@ func (s *Service) handleNoop(msg service.DIDCommMsg) error {
 	return nil
 }
 
-type inbox struct {
-	DID               string          `json:"DID"`
-	MessageCount      int             `json:"message_count"`
-	LastAddedTime     time.Time       `json:"last_added_time,omitempty"`
-	LastDeliveredTime time.Time       `json:"last_delivered_time,omitempty"`
-	LastRemovedTime   time.Time       `json:"last_removed_time,omitempty"`
-	TotalSize         int             `json:"total_size,omitempty"`
-	Messages          json.RawMessage `json:"messages"`
+// AddMessage add message to inbox.
+func (s *Service) AddMessage(message *model.Envelope, theirDID string) error {
+	return s.AddMessageForRecipient(message, theirDID, "")
 }
 
-// DecodeMessages Messages.
-func (r *inbox) DecodeMessages() ([]*Message, error) {
-	var out []*Message
+// AddMessageForRecipient adds the message for the recipient key to the inbox of the DID. The message is pushed
+// right away if the DID turned on the live delivery, it stays in the inbox until the DID acknowledges it.
+func (s *Service) AddMessageForRecipient(message *model.Envelope, theirDID, recipientKey string) error {
+	msg, err := s.storeMessage(message, theirDID, recipientKey)
+	if err != nil {
+		return err
+	}
+
+	if myDID, ok := s.getLiveDID(theirDID); ok {
+		s.deliverLive(msg, myDID, theirDID)
+	}
+
+	return nil
+}
+
+func (s *Service) storeMessage(message *model.Envelope, theirDID, recipientKey string) (*inboxMessage, error) {
+	s.inboxLock.Lock(theirDID)
+	defer s.inboxLock.Unlock(theirDID)
+
+	outbox, err := s.createInbox(theirDID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to pull messages: %w", err)
+	}
+
+	src, err := json.Marshal(message)
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal message: %w", err)
+	}
+
+	msg := &inboxMessage{
+		Message: Message{
+			ID:        uuid.New().String(),
+			AddedTime: time.Now(),
+			Message:   message,
+		},
+		RecipientKey: recipientKey,
+		Size:         len(src),
+	}
+
+	err = s.putMessage(theirDID, msg)
+	if err != nil {
+		return nil, fmt.Errorf("unable to put message: %w", err)
+	}
+
+	outbox.MessageCount++
+	outbox.TotalSize += msg.Size
+	outbox.LastAddedTime = msg.AddedTime
+
+	err = s.putInbox(theirDID, outbox)
+	if err != nil {
+		return nil, fmt.Errorf("unable to put messages: %w", err)
+	}
+
+	return msg, nil
+}
 
-	var err error
+// deliverLive pushes the message to the DID in the live delivery mode, the message stays in the inbox
+// if it can't be delivered.
+func (s *Service) deliverLive(msg *inboxMessage, myDID, theirDID string) {
+	delivery, err := newDelivery([]*inboxMessage{msg}, msg.RecipientKey)
+	if err != nil {
+		logger.Errorf("live delivery to %s: %s", theirDID, err)
 
-	if r.Messages != nil {
-		err = json.Unmarshal(r.Messages, &out)
+		return
 	}
 
-	return out, err
+	err = s.outbound.SendToDID(delivery, myDID, theirDID)
+	if err != nil {
+		logger.Warnf("live delivery to %s failed, the message is kept in the inbox: %s", theirDID, err)
+	}
 }
 
-// EncodeMessages Messages.
-func (r *inbox) EncodeMessages(msg []*Message) error {
-	d, err := json.Marshal(msg)
+func (s *Service) handleStatusRequestV2(msg service.DIDCommMsg, myDID, theirDID string) error {
+	request := &StatusRequestV2{}
+
+	err := msg.Decode(request)
 	if err != nil {
-		return fmt.Errorf("unable to marshal: %w", err)
+		return fmt.Errorf("status request v2 message unmarshal: %w", err)
 	}
 
-	r.Messages = d
-	r.MessageCount = len(msg)
-	r.TotalSize = len(d)
+	s.inboxLock.Lock(theirDID)
+	status, err := s.statusV2(theirDID, request.RecipientKey)
+	s.inboxLock.Unlock(theirDID)
 
-	return nil
+	if err != nil {
+		return fmt.Errorf("status request v2: %w", err)
+	}
+
+	status.Thread = &decorator.Thread{ID: msg.ID()}
+
+	return s.outbound.SendToDID(status, myDID, theirDID)
 }
 
-// AddMessage add message to inbox.
-func (s *Service) AddMessage(message *model.Envelope, theirDID string) error {
+// statusV2 returns the status of the inbox, the caller holds the lock of the inbox.
+func (s *Service) statusV2(theirDID, recipientKey string) (*StatusV2, error) {
+	// the inbox is read first to move the messages stored by the previous versions to the separate records
+	_, err := s.createInbox(theirDID)
+	if err != nil {
+		return nil, fmt.Errorf("get inbox: %w", err)
+	}
+
+	msgs, err := s.getMessages(theirDID, recipientKey)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &StatusV2{
+		Type:         StatusMsgTypeV2,
+		ID:           uuid.New().String(),
+		RecipientKey: recipientKey,
+		MessageCount: len(msgs),
+		LiveDelivery: s.isLive(theirDID),
+	}
+
+	for _, msg := range msgs {
+		status.TotalBytes += msg.Size
+	}
+
+	if len(msgs) != 0 {
+		status.OldestReceivedTime = msgs[0].AddedTime
+		status.NewestReceivedTime = msgs[len(msgs)-1].AddedTime
+		status.LongestWaitedSeconds = int(time.Since(status.OldestReceivedTime).Seconds())
+	}
+
+	return status, nil
+}
+
+func (s *Service) handleDeliveryRequest(msg service.DIDCommMsg, myDID, theirDID string) error {
+	request := &DeliveryRequest{}
+
+	err := msg.Decode(request)
+	if err != nil {
+		return fmt.Errorf("delivery request message unmarshal: %w", err)
+	}
+
 	s.inboxLock.Lock(theirDID)
 	defer s.inboxLock.Unlock(theirDID)
 
 	outbox, err := s.createInbox(theirDID)
 	if err != nil {
-		return fmt.Errorf("unable to pull messages: %w", err)
+		return fmt.Errorf("delivery request get inbox: %w", err)
 	}
 
-	msgs, err := outbox.DecodeMessages()
+	msgs, err := s.getMessages(theirDID, request.RecipientKey)
 	if err != nil {
-		return fmt.Errorf("unable to decode messages: %w", err)
+		return fmt.Errorf("delivery request get messages: %w", err)
 	}
 
-	m := Message{
-		ID:        uuid.New().String(),
-		AddedTime: time.Now(),
-		Message:   message,
-	}
+	msgs = limitMessages(msgs, request.Limit)
+
+	// the status is sent instead of the delivery if there are no messages
+	if len(msgs) == 0 {
+		status, e := s.statusV2(theirDID, request.RecipientKey)
+		if e != nil {
+			return fmt.Errorf("delivery request: %w", e)
+		}
 
-	msgs = append(msgs, &m)
+		status.Thread = &decorator.Thread{ID: msg.ID()}
+
+		return s.outbound.SendToDID(status, myDID, theirDID)
+	}
 
 	outbox.LastDeliveredTime = time.Now()
-	outbox.LastRemovedTime = outbox.LastDeliveredTime
 
-	err = outbox.EncodeMessages(msgs)
+	err = s.putInbox(theirDID, outbox)
 	if err != nil {
-		return fmt.Errorf("unable to encode messages: %w", err)
+		return fmt.Errorf("delivery request put inbox: %w", err)
 	}
 
-	err = s.putInbox(theirDID, outbox)
+	delivery, err := newDelivery(msgs, request.RecipientKey)
 	if err != nil {
-		return fmt.Errorf("unable to put messages: %w", err)
+		return fmt.Errorf("delivery request: %w", err)
 	}
 
-	return nil
+	delivery.Thread = &decorator.Thread{ID: msg.ID()}
+
+	return s.outbound.SendToDID(delivery, myDID, theirDID)
 }
 
-func (s *Service) createInbox(theirDID string) (*inbox, error) {
-	msgs, err := s.getInbox(theirDID)
-	if err != nil && err == storage.ErrDataNotFound {
-		msgs = &inbox{DID: theirDID}
+func (s *Service) handleMessagesReceived(msg service.DIDCommMsg, myDID, theirDID string) error {
+	request := &MessagesReceived{}
 
-		msgBytes, e := json.Marshal(msgs)
-		if e != nil {
-			return nil, e
+	err := msg.Decode(request)
+	if err != nil {
+		return fmt.Errorf("messages received message unmarshal: %w", err)
+	}
+
+	s.inboxLock.Lock(theirDID)
+	defer s.inboxLock.Unlock(theirDID)
+
+	outbox, err := s.createInbox(theirDID)
+	if err != nil {
+		return fmt.Errorf("messages received get inbox: %w", err)
+	}
+
+	var msgs []*inboxMessage
+
+	for _, id := range request.MessageIDList {
+		m, e := s.getMessage(theirDID, id)
+		if errors.Is(e, storage.ErrDataNotFound) {
+			// already removed, ex. the acknowledgement was sent twice
+			continue
 		}
 
-		e = s.msgStore.Put(theirDID, msgBytes)
 		if e != nil {
-			return nil, e
+			return fmt.Errorf("messages received get message: %w", e)
 		}
 
-		return msgs, nil
+		msgs = append(msgs, m)
+	}
+
+	err = s.removeMessages(outbox, msgs)
+	if err != nil {
+		return fmt.Errorf("messages received: %w", err)
 	}
 
-	return msgs, err
+	err = s.putInbox(theirDID, outbox)
+	if err != nil {
+		return fmt.Errorf("messages received put inbox: %w", err)
+	}
+
+	status, err := s.statusV2(theirDID, "")
+	if err != nil {
+		return fmt.Errorf("messages received: %w", err)
+	}
+
+	status.Thread = &decorator.Thread{ID: msg.ID()}
+
+	return s.outbound.SendToDID(status, myDID, theirDID)
 }
 
-func (s *Service) getInbox(theirDID string) (*inbox, error) {
-	msgs := &inbox{DID: theirDID}
+func (s *Service) handleLiveDeliveryChange(msg service.DIDCommMsg, myDID, theirDID string) error {
+	request := &LiveDeliveryChange{}
 
-	b, err := s.msgStore.Get(theirDID)
+	err := msg.Decode(request)
 	if err != nil {
-		return nil, err
+		return fmt.Errorf("live delivery change message unmarshal: %w", err)
 	}
 
-	err = json.Unmarshal(b, msgs)
+	s.setLiveDID(theirDID, myDID, request.LiveDelivery)
+
+	s.inboxLock.Lock(theirDID)
+	status, err := s.statusV2(theirDID, "")
+	s.inboxLock.Unlock(theirDID)
+
 	if err != nil {
-		return nil, err
+		return fmt.Errorf("live delivery change: %w", err)
 	}
 
-	return msgs, nil
+	status.Thread = &decorator.Thread{ID: msg.ID()}
+
+	return s.outbound.SendToDID(status, myDID, theirDID)
 }
 
-func (s *Service) putInbox(theirDID string, o *inbox) error {
-	b, err := json.Marshal(o)
+// handleResponseV2 passes the status or the delivery to the request waiting for it. The delivery without
+// the request is pushed by the mediator in the live delivery mode, the messages are handled and acknowledged.
+func (s *Service) handleResponseV2(msg service.DIDCommMsg, myDID, theirDID string) error {
+	thID, err := msg.ThreadID()
+	if err == nil {
+		if responseCh := s.getResponseCh(thID); responseCh != nil {
+			select {
+			case responseCh <- msg:
+			default:
+				logger.Warnf("ignoring duplicate response msgID=%s thID=%s", msg.ID(), thID)
+			}
+
+			return nil
+		}
+	}
+
+	if msg.Type() != DeliveryMsgTypeV2 {
+		return nil
+	}
+
+	delivery := &Delivery{}
+
+	err = msg.Decode(delivery)
 	if err != nil {
-		return err
+		return fmt.Errorf("delivery message unmarshal: %w", err)
 	}
 
-	return s.msgStore.Put(theirDID, b)
+	return s.acknowledge(s.handleDelivery(delivery), myDID, theirDID)
+}
+
+// handleDelivery handles the messages of the delivery and returns the IDs of the handled ones.
+func (s *Service) handleDelivery(delivery *Delivery) []string {
+	var handled []string
+
+	for _, attachment := range delivery.Attachments {
+		src, err := base64.StdEncoding.DecodeString(attachment.Data.Base64)
+		if err != nil {
+			logger.Errorf("error decoding delivered message %s: %s", attachment.ID, err)
+
+			continue
+		}
+
+		err = s.handleEnvelope(src)
+		if err != nil {
+			logger.Errorf("error handling delivered message %s: %s", attachment.ID, err)
+
+			continue
+		}
+
+		handled = append(handled, attachment.ID)
+	}
+
+	return handled
+}
+
+// acknowledge sends the messages-received for the handled messages so the mediator removes them.
+func (s *Service) acknowledge(ids []string, myDID, theirDID string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	ack := &MessagesReceived{
+		Type:          MessagesReceivedMsgTypeV2,
+		ID:            uuid.New().String(),
+		MessageIDList: ids,
+	}
+
+	return s.outbound.SendToDID(ack, myDID, theirDID)
+}
+
+func newDelivery(msgs []*inboxMessage, recipientKey string) (*Delivery, error) {
+	delivery := &Delivery{
+		Type:         DeliveryMsgTypeV2,
+		ID:           uuid.New().String(),
+		RecipientKey: recipientKey,
+	}
+
+	for _, msg := range msgs {
+		src, err := json.Marshal(msg.Message.Message)
+		if err != nil {
+			return nil, fmt.Errorf("marshal message: %w", err)
+		}
+
+		delivery.Attachments = append(delivery.Attachments, decorator.Attachment{
+			ID:       msg.ID,
+			MimeType: "application/json",
+			Data:     decorator.AttachmentData{Base64: base64.StdEncoding.EncodeToString(src)},
+		})
+	}
+
+	return delivery, nil
+}
+
+func limitMessages(msgs []*inboxMessage, limit int) []*inboxMessage {
+	if limit < 0 {
+		limit = 0
+	}
+
+	if limit < len(msgs) {
+		return msgs[:limit]
+	}
+
+	return msgs
 }
 
 // StatusRequest request a status message.
@@ -525,6 +813,132 @@ func (s *Service) Noop(connectionID string) error {
 	return nil
 }
 
+// StatusRequestV2 requests the status of the messages queued by the mediator (message pickup 2.0), only
+// the messages for the recipient key are counted if it is provided.
+func (s *Service) StatusRequestV2(connectionID, recipientKey string) (*StatusV2, error) {
+	conn, err := s.getConnection(connectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &StatusRequestV2{
+		Type:         StatusRequestMsgTypeV2,
+		ID:           uuid.New().String(),
+		RecipientKey: recipientKey,
+	}
+
+	resp, err := s.sendAndWait(conn, req.ID, req)
+	if err != nil {
+		return nil, fmt.Errorf("status request v2: %w", err)
+	}
+
+	status := &StatusV2{}
+
+	err = resp.Decode(status)
+	if err != nil {
+		return nil, fmt.Errorf("status v2 message unmarshal: %w", err)
+	}
+
+	return status, nil
+}
+
+// DeliveryRequest requests up to limit messages queued by the mediator (message pickup 2.0) and handles them.
+// The handled messages are acknowledged, so the mediator removes only them and the rest are delivered again
+// if the delivery was interrupted. Returns the number of the handled messages.
+func (s *Service) DeliveryRequest(connectionID string, limit int, recipientKey string) (int, error) {
+	conn, err := s.getConnection(connectionID)
+	if err != nil {
+		return -1, err
+	}
+
+	req := &DeliveryRequest{
+		Type:         DeliveryRequestMsgTypeV2,
+		ID:           uuid.New().String(),
+		Limit:        limit,
+		RecipientKey: recipientKey,
+	}
+
+	resp, err := s.sendAndWait(conn, req.ID, req)
+	if err != nil {
+		return -1, fmt.Errorf("delivery request: %w", err)
+	}
+
+	// the mediator sends the status instead of the delivery if there are no messages
+	if resp.Type() != DeliveryMsgTypeV2 {
+		return 0, nil
+	}
+
+	delivery := &Delivery{}
+
+	err = resp.Decode(delivery)
+	if err != nil {
+		return -1, fmt.Errorf("delivery message unmarshal: %w", err)
+	}
+
+	handled := s.handleDelivery(delivery)
+
+	err = s.acknowledge(handled, conn.MyDID, conn.TheirDID)
+	if err != nil {
+		return -1, fmt.Errorf("send messages received: %w", err)
+	}
+
+	return len(handled), nil
+}
+
+// LiveDeliveryChange turns on or off the live delivery mode (message pickup 2.0). In the live delivery mode
+// the mediator pushes the messages over the open connection (ex. WebSocket) as soon as they arrive.
+func (s *Service) LiveDeliveryChange(connectionID string, liveDelivery bool) error {
+	conn, err := s.getConnection(connectionID)
+	if err != nil {
+		return err
+	}
+
+	req := &LiveDeliveryChange{
+		Type:         LiveDeliveryChangeMsgTypeV2,
+		ID:           uuid.New().String(),
+		LiveDelivery: liveDelivery,
+	}
+
+	resp, err := s.sendAndWait(conn, req.ID, req)
+	if err != nil {
+		return fmt.Errorf("live delivery change: %w", err)
+	}
+
+	status := &StatusV2{}
+
+	err = resp.Decode(status)
+	if err != nil {
+		return fmt.Errorf("status v2 message unmarshal: %w", err)
+	}
+
+	if status.LiveDelivery != liveDelivery {
+		return errors.New("live delivery mode was not changed by the mediator")
+	}
+
+	return nil
+}
+
+// sendAndWait sends the message pickup 2.0 request and waits for the response on the thread of the request.
+func (s *Service) sendAndWait(conn *connection.Record, msgID string, msg interface{}) (service.DIDCommMsg, error) {
+	// buffered, so the late response doesn't block the handler
+	responseCh := make(chan service.DIDCommMsg, 1)
+	s.setResponseCh(msgID, responseCh)
+
+	defer s.setResponseCh(msgID, nil)
+
+	if err := s.outbound.SendToDID(msg, conn.MyDID, conn.TheirDID); err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+
+	select {
+	case resp := <-responseCh:
+		return resp, nil
+	// TODO https://github.com/hyperledger/aries-framework-go/issues/1134 configure this timeout at decorator level
+	case <-time.After(updateTimeout):
+		return nil, errors.New("timeout waiting for response")
+	}
+}
+
 func (s *Service) getConnection(routerConnID string) (*connection.Record, error) {
 	conn, err := s.connectionLookup.GetConnectionRecord(routerConnID)
 	if err != nil {
@@ -574,12 +988,60 @@ func (s *Service) setStatusCh(msgID string, statusCh chan Status) {
 	}
 }
 
+func (s *Service) getResponseCh(thID string) chan service.DIDCommMsg {
+	s.responseMapLock.RLock()
+	defer s.responseMapLock.RUnlock()
+
+	return s.responseMap[thID]
+}
+
+func (s *Service) setResponseCh(thID string, responseCh chan service.DIDCommMsg) {
+	s.responseMapLock.Lock()
+	defer s.responseMapLock.Unlock()
+
+	if responseCh == nil {
+		delete(s.responseMap, thID)
+	} else {
+		s.responseMap[thID] = responseCh
+	}
+}
+
+func (s *Service) getLiveDID(theirDID string) (string, bool) {
+	s.liveMapLock.RLock()
+	defer s.liveMapLock.RUnlock()
+
+	myDID, ok := s.liveMap[theirDID]
+
+	return myDID, ok
+}
+
+func (s *Service) isLive(theirDID string) bool {
+	_, ok := s.getLiveDID(theirDID)
+
+	return ok
+}
+
+func (s *Service) setLiveDID(theirDID, myDID string, live bool) {
+	s.liveMapLock.Lock()
+	defer s.liveMapLock.Unlock()
+
+	if live {
+		s.liveMap[theirDID] = myDID
+	} else {
+		delete(s.liveMap, theirDID)
+	}
+}
+
 func (s *Service) handle(msg *Message) error {
 	d, err := json.Marshal(msg.Message)
 	if err != nil {
 		return fmt.Errorf("failed to marshal msg: %w", err)
 	}
 
+	return s.handleEnvelope(d)
+}
+
+func (s *Service) handleEnvelope(d []byte) error {
 	unpackMsg, err := s.packager.UnpackMessage(d)
 	if err != nil {
 		return fmt.Errorf("failed to unpack msg: %w", err)
@@ -594,7 +1056,7 @@ func (s *Service) handle(msg *Message) error {
 
 	messageHandler := s.msgHandler
 
-	err = messageHandler(unpackMsg.Message, unpackMsg.ToDID, unpackMsg.FromDID)
+	err = messageHandler(unpackMsg)
 	if err != nil {
 		return fmt.Errorf("incoming msg processing failed: %w", err)
 	}
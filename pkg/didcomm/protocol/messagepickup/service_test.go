@@ -18,6 +18,7 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/model"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	commontransport "github.com/hyperledger/aries-framework-go/pkg/didcomm/common/transport"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/transport"
 	mockdispatcher "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/dispatcher"
 	mockprovider "github.com/hyperledger/aries-framework-go/pkg/mock/provider"
@@ -971,6 +972,297 @@ func TestGetConnection(t *testing.T) {
 	})
 }
 
+func TestPickupV2(t *testing.T) {
+	mediatorOutbound := &mockdispatcher.MockOutbound{}
+	recipientOutbound := &mockdispatcher.MockOutbound{}
+
+	mediator, err := New(&mockprovider.Provider{
+		StorageProviderValue:              mockstore.NewMockStoreProvider(),
+		ProtocolStateStorageProviderValue: mockstore.NewMockStoreProvider(),
+		OutboundDispatcherValue:           mediatorOutbound,
+	}, &mockTransportProvider{packagerValue: &mockPackager{}})
+	require.NoError(t, err)
+
+	var handleErr error
+
+	handled := make(chan struct{}, 10)
+
+	recipientProvider := &mockprovider.Provider{
+		StorageProviderValue:              mockstore.NewMockStoreProvider(),
+		ProtocolStateStorageProviderValue: mockstore.NewMockStoreProvider(),
+		OutboundDispatcherValue:           recipientOutbound,
+	}
+
+	recipient, err := New(recipientProvider, &mockTransportProvider{
+		packagerValue: &mockPackager{},
+		inboundHandler: func(*commontransport.Envelope) error {
+			if handleErr != nil {
+				return handleErr
+			}
+
+			handled <- struct{}{}
+
+			return nil
+		},
+	})
+	require.NoError(t, err)
+
+	r, err := connection.NewRecorder(recipientProvider)
+	require.NoError(t, err)
+	require.NoError(t, r.SaveConnectionRecord(&connection.Record{
+		ConnectionID: "conn", MyDID: THEIRDID, TheirDID: MYDID, State: "completed",
+	}))
+
+	// the mediator and the recipient deliver the messages to each other
+	mediatorOutbound.ValidateSendToDID = func(msg interface{}, myDID, theirDID string) error {
+		_, e := recipient.HandleInbound(toDIDCommMsg(t, msg), theirDID, myDID)
+
+		return e
+	}
+	recipientOutbound.ValidateSendToDID = func(msg interface{}, myDID, theirDID string) error {
+		_, e := mediator.HandleInbound(toDIDCommMsg(t, msg), theirDID, myDID)
+
+		return e
+	}
+
+	messageCount := func() int {
+		mediator.inboxLock.Lock(THEIRDID)
+		defer mediator.inboxLock.Unlock(THEIRDID)
+
+		ibx, e := mediator.getInbox(THEIRDID)
+		require.NoError(t, e)
+
+		return ibx.MessageCount
+	}
+
+	require.NoError(t, mediator.AddMessageForRecipient(&model.Envelope{CipherText: "1"}, THEIRDID, "key-1"))
+	require.NoError(t, mediator.AddMessageForRecipient(&model.Envelope{CipherText: "2"}, THEIRDID, "key-2"))
+	require.NoError(t, mediator.AddMessage(&model.Envelope{CipherText: "3"}, THEIRDID))
+
+	t.Run("status", func(t *testing.T) {
+		status, err := recipient.StatusRequestV2("conn", "")
+		require.NoError(t, err)
+		require.Equal(t, 3, status.MessageCount)
+		require.False(t, status.LiveDelivery)
+		require.False(t, status.OldestReceivedTime.After(status.NewestReceivedTime))
+
+		status, err = recipient.StatusRequestV2("conn", "key-1")
+		require.NoError(t, err)
+		require.Equal(t, 1, status.MessageCount)
+		require.Equal(t, "key-1", status.RecipientKey)
+	})
+
+	t.Run("interrupted delivery keeps the messages", func(t *testing.T) {
+		handleErr = errors.New("handle error")
+		defer func() { handleErr = nil }()
+
+		count, err := recipient.DeliveryRequest("conn", 2, "")
+		require.NoError(t, err)
+		require.Equal(t, 0, count)
+		require.Equal(t, 3, messageCount())
+	})
+
+	t.Run("delivery removes only the acknowledged messages", func(t *testing.T) {
+		count, err := recipient.DeliveryRequest("conn", 2, "")
+		require.NoError(t, err)
+		require.Equal(t, 2, count)
+
+		require.Eventually(t, func() bool { return messageCount() == 1 }, time.Second, 10*time.Millisecond)
+
+		status, err := recipient.StatusRequestV2("conn", "")
+		require.NoError(t, err)
+		require.Equal(t, 1, status.MessageCount)
+
+		count, err = recipient.DeliveryRequest("conn", 10, "")
+		require.NoError(t, err)
+		require.Equal(t, 1, count)
+
+		require.Eventually(t, func() bool { return messageCount() == 0 }, time.Second, 10*time.Millisecond)
+
+		// the status is sent instead of the delivery if there are no messages
+		count, err = recipient.DeliveryRequest("conn", 10, "")
+		require.NoError(t, err)
+		require.Equal(t, 0, count)
+	})
+
+	t.Run("live delivery", func(t *testing.T) {
+		for len(handled) > 0 {
+			<-handled
+		}
+
+		require.NoError(t, recipient.LiveDeliveryChange("conn", true))
+		require.True(t, mediator.isLive(THEIRDID))
+
+		require.NoError(t, mediator.AddMessage(&model.Envelope{CipherText: "4"}, THEIRDID))
+
+		select {
+		case <-handled:
+		case <-time.After(time.Second):
+			require.Fail(t, "the message was not pushed")
+		}
+
+		require.Eventually(t, func() bool { return messageCount() == 0 }, time.Second, 10*time.Millisecond)
+
+		require.NoError(t, recipient.LiveDeliveryChange("conn", false))
+		require.False(t, mediator.isLive(THEIRDID))
+
+		require.NoError(t, mediator.AddMessage(&model.Envelope{CipherText: "5"}, THEIRDID))
+		require.Equal(t, 1, messageCount())
+	})
+
+	t.Run("live delivery failure keeps the message", func(t *testing.T) {
+		mediator.setLiveDID(THEIRDID, MYDID, true)
+		defer mediator.setLiveDID(THEIRDID, MYDID, false)
+
+		send := mediatorOutbound.ValidateSendToDID
+		mediatorOutbound.ValidateSendToDID = func(interface{}, string, string) error {
+			return errors.New("connection closed")
+		}
+
+		defer func() { mediatorOutbound.ValidateSendToDID = send }()
+
+		require.NoError(t, mediator.AddMessage(&model.Envelope{CipherText: "6"}, THEIRDID))
+		require.Equal(t, 2, messageCount())
+	})
+}
+
+func TestPickupV2Errors(t *testing.T) {
+	t.Run("message unmarshal errors", func(t *testing.T) {
+		svc, err := getService()
+		require.NoError(t, err)
+
+		msg := &service.DIDCommMsgMap{"@id": map[int]int{}}
+
+		require.Contains(t, svc.handleStatusRequestV2(msg, MYDID, THEIRDID).Error(),
+			"status request v2 message unmarshal")
+		require.Contains(t, svc.handleDeliveryRequest(msg, MYDID, THEIRDID).Error(),
+			"delivery request message unmarshal")
+		require.Contains(t, svc.handleMessagesReceived(msg, MYDID, THEIRDID).Error(),
+			"messages received message unmarshal")
+		require.Contains(t, svc.handleLiveDeliveryChange(msg, MYDID, THEIRDID).Error(),
+			"live delivery change message unmarshal")
+	})
+
+	t.Run("get inbox error", func(t *testing.T) {
+		mockStore := mockstore.NewMockStoreProvider()
+		svc, err := New(&mockprovider.Provider{
+			StorageProviderValue:              mockStore,
+			ProtocolStateStorageProviderValue: mockstore.NewMockStoreProvider(),
+		}, &mockTransportProvider{packagerValue: &mockPackager{}})
+		require.NoError(t, err)
+
+		mockStore.Store.ErrGet = errors.New("error get inbox")
+
+		msg := service.NewDIDCommMsgMap(&DeliveryRequest{Type: DeliveryRequestMsgTypeV2, ID: "id", Limit: 1})
+
+		err = svc.handleDeliveryRequest(msg, MYDID, THEIRDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "error get inbox")
+
+		err = svc.handleStatusRequestV2(msg, MYDID, THEIRDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "error get inbox")
+	})
+
+	t.Run("connection errors", func(t *testing.T) {
+		svc, err := getService()
+		require.NoError(t, err)
+
+		expected := errors.New("get error")
+		svc.connectionLookup = &connectionsStub{
+			getConnRecord: func(string) (*connection.Record, error) {
+				return nil, expected
+			},
+		}
+
+		_, err = svc.StatusRequestV2("conn", "")
+		require.True(t, errors.Is(err, expected))
+
+		_, err = svc.DeliveryRequest("conn", 1, "")
+		require.True(t, errors.Is(err, expected))
+
+		require.True(t, errors.Is(svc.LiveDeliveryChange("conn", true), expected))
+	})
+
+	t.Run("duplicate response doesn't block", func(t *testing.T) {
+		svc, err := getService()
+		require.NoError(t, err)
+
+		responseCh := make(chan service.DIDCommMsg, 1)
+		svc.setResponseCh("thid", responseCh)
+
+		msg := service.NewDIDCommMsgMap(&StatusV2{Type: StatusMsgTypeV2, ID: "id", Thread: &decorator.Thread{ID: "thid"}})
+
+		require.NoError(t, svc.handleResponseV2(msg, MYDID, THEIRDID))
+		require.NoError(t, svc.handleResponseV2(msg, MYDID, THEIRDID))
+		require.Len(t, responseCh, 1)
+	})
+
+	t.Run("send errors", func(t *testing.T) {
+		provider := &mockprovider.Provider{
+			StorageProviderValue:              mockstore.NewMockStoreProvider(),
+			ProtocolStateStorageProviderValue: mockstore.NewMockStoreProvider(),
+			OutboundDispatcherValue:           &mockdispatcher.MockOutbound{SendErr: errors.New("send error")},
+		}
+
+		r, err := connection.NewRecorder(provider)
+		require.NoError(t, err)
+		require.NoError(t, r.SaveConnectionRecord(&connection.Record{
+			ConnectionID: "conn", MyDID: MYDID, TheirDID: THEIRDID, State: "completed",
+		}))
+
+		svc, err := New(provider, &mockTransportProvider{packagerValue: &mockPackager{}})
+		require.NoError(t, err)
+
+		_, err = svc.StatusRequestV2("conn", "")
+		require.EqualError(t, err, "status request v2: send request: send error")
+
+		_, err = svc.DeliveryRequest("conn", 1, "")
+		require.EqualError(t, err, "delivery request: send request: send error")
+
+		require.EqualError(t, svc.LiveDeliveryChange("conn", true), "live delivery change: send request: send error")
+	})
+}
+
+func TestMigrateInbox(t *testing.T) {
+	svc, err := getService()
+	require.NoError(t, err)
+
+	b, err := json.Marshal(inbox{
+		DID:          THEIRDID,
+		MessageCount: 3,
+		Messages:     []byte(`[{"id": "8910"}, {"id": "8911"}, {}]`),
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, svc.msgStore.Put(THEIRDID, b))
+
+	ibx, err := svc.getInbox(THEIRDID)
+	require.NoError(t, err)
+	require.Empty(t, ibx.Messages)
+	require.Equal(t, 3, ibx.MessageCount)
+
+	msgs, err := svc.getMessages(THEIRDID, "")
+	require.NoError(t, err)
+	require.Len(t, msgs, 3)
+
+	b, err = svc.msgStore.Get(THEIRDID)
+	require.NoError(t, err)
+	require.NotContains(t, string(b), "8910")
+}
+
+func toDIDCommMsg(t *testing.T, msg interface{}) service.DIDCommMsgMap {
+	t.Helper()
+
+	src, err := json.Marshal(msg)
+	require.NoError(t, err)
+
+	didCommMsg, err := service.ParseDIDCommMsgMap(src)
+	require.NoError(t, err)
+
+	return didCommMsg
+}
+
 func getService() (*Service, error) {
 	svc, err := New(&mockprovider.Provider{
 		StorageProviderValue:              mockstore.NewMockStoreProvider(),
@@ -985,7 +1277,8 @@ func getService() (*Service, error) {
 
 // mockProvider mock provider.
 type mockTransportProvider struct {
-	packagerValue commontransport.Packager
+	packagerValue  commontransport.Packager
+	inboundHandler transport.InboundMessageHandler
 }
 
 func (p *mockTransportProvider) Packager() commontransport.Packager {
@@ -993,8 +1286,12 @@ func (p *mockTransportProvider) Packager() commontransport.Packager {
 }
 
 func (p *mockTransportProvider) InboundMessageHandler() transport.InboundMessageHandler {
-	return func(message []byte, myDID, theirDID string) error {
-		logger.Debugf("message received is %s", message)
+	if p.inboundHandler != nil {
+		return p.inboundHandler
+	}
+
+	return func(envelope *commontransport.Envelope) error {
+		logger.Debugf("message received is %s", envelope.Message)
 		return nil
 	}
 }
@@ -0,0 +1,397 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package issuecredential
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/piprate/json-gold/ld"
+
+	"github.com/hyperledger/aries-framework-go/pkg/crypto"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/issuecredential"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/jsonld"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/signer"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/suite"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/suite/ed25519signature2018"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/suite/jsonwebsignature2020"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+)
+
+const (
+	stateNameRequestReceived = "request-received"
+
+	// LDProofVCDetailFormat is the format of the attachments requesting a linked data proof credential
+	// (Aries RFC 0593).
+	LDProofVCDetailFormat = "aries/ld-proof-vc-detail@v1.0"
+	// LDProofVCFormat is the format of the attachments holding an issued linked data proof credential.
+	LDProofVCFormat = "aries/ld-proof-vc@v1.0"
+
+	// Ed25519Signature2018 is the Ed25519 signature suite.
+	Ed25519Signature2018 = "Ed25519Signature2018"
+	// JSONWebSignature2020 is the JSON web signature suite.
+	JSONWebSignature2020 = "JsonWebSignature2020"
+
+	assertionMethodPurpose    = "assertionMethod"
+	mimeTypeApplicationLdJSON = "application/ld+json"
+)
+
+// LDProofVCDetail is the payload of the aries/ld-proof-vc-detail@v1.0 attachments.
+type LDProofVCDetail struct {
+	// Credential is the requested credential without a proof.
+	Credential json.RawMessage `json:"credential"`
+	// Options are the requested proof options.
+	Options *LDProofVCDetailOptions `json:"options"`
+}
+
+// LDProofVCDetailOptions are the proof options requested by the holder.
+type LDProofVCDetailOptions struct {
+	ProofPurpose     string          `json:"proofPurpose,omitempty"`
+	Created          string          `json:"created,omitempty"`
+	Domain           string          `json:"domain,omitempty"`
+	Challenge        string          `json:"challenge,omitempty"`
+	CredentialStatus json.RawMessage `json:"credentialStatus,omitempty"`
+	ProofType        string          `json:"proofType"`
+}
+
+// IssuerProvider contains dependencies for the IssueLDProofCredentials middleware function.
+type IssuerProvider interface {
+	KMS() kms.KeyManager
+	Crypto() crypto.Crypto
+}
+
+// IssuerOpt represents option function for the IssueLDProofCredentials middleware.
+type IssuerOpt func(o *issuerOptions)
+
+// WithKeyID sets the ID of the KMS key signing the credentials, the fragment of the verification method is used
+// by default.
+func WithKeyID(kid string) IssuerOpt {
+	return func(o *issuerOptions) {
+		o.kid = kid
+	}
+}
+
+// WithSignatureType sets the signature suite of the proofs, Ed25519Signature2018 is used by default.
+func WithSignatureType(signatureType string) IssuerOpt {
+	return func(o *issuerOptions) {
+		o.signatureType = signatureType
+	}
+}
+
+// WithIssuerPolicy sets the policy the requested credentials are checked against, every credential is issued
+// by default.
+func WithIssuerPolicy(p IssuerPolicy) IssuerOpt {
+	return func(o *issuerOptions) {
+		o.policy = p
+	}
+}
+
+// WithJSONLDDocumentLoader sets the loader of the JSON-LD contexts of the requested credentials.
+func WithJSONLDDocumentLoader(loader ld.DocumentLoader) IssuerOpt {
+	return func(o *issuerOptions) {
+		o.documentLoader = loader
+	}
+}
+
+type issuerOptions struct {
+	verificationMethod string
+	kid                string
+	signatureType      string
+	policy             IssuerPolicy
+	documentLoader     ld.DocumentLoader
+}
+
+// IssueLDProofCredentials the helper function for the issue credential protocol which issues the linked data proof
+// credentials requested in the aries/ld-proof-vc-detail@v1.0 format. The Issuer accepts the request with an empty
+// IssueCredential message, the requested credentials are checked against the issuer policy, signed with the given
+// verification method and attached to the message in the aries/ld-proof-vc@v1.0 format.
+func IssueLDProofCredentials(p IssuerProvider, verificationMethod string,
+	opts ...IssuerOpt) issuecredential.Middleware {
+	issuer := newIssuer(p, verificationMethod, opts...)
+
+	return func(next issuecredential.Handler) issuecredential.Handler {
+		return issuecredential.HandlerFunc(func(metadata issuecredential.Metadata) error {
+			if metadata.StateName() != stateNameRequestReceived {
+				return next.Handle(metadata)
+			}
+
+			request := issuecredential.RequestCredential{}
+			if err := metadata.Message().Decode(&request); err != nil {
+				return fmt.Errorf("decode: %w", err)
+			}
+
+			if metadata.IssueCredential() == nil ||
+				!hasFormat(request.Formats, LDProofVCDetailFormat) ||
+				hasFormat(metadata.IssueCredential().Formats, LDProofVCFormat) {
+				return next.Handle(metadata)
+			}
+
+			properties := metadata.Properties()
+
+			// nolint: errcheck
+			myDID, _ := properties[myDIDKey].(string)
+			// nolint: errcheck
+			theirDID, _ := properties[theirDIDKey].(string)
+
+			msg := metadata.IssueCredential()
+
+			for _, format := range request.Formats {
+				if format.Format != LDProofVCDetailFormat {
+					continue
+				}
+
+				src, err := getAttachment(request.RequestsAttach, format.AttachID)
+				if err != nil {
+					return fmt.Errorf("get attachment %s: %w", format.AttachID, err)
+				}
+
+				vc, err := issuer.issue(src, myDID, theirDID)
+				if err != nil {
+					return fmt.Errorf("issue credential: %w", err)
+				}
+
+				attachID := uuid.New().String()
+
+				msg.Formats = append(msg.Formats, issuecredential.Format{AttachID: attachID, Format: LDProofVCFormat})
+				msg.CredentialsAttach = append(msg.CredentialsAttach, decorator.Attachment{
+					ID:       attachID,
+					MimeType: mimeTypeApplicationLdJSON,
+					Data:     decorator.AttachmentData{JSON: vc},
+				})
+			}
+
+			return next.Handle(metadata)
+		})
+	}
+}
+
+// AutoAcceptLDProofRequests returns the function accepting the credential requests without raising action events
+// (see issuecredential.WithAutoAccept). A request is accepted when all of its attachments request a linked data
+// proof credential in the aries/ld-proof-vc-detail@v1.0 format and every credential is approved by the issuer
+// policy, the credentials are then issued by the IssueLDProofCredentials middleware given the same options.
+// The issuer policy has to be created by NewIssuerPolicy with WithAllowedTypes and WithSubjectBinding,
+// ErrPolicyNotRestrictive is returned otherwise.
+func AutoAcceptLDProofRequests(p IssuerProvider, verificationMethod string,
+	opts ...IssuerOpt) (issuecredential.AutoAccept, error) {
+	issuer := newIssuer(p, verificationMethod, opts...)
+
+	if err := checkAutoAcceptPolicy(issuer.policy); err != nil {
+		return nil, err
+	}
+
+	return func(msg service.DIDCommMsg, myDID, theirDID string) (issuecredential.Opt, bool) {
+		if msg.Type() != issuecredential.RequestCredentialMsgType {
+			return nil, false
+		}
+
+		request := issuecredential.RequestCredential{}
+		if err := msg.Decode(&request); err != nil {
+			return nil, false
+		}
+
+		if len(request.Formats) == 0 || len(request.Formats) != len(request.RequestsAttach) {
+			return nil, false
+		}
+
+		for _, format := range request.Formats {
+			if format.Format != LDProofVCDetailFormat {
+				return nil, false
+			}
+
+			src, err := getAttachment(request.RequestsAttach, format.AttachID)
+			if err != nil {
+				return nil, false
+			}
+
+			if _, _, err = issuer.prepare(src, myDID, theirDID); err != nil {
+				return nil, false
+			}
+		}
+
+		return issuecredential.WithIssueCredential(&issuecredential.IssueCredential{}), true
+	}, nil
+}
+
+func newIssuer(p IssuerProvider, verificationMethod string, opts ...IssuerOpt) *ldProofIssuer {
+	options := &issuerOptions{
+		verificationMethod: verificationMethod,
+		signatureType:      Ed25519Signature2018,
+		policy:             NewIssuerPolicy(),
+		documentLoader:     verifiable.CachingJSONLDLoader(),
+	}
+
+	for i := range opts {
+		opts[i](options)
+	}
+
+	if options.kid == "" {
+		if idx := strings.Index(verificationMethod, "#"); idx != -1 {
+			options.kid = verificationMethod[idx+1:]
+		}
+	}
+
+	return &ldProofIssuer{km: p.KMS(), crypto: p.Crypto(), issuerOptions: options}
+}
+
+type ldProofIssuer struct {
+	*issuerOptions
+	km     kms.KeyManager
+	crypto crypto.Crypto
+}
+
+func (i *ldProofIssuer) issue(src []byte, myDID, theirDID string) (*verifiable.Credential, error) {
+	vc, signingCtx, err := i.prepare(src, myDID, theirDID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = vc.AddLinkedDataProof(signingCtx, jsonld.WithDocumentLoader(i.documentLoader)); err != nil {
+		return nil, fmt.Errorf("add linked data proof: %w", err)
+	}
+
+	return vc, nil
+}
+
+// prepare parses the requested credential and checks it against the issuer policy, it returns the credential
+// and the context of its proof.
+func (i *ldProofIssuer) prepare(src []byte, myDID, theirDID string) (*verifiable.Credential,
+	*verifiable.LinkedDataProofContext, error) {
+	var detail LDProofVCDetail
+
+	if err := json.Unmarshal(src, &detail); err != nil {
+		return nil, nil, fmt.Errorf("unmarshal detail: %w", err)
+	}
+
+	if detail.Options == nil {
+		detail.Options = &LDProofVCDetailOptions{}
+	}
+
+	vc, err := verifiable.ParseCredential(detail.Credential,
+		verifiable.WithDisabledProofCheck(),
+		verifiable.WithJSONLDDocumentLoader(i.documentLoader),
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parse credential: %w", err)
+	}
+
+	if len(vc.Proofs) > 0 {
+		return nil, nil, errors.New("requested credential already has a proof")
+	}
+
+	issuerDID := strings.Split(i.verificationMethod, "#")[0]
+
+	if vc.Issuer.ID != issuerDID {
+		return nil, nil, fmt.Errorf("issuer %s is not the issuer DID %s", vc.Issuer.ID, issuerDID)
+	}
+
+	signingCtx, err := i.signingContext(detail.Options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = i.policy.Approve(&CredentialRequest{
+		Credential: vc,
+		Options:    detail.Options,
+		MyDID:      myDID,
+		TheirDID:   theirDID,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("issuer policy: %w", err)
+	}
+
+	return vc, signingCtx, nil
+}
+
+func (i *ldProofIssuer) signingContext(options *LDProofVCDetailOptions) (*verifiable.LinkedDataProofContext, error) {
+	if options.ProofType != "" && options.ProofType != i.signatureType {
+		return nil, fmt.Errorf("proof type %s is not supported", options.ProofType)
+	}
+
+	if options.ProofPurpose != "" && options.ProofPurpose != assertionMethodPurpose {
+		return nil, fmt.Errorf("proof purpose %s is not supported", options.ProofPurpose)
+	}
+
+	if len(options.CredentialStatus) > 0 {
+		return nil, errors.New("credential status is not supported")
+	}
+
+	var created *time.Time
+
+	if options.Created != "" {
+		t, err := time.Parse(time.RFC3339, options.Created)
+		if err != nil {
+			return nil, fmt.Errorf("parse created: %w", err)
+		}
+
+		created = &t
+	}
+
+	kh, err := i.km.Get(i.kid)
+	if err != nil {
+		return nil, fmt.Errorf("get key %s: %w", i.kid, err)
+	}
+
+	s := &kmsSigner{crypto: i.crypto, keyHandle: kh}
+
+	var signatureSuite signer.SignatureSuite
+
+	switch i.signatureType {
+	case Ed25519Signature2018:
+		signatureSuite = ed25519signature2018.New(suite.WithSigner(s))
+	case JSONWebSignature2020:
+		signatureSuite = jsonwebsignature2020.New(suite.WithSigner(s))
+	default:
+		return nil, fmt.Errorf("signature type %s is not supported", i.signatureType)
+	}
+
+	return &verifiable.LinkedDataProofContext{
+		SignatureType:           i.signatureType,
+		SignatureRepresentation: verifiable.SignatureJWS,
+		Suite:                   signatureSuite,
+		VerificationMethod:      i.verificationMethod,
+		Purpose:                 assertionMethodPurpose,
+		Created:                 created,
+		Domain:                  options.Domain,
+		Challenge:               options.Challenge,
+	}, nil
+}
+
+type kmsSigner struct {
+	crypto    crypto.Crypto
+	keyHandle interface{}
+}
+
+func (s *kmsSigner) Sign(data []byte) ([]byte, error) {
+	return s.crypto.Sign(data, s.keyHandle)
+}
+
+func getAttachment(attachments []decorator.Attachment, id string) ([]byte, error) {
+	for i := range attachments {
+		if attachments[i].ID == id {
+			return attachments[i].Data.Fetch()
+		}
+	}
+
+	return nil, errors.New("not found")
+}
+
+func hasFormat(formats []issuecredential.Format, format string) bool {
+	for _, fm := range formats {
+		if fm.Format == format {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,498 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package issuecredential
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/piprate/json-gold/ld"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/issuecredential"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/jose"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/verifier"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	mocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/didcomm/protocol/middleware/issuecredential"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/localkms"
+	"github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/protocol"
+	mockprovider "github.com/hyperledger/aries-framework-go/pkg/mock/provider"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock/noop"
+	"github.com/hyperledger/aries-framework-go/pkg/vdr/fingerprint"
+)
+
+const (
+	holderDID          = "did:example:holder"
+	exampleContextURI  = "https://example.com/context/v1"
+	exampleContextJSON = `{"@context":{"@vocab":"https://example.com/vocab#"}}`
+)
+
+func TestIssueLDProofCredentials(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	issuer := newLDProofIssuer(t, kms.ED25519Type)
+
+	next := issuecredential.HandlerFunc(func(metadata issuecredential.Metadata) error {
+		return nil
+	})
+
+	newMetadata := func(request *issuecredential.RequestCredential, msg *issuecredential.IssueCredential,
+		theirDID string) *mocks.MockMetadata {
+		metadata := mocks.NewMockMetadata(ctrl)
+		metadata.EXPECT().StateName().Return(stateNameRequestReceived)
+		metadata.EXPECT().Message().Return(service.NewDIDCommMsgMap(request)).AnyTimes()
+		metadata.EXPECT().IssueCredential().Return(msg).AnyTimes()
+		metadata.EXPECT().Properties().Return(map[string]interface{}{
+			myDIDKey:    "did:example:issuer",
+			theirDIDKey: theirDID,
+		}).AnyTimes()
+
+		return metadata
+	}
+
+	t.Run("Ignores processing", func(t *testing.T) {
+		metadata := mocks.NewMockMetadata(ctrl)
+		metadata.EXPECT().StateName().Return("state-name")
+		require.NoError(t, issuer.middleware()(next).Handle(metadata))
+
+		// request without the ld-proof format
+		metadata = newMetadata(&issuecredential.RequestCredential{
+			Type: issuecredential.RequestCredentialMsgType,
+		}, &issuecredential.IssueCredential{}, holderDID)
+		require.NoError(t, issuer.middleware()(next).Handle(metadata))
+
+		// the Issuer did not accept the request with an IssueCredential message
+		request := issuer.request(t, issuer.credential(holderDID), nil)
+		metadata = newMetadata(request, nil, holderDID)
+		require.NoError(t, issuer.middleware()(next).Handle(metadata))
+
+		// the Issuer provided the credentials
+		msg := &issuecredential.IssueCredential{Formats: []issuecredential.Format{{Format: LDProofVCFormat}}}
+		metadata = newMetadata(request, msg, holderDID)
+		require.NoError(t, issuer.middleware()(next).Handle(metadata))
+		require.Empty(t, msg.CredentialsAttach)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		for _, keyType := range []kms.KeyType{kms.ED25519Type, kms.ECDSAP256TypeIEEEP1363} {
+			keyIssuer := newLDProofIssuer(t, keyType)
+
+			signatureType := Ed25519Signature2018
+			if keyType != kms.ED25519Type {
+				signatureType = JSONWebSignature2020
+			}
+
+			request := keyIssuer.request(t, keyIssuer.credential(holderDID), &LDProofVCDetailOptions{
+				ProofPurpose: assertionMethodPurpose,
+				Created:      "2010-01-01T19:23:24Z",
+				Domain:       "example.com",
+				Challenge:    "challenge",
+				ProofType:    signatureType,
+			})
+
+			msg := &issuecredential.IssueCredential{}
+			metadata := newMetadata(request, msg, holderDID)
+
+			policy := NewIssuerPolicy(
+				WithAllowedTypes("UniversityDegreeCredential"),
+				WithAllowedClaims("name"),
+				WithSubjectBinding(),
+			)
+
+			err := keyIssuer.middleware(WithSignatureType(signatureType), WithIssuerPolicy(policy))(next).
+				Handle(metadata)
+			require.NoError(t, err)
+
+			require.Len(t, msg.Formats, 1)
+			require.Len(t, msg.CredentialsAttach, 1)
+			require.Equal(t, LDProofVCFormat, msg.Formats[0].Format)
+			require.Equal(t, msg.Formats[0].AttachID, msg.CredentialsAttach[0].ID)
+			require.Equal(t, mimeTypeApplicationLdJSON, msg.CredentialsAttach[0].MimeType)
+
+			vc := keyIssuer.parse(t, msg.CredentialsAttach[0])
+			require.Len(t, vc.Proofs, 1)
+			require.Equal(t, signatureType, vc.Proofs[0]["type"])
+			require.Equal(t, keyIssuer.verificationMethod, vc.Proofs[0]["verificationMethod"])
+			require.Equal(t, assertionMethodPurpose, vc.Proofs[0]["proofPurpose"])
+			require.Equal(t, "2010-01-01T19:23:24Z", vc.Proofs[0]["created"])
+			require.Equal(t, "example.com", vc.Proofs[0]["domain"])
+			require.Equal(t, "challenge", vc.Proofs[0]["challenge"])
+		}
+	})
+
+	t.Run("Success (default options)", func(t *testing.T) {
+		msg := &issuecredential.IssueCredential{}
+		metadata := newMetadata(issuer.request(t, issuer.credential("did:example:other"), nil), msg, holderDID)
+
+		require.NoError(t, issuer.middleware()(next).Handle(metadata))
+
+		vc := issuer.parse(t, msg.CredentialsAttach[0])
+		require.Equal(t, Ed25519Signature2018, vc.Proofs[0]["type"])
+	})
+
+	t.Run("Denied by the issuer policy", func(t *testing.T) {
+		metadata := newMetadata(issuer.request(t, issuer.credential("did:example:other"), nil),
+			&issuecredential.IssueCredential{}, holderDID)
+
+		err := issuer.middleware(WithIssuerPolicy(NewIssuerPolicy(WithSubjectBinding())))(next).Handle(metadata)
+		require.True(t, errors.Is(err, ErrCredentialDenied))
+		require.Contains(t, err.Error(), "subject did:example:other is not the DID of the connection")
+	})
+
+	t.Run("Invalid requests", func(t *testing.T) {
+		detail := func(d interface{}) []byte {
+			raw, err := json.Marshal(d)
+			require.NoError(t, err)
+
+			return raw
+		}
+
+		vc := issuer.credential(holderDID)
+
+		otherIssuer := issuer.credential(holderDID)
+		otherIssuer["issuer"] = "did:example:other"
+
+		withProof := issuer.credential(holderDID)
+		withProof["proof"] = map[string]interface{}{"type": Ed25519Signature2018}
+
+		tests := []struct {
+			name   string
+			detail []byte
+			err    string
+		}{{
+			name:   "not json",
+			detail: []byte("{"),
+			err:    "unmarshal detail",
+		}, {
+			name:   "invalid credential",
+			detail: detail(&LDProofVCDetail{Credential: []byte(`{}`)}),
+			err:    "parse credential",
+		}, {
+			name:   "credential with a proof",
+			detail: detail(&LDProofVCDetail{Credential: detail(withProof)}),
+			err:    "requested credential already has a proof",
+		}, {
+			name:   "other issuer",
+			detail: detail(&LDProofVCDetail{Credential: detail(otherIssuer)}),
+			err:    "issuer did:example:other is not the issuer DID",
+		}, {
+			name: "proof type",
+			detail: detail(&LDProofVCDetail{
+				Credential: detail(vc),
+				Options:    &LDProofVCDetailOptions{ProofType: "BbsBlsSignature2020"},
+			}),
+			err: "proof type BbsBlsSignature2020 is not supported",
+		}, {
+			name: "proof purpose",
+			detail: detail(&LDProofVCDetail{
+				Credential: detail(vc),
+				Options:    &LDProofVCDetailOptions{ProofPurpose: "authentication"},
+			}),
+			err: "proof purpose authentication is not supported",
+		}, {
+			name: "credential status",
+			detail: detail(&LDProofVCDetail{
+				Credential: detail(vc),
+				Options:    &LDProofVCDetailOptions{CredentialStatus: []byte(`{"type":"CredentialStatusList2017"}`)},
+			}),
+			err: "credential status is not supported",
+		}, {
+			name: "created",
+			detail: detail(&LDProofVCDetail{
+				Credential: detail(vc),
+				Options:    &LDProofVCDetailOptions{Created: "yesterday"},
+			}),
+			err: "parse created",
+		}}
+
+		for _, test := range tests {
+			tc := test
+			t.Run(tc.name, func(t *testing.T) {
+				msg := &issuecredential.IssueCredential{}
+				metadata := newMetadata(issuer.requestWithDetail(tc.detail), msg, holderDID)
+
+				err := issuer.middleware()(next).Handle(metadata)
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.err)
+				require.Empty(t, msg.CredentialsAttach)
+			})
+		}
+	})
+
+	t.Run("Attachment not found", func(t *testing.T) {
+		request := issuer.request(t, issuer.credential(holderDID), nil)
+		request.RequestsAttach[0].ID = "other"
+
+		err := issuer.middleware()(next).Handle(newMetadata(request, &issuecredential.IssueCredential{}, holderDID))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "not found")
+	})
+
+	t.Run("Key not found", func(t *testing.T) {
+		metadata := newMetadata(issuer.request(t, issuer.credential(holderDID), nil),
+			&issuecredential.IssueCredential{}, holderDID)
+
+		err := issuer.middleware(WithKeyID("unknown"))(next).Handle(metadata)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "get key unknown")
+	})
+
+	t.Run("Unsupported signature type", func(t *testing.T) {
+		metadata := newMetadata(issuer.request(t, issuer.credential(holderDID), nil),
+			&issuecredential.IssueCredential{}, holderDID)
+
+		err := issuer.middleware(WithSignatureType("BbsBlsSignature2020"))(next).Handle(metadata)
+		require.EqualError(t, err, "issue credential: signature type BbsBlsSignature2020 is not supported")
+	})
+
+	t.Run("Key ID from the verification method", func(t *testing.T) {
+		mw := IssueLDProofCredentials(issuer.provider, issuer.did+"#"+issuer.kid,
+			WithJSONLDDocumentLoader(issuer.loader))
+
+		msg := &issuecredential.IssueCredential{}
+		metadata := newMetadata(issuer.request(t, issuer.credential(holderDID), nil), msg, holderDID)
+
+		require.NoError(t, mw(next).Handle(metadata))
+		require.Len(t, msg.CredentialsAttach, 1)
+	})
+}
+
+func TestAutoAcceptLDProofRequests(t *testing.T) {
+	issuer := newLDProofIssuer(t, kms.ED25519Type)
+
+	autoAccept := func(opts ...IssuerOpt) issuecredential.AutoAccept {
+		accept, err := AutoAcceptLDProofRequests(issuer.provider, issuer.verificationMethod,
+			append([]IssuerOpt{WithKeyID(issuer.kid), WithJSONLDDocumentLoader(issuer.loader)}, opts...)...)
+		require.NoError(t, err)
+
+		return accept
+	}
+
+	policy := WithIssuerPolicy(NewIssuerPolicy(WithAllowedTypes("UniversityDegreeCredential"), WithSubjectBinding()))
+
+	t.Run("Refuses the policies which are not restrictive", func(t *testing.T) {
+		for _, p := range []IssuerPolicy{
+			NewIssuerPolicy(),
+			NewIssuerPolicy(WithSubjectBinding()),
+			NewIssuerPolicy(WithAllowedTypes("UniversityDegreeCredential")),
+			&stubPolicy{},
+		} {
+			_, err := AutoAcceptLDProofRequests(issuer.provider, issuer.verificationMethod, WithIssuerPolicy(p))
+			require.True(t, errors.Is(err, ErrPolicyNotRestrictive))
+		}
+	})
+
+	t.Run("Accepts the requests approved by the issuer policy", func(t *testing.T) {
+		request := issuer.request(t, issuer.credential(holderDID), nil)
+
+		opt, ok := autoAccept(policy)(service.NewDIDCommMsgMap(request), "did:example:issuer", holderDID)
+		require.True(t, ok)
+		require.NotNil(t, opt)
+	})
+
+	t.Run("Does not accept", func(t *testing.T) {
+		otherFormat := issuer.request(t, issuer.credential(holderDID), nil)
+		otherFormat.Formats = append(otherFormat.Formats, issuecredential.Format{AttachID: "indy", Format: "hlindy-zkp-v1.0"})
+		otherFormat.RequestsAttach = append(otherFormat.RequestsAttach, decorator.Attachment{ID: "indy"})
+
+		notFound := issuer.request(t, issuer.credential(holderDID), nil)
+		notFound.RequestsAttach[0].ID = "other"
+
+		withoutFormat := issuer.request(t, issuer.credential(holderDID), nil)
+		withoutFormat.Formats = nil
+
+		withEvidence := issuer.credential(holderDID)
+		withEvidence["evidence"] = map[string]interface{}{"type": "DocumentVerification"}
+
+		tests := []struct {
+			name string
+			msg  service.DIDCommMsgMap
+		}{{
+			name: "not a request",
+			msg:  service.NewDIDCommMsgMap(issuecredential.OfferCredential{Type: issuecredential.OfferCredentialMsgType}),
+		}, {
+			name: "not a request message",
+			msg:  service.DIDCommMsgMap{"@type": issuecredential.RequestCredentialMsgType, "formats": "formats"},
+		}, {
+			name: "request without formats",
+			msg:  service.NewDIDCommMsgMap(withoutFormat),
+		}, {
+			name: "request in other format",
+			msg:  service.NewDIDCommMsgMap(otherFormat),
+		}, {
+			name: "attachment not found",
+			msg:  service.NewDIDCommMsgMap(notFound),
+		}, {
+			name: "invalid detail",
+			msg:  service.NewDIDCommMsgMap(issuer.requestWithDetail([]byte("{"))),
+		}, {
+			name: "denied by the issuer policy",
+			msg:  service.NewDIDCommMsgMap(issuer.request(t, issuer.credential("did:example:other"), nil)),
+		}, {
+			name: "credential with a field which is not allowed",
+			msg:  service.NewDIDCommMsgMap(issuer.request(t, withEvidence, nil)),
+		}}
+
+		for _, test := range tests {
+			tc := test
+			t.Run(tc.name, func(t *testing.T) {
+				opt, ok := autoAccept(policy)(tc.msg, "did:example:issuer", holderDID)
+				require.False(t, ok)
+				require.Nil(t, opt)
+			})
+		}
+	})
+}
+
+type stubPolicy struct{}
+
+func (p *stubPolicy) Approve(*CredentialRequest) error {
+	return nil
+}
+
+type testLDProofIssuer struct {
+	provider           *mockprovider.Provider
+	loader             ld.DocumentLoader
+	pubKey             []byte
+	keyType            kms.KeyType
+	kid                string
+	did                string
+	verificationMethod string
+}
+
+func newLDProofIssuer(t *testing.T, keyType kms.KeyType) *testLDProofIssuer {
+	t.Helper()
+
+	km, err := localkms.New("local-lock://primary/test/", &protocol.MockProvider{
+		StoreProvider: mem.NewProvider(),
+		CustomLock:    &noop.NoLock{},
+	})
+	require.NoError(t, err)
+
+	cr, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	kid, pubKey, err := km.CreateAndExportPubKeyBytes(keyType)
+	require.NoError(t, err)
+
+	codec := uint64(fingerprint.ED25519PubKeyMultiCodec)
+	if keyType != kms.ED25519Type {
+		codec = fingerprint.P256PubKeyMultiCodec
+	}
+
+	didKey, keyID := fingerprint.CreateDIDKeyByCode(codec, pubKey)
+
+	loader := verifiable.CachingJSONLDLoader()
+
+	doc, err := ld.DocumentFromReader(strings.NewReader(exampleContextJSON))
+	require.NoError(t, err)
+
+	loader.AddDocument(exampleContextURI, doc)
+
+	return &testLDProofIssuer{
+		provider:           &mockprovider.Provider{KMSValue: km, CryptoValue: cr},
+		loader:             loader,
+		pubKey:             pubKey,
+		keyType:            keyType,
+		kid:                kid,
+		did:                didKey,
+		verificationMethod: keyID,
+	}
+}
+
+func (i *testLDProofIssuer) middleware(opts ...IssuerOpt) issuecredential.Middleware {
+	return IssueLDProofCredentials(i.provider, i.verificationMethod,
+		append([]IssuerOpt{WithKeyID(i.kid), WithJSONLDDocumentLoader(i.loader)}, opts...)...)
+}
+
+func (i *testLDProofIssuer) credential(subjectID string) map[string]interface{} {
+	return map[string]interface{}{
+		"@context":     []string{verifiable.ContextURI, exampleContextURI},
+		"type":         []string{verifiable.VCType, "UniversityDegreeCredential"},
+		"issuer":       i.did,
+		"issuanceDate": "2010-01-01T19:23:24Z",
+		"credentialSubject": map[string]interface{}{
+			"id":   subjectID,
+			"name": "Jayden Doe",
+		},
+	}
+}
+
+func (i *testLDProofIssuer) request(t *testing.T, vc map[string]interface{},
+	options *LDProofVCDetailOptions) *issuecredential.RequestCredential {
+	t.Helper()
+
+	credential, err := json.Marshal(vc)
+	require.NoError(t, err)
+
+	detail, err := json.Marshal(&LDProofVCDetail{Credential: credential, Options: options})
+	require.NoError(t, err)
+
+	return i.requestWithDetail(detail)
+}
+
+func (i *testLDProofIssuer) requestWithDetail(detail []byte) *issuecredential.RequestCredential {
+	return &issuecredential.RequestCredential{
+		Type:    issuecredential.RequestCredentialMsgType,
+		Formats: []issuecredential.Format{{AttachID: "detail", Format: LDProofVCDetailFormat}},
+		RequestsAttach: []decorator.Attachment{{
+			ID:       "detail",
+			MimeType: "application/json",
+			Data:     decorator.AttachmentData{Base64: base64.StdEncoding.EncodeToString(detail)},
+		}},
+	}
+}
+
+// parse parses the issued credential and checks its proof.
+func (i *testLDProofIssuer) parse(t *testing.T, attachment decorator.Attachment) *verifiable.Credential {
+	t.Helper()
+
+	raw, err := attachment.Data.Fetch()
+	require.NoError(t, err)
+
+	keyType := "Ed25519VerificationKey2018"
+	if i.keyType != kms.ED25519Type {
+		keyType = "JwsVerificationKey2020"
+	}
+
+	vc, err := verifiable.ParseCredential(raw,
+		verifiable.WithJSONLDDocumentLoader(i.loader),
+		verifiable.WithPublicKeyFetcher(func(issuerID, keyID string) (*verifier.PublicKey, error) {
+			return publicKey(t, i, keyType), nil
+		}),
+	)
+	require.NoError(t, err)
+
+	return vc
+}
+
+func publicKey(t *testing.T, i *testLDProofIssuer, keyType string) *verifier.PublicKey {
+	t.Helper()
+
+	if i.keyType == kms.ED25519Type {
+		return &verifier.PublicKey{Type: keyType, Value: i.pubKey}
+	}
+
+	x, y := elliptic.Unmarshal(elliptic.P256(), i.pubKey)
+
+	j, err := jose.JWKFromPublicKey(&ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y})
+	require.NoError(t, err)
+
+	return &verifier.PublicKey{Type: keyType, Value: i.pubKey, JWK: j}
+}
@@ -46,7 +46,12 @@ func SaveCredentials(p Provider) issuecredential.Middleware {
 				return next.Handle(metadata)
 			}
 
-			credential := issuecredential.IssueCredential{}
+			credential := struct {
+				// nolint: staticcheck
+				issuecredential.IssueCredential `json:",squash"`
+				// Service is set for the connectionless messages which are received without the connection.
+				Service *decorator.Service `json:"~service,omitempty"`
+			}{}
 
 			err := metadata.Message().Decode(&credential)
 			if err != nil {
@@ -69,7 +74,7 @@ func SaveCredentials(p Provider) issuecredential.Middleware {
 			myDID, _ := properties[myDIDKey].(string)
 			// nolint: errcheck
 			theirDID, _ := properties[theirDIDKey].(string)
-			if myDID == "" || theirDID == "" {
+			if (myDID == "" || theirDID == "") && credential.Service == nil {
 				return errors.New("myDID or theirDID is absent")
 			}
 
@@ -170,6 +170,37 @@ func TestSaveCredentials(t *testing.T) {
 		require.EqualError(t, SaveCredentials(provider)(next).Handle(metadata), "myDID or theirDID is absent")
 	})
 
+	t.Run("Success (connectionless)", func(t *testing.T) {
+		props := map[string]interface{}{}
+
+		metadata := mocks.NewMockMetadata(ctrl)
+		metadata.EXPECT().StateName().Return(stateNameCredentialReceived)
+		metadata.EXPECT().CredentialNames().Return([]string{"vc-name"}).Times(2)
+		metadata.EXPECT().Properties().Return(props)
+		metadata.EXPECT().Message().Return(service.NewDIDCommMsgMap(struct {
+			issuecredential.IssueCredential
+			Service *decorator.Service `json:"~service"`
+		}{
+			IssueCredential: issuecredential.IssueCredential{
+				Type: issuecredential.IssueCredentialMsgType,
+				CredentialsAttach: []decorator.Attachment{
+					{Data: decorator.AttachmentData{JSON: getCredential()}},
+				},
+			},
+			Service: &decorator.Service{RecipientKeys: []string{"did:key:issuer"}, ServiceEndpoint: "endpoint"},
+		}))
+
+		verifiableStore := mockstore.NewMockStore(ctrl)
+		verifiableStore.EXPECT().SaveCredential(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+
+		provider := mocks.NewMockProvider(ctrl)
+		provider.EXPECT().VDRegistry().Return(nil).AnyTimes()
+		provider.EXPECT().VerifiableStore().Return(verifiableStore)
+
+		require.NoError(t, SaveCredentials(provider)(next).Handle(metadata))
+		require.Equal(t, []string{"vc-name"}, props[namesKey])
+	})
+
 	t.Run("Success", func(t *testing.T) {
 		const vcName = "vc-name"
 
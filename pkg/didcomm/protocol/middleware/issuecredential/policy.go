@@ -0,0 +1,374 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package issuecredential
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+)
+
+// ErrCredentialDenied is returned when the issuer policy denies the requested credential.
+var ErrCredentialDenied = errors.New("credential denied")
+
+// ErrPolicyNotRestrictive is returned when the requests would be accepted without raising action events while
+// the issuer policy does not restrict the types of the credentials and bind their subjects to the connection.
+var ErrPolicyNotRestrictive = errors.New("issuer policy is not restrictive enough to auto-accept requests")
+
+// CredentialRequest is a credential requested by the holder, it is checked by the issuer policy before it's signed.
+type CredentialRequest struct {
+	// Credential is the requested credential without a proof.
+	Credential *verifiable.Credential
+	// Options are the requested proof options.
+	Options *LDProofVCDetailOptions
+	// MyDID and TheirDID are the DIDs of the connection, they are empty for connectionless requests.
+	MyDID    string
+	TheirDID string
+}
+
+// IssuerPolicy decides on the credentials requested from the issuer.
+type IssuerPolicy interface {
+	// Approve returns an error if the credential is not issued.
+	Approve(request *CredentialRequest) error
+}
+
+// PolicyOption configures the issuer policy.
+type PolicyOption func(p *policy)
+
+// WithAllowedTypes issues only the credentials of the given types, the VerifiableCredential type is always allowed.
+func WithAllowedTypes(types ...string) PolicyOption {
+	return func(p *policy) {
+		addAll(p.allowedTypes, types)
+	}
+}
+
+// WithAllowedClaims issues only the credentials whose subjects have the given claims, the subject ID is always
+// allowed. A claim allows all of its nested claims, the nested claims can be allowed one by one with their
+// dot-separated path instead (e.g. "degree.type").
+func WithAllowedClaims(claims ...string) PolicyOption {
+	return func(p *policy) {
+		addAll(p.allowedClaims, claims)
+	}
+}
+
+// WithSubjectBinding issues only the credentials whose subjects are the DID of the connection the credential
+// is requested over (or a DID URL of it).
+func WithSubjectBinding() PolicyOption {
+	return func(p *policy) {
+		p.subjectBinding = true
+	}
+}
+
+// WithIssuanceDateTolerance issues only the credentials whose issuanceDate, chosen by the holder, is at most
+// the given duration away from the time the request is approved.
+func WithIssuanceDateTolerance(tolerance time.Duration) PolicyOption {
+	return func(p *policy) {
+		p.issuanceDateTolerance = tolerance
+	}
+}
+
+// WithMaxValidity issues only the credentials which have an expirationDate at most the given duration after their
+// issuanceDate.
+func WithMaxValidity(validity time.Duration) PolicyOption {
+	return func(p *policy) {
+		p.maxValidity = validity
+	}
+}
+
+// WithAllowedFields issues the credentials having the given optional fields (e.g. credentialSchema, evidence,
+// termsOfUse, refreshService, credentialStatus or any other field the holder adds to the credential). The credentials
+// having other fields than @context, id, type, issuer (without other properties than its id), issuanceDate,
+// expirationDate and credentialSubject are denied otherwise.
+func WithAllowedFields(fields ...string) PolicyOption {
+	return func(p *policy) {
+		addAll(p.allowedFields, fields)
+	}
+}
+
+// WithoutCredentialID issues only the credentials without an id, the holder can't choose the id of the credential.
+func WithoutCredentialID() PolicyOption {
+	return func(p *policy) {
+		p.noCredentialID = true
+	}
+}
+
+type policy struct {
+	allowedTypes          map[string]struct{}
+	allowedClaims         map[string]struct{}
+	allowedFields         map[string]struct{}
+	subjectBinding        bool
+	issuanceDateTolerance time.Duration
+	maxValidity           time.Duration
+	noCredentialID        bool
+}
+
+// NewIssuerPolicy returns the issuer policy. Credentials of any type with any claims, subjects, dates and id are
+// issued unless the options say otherwise, the optional fields of the credentials have to be allowed with
+// WithAllowedFields.
+func NewIssuerPolicy(opts ...PolicyOption) IssuerPolicy {
+	p := &policy{
+		allowedTypes:  make(map[string]struct{}),
+		allowedClaims: make(map[string]struct{}),
+		allowedFields: make(map[string]struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// Approve denies the credentials having a type, a claim or a field which is not allowed, the credentials whose
+// subjects are not bound to the connection if the subject binding is required and the credentials whose id
+// or dates are not allowed.
+func (p *policy) Approve(request *CredentialRequest) error {
+	if p.noCredentialID && request.Credential.ID != "" {
+		return fmt.Errorf("credential id %s is not allowed: %w", request.Credential.ID, ErrCredentialDenied)
+	}
+
+	if err := p.approveFields(request.Credential); err != nil {
+		return err
+	}
+
+	if err := p.approveDates(request.Credential); err != nil {
+		return err
+	}
+
+	if err := p.approveTypes(request.Credential.Types); err != nil {
+		return err
+	}
+
+	subjects, err := credentialSubjects(request.Credential)
+	if err != nil {
+		return err
+	}
+
+	if p.subjectBinding && len(subjects) == 0 {
+		return fmt.Errorf("credential without subject is not bound to the connection: %w", ErrCredentialDenied)
+	}
+
+	for _, subject := range subjects {
+		if err := p.approveSubject(subject, request.TheirDID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// approveFields denies the optional fields of the credential which are not allowed, the holder would choose
+// them otherwise.
+func (p *policy) approveFields(vc *verifiable.Credential) error {
+	if len(vc.CustomContext) > 0 {
+		return fmt.Errorf("embedded contexts are not allowed: %w", ErrCredentialDenied)
+	}
+
+	if len(vc.Issuer.CustomFields) > 0 {
+		return fmt.Errorf("issuer properties other than its id are not allowed: %w", ErrCredentialDenied)
+	}
+
+	var fields []string
+
+	for _, f := range []struct {
+		name string
+		set  bool
+	}{
+		{name: "credentialStatus", set: vc.Status != nil},
+		{name: "credentialSchema", set: len(vc.Schemas) > 0},
+		{name: "evidence", set: vc.Evidence != nil},
+		{name: "termsOfUse", set: len(vc.TermsOfUse) > 0},
+		{name: "refreshService", set: len(vc.RefreshService) > 0},
+	} {
+		if f.set {
+			fields = append(fields, f.name)
+		}
+	}
+
+	for field := range vc.CustomFields {
+		fields = append(fields, field)
+	}
+
+	for _, field := range fields {
+		if _, ok := p.allowedFields[field]; !ok {
+			return fmt.Errorf("field %s is not allowed: %w", field, ErrCredentialDenied)
+		}
+	}
+
+	return nil
+}
+
+// approveTypes denies the types which are not allowed, the credential has to have at least one allowed type
+// besides VerifiableCredential.
+func (p *policy) approveTypes(types []string) error {
+	if len(p.allowedTypes) == 0 {
+		return nil
+	}
+
+	allowed := false
+
+	for _, t := range types {
+		if t == verifiable.VCType {
+			continue
+		}
+
+		if _, ok := p.allowedTypes[t]; !ok {
+			return fmt.Errorf("type %s is not allowed: %w", t, ErrCredentialDenied)
+		}
+
+		allowed = true
+	}
+
+	if !allowed {
+		return fmt.Errorf("credential has no allowed type: %w", ErrCredentialDenied)
+	}
+
+	return nil
+}
+
+func (p *policy) approveDates(vc *verifiable.Credential) error {
+	if p.issuanceDateTolerance == 0 && p.maxValidity == 0 {
+		return nil
+	}
+
+	if vc.Issued == nil {
+		return fmt.Errorf("issuance date is not set: %w", ErrCredentialDenied)
+	}
+
+	issued := vc.Issued.Time
+
+	if p.issuanceDateTolerance != 0 {
+		if d := time.Since(issued); d > p.issuanceDateTolerance || d < -p.issuanceDateTolerance {
+			return fmt.Errorf("issuance date %s is not allowed: %w", issued.Format(time.RFC3339),
+				ErrCredentialDenied)
+		}
+	}
+
+	if p.maxValidity == 0 {
+		return nil
+	}
+
+	if vc.Expired == nil {
+		return fmt.Errorf("expiration date is not set: %w", ErrCredentialDenied)
+	}
+
+	if expired := vc.Expired.Time; expired.Before(issued) || expired.Sub(issued) > p.maxValidity {
+		return fmt.Errorf("expiration date %s is not allowed: %w", expired.Format(time.RFC3339),
+			ErrCredentialDenied)
+	}
+
+	return nil
+}
+
+func (p *policy) approveSubject(subject verifiable.Subject, theirDID string) error {
+	if len(p.allowedClaims) > 0 {
+		if err := p.approveClaims(subject.CustomFields, ""); err != nil {
+			return err
+		}
+	}
+
+	if !p.subjectBinding {
+		return nil
+	}
+
+	if theirDID == "" {
+		return fmt.Errorf("subject %s is not bound to a connection: %w", subject.ID, ErrCredentialDenied)
+	}
+
+	if subject.ID != theirDID && !strings.HasPrefix(subject.ID, theirDID+"#") {
+		return fmt.Errorf("subject %s is not the DID of the connection %s: %w", subject.ID, theirDID,
+			ErrCredentialDenied)
+	}
+
+	return nil
+}
+
+// approveClaims denies the claims which are not allowed, the nested claims are checked unless their parent claim
+// is allowed.
+func (p *policy) approveClaims(claims map[string]interface{}, prefix string) error {
+	for name, value := range claims {
+		claim := prefix + name
+
+		if _, ok := p.allowedClaims[claim]; ok {
+			continue
+		}
+
+		if !p.hasNestedClaims(claim) {
+			return fmt.Errorf("claim %s is not allowed: %w", claim, ErrCredentialDenied)
+		}
+
+		values, ok := value.([]interface{})
+		if !ok {
+			values = []interface{}{value}
+		}
+
+		for _, v := range values {
+			nested, ok := v.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("claim %s is not allowed: %w", claim, ErrCredentialDenied)
+			}
+
+			if err := p.approveClaims(nested, claim+"."); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (p *policy) hasNestedClaims(claim string) bool {
+	for allowed := range p.allowedClaims {
+		if strings.HasPrefix(allowed, claim+".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkAutoAcceptPolicy returns an error unless the issuer policy issues only the credentials of the allowed types
+// whose subjects are bound to the connection they are requested over. The policies not created by NewIssuerPolicy
+// are refused.
+func checkAutoAcceptPolicy(issuerPolicy IssuerPolicy) error {
+	p, ok := issuerPolicy.(*policy)
+	if !ok {
+		return fmt.Errorf("policy %T is not created by NewIssuerPolicy: %w", issuerPolicy, ErrPolicyNotRestrictive)
+	}
+
+	if len(p.allowedTypes) == 0 {
+		return fmt.Errorf("no allowed types: %w", ErrPolicyNotRestrictive)
+	}
+
+	if !p.subjectBinding {
+		return fmt.Errorf("no subject binding: %w", ErrPolicyNotRestrictive)
+	}
+
+	return nil
+}
+
+func credentialSubjects(vc *verifiable.Credential) ([]verifiable.Subject, error) {
+	switch subject := vc.Subject.(type) {
+	case []verifiable.Subject:
+		return subject, nil
+	case verifiable.Subject:
+		return []verifiable.Subject{subject}, nil
+	case nil:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unsupported credential subject %T", vc.Subject)
+	}
+}
+
+func addAll(set map[string]struct{}, values []string) {
+	for _, v := range values {
+		set[v] = struct{}{}
+	}
+}
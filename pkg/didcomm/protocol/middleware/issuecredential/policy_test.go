@@ -0,0 +1,269 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package issuecredential
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/util"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+)
+
+func TestIssuerPolicy_Approve(t *testing.T) {
+	newRequest := func(types []string, subject interface{}) *CredentialRequest {
+		return &CredentialRequest{
+			Credential: &verifiable.Credential{Types: types, Subject: subject},
+			MyDID:      "did:example:issuer",
+			TheirDID:   holderDID,
+		}
+	}
+
+	subject := verifiable.Subject{
+		ID:           holderDID,
+		CustomFields: verifiable.CustomFields{"name": "Jayden Doe", "degree": "BachelorDegree"},
+	}
+
+	degreeTypes := []string{verifiable.VCType, "UniversityDegreeCredential"}
+
+	t.Run("approves every credential by default", func(t *testing.T) {
+		policy := NewIssuerPolicy()
+
+		require.NoError(t, policy.Approve(newRequest(degreeTypes, []verifiable.Subject{subject})))
+		require.NoError(t, policy.Approve(newRequest(degreeTypes, verifiable.Subject{ID: "did:example:other"})))
+		require.NoError(t, policy.Approve(newRequest(degreeTypes, nil)))
+	})
+
+	t.Run("allowed types", func(t *testing.T) {
+		policy := NewIssuerPolicy(WithAllowedTypes("UniversityDegreeCredential"))
+
+		require.NoError(t, policy.Approve(newRequest(degreeTypes, subject)))
+
+		err := policy.Approve(newRequest([]string{verifiable.VCType, "PermanentResidentCard"}, subject))
+		require.True(t, errors.Is(err, ErrCredentialDenied))
+		require.Contains(t, err.Error(), "type PermanentResidentCard is not allowed")
+	})
+
+	t.Run("allowed claims", func(t *testing.T) {
+		require.NoError(t, NewIssuerPolicy(WithAllowedClaims("name", "degree")).
+			Approve(newRequest(degreeTypes, subject)))
+
+		err := NewIssuerPolicy(WithAllowedClaims("name")).Approve(newRequest(degreeTypes, []verifiable.Subject{
+			{ID: holderDID, CustomFields: verifiable.CustomFields{"name": "Jayden Doe"}},
+			subject,
+		}))
+		require.True(t, errors.Is(err, ErrCredentialDenied))
+		require.Contains(t, err.Error(), "claim degree is not allowed")
+	})
+
+	t.Run("subject binding", func(t *testing.T) {
+		policy := NewIssuerPolicy(WithSubjectBinding())
+
+		require.NoError(t, policy.Approve(newRequest(degreeTypes, subject)))
+		require.NoError(t, policy.Approve(newRequest(degreeTypes, verifiable.Subject{ID: holderDID + "#key-1"})))
+
+		err := policy.Approve(newRequest(degreeTypes, verifiable.Subject{ID: holderDID + "x"}))
+		require.True(t, errors.Is(err, ErrCredentialDenied))
+		require.Contains(t, err.Error(), "is not the DID of the connection "+holderDID)
+
+		request := newRequest(degreeTypes, subject)
+		request.TheirDID = ""
+
+		err = policy.Approve(request)
+		require.True(t, errors.Is(err, ErrCredentialDenied))
+		require.Contains(t, err.Error(), "is not bound to a connection")
+	})
+
+	t.Run("credential id", func(t *testing.T) {
+		request := newRequest(degreeTypes, subject)
+		request.Credential.ID = "http://example.edu/credentials/1872"
+
+		require.NoError(t, NewIssuerPolicy().Approve(request))
+
+		err := NewIssuerPolicy(WithoutCredentialID()).Approve(request)
+		require.True(t, errors.Is(err, ErrCredentialDenied))
+		require.Contains(t, err.Error(), "credential id http://example.edu/credentials/1872 is not allowed")
+
+		require.NoError(t, NewIssuerPolicy(WithoutCredentialID()).Approve(newRequest(degreeTypes, subject)))
+	})
+
+	t.Run("issuance date", func(t *testing.T) {
+		policy := NewIssuerPolicy(WithIssuanceDateTolerance(time.Hour))
+
+		newDatedRequest := func(issued time.Time) *CredentialRequest {
+			request := newRequest(degreeTypes, subject)
+			request.Credential.Issued = util.NewTime(issued)
+
+			return request
+		}
+
+		require.NoError(t, policy.Approve(newDatedRequest(time.Now())))
+		require.NoError(t, policy.Approve(newDatedRequest(time.Now().Add(-time.Minute))))
+
+		for _, issued := range []time.Time{time.Now().Add(-2 * time.Hour), time.Now().Add(2 * time.Hour)} {
+			err := policy.Approve(newDatedRequest(issued))
+			require.True(t, errors.Is(err, ErrCredentialDenied))
+			require.Contains(t, err.Error(), "issuance date")
+		}
+
+		err := policy.Approve(newRequest(degreeTypes, subject))
+		require.True(t, errors.Is(err, ErrCredentialDenied))
+		require.Contains(t, err.Error(), "issuance date is not set")
+	})
+
+	t.Run("max validity", func(t *testing.T) {
+		policy := NewIssuerPolicy(WithMaxValidity(24 * time.Hour))
+
+		issued := time.Date(2010, 1, 1, 19, 23, 24, 0, time.UTC)
+
+		newDatedRequest := func(expired *time.Time) *CredentialRequest {
+			request := newRequest(degreeTypes, subject)
+			request.Credential.Issued = util.NewTime(issued)
+
+			if expired != nil {
+				request.Credential.Expired = util.NewTime(*expired)
+			}
+
+			return request
+		}
+
+		expired := issued.Add(time.Hour)
+		require.NoError(t, policy.Approve(newDatedRequest(&expired)))
+
+		err := policy.Approve(newDatedRequest(nil))
+		require.True(t, errors.Is(err, ErrCredentialDenied))
+		require.Contains(t, err.Error(), "expiration date is not set")
+
+		for _, expired := range []time.Time{issued.Add(48 * time.Hour), issued.Add(-time.Hour)} {
+			expired := expired
+
+			err = policy.Approve(newDatedRequest(&expired))
+			require.True(t, errors.Is(err, ErrCredentialDenied))
+			require.Contains(t, err.Error(), "expiration date "+expired.Format(time.RFC3339)+" is not allowed")
+		}
+
+		err = policy.Approve(newRequest(degreeTypes, subject))
+		require.True(t, errors.Is(err, ErrCredentialDenied))
+		require.Contains(t, err.Error(), "issuance date is not set")
+	})
+
+	t.Run("credential without allowed type", func(t *testing.T) {
+		err := NewIssuerPolicy(WithAllowedTypes("UniversityDegreeCredential")).
+			Approve(newRequest([]string{verifiable.VCType}, subject))
+		require.True(t, errors.Is(err, ErrCredentialDenied))
+		require.Contains(t, err.Error(), "credential has no allowed type")
+	})
+
+	t.Run("nested claims", func(t *testing.T) {
+		nested := verifiable.Subject{
+			ID: holderDID,
+			CustomFields: verifiable.CustomFields{
+				"degree": map[string]interface{}{"type": "BachelorDegree", "name": "Bachelor of Science"},
+				"alumniOf": []interface{}{
+					map[string]interface{}{"id": "did:example:university"},
+				},
+			},
+		}
+
+		require.NoError(t, NewIssuerPolicy(WithAllowedClaims("degree", "alumniOf")).
+			Approve(newRequest(degreeTypes, nested)))
+		require.NoError(t, NewIssuerPolicy(WithAllowedClaims("degree.type", "degree.name", "alumniOf.id")).
+			Approve(newRequest(degreeTypes, nested)))
+
+		err := NewIssuerPolicy(WithAllowedClaims("degree.type", "alumniOf")).Approve(newRequest(degreeTypes, nested))
+		require.True(t, errors.Is(err, ErrCredentialDenied))
+		require.Contains(t, err.Error(), "claim degree.name is not allowed")
+
+		notObject := verifiable.Subject{ID: holderDID, CustomFields: verifiable.CustomFields{"degree": "Bachelor"}}
+
+		err = NewIssuerPolicy(WithAllowedClaims("degree.type")).Approve(newRequest(degreeTypes, notObject))
+		require.True(t, errors.Is(err, ErrCredentialDenied))
+		require.Contains(t, err.Error(), "claim degree is not allowed")
+	})
+
+	t.Run("credential fields", func(t *testing.T) {
+		tests := []struct {
+			field string
+			set   func(vc *verifiable.Credential)
+		}{{
+			field: "credentialStatus",
+			set: func(vc *verifiable.Credential) {
+				vc.Status = &verifiable.TypedID{ID: "https://example.edu/status/24", Type: "CredentialStatusList2017"}
+			},
+		}, {
+			field: "credentialSchema",
+			set: func(vc *verifiable.Credential) {
+				vc.Schemas = []verifiable.TypedID{{ID: "https://example.org/schema", Type: "JsonSchemaValidator2018"}}
+			},
+		}, {
+			field: "evidence",
+			set: func(vc *verifiable.Credential) {
+				vc.Evidence = map[string]interface{}{"type": "DocumentVerification"}
+			},
+		}, {
+			field: "termsOfUse",
+			set: func(vc *verifiable.Credential) {
+				vc.TermsOfUse = []verifiable.TypedID{{Type: "IssuerPolicy"}}
+			},
+		}, {
+			field: "refreshService",
+			set: func(vc *verifiable.Credential) {
+				vc.RefreshService = []verifiable.TypedID{{ID: "https://example.edu/refresh", Type: "ManualRefreshService2018"}}
+			},
+		}, {
+			field: "referenceNumber",
+			set: func(vc *verifiable.Credential) {
+				vc.CustomFields = verifiable.CustomFields{"referenceNumber": 83294847}
+			},
+		}}
+
+		for _, test := range tests {
+			tc := test
+			t.Run(tc.field, func(t *testing.T) {
+				request := newRequest(degreeTypes, subject)
+				tc.set(request.Credential)
+
+				err := NewIssuerPolicy().Approve(request)
+				require.True(t, errors.Is(err, ErrCredentialDenied))
+				require.Contains(t, err.Error(), "field "+tc.field+" is not allowed")
+
+				require.NoError(t, NewIssuerPolicy(WithAllowedFields(tc.field)).Approve(request))
+			})
+		}
+
+		request := newRequest(degreeTypes, subject)
+		request.Credential.Issuer = verifiable.Issuer{
+			ID:           "did:example:issuer",
+			CustomFields: verifiable.CustomFields{"name": "Example University"},
+		}
+
+		err := NewIssuerPolicy().Approve(request)
+		require.True(t, errors.Is(err, ErrCredentialDenied))
+		require.Contains(t, err.Error(), "issuer properties other than its id are not allowed")
+
+		request = newRequest(degreeTypes, subject)
+		request.Credential.CustomContext = []interface{}{map[string]interface{}{"name": "https://example.org/name"}}
+
+		err = NewIssuerPolicy().Approve(request)
+		require.True(t, errors.Is(err, ErrCredentialDenied))
+		require.Contains(t, err.Error(), "embedded contexts are not allowed")
+	})
+
+	t.Run("subject binding of a credential without subject", func(t *testing.T) {
+		err := NewIssuerPolicy(WithSubjectBinding()).Approve(newRequest(degreeTypes, nil))
+		require.True(t, errors.Is(err, ErrCredentialDenied))
+		require.Contains(t, err.Error(), "credential without subject")
+	})
+
+	t.Run("unsupported subject", func(t *testing.T) {
+		err := NewIssuerPolicy().Approve(newRequest(degreeTypes, "did:example:holder"))
+		require.EqualError(t, err, "unsupported credential subject string")
+	})
+}
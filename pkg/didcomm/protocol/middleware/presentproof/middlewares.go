@@ -65,7 +65,12 @@ func SavePresentation(p Provider) presentproof.Middleware {
 				return next.Handle(metadata)
 			}
 
-			presentation := presentproof.Presentation{}
+			presentation := struct {
+				// nolint: staticcheck
+				presentproof.Presentation `json:",squash"`
+				// Service is set for the connectionless messages which are received without the connection.
+				Service *decorator.Service `json:"~service,omitempty"`
+			}{}
 			if err := metadata.Message().Decode(&presentation); err != nil {
 				return fmt.Errorf("decode: %w", err)
 			}
@@ -86,7 +91,7 @@ func SavePresentation(p Provider) presentproof.Middleware {
 			myDID, _ := properties[myDIDKey].(string)
 			// nolint: errcheck
 			theirDID, _ := properties[theirDIDKey].(string)
-			if myDID == "" || theirDID == "" {
+			if (myDID == "" || theirDID == "") && presentation.Service == nil {
 				return errors.New("myDID or theirDID is absent")
 			}
 
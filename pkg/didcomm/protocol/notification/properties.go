@@ -0,0 +1,47 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package notification
+
+const (
+	myDIDPropKey          = "myDID"
+	theirDIDPropKey       = "theirDID"
+	threadIDPropKey       = "threadID"
+	parentThreadIDPropKey = "parentThreadID"
+)
+
+type eventProps struct {
+	myDID          string
+	theirDID       string
+	threadID       string
+	parentThreadID string
+}
+
+func (e *eventProps) MyDID() string {
+	return e.myDID
+}
+
+func (e *eventProps) TheirDID() string {
+	return e.theirDID
+}
+
+func (e *eventProps) ThreadID() string {
+	return e.threadID
+}
+
+func (e *eventProps) ParentThreadID() string {
+	return e.parentThreadID
+}
+
+// All implements EventProperties interface.
+func (e *eventProps) All() map[string]interface{} {
+	return map[string]interface{}{
+		myDIDPropKey:          e.myDID,
+		theirDIDPropKey:       e.theirDID,
+		threadIDPropKey:       e.threadID,
+		parentThreadIDPropKey: e.parentThreadID,
+	}
+}
@@ -0,0 +1,102 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package notification
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/model"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+)
+
+const (
+	// Notification defines the protocol name.
+	Notification = "notification"
+
+	// StateAck is the state of the msg event emitted for the received generic ack.
+	StateAck = "ack"
+	// StateProblemReport is the state of the msg event emitted for the received generic problem report.
+	StateProblemReport = "problem-report"
+)
+
+var logger = log.New("aries-framework/notification")
+
+// Service handles the generic acks and problem reports which do not belong to any protocol instance,
+// e.g. the acks sent on receipt of the messages with the ~please_ack decorator or the problem reports
+// reported out of any thread. They are emitted as post state msg events, so apps can tell whether
+// the other side got the message.
+type Service struct {
+	service.Message
+}
+
+// New returns the notification service.
+func New() *Service {
+	return &Service{}
+}
+
+// HandleInbound handles the generic ack and problem report messages.
+func (s *Service) HandleInbound(msg service.DIDCommMsg, myDID, theirDID string) (string, error) {
+	var stateID string
+
+	switch msg.Type() {
+	case model.AckMsgType:
+		stateID = StateAck
+	case model.ProblemReportMsgType:
+		stateID = StateProblemReport
+	default:
+		return "", fmt.Errorf("unsupported message type %s", msg.Type())
+	}
+
+	// the thread ID is the message ID for the unthreaded problem reports
+	thID, err := msg.ThreadID()
+	if err != nil {
+		return "", fmt.Errorf("thread ID: %w", err)
+	}
+
+	logger.Debugf("%s %s received from %s", stateID, msg.ID(), theirDID)
+
+	stateMsg := service.StateMsg{
+		ProtocolName: Notification,
+		Type:         service.PostState,
+		StateID:      stateID,
+		Msg:          msg,
+		Properties: &eventProps{
+			myDID:          myDID,
+			theirDID:       theirDID,
+			threadID:       thID,
+			parentThreadID: msg.ParentThreadID(),
+		},
+	}
+
+	for _, handler := range s.MsgEvents() {
+		handler <- stateMsg
+	}
+
+	return thID, nil
+}
+
+// HandleOutbound adherence to dispatcher.ProtocolService.
+func (s *Service) HandleOutbound(_ service.DIDCommMsg, _, _ string) (string, error) {
+	return "", errors.New("not implemented")
+}
+
+// Accept checks whether the service can handle the message type.
+func (s *Service) Accept(msgType string) bool {
+	return msgType == model.AckMsgType || msgType == model.ProblemReportMsgType
+}
+
+// MessageTypes returns the message types handled by the service.
+func (s *Service) MessageTypes() []string {
+	return []string{model.AckMsgType, model.ProblemReportMsgType}
+}
+
+// Name of the service.
+func (s *Service) Name() string {
+	return Notification
+}
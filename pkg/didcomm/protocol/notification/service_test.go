@@ -0,0 +1,118 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package notification
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/model"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+)
+
+const (
+	myDID    = "did:example:alice"
+	theirDID = "did:example:bob"
+)
+
+func TestNew(t *testing.T) {
+	svc := New()
+	require.Equal(t, Notification, svc.Name())
+	require.True(t, svc.Accept(model.AckMsgType))
+	require.True(t, svc.Accept(model.ProblemReportMsgType))
+	require.False(t, svc.Accept("unknown"))
+
+	_, err := svc.HandleOutbound(service.DIDCommMsgMap{}, myDID, theirDID)
+	require.EqualError(t, err, "not implemented")
+}
+
+func TestService_HandleInbound(t *testing.T) {
+	t.Run("ack", func(t *testing.T) {
+		svc := New()
+
+		events := make(chan service.StateMsg, 1)
+		require.NoError(t, svc.RegisterMsgEvent(events))
+
+		thID, err := svc.HandleInbound(service.NewDIDCommMsgMap(model.Ack{
+			Type:   model.AckMsgType,
+			ID:     "ack-1",
+			Status: model.AckStatusOK,
+			Thread: &decorator.Thread{ID: "msg-1"},
+		}), myDID, theirDID)
+		require.NoError(t, err)
+		require.Equal(t, "msg-1", thID)
+
+		select {
+		case event := <-events:
+			require.Equal(t, Notification, event.ProtocolName)
+			require.Equal(t, service.PostState, event.Type)
+			require.Equal(t, StateAck, event.StateID)
+			require.Equal(t, "ack-1", event.Msg.ID())
+			require.Equal(t, map[string]interface{}{
+				myDIDPropKey:          myDID,
+				theirDIDPropKey:       theirDID,
+				threadIDPropKey:       "msg-1",
+				parentThreadIDPropKey: "",
+			}, event.Properties.All())
+		case <-time.After(time.Second):
+			t.Fatal("timeout")
+		}
+	})
+
+	t.Run("unthreaded problem report", func(t *testing.T) {
+		svc := New()
+
+		events := make(chan service.StateMsg, 1)
+		require.NoError(t, svc.RegisterMsgEvent(events))
+
+		thID, err := svc.HandleInbound(service.NewDIDCommMsgMap(model.ProblemReport{
+			Type:        model.ProblemReportMsgType,
+			ID:          "report-1",
+			Description: model.Code{Code: "unreachable", En: "the mediator is unreachable"},
+			Impact:      "connection",
+		}), myDID, theirDID)
+		require.NoError(t, err)
+		require.Equal(t, "report-1", thID)
+
+		select {
+		case event := <-events:
+			require.Equal(t, StateProblemReport, event.StateID)
+
+			report := model.ProblemReport{}
+			require.NoError(t, event.Msg.Decode(&report))
+			require.Equal(t, "unreachable", report.Description.Code)
+			require.Equal(t, "the mediator is unreachable", report.Description.En)
+			require.Equal(t, "connection", report.Impact)
+
+			props, ok := event.Properties.(*eventProps)
+			require.True(t, ok)
+			require.Equal(t, myDID, props.MyDID())
+			require.Equal(t, theirDID, props.TheirDID())
+			require.Equal(t, "report-1", props.ThreadID())
+			require.Empty(t, props.ParentThreadID())
+		case <-time.After(time.Second):
+			t.Fatal("timeout")
+		}
+	})
+
+	t.Run("unsupported message type", func(t *testing.T) {
+		_, err := New().HandleInbound(service.DIDCommMsgMap{"@type": "unknown"}, myDID, theirDID)
+		require.EqualError(t, err, "unsupported message type unknown")
+	})
+
+	t.Run("invalid thread", func(t *testing.T) {
+		_, err := New().HandleInbound(service.DIDCommMsgMap{
+			"@type":   model.AckMsgType,
+			"~thread": map[string]interface{}{"thid": "msg-1"},
+		}, myDID, theirDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "thread ID")
+	})
+}
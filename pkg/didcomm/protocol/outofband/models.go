@@ -28,4 +28,23 @@ type Invitation struct {
 	GoalCode  string        `json:"goal_code,omitempty"`
 	Service   []interface{} `json:"service"` // Service is an array of either DIDs or 'service' block entries.
 	Protocols []string      `json:"handshake_protocols"`
+	// MultiUse allows the invitation to create many connections, an invitation is single-use by default. It's kept
+	// by the inviter and not shared with the invitee.
+	MultiUse bool `json:"-"`
+}
+
+// HandshakeReuse is this protocol's `handshake-reuse` message, the invitee sends it over an existing connection with
+// the inviter instead of starting a new DID exchange. The parent thread ID is the ID of the invitation.
+type HandshakeReuse struct {
+	ID     string            `json:"@id"`
+	Type   string            `json:"@type"`
+	Thread *decorator.Thread `json:"~thread,omitempty"`
+}
+
+// HandshakeReuseAccepted is this protocol's `handshake-reuse-accepted` message, the inviter replies with it to the
+// `handshake-reuse` message.
+type HandshakeReuseAccepted struct {
+	ID     string            `json:"@id"`
+	Type   string            `json:"@type"`
+	Thread *decorator.Thread `json:"~thread,omitempty"`
 }
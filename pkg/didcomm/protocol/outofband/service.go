@@ -31,11 +31,19 @@ const (
 	RequestMsgType = "https://didcomm.org/oob-request/1.0/request"
 	// InvitationMsgType is the '@type' for the invitation message.
 	InvitationMsgType = "https://didcomm.org/out-of-band/1.0/invitation"
+	// HandshakeReuseMsgType is the '@type' for the handshake-reuse message.
+	HandshakeReuseMsgType = "https://didcomm.org/out-of-band/1.0/handshake-reuse"
+	// HandshakeReuseAcceptedMsgType is the '@type' for the handshake-reuse-accepted message.
+	HandshakeReuseAcceptedMsgType = "https://didcomm.org/out-of-band/1.0/handshake-reuse-accepted"
 
 	// StateRequested is one of the possible states of this protocol.
 	StateRequested = "requested"
 	// StateInvited is this protocol's state after accepting an invitation.
 	StateInvited = "invited"
+	// StateAwaitResponse is this protocol's state after sending a handshake-reuse message.
+	StateAwaitResponse = "await-response"
+	// StateDone is this protocol's state once an existing connection is reused.
+	StateDone = "done"
 
 	// TODO channel size - https://github.com/hyperledger/aries-framework-go/issues/246
 	callbackChannelSize = 10
@@ -69,6 +77,7 @@ type Service struct {
 	store                      storage.Store
 	connections                *connection.Recorder
 	outboundHandler            service.OutboundHandler
+	messenger                  service.Messenger
 	chooseRequestFunc          func(*myState) (*decorator.Attachment, bool)
 	extractDIDCommMsgBytesFunc func(*decorator.Attachment) ([]byte, error)
 	listenerFunc               func()
@@ -82,12 +91,14 @@ type callback struct {
 }
 
 type myState struct {
-	// ID becomes the parent thread ID of didexchange
+	// ID becomes the parent thread ID of didexchange, it's the thread ID of the handshake-reuse message when an
+	// existing connection is reused
 	ID           string
 	ConnectionID string
 	Request      *Request
 	Invitation   *Invitation
 	Done         bool
+	Reused       bool
 }
 
 // Action contains helpful information about action.
@@ -111,6 +122,7 @@ type Provider interface {
 	StorageProvider() storage.Provider
 	ProtocolStateStorageProvider() storage.Provider
 	OutboundMessageHandler() service.OutboundHandler
+	Messenger() service.Messenger
 }
 
 // New creates a new instance of the out-of-band service.
@@ -148,6 +160,7 @@ func New(p Provider) (*Service, error) {
 		store:                      store,
 		connections:                connectionRecorder,
 		outboundHandler:            p.OutboundMessageHandler(),
+		messenger:                  p.Messenger(),
 		chooseRequestFunc:          chooseRequest,
 		extractDIDCommMsgBytesFunc: extractDIDCommMsgBytes,
 	}
@@ -175,7 +188,19 @@ func (s *Service) Name() string {
 
 // Accept determines whether this service can handle the given type of message.
 func (s *Service) Accept(msgType string) bool {
-	return msgType == RequestMsgType || msgType == InvitationMsgType
+	switch msgType {
+	case RequestMsgType, InvitationMsgType, HandshakeReuseMsgType, HandshakeReuseAcceptedMsgType:
+		return true
+	}
+
+	return false
+}
+
+// MessageTypes returns the message types handled by the service.
+func (s *Service) MessageTypes() []string {
+	return []string{
+		RequestMsgType, InvitationMsgType, HandshakeReuseMsgType, HandshakeReuseAcceptedMsgType,
+	}
 }
 
 // HandleInbound handles inbound messages.
@@ -186,6 +211,13 @@ func (s *Service) HandleInbound(msg service.DIDCommMsg, myDID, theirDID string)
 		return "", fmt.Errorf("unsupported message type %s", msg.Type())
 	}
 
+	switch msg.Type() {
+	case HandshakeReuseMsgType:
+		return s.handleHandshakeReuse(msg, myDID, theirDID)
+	case HandshakeReuseAcceptedMsgType:
+		return s.handleHandshakeReuseAccepted(msg)
+	}
+
 	events := s.ActionEvent()
 	if events == nil {
 		return "", fmt.Errorf("no clients registered to handle action events for %s protocol", Name)
@@ -354,9 +386,12 @@ func sendMsgEvent(t service.StateMsgType, listeners *service.Message,
 	msg service.DIDCommMsg, p service.EventProperties) {
 	var stateName string
 
-	if msg.Type() == RequestMsgType {
+	switch msg.Type() {
+	case RequestMsgType:
 		stateName = StateRequested
-	} else {
+	case HandshakeReuseMsgType, HandshakeReuseAcceptedMsgType:
+		stateName = StateDone
+	default:
 		stateName = StateInvited
 	}
 
@@ -381,7 +416,9 @@ func (s *Service) HandleOutbound(_ service.DIDCommMsg, _, _ string) (string, err
 	return "", errors.New("not implemented")
 }
 
-// AcceptRequest from another agent and return the connection ID.
+// AcceptRequest from another agent and return the connection ID. If the request includes the public DID of an agent
+// we already have a connection with, the existing connection is reused (handshake-reuse) and its ID is returned,
+// the attached request is sent over it once the other agent accepts the reuse.
 func (s *Service) AcceptRequest(r *Request, myLabel string, routerConnections []string) (string, error) {
 	connID, err := s.handleCallback(&callback{
 		msg:     service.NewDIDCommMsgMap(r),
@@ -394,7 +431,8 @@ func (s *Service) AcceptRequest(r *Request, myLabel string, routerConnections []
 	return connID, err
 }
 
-// AcceptInvitation from another agent and return the connection ID.
+// AcceptInvitation from another agent and return the connection ID. If the invitation includes the public DID of an
+// agent we already have a connection with, the existing connection is reused (handshake-reuse) and its ID is returned.
 func (s *Service) AcceptInvitation(i *Invitation, myLabel string, routerConnections []string) (string, error) {
 	connID, err := s.handleCallback(&callback{
 		msg:     service.NewDIDCommMsgMap(i),
@@ -451,6 +489,7 @@ func (s *Service) SaveInvitation(i *Invitation) error {
 		ThreadID:   i.ID,
 		TheirLabel: i.Label,
 		Target:     target,
+		MultiUse:   i.MultiUse,
 	})
 	if err != nil {
 		return fmt.Errorf("the didexchange service failed to save the oob invitation : %w", err)
@@ -519,6 +558,16 @@ func (s *Service) handleRequestCallback(c *callback) (string, error) {
 		return "", fmt.Errorf("failed to decode didexchange invitation and out-of-band request : %w", err)
 	}
 
+	record, err := s.findReusableConnection(req.Service)
+	if err != nil {
+		return "", fmt.Errorf("handleRequestCallback: %w", err)
+	}
+
+	// the attached request is sent over the reused connection once the handshake-reuse is accepted
+	if record != nil {
+		return s.reuseConnection(record, req.ID, &myState{Request: req})
+	}
+
 	state := &myState{
 		// the pthid of the didexchange thread will equal this invitation's ID as per the RFC
 		ID:      invitation.ThreadID,
@@ -557,6 +606,15 @@ func (s *Service) handleInvitationCallback(c *callback) (string, error) {
 		return "", fmt.Errorf("handleInvitationCallback: failed to decode callback message : %w", err)
 	}
 
+	record, err := s.findReusableConnection(oobInv.Service)
+	if err != nil {
+		return "", fmt.Errorf("handleInvitationCallback: %w", err)
+	}
+
+	if record != nil {
+		return s.reuseConnection(record, oobInv.ID, &myState{Invitation: oobInv})
+	}
+
 	connID, err := s.didSvc.RespondTo(didInv, c.options.RouterConnections())
 	if err != nil {
 		return "", fmt.Errorf("didexchange service failed to handle inbound invitation : %w", err)
@@ -576,6 +634,160 @@ func (s *Service) handleInvitationCallback(c *callback) (string, error) {
 	return connID, nil
 }
 
+// findReusableConnection returns a completed connection with one of the public DIDs of the invitation or
+// the request, if any.
+func (s *Service) findReusableConnection(services []interface{}) (*connection.Record, error) {
+	publicDIDs := map[string]bool{}
+
+	for _, svc := range services {
+		if d, ok := svc.(string); ok {
+			publicDIDs[d] = true
+		}
+	}
+
+	if len(publicDIDs) == 0 {
+		return nil, nil
+	}
+
+	records, err := s.connections.QueryConnectionRecords()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query connection records : %w", err)
+	}
+
+	for _, record := range records {
+		if record.State != connection.StateNameCompleted {
+			continue
+		}
+
+		if publicDIDs[record.TheirDID] || publicDIDs[record.InvitationDID] {
+			return record, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// reuseConnection sends a handshake-reuse message over the existing connection instead of starting a new DID exchange,
+// the parent thread ID is the ID of the invitation or the request kept in the state.
+func (s *Service) reuseConnection(record *connection.Record, pthID string, state *myState) (string, error) {
+	reuse := &HandshakeReuse{
+		ID:     uuid.New().String(),
+		Type:   HandshakeReuseMsgType,
+		Thread: &decorator.Thread{PID: pthID},
+	}
+
+	state.ID = reuse.ID
+	state.ConnectionID = record.ConnectionID
+	state.Reused = true
+
+	err := s.save(state)
+	if err != nil {
+		return "", fmt.Errorf("failed to save my state : %w", err)
+	}
+
+	err = s.messenger.ReplyToNested(service.NewDIDCommMsgMap(reuse), &service.NestedReplyOpts{
+		ThreadID: pthID,
+		MyDID:    record.MyDID,
+		TheirDID: record.TheirDID,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to send handshake-reuse message : %w", err)
+	}
+
+	logger.Debugf("reusing connection %s for out-of-band message %s", record.ConnectionID, pthID)
+
+	return record.ConnectionID, nil
+}
+
+// handleHandshakeReuse replies to the handshake-reuse message of an invitee reusing an existing connection for one
+// of our invitations.
+func (s *Service) handleHandshakeReuse(msg service.DIDCommMsg, myDID, theirDID string) (string, error) {
+	if myDID == "" || theirDID == "" {
+		return "", errors.New("handshake-reuse message received out of an existing connection")
+	}
+
+	invID := msg.ParentThreadID()
+
+	// TODO where should we save this invitation? - https://github.com/hyperledger/aries-framework-go/issues/1547
+	err := s.connections.GetInvitation(invID+"-TODO", &Invitation{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get the invitation %s of the handshake-reuse message : %w", invID, err)
+	}
+
+	connID, err := s.connections.GetConnectionIDByDIDs(myDID, theirDID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get the reused connection : %w", err)
+	}
+
+	thID, err := msg.ThreadID()
+	if err != nil {
+		return "", fmt.Errorf("threadID: %w", err)
+	}
+
+	err = s.messenger.ReplyToMsg(msg.Clone(), service.NewDIDCommMsgMap(&HandshakeReuseAccepted{
+		ID:     uuid.New().String(),
+		Type:   HandshakeReuseAcceptedMsgType,
+		Thread: &decorator.Thread{ID: thID, PID: invID},
+	}), myDID, theirDID)
+	if err != nil {
+		return "", fmt.Errorf("failed to send handshake-reuse-accepted message : %w", err)
+	}
+
+	go sendMsgEvent(service.PostState, &s.Message, msg, &eventProps{ConnID: connID})
+
+	return connID, nil
+}
+
+// handleHandshakeReuseAccepted completes the reuse of a connection once the inviter accepted it, the request
+// attached to an out-of-band request is sent over the reused connection.
+func (s *Service) handleHandshakeReuseAccepted(msg service.DIDCommMsg) (string, error) {
+	thID, err := msg.ThreadID()
+	if err != nil {
+		return "", fmt.Errorf("threadID: %w", err)
+	}
+
+	state, err := s.fetchMyState(thID)
+	if err != nil {
+		return "", fmt.Errorf("failed to load the state of the handshake-reuse : %w", err)
+	}
+
+	if !state.Reused || state.Done {
+		return "", fmt.Errorf("unexpected handshake-reuse-accepted message for thread %s", thID)
+	}
+
+	var request service.DIDCommMsg
+
+	if state.Request != nil {
+		request, err = s.extractDIDCommMsg(state)
+		if err != nil {
+			return "", fmt.Errorf("failed to extract DIDComm msg : %w", err)
+		}
+	}
+
+	state.Done = true
+
+	err = s.save(state)
+	if err != nil {
+		return "", fmt.Errorf("failed to update state : %w", err)
+	}
+
+	if request != nil {
+		record, err := s.connections.GetConnectionRecord(state.ConnectionID)
+		if err != nil {
+			return "", fmt.Errorf("failed to get the reused connection : %w", err)
+		}
+
+		_, err = s.outboundHandler.HandleOutbound(request, record.MyDID, record.TheirDID)
+		if err != nil {
+			return "", fmt.Errorf("failed to dispatch message : %w", err)
+		}
+	}
+
+	go sendMsgEvent(service.PostState, &s.Message, msg, &eventProps{ConnID: state.ConnectionID})
+
+	return state.ConnectionID, nil
+}
+
 func (s *Service) handleDIDEvent(e service.StateMsg) error {
 	logger.Debugf("input: %+v", e)
 
@@ -661,7 +873,7 @@ func (s *Service) fetchMyState(id string) (*myState, error) {
 //  - https://github.com/hyperledger/aries-rfcs/issues/451
 //  This logic should be injected into the service.
 func chooseRequest(state *myState) (*decorator.Attachment, bool) {
-	if !state.Done {
+	if !state.Done && len(state.Request.Requests) > 0 {
 		return state.Request.Requests[0], true
 	}
 
@@ -18,6 +18,7 @@ import (
 
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/model"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/transport"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didexchange"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
@@ -109,6 +110,12 @@ func TestAccept(t *testing.T) {
 		require.NoError(t, err)
 		require.True(t, s.Accept("https://didcomm.org/out-of-band/1.0/invitation"))
 	})
+	t.Run("accepts handshake-reuse messages", func(t *testing.T) {
+		s, err := New(testProvider())
+		require.NoError(t, err)
+		require.True(t, s.Accept("https://didcomm.org/out-of-band/1.0/handshake-reuse"))
+		require.True(t, s.Accept("https://didcomm.org/out-of-band/1.0/handshake-reuse-accepted"))
+	})
 	t.Run("rejects unsupported messages", func(t *testing.T) {
 		s, err := New(testProvider())
 		require.NoError(t, err)
@@ -587,7 +594,7 @@ func TestHandleDIDEvent(t *testing.T) {
 				didexchange.DIDExchange: &mockdidexchange.MockDIDExchangeSvc{},
 			},
 		}
-		provider.InboundMsgHandler = func([]byte, string, string) error {
+		provider.InboundMsgHandler = func(*transport.Envelope) error {
 			return nil
 		}
 
@@ -944,6 +951,240 @@ func TestAcceptInvitation(t *testing.T) {
 	})
 }
 
+func TestHandshakeReuse(t *testing.T) {
+	const (
+		inviterDID = "did:example:inviter"
+		inviteeDID = "did:example:invitee"
+	)
+
+	completedConnection := func(t *testing.T, s *Service, myDID, theirDID string) string {
+		t.Helper()
+
+		record := &connection.Record{
+			ConnectionID: uuid.New().String(),
+			ThreadID:     uuid.New().String(),
+			State:        connection.StateNameCompleted,
+			Namespace:    connection.MyNSPrefix,
+			MyDID:        myDID,
+			TheirDID:     theirDID,
+		}
+		require.NoError(t, s.connections.SaveConnectionRecord(record))
+
+		return record.ConnectionID
+	}
+
+	// wire delivers the messages of the invitee to the inviter and the replies of the inviter back to the invitee
+	wire := func(t *testing.T, inviter, invitee *Service) {
+		t.Helper()
+
+		inviter.messenger = &stubMessenger{replyToMsg: func(in, out service.DIDCommMsgMap, myDID, theirDID string) error {
+			thID, err := in.ThreadID()
+			require.NoError(t, err)
+
+			out["~thread"] = map[string]interface{}{"thid": thID, "pthid": in.ParentThreadID()}
+
+			_, err = invitee.HandleInbound(out, theirDID, myDID)
+
+			return err
+		}}
+		invitee.messenger = &stubMessenger{replyToNested: func(msg service.DIDCommMsgMap,
+			opts *service.NestedReplyOpts) error {
+			msg["~thread"] = map[string]interface{}{"pthid": opts.ThreadID}
+
+			_, err := inviter.HandleInbound(msg, opts.TheirDID, opts.MyDID)
+
+			return err
+		}}
+	}
+
+	t.Run("reuses the existing connection with the inviter public DID", func(t *testing.T) {
+		inviter := newAutoService(t, testProvider())
+		invitee := newAutoService(t, testProvider(), func(s *Service) {
+			s.didSvc = &mockdidexchange.MockDIDExchangeSvc{
+				RespondToFunc: func(*didexchange.OOBInvitation, []string) (string, error) {
+					return "", errors.New("unexpected did exchange")
+				},
+			}
+		})
+
+		inviterConnID := completedConnection(t, inviter, inviterDID, inviteeDID)
+		inviteeConnID := completedConnection(t, invitee, inviteeDID, inviterDID)
+
+		wire(t, inviter, invitee)
+
+		inviterEvents := make(chan service.StateMsg, 1)
+		require.NoError(t, inviter.RegisterMsgEvent(inviterEvents))
+
+		inviteeEvents := make(chan service.StateMsg, 1)
+		require.NoError(t, invitee.RegisterMsgEvent(inviteeEvents))
+
+		inv := newInvitation()
+		inv.Service = []interface{}{inviterDID}
+		require.NoError(t, inviter.SaveInvitation(inv))
+
+		connID, err := invitee.AcceptInvitation(inv, "", nil)
+		require.NoError(t, err)
+		require.Equal(t, inviteeConnID, connID)
+
+		for _, events := range []chan service.StateMsg{inviterEvents, inviteeEvents} {
+			select {
+			case e := <-events:
+				require.Equal(t, StateDone, e.StateID)
+
+				props, ok := e.Properties.(*eventProps)
+				require.True(t, ok)
+				require.Contains(t, []string{inviterConnID, inviteeConnID}, props.ConnectionID())
+			case <-time.After(time.Second):
+				require.Fail(t, "timeout waiting for the handshake-reuse events")
+			}
+		}
+	})
+
+	t.Run("sends the attached request over the reused connection", func(t *testing.T) {
+		var sent []service.DIDCommMsg
+
+		provider := testProvider()
+		provider.OutboundMsgHandler = &outboundMsgHandlerStub{
+			handleFunc: func(msg service.DIDCommMsg, myDID, theirDID string) (string, error) {
+				require.Equal(t, inviteeDID, myDID)
+				require.Equal(t, inviterDID, theirDID)
+
+				sent = append(sent, msg)
+
+				return "", nil
+			},
+		}
+
+		inviter := newAutoService(t, testProvider())
+		invitee := newAutoService(t, provider, func(s *Service) {
+			s.didSvc = &mockdidexchange.MockDIDExchangeSvc{
+				RespondToFunc: func(*didexchange.OOBInvitation, []string) (string, error) {
+					return "", errors.New("unexpected did exchange")
+				},
+			}
+		})
+
+		completedConnection(t, inviter, inviterDID, inviteeDID)
+		inviteeConnID := completedConnection(t, invitee, inviteeDID, inviterDID)
+
+		wire(t, inviter, invitee)
+
+		req := newRequest()
+		req.Service = []interface{}{inviterDID}
+		require.NoError(t, inviter.SaveRequest(req))
+
+		connID, err := invitee.AcceptRequest(req, "", nil)
+		require.NoError(t, err)
+		require.Equal(t, inviteeConnID, connID)
+
+		require.Len(t, sent, 1)
+		require.Equal(t, "test-type", sent[0].Type())
+		require.Equal(t, "123", sent[0].ID())
+	})
+
+	t.Run("fails to send the attached request over the reused connection", func(t *testing.T) {
+		provider := testProvider()
+		provider.OutboundMsgHandler = &outboundMsgHandlerStub{
+			handleFunc: func(service.DIDCommMsg, string, string) (string, error) {
+				return "", errors.New("send error")
+			},
+		}
+
+		inviter := newAutoService(t, testProvider())
+		invitee := newAutoService(t, provider)
+
+		completedConnection(t, inviter, inviterDID, inviteeDID)
+		completedConnection(t, invitee, inviteeDID, inviterDID)
+
+		wire(t, inviter, invitee)
+
+		req := newRequest()
+		req.Service = []interface{}{inviterDID}
+		require.NoError(t, inviter.SaveRequest(req))
+
+		_, err := invitee.AcceptRequest(req, "", nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to dispatch message : send error")
+	})
+
+	t.Run("starts a did exchange without connection with the inviter", func(t *testing.T) {
+		invitee := newAutoService(t, testProvider(), func(s *Service) {
+			s.didSvc = &mockdidexchange.MockDIDExchangeSvc{
+				RespondToFunc: func(*didexchange.OOBInvitation, []string) (string, error) {
+					return "new-connection", nil
+				},
+			}
+		})
+
+		completedConnection(t, invitee, inviteeDID, "did:example:other")
+
+		inv := newInvitation()
+		inv.Service = []interface{}{inviterDID}
+
+		connID, err := invitee.AcceptInvitation(inv, "", nil)
+		require.NoError(t, err)
+		require.Equal(t, "new-connection", connID)
+	})
+
+	t.Run("fails to send the handshake-reuse message", func(t *testing.T) {
+		invitee := newAutoService(t, testProvider())
+		invitee.messenger = &stubMessenger{replyToNested: func(service.DIDCommMsgMap,
+			*service.NestedReplyOpts) error {
+			return errors.New("send error")
+		}}
+
+		completedConnection(t, invitee, inviteeDID, inviterDID)
+
+		inv := newInvitation()
+		inv.Service = []interface{}{inviterDID}
+
+		_, err := invitee.AcceptInvitation(inv, "", nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to send handshake-reuse message : send error")
+	})
+
+	t.Run("rejects invalid handshake-reuse messages", func(t *testing.T) {
+		s := newAutoService(t, testProvider())
+
+		reuse := service.NewDIDCommMsgMap(&HandshakeReuse{
+			ID:     uuid.New().String(),
+			Type:   HandshakeReuseMsgType,
+			Thread: &decorator.Thread{PID: uuid.New().String()},
+		})
+
+		_, err := s.HandleInbound(reuse, "", "")
+		require.EqualError(t, err, "handshake-reuse message received out of an existing connection")
+
+		_, err = s.HandleInbound(reuse, inviterDID, inviteeDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to get the invitation")
+
+		accepted := service.NewDIDCommMsgMap(&HandshakeReuseAccepted{
+			ID:     uuid.New().String(),
+			Type:   HandshakeReuseAcceptedMsgType,
+			Thread: &decorator.Thread{ID: uuid.New().String()},
+		})
+
+		_, err = s.HandleInbound(accepted, inviteeDID, inviterDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to load the state of the handshake-reuse")
+	})
+}
+
+type stubMessenger struct {
+	service.Messenger
+	replyToMsg    func(in, out service.DIDCommMsgMap, myDID, theirDID string) error
+	replyToNested func(msg service.DIDCommMsgMap, opts *service.NestedReplyOpts) error
+}
+
+func (m *stubMessenger) ReplyToMsg(in, out service.DIDCommMsgMap, myDID, theirDID string) error {
+	return m.replyToMsg(in, out, myDID, theirDID)
+}
+
+func (m *stubMessenger) ReplyToNested(msg service.DIDCommMsgMap, opts *service.NestedReplyOpts) error {
+	return m.replyToNested(msg, opts)
+}
+
 func TestSaveRequest(t *testing.T) {
 	t.Run("saves request", func(t *testing.T) {
 		expected := newRequest()
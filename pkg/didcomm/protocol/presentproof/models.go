@@ -22,6 +22,8 @@ type ProposePresentation struct {
 	// ProposalsAttach is an array of attachments that further define the presentation request being proposed.
 	// This might be used to clarify which formats or format versions are wanted.
 	ProposalsAttach []decorator.Attachment `json:"proposals~attach,omitempty"`
+	// Timing is an optional ~timing decorator, the message is not accepted after its expires_time.
+	Timing *decorator.Timing `json:"~timing,omitempty"`
 }
 
 // RequestPresentation describes values that need to be revealed and predicates that need to be fulfilled.
@@ -38,10 +40,11 @@ type RequestPresentation struct {
 	Formats []Format `json:"formats,omitempty"`
 	// RequestPresentationsAttach is an array of attachments containing the acceptable verifiable presentation requests.
 	RequestPresentationsAttach []decorator.Attachment `json:"request_presentations~attach,omitempty"`
+	// Timing is an optional ~timing decorator, the message is not accepted after its expires_time.
+	Timing *decorator.Timing `json:"~timing,omitempty"`
 }
 
 // Presentation is a response to a RequestPresentation message and contains signed presentations.
-// TODO: Add ~please_ack decorator support for the protocol [Issue #2047].
 type Presentation struct {
 	Type string `json:"@type,omitempty"`
 	// Comment is a field that provides some human readable information about the proposed presentation.
@@ -52,6 +55,11 @@ type Presentation struct {
 	Formats []Format `json:"formats,omitempty"`
 	// PresentationsAttach an array of attachments containing the presentation in the requested format(s).
 	PresentationsAttach []decorator.Attachment `json:"presentations~attach,omitempty"`
+	// Timing is an optional ~timing decorator, the message is not accepted after its expires_time.
+	Timing *decorator.Timing `json:"~timing,omitempty"`
+	// PleaseAck is an optional ~please_ack decorator, the verifier acknowledges the presentation if it is set
+	// even when the request did not ask for a confirmation.
+	PleaseAck *decorator.PleaseAck `json:"~please_ack,omitempty"`
 }
 
 // Format contains the the value of the attachment @id and the verifiable credential format of the attachment.
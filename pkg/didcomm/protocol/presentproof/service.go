@@ -14,6 +14,7 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/hyperledger/aries-framework-go/pkg/common/log"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/expiry"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
 	"github.com/hyperledger/aries-framework-go/spi/storage"
@@ -177,6 +178,24 @@ type Provider interface {
 	StorageProvider() storage.Provider
 }
 
+// ServiceOption configures the presentproof service.
+type ServiceOption func(opts *serviceOptions)
+
+type serviceOptions struct {
+	expiry     bool
+	expiryOpts []expiry.Option
+}
+
+// WithExpiry enables the reaper which abandons the protocol instances that have expired
+// either because the ~timing.expires_time of their last message has passed or because they stayed idle
+// longer than the timeout provided with the options.
+func WithExpiry(opts ...expiry.Option) ServiceOption {
+	return func(o *serviceOptions) {
+		o.expiry = true
+		o.expiryOpts = opts
+	}
+}
+
 // Service for the presentproof protocol.
 type Service struct {
 	service.Action
@@ -185,10 +204,11 @@ type Service struct {
 	callbacks  chan *metaData
 	messenger  service.Messenger
 	middleware Handler
+	reaper     *expiry.Reaper
 }
 
 // New returns the presentproof service.
-func New(p Provider) (*Service, error) {
+func New(p Provider, opts ...ServiceOption) (*Service, error) {
 	store, err := p.StorageProvider().OpenStore(Name)
 	if err != nil {
 		return nil, err
@@ -206,6 +226,21 @@ func New(p Provider) (*Service, error) {
 		middleware: initialHandler,
 	}
 
+	svcOpts := &serviceOptions{}
+
+	for _, opt := range opts {
+		opt(svcOpts)
+	}
+
+	if svcOpts.expiry {
+		svc.reaper, err = expiry.NewReaper(p.StorageProvider(), Name, svc.abandonExpired, svcOpts.expiryOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("new reaper: %w", err)
+		}
+
+		svc.reaper.Start()
+	}
+
 	// start the listener
 	go svc.startInternalListener()
 
@@ -237,6 +272,10 @@ func (s *Service) HandleInbound(msg service.DIDCommMsg, myDID, theirDID string)
 		return "", errors.New("no clients are registered to handle the message")
 	}
 
+	if err := s.checkExpiry(msgMap); err != nil {
+		return "", err
+	}
+
 	md, err := s.doHandle(msgMap)
 	if err != nil {
 		return "", fmt.Errorf("doHandle: %w", err)
@@ -251,6 +290,11 @@ func (s *Service) HandleInbound(msg service.DIDCommMsg, myDID, theirDID string)
 		if err != nil {
 			return "", fmt.Errorf("save transitional payload: %w", err)
 		}
+
+		if err = s.trackInstance(md); err != nil {
+			return "", fmt.Errorf("track instance: %w", err)
+		}
+
 		aEvent <- s.newDIDCommActionMsg(md)
 
 		return "", nil
@@ -376,6 +420,67 @@ func (s *Service) handle(md *metaData) error {
 		current = next
 	}
 
+	if err := s.trackInstance(md); err != nil {
+		return fmt.Errorf("track instance: %w", err)
+	}
+
+	return nil
+}
+
+// checkExpiry rejects the message if the protocol instance it belongs to has expired.
+func (s *Service) checkExpiry(msg service.DIDCommMsg) error {
+	if s.reaper == nil {
+		return nil
+	}
+
+	piID, err := getPIID(msg)
+	if errors.Is(err, service.ErrThreadIDNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("piID: %w", err)
+	}
+
+	return s.reaper.Check(piID)
+}
+
+// trackInstance keeps the reaper informed about the activity of the protocol instance.
+func (s *Service) trackInstance(md *metaData) error {
+	if s.reaper == nil {
+		return nil
+	}
+
+	switch md.state.Name() {
+	case stateNameDone, stateNameAbandoned:
+		return s.reaper.Remove(md.PIID)
+	}
+
+	return s.reaper.Touch(md.PIID, md.Msg, md.MyDID, md.TheirDID)
+}
+
+// abandonExpired abandons the protocol instance and notifies another agent with the problem report.
+func (s *Service) abandonExpired(instance *expiry.Instance) error {
+	err := s.deleteTransitionalPayload(instance.PIID)
+	if err != nil && !errors.Is(err, storage.ErrDataNotFound) {
+		return fmt.Errorf("delete transitional payload: %w", err)
+	}
+
+	s.processCallback(&metaData{
+		transitionalPayload: transitionalPayload{
+			StateName: stateNameAbandoned,
+			Action: Action{
+				PIID:     instance.PIID,
+				Msg:      instance.Msg,
+				MyDID:    instance.MyDID,
+				TheirDID: instance.TheirDID,
+			},
+		},
+		state:      &abandoned{Code: expiry.ProblemCode},
+		msgClone:   instance.Msg.Clone(),
+		properties: map[string]interface{}{errorPropKey: expiry.ErrExpired},
+	})
+
 	return nil
 }
 
@@ -677,6 +782,21 @@ func (s *Service) Name() string {
 	return Name
 }
 
+// Close stops the reaper of the expired protocol instances, if the expiry is enabled.
+func (s *Service) Close() error {
+	if s.reaper != nil {
+		s.reaper.Stop()
+	}
+
+	return nil
+}
+
+// Acknowledges checks whether the ~please_ack decorator of the message type is handled by the protocol,
+// the presentation is acknowledged by the verifier with the ack of the protocol.
+func (s *Service) Acknowledges(msgType string) bool {
+	return msgType == PresentationMsgType
+}
+
 // Accept msg checks the msg type.
 func (s *Service) Accept(msgType string) bool {
 	switch msgType {
@@ -687,3 +807,11 @@ func (s *Service) Accept(msgType string) bool {
 
 	return false
 }
+
+// MessageTypes returns the message types handled by the service.
+func (s *Service) MessageTypes() []string {
+	return []string{
+		ProposePresentationMsgType, RequestPresentationMsgType, PresentationMsgType, AckMsgType,
+		ProblemReportMsgType,
+	}
+}
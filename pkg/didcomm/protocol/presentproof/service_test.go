@@ -19,6 +19,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/expiry"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/model"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
@@ -1081,6 +1082,12 @@ func TestService_Accept(t *testing.T) {
 	require.False(t, (*Service).Accept(nil, "unknown"))
 }
 
+func TestService_Acknowledges(t *testing.T) {
+	require.True(t, (*Service).Acknowledges(nil, PresentationMsgType))
+	require.False(t, (*Service).Acknowledges(nil, RequestPresentationMsgType))
+	require.False(t, (*Service).Acknowledges(nil, "unknown"))
+}
+
 func TestService_canTriggerActionEvents(t *testing.T) {
 	require.True(t, canTriggerActionEvents(service.NewDIDCommMsgMap(ProposePresentation{
 		Type: ProposePresentationMsgType,
@@ -1158,3 +1165,95 @@ func Test_nextState(t *testing.T) {
 	require.Error(t, err)
 	require.Nil(t, next)
 }
+
+func TestService_Expiry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	messenger := serviceMocks.NewMockMessenger(ctrl)
+
+	provider := presentproofMocks.NewMockProvider(ctrl)
+	provider.EXPECT().Messenger().Return(messenger).AnyTimes()
+	provider.EXPECT().StorageProvider().Return(mem.NewProvider()).AnyTimes()
+
+	t.Run("Abandons expired request", func(t *testing.T) {
+		svc, err := New(provider, WithExpiry(expiry.WithInterval(10*time.Millisecond)))
+		require.NoError(t, err)
+
+		defer func() {
+			// the reaper is stopped and closing again is a no-op
+			require.NoError(t, svc.Close())
+			require.NoError(t, svc.Close())
+		}()
+
+		events := make(chan service.StateMsg, 10)
+		require.NoError(t, svc.RegisterMsgEvent(events))
+
+		messenger.EXPECT().Send(gomock.Any(), Alice, Bob).Return(nil)
+
+		done := make(chan struct{})
+
+		messenger.EXPECT().ReplyToNested(gomock.Any(), gomock.Any()).
+			Do(func(msg service.DIDCommMsgMap, opts *service.NestedReplyOpts) error {
+				defer close(done)
+
+				r := &model.ProblemReport{}
+				require.NoError(t, msg.Decode(r))
+				require.Equal(t, expiry.ProblemCode, r.Description.Code)
+				require.Equal(t, Alice, opts.MyDID)
+				require.Equal(t, Bob, opts.TheirDID)
+
+				return nil
+			})
+
+		piid, err := svc.HandleInbound(service.NewDIDCommMsgMap(RequestPresentation{
+			Type:   RequestPresentationMsgType,
+			Timing: &decorator.Timing{ExpiresTime: time.Now().Add(20 * time.Millisecond)},
+		}), Alice, Bob)
+		require.NoError(t, err)
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("timeout")
+		}
+
+		for {
+			select {
+			case event := <-events:
+				if event.StateID != stateNameAbandoned || event.Type != service.PostState {
+					continue
+				}
+
+				require.Equal(t, piid, event.Properties.All()[piidPropKey])
+				require.Equal(t, expiry.ErrExpired, event.Properties.All()[errorPropKey])
+
+				return
+			case <-time.After(time.Second):
+				t.Fatal("timeout")
+			}
+		}
+	})
+
+	t.Run("Rejects the presentation for expired request", func(t *testing.T) {
+		svc, err := New(provider, WithExpiry(expiry.WithInterval(time.Hour)))
+		require.NoError(t, err)
+
+		require.NoError(t, svc.RegisterActionEvent(make(chan<- service.DIDCommAction)))
+
+		messenger.EXPECT().Send(gomock.Any(), Alice, Bob).Return(nil)
+
+		piid, err := svc.HandleInbound(service.NewDIDCommMsgMap(RequestPresentation{
+			Type:   RequestPresentationMsgType,
+			Timing: &decorator.Timing{ExpiresTime: time.Now().Add(-time.Second)},
+		}), Alice, Bob)
+		require.NoError(t, err)
+
+		reply := service.NewDIDCommMsgMap(Presentation{Type: PresentationMsgType})
+		require.NoError(t, reply.SetID(uuid.New().String()))
+		reply[jsonThread] = map[string]interface{}{"thid": piid}
+
+		_, err = svc.HandleInbound(reply, Alice, Bob)
+		require.True(t, errors.Is(err, expiry.ErrExpired))
+	})
+}
@@ -248,7 +248,8 @@ func (s *presentationSent) Execute(md *metaData) (state, stateAction, error) {
 		return messenger.ReplyToMsg(md.Msg, service.NewDIDCommMsgMap(md.presentation), md.MyDID, md.TheirDID)
 	}
 
-	if !s.WillConfirm {
+	// the verifier acknowledges the presentation if it was asked to do so
+	if !s.WillConfirm && md.presentation.PleaseAck == nil {
 		return &done{}, action, nil
 	}
 
@@ -268,7 +269,13 @@ func (s *presentationReceived) CanTransitionTo(st state) bool {
 }
 
 func (s *presentationReceived) Execute(md *metaData) (state, stateAction, error) {
-	if !md.AckRequired {
+	presentation := Presentation{}
+
+	if err := md.Msg.Decode(&presentation); err != nil {
+		return nil, nil, fmt.Errorf("decode presentation: %w", err)
+	}
+
+	if !md.AckRequired && presentation.PleaseAck == nil {
 		return &done{}, zeroAction, nil
 	}
 
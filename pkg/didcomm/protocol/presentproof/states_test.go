@@ -375,6 +375,14 @@ func TestPresentationSent_Execute(t *testing.T) {
 		require.NoError(t, action(messenger))
 	})
 
+	t.Run("Success (PleaseAck)", func(t *testing.T) {
+		followup, action, err := (&presentationSent{}).
+			Execute(&metaData{presentation: &Presentation{PleaseAck: &decorator.PleaseAck{}}})
+		require.NoError(t, err)
+		require.Equal(t, &noOp{}, followup)
+		require.NotNil(t, action)
+	})
+
 	t.Run("Presentation is absent", func(t *testing.T) {
 		followup, action, err := (&presentationSent{}).Execute(&metaData{})
 		require.EqualError(t, err, "presentation was not provided")
@@ -420,6 +428,34 @@ func TestPresentationReceived_Execute(t *testing.T) {
 		require.NoError(t, action(messenger))
 	})
 
+	t.Run("Success (PleaseAck)", func(t *testing.T) {
+		followup, action, err := (&presentationReceived{}).Execute(&metaData{
+			transitionalPayload: transitionalPayload{Action: Action{Msg: service.NewDIDCommMsgMap(Presentation{
+				Type:      PresentationMsgType,
+				PleaseAck: &decorator.PleaseAck{On: []string{decorator.PleaseAckOnOutcome}},
+			})}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, &done{}, followup)
+		require.NotNil(t, action)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		messenger := serviceMocks.NewMockMessenger(ctrl)
+		messenger.EXPECT().ReplyToMsg(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any())
+
+		require.NoError(t, action(messenger))
+	})
+
+	t.Run("Decode error", func(t *testing.T) {
+		_, _, err := (&presentationReceived{}).Execute(&metaData{
+			transitionalPayload: transitionalPayload{Action: Action{Msg: service.DIDCommMsgMap{"~please_ack": "invalid"}}},
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "decode presentation")
+	})
+
 	t.Run("Ack is not required", func(t *testing.T) {
 		followup, action, err := (&presentationReceived{}).Execute(&metaData{
 			request:      &RequestPresentation{WillConfirm: true},
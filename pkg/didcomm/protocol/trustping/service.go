@@ -0,0 +1,215 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package trustping
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/dispatcher"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+)
+
+const (
+	// TrustPing defines the protocol name.
+	TrustPing = "trustping"
+	// Spec defines the protocol spec.
+	Spec = "https://didcomm.org/trust_ping/1.0/"
+	// PingMsgType defines the protocol ping message type.
+	PingMsgType = Spec + "ping"
+	// PingResponseMsgType defines the protocol ping response message type.
+	PingResponseMsgType = Spec + "ping_response"
+)
+
+// DefaultTimeout is the default time to wait for the ping response.
+const DefaultTimeout = 30 * time.Second
+
+var logger = log.New("aries-framework/trustping")
+
+// ErrTimeout is returned when the ping response is not received in time.
+var ErrTimeout = errors.New("timeout waiting for ping response")
+
+type provider interface {
+	OutboundDispatcher() dispatcher.Outbound
+	StorageProvider() storage.Provider
+	ProtocolStateStorageProvider() storage.Provider
+}
+
+type connections interface {
+	GetConnectionRecord(string) (*connection.Record, error)
+}
+
+// Service for the trust ping protocol. It responds to the pings requesting the response automatically.
+type Service struct {
+	outbound         dispatcher.Outbound
+	connectionLookup connections
+	pending          map[string]chan struct{}
+	pendingLock      sync.Mutex
+}
+
+// New returns the trust ping service.
+func New(prov provider) (*Service, error) {
+	connectionLookup, err := connection.NewLookup(prov)
+	if err != nil {
+		return nil, fmt.Errorf("new connection lookup: %w", err)
+	}
+
+	return &Service{
+		outbound:         prov.OutboundDispatcher(),
+		connectionLookup: connectionLookup,
+		pending:          make(map[string]chan struct{}),
+	}, nil
+}
+
+// HandleInbound handles inbound trust ping messages.
+func (s *Service) HandleInbound(msg service.DIDCommMsg, myDID, theirDID string) (string, error) {
+	switch msg.Type() {
+	case PingMsgType:
+		return "", s.handlePing(msg, myDID, theirDID)
+	case PingResponseMsgType:
+		return "", s.handlePingResponse(msg)
+	}
+
+	return "", fmt.Errorf("unsupported message type %s", msg.Type())
+}
+
+// HandleOutbound adherence to dispatcher.ProtocolService.
+func (s *Service) HandleOutbound(_ service.DIDCommMsg, _, _ string) (string, error) {
+	return "", errors.New("not implemented")
+}
+
+// Accept checks whether the service can handle the message type.
+func (s *Service) Accept(msgType string) bool {
+	return msgType == PingMsgType || msgType == PingResponseMsgType
+}
+
+// MessageTypes returns the message types handled by the service.
+func (s *Service) MessageTypes() []string {
+	return []string{PingMsgType, PingResponseMsgType}
+}
+
+// Name of the service.
+func (s *Service) Name() string {
+	return TrustPing
+}
+
+// Ping sends the ping to the connection and waits for the response if it's requested.
+// Returns the round-trip time, which is zero if the response was not requested.
+func (s *Service) Ping(connectionID string, responseRequested bool, timeout time.Duration) (time.Duration, error) {
+	conn, err := s.connectionLookup.GetConnectionRecord(connectionID)
+	if err != nil {
+		return 0, fmt.Errorf("get connection record: %w", err)
+	}
+
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	ping := &Ping{
+		Type:              PingMsgType,
+		ID:                uuid.New().String(),
+		ResponseRequested: responseRequested,
+	}
+
+	if !responseRequested {
+		if err = s.outbound.SendToDID(ping, conn.MyDID, conn.TheirDID); err != nil {
+			return 0, fmt.Errorf("send ping: %w", err)
+		}
+
+		return 0, nil
+	}
+
+	responseCh := make(chan struct{}, 1)
+
+	s.setPending(ping.ID, responseCh)
+	defer s.setPending(ping.ID, nil)
+
+	start := time.Now()
+
+	if err = s.outbound.SendToDID(ping, conn.MyDID, conn.TheirDID); err != nil {
+		return 0, fmt.Errorf("send ping: %w", err)
+	}
+
+	select {
+	case <-responseCh:
+		return time.Since(start), nil
+	case <-time.After(timeout):
+		return 0, ErrTimeout
+	}
+}
+
+func (s *Service) handlePing(msg service.DIDCommMsg, myDID, theirDID string) error {
+	// response is requested unless the ping says otherwise
+	ping := &Ping{ResponseRequested: true}
+
+	if err := msg.Decode(ping); err != nil {
+		return fmt.Errorf("decode ping: %w", err)
+	}
+
+	if !ping.ResponseRequested {
+		logger.Debugf("ping %s received from %s, response is not requested", ping.ID, theirDID)
+
+		return nil
+	}
+
+	resp := &PingResponse{
+		Type:   PingResponseMsgType,
+		ID:     uuid.New().String(),
+		Thread: &decorator.Thread{ID: ping.ID},
+	}
+
+	if err := s.outbound.SendToDID(resp, myDID, theirDID); err != nil {
+		return fmt.Errorf("send ping response: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) handlePingResponse(msg service.DIDCommMsg) error {
+	thID, err := msg.ThreadID()
+	if err != nil {
+		return fmt.Errorf("ping response thread ID: %w", err)
+	}
+
+	s.pendingLock.Lock()
+	responseCh := s.pending[thID]
+	s.pendingLock.Unlock()
+
+	if responseCh == nil {
+		logger.Debugf("ping response %s received for unknown ping %s", msg.ID(), thID)
+
+		return nil
+	}
+
+	select {
+	case responseCh <- struct{}{}:
+	default:
+	}
+
+	return nil
+}
+
+func (s *Service) setPending(id string, responseCh chan struct{}) {
+	s.pendingLock.Lock()
+	defer s.pendingLock.Unlock()
+
+	if responseCh == nil {
+		delete(s.pending, id)
+
+		return
+	}
+
+	s.pending[id] = responseCh
+}
@@ -0,0 +1,273 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package trustping
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	mockdispatcher "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/dispatcher"
+	mockprovider "github.com/hyperledger/aries-framework-go/pkg/mock/provider"
+	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+)
+
+const (
+	aliceDID = "did:example:alice"
+	bobDID   = "did:example:bob"
+	connID   = "conn-1"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		svc, err := New(newProvider(&mockdispatcher.MockOutbound{}))
+		require.NoError(t, err)
+		require.Equal(t, TrustPing, svc.Name())
+		require.True(t, svc.Accept(PingMsgType))
+		require.True(t, svc.Accept(PingResponseMsgType))
+		require.False(t, svc.Accept("unknown"))
+	})
+
+	t.Run("connection lookup error", func(t *testing.T) {
+		_, err := New(&mockprovider.Provider{
+			StorageProviderValue: &mockstorage.MockStoreProvider{
+				ErrOpenStoreHandle: errors.New("test error"),
+			},
+			ProtocolStateStorageProviderValue: mem.NewProvider(),
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "new connection lookup")
+	})
+}
+
+func TestService_Ping(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		alice, bob := newConnectedServices(t)
+
+		rtt, err := alice.Ping(connID, true, time.Second)
+		require.NoError(t, err)
+		require.True(t, rtt > 0)
+
+		rtt, err = bob.Ping(connID, true, 0)
+		require.NoError(t, err)
+		require.True(t, rtt > 0)
+	})
+
+	t.Run("response not requested", func(t *testing.T) {
+		var sent *Ping
+
+		svc, err := New(newProvider(&mockdispatcher.MockOutbound{
+			ValidateSendToDID: func(msg interface{}, myDID, theirDID string) error {
+				require.Equal(t, aliceDID, myDID)
+				require.Equal(t, bobDID, theirDID)
+
+				sent = msg.(*Ping)
+
+				return nil
+			},
+		}))
+		require.NoError(t, err)
+
+		saveConnection(t, svc, aliceDID, bobDID)
+
+		rtt, err := svc.Ping(connID, false, time.Second)
+		require.NoError(t, err)
+		require.Zero(t, rtt)
+		require.NotNil(t, sent)
+		require.False(t, sent.ResponseRequested)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		svc, err := New(newProvider(&mockdispatcher.MockOutbound{}))
+		require.NoError(t, err)
+
+		saveConnection(t, svc, aliceDID, bobDID)
+
+		_, err = svc.Ping(connID, true, time.Millisecond)
+		require.True(t, errors.Is(err, ErrTimeout))
+		require.Empty(t, svc.pending)
+	})
+
+	t.Run("connection not found", func(t *testing.T) {
+		svc, err := New(newProvider(&mockdispatcher.MockOutbound{}))
+		require.NoError(t, err)
+
+		_, err = svc.Ping(connID, true, time.Second)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "get connection record")
+	})
+
+	t.Run("send error", func(t *testing.T) {
+		svc, err := New(newProvider(&mockdispatcher.MockOutbound{SendErr: errors.New("test error")}))
+		require.NoError(t, err)
+
+		saveConnection(t, svc, aliceDID, bobDID)
+
+		_, err = svc.Ping(connID, true, time.Second)
+		require.EqualError(t, err, "send ping: test error")
+
+		_, err = svc.Ping(connID, false, time.Second)
+		require.EqualError(t, err, "send ping: test error")
+	})
+}
+
+func TestService_HandleInbound(t *testing.T) {
+	t.Run("ping without response_requested is responded", func(t *testing.T) {
+		var resp *PingResponse
+
+		svc, err := New(newProvider(&mockdispatcher.MockOutbound{
+			ValidateSendToDID: func(msg interface{}, myDID, theirDID string) error {
+				resp = msg.(*PingResponse)
+
+				return nil
+			},
+		}))
+		require.NoError(t, err)
+
+		msg := service.NewDIDCommMsgMap(struct {
+			Type string `json:"@type"`
+			ID   string `json:"@id"`
+		}{Type: PingMsgType, ID: "ping-1"})
+
+		_, err = svc.HandleInbound(msg, aliceDID, bobDID)
+		require.NoError(t, err)
+		require.NotNil(t, resp)
+		require.Equal(t, PingResponseMsgType, resp.Type)
+		require.Equal(t, "ping-1", resp.Thread.ID)
+	})
+
+	t.Run("ping with response not requested", func(t *testing.T) {
+		svc, err := New(newProvider(&mockdispatcher.MockOutbound{
+			ValidateSendToDID: func(msg interface{}, myDID, theirDID string) error {
+				return errors.New("unexpected response")
+			},
+		}))
+		require.NoError(t, err)
+
+		_, err = svc.HandleInbound(service.NewDIDCommMsgMap(&Ping{Type: PingMsgType, ID: "ping-1"}), aliceDID, bobDID)
+		require.NoError(t, err)
+	})
+
+	t.Run("ping response send error", func(t *testing.T) {
+		svc, err := New(newProvider(&mockdispatcher.MockOutbound{SendErr: errors.New("test error")}))
+		require.NoError(t, err)
+
+		_, err = svc.HandleInbound(service.NewDIDCommMsgMap(&Ping{
+			Type: PingMsgType, ID: "ping-1", ResponseRequested: true,
+		}), aliceDID, bobDID)
+		require.EqualError(t, err, "send ping response: test error")
+	})
+
+	t.Run("invalid ping", func(t *testing.T) {
+		svc, err := New(newProvider(&mockdispatcher.MockOutbound{}))
+		require.NoError(t, err)
+
+		_, err = svc.HandleInbound(service.DIDCommMsgMap{
+			"@type": PingMsgType, "@id": "ping-1", "response_requested": []string{"invalid"},
+		}, aliceDID, bobDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "decode ping")
+	})
+
+	t.Run("ping response for unknown ping", func(t *testing.T) {
+		svc, err := New(newProvider(&mockdispatcher.MockOutbound{}))
+		require.NoError(t, err)
+
+		_, err = svc.HandleInbound(service.NewDIDCommMsgMap(&PingResponse{
+			Type: PingResponseMsgType, ID: "resp-1", Thread: &decorator.Thread{ID: "ping-1"},
+		}), aliceDID, bobDID)
+		require.NoError(t, err)
+	})
+
+	t.Run("ping response without thread", func(t *testing.T) {
+		svc, err := New(newProvider(&mockdispatcher.MockOutbound{}))
+		require.NoError(t, err)
+
+		_, err = svc.HandleInbound(service.DIDCommMsgMap{"@type": PingResponseMsgType}, aliceDID, bobDID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "ping response thread ID")
+	})
+
+	t.Run("unsupported message type", func(t *testing.T) {
+		svc, err := New(newProvider(&mockdispatcher.MockOutbound{}))
+		require.NoError(t, err)
+
+		_, err = svc.HandleInbound(service.DIDCommMsgMap{"@type": "unknown"}, aliceDID, bobDID)
+		require.EqualError(t, err, "unsupported message type unknown")
+
+		_, err = svc.HandleOutbound(nil, aliceDID, bobDID)
+		require.EqualError(t, err, "not implemented")
+	})
+}
+
+// newConnectedServices returns two services connected with connID, which deliver the messages to each other.
+func newConnectedServices(t *testing.T) (*Service, *Service) {
+	t.Helper()
+
+	var alice, bob *Service
+
+	deliver := func(to **Service) func(msg interface{}, myDID, theirDID string) error {
+		return func(msg interface{}, myDID, theirDID string) error {
+			src, err := json.Marshal(msg)
+			require.NoError(t, err)
+
+			didCommMsg, err := service.ParseDIDCommMsgMap(src)
+			require.NoError(t, err)
+
+			go func() {
+				_, err := (*to).HandleInbound(didCommMsg, theirDID, myDID)
+				require.NoError(t, err)
+			}()
+
+			return nil
+		}
+	}
+
+	var err error
+
+	alice, err = New(newProvider(&mockdispatcher.MockOutbound{ValidateSendToDID: deliver(&bob)}))
+	require.NoError(t, err)
+
+	bob, err = New(newProvider(&mockdispatcher.MockOutbound{ValidateSendToDID: deliver(&alice)}))
+	require.NoError(t, err)
+
+	saveConnection(t, alice, aliceDID, bobDID)
+	saveConnection(t, bob, bobDID, aliceDID)
+
+	return alice, bob
+}
+
+func newProvider(outbound *mockdispatcher.MockOutbound) *mockprovider.Provider {
+	return &mockprovider.Provider{
+		StorageProviderValue:              mem.NewProvider(),
+		ProtocolStateStorageProviderValue: mem.NewProvider(),
+		OutboundDispatcherValue:           outbound,
+	}
+}
+
+func saveConnection(t *testing.T, svc *Service, myDID, theirDID string) {
+	t.Helper()
+
+	lookup, ok := svc.connectionLookup.(*connection.Lookup)
+	require.True(t, ok)
+
+	recorder := &connection.Recorder{Lookup: lookup}
+
+	require.NoError(t, recorder.SaveConnectionRecord(&connection.Record{
+		ConnectionID: connID,
+		State:        connection.StateNameCompleted,
+		MyDID:        myDID,
+		TheirDID:     theirDID,
+	}))
+}
@@ -0,0 +1,323 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package replay
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+)
+
+var logger = log.New("aries-framework/replay")
+
+const (
+	// StoreName is the name of the store of the seen messages.
+	StoreName = "seen_messages"
+
+	seenTag     = "seen"
+	seenDataKey = "seen_%s_%s"
+
+	defaultTTL       = 24 * time.Hour
+	defaultClockSkew = 5 * time.Minute
+
+	// pendingTimeout is how long a message being handled blocks its duplicates, the record is left pending
+	// if the agent stops while handling the message.
+	pendingTimeout = time.Minute
+)
+
+var (
+	// ErrDuplicate is returned when the message was already seen.
+	ErrDuplicate = errors.New("duplicate message")
+	// ErrOutOfWindow is returned when the time the message was sent at falls outside of the replay window.
+	ErrOutOfWindow = errors.New("message is outside of the replay window")
+	// ErrInProgress is returned when the message is still being handled, the sender should retry it later.
+	ErrInProgress = errors.New("message is being handled")
+)
+
+// Cache keeps track of the inbound messages to detect the duplicates and the replays.
+type Cache interface {
+	// Check records the message received from the sender key as pending, an error is returned if the message is
+	// a duplicate or if it can't be accepted according to its ~timing.
+	Check(senderKey string, msg service.DIDCommMsgMap) error
+	// Commit records the checked message as handled, its duplicates are rejected with ErrDuplicate.
+	Commit(senderKey string, msg service.DIDCommMsgMap) error
+	// Forget removes the checked message which failed to be handled, so its retry is accepted.
+	Forget(senderKey string, msg service.DIDCommMsgMap) error
+}
+
+// Option configures the cache of the seen messages.
+type Option func(c *StoreCache)
+
+// WithTTL sets the duration the messages are remembered for, the messages which claim to be sent
+// before that are rejected.
+func WithTTL(ttl time.Duration) Option {
+	return func(c *StoreCache) {
+		c.ttl = ttl
+	}
+}
+
+// WithClockSkew sets the tolerated difference between the clocks of the sender and the agent.
+func WithClockSkew(skew time.Duration) Option {
+	return func(c *StoreCache) {
+		c.clockSkew = skew
+	}
+}
+
+// StoreCache is the storage backed cache of the seen messages. The messages are keyed by the sender key and
+// the message ID. The messages sent with ~timing.out_time are accepted only within the TTL of the cache, so
+// their replays are detected even after the cache forgets them; the replays of the messages without the
+// ~timing are detected only within the TTL.
+type StoreCache struct {
+	store     storage.Store
+	ttl       time.Duration
+	clockSkew time.Duration
+	lock      sync.Mutex
+	lastSweep time.Time
+}
+
+type seenRecord struct {
+	SeenTime time.Time `json:"seen_time"`
+	Pending  bool      `json:"pending,omitempty"`
+}
+
+// NewCache returns the storage backed cache of the seen messages.
+func NewCache(prov storage.Provider, opts ...Option) (*StoreCache, error) {
+	store, err := prov.OpenStore(StoreName)
+	if err != nil {
+		return nil, fmt.Errorf("open seen messages store: %w", err)
+	}
+
+	err = prov.SetStoreConfig(StoreName, storage.StoreConfiguration{TagNames: []string{seenTag}})
+	if err != nil {
+		return nil, fmt.Errorf("set seen messages store config: %w", err)
+	}
+
+	c := &StoreCache{
+		store:     store,
+		ttl:       defaultTTL,
+		clockSkew: defaultClockSkew,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c, nil
+}
+
+// Check records the message received from the sender key as pending until it's committed or forgotten.
+// ErrDuplicate is returned if the message was handled within the TTL, ErrInProgress is returned if the message is
+// still being handled and ErrOutOfWindow is returned if its ~timing.out_time is either in the future or older than
+// the TTL. The messages without ID can't be tracked and are accepted.
+func (c *StoreCache) Check(senderKey string, msg service.DIDCommMsgMap) error {
+	id := messageID(msg)
+	if id == "" {
+		return nil
+	}
+
+	now := time.Now()
+
+	err := c.checkTiming(msg, now)
+	if err != nil {
+		return err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.sweep(now)
+
+	key := fmt.Sprintf(seenDataKey, senderKey, id)
+
+	record, err := c.get(key)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case record == nil:
+	case record.Pending && now.Sub(record.SeenTime) <= pendingTimeout:
+		return fmt.Errorf("message %s: %w", id, ErrInProgress)
+	case !record.Pending && now.Sub(record.SeenTime) <= c.ttl:
+		return fmt.Errorf("message %s: %w", id, ErrDuplicate)
+	}
+
+	return c.put(key, &seenRecord{SeenTime: now, Pending: true})
+}
+
+// Commit records the checked message as handled.
+func (c *StoreCache) Commit(senderKey string, msg service.DIDCommMsgMap) error {
+	id := messageID(msg)
+	if id == "" {
+		return nil
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.put(fmt.Sprintf(seenDataKey, senderKey, id), &seenRecord{SeenTime: time.Now()})
+}
+
+// Forget removes the checked message, so the sender can retry it.
+func (c *StoreCache) Forget(senderKey string, msg service.DIDCommMsgMap) error {
+	id := messageID(msg)
+	if id == "" {
+		return nil
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	err := c.store.Delete(fmt.Sprintf(seenDataKey, senderKey, id))
+	if err != nil && !errors.Is(err, storage.ErrDataNotFound) {
+		return fmt.Errorf("delete seen message: %w", err)
+	}
+
+	return nil
+}
+
+func (c *StoreCache) get(key string) (*seenRecord, error) {
+	src, err := c.store.Get(key)
+	if errors.Is(err, storage.ErrDataNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("get seen message: %w", err)
+	}
+
+	record := &seenRecord{}
+
+	err = json.Unmarshal(src, record)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal seen message: %w", err)
+	}
+
+	return record, nil
+}
+
+func (c *StoreCache) put(key string, record *seenRecord) error {
+	src, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("marshal seen message: %w", err)
+	}
+
+	return c.store.Put(key, src, storage.Tag{Name: seenTag})
+}
+
+func (c *StoreCache) checkTiming(msg service.DIDCommMsgMap, now time.Time) error {
+	timing := struct {
+		Timing *decorator.Timing `json:"~timing,omitempty"`
+	}{}
+
+	err := msg.Decode(&timing)
+	if err != nil {
+		return fmt.Errorf("decode timing: %w", err)
+	}
+
+	if timing.Timing == nil || timing.Timing.OutTime.IsZero() {
+		return nil
+	}
+
+	outTime := timing.Timing.OutTime
+
+	if outTime.After(now.Add(c.clockSkew)) {
+		return fmt.Errorf("out_time %s is in the future: %w", outTime.Format(time.RFC3339), ErrOutOfWindow)
+	}
+
+	if outTime.Before(now.Add(-c.ttl)) {
+		return fmt.Errorf("out_time %s is too old: %w", outTime.Format(time.RFC3339), ErrOutOfWindow)
+	}
+
+	return nil
+}
+
+// sweep removes the expired records, it runs at most once per TTL.
+func (c *StoreCache) sweep(now time.Time) {
+	if now.Sub(c.lastSweep) < c.ttl {
+		return
+	}
+
+	c.lastSweep = now
+
+	expired, err := c.expiredKeys(now)
+	if err != nil {
+		logger.Warnf("failed to query the expired seen messages: %s", err)
+
+		return
+	}
+
+	for _, key := range expired {
+		if err := c.store.Delete(key); err != nil {
+			logger.Warnf("failed to remove the expired seen message: %s", err)
+		}
+	}
+}
+
+func (c *StoreCache) expiredKeys(now time.Time) ([]string, error) {
+	records, err := c.store.Query(seenTag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query seen messages: %w", err)
+	}
+
+	defer storage.Close(records, logger)
+
+	var expired []string
+
+	more, err := records.Next()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get next record: %w", err)
+	}
+
+	for more {
+		value, err := records.Value()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get value from records: %w", err)
+		}
+
+		record := &seenRecord{}
+
+		err = json.Unmarshal(value, record)
+		if err != nil {
+			return nil, fmt.Errorf("unmarshal seen message: %w", err)
+		}
+
+		if now.Sub(record.SeenTime) > c.ttl {
+			key, err := records.Key()
+			if err != nil {
+				return nil, fmt.Errorf("failed to get key from records: %w", err)
+			}
+
+			expired = append(expired, key)
+		}
+
+		more, err = records.Next()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get next record: %w", err)
+		}
+	}
+
+	return expired, nil
+}
+
+// messageID returns the @id of the message or the id of the DIDComm V2 message.
+func messageID(msg service.DIDCommMsgMap) string {
+	if id := msg.ID(); id != "" {
+		return id
+	}
+
+	id, _ := msg["id"].(string)
+
+	return id
+}
@@ -0,0 +1,197 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package replay
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	mockstore "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+)
+
+const senderKey = "sender-key"
+
+func TestNewCache(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		c, err := NewCache(mem.NewProvider(), WithTTL(time.Hour), WithClockSkew(time.Second))
+		require.NoError(t, err)
+		require.Equal(t, time.Hour, c.ttl)
+		require.Equal(t, time.Second, c.clockSkew)
+	})
+
+	t.Run("open store error", func(t *testing.T) {
+		_, err := NewCache(&mockstore.MockStoreProvider{ErrOpenStoreHandle: errors.New("open error")})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "open seen messages store: open error")
+	})
+}
+
+func TestStoreCache_Check(t *testing.T) {
+	t.Run("duplicate message", func(t *testing.T) {
+		c, err := NewCache(mem.NewProvider())
+		require.NoError(t, err)
+
+		msg := service.DIDCommMsgMap{"@id": "msg-1", "@type": "type"}
+
+		require.NoError(t, c.Check(senderKey, msg))
+		require.NoError(t, c.Commit(senderKey, msg))
+
+		err = c.Check(senderKey, msg)
+		require.True(t, errors.Is(err, ErrDuplicate))
+		require.Contains(t, err.Error(), "message msg-1")
+
+		// the same ID sent by another sender is not a duplicate
+		require.NoError(t, c.Check("another-key", msg))
+	})
+
+	t.Run("DIDComm V2 message", func(t *testing.T) {
+		c, err := NewCache(mem.NewProvider())
+		require.NoError(t, err)
+
+		msg := service.DIDCommMsgMap{"id": "msg-1", "type": "type"}
+
+		require.NoError(t, c.Check(senderKey, msg))
+		require.NoError(t, c.Commit(senderKey, msg))
+		require.True(t, errors.Is(c.Check(senderKey, msg), ErrDuplicate))
+	})
+
+	t.Run("message without ID is accepted", func(t *testing.T) {
+		c, err := NewCache(mem.NewProvider())
+		require.NoError(t, err)
+
+		msg := service.DIDCommMsgMap{"@type": "type"}
+
+		require.NoError(t, c.Check(senderKey, msg))
+		require.NoError(t, c.Commit(senderKey, msg))
+		require.NoError(t, c.Check(senderKey, msg))
+		require.NoError(t, c.Forget(senderKey, msg))
+	})
+
+	t.Run("message being handled", func(t *testing.T) {
+		c, err := NewCache(mem.NewProvider())
+		require.NoError(t, err)
+
+		msg := service.DIDCommMsgMap{"@id": "msg-1", "@type": "type"}
+
+		require.NoError(t, c.Check(senderKey, msg))
+
+		err = c.Check(senderKey, msg)
+		require.True(t, errors.Is(err, ErrInProgress))
+		require.Contains(t, err.Error(), "message msg-1")
+	})
+
+	t.Run("forgotten message is accepted", func(t *testing.T) {
+		c, err := NewCache(mem.NewProvider())
+		require.NoError(t, err)
+
+		msg := service.DIDCommMsgMap{"@id": "msg-1", "@type": "type"}
+
+		require.NoError(t, c.Check(senderKey, msg))
+		require.NoError(t, c.Forget(senderKey, msg))
+		require.NoError(t, c.Forget(senderKey, msg))
+
+		require.NoError(t, c.Check(senderKey, msg))
+	})
+
+	t.Run("message is forgotten after the TTL", func(t *testing.T) {
+		c, err := NewCache(mem.NewProvider(), WithTTL(10*time.Millisecond))
+		require.NoError(t, err)
+
+		msg := service.DIDCommMsgMap{"@id": "msg-1", "@type": "type"}
+
+		require.NoError(t, c.Check(senderKey, msg))
+		require.NoError(t, c.Commit(senderKey, msg))
+
+		time.Sleep(20 * time.Millisecond)
+
+		require.NoError(t, c.Check(senderKey, msg))
+	})
+
+	t.Run("out_time", func(t *testing.T) {
+		c, err := NewCache(mem.NewProvider(), WithTTL(time.Hour), WithClockSkew(time.Minute))
+		require.NoError(t, err)
+
+		require.NoError(t, c.Check(senderKey, timedMessage("msg-1", time.Now().Add(30*time.Second))))
+
+		err = c.Check(senderKey, timedMessage("msg-2", time.Now().Add(2*time.Minute)))
+		require.True(t, errors.Is(err, ErrOutOfWindow))
+		require.Contains(t, err.Error(), "is in the future")
+
+		err = c.Check(senderKey, timedMessage("msg-3", time.Now().Add(-2*time.Hour)))
+		require.True(t, errors.Is(err, ErrOutOfWindow))
+		require.Contains(t, err.Error(), "is too old")
+	})
+
+	t.Run("invalid timing", func(t *testing.T) {
+		c, err := NewCache(mem.NewProvider())
+		require.NoError(t, err)
+
+		err = c.Check(senderKey, service.DIDCommMsgMap{"@id": "msg-1", "~timing": "invalid"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "decode timing")
+	})
+
+	t.Run("store errors", func(t *testing.T) {
+		store := &mockstore.MockStore{Store: make(map[string]mockstore.DBEntry)}
+
+		c, err := NewCache(&mockstore.MockStoreProvider{Store: store})
+		require.NoError(t, err)
+
+		msg := service.DIDCommMsgMap{"@id": "msg-1", "@type": "type"}
+
+		store.ErrGet = errors.New("get error")
+		err = c.Check(senderKey, msg)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "get seen message: get error")
+
+		store.ErrGet = nil
+		store.ErrPut = errors.New("put error")
+		require.EqualError(t, c.Check(senderKey, msg), "put error")
+		require.EqualError(t, c.Commit(senderKey, msg), "put error")
+
+		store.ErrDelete = errors.New("delete error")
+		err = c.Forget(senderKey, msg)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "delete seen message: delete error")
+	})
+}
+
+func TestStoreCache_Sweep(t *testing.T) {
+	c, err := NewCache(mem.NewProvider(), WithTTL(10*time.Millisecond))
+	require.NoError(t, err)
+
+	require.NoError(t, c.Check(senderKey, service.DIDCommMsgMap{"@id": "msg-1"}))
+
+	time.Sleep(20 * time.Millisecond)
+
+	require.NoError(t, c.Check(senderKey, service.DIDCommMsgMap{"@id": "msg-2"}))
+
+	_, err = c.store.Get("seen_sender-key_msg-1")
+	require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+	_, err = c.store.Get("seen_sender-key_msg-2")
+	require.NoError(t, err)
+}
+
+func timedMessage(id string, outTime time.Time) service.DIDCommMsgMap {
+	return service.NewDIDCommMsgMap(struct {
+		ID     string            `json:"@id"`
+		Type   string            `json:"@type"`
+		Timing *decorator.Timing `json:"~timing"`
+	}{
+		ID:     id,
+		Type:   "type",
+		Timing: &decorator.Timing{OutTime: outTime},
+	})
+}
@@ -16,37 +16,135 @@ import (
 	"github.com/rs/cors"
 
 	"github.com/hyperledger/aries-framework-go/pkg/common/log"
+	commontransport "github.com/hyperledger/aries-framework-go/pkg/didcomm/common/transport"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/replay"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/transport"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/transport/middleware"
 )
 
 var logger = log.New("aries-framework/http")
 
 // TODO https://github.com/hyperledger/aries-framework-go/issues/891 Support for Transport Return Route (Duplex)
 
+// defaultAddrRateFactor is how many times the rate of the sender rate limiter is allowed per remote address by default,
+// the address may be shared by several senders (ie: a mediator or a NAT).
+const defaultAddrRateFactor = 10
+
+type inboundCommHTTPOpts struct {
+	middlewares       []middleware.Middleware
+	maxPayloadSize    int64
+	contentTypes      []string
+	corsOpts          *cors.Options
+	senderRateLimiter *middleware.RateLimiter
+	addrRateLimiter   *middleware.RateLimiter
+}
+
+// InboundHTTPOpt is an inbound HTTP transport option.
+type InboundHTTPOpt func(opts *inboundCommHTTPOpts)
+
+// WithInboundMiddleware adds middlewares to the inbound handler (ie: middleware.RateLimitByIP or an authentication
+// check), they are executed in the given order after the CORS handling.
+func WithInboundMiddleware(middlewares ...middleware.Middleware) InboundHTTPOpt {
+	return func(opts *inboundCommHTTPOpts) {
+		opts.middlewares = append(opts.middlewares, middlewares...)
+	}
+}
+
+// WithInboundMaxPayloadSize rejects the payloads larger than limit bytes, the size is not limited by default.
+func WithInboundMaxPayloadSize(limit int64) InboundHTTPOpt {
+	return func(opts *inboundCommHTTPOpts) {
+		opts.maxPayloadSize = limit
+	}
+}
+
+// WithInboundContentTypes sets the content types accepted by the inbound handler, all the DIDComm envelope media
+// types are accepted by default.
+func WithInboundContentTypes(contentTypes ...string) InboundHTTPOpt {
+	return func(opts *inboundCommHTTPOpts) {
+		opts.contentTypes = contentTypes
+	}
+}
+
+// WithInboundCORS sets the CORS options of the inbound handler, the cors default options are used by default.
+func WithInboundCORS(corsOpts cors.Options) InboundHTTPOpt {
+	return func(opts *inboundCommHTTPOpts) {
+		opts.corsOpts = &corsOpts
+	}
+}
+
+// WithInboundSenderRateLimiter limits the rate of the messages per sender key, the messages are checked once
+// unpacked and the ones exceeding the limit are rejected before being handled. Unpacking is not free, so the requests
+// are also limited per remote address before their payload is read, see WithInboundAddrRateLimiter.
+func WithInboundSenderRateLimiter(limiter *middleware.RateLimiter) InboundHTTPOpt {
+	return func(opts *inboundCommHTTPOpts) {
+		opts.senderRateLimiter = limiter
+	}
+}
+
+// WithInboundAddrRateLimiter limits the rate of the requests per remote address, the requests are checked before
+// their payload is read and unpacked. If only the sender rate limiter is set, the remote addresses are allowed ten
+// times its rate.
+func WithInboundAddrRateLimiter(limiter *middleware.RateLimiter) InboundHTTPOpt {
+	return func(opts *inboundCommHTTPOpts) {
+		opts.addrRateLimiter = limiter
+	}
+}
+
 // NewInboundHandler will create a new handler to enforce Did-Comm HTTP transport specs
 // then routes processing to the mandatory 'msgHandler' argument.
 //
 // Arguments:
-// * 'msgHandler' is the handler function that will be executed with the inbound request payload.
-//    Users of this library must manage the handling of all inbound payloads in this function.
-func NewInboundHandler(prov transport.Provider) (http.Handler, error) {
+//   - 'msgHandler' is the handler function that will be executed with the inbound request payload.
+//     Users of this library must manage the handling of all inbound payloads in this function.
+//   - 'opts' configure the middleware chain executed before the handler (CORS, payload size, rate limits, etc.).
+func NewInboundHandler(prov transport.Provider, opts ...InboundHTTPOpt) (http.Handler, error) {
 	if prov == nil || prov.InboundMessageHandler() == nil {
 		logger.Errorf("Error creating a new inbound handler: message handler function is nil")
 		return nil, errors.New("creation of inbound handler failed")
 	}
 
+	o := &inboundCommHTTPOpts{
+		contentTypes: []string{commContentType, commV2ContentType, commontransport.MediaTypeV2SignedEnvelope},
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
 	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		processPOSTRequest(w, r, prov)
+		processPOSTRequest(w, r, prov, o.senderRateLimiter)
 	})
 
-	return cors.Default().Handler(handler), nil
-}
+	corsHandler := cors.Default().Handler
+	if o.corsOpts != nil {
+		corsHandler = cors.New(*o.corsOpts).Handler
+	}
 
-func processPOSTRequest(w http.ResponseWriter, r *http.Request, prov transport.Provider) {
-	if valid := validateHTTPMethod(w, r); !valid {
-		return
+	middlewares := []middleware.Middleware{corsHandler}
+
+	addrRateLimiter := o.addrRateLimiter
+	if addrRateLimiter == nil && o.senderRateLimiter != nil {
+		addrRateLimiter = o.senderRateLimiter.Scaled(defaultAddrRateFactor)
+	}
+
+	if addrRateLimiter != nil {
+		middlewares = append(middlewares, middleware.RateLimitByIP(addrRateLimiter))
+	}
+
+	middlewares = append(middlewares, o.middlewares...)
+
+	if o.maxPayloadSize > 0 {
+		middlewares = append(middlewares, middleware.MaxPayloadSize(o.maxPayloadSize))
 	}
 
+	middlewares = append(middlewares, postOnly, middleware.ContentTypes(o.contentTypes...))
+
+	return middleware.Chain(handler, middlewares...), nil
+}
+
+func processPOSTRequest(w http.ResponseWriter, r *http.Request, prov transport.Provider,
+	senderRateLimiter *middleware.RateLimiter) {
+
 	if valid := validatePayload(r, w); !valid {
 		return
 	}
@@ -67,9 +165,24 @@ func processPOSTRequest(w http.ResponseWriter, r *http.Request, prov transport.P
 		return
 	}
 
+	if senderRateLimiter != nil && !senderRateLimiter.AllowSender(unpackMsg) {
+		logger.Warnf("sender rate limit exceeded - returning Code: %d", http.StatusTooManyRequests)
+		http.Error(w, "too many requests", http.StatusTooManyRequests)
+
+		return
+	}
+
 	messageHandler := prov.InboundMessageHandler()
 
-	err = messageHandler(unpackMsg.Message, unpackMsg.ToDID, unpackMsg.FromDID)
+	err = messageHandler(unpackMsg)
+	if errors.Is(err, replay.ErrDuplicate) {
+		// the sender is told the message was already handled, so it doesn't retry it
+		logger.Warnf("duplicate msg: %s - returning Code: %d", err, http.StatusConflict)
+		http.Error(w, "duplicate message", http.StatusConflict)
+
+		return
+	}
+
 	if err != nil {
 		// TODO https://github.com/hyperledger/aries-framework-go/issues/271 HTTP Response Codes based on errors
 		//  from service
@@ -90,20 +203,17 @@ func validatePayload(r *http.Request, w http.ResponseWriter) bool {
 	return true
 }
 
-// validateHTTPMethod validate HTTP method and content-type.
-func validateHTTPMethod(w http.ResponseWriter, r *http.Request) bool {
-	if r.Method != "POST" {
-		http.Error(w, "HTTP Method not allowed", http.StatusMethodNotAllowed)
-		return false
-	}
-
-	ct := r.Header.Get("Content-type")
-	if ct != commContentType {
-		http.Error(w, fmt.Sprintf("Unsupported Content-type \"%s\"", ct), http.StatusUnsupportedMediaType)
-		return false
-	}
+// postOnly rejects the requests with an HTTP method other than POST, the content type of the POST requests tells the
+// DIDComm version of the envelope and is checked by the next middleware.
+func postOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "HTTP Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
 
-	return true
+		next.ServeHTTP(w, r)
+	})
 }
 
 // Inbound http type.
@@ -111,10 +221,11 @@ type Inbound struct {
 	externalAddr      string
 	server            *http.Server
 	certFile, keyFile string
+	opts              []InboundHTTPOpt
 }
 
-// NewInbound creates a new HTTP inbound transport instance.
-func NewInbound(internalAddr, externalAddr, certFile, keyFile string) (*Inbound, error) {
+// NewInbound creates a new HTTP inbound transport instance, the options configure the inbound handler.
+func NewInbound(internalAddr, externalAddr, certFile, keyFile string, opts ...InboundHTTPOpt) (*Inbound, error) {
 	if internalAddr == "" {
 		return nil, errors.New("http address is mandatory")
 	}
@@ -128,12 +239,13 @@ func NewInbound(internalAddr, externalAddr, certFile, keyFile string) (*Inbound,
 		keyFile:      keyFile,
 		externalAddr: externalAddr,
 		server:       &http.Server{Addr: internalAddr},
+		opts:         opts,
 	}, nil
 }
 
 // Start the http server.
 func (i *Inbound) Start(prov transport.Provider) error {
-	handler, err := NewInboundHandler(prov)
+	handler, err := NewInboundHandler(prov, i.opts...)
 	if err != nil {
 		return fmt.Errorf("HTTP server start failed: %w", err)
 	}
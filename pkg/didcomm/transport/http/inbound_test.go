@@ -15,24 +15,29 @@ import (
 	"io/ioutil"
 	"net"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/rs/cors"
 	"github.com/stretchr/testify/require"
 
 	commontransport "github.com/hyperledger/aries-framework-go/pkg/didcomm/common/transport"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/replay"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/transport"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/transport/middleware"
 	mockpackager "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/packager"
 )
 
 type mockProvider struct {
 	packagerValue commontransport.Packager
+	handleErr     error
 }
 
 func (p *mockProvider) InboundMessageHandler() transport.InboundMessageHandler {
-	return func(message []byte, myDID, theirDID string) error {
-		logger.Debugf("message received is %s", message)
-		return nil
+	return func(envelope *commontransport.Envelope) error {
+		logger.Debugf("message received is %s", envelope.Message)
+		return p.handleErr
 	}
 }
 
@@ -131,6 +136,126 @@ func TestInboundHandler(t *testing.T) {
 	require.NoError(t, resp.Body.Close())
 }
 
+func TestInboundHandler_Duplicate(t *testing.T) {
+	inHandler, err := NewInboundHandler(&mockProvider{
+		packagerValue: &mockpackager.Packager{UnpackValue: &commontransport.Envelope{Message: []byte("data")}},
+		handleErr:     fmt.Errorf("replay check: %w", replay.ErrDuplicate),
+	})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString("data"))
+	req.Header.Set("Content-Type", commContentType)
+
+	rr := httptest.NewRecorder()
+	inHandler.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusConflict, rr.Code)
+	require.Contains(t, rr.Body.String(), "duplicate message")
+}
+
+func TestInboundHandler_Middlewares(t *testing.T) {
+	prov := &mockProvider{
+		packagerValue: &mockpackager.Packager{UnpackValue: &commontransport.Envelope{
+			Message: []byte("data"),
+			FromKey: []byte("sender"),
+		}},
+	}
+
+	post := func(handler http.Handler, body, contentType string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set("Origin", "https://example.com")
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		return rr
+	}
+
+	t.Run("max payload size", func(t *testing.T) {
+		inHandler, err := NewInboundHandler(prov, WithInboundMaxPayloadSize(4))
+		require.NoError(t, err)
+
+		require.Equal(t, http.StatusAccepted, post(inHandler, "data", commContentType).Code)
+		require.Equal(t, http.StatusRequestEntityTooLarge, post(inHandler, "large data", commContentType).Code)
+	})
+
+	t.Run("content types", func(t *testing.T) {
+		inHandler, err := NewInboundHandler(prov)
+		require.NoError(t, err)
+
+		require.Equal(t, http.StatusAccepted, post(inHandler, "data", commV2ContentType).Code)
+		require.Equal(t, http.StatusUnsupportedMediaType, post(inHandler, "data", "application/json").Code)
+
+		inHandler, err = NewInboundHandler(prov, WithInboundContentTypes(commV2ContentType))
+		require.NoError(t, err)
+
+		require.Equal(t, http.StatusAccepted, post(inHandler, "data", commV2ContentType).Code)
+		require.Equal(t, http.StatusUnsupportedMediaType, post(inHandler, "data", commContentType).Code)
+	})
+
+	t.Run("rate limits", func(t *testing.T) {
+		inHandler, err := NewInboundHandler(prov,
+			WithInboundMiddleware(middleware.RateLimitByIP(middleware.NewRateLimiter(0, 2))))
+		require.NoError(t, err)
+
+		require.Equal(t, http.StatusAccepted, post(inHandler, "data", commContentType).Code)
+		require.Equal(t, http.StatusAccepted, post(inHandler, "data", commContentType).Code)
+		require.Equal(t, http.StatusTooManyRequests, post(inHandler, "data", commContentType).Code)
+
+		inHandler, err = NewInboundHandler(prov, WithInboundSenderRateLimiter(middleware.NewRateLimiter(0, 1)))
+		require.NoError(t, err)
+
+		require.Equal(t, http.StatusAccepted, post(inHandler, "data", commContentType).Code)
+		require.Equal(t, http.StatusTooManyRequests, post(inHandler, "data", commContentType).Code)
+	})
+
+	t.Run("remote address rate limit before unpacking", func(t *testing.T) {
+		// the unpacked requests fail, the limited ones are rejected before
+		failingProv := &mockProvider{
+			packagerValue: &mockpackager.Packager{UnpackErr: errors.New("unpack error")},
+		}
+
+		inHandler, err := NewInboundHandler(failingProv,
+			WithInboundAddrRateLimiter(middleware.NewRateLimiter(0, 1)))
+		require.NoError(t, err)
+
+		require.Equal(t, http.StatusInternalServerError, post(inHandler, "data", commContentType).Code)
+		require.Equal(t, http.StatusTooManyRequests, post(inHandler, "data", commContentType).Code)
+
+		// the sender rate limiter sets the remote address limit by default
+		inHandler, err = NewInboundHandler(failingProv,
+			WithInboundSenderRateLimiter(middleware.NewRateLimiter(0, 1)))
+		require.NoError(t, err)
+
+		for i := 0; i < defaultAddrRateFactor; i++ {
+			require.Equal(t, http.StatusInternalServerError, post(inHandler, "data", commContentType).Code)
+		}
+
+		require.Equal(t, http.StatusTooManyRequests, post(inHandler, "data", commContentType).Code)
+	})
+
+	t.Run("CORS and custom middleware", func(t *testing.T) {
+		called := false
+
+		inHandler, err := NewInboundHandler(prov,
+			WithInboundCORS(cors.Options{AllowedOrigins: []string{"https://example.org"}}),
+			WithInboundMiddleware(func(next http.Handler) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					called = true
+
+					next.ServeHTTP(w, r)
+				})
+			}))
+		require.NoError(t, err)
+
+		rr := post(inHandler, "data", commContentType)
+		require.Equal(t, http.StatusAccepted, rr.Code)
+		require.Empty(t, rr.Header().Get("Access-Control-Allow-Origin"))
+		require.True(t, called)
+	})
+}
+
 func TestInboundTransport(t *testing.T) {
 	t.Run("test inbound transport - with host/port", func(t *testing.T) {
 		port := "26601"
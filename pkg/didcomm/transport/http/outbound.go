@@ -16,14 +16,16 @@ import (
 	"time"
 
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	commontransport "github.com/hyperledger/aries-framework-go/pkg/didcomm/common/transport"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/transport"
 )
 
 //go:generate testdata/scripts/openssl_env.sh testdata/scripts/generate_test_keys.sh
 
 const (
-	commContentType = "application/didcomm-envelope-enc"
-	httpScheme      = "http"
+	commContentType   = "application/didcomm-envelope-enc"
+	commV2ContentType = commontransport.MediaTypeV2EncryptedEnvelope
+	httpScheme        = "http"
 )
 
 // outboundCommHTTPOpts holds options for the HTTP transport implementation of CommTransport
@@ -92,7 +94,12 @@ func (cs *OutboundHTTPClient) Start(prov transport.Provider) error {
 
 // Send sends a2a exchange data via HTTP (client side).
 func (cs *OutboundHTTPClient) Send(data []byte, destination *service.Destination) (string, error) {
-	resp, err := cs.client.Post(destination.ServiceEndpoint, commContentType, bytes.NewBuffer(data))
+	contentType := commContentType
+	if destination.DIDCommVersion == service.V2 {
+		contentType = commV2ContentType
+	}
+
+	resp, err := cs.client.Post(destination.ServiceEndpoint, contentType, bytes.NewBuffer(data))
 	if err != nil {
 		logger.Errorf("posting DID envelope to agent failed [%s, %v]", destination.ServiceEndpoint, err)
 		return "", err
@@ -118,6 +125,14 @@ func (cs *OutboundHTTPClient) Send(data []byte, destination *service.Destination
 
 		respData = buf.String()
 
+		if resp.StatusCode == http.StatusConflict {
+			// the agent has already handled the message, ex. the message was delivered but the response was lost
+			logger.Warnf("didcomm duplicate : transport=http serviceEndpoint=%s status=%v",
+				destination.ServiceEndpoint, resp.Status)
+
+			return "", nil
+		}
+
 		isStatusSuccess := resp.StatusCode == http.StatusAccepted || resp.StatusCode == http.StatusOK
 		if !isStatusSuccess {
 			logger.Errorf("didcomm failed : transport=http serviceEndpoint=%s status=%v errMsg=%s",
@@ -10,6 +10,8 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -99,6 +101,21 @@ func TestOutboundHTTPTransport(t *testing.T) {
 	require.False(t, ot.Accept("123:22"))
 }
 
+func TestOutboundHTTPTransport_Duplicate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "duplicate message", http.StatusConflict)
+	}))
+	defer server.Close()
+
+	ot, err := NewOutbound(WithOutboundHTTPClient(server.Client()))
+	require.NoError(t, err)
+
+	// the message the agent has already received is not sent again
+	r, err := ot.Send([]byte("Hello World"), prepareDestination(server.URL))
+	require.NoError(t, err)
+	require.Empty(t, r)
+}
+
 func prepareDestination(endPoint string) *service.Destination {
 	return &service.Destination{
 		ServiceEndpoint: endPoint,
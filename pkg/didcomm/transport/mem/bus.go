@@ -0,0 +1,256 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mem
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
+)
+
+// Package mem includes an in-process transport routing the packed messages between the agents (aries.Aries instances)
+// of the same process through an in-memory bus. The endpoints of the agents are `mem://` URLs, it's meant for hermetic
+// multi-agent tests: latency, loss and reordering of the messages may be injected to test the resilience of the
+// protocols.
+
+const (
+	memScheme = "mem://"
+
+	// reorderHoldTimeout is the time a message held for reordering waits for the next message.
+	reorderHoldTimeout = 100 * time.Millisecond
+)
+
+var logger = log.New("aries-framework/transport/mem")
+
+// deliverFunc delivers the packed message to the agent listening on the endpoint.
+type deliverFunc func(data []byte)
+
+// BusOption configures the bus.
+type BusOption func(b *Bus)
+
+// WithLatency delays each message by a random duration between min and max, the messages sent to an endpoint are
+// delivered in order unless reordering is enabled.
+func WithLatency(min, max time.Duration) BusOption {
+	return func(b *Bus) {
+		b.minLatency = min
+		b.maxLatency = max
+	}
+}
+
+// WithLoss drops the messages with the given probability (between 0 and 1), the sender is not told about it.
+func WithLoss(rate float64) BusOption {
+	return func(b *Bus) {
+		b.lossRate = rate
+	}
+}
+
+// WithReordering holds the messages with the given probability (between 0 and 1) and delivers them after the next
+// message sent to the same endpoint, or after a short timeout if there is no such message.
+func WithReordering(rate float64) BusOption {
+	return func(b *Bus) {
+		b.reorderRate = rate
+	}
+}
+
+// WithSeed seeds the random source of the injected faults, so the tests are repeatable.
+func WithSeed(seed int64) BusOption {
+	return func(b *Bus) {
+		b.rand = rand.New(rand.NewSource(seed)) // nolint:gosec
+	}
+}
+
+// Bus is an in-memory message bus, the inbound transports listen to their `mem://` endpoint on the bus and the
+// outbound transports send the messages to these endpoints. Each endpoint delivers its messages sequentially.
+type Bus struct {
+	minLatency  time.Duration
+	maxLatency  time.Duration
+	lossRate    float64
+	reorderRate float64
+
+	randLock sync.Mutex
+	rand     *rand.Rand
+
+	lock      sync.RWMutex
+	endpoints map[string]*mailbox
+}
+
+// NewBus creates a new in-memory bus.
+func NewBus(opts ...BusOption) *Bus {
+	b := &Bus{
+		endpoints: map[string]*mailbox{},
+		rand:      rand.New(rand.NewSource(time.Now().UnixNano())), // nolint:gosec
+	}
+
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	return b
+}
+
+// listen registers the endpoint on the bus, the messages sent to it are delivered with the deliver function.
+func (b *Bus) listen(endpoint string, deliver deliverFunc) error {
+	if !strings.HasPrefix(endpoint, memScheme) {
+		return fmt.Errorf("invalid endpoint %s: %s scheme expected", endpoint, memScheme)
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if _, ok := b.endpoints[endpoint]; ok {
+		return fmt.Errorf("endpoint %s is already in use", endpoint)
+	}
+
+	m := &mailbox{
+		bus:     b,
+		deliver: deliver,
+		queue:   make(chan *envelope, 100), // nolint:gomnd
+		done:    make(chan struct{}),
+	}
+
+	b.endpoints[endpoint] = m
+
+	go m.run()
+
+	return nil
+}
+
+// unlisten removes the endpoint from the bus, the pending messages are dropped.
+func (b *Bus) unlisten(endpoint string) {
+	b.lock.Lock()
+	m, ok := b.endpoints[endpoint]
+	delete(b.endpoints, endpoint)
+	b.lock.Unlock()
+
+	if ok {
+		close(m.done)
+	}
+}
+
+// send sends the message to the endpoint, the message is delivered asynchronously.
+func (b *Bus) send(endpoint string, data []byte) error {
+	b.lock.RLock()
+	m, ok := b.endpoints[endpoint]
+	b.lock.RUnlock()
+
+	if !ok {
+		return fmt.Errorf("no agent listening on endpoint %s", endpoint)
+	}
+
+	if b.chance(b.lossRate) {
+		logger.Debugf("message to %s dropped", endpoint)
+
+		return nil
+	}
+
+	// the message is copied, the sender may reuse its buffer
+	msg := &envelope{data: append([]byte(nil), data...), deliverAt: time.Now().Add(b.latency())}
+
+	select {
+	case m.queue <- msg:
+		return nil
+	case <-m.done:
+		return fmt.Errorf("no agent listening on endpoint %s", endpoint)
+	}
+}
+
+func (b *Bus) latency() time.Duration {
+	if b.maxLatency <= b.minLatency {
+		return b.minLatency
+	}
+
+	b.randLock.Lock()
+	defer b.randLock.Unlock()
+
+	return b.minLatency + time.Duration(b.rand.Int63n(int64(b.maxLatency-b.minLatency)))
+}
+
+func (b *Bus) chance(rate float64) bool {
+	if rate <= 0 {
+		return false
+	}
+
+	b.randLock.Lock()
+	defer b.randLock.Unlock()
+
+	return b.rand.Float64() < rate
+}
+
+type envelope struct {
+	data      []byte
+	deliverAt time.Time
+}
+
+// mailbox delivers the messages sent to an endpoint one after the other.
+type mailbox struct {
+	bus     *Bus
+	deliver deliverFunc
+	queue   chan *envelope
+	done    chan struct{}
+}
+
+func (m *mailbox) run() {
+	var held *envelope
+
+	for {
+		var flush <-chan time.Time
+
+		if held != nil {
+			flush = time.After(reorderHoldTimeout)
+		}
+
+		select {
+		case <-m.done:
+			return
+		case <-flush:
+			if !m.wait(held) {
+				return
+			}
+
+			m.deliver(held.data)
+
+			held = nil
+		case msg := <-m.queue:
+			if held == nil && m.bus.chance(m.bus.reorderRate) {
+				held = msg
+
+				continue
+			}
+
+			if !m.wait(msg) {
+				return
+			}
+
+			m.deliver(msg.data)
+
+			if held != nil {
+				m.deliver(held.data)
+
+				held = nil
+			}
+		}
+	}
+}
+
+// wait waits for the delivery time of the message, it returns false if the endpoint is closed in the meantime.
+func (m *mailbox) wait(msg *envelope) bool {
+	d := time.Until(msg.deliverAt)
+	if d <= 0 {
+		return true
+	}
+
+	select {
+	case <-time.After(d):
+		return true
+	case <-m.done:
+		return false
+	}
+}
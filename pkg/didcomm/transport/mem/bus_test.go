@@ -0,0 +1,115 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mem
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBus(t *testing.T) {
+	t.Run("messages are delivered in order", func(t *testing.T) {
+		prov := &mockProvider{}
+		bus := NewBus()
+
+		require.NoError(t, bus.listen("mem://alice", deliverTo(prov)))
+
+		for i := 0; i < 10; i++ {
+			require.NoError(t, bus.send("mem://alice", []byte(fmt.Sprint(i))))
+		}
+
+		require.Equal(t, []string{"0", "1", "2", "3", "4", "5", "6", "7", "8", "9"}, prov.waitFor(t, 10))
+	})
+
+	t.Run("listen errors", func(t *testing.T) {
+		bus := NewBus()
+
+		err := bus.listen("http://alice", deliverTo(&mockProvider{}))
+		require.EqualError(t, err, "invalid endpoint http://alice: mem:// scheme expected")
+
+		require.NoError(t, bus.listen("mem://alice", deliverTo(&mockProvider{})))
+
+		err = bus.listen("mem://alice", deliverTo(&mockProvider{}))
+		require.EqualError(t, err, "endpoint mem://alice is already in use")
+
+		bus.unlisten("mem://alice")
+		require.NoError(t, bus.listen("mem://alice", deliverTo(&mockProvider{})))
+	})
+
+	t.Run("send to unknown endpoint", func(t *testing.T) {
+		err := NewBus().send("mem://bob", []byte("msg"))
+		require.EqualError(t, err, "no agent listening on endpoint mem://bob")
+	})
+
+	t.Run("latency", func(t *testing.T) {
+		prov := &mockProvider{}
+		bus := NewBus(WithLatency(50*time.Millisecond, 60*time.Millisecond))
+
+		require.NoError(t, bus.listen("mem://alice", deliverTo(prov)))
+
+		start := time.Now()
+
+		require.NoError(t, bus.send("mem://alice", []byte("msg")))
+		require.Equal(t, []string{"msg"}, prov.waitFor(t, 1))
+		require.True(t, time.Since(start) >= 50*time.Millisecond)
+	})
+
+	t.Run("loss", func(t *testing.T) {
+		prov := &mockProvider{}
+		bus := NewBus(WithLoss(1))
+
+		require.NoError(t, bus.listen("mem://alice", deliverTo(prov)))
+		require.NoError(t, bus.send("mem://alice", []byte("msg")))
+
+		time.Sleep(20 * time.Millisecond)
+		require.Empty(t, prov.received())
+	})
+
+	t.Run("reordering", func(t *testing.T) {
+		prov := &mockProvider{}
+		bus := NewBus(WithReordering(0.5), WithSeed(1))
+
+		require.NoError(t, bus.listen("mem://alice", deliverTo(prov)))
+
+		expected := make([]string, 20)
+
+		for i := range expected {
+			expected[i] = fmt.Sprintf("%02d", i)
+			require.NoError(t, bus.send("mem://alice", []byte(expected[i])))
+		}
+
+		received := prov.waitFor(t, len(expected))
+		require.NotEqual(t, expected, received)
+
+		sort.Strings(received)
+		require.Equal(t, expected, received)
+	})
+
+	t.Run("held message is flushed", func(t *testing.T) {
+		prov := &mockProvider{}
+		bus := NewBus(WithReordering(1))
+
+		require.NoError(t, bus.listen("mem://alice", deliverTo(prov)))
+		require.NoError(t, bus.send("mem://alice", []byte("msg")))
+
+		require.Equal(t, []string{"msg"}, prov.waitFor(t, 1))
+	})
+}
+
+func deliverTo(prov *mockProvider) deliverFunc {
+	handler := prov.InboundMessageHandler()
+
+	return func(data []byte) {
+		env, _ := prov.Packager().UnpackMessage(data) // nolint:errcheck
+
+		_ = handler(env) // nolint:errcheck
+	}
+}
@@ -0,0 +1,75 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mem
+
+import (
+	"errors"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/replay"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/transport"
+)
+
+// Inbound in-memory transport, it listens to its `mem://` endpoint on the bus.
+type Inbound struct {
+	bus      *Bus
+	endpoint string
+}
+
+// NewInbound creates a new in-memory inbound transport listening to the endpoint (eg. "mem://alice") on the bus.
+func NewInbound(bus *Bus, endpoint string) (*Inbound, error) {
+	if bus == nil {
+		return nil, errors.New("bus is mandatory")
+	}
+
+	if endpoint == "" {
+		return nil, errors.New("endpoint is mandatory")
+	}
+
+	return &Inbound{bus: bus, endpoint: endpoint}, nil
+}
+
+// Start starts listening to the endpoint, the received messages are unpacked and passed to the inbound message
+// handler of the agent.
+func (i *Inbound) Start(prov transport.Provider) error {
+	if prov == nil || prov.InboundMessageHandler() == nil {
+		return errors.New("creation of inbound handler failed")
+	}
+
+	packager, msgHandler := prov.Packager(), prov.InboundMessageHandler()
+
+	return i.bus.listen(i.endpoint, func(data []byte) {
+		unpackMsg, err := packager.UnpackMessage(data)
+		if err != nil {
+			logger.Errorf("failed to unpack msg: %v", err)
+
+			return
+		}
+
+		err = msgHandler(unpackMsg)
+		if errors.Is(err, replay.ErrDuplicate) {
+			logger.Warnf("duplicate msg: %v", err)
+
+			return
+		}
+
+		if err != nil {
+			logger.Errorf("incoming msg processing failed: %v", err)
+		}
+	})
+}
+
+// Stop stops listening to the endpoint.
+func (i *Inbound) Stop() error {
+	i.bus.unlisten(i.endpoint)
+
+	return nil
+}
+
+// Endpoint provides the `mem://` endpoint.
+func (i *Inbound) Endpoint() string {
+	return i.endpoint
+}
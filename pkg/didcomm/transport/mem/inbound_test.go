@@ -0,0 +1,78 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mem
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	mockpackager "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/packager"
+)
+
+func TestInbound(t *testing.T) {
+	t.Run("test new inbound", func(t *testing.T) {
+		_, err := NewInbound(nil, "mem://alice")
+		require.EqualError(t, err, "bus is mandatory")
+
+		_, err = NewInbound(NewBus(), "")
+		require.EqualError(t, err, "endpoint is mandatory")
+
+		inbound, err := NewInbound(NewBus(), "mem://alice")
+		require.NoError(t, err)
+		require.Equal(t, "mem://alice", inbound.Endpoint())
+
+		require.EqualError(t, inbound.Start(nil), "creation of inbound handler failed")
+	})
+
+	t.Run("test send and receive", func(t *testing.T) {
+		bus := NewBus()
+		prov := &mockProvider{}
+
+		inbound, err := NewInbound(bus, "mem://alice")
+		require.NoError(t, err)
+		require.NoError(t, inbound.Start(prov))
+
+		outbound := NewOutbound(bus)
+		require.NoError(t, outbound.Start(prov))
+
+		_, err = outbound.Send([]byte("hello"), &service.Destination{ServiceEndpoint: "mem://alice"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"hello"}, prov.waitFor(t, 1))
+
+		require.NoError(t, inbound.Stop())
+
+		_, err = outbound.Send([]byte("hello"), &service.Destination{ServiceEndpoint: "mem://alice"})
+		require.EqualError(t, err, "mem send: no agent listening on endpoint mem://alice")
+	})
+
+	t.Run("test unpack and handler errors", func(t *testing.T) {
+		bus := NewBus()
+		prov := &mockProvider{packager: &mockpackager.Packager{UnpackErr: errors.New("unpack error")}}
+
+		inbound, err := NewInbound(bus, "mem://alice")
+		require.NoError(t, err)
+		require.NoError(t, inbound.Start(prov))
+
+		require.NoError(t, bus.send("mem://alice", []byte("msg")))
+
+		time.Sleep(20 * time.Millisecond)
+		require.Empty(t, prov.received())
+
+		prov = &mockProvider{err: errors.New("handler error")}
+
+		inbound, err = NewInbound(bus, "mem://bob")
+		require.NoError(t, err)
+		require.NoError(t, inbound.Start(prov))
+
+		require.NoError(t, bus.send("mem://bob", []byte("msg")))
+		require.Equal(t, []string{"msg"}, prov.waitFor(t, 1))
+	})
+}
@@ -0,0 +1,51 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mem
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/transport"
+)
+
+// Outbound in-memory transport, it sends the messages to the `mem://` endpoints of the bus.
+type Outbound struct {
+	bus *Bus
+}
+
+// NewOutbound creates a new in-memory outbound transport sending the messages on the bus.
+func NewOutbound(bus *Bus) *Outbound {
+	return &Outbound{bus: bus}
+}
+
+// Start starts the outbound transport.
+func (o *Outbound) Start(prov transport.Provider) error {
+	return nil
+}
+
+// Send sends the packed message to the agent listening to the destination endpoint. The message is delivered
+// asynchronously, an error is returned if there is no agent listening to the endpoint.
+func (o *Outbound) Send(data []byte, destination *service.Destination) (string, error) {
+	err := o.bus.send(destination.ServiceEndpoint, data)
+	if err != nil {
+		return "", fmt.Errorf("mem send: %w", err)
+	}
+
+	return "", nil
+}
+
+// Accept checks for the url scheme.
+func (o *Outbound) Accept(url string) bool {
+	return strings.HasPrefix(url, memScheme)
+}
+
+// AcceptRecipient checks if there is a connection for the list of recipient keys, there are no connections on the bus.
+func (o *Outbound) AcceptRecipient([]string) bool {
+	return false
+}
@@ -0,0 +1,21 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mem
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutbound(t *testing.T) {
+	outbound := NewOutbound(NewBus())
+
+	require.True(t, outbound.Accept("mem://alice"))
+	require.False(t, outbound.Accept("http://alice"))
+	require.False(t, outbound.AcceptRecipient([]string{"key"}))
+}
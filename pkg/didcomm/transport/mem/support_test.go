@@ -0,0 +1,78 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mem
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	commontransport "github.com/hyperledger/aries-framework-go/pkg/didcomm/common/transport"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/transport"
+)
+
+// mockProvider records the messages received by the inbound message handler.
+type mockProvider struct {
+	lock     sync.Mutex
+	messages []string
+	err      error
+	packager commontransport.Packager
+}
+
+func (p *mockProvider) InboundMessageHandler() transport.InboundMessageHandler {
+	return func(envelope *commontransport.Envelope) error {
+		p.lock.Lock()
+		defer p.lock.Unlock()
+
+		p.messages = append(p.messages, string(envelope.Message))
+
+		return p.err
+	}
+}
+
+func (p *mockProvider) Packager() commontransport.Packager {
+	if p.packager != nil {
+		return p.packager
+	}
+
+	return &noopPackager{}
+}
+
+func (p *mockProvider) AriesFrameworkID() string {
+	return "mem-test"
+}
+
+func (p *mockProvider) received() []string {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return append([]string(nil), p.messages...)
+}
+
+// waitFor waits until the provider received n messages.
+func (p *mockProvider) waitFor(t *testing.T, n int) []string {
+	t.Helper()
+
+	require.Eventually(t, func() bool {
+		return len(p.received()) >= n
+	}, 5*time.Second, 5*time.Millisecond)
+
+	return p.received()
+}
+
+// noopPackager doesn't pack the messages.
+type noopPackager struct{}
+
+func (p *noopPackager) PackMessage(envelope *commontransport.Envelope) ([]byte, error) {
+	return envelope.Message, nil
+}
+
+func (p *noopPackager) UnpackMessage(encMessage []byte) (*commontransport.Envelope, error) {
+	return &commontransport.Envelope{Message: encMessage}, nil
+}
@@ -0,0 +1,105 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package middleware
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/rs/cors"
+)
+
+// Package middleware includes the HTTP middlewares shared by the inbound transports (HTTP and WebSocket). The
+// middlewares are executed before the inbound transport handler, they protect the agent against oversized payloads
+// and floods of requests, and may be used to plug authentication or any other request check.
+
+// Middleware wraps the next handler of the inbound transport chain.
+type Middleware func(next http.Handler) http.Handler
+
+// Chain wraps the handler with the middlewares, the first middleware is the first executed.
+func Chain(handler http.Handler, middlewares ...Middleware) http.Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+
+	return handler
+}
+
+// CORS handles the CORS requests with the given options.
+func CORS(opts cors.Options) Middleware {
+	return cors.New(opts).Handler
+}
+
+// MaxPayloadSize rejects the requests with a payload larger than limit bytes (413 status code), the bodies without
+// content length are cut at the limit so the handler fails to read them.
+func MaxPayloadSize(limit int64) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength > limit {
+				http.Error(w, fmt.Sprintf("payload exceeds %d bytes", limit), http.StatusRequestEntityTooLarge)
+
+				return
+			}
+
+			r.Body = http.MaxBytesReader(w, r.Body, limit)
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ContentTypes rejects the POST requests with a content type not in the list (415 status code). The other requests,
+// like the WebSocket upgrade requests, don't carry a payload and are not checked.
+func ContentTypes(types ...string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			ct := r.Header.Get("Content-Type")
+
+			for _, t := range types {
+				if ct == t {
+					next.ServeHTTP(w, r)
+
+					return
+				}
+			}
+
+			http.Error(w, fmt.Sprintf("Unsupported Content-type \"%s\"", ct), http.StatusUnsupportedMediaType)
+		})
+	}
+}
+
+// RateLimitByIP rejects the requests exceeding the rate limit of the client IP address (429 status code).
+func RateLimitByIP(limiter *RateLimiter) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !limiter.Allow(clientIP(r)) {
+				http.Error(w, "too many requests", http.StatusTooManyRequests)
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// clientIP returns the IP address of the client, the forwarding headers are ignored as they can be forged.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
@@ -0,0 +1,111 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package middleware
+
+import (
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcutil/base58"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/transport"
+)
+
+// staleBucketTimeout is the time after which the bucket of an inactive key is dropped.
+const staleBucketTimeout = 10 * time.Minute
+
+// RateLimiter limits the rate of the events per key (a client IP address, a sender key, etc.) with a token bucket:
+// each key may burst up to 'burst' events, the bucket is then refilled at 'rate' events per second.
+type RateLimiter struct {
+	rate    float64
+	burst   float64
+	now     func() time.Time
+	lock    sync.Mutex
+	buckets map[string]*bucket
+	sweep   time.Time
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter creates a rate limiter allowing 'rate' events per second with bursts of 'burst' events per key.
+// The burst is at least one event.
+func NewRateLimiter(rate float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &RateLimiter{
+		rate:    rate,
+		burst:   float64(burst),
+		now:     time.Now,
+		buckets: map[string]*bucket{},
+	}
+}
+
+// Scaled returns a new rate limiter allowing factor times the rate and the burst of the limiter.
+func (l *RateLimiter) Scaled(factor float64) *RateLimiter {
+	return NewRateLimiter(l.rate*factor, int(l.burst*factor))
+}
+
+// Allow consumes a token of the key bucket, it returns false if the bucket is empty.
+func (l *RateLimiter) Allow(key string) bool {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	now := l.now()
+
+	l.dropStaleBuckets(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	}
+
+	b.tokens += now.Sub(b.last).Seconds() * l.rate
+	if b.tokens > l.burst {
+		b.tokens = l.burst
+	}
+
+	b.last = now
+
+	if b.tokens < 1 {
+		return false
+	}
+
+	b.tokens--
+
+	return true
+}
+
+// AllowSender consumes a token of the bucket of the sender key of an unpacked message. The anonymous messages
+// (anoncrypt) are always allowed, their rate may only be limited by IP address.
+func (l *RateLimiter) AllowSender(envelope *transport.Envelope) bool {
+	if len(envelope.FromKey) == 0 {
+		return true
+	}
+
+	return l.Allow(base58.Encode(envelope.FromKey))
+}
+
+// dropStaleBuckets drops the buckets of the keys inactive for a while, so the limiter doesn't grow without bound.
+func (l *RateLimiter) dropStaleBuckets(now time.Time) {
+	if now.Sub(l.sweep) < staleBucketTimeout {
+		return
+	}
+
+	l.sweep = now
+
+	for k, b := range l.buckets {
+		if now.Sub(b.last) > staleBucketTimeout {
+			delete(l.buckets, k)
+		}
+	}
+}
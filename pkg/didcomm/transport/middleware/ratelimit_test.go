@@ -0,0 +1,82 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package middleware
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/transport"
+)
+
+func TestRateLimiter(t *testing.T) {
+	t.Run("burst then refill", func(t *testing.T) {
+		now := time.Now()
+
+		l := NewRateLimiter(2, 3)
+		l.now = func() time.Time { return now }
+
+		for i := 0; i < 3; i++ {
+			require.True(t, l.Allow("key"))
+		}
+
+		require.False(t, l.Allow("key"))
+		require.True(t, l.Allow("other key"))
+
+		now = now.Add(500 * time.Millisecond)
+
+		require.True(t, l.Allow("key"))
+		require.False(t, l.Allow("key"))
+
+		now = now.Add(time.Hour)
+
+		for i := 0; i < 3; i++ {
+			require.True(t, l.Allow("key"))
+		}
+
+		require.False(t, l.Allow("key"))
+	})
+
+	t.Run("stale buckets are dropped", func(t *testing.T) {
+		now := time.Now()
+
+		l := NewRateLimiter(1, 0)
+		l.now = func() time.Time { return now }
+
+		require.True(t, l.Allow("key"))
+		require.Len(t, l.buckets, 1)
+
+		now = now.Add(2 * staleBucketTimeout)
+
+		require.True(t, l.Allow("other key"))
+		require.Len(t, l.buckets, 1)
+	})
+
+	t.Run("sender keys", func(t *testing.T) {
+		l := NewRateLimiter(0, 1)
+
+		require.True(t, l.AllowSender(&transport.Envelope{FromKey: []byte("sender")}))
+		require.False(t, l.AllowSender(&transport.Envelope{FromKey: []byte("sender")}))
+		require.True(t, l.AllowSender(&transport.Envelope{}))
+		require.True(t, l.AllowSender(&transport.Envelope{}))
+	})
+
+	t.Run("scaled", func(t *testing.T) {
+		now := time.Now()
+
+		l := NewRateLimiter(1, 2).Scaled(3)
+		l.now = func() time.Time { return now }
+
+		for i := 0; i < 6; i++ {
+			require.True(t, l.Allow("key"))
+		}
+
+		require.False(t, l.Allow("key"))
+	})
+}
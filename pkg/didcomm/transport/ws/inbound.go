@@ -16,6 +16,7 @@ import (
 
 	"github.com/hyperledger/aries-framework-go/pkg/common/log"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/transport"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/transport/middleware"
 )
 
 var logger = log.New("aries-framework/ws")
@@ -24,12 +25,14 @@ var logger = log.New("aries-framework/ws")
 type Inbound struct {
 	externalAddr      string
 	server            *http.Server
-	pool              *connPool
+	pool              *Pool
+	ownsPool          bool
+	middlewares       []middleware.Middleware
 	certFile, keyFile string
 }
 
 // NewInbound creates a new WebSocket inbound transport instance.
-func NewInbound(internalAddr, externalAddr, certFile, keyFile string) (*Inbound, error) {
+func NewInbound(internalAddr, externalAddr, certFile, keyFile string, opts ...Option) (*Inbound, error) {
 	if internalAddr == "" {
 		return nil, errors.New("websocket address is mandatory")
 	}
@@ -38,11 +41,19 @@ func NewInbound(internalAddr, externalAddr, certFile, keyFile string) (*Inbound,
 		externalAddr = internalAddr
 	}
 
+	o := &transportOpts{}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
 	return &Inbound{
 		certFile:     certFile,
 		keyFile:      keyFile,
 		externalAddr: externalAddr,
 		server:       &http.Server{Addr: internalAddr},
+		pool:         o.pool,
+		middlewares:  o.middlewares,
 	}, nil
 }
 
@@ -52,11 +63,11 @@ func (i *Inbound) Start(prov transport.Provider) error {
 		return errors.New("creation of inbound handler failed")
 	}
 
-	i.server.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	i.server.Handler = middleware.Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		i.processRequest(w, r)
-	})
+	}), i.middlewares...)
 
-	i.pool = getConnPool(prov)
+	i.pool, i.ownsPool = startPool(i.pool, prov)
 
 	go func() {
 		if err := i.listenAndServe(); err != http.ErrServerClosed {
@@ -75,28 +86,54 @@ func (i *Inbound) listenAndServe() error {
 	return i.server.ListenAndServe()
 }
 
-// Stop the http(ws) server.
+// Stop the http(ws) server. The pool is closed only if the transport created it, the pool of the framework is
+// closed by the framework.
 func (i *Inbound) Stop() error {
 	if err := i.server.Shutdown(context.Background()); err != nil {
 		return fmt.Errorf("websocket server shutdown failed: %w", err)
 	}
 
+	if i.ownsPool {
+		return i.pool.Close()
+	}
+
 	return nil
 }
 
+// Pool returns the connection pool of the transport, it's set once the transport is started.
+func (i *Inbound) Pool() *Pool {
+	return i.pool
+}
+
 // Endpoint provides the http(ws) connection details.
 func (i *Inbound) Endpoint() string {
 	return i.externalAddr
 }
 
 func (i *Inbound) processRequest(w http.ResponseWriter, r *http.Request) {
+	if !i.pool.canAccept() {
+		logger.Warnf("websocket connection refused: %v", errPoolFull)
+		http.Error(w, errPoolFull.Error(), http.StatusServiceUnavailable)
+
+		return
+	}
+
 	c, err := upgradeConnection(w, r)
 	if err != nil {
 		logger.Errorf("failed to upgrade the connection : %v", err)
 		return
 	}
 
-	i.pool.listener(c, false)
+	conn, err := i.pool.register(c, false, "")
+	if err != nil {
+		logger.Warnf("websocket connection refused: %v", err)
+
+		_ = c.Close(websocket.StatusTryAgainLater, err.Error()) // nolint:errcheck
+
+		return
+	}
+
+	i.pool.listener(conn)
 }
 
 func upgradeConnection(w http.ResponseWriter, r *http.Request) (*websocket.Conn, error) {
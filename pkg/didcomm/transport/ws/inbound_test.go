@@ -9,13 +9,18 @@ package ws
 import (
 	"context"
 	"errors"
+	"net/http"
+	"net/url"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"nhooyr.io/websocket"
 
 	commontransport "github.com/hyperledger/aries-framework-go/pkg/didcomm/common/transport"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/transport"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/transport/middleware"
 	"github.com/hyperledger/aries-framework-go/pkg/internal/test/transportutil"
 	mockpackager "github.com/hyperledger/aries-framework-go/pkg/mock/didcomm/packager"
 )
@@ -165,3 +170,93 @@ func TestInboundDataProcessing(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
+
+func TestInboundMiddlewares(t *testing.T) {
+	t.Run("test inbound transport - connection refused by the IP rate limit", func(t *testing.T) {
+		port := ":" + strconv.Itoa(transportutil.GetRandomPort(5))
+
+		inbound, err := NewInbound(port, "", "", "",
+			WithMiddleware(middleware.RateLimitByIP(middleware.NewRateLimiter(0, 1))))
+		require.NoError(t, err)
+
+		err = inbound.Start(&mockProvider{packagerValue: &mockpackager.Packager{}})
+		require.NoError(t, err)
+
+		defer func() {
+			require.NoError(t, inbound.Stop())
+		}()
+
+		client, cleanup := websocketClient(t, port)
+		require.NotNil(t, client)
+
+		cleanup()
+
+		u := url.URL{Scheme: "ws", Host: "localhost" + port}
+
+		_, resp, err := websocket.Dial(context.Background(), u.String(), nil)
+		require.Error(t, err)
+		require.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		require.NoError(t, resp.Body.Close())
+	})
+
+	t.Run("test inbound transport - messages dropped by the sender rate limit", func(t *testing.T) {
+		port := ":" + strconv.Itoa(transportutil.GetRandomPort(5))
+
+		pool := NewPool(WithSenderRateLimiter(middleware.NewRateLimiter(0, 2)))
+		defer func() {
+			require.NoError(t, pool.Close())
+		}()
+
+		inbound, err := NewInbound(port, "", "", "", WithPool(pool))
+		require.NoError(t, err)
+
+		handled := make(chan struct{}, 5)
+
+		err = inbound.Start(&handlerProvider{
+			mockProvider: mockProvider{packagerValue: &mockpackager.Packager{UnpackValue: &commontransport.Envelope{
+				Message: []byte("data"),
+				FromKey: []byte("sender"),
+			}}},
+			handler: func(*commontransport.Envelope) error {
+				handled <- struct{}{}
+
+				return nil
+			},
+		})
+		require.NoError(t, err)
+
+		defer func() {
+			require.NoError(t, inbound.Stop())
+		}()
+
+		client, cleanup := websocketClient(t, port)
+		defer cleanup()
+
+		for i := 0; i < 5; i++ {
+			require.NoError(t, client.Write(context.Background(), websocket.MessageText, []byte("data")))
+		}
+
+		for i := 0; i < 2; i++ {
+			select {
+			case <-handled:
+			case <-time.After(time.Second):
+				require.Fail(t, "message not handled")
+			}
+		}
+
+		select {
+		case <-handled:
+			require.Fail(t, "message exceeding the sender rate limit handled")
+		case <-time.After(100 * time.Millisecond):
+		}
+	})
+}
+
+type handlerProvider struct {
+	mockProvider
+	handler transport.InboundMessageHandler
+}
+
+func (p *handlerProvider) InboundMessageHandler() transport.InboundMessageHandler {
+	return p.handler
+}
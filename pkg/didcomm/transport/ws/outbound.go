@@ -22,23 +22,35 @@ const webSocketScheme = "ws"
 
 // OutboundClient websocket outbound.
 type OutboundClient struct {
-	pool *connPool
+	pool *Pool
 	prov transport.Provider
 }
 
 // NewOutbound creates a client for Outbound WS transport.
-func NewOutbound() *OutboundClient {
-	return &OutboundClient{}
+func NewOutbound(opts ...Option) *OutboundClient {
+	o := &transportOpts{}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return &OutboundClient{pool: o.pool}
 }
 
 // Start starts the outbound transport.
 func (cs *OutboundClient) Start(prov transport.Provider) error {
-	cs.pool = getConnPool(prov)
+	cs.pool, _ = startPool(cs.pool, prov)
+
 	cs.prov = prov
 
 	return nil
 }
 
+// Pool returns the connection pool of the transport, it's set once the transport is started.
+func (cs *OutboundClient) Pool() *Pool {
+	return cs.pool
+}
+
 // Send sends a2a data via WS.
 func (cs *OutboundClient) Send(data []byte, destination *service.Destination) (string, error) {
 	conn, cleanup, err := cs.getConnection(destination)
@@ -48,7 +60,7 @@ func (cs *OutboundClient) Send(data []byte, destination *service.Destination) (s
 		return "", fmt.Errorf("get websocket connection : %w", err)
 	}
 
-	err = conn.Write(context.Background(), websocket.MessageText, data)
+	err = cs.pool.write(conn, data)
 	if err != nil {
 		logger.Errorf("didcomm failed : transport=ws serviceEndpoint=%s errMsg=%s",
 			destination.ServiceEndpoint, err.Error())
@@ -92,22 +104,25 @@ func (cs *OutboundClient) getConnection(destination *service.Destination) (*webs
 		return conn, cleanup, nil
 	}
 
-	var err error
-
-	conn, _, err = websocket.Dial(context.Background(), destination.ServiceEndpoint, nil)
-	if err != nil {
-		return nil, cleanup, fmt.Errorf("websocket client : %w", err)
-	}
-
 	// keep the connection open to listen to the response in case of return route option set
 	if destination.TransportReturnRoute == decorator.TransportReturnRouteAll {
+		c, err := cs.pool.dial(destination.ServiceEndpoint)
+		if err != nil {
+			return nil, cleanup, err
+		}
+
 		for _, v := range destination.RecipientKeys {
-			cs.pool.add(v, conn)
+			cs.pool.add(v, c)
 		}
 
-		go cs.pool.listener(conn, true)
+		go cs.pool.listener(c)
 
-		return conn, cleanup, nil
+		return c.conn, cleanup, nil
+	}
+
+	conn, _, err := websocket.Dial(context.Background(), destination.ServiceEndpoint, nil) // nolint:bodyclose
+	if err != nil {
+		return nil, cleanup, fmt.Errorf("websocket client : %w", err)
 	}
 
 	cleanup = func() {
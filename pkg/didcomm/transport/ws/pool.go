@@ -8,120 +8,595 @@ package ws
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"nhooyr.io/websocket"
 
 	commtransport "github.com/hyperledger/aries-framework-go/pkg/didcomm/common/transport"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/replay"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/transport"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/transport/middleware"
 	"github.com/hyperledger/aries-framework-go/pkg/vdr/fingerprint"
 )
 
 const (
-	// TODO configure ping request frequency.
-	pingFrequency = 30 * time.Second
+	defaultPingInterval   = 30 * time.Second
+	defaultInitialBackoff = time.Second
+	defaultMaxBackoff     = time.Minute
 )
 
-type connPool struct {
-	connMap map[string]*websocket.Conn
-	sync.RWMutex
+// errPoolFull is returned when the pool holds the maximum number of connections.
+var errPoolFull = errors.New("websocket connection limit reached")
+
+// poolProvider is implemented by the providers of the frameworks owning a websocket connection pool, e.g. the
+// framework context.
+type poolProvider interface {
+	WebSocketPool() *Pool
+}
+
+// PoolOption configures the connection pool.
+type PoolOption func(p *Pool)
+
+// WithPingInterval sets the interval of the pings sent on the outbound connections to keep them alive (30s by
+// default), zero disables the pings.
+func WithPingInterval(interval time.Duration) PoolOption {
+	return func(p *Pool) {
+		p.pingInterval = interval
+	}
+}
+
+// WithReadLimit sets the maximum size in bytes of the messages read from the connections, the connection is closed
+// if a bigger message is received. The websocket library default (32KB) is used if not set.
+func WithReadLimit(limit int64) PoolOption {
+	return func(p *Pool) {
+		p.readLimit = limit
+	}
+}
+
+// WithIdleTimeout closes the connections without any message read or written for the given duration.
+func WithIdleTimeout(timeout time.Duration) PoolOption {
+	return func(p *Pool) {
+		p.idleTimeout = timeout
+	}
+}
+
+// WithMaxConnections caps the number of open connections, the new connections are refused once it is reached.
+func WithMaxConnections(max int) PoolOption {
+	return func(p *Pool) {
+		p.maxConnections = max
+	}
+}
+
+// WithReconnect reconnects the outbound return route sessions when the connection is lost, the reconnection is
+// retried with an exponential backoff from initialBackoff up to maxBackoff, at most maxRetries times (no limit if
+// zero).
+func WithReconnect(initialBackoff, maxBackoff time.Duration, maxRetries int) PoolOption {
+	return func(p *Pool) {
+		p.reconnect = true
+		p.initialBackoff = initialBackoff
+		p.maxBackoff = maxBackoff
+		p.maxRetries = maxRetries
+	}
+}
+
+// WithSenderRateLimiter limits the rate of the messages per sender key, the messages exceeding the limit are dropped
+// once unpacked.
+func WithSenderRateLimiter(limiter *middleware.RateLimiter) PoolOption {
+	return func(p *Pool) {
+		p.senderRateLimiter = limiter
+	}
+}
+
+// PoolStats holds the statistics of the connection pool.
+type PoolStats struct {
+	// OpenConnections is the number of open connections, inbound and outbound.
+	OpenConnections int
+	// InboundConnections is the number of open connections accepted by the inbound transport.
+	InboundConnections int
+	// OutboundConnections is the number of open connections dialed by the outbound transport.
+	OutboundConnections int
+	// Keys is the number of recipient keys which have a connection.
+	Keys int
+	// Opened is the total number of connections opened.
+	Opened uint64
+	// Closed is the total number of connections closed.
+	Closed uint64
+	// IdleClosed is the total number of connections closed because they were idle.
+	IdleClosed uint64
+	// Reconnects is the total number of successful reconnections.
+	Reconnects uint64
+	// Refused is the total number of connections refused because of the connection limit.
+	Refused uint64
+}
+
+// Pool is the websocket connection pool of a framework. It keeps the duplex connections (return route) of the
+// recipient keys, so the messages to these keys are sent on the connection they opened.
+type Pool struct {
+	// the counters are first for the alignment of the atomic operations on 32-bit platforms
+	opened, closedCount, idleClosed, reconnects, refused uint64
+
+	pingInterval   time.Duration
+	readLimit      int64
+	idleTimeout    time.Duration
+	maxConnections int
+	reconnect      bool
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	maxRetries     int
+
+	senderRateLimiter *middleware.RateLimiter
+
+	startOnce  sync.Once
 	packager   commtransport.Packager
 	msgHandler transport.InboundMessageHandler
+
+	lock    sync.RWMutex
+	conns   map[*websocket.Conn]*connection
+	connMap map[string]*connection
+	closed  bool
+	done    chan struct{}
+}
+
+// connection is a websocket connection of the pool.
+type connection struct {
+	lastActivity int64
+	conn         *websocket.Conn
+	outbound     bool
+	// endpoint is the URL dialed by the outbound connections, it's used to reconnect.
+	endpoint string
+	keys     map[string]struct{}
+	// closing is set when the connection is closed on purpose, it's not reconnected then.
+	closing int32
+}
+
+func (c *connection) touch() {
+	atomic.StoreInt64(&c.lastActivity, time.Now().UnixNano())
+}
+
+func (c *connection) idleSince() time.Time {
+	return time.Unix(0, atomic.LoadInt64(&c.lastActivity))
 }
 
-// nolint: gochecknoglobals
-var pool = make(map[string]*connPool)
+// NewPool creates a websocket connection pool, the pool is shared by the inbound and outbound transports of a
+// framework (see WithPool). The pool is closed by its creator.
+func NewPool(opts ...PoolOption) *Pool {
+	p := &Pool{
+		pingInterval:   defaultPingInterval,
+		initialBackoff: defaultInitialBackoff,
+		maxBackoff:     defaultMaxBackoff,
+		conns:          make(map[*websocket.Conn]*connection),
+		connMap:        make(map[string]*connection),
+		done:           make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
 
-func getConnPool(prov transport.Provider) *connPool {
-	id := prov.AriesFrameworkID()
+	return p
+}
+
+// startPool starts the pool of the transport: the pool the transport was given, otherwise the pool of the framework.
+// A transport started without any pool creates its own, the returned flag tells whether the transport owns the pool.
+func startPool(pool *Pool, prov transport.Provider) (*Pool, bool) {
+	owned := false
 
-	if _, ok := pool[id]; !ok {
-		pool[id] = &connPool{
-			connMap:    make(map[string]*websocket.Conn),
-			packager:   prov.Packager(),
-			msgHandler: prov.InboundMessageHandler(),
+	if pool == nil {
+		if p, ok := prov.(poolProvider); ok {
+			pool = p.WebSocketPool()
 		}
 	}
 
-	return pool[id]
+	if pool == nil {
+		pool = NewPool()
+		owned = true
+	}
+
+	pool.start(prov)
+
+	return pool, owned
 }
 
-func (d *connPool) add(verKey string, wsConn *websocket.Conn) {
-	d.Lock()
-	defer d.Unlock()
+// start sets the packager and the message handler of the framework, the first provider is kept.
+func (p *Pool) start(prov transport.Provider) {
+	p.startOnce.Do(func() {
+		p.packager = prov.Packager()
+		p.msgHandler = prov.InboundMessageHandler()
 
-	d.connMap[verKey] = wsConn
+		if p.idleTimeout > 0 {
+			go p.evictIdle()
+		}
+	})
 }
 
-func (d *connPool) fetch(verKey string) *websocket.Conn {
-	d.RLock()
-	defer d.RUnlock()
+// Stats returns the statistics of the pool.
+func (p *Pool) Stats() PoolStats {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	stats := PoolStats{
+		OpenConnections: len(p.conns),
+		Keys:            len(p.connMap),
+		Opened:          atomic.LoadUint64(&p.opened),
+		Closed:          atomic.LoadUint64(&p.closedCount),
+		IdleClosed:      atomic.LoadUint64(&p.idleClosed),
+		Reconnects:      atomic.LoadUint64(&p.reconnects),
+		Refused:         atomic.LoadUint64(&p.refused),
+	}
 
-	return d.connMap[verKey]
+	for _, c := range p.conns {
+		if c.outbound {
+			stats.OutboundConnections++
+		} else {
+			stats.InboundConnections++
+		}
+	}
+
+	return stats
+}
+
+// CloseConnection closes the connection of the recipient key, ie the socket of the peer. The connection is not
+// reconnected.
+func (p *Pool) CloseConnection(verKey string) error {
+	c := p.get(verKey)
+	if c == nil {
+		return fmt.Errorf("no websocket connection for key %s", verKey)
+	}
+
+	p.closeConn(c, "connection closed by the agent")
+
+	return nil
+}
+
+// Close closes all the connections of the pool, the pool can't be used afterwards.
+func (p *Pool) Close() error {
+	p.lock.Lock()
+	if p.closed {
+		p.lock.Unlock()
+
+		return nil
+	}
+
+	p.closed = true
+	close(p.done)
+
+	conns := make([]*connection, 0, len(p.conns))
+	for _, c := range p.conns {
+		conns = append(conns, c)
+	}
+	p.lock.Unlock()
+
+	for _, c := range conns {
+		p.closeConn(c, "closing the connection")
+	}
+
+	return nil
+}
+
+func (p *Pool) add(verKey string, c *connection) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	c.keys[verKey] = struct{}{}
+	p.connMap[verKey] = c
+}
+
+func (p *Pool) get(verKey string) *connection {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	return p.connMap[verKey]
+}
+
+func (p *Pool) fetch(verKey string) *websocket.Conn {
+	if c := p.get(verKey); c != nil {
+		return c.conn
+	}
+
+	return nil
 }
 
-func (d *connPool) remove(verKey string) {
-	d.Lock()
-	defer d.Unlock()
+func (p *Pool) remove(verKey string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if c, ok := p.connMap[verKey]; ok {
+		delete(c.keys, verKey)
+		delete(p.connMap, verKey)
+	}
+}
+
+// register adds the connection to the pool, errPoolFull is returned if the connection limit is reached.
+func (p *Pool) register(conn *websocket.Conn, outbound bool, endpoint string) (*connection, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.closed {
+		return nil, errors.New("websocket connection pool is closed")
+	}
+
+	if p.full() {
+		atomic.AddUint64(&p.refused, 1)
+
+		return nil, errPoolFull
+	}
+
+	if p.readLimit > 0 {
+		conn.SetReadLimit(p.readLimit)
+	}
 
-	delete(d.connMap, verKey)
+	c := &connection{conn: conn, outbound: outbound, endpoint: endpoint, keys: map[string]struct{}{}}
+	c.touch()
+
+	p.conns[conn] = c
+	atomic.AddUint64(&p.opened, 1)
+
+	return c, nil
 }
 
-func (d *connPool) listener(conn *websocket.Conn, outbound bool) {
-	verKeys := []string{}
+// full checks the connection limit, the caller holds the lock.
+func (p *Pool) full() bool {
+	return p.maxConnections > 0 && len(p.conns) >= p.maxConnections
+}
 
-	defer d.close(conn, verKeys)
+// canAccept checks if a new connection can be opened.
+func (p *Pool) canAccept() bool {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
 
-	go keepConnAlive(conn, outbound, pingFrequency)
+	if p.full() {
+		atomic.AddUint64(&p.refused, 1)
+
+		return false
+	}
+
+	return !p.closed
+}
+
+// unregister removes the connection and its keys from the pool, the keys are returned.
+func (p *Pool) unregister(c *connection) []string {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if _, ok := p.conns[c.conn]; !ok {
+		return nil
+	}
+
+	delete(p.conns, c.conn)
+	atomic.AddUint64(&p.closedCount, 1)
+
+	keys := make([]string, 0, len(c.keys))
+
+	for k := range c.keys {
+		keys = append(keys, k)
+
+		if p.connMap[k] == c {
+			delete(p.connMap, k)
+		}
+	}
+
+	return keys
+}
+
+// closeConn closes the connection on purpose, the listener of the connection unregisters it.
+func (p *Pool) closeConn(c *connection, reason string) {
+	atomic.StoreInt32(&c.closing, 1)
+
+	if err := c.conn.Close(websocket.StatusNormalClosure, reason); err != nil &&
+		websocket.CloseStatus(err) != websocket.StatusNormalClosure {
+		logger.Debugf("connection close error: %v", err)
+	}
+}
+
+// write writes the message on the connection, the activity of the connection is tracked if it belongs to the pool.
+func (p *Pool) write(conn *websocket.Conn, data []byte) error {
+	if p != nil {
+		p.lock.RLock()
+		c := p.conns[conn]
+		p.lock.RUnlock()
+
+		if c != nil {
+			c.touch()
+		}
+	}
+
+	return conn.Write(context.Background(), websocket.MessageText, data)
+}
+
+func (p *Pool) listener(c *connection) {
+	done := make(chan struct{})
+
+	if c.outbound {
+		go keepConnAlive(c.conn, p.pingInterval, done)
+	}
 
 	for {
-		_, message, err := conn.Read(context.Background())
+		_, message, err := c.conn.Read(context.Background())
 		if err != nil {
-			if websocket.CloseStatus(err) != websocket.StatusNormalClosure {
+			if websocket.CloseStatus(err) != websocket.StatusNormalClosure && atomic.LoadInt32(&c.closing) == 0 {
 				logger.Errorf("Error reading request message: %v", err)
 			}
 
 			break
 		}
 
-		unpackMsg, err := d.packager.UnpackMessage(message)
-		if err != nil {
-			logger.Errorf("failed to unpack msg: %v", err)
+		c.touch()
+
+		p.handle(c, message)
+	}
+
+	close(done)
+
+	// the connection is lost if it was not closed on purpose
+	lost := atomic.LoadInt32(&c.closing) == 0
+
+	p.closeConn(c, "closing the connection")
+
+	keys := p.unregister(c)
+
+	if lost && c.outbound && p.reconnect && !p.isClosed() {
+		go p.reconnectSession(c.endpoint, keys)
+	}
+}
+
+func (p *Pool) handle(c *connection, message []byte) {
+	unpackMsg, err := p.packager.UnpackMessage(message)
+	if err != nil {
+		logger.Errorf("failed to unpack msg: %v", err)
+
+		return
+	}
+
+	if p.senderRateLimiter != nil && !p.senderRateLimiter.AllowSender(unpackMsg) {
+		logger.Warnf("sender rate limit exceeded: message dropped")
+
+		return
+	}
+
+	trans := &decorator.Transport{}
+
+	err = json.Unmarshal(unpackMsg.Message, trans)
+	if err != nil {
+		logger.Errorf("unmarshal transport decorator : %v", err)
+	}
+
+	if trans.ReturnRoute != nil && trans.ReturnRoute.Value == decorator.TransportReturnRouteAll {
+		didKey, _ := fingerprint.CreateDIDKey(unpackMsg.FromKey)
+
+		p.add(didKey, c)
+	}
+
+	err = p.msgHandler(unpackMsg)
+	if errors.Is(err, replay.ErrDuplicate) {
+		logger.Warnf("duplicate msg: %v", err)
+
+		return
+	}
+
+	if err != nil {
+		logger.Errorf("incoming msg processing failed: %v", err)
+	}
+}
 
-			continue
+func (p *Pool) isClosed() bool {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	return p.closed
+}
+
+// reconnectSession dials the endpoint of a lost return route session with an exponential backoff, the keys of the
+// session are registered on the new connection.
+func (p *Pool) reconnectSession(endpoint string, keys []string) {
+	backoff := p.initialBackoff
+
+	for attempt := 1; p.maxRetries == 0 || attempt <= p.maxRetries; attempt++ {
+		select {
+		case <-p.done:
+			return
+		case <-time.After(backoff):
 		}
 
-		trans := &decorator.Transport{}
+		conn, err := p.dial(endpoint)
+		if err == nil {
+			for _, k := range keys {
+				p.add(k, conn)
+			}
 
-		err = json.Unmarshal(unpackMsg.Message, trans)
-		if err != nil {
-			logger.Errorf("unmarshal transport decorator : %v", err)
+			atomic.AddUint64(&p.reconnects, 1)
+
+			go p.listener(conn)
+
+			return
 		}
 
-		didKey, _ := fingerprint.CreateDIDKey(unpackMsg.FromKey)
+		logger.Warnf("websocket reconnect to %s failed (attempt %d): %v", endpoint, attempt, err)
+
+		backoff *= 2
+		if backoff > p.maxBackoff {
+			backoff = p.maxBackoff
+		}
+	}
+
+	logger.Errorf("websocket reconnect to %s failed, giving up", endpoint)
+}
+
+// dial opens an outbound connection and registers it in the pool.
+func (p *Pool) dial(endpoint string) (*connection, error) {
+	if !p.canAccept() {
+		return nil, errPoolFull
+	}
+
+	conn, _, err := websocket.Dial(context.Background(), endpoint, nil) // nolint:bodyclose
+	if err != nil {
+		return nil, fmt.Errorf("websocket client : %w", err)
+	}
+
+	c, err := p.register(conn, true, endpoint)
+	if err != nil {
+		_ = conn.Close(websocket.StatusTryAgainLater, err.Error()) // nolint:errcheck
+
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// evictIdle closes the connections idle for longer than the idle timeout.
+func (p *Pool) evictIdle() {
+	ticker := time.NewTicker(p.idleTimeout / 2) // nolint:gomnd
+	defer ticker.Stop()
 
-		if trans != nil && trans.ReturnRoute != nil && trans.ReturnRoute.Value == decorator.TransportReturnRouteAll {
-			d.add(didKey, conn)
+	for {
+		select {
+		case <-p.done:
+			return
+		case <-ticker.C:
 		}
 
-		messageHandler := d.msgHandler
+		var idle []*connection
 
-		err = messageHandler(unpackMsg.Message, unpackMsg.ToDID, unpackMsg.FromDID)
-		if err != nil {
-			logger.Errorf("incoming msg processing failed: %v", err)
+		p.lock.RLock()
+		for _, c := range p.conns {
+			if time.Since(c.idleSince()) > p.idleTimeout {
+				idle = append(idle, c)
+			}
+		}
+		p.lock.RUnlock()
+
+		for _, c := range idle {
+			atomic.AddUint64(&p.idleClosed, 1)
+			p.closeConn(c, "idle connection")
 		}
 	}
 }
 
-func (d *connPool) close(conn *websocket.Conn, verKeys []string) {
-	if err := conn.Close(websocket.StatusNormalClosure,
-		"closing the connection"); websocket.CloseStatus(err) != websocket.StatusNormalClosure {
-		logger.Errorf("connection close error")
+// Option configures the inbound and outbound transports.
+type Option func(o *transportOpts)
+
+type transportOpts struct {
+	pool        *Pool
+	middlewares []middleware.Middleware
+}
+
+// WithPool sets the connection pool of the transport, the inbound and outbound transports sharing a pool send the
+// messages on the connections opened by each other (return route). By default, the transports use the pool of the
+// framework, see aries.WithWebSocketPoolOptions.
+func WithPool(pool *Pool) Option {
+	return func(o *transportOpts) {
+		o.pool = pool
 	}
+}
 
-	for _, v := range verKeys {
-		d.remove(v)
+// WithMiddleware adds middlewares to the inbound transport (ie: middleware.RateLimitByIP, middleware.CORS or an
+// authentication check), they are executed in the given order before the connection upgrade.
+func WithMiddleware(middlewares ...middleware.Middleware) Option {
+	return func(o *transportOpts) {
+		o.middlewares = append(o.middlewares, middlewares...)
 	}
 }
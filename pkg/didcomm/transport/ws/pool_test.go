@@ -7,11 +7,12 @@ package ws
 
 import (
 	"context"
+	"net/http"
 	"strconv"
+	"sync/atomic"
 	"testing"
 	"time"
 
-	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 	"nhooyr.io/websocket"
 
@@ -49,11 +50,16 @@ func TestConnectionStore(t *testing.T) {
 			UnpackValue: &commontransport.Envelope{Message: request, FromKey: verKeyBytes},
 		}
 
+		pool := NewPool()
+		defer func() {
+			require.NoError(t, pool.Close())
+		}()
+
 		response := "Hello"
 		transportProvider := &mockTransportProvider{
 			packagerValue: mockPackager,
-			frameworkID:   uuid.New().String(),
-			executeInbound: func(message []byte, myDID, theirDID string) error {
+			pool:          pool,
+			executeInbound: func(envelope *commontransport.Envelope) error {
 				resp, outboundErr := outbound.Send([]byte(response),
 					prepareDestinationWithTransport("ws://doesnt-matter", "", []string{verKey}))
 				require.NoError(t, outboundErr)
@@ -105,10 +111,9 @@ func TestConnectionStore(t *testing.T) {
 
 		transportProvider := &mockTransportProvider{
 			packagerValue: &mockPackager{verKey: verKey},
-			frameworkID:   uuid.New().String(),
-			executeInbound: func(message []byte, myDID, theirDID string) error {
+			executeInbound: func(envelope *commontransport.Envelope) error {
 				// validate the echo server response with the outbound sent message
-				require.Equal(t, request, message)
+				require.Equal(t, request, envelope.Message)
 				done <- struct{}{}
 				return nil
 			},
@@ -133,3 +138,214 @@ func TestConnectionStore(t *testing.T) {
 		}
 	})
 }
+
+func TestPool(t *testing.T) {
+	const verKey = "did:key:z6MkpTHR8VNsBxYAAWHut2Geadd9jSwuBV8xRoAnwWsdvktH"
+
+	newProvider := func() *mockTransportProvider {
+		return &mockTransportProvider{
+			packagerValue:  &mockPackager{verKey: "ABCD"},
+			executeInbound: func(envelope *commontransport.Envelope) error { return nil },
+		}
+	}
+
+	returnRouteSession := func(t *testing.T, outbound *OutboundClient, addr string) {
+		t.Helper()
+
+		_, err := outbound.Send(createTransportDecRequest(t, decorator.TransportReturnRouteAll),
+			prepareDestinationWithTransport("ws://"+addr, decorator.TransportReturnRouteAll, []string{verKey}))
+		require.NoError(t, err)
+	}
+
+	waitFor := func(t *testing.T, cond func() bool) {
+		t.Helper()
+
+		require.Eventually(t, cond, 5*time.Second, 10*time.Millisecond)
+	}
+
+	t.Run("transports share the pool of the framework", func(t *testing.T) {
+		prov := newProvider()
+		prov.pool = NewPool()
+
+		inbound, err := NewInbound(":"+strconv.Itoa(transportutil.GetRandomPort(5)), "", "", "")
+		require.NoError(t, err)
+		require.NoError(t, inbound.Start(prov))
+		require.Equal(t, prov.pool, inbound.Pool())
+
+		outbound := NewOutbound()
+		require.NoError(t, outbound.Start(prov))
+		require.Equal(t, prov.pool, outbound.Pool())
+
+		pool := NewPool()
+		outbound = NewOutbound(WithPool(pool))
+		require.NoError(t, outbound.Start(prov))
+		require.Equal(t, pool, outbound.Pool())
+
+		// the pool of the framework is closed by the framework
+		require.NoError(t, inbound.Stop())
+		require.False(t, prov.pool.isClosed())
+
+		require.NoError(t, prov.pool.Close())
+		require.True(t, prov.pool.isClosed())
+	})
+
+	t.Run("transport without a pool owns its pool", func(t *testing.T) {
+		prov := newProvider()
+
+		inbound, err := NewInbound(":"+strconv.Itoa(transportutil.GetRandomPort(5)), "", "", "")
+		require.NoError(t, err)
+		require.NoError(t, inbound.Start(prov))
+
+		outbound := NewOutbound()
+		require.NoError(t, outbound.Start(prov))
+		require.NotEqual(t, inbound.Pool(), outbound.Pool())
+
+		require.NoError(t, inbound.Stop())
+		require.True(t, inbound.Pool().isClosed())
+		require.False(t, outbound.Pool().isClosed())
+	})
+
+	t.Run("stats and close connection", func(t *testing.T) {
+		addr := startWebSocketServer(t, echo)
+
+		outbound := NewOutbound(WithPool(NewPool(WithReconnect(time.Millisecond, time.Millisecond, 1))))
+		require.NoError(t, outbound.Start(newProvider()))
+
+		pool := outbound.Pool()
+
+		returnRouteSession(t, outbound, addr)
+
+		stats := pool.Stats()
+		require.Equal(t, 1, stats.OpenConnections)
+		require.Equal(t, 1, stats.OutboundConnections)
+		require.Equal(t, 0, stats.InboundConnections)
+		require.Equal(t, 1, stats.Keys)
+		require.Equal(t, uint64(1), stats.Opened)
+
+		require.NoError(t, pool.CloseConnection(verKey))
+		waitFor(t, func() bool { return pool.Stats().OpenConnections == 0 })
+
+		time.Sleep(20 * time.Millisecond)
+
+		stats = pool.Stats()
+		require.Equal(t, 0, stats.Keys)
+		require.Equal(t, uint64(1), stats.Closed)
+		require.Equal(t, uint64(0), stats.Reconnects)
+
+		require.EqualError(t, pool.CloseConnection(verKey), "no websocket connection for key "+verKey)
+		require.NoError(t, pool.Close())
+	})
+
+	t.Run("idle connections are closed", func(t *testing.T) {
+		addr := startWebSocketServer(t, echo)
+
+		pool := NewPool(WithIdleTimeout(50 * time.Millisecond))
+		outbound := NewOutbound(WithPool(pool))
+		require.NoError(t, outbound.Start(newProvider()))
+
+		returnRouteSession(t, outbound, addr)
+
+		waitFor(t, func() bool { return pool.Stats().IdleClosed == 1 && pool.Stats().OpenConnections == 0 })
+		require.NoError(t, pool.Close())
+	})
+
+	t.Run("connection limit", func(t *testing.T) {
+		port := ":" + strconv.Itoa(transportutil.GetRandomPort(5))
+
+		pool := NewPool(WithMaxConnections(1))
+		inbound, err := NewInbound(port, "", "", "", WithPool(pool))
+		require.NoError(t, err)
+		require.NoError(t, inbound.Start(newProvider()))
+
+		_, cleanup := websocketClient(t, port)
+
+		waitFor(t, func() bool { return pool.Stats().InboundConnections == 1 })
+
+		_, _, err = websocket.Dial(context.Background(), "ws://localhost"+port, nil) // nolint:bodyclose
+		require.Error(t, err)
+		require.Equal(t, uint64(1), pool.Stats().Refused)
+
+		outbound := NewOutbound(WithPool(pool))
+		require.NoError(t, outbound.Start(newProvider()))
+
+		_, err = outbound.Send([]byte("msg"),
+			prepareDestinationWithTransport("ws://localhost"+port, decorator.TransportReturnRouteAll, []string{verKey}))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), errPoolFull.Error())
+
+		cleanup()
+		require.NoError(t, inbound.Stop())
+	})
+
+	t.Run("read limit", func(t *testing.T) {
+		port := ":" + strconv.Itoa(transportutil.GetRandomPort(5))
+
+		pool := NewPool(WithReadLimit(10))
+		inbound, err := NewInbound(port, "", "", "", WithPool(pool))
+		require.NoError(t, err)
+		require.NoError(t, inbound.Start(newProvider()))
+
+		client, cleanup := websocketClient(t, port)
+		defer cleanup()
+
+		require.NoError(t, client.Write(context.Background(), websocket.MessageText, make([]byte, 100)))
+
+		waitFor(t, func() bool { return pool.Stats().Closed == 1 })
+		require.NoError(t, inbound.Stop())
+	})
+
+	t.Run("inbound return route keys are removed with the connection", func(t *testing.T) {
+		port := ":" + strconv.Itoa(transportutil.GetRandomPort(5))
+
+		inbound, err := NewInbound(port, "", "", "", WithPool(NewPool()))
+		require.NoError(t, err)
+		require.NoError(t, inbound.Start(newProvider()))
+
+		client, cleanup := websocketClient(t, port)
+
+		require.NoError(t, client.Write(context.Background(), websocket.MessageText,
+			createTransportDecRequest(t, decorator.TransportReturnRouteAll)))
+
+		waitFor(t, func() bool { return inbound.Pool().Stats().Keys == 1 })
+
+		cleanup()
+
+		waitFor(t, func() bool { return inbound.Pool().Stats().Keys == 0 })
+		require.Equal(t, 0, inbound.Pool().Stats().OpenConnections)
+		require.NoError(t, inbound.Stop())
+	})
+
+	t.Run("lost return route session is reconnected", func(t *testing.T) {
+		var accepted int32
+
+		addr := startWebSocketServer(t, func(t *testing.T, w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&accepted, 1) == 1 {
+				c, err := Accept(w, r)
+				require.NoError(t, err)
+
+				// drop the first session
+				_ = c.Close(websocket.StatusGoingAway, "going away") // nolint:errcheck
+
+				return
+			}
+
+			echo(t, w, r)
+		})
+
+		pool := NewPool(WithReconnect(10*time.Millisecond, 20*time.Millisecond, 5))
+		outbound := NewOutbound(WithPool(pool))
+		require.NoError(t, outbound.Start(newProvider()))
+
+		returnRouteSession(t, outbound, addr)
+
+		waitFor(t, func() bool { return pool.Stats().Reconnects == 1 })
+
+		stats := pool.Stats()
+		require.Equal(t, 1, stats.OpenConnections)
+		require.Equal(t, 1, stats.Keys)
+		require.NotNil(t, pool.fetch(verKey))
+
+		require.NoError(t, pool.Close())
+		waitFor(t, func() bool { return pool.Stats().OpenConnections == 0 })
+	})
+}
@@ -33,10 +33,10 @@ type mockProvider struct {
 }
 
 func (p *mockProvider) InboundMessageHandler() transport.InboundMessageHandler {
-	return func(message []byte, myDID, theirDID string) error {
-		logger.Infof("message received is %s", string(message))
+	return func(envelope *commontransport.Envelope) error {
+		logger.Infof("message received is %s", string(envelope.Message))
 
-		if string(message) == "invalid-data" {
+		if string(envelope.Message) == "invalid-data" {
 			return errors.New("error")
 		}
 
@@ -153,8 +153,8 @@ func (m *mockPackager) UnpackMessage(encMessage []byte) (*commontransport.Envelo
 
 type mockTransportProvider struct {
 	packagerValue  commontransport.Packager
-	executeInbound func(message []byte, myDID, theirDID string) error
-	frameworkID    string
+	executeInbound func(envelope *commontransport.Envelope) error
+	pool           *Pool
 }
 
 func (p *mockTransportProvider) InboundMessageHandler() transport.InboundMessageHandler {
@@ -166,9 +166,9 @@ func (p *mockTransportProvider) Packager() commontransport.Packager {
 }
 
 func (p *mockTransportProvider) AriesFrameworkID() string {
-	if p.frameworkID != "" {
-		return p.frameworkID
-	}
-
 	return "framework-instance-1"
 }
+
+func (p *mockTransportProvider) WebSocketPool() *Pool {
+	return p.pool
+}
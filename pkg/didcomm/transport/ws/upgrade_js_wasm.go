@@ -20,7 +20,7 @@ func Accept(_ http.ResponseWriter, _ *http.Request) (*websocket.Conn, error) {
 	return nil, errors.New("invalid operation with JS/WASM target")
 }
 
-func acceptRecipient(pool *connPool, keys []string) bool {
+func acceptRecipient(pool *Pool, keys []string) bool {
 	for _, v := range keys {
 		// check if the connection exists for the key
 		if c := pool.fetch(v); c != nil {
@@ -32,6 +32,6 @@ func acceptRecipient(pool *connPool, keys []string) bool {
 	return false
 }
 
-func keepConnAlive(conn *websocket.Conn, outbound bool, frequency time.Duration) {
+func keepConnAlive(conn *websocket.Conn, frequency time.Duration, done <-chan struct{}) {
 	// TODO make sure connection is alive (conn.Ping() doesn't work with JS/WASM build)
 }
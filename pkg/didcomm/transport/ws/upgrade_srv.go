@@ -1,3 +1,4 @@
+//go:build !js && !wasm
 // +build !js,!wasm
 
 /*
@@ -26,7 +27,7 @@ func Accept(w http.ResponseWriter, r *http.Request) (*websocket.Conn, error) {
 	})
 }
 
-func acceptRecipient(pool *connPool, keys []string) bool {
+func acceptRecipient(pool *Pool, keys []string) bool {
 	for _, v := range keys {
 		// check if the connection exists for the key
 		if c := pool.fetch(v); c != nil {
@@ -35,7 +36,7 @@ func acceptRecipient(pool *connPool, keys []string) bool {
 				// remove from the pool
 				pool.remove(v)
 
-				logger.Infof("failed to ping to the connection for key=%s err=%v", err)
+				logger.Infof("failed to ping to the connection for key=%s err=%v", v, err)
 
 				return false
 			}
@@ -49,23 +50,27 @@ func acceptRecipient(pool *connPool, keys []string) bool {
 
 // keepConnAlive sends the pings the server based on time frequency. The web server, load balancer, network routers
 // between the client and server closes the TCP keepalives connection. This function calls websocket ping request
-// directly to the server and keeps the connection active.
-func keepConnAlive(conn *websocket.Conn, outbound bool, frequency time.Duration) {
-	if outbound {
-		ticker := time.NewTicker(frequency)
-		done := make(chan struct{})
-
-		for {
-			select {
-			case <-done:
-				return
-			case <-ticker.C:
-				if err := conn.Ping(context.Background()); err != nil {
-					logger.Errorf("websocket ping error : %v", err)
+// directly to the server and keeps the connection active. The connection is closed if the ping fails, it stops when
+// the done channel is closed.
+func keepConnAlive(conn *websocket.Conn, frequency time.Duration, done <-chan struct{}) {
+	if frequency <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(frequency)
+	defer ticker.Stop()
 
-					ticker.Stop()
-					done <- struct{}{}
-				}
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			if err := conn.Ping(context.Background()); err != nil {
+				logger.Errorf("websocket ping error : %v", err)
+
+				_ = conn.Close(websocket.StatusGoingAway, "ping failed") // nolint:errcheck
+
+				return
 			}
 		}
 	}
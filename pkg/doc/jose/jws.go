@@ -23,6 +23,9 @@ const (
 )
 
 // JSONWebSignature defines JSON Web Signature (https://tools.ietf.org/html/rfc7515)
+//
+// ProtectedHeaders and UnprotectedHeaders hold the headers of the first signature. A JWS with several
+// signatures (JWS JSON Serialization) exposes all of them via Signatures().
 type JSONWebSignature struct {
 	ProtectedHeaders   Headers
 	UnprotectedHeaders Headers
@@ -30,6 +33,15 @@ type JSONWebSignature struct {
 
 	signature   []byte
 	joseHeaders Headers
+	signatures  []JWSSignature
+}
+
+// JWSSignature is a single signature of the JWS along with its per-signature headers
+// (https://tools.ietf.org/html/rfc7515#section-7.2.1).
+type JWSSignature struct {
+	ProtectedHeaders   Headers
+	UnprotectedHeaders Headers
+	Signature          []byte
 }
 
 // SignatureVerifier makes verification of JSON Web Signature.
@@ -112,12 +124,81 @@ func NewJWS(protectedHeaders, unprotectedHeaders Headers, payload []byte, signer
 	}
 
 	jws.signature = signature
+	jws.signatures = []JWSSignature{{
+		ProtectedHeaders:   headers,
+		UnprotectedHeaders: unprotectedHeaders,
+		Signature:          signature,
+	}}
 
 	return jws, nil
 }
 
+// AddSignature signs JWS payload by one more signer. The resulting multi-signature JWS can be serialized
+// using JWS JSON Serialization only.
+func (s *JSONWebSignature) AddSignature(protectedHeaders, unprotectedHeaders Headers, signer Signer) error {
+	headers := mergeHeaders(protectedHeaders, signer.Headers())
+
+	err := checkDisjointHeaders(headers, unprotectedHeaders)
+	if err != nil {
+		return err
+	}
+
+	if len(s.signatures) > 0 {
+		err = checkSameB64Payload(s.joseHeaders, headers)
+		if err != nil {
+			return err
+		}
+	}
+
+	signature, err := sign(headers, s.Payload, signer)
+	if err != nil {
+		return fmt.Errorf("sign JWS: %w", err)
+	}
+
+	if len(s.signatures) == 0 {
+		s.ProtectedHeaders = headers
+		s.UnprotectedHeaders = unprotectedHeaders
+		s.joseHeaders = headers
+		s.signature = signature
+	}
+
+	s.signatures = append(s.signatures, JWSSignature{
+		ProtectedHeaders:   headers,
+		UnprotectedHeaders: unprotectedHeaders,
+		Signature:          signature,
+	})
+
+	return nil
+}
+
+// Signatures returns a copy of all JWS signatures.
+func (s JSONWebSignature) Signatures() []JWSSignature {
+	if len(s.signatures) == 0 {
+		return nil
+	}
+
+	sigs := make([]JWSSignature, len(s.signatures))
+
+	for i, sig := range s.signatures {
+		sigCopy := make([]byte, len(sig.Signature))
+		copy(sigCopy, sig.Signature)
+
+		sigs[i] = JWSSignature{
+			ProtectedHeaders:   sig.ProtectedHeaders,
+			UnprotectedHeaders: sig.UnprotectedHeaders,
+			Signature:          sigCopy,
+		}
+	}
+
+	return sigs
+}
+
 // SerializeCompact makes JWS Compact Serialization (https://tools.ietf.org/html/rfc7515#section-7.1)
 func (s JSONWebSignature) SerializeCompact(detached bool) (string, error) {
+	if len(s.signatures) > 1 {
+		return "", errors.New("JWS compact serialization supports only one signature")
+	}
+
 	byteHeaders, err := json.Marshal(s.joseHeaders)
 	if err != nil {
 		return "", fmt.Errorf("marshal JWS JOSE Headers: %w", err)
@@ -126,8 +207,17 @@ func (s JSONWebSignature) SerializeCompact(detached bool) (string, error) {
 	b64Headers := base64.RawURLEncoding.EncodeToString(byteHeaders)
 
 	b64Payload := ""
+
 	if !detached {
-		b64Payload = base64.RawURLEncoding.EncodeToString(s.Payload)
+		b64Payload, err = encodePayload(s.joseHeaders, s.Payload)
+		if err != nil {
+			return "", err
+		}
+
+		// unencoded payload must not contain '.' in compact form (https://tools.ietf.org/html/rfc7797#section-5.2)
+		if strings.Contains(b64Payload, ".") {
+			return "", errors.New("unencoded JWS payload with '.' character cannot be compact serialized")
+		}
 	}
 
 	b64Signature := base64.RawURLEncoding.EncodeToString(s.signature)
@@ -198,7 +288,8 @@ func WithJWSDetachedPayload(payload []byte) JWSParseOpt {
 	}
 }
 
-// ParseJWS parses serialized JWS. Currently only JWS Compact Serialization parsing is supported.
+// ParseJWS parses serialized JWS. JWS Compact Serialization as well as general and flattened
+// JWS JSON Serialization are supported. Every signature of the JWS is checked by the verifier.
 func ParseJWS(jws string, verifier SignatureVerifier, opts ...JWSParseOpt) (*JSONWebSignature, error) {
 	pOpts := &jwsParseOpts{}
 
@@ -206,10 +297,8 @@ func ParseJWS(jws string, verifier SignatureVerifier, opts ...JWSParseOpt) (*JSO
 		opt(pOpts)
 	}
 
-	if strings.HasPrefix(jws, "{") {
-		// TODO support JWS JSON serialization format
-		//  https://github.com/hyperledger/aries-framework-go/issues/1331
-		return nil, errors.New("JWS JSON serialization is not supported")
+	if strings.HasPrefix(strings.TrimSpace(jws), "{") {
+		return parseJSON(jws, verifier, pOpts)
 	}
 
 	return parseCompacted(jws, verifier, pOpts)
@@ -233,7 +322,7 @@ func parseCompacted(jwsCompact string, verifier SignatureVerifier, opts *jwsPars
 		return nil, err
 	}
 
-	payload, err := parseCompactedPayload(parts[jwsPayloadPart], opts)
+	payload, err := parseCompactedPayload(joseHeaders, parts[jwsPayloadPart], opts)
 	if err != nil {
 		return nil, err
 	}
@@ -258,20 +347,19 @@ func parseCompacted(jwsCompact string, verifier SignatureVerifier, opts *jwsPars
 		Payload:          payload,
 		signature:        signature,
 		joseHeaders:      joseHeaders,
+		signatures: []JWSSignature{{
+			ProtectedHeaders: joseHeaders,
+			Signature:        signature,
+		}},
 	}, nil
 }
 
-func parseCompactedPayload(jwsPayload string, opts *jwsParseOpts) ([]byte, error) {
+func parseCompactedPayload(joseHeaders Headers, jwsPayload string, opts *jwsParseOpts) ([]byte, error) {
 	if len(opts.detachedPayload) > 0 {
 		return opts.detachedPayload, nil
 	}
 
-	payload, err := base64.RawURLEncoding.DecodeString(jwsPayload)
-	if err != nil {
-		return nil, fmt.Errorf("decode base64 payload: %w", err)
-	}
-
-	return payload, nil
+	return decodePayload(joseHeaders, jwsPayload)
 }
 
 func parseCompactedHeaders(parts []string) (Headers, error) {
@@ -301,25 +389,97 @@ func signingInput(headers Headers, payload []byte) ([]byte, error) {
 		return nil, fmt.Errorf("serialize JWS headers: %w", err)
 	}
 
-	hBase64 := true
+	headersStr := base64.RawURLEncoding.EncodeToString(headersBytes)
 
-	if b64, ok := headers[HeaderB64Payload]; ok {
-		if hBase64, ok = b64.(bool); !ok {
-			return nil, errors.New("invalid b64 header")
-		}
+	payloadStr, err := encodePayload(headers, payload)
+	if err != nil {
+		return nil, err
 	}
 
-	headersStr := base64.RawURLEncoding.EncodeToString(headersBytes)
+	return []byte(fmt.Sprintf("%s.%s", headersStr, payloadStr)), nil
+}
 
-	var payloadStr string
+// isB64Payload checks the b64 header (https://tools.ietf.org/html/rfc7797#section-3). The payload is
+// base64url encoded unless b64 is explicitly set to false.
+func isB64Payload(headers Headers) (bool, error) {
+	b64, ok := headers[HeaderB64Payload]
+	if !ok {
+		return true, nil
+	}
 
-	if hBase64 {
-		payloadStr = base64.RawURLEncoding.EncodeToString(payload)
-	} else {
-		payloadStr = string(payload)
+	hBase64, ok := b64.(bool)
+	if !ok {
+		return false, errors.New("invalid b64 header")
 	}
 
-	return []byte(fmt.Sprintf("%s.%s", headersStr, payloadStr)), nil
+	return hBase64, nil
+}
+
+func encodePayload(headers Headers, payload []byte) (string, error) {
+	hBase64, err := isB64Payload(headers)
+	if err != nil {
+		return "", err
+	}
+
+	if !hBase64 {
+		return string(payload), nil
+	}
+
+	return base64.RawURLEncoding.EncodeToString(payload), nil
+}
+
+func decodePayload(headers Headers, jwsPayload string) ([]byte, error) {
+	hBase64, err := isB64Payload(headers)
+	if err != nil {
+		return nil, err
+	}
+
+	if !hBase64 {
+		return []byte(jwsPayload), nil
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(jwsPayload)
+	if err != nil {
+		return nil, fmt.Errorf("decode base64 payload: %w", err)
+	}
+
+	return payload, nil
+}
+
+// checkSameB64Payload checks that all signatures of JWS agree on payload encoding
+// (https://tools.ietf.org/html/rfc7797#section-3).
+func checkSameB64Payload(h1, h2 Headers) error {
+	b64First, err := isB64Payload(h1)
+	if err != nil {
+		return err
+	}
+
+	b64Second, err := isB64Payload(h2)
+	if err != nil {
+		return err
+	}
+
+	if b64First != b64Second {
+		return errors.New("b64 header value must be the same for all JWS signatures")
+	}
+
+	return nil
+}
+
+// checkDisjointHeaders checks that protected and unprotected headers do not share parameter names
+// (https://tools.ietf.org/html/rfc7515#section-7.2.1).
+func checkDisjointHeaders(protectedHeaders, unprotectedHeaders Headers) error {
+	for k := range unprotectedHeaders {
+		if _, ok := protectedHeaders[k]; ok {
+			return fmt.Errorf("header %s is present in both protected and unprotected JWS headers", k)
+		}
+	}
+
+	if _, ok := unprotectedHeaders[HeaderB64Payload]; ok {
+		return fmt.Errorf("%s header must be integrity protected", HeaderB64Payload)
+	}
+
+	return nil
 }
 
 func checkJWSHeaders(headers Headers) error {
@@ -0,0 +1,259 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jose
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/square/go-jose/v3/json"
+)
+
+// rawJSONWebSignature represents a RAW JWS in JSON Serialization (https://tools.ietf.org/html/rfc7515#section-7.2).
+// General syntax uses Signatures, flattened syntax uses the single signature fields on the top level.
+type rawJSONWebSignature struct {
+	Payload    string             `json:"payload,omitempty"`
+	Signatures []*rawJWSSignature `json:"signatures,omitempty"`
+
+	B64ProtectedHeaders string  `json:"protected,omitempty"`
+	UnprotectedHeaders  Headers `json:"header,omitempty"`
+	B64Signature        string  `json:"signature,omitempty"`
+}
+
+// rawJWSSignature represents a single signature of RAW JWS in general JSON Serialization.
+type rawJWSSignature struct {
+	B64ProtectedHeaders string  `json:"protected,omitempty"`
+	UnprotectedHeaders  Headers `json:"header,omitempty"`
+	B64Signature        string  `json:"signature"`
+}
+
+// SerializeJSON makes general JWS JSON Serialization (https://tools.ietf.org/html/rfc7515#section-7.2.1).
+// If detached is true, the payload is omitted (https://tools.ietf.org/html/rfc7515#appendix-F).
+func (s JSONWebSignature) SerializeJSON(detached bool) (string, error) {
+	if len(s.signatures) == 0 {
+		return "", errors.New("JWS has no signatures")
+	}
+
+	payload, err := s.serializedPayload(detached)
+	if err != nil {
+		return "", err
+	}
+
+	rawJWS := rawJSONWebSignature{
+		Payload:    payload,
+		Signatures: make([]*rawJWSSignature, len(s.signatures)),
+	}
+
+	for i, sig := range s.signatures {
+		rawSig, err := sig.toRaw()
+		if err != nil {
+			return "", err
+		}
+
+		rawJWS.Signatures[i] = rawSig
+	}
+
+	return marshalRawJWS(rawJWS)
+}
+
+// SerializeFlattenedJSON makes flattened JWS JSON Serialization (https://tools.ietf.org/html/rfc7515#section-7.2.2).
+// Flattened syntax supports a JWS with exactly one signature.
+func (s JSONWebSignature) SerializeFlattenedJSON(detached bool) (string, error) {
+	if len(s.signatures) != 1 {
+		return "", errors.New("JWS flattened JSON serialization supports only one signature")
+	}
+
+	payload, err := s.serializedPayload(detached)
+	if err != nil {
+		return "", err
+	}
+
+	rawSig, err := s.signatures[0].toRaw()
+	if err != nil {
+		return "", err
+	}
+
+	return marshalRawJWS(rawJSONWebSignature{
+		Payload:             payload,
+		B64ProtectedHeaders: rawSig.B64ProtectedHeaders,
+		UnprotectedHeaders:  rawSig.UnprotectedHeaders,
+		B64Signature:        rawSig.B64Signature,
+	})
+}
+
+func (s JSONWebSignature) serializedPayload(detached bool) (string, error) {
+	if detached {
+		return "", nil
+	}
+
+	return encodePayload(s.joseHeaders, s.Payload)
+}
+
+func (sig JWSSignature) toRaw() (*rawJWSSignature, error) {
+	protectedBytes, err := json.Marshal(sig.ProtectedHeaders)
+	if err != nil {
+		return nil, fmt.Errorf("marshal JWS protected headers: %w", err)
+	}
+
+	return &rawJWSSignature{
+		B64ProtectedHeaders: base64.RawURLEncoding.EncodeToString(protectedBytes),
+		UnprotectedHeaders:  sig.UnprotectedHeaders,
+		B64Signature:        base64.RawURLEncoding.EncodeToString(sig.Signature),
+	}, nil
+}
+
+func marshalRawJWS(rawJWS rawJSONWebSignature) (string, error) {
+	jwsBytes, err := json.Marshal(rawJWS)
+	if err != nil {
+		return "", fmt.Errorf("marshal JWS JSON: %w", err)
+	}
+
+	return string(jwsBytes), nil
+}
+
+func parseJSON(jwsJSON string, verifier SignatureVerifier, opts *jwsParseOpts) (*JSONWebSignature, error) {
+	var rawJWS rawJSONWebSignature
+
+	err := json.Unmarshal([]byte(jwsJSON), &rawJWS)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal JWS JSON: %w", err)
+	}
+
+	rawSigs, err := rawJWS.rawSignatures()
+	if err != nil {
+		return nil, err
+	}
+
+	jws := &JSONWebSignature{
+		signatures: make([]JWSSignature, len(rawSigs)),
+	}
+
+	for i, rawSig := range rawSigs {
+		sig, joseHeaders, err := parseJSONSignature(rawSig)
+		if err != nil {
+			return nil, fmt.Errorf("parse JWS signature %d: %w", i, err)
+		}
+
+		if i == 0 {
+			jws.ProtectedHeaders = sig.ProtectedHeaders
+			jws.UnprotectedHeaders = sig.UnprotectedHeaders
+			jws.signature = sig.Signature
+			jws.joseHeaders = sig.ProtectedHeaders
+
+			jws.Payload, err = parseJSONPayload(joseHeaders, rawJWS.Payload, opts)
+			if err != nil {
+				return nil, err
+			}
+		} else if err = checkSameB64Payload(jws.joseHeaders, joseHeaders); err != nil {
+			return nil, err
+		}
+
+		sInput, err := jsonSigningInput(rawSig.B64ProtectedHeaders, joseHeaders, rawJWS.Payload, jws.Payload)
+		if err != nil {
+			return nil, fmt.Errorf("build signing input: %w", err)
+		}
+
+		err = verifier.Verify(joseHeaders, jws.Payload, sInput, sig.Signature)
+		if err != nil {
+			return nil, err
+		}
+
+		jws.signatures[i] = *sig
+	}
+
+	return jws, nil
+}
+
+func (r *rawJSONWebSignature) rawSignatures() ([]*rawJWSSignature, error) {
+	flattened := r.B64Signature != "" || r.B64ProtectedHeaders != "" || r.UnprotectedHeaders != nil
+
+	switch {
+	case len(r.Signatures) > 0 && flattened:
+		return nil, errors.New("JWS JSON must not mix general and flattened syntax")
+	case len(r.Signatures) > 0:
+		return r.Signatures, nil
+	case r.B64Signature != "":
+		return []*rawJWSSignature{{
+			B64ProtectedHeaders: r.B64ProtectedHeaders,
+			UnprotectedHeaders:  r.UnprotectedHeaders,
+			B64Signature:        r.B64Signature,
+		}}, nil
+	default:
+		return nil, errors.New("JWS JSON has no signatures")
+	}
+}
+
+// parseJSONSignature parses a single signature and returns it together with its JOSE Header which is
+// the union of protected and unprotected headers (https://tools.ietf.org/html/rfc7515#section-7.2.1).
+func parseJSONSignature(rawSig *rawJWSSignature) (*JWSSignature, Headers, error) {
+	if rawSig == nil {
+		return nil, nil, errors.New("empty signature")
+	}
+
+	var protectedHeaders Headers
+
+	if rawSig.B64ProtectedHeaders != "" {
+		headersBytes, err := base64.RawURLEncoding.DecodeString(rawSig.B64ProtectedHeaders)
+		if err != nil {
+			return nil, nil, fmt.Errorf("decode base64 header: %w", err)
+		}
+
+		err = json.Unmarshal(headersBytes, &protectedHeaders)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unmarshal JSON headers: %w", err)
+		}
+	}
+
+	err := checkDisjointHeaders(protectedHeaders, rawSig.UnprotectedHeaders)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	joseHeaders := mergeHeaders(protectedHeaders, rawSig.UnprotectedHeaders)
+
+	err = checkJWSHeaders(joseHeaders)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(rawSig.B64Signature)
+	if err != nil {
+		return nil, nil, fmt.Errorf("decode base64 signature: %w", err)
+	}
+
+	return &JWSSignature{
+		ProtectedHeaders:   protectedHeaders,
+		UnprotectedHeaders: rawSig.UnprotectedHeaders,
+		Signature:          signature,
+	}, joseHeaders, nil
+}
+
+func parseJSONPayload(joseHeaders Headers, jwsPayload string, opts *jwsParseOpts) ([]byte, error) {
+	if len(opts.detachedPayload) > 0 {
+		return opts.detachedPayload, nil
+	}
+
+	return decodePayload(joseHeaders, jwsPayload)
+}
+
+// jsonSigningInput builds signing input using protected headers exactly as they were serialized.
+func jsonSigningInput(b64ProtectedHeaders string, joseHeaders Headers, jwsPayload string,
+	payload []byte) ([]byte, error) {
+	payloadStr := jwsPayload
+
+	if payloadStr == "" {
+		var err error
+
+		payloadStr, err = encodePayload(joseHeaders, payload)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return []byte(fmt.Sprintf("%s.%s", b64ProtectedHeaders, payloadStr)), nil
+}
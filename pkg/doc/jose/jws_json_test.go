@@ -0,0 +1,291 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jose
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONWebSignature_SerializeJSON(t *testing.T) {
+	payload := []byte("payload")
+
+	jws, err := NewJWS(Headers{"typ": "JWT"}, Headers{"kid": "key-1"}, payload,
+		&testSigner{
+			headers:   Headers{"alg": "EdDSA"},
+			signature: []byte("signature-1"),
+		})
+	require.NoError(t, err)
+
+	err = jws.AddSignature(nil, Headers{"kid": "key-2"},
+		&testSigner{
+			headers:   Headers{"alg": "ES256"},
+			signature: []byte("signature-2"),
+		})
+	require.NoError(t, err)
+	require.Len(t, jws.Signatures(), 2)
+
+	t.Run("general JSON serialization", func(t *testing.T) {
+		jwsJSON, err := jws.SerializeJSON(false)
+		require.NoError(t, err)
+		require.Contains(t, jwsJSON, `"signatures"`)
+
+		verifier := &recordingVerifier{}
+
+		parsedJWS, err := ParseJWS(jwsJSON, verifier)
+		require.NoError(t, err)
+		require.Equal(t, payload, parsedJWS.Payload)
+		require.Equal(t, jws.Signatures(), parsedJWS.Signatures())
+		require.Equal(t, []string{"key-1", "key-2"}, verifier.kids)
+
+		for i, sig := range jws.Signatures() {
+			require.Equal(t, sig.Signature, verifier.signatures[i])
+		}
+	})
+
+	t.Run("detached payload", func(t *testing.T) {
+		jwsJSON, err := jws.SerializeJSON(true)
+		require.NoError(t, err)
+		require.NotContains(t, jwsJSON, `"payload"`)
+
+		parsedJWS, err := ParseJWS(jwsJSON, &testVerifier{}, WithJWSDetachedPayload(payload))
+		require.NoError(t, err)
+		require.Equal(t, payload, parsedJWS.Payload)
+	})
+
+	t.Run("compact and flattened serialization are not supported for several signatures", func(t *testing.T) {
+		_, err := jws.SerializeCompact(false)
+		require.EqualError(t, err, "JWS compact serialization supports only one signature")
+
+		_, err = jws.SerializeFlattenedJSON(false)
+		require.EqualError(t, err, "JWS flattened JSON serialization supports only one signature")
+	})
+
+	t.Run("no signatures", func(t *testing.T) {
+		_, err := JSONWebSignature{}.SerializeJSON(false)
+		require.EqualError(t, err, "JWS has no signatures")
+	})
+
+	t.Run("protected headers marshalling error", func(t *testing.T) {
+		badJWS := JSONWebSignature{signatures: []JWSSignature{{ProtectedHeaders: getUnmarshallableMap()}}}
+
+		_, err := badJWS.SerializeJSON(false)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "marshal JWS protected headers")
+	})
+}
+
+func TestJSONWebSignature_AddSignature(t *testing.T) {
+	t.Run("add to empty JWS", func(t *testing.T) {
+		jws := &JSONWebSignature{Payload: []byte("payload")}
+
+		err := jws.AddSignature(Headers{"typ": "JWT"}, nil,
+			&testSigner{headers: Headers{"alg": "EdDSA"}, signature: []byte("signature")})
+		require.NoError(t, err)
+		require.Equal(t, []byte("signature"), jws.Signature())
+		require.Equal(t, Headers{"typ": "JWT", "alg": "EdDSA"}, jws.ProtectedHeaders)
+	})
+
+	jws, err := NewJWS(nil, nil, []byte("payload"),
+		&testSigner{headers: Headers{"alg": "EdDSA"}, signature: []byte("signature")})
+	require.NoError(t, err)
+
+	t.Run("protected and unprotected headers are not disjoint", func(t *testing.T) {
+		err := jws.AddSignature(Headers{"kid": "key-1"}, Headers{"kid": "key-1"},
+			&testSigner{headers: Headers{"alg": "EdDSA"}})
+		require.EqualError(t, err, "header kid is present in both protected and unprotected JWS headers")
+	})
+
+	t.Run("b64 in unprotected headers", func(t *testing.T) {
+		err := jws.AddSignature(nil, Headers{"b64": false},
+			&testSigner{headers: Headers{"alg": "EdDSA"}})
+		require.EqualError(t, err, "b64 header must be integrity protected")
+	})
+
+	t.Run("b64 differs from other signatures", func(t *testing.T) {
+		err := jws.AddSignature(Headers{"b64": false}, nil,
+			&testSigner{headers: Headers{"alg": "EdDSA"}})
+		require.EqualError(t, err, "b64 header value must be the same for all JWS signatures")
+	})
+
+	t.Run("signer error", func(t *testing.T) {
+		err := jws.AddSignature(nil, nil,
+			&testSigner{headers: Headers{"alg": "EdDSA"}, err: errors.New("signer error")})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "sign JWS verification data")
+	})
+}
+
+func TestJSONWebSignature_SerializeFlattenedJSON(t *testing.T) {
+	payload := []byte("payload")
+
+	jws, err := NewJWS(Headers{"typ": "JWT"}, Headers{"kid": "key-1"}, payload,
+		&testSigner{headers: Headers{"alg": "EdDSA"}, signature: []byte("signature")})
+	require.NoError(t, err)
+
+	jwsJSON, err := jws.SerializeFlattenedJSON(false)
+	require.NoError(t, err)
+	require.NotContains(t, jwsJSON, `"signatures"`)
+	require.Contains(t, jwsJSON, `"header":{"kid":"key-1"}`)
+
+	parsedJWS, err := ParseJWS(jwsJSON, &testVerifier{})
+	require.NoError(t, err)
+	require.Equal(t, jws, parsedJWS)
+}
+
+func TestJSONWebSignature_UnencodedPayload(t *testing.T) {
+	payload := []byte(`{"hello": "world"}`)
+	protectedHeaders := Headers{"b64": false, "crit": []interface{}{"b64"}}
+
+	jws, err := NewJWS(protectedHeaders, nil, payload,
+		&testSigner{headers: Headers{"alg": "EdDSA"}, signature: []byte("signature")})
+	require.NoError(t, err)
+
+	err = jws.AddSignature(protectedHeaders, nil,
+		&testSigner{headers: Headers{"alg": "ES256"}, signature: []byte("signature-2")})
+	require.NoError(t, err)
+
+	jwsJSON, err := jws.SerializeJSON(false)
+	require.NoError(t, err)
+	require.Contains(t, jwsJSON, `"payload":"{\"hello\": \"world\"}"`)
+
+	verifier := &recordingVerifier{}
+
+	parsedJWS, err := ParseJWS(jwsJSON, verifier)
+	require.NoError(t, err)
+	require.Equal(t, payload, parsedJWS.Payload)
+
+	for _, sInput := range verifier.signingInputs {
+		require.True(t, bytes.HasSuffix(sInput, append([]byte("."), payload...)))
+	}
+
+	t.Run("compact serialization", func(t *testing.T) {
+		jws, err := NewJWS(protectedHeaders, nil, []byte("payload"),
+			&testSigner{headers: Headers{"alg": "EdDSA"}, signature: []byte("signature")})
+		require.NoError(t, err)
+
+		jwsCompact, err := jws.SerializeCompact(false)
+		require.NoError(t, err)
+		require.Equal(t, "payload", strings.Split(jwsCompact, ".")[1])
+
+		parsedJWS, err := ParseJWS(jwsCompact, &testVerifier{})
+		require.NoError(t, err)
+		require.Equal(t, []byte("payload"), parsedJWS.Payload)
+
+		jws, err = NewJWS(protectedHeaders, nil, []byte("pay.load"),
+			&testSigner{headers: Headers{"alg": "EdDSA"}, signature: []byte("signature")})
+		require.NoError(t, err)
+
+		_, err = jws.SerializeCompact(false)
+		require.EqualError(t, err, "unencoded JWS payload with '.' character cannot be compact serialized")
+
+		jwsCompact, err = jws.SerializeCompact(true)
+		require.NoError(t, err)
+
+		parsedJWS, err = ParseJWS(jwsCompact, &testVerifier{}, WithJWSDetachedPayload([]byte("pay.load")))
+		require.NoError(t, err)
+		require.Equal(t, []byte("pay.load"), parsedJWS.Payload)
+	})
+}
+
+func TestParseJWS_JSON(t *testing.T) {
+	jws, err := NewJWS(Headers{"typ": "JWT"}, nil, []byte("payload"),
+		&testSigner{headers: Headers{"alg": "EdDSA"}, signature: []byte("signature")})
+	require.NoError(t, err)
+
+	validJSON, err := jws.SerializeFlattenedJSON(false)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		jws    string
+		errMsg string
+	}{
+		{
+			name:   "invalid JSON",
+			jws:    `{"payload":`,
+			errMsg: "unmarshal JWS JSON",
+		},
+		{
+			name:   "general and flattened syntax are mixed",
+			jws:    `{"payload":"cGF5bG9hZA","signature":"c2ln","signatures":[{"signature":"c2ln"}]}`,
+			errMsg: "JWS JSON must not mix general and flattened syntax",
+		},
+		{
+			name:   "empty signature",
+			jws:    `{"payload":"cGF5bG9hZA","signatures":[null]}`,
+			errMsg: "parse JWS signature 0: empty signature",
+		},
+		{
+			name:   "invalid protected headers encoding",
+			jws:    `{"payload":"cGF5bG9hZA","protected":"XXXXXaGVsbG8=","signature":"c2ln"}`,
+			errMsg: "decode base64 header",
+		},
+		{
+			name:   "invalid protected headers",
+			jws:    `{"payload":"cGF5bG9hZA","protected":"aW52YWxpZA","signature":"c2ln"}`,
+			errMsg: "unmarshal JSON headers",
+		},
+		{
+			name:   "alg is missing",
+			jws:    `{"payload":"cGF5bG9hZA","header":{"kid":"k"},"signature":"c2ln"}`,
+			errMsg: "alg JWS header is not defined",
+		},
+		{
+			name:   "invalid signature encoding",
+			jws:    `{"payload":"cGF5bG9hZA","header":{"alg":"EdDSA"},"signature":"XXXXXaGVsbG8="}`,
+			errMsg: "decode base64 signature",
+		},
+		{
+			name:   "invalid payload encoding",
+			jws:    `{"payload":"XXXXXaGVsbG8=","header":{"alg":"EdDSA"},"signature":"c2ln"}`,
+			errMsg: "decode base64 payload",
+		},
+		{
+			name: "b64 differs between signatures",
+			jws: `{"payload":"cGF5bG9hZA","signatures":[{"header":{"alg":"EdDSA"},"signature":"c2ln"},` +
+				`{"protected":"eyJhbGciOiJFZERTQSIsImI2NCI6ZmFsc2V9","signature":"c2ln"}]}`,
+			errMsg: "b64 header value must be the same for all JWS signatures",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			parsedJWS, err := ParseJWS(tc.jws, &testVerifier{})
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.errMsg)
+			require.Nil(t, parsedJWS)
+		})
+	}
+
+	t.Run("verifier error", func(t *testing.T) {
+		parsedJWS, err := ParseJWS(validJSON, &testVerifier{err: errors.New("bad signature")})
+		require.EqualError(t, err, "bad signature")
+		require.Nil(t, parsedJWS)
+	})
+}
+
+type recordingVerifier struct {
+	kids          []string
+	signatures    [][]byte
+	signingInputs [][]byte
+}
+
+func (v *recordingVerifier) Verify(joseHeaders Headers, _, signingInput, signature []byte) error {
+	kid, _ := joseHeaders.KeyID()
+
+	v.kids = append(v.kids, kid)
+	v.signatures = append(v.signatures, signature)
+	v.signingInputs = append(v.signingInputs, signingInput)
+
+	return nil
+}
@@ -176,10 +176,10 @@ func TestParseJWS(t *testing.T) {
 	require.NotNil(t, parsedJWS)
 	require.Equal(t, jws, parsedJWS)
 
-	// Parse not compact JWS format
+	// Parse JSON without signatures
 	parsedJWS, err = ParseJWS(`{"some": "JSON"}`, &testVerifier{})
 	require.Error(t, err)
-	require.EqualError(t, err, "JWS JSON serialization is not supported")
+	require.EqualError(t, err, "JWS JSON has no signatures")
 	require.Nil(t, parsedJWS)
 
 	// Parse invalid compact JWS format
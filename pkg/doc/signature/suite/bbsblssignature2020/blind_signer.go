@@ -0,0 +1,77 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bbsblssignature2020
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/hyperledger/aries-framework-go/pkg/crypto"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/primitive/bbs12381g2pub"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/proof"
+	sigverifier "github.com/hyperledger/aries-framework-go/pkg/doc/signature/verifier"
+)
+
+const proofValueKey = "proofValue"
+
+// BlindSigner creates BbsBlsSignature2020 signatures which are bound to the holder secrets hidden from the issuer.
+//
+// The holder commits to its secrets with bbs12381g2pub.BBSG2Pub.BlindMessages placing them right after the
+// document statements, i.e. at indexes [statementsCount, statementsCount+hiddenMessagesCount). The issuer signs
+// the document with the BlindSigner using the resulting blind signature context and its BBS+ key handle. The
+// issued proof must be unblinded by the holder with UnblindProof before it can be verified with
+// NewG2HolderBoundPublicKeyVerifier or used to derive BbsBlsSignatureProof2020 selective disclosures.
+type BlindSigner struct {
+	cr                  crypto.Crypto
+	kh                  interface{}
+	blindContext        []byte
+	nonce               []byte
+	hiddenMessagesCount int
+}
+
+// NewBlindSigner creates a new BlindSigner signing with the BBS+ private key in kh key handle of cr.
+func NewBlindSigner(cr crypto.Crypto, kh interface{}, blindContext, nonce []byte,
+	hiddenMessagesCount int) *BlindSigner {
+	return &BlindSigner{
+		cr:                  cr,
+		kh:                  kh,
+		blindContext:        blindContext,
+		nonce:               nonce,
+		hiddenMessagesCount: hiddenMessagesCount,
+	}
+}
+
+// Sign verifies the holder commitment and signs the document statements along with the committed holder secrets.
+func (s *BlindSigner) Sign(doc []byte) ([]byte, error) {
+	statements := sigverifier.SplitMessageIntoLines(string(doc), false)
+
+	revealedIndexes := make([]int, len(statements))
+	for i := range statements {
+		revealedIndexes[i] = i
+	}
+
+	return s.cr.BlindSignMulti(statements, revealedIndexes, len(statements)+s.hiddenMessagesCount,
+		s.blindContext, s.nonce, s.kh)
+}
+
+// UnblindProof unblinds the value of BbsBlsSignature2020 proof created by BlindSigner using the signature blinding
+// the holder secrets were committed with. The proof is updated in place.
+func UnblindProof(ldProof map[string]interface{}, blinding []byte) error {
+	p, err := proof.NewProof(ldProof)
+	if err != nil {
+		return fmt.Errorf("parse BBS+ proof: %w", err)
+	}
+
+	signature, err := bbs12381g2pub.New().UnblindSignature(p.ProofValue, blinding)
+	if err != nil {
+		return fmt.Errorf("unblind BBS+ signature: %w", err)
+	}
+
+	ldProof[proofValueKey] = base64.StdEncoding.EncodeToString(signature)
+
+	return nil
+}
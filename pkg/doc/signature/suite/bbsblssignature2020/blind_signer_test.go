@@ -0,0 +1,104 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bbsblssignature2020
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/google/tink/go/keyset"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/primitive/bbs12381g2pub"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/primitive/bbs"
+	sigverifier "github.com/hyperledger/aries-framework-go/pkg/doc/signature/verifier"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/localkms"
+	mockcrypto "github.com/hyperledger/aries-framework-go/pkg/mock/crypto"
+)
+
+func TestBlindSigner(t *testing.T) {
+	kh, err := keyset.NewHandle(bbs.BLS12381G2KeyTemplate())
+	require.NoError(t, err)
+
+	pubKH, err := kh.Public()
+	require.NoError(t, err)
+
+	pubKeyBuf := new(bytes.Buffer)
+	require.NoError(t, pubKH.WriteWithNoSecrets(localkms.NewWriter(pubKeyBuf)))
+
+	pubKeyBytes := pubKeyBuf.Bytes()
+
+	cr, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	doc := "statement 1\nstatement 2\n\nstatement 3\n"
+	holderSecret := []byte("holder link secret")
+	nonce := []byte("issuer nonce")
+
+	// the holder commits to its secret placed right after 3 document statements
+	blindContext, blinding, err := bbs12381g2pub.New().BlindMessages([][]byte{holderSecret}, []int{3}, 4,
+		nonce, pubKeyBytes)
+	require.NoError(t, err)
+
+	blindSignature, err := NewBlindSigner(cr, kh, blindContext, nonce, 1).Sign([]byte(doc))
+	require.NoError(t, err)
+
+	ldProof := map[string]interface{}{
+		"type":               signatureType,
+		"verificationMethod": "did:example:issuer#key1",
+		"created":            "2021-03-01T10:00:00Z",
+		"proofValue":         base64.StdEncoding.EncodeToString(blindSignature),
+	}
+
+	pubKeyValue := &sigverifier.PublicKey{Type: g2PubKeyType, Value: pubKeyBytes}
+	verifier := NewG2HolderBoundPublicKeyVerifier(holderSecret)
+
+	// blind signature is not valid until unblinded by the holder
+	require.Error(t, verifier.Verify(pubKeyValue, []byte(doc), blindSignature))
+
+	require.NoError(t, UnblindProof(ldProof, blinding))
+
+	signature, err := base64.StdEncoding.DecodeString(ldProof["proofValue"].(string))
+	require.NoError(t, err)
+
+	require.NoError(t, verifier.Verify(pubKeyValue, []byte(doc), signature))
+
+	t.Run("verification fails without holder secret", func(t *testing.T) {
+		require.Error(t, NewG2PublicKeyVerifier().Verify(pubKeyValue, []byte(doc), signature))
+		require.Error(t, NewG2HolderBoundPublicKeyVerifier([]byte("other secret")).Verify(pubKeyValue,
+			[]byte(doc), signature))
+	})
+
+	t.Run("unblind errors", func(t *testing.T) {
+		err := UnblindProof(map[string]interface{}{"type": signatureType}, blinding)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "parse BBS+ proof")
+
+		err = UnblindProof(map[string]interface{}{
+			"type":       signatureType,
+			"created":    "2021-03-01T10:00:00Z",
+			"proofValue": base64.StdEncoding.EncodeToString([]byte("invalid")),
+		}, blinding)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unblind BBS+ signature")
+	})
+
+	t.Run("sign with invalid blind context", func(t *testing.T) {
+		_, err := NewBlindSigner(cr, kh, blindContext, []byte("other nonce"), 1).Sign([]byte(doc))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid proof of hidden messages")
+	})
+
+	t.Run("sign error", func(t *testing.T) {
+		_, err := NewBlindSigner(&mockcrypto.Crypto{BBSBlindSignErr: errors.New("blind sign error")}, kh,
+			blindContext, nonce, 1).Sign([]byte(doc))
+		require.EqualError(t, err, "blind sign error")
+	})
+}
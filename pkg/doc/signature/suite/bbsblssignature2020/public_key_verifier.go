@@ -16,3 +16,10 @@ func NewG2PublicKeyVerifier() *verifier.PublicKeyVerifier {
 	return verifier.NewPublicKeyVerifier(verifier.NewBBSG2SignatureVerifier(),
 		verifier.WithExactPublicKeyType(g2PubKeyType))
 }
+
+// NewG2HolderBoundPublicKeyVerifier creates a signature verifier that verifies a BbsBlsSignature2020 signature
+// bound to the hidden holder secret (see BlindSigner) taking Bls12381G2Key2020 public key bytes as input.
+func NewG2HolderBoundPublicKeyVerifier(holderSecret []byte) *verifier.PublicKeyVerifier {
+	return verifier.NewPublicKeyVerifier(verifier.NewBBSG2HolderBoundSignatureVerifier(holderSecret),
+		verifier.WithExactPublicKeyType(g2PubKeyType))
+}
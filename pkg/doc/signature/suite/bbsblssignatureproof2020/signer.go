@@ -34,6 +34,23 @@ type keyResolver interface {
 // (with BbsBlsSignature2020 type).
 func (s *Suite) SelectiveDisclosure(doc map[string]interface{}, revealDoc map[string]interface{},
 	nonce []byte, resolver keyResolver, opts ...jsonld.ProcessorOpts) (map[string]interface{}, error) {
+	return s.selectiveDisclosure(doc, revealDoc, nonce, nil, resolver, opts...)
+}
+
+// SelectiveDisclosureWithHolderSecret creates selective disclosure from the input doc which must have a BBS+ proof
+// (with BbsBlsSignature2020 type) bound to the holder secret. The holder secret is never disclosed.
+func (s *Suite) SelectiveDisclosureWithHolderSecret(doc map[string]interface{}, revealDoc map[string]interface{},
+	nonce, holderSecret []byte, resolver keyResolver, opts ...jsonld.ProcessorOpts) (map[string]interface{}, error) {
+	if len(holderSecret) == 0 {
+		return nil, errors.New("holder secret is not defined")
+	}
+
+	return s.selectiveDisclosure(doc, revealDoc, nonce, [][]byte{holderSecret}, resolver, opts...)
+}
+
+func (s *Suite) selectiveDisclosure(doc map[string]interface{}, revealDoc map[string]interface{},
+	nonce []byte, holderSecrets [][]byte, resolver keyResolver,
+	opts ...jsonld.ProcessorOpts) (map[string]interface{}, error) {
 	docWithoutProof, rawProofs, err := prepareDocAndProof(doc, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("preparing doc failed: %w", err)
@@ -61,6 +78,9 @@ func (s *Suite) SelectiveDisclosure(doc map[string]interface{}, revealDoc map[st
 			return nil, fmt.Errorf("build verification data: %w", dErr)
 		}
 
+		// holder secrets are signed as hidden messages following the document statements
+		verData.blsMessages = append(verData.blsMessages, holderSecrets...)
+
 		derivedProof, dErr := generateSignatureProof(blsSignature, resolver, nonce, verData)
 		if dErr != nil {
 			return nil, fmt.Errorf("generate signature proof: %w", dErr)
@@ -219,6 +219,18 @@ func TestSuite_SelectiveDisclosure(t *testing.T) {
 		require.NotEmpty(t, proofs[0]["proofValue"])
 	})
 
+	t.Run("holder secret", func(t *testing.T) {
+		_, err := s.SelectiveDisclosureWithHolderSecret(docMap, revealDocMap, nonce, nil,
+			pubKeyResolver, withDocLoader)
+		require.EqualError(t, err, "holder secret is not defined")
+
+		// the credential is not bound to the holder secret
+		_, err = s.SelectiveDisclosureWithHolderSecret(docMap, revealDocMap, nonce, []byte("holder secret"),
+			pubKeyResolver, withDocLoader)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid BLS12-381 signature")
+	})
+
 	t.Run("several proofs including BBS+ signature", func(t *testing.T) {
 		// TODO re-enable (#2562).
 		t.Skip()
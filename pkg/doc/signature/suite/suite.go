@@ -28,6 +28,11 @@ type verifier interface {
 	Verify(pubKeyValue *sigverifier.PublicKey, doc, signature []byte) error
 }
 
+type batchVerifier interface {
+	// VerifyBatch will verify several signatures at once.
+	VerifyBatch(entries []*sigverifier.BatchEntry) error
+}
+
 // Opt is the SignatureSuite option.
 type Opt func(opts *SignatureSuite)
 
@@ -70,6 +75,26 @@ func (s *SignatureSuite) Verify(pubKeyValue *sigverifier.PublicKey, doc, signatu
 	return s.Verifier.Verify(pubKeyValue, doc, signature)
 }
 
+// VerifyBatch will verify several signatures at once if the verifier supports batch verification,
+// otherwise the signatures are verified one by one.
+func (s *SignatureSuite) VerifyBatch(entries []*sigverifier.BatchEntry) error {
+	if s.Verifier == nil {
+		return ErrVerifierNotDefined
+	}
+
+	if bv, ok := s.Verifier.(batchVerifier); ok {
+		return bv.VerifyBatch(entries)
+	}
+
+	for _, e := range entries {
+		if err := s.Verifier.Verify(e.PublicKey, e.Message, e.Signature); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // Sign will sign input data.
 func (s *SignatureSuite) Sign(data []byte) ([]byte, error) {
 	if s.Signer == nil {
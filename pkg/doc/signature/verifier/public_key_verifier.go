@@ -87,6 +87,33 @@ func (pkv *PublicKeyVerifier) Verify(pubKey *PublicKey, msg, signature []byte) e
 	return errors.New("no matching verifier found")
 }
 
+// VerifyBatch verifies several signatures at once if the signature algorithm supports batch verification
+// (see BatchSignatureVerifier), otherwise the signatures are verified one by one.
+func (pkv *PublicKeyVerifier) VerifyBatch(entries []*BatchEntry) error {
+	batchVerifier, ok := pkv.singleVerifier.(BatchSignatureVerifier)
+	if !ok {
+		for _, e := range entries {
+			if err := pkv.Verify(e.PublicKey, e.Message, e.Signature); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	for _, e := range entries {
+		if pkv.exactType != "" && e.PublicKey.Type != pkv.exactType {
+			return fmt.Errorf("a type of public key is not '%s'", pkv.exactType)
+		}
+
+		if e.PublicKey.JWK != nil && !pkv.matchVerifier(pkv.singleVerifier, e.PublicKey.JWK) {
+			return errors.New("verifier does not match JSON Web Key")
+		}
+	}
+
+	return batchVerifier.VerifyBatch(entries)
+}
+
 func (pkv *PublicKeyVerifier) matchVerifier(verifier SignatureVerifier, jwk *jose.JWK) bool {
 	// "kty" is a mandatory field in JWK.
 	if verifier.KeyType() != jwk.Kty {
@@ -124,6 +151,19 @@ type SignatureVerifier interface {
 	Verify(pubKey *PublicKey, msg, signature []byte) error
 }
 
+// BatchEntry is a signature of the message to be verified along with other signatures.
+type BatchEntry struct {
+	PublicKey *PublicKey
+	Message   []byte
+	Signature []byte
+}
+
+// BatchSignatureVerifier is a SignatureVerifier which verifies several signatures at once faster than one by one
+// (e.g. BBS+ signatures).
+type BatchSignatureVerifier interface {
+	VerifyBatch(entries []*BatchEntry) error
+}
+
 type baseSignatureVerifier struct {
 	keyType   string
 	curve     string
@@ -160,6 +200,20 @@ func NewEd25519SignatureVerifier() *Ed25519SignatureVerifier {
 
 // Verify verifies the signature.
 func (sv Ed25519SignatureVerifier) Verify(pubKey *PublicKey, msg, signature []byte) error {
+	value, err := ed25519PublicKey(pubKey)
+	if err != nil {
+		return err
+	}
+
+	verified := ed25519.Verify(value, msg, signature)
+	if !verified {
+		return errors.New("ed25519: invalid signature")
+	}
+
+	return nil
+}
+
+func ed25519PublicKey(pubKey *PublicKey) (ed25519.PublicKey, error) {
 	value := pubKey.Value
 
 	if pubKey.JWK != nil {
@@ -167,20 +221,15 @@ func (sv Ed25519SignatureVerifier) Verify(pubKey *PublicKey, msg, signature []by
 		value, ok = pubKey.JWK.Public().Key.(ed25519.PublicKey)
 
 		if !ok {
-			return fmt.Errorf("public key not ed25519.VerificationMethod")
+			return nil, fmt.Errorf("public key not ed25519.VerificationMethod")
 		}
 	}
 	// ed25519 panics if key size is wrong
 	if len(value) != ed25519.PublicKeySize {
-		return errors.New("ed25519: invalid key")
+		return nil, errors.New("ed25519: invalid key")
 	}
 
-	verified := ed25519.Verify(value, msg, signature)
-	if !verified {
-		return errors.New("ed25519: invalid signature")
-	}
-
-	return nil
+	return value, nil
 }
 
 // RSAPS256SignatureVerifier verifies a Ed25519 signature taking RSA public key bytes as input.
@@ -377,19 +426,46 @@ func NewBBSG2SignatureVerifier() *BBSG2SignatureVerifier {
 	return &BBSG2SignatureVerifier{}
 }
 
+// NewBBSG2HolderBoundSignatureVerifier creates a new BBSG2SignatureVerifier for a signature which is bound to
+// the holder secrets. The secrets are signed as hidden messages following the document statements.
+func NewBBSG2HolderBoundSignatureVerifier(holderSecrets ...[]byte) *BBSG2SignatureVerifier {
+	return &BBSG2SignatureVerifier{
+		holderSecrets: holderSecrets,
+	}
+}
+
 // BBSG2SignatureVerifier is a signature verifier that verifies a BBS+ Signature
 // taking Bls12381G2Key2020 public key bytes as input.
 // The reference implementation https://github.com/mattrglobal/bls12381-key-pair supports public key bytes only,
 // JWK is not supported.
 type BBSG2SignatureVerifier struct {
 	baseSignatureVerifier
+
+	holderSecrets [][]byte
 }
 
 // Verify verifies the signature.
 func (v *BBSG2SignatureVerifier) Verify(pubKeyValue *PublicKey, doc, signature []byte) error {
 	bbs := bbs12381g2pub.New()
 
-	return bbs.Verify(splitMessageIntoLines(string(doc), false), signature, pubKeyValue.Value)
+	messages := append(SplitMessageIntoLines(string(doc), false), v.holderSecrets...)
+
+	return bbs.Verify(messages, signature, pubKeyValue.Value)
+}
+
+// VerifyBatch verifies several signatures at once.
+func (v *BBSG2SignatureVerifier) VerifyBatch(entries []*BatchEntry) error {
+	batch := make([]*bbs12381g2pub.SignatureBatchEntry, len(entries))
+
+	for i, e := range entries {
+		batch[i] = &bbs12381g2pub.SignatureBatchEntry{
+			Messages:  append(SplitMessageIntoLines(string(e.Message), false), v.holderSecrets...),
+			Signature: e.Signature,
+			PublicKey: e.PublicKey.Value,
+		}
+	}
+
+	return bbs12381g2pub.New().BatchVerify(batch)
 }
 
 // NewBBSG2SignatureProofVerifier creates a new BBSG2SignatureProofVerifier.
@@ -413,11 +489,29 @@ type BBSG2SignatureProofVerifier struct {
 func (v *BBSG2SignatureProofVerifier) Verify(pubKeyValue *PublicKey, doc, signature []byte) error {
 	bbs := bbs12381g2pub.New()
 
-	return bbs.VerifyProof(splitMessageIntoLines(string(doc), true),
+	return bbs.VerifyProof(SplitMessageIntoLines(string(doc), true),
 		signature, v.nonce, pubKeyValue.Value)
 }
 
-func splitMessageIntoLines(msg string, transformBlankNodes bool) [][]byte {
+// VerifyBatch verifies several signature proofs at once.
+func (v *BBSG2SignatureProofVerifier) VerifyBatch(entries []*BatchEntry) error {
+	batch := make([]*bbs12381g2pub.ProofBatchEntry, len(entries))
+
+	for i, e := range entries {
+		batch[i] = &bbs12381g2pub.ProofBatchEntry{
+			Messages:  SplitMessageIntoLines(string(e.Message), true),
+			Proof:     e.Signature,
+			Nonce:     v.nonce,
+			PublicKey: e.PublicKey.Value,
+		}
+	}
+
+	return bbs12381g2pub.New().BatchVerifyProof(batch)
+}
+
+// SplitMessageIntoLines splits the canonical document into the statements signed by BBS+ signatures, blank node
+// identifiers are restored from the urn:bnid form if transformBlankNodes is set.
+func SplitMessageIntoLines(msg string, transformBlankNodes bool) [][]byte {
 	rows := strings.Split(msg, "\n")
 
 	msgs := make([][]byte, 0, len(rows))
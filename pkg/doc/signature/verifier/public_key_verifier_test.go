@@ -184,6 +184,32 @@ func TestNewEd25519SignatureVerifier(t *testing.T) {
 	err = v.Verify(pubKey, msg, []byte("invalid signature"))
 	require.Error(t, err)
 	require.EqualError(t, err, "ed25519: invalid signature")
+
+	t.Run("verify batch", func(t *testing.T) {
+		otherMsg := []byte("other message")
+		otherMsgSig, err := signer.Sign(otherMsg)
+		require.NoError(t, err)
+
+		entry := &BatchEntry{PublicKey: pubKey, Message: msg, Signature: msgSig}
+
+		pkVerifier := NewPublicKeyVerifier(v)
+		require.NoError(t, pkVerifier.VerifyBatch([]*BatchEntry{
+			entry,
+			{PublicKey: pubKey, Message: otherMsg, Signature: otherMsgSig},
+		}))
+
+		err = pkVerifier.VerifyBatch([]*BatchEntry{
+			entry,
+			{PublicKey: pubKey, Message: otherMsg, Signature: msgSig},
+		})
+		require.EqualError(t, err, "ed25519: invalid signature")
+
+		err = pkVerifier.VerifyBatch([]*BatchEntry{
+			entry,
+			{PublicKey: &PublicKey{Type: kmsapi.ED25519, Value: []byte("invalid-key")}, Message: msg, Signature: msgSig},
+		})
+		require.EqualError(t, err, "ed25519: invalid key")
+	})
 }
 
 func TestNewRSAPS256SignatureVerifier(t *testing.T) {
@@ -455,6 +481,32 @@ _:c14n0 <https://w3id.org/security#verificationMethod> <did:key:zUC724vuGvHpnCGF
 	}, []byte(msg), sigBytes)
 
 	require.NoError(t, err)
+
+	t.Run("batch verification", func(t *testing.T) {
+		pkVerifier := NewPublicKeyVerifier(verifier, WithExactPublicKeyType("Bls12381G2Key2020"))
+
+		entry := &BatchEntry{
+			PublicKey: &PublicKey{Type: "Bls12381G2Key2020", Value: pubKeyBytes},
+			Message:   []byte(msg),
+			Signature: sigBytes,
+		}
+
+		require.NoError(t, pkVerifier.VerifyBatch([]*BatchEntry{entry, entry}))
+
+		err = pkVerifier.VerifyBatch([]*BatchEntry{entry, {
+			PublicKey: entry.PublicKey,
+			Message:   []byte("invalid message"),
+			Signature: sigBytes,
+		}})
+		require.EqualError(t, err, "signature 1: invalid BLS12-381 signature")
+
+		err = pkVerifier.VerifyBatch([]*BatchEntry{entry, {
+			PublicKey: &PublicKey{Type: "Ed25519VerificationKey2018", Value: pubKeyBytes},
+			Message:   []byte(msg),
+			Signature: sigBytes,
+		}})
+		require.EqualError(t, err, "a type of public key is not 'Bls12381G2Key2020'")
+	})
 }
 
 //nolint:lll,goconst
@@ -496,6 +548,19 @@ _:c14n0 <https://w3id.org/security#verificationMethod> <did:key:zUC724vuGvHpnCGF
 	}, []byte(msg), sigBytes)
 
 	require.NoError(t, err)
+
+	t.Run("batch verification", func(t *testing.T) {
+		entry := &BatchEntry{
+			PublicKey: &PublicKey{Type: "Bls12381G2Key2020", Value: pubKeyBytes},
+			Message:   []byte(msg),
+			Signature: sigBytes,
+		}
+
+		require.NoError(t, verifier.VerifyBatch([]*BatchEntry{entry, entry}))
+
+		err = NewBBSG2SignatureProofVerifier([]byte("other nonce")).VerifyBatch([]*BatchEntry{entry, entry})
+		require.EqualError(t, err, "signature proof 0: bad signature")
+	})
 }
 
 //nolint:lll,goconst
@@ -75,43 +75,93 @@ func (dv *DocumentVerifier) Verify(jsonLdDoc []byte, opts ...jsonld.ProcessorOpt
 		return fmt.Errorf("failed to unmarshal json ld document: %w", err)
 	}
 
-	return dv.verifyObject(jsonLdObject, opts...)
+	return dv.verifyObjects([]map[string]interface{}{jsonLdObject}, opts...)
 }
 
-// verifyObject will verify document proofs for JSON LD object.
-func (dv *DocumentVerifier) verifyObject(jsonLdObject map[string]interface{}, opts ...jsonld.ProcessorOpts) error {
-	proofs, err := proof.GetProofs(jsonLdObject)
-	if err != nil {
-		return err
-	}
+// VerifyBatch will verify proofs of several documents. The proofs of signature suites supporting batch
+// verification are verified at once per suite across all the documents.
+func (dv *DocumentVerifier) VerifyBatch(jsonLdDocs [][]byte, opts ...jsonld.ProcessorOpts) error {
+	jsonLdObjects := make([]map[string]interface{}, len(jsonLdDocs))
 
-	for _, p := range proofs {
-		publicKeyID, err := p.PublicKeyID()
+	for i, jsonLdDoc := range jsonLdDocs {
+		err := json.Unmarshal(jsonLdDoc, &jsonLdObjects[i])
 		if err != nil {
-			return err
+			return fmt.Errorf("failed to unmarshal json ld document: %w", err)
 		}
+	}
+
+	return dv.verifyObjects(jsonLdObjects, opts...)
+}
+
+// batchSignatureSuite is a SignatureSuite which can verify several signatures at once.
+type batchSignatureSuite interface {
+	SignatureSuite
+
+	// VerifyBatch will verify several signatures against public keys
+	VerifyBatch(entries []*BatchEntry) error
+}
+
+// proofsBatch is a group of document proofs of the same signature suite.
+type proofsBatch struct {
+	suite   batchSignatureSuite
+	entries []*BatchEntry
+}
+
+// documentProof is a proof along with the JSON LD object it belongs to.
+type documentProof struct {
+	jsonLdObject map[string]interface{}
+	proof        *proof.Proof
+}
 
-		publicKey, err := dv.pkResolver.Resolve(publicKeyID)
+// verifyObjects will verify document proofs for JSON LD objects. If the documents contain several proofs,
+// the ones of signature suites supporting batch verification (e.g. Ed25519, BBS+) are verified at once per suite.
+func (dv *DocumentVerifier) verifyObjects(jsonLdObjects []map[string]interface{},
+	opts ...jsonld.ProcessorOpts) error {
+	var docProofs []*documentProof
+
+	for _, jsonLdObject := range jsonLdObjects {
+		proofs, err := proof.GetProofs(jsonLdObject)
 		if err != nil {
 			return err
 		}
 
-		suite, err := dv.getSignatureSuite(p.Type)
-		if err != nil {
-			return err
+		for _, p := range proofs {
+			docProofs = append(docProofs, &documentProof{jsonLdObject: jsonLdObject, proof: p})
 		}
+	}
+
+	batches := make(map[int]*proofsBatch)
+
+	var batchesOrder []int
 
-		message, err := proof.CreateVerifyData(suite, jsonLdObject, p, opts...)
+	for _, dp := range docProofs {
+		suiteInd, entry, err := dv.getVerifyData(dp.jsonLdObject, dp.proof, opts...)
 		if err != nil {
 			return err
 		}
 
-		signature, err := getProofVerifyValue(p)
-		if err != nil {
-			return err
+		suite := dv.signatureSuites[suiteInd]
+
+		bs, ok := suite.(batchSignatureSuite)
+		if !ok || len(docProofs) == 1 {
+			err = suite.Verify(entry.PublicKey, entry.Message, entry.Signature)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if _, ok = batches[suiteInd]; !ok {
+			batches[suiteInd] = &proofsBatch{suite: bs}
+			batchesOrder = append(batchesOrder, suiteInd)
 		}
 
-		err = suite.Verify(publicKey, message, signature)
+		batches[suiteInd].entries = append(batches[suiteInd].entries, entry)
+	}
+
+	for _, suiteInd := range batchesOrder {
+		err := batches[suiteInd].verify()
 		if err != nil {
 			return err
 		}
@@ -120,15 +170,58 @@ func (dv *DocumentVerifier) verifyObject(jsonLdObject map[string]interface{}, op
 	return nil
 }
 
-// getSignatureSuite returns signature suite based on signature type.
-func (dv *DocumentVerifier) getSignatureSuite(signatureType string) (SignatureSuite, error) {
-	for _, s := range dv.signatureSuites {
+// getVerifyData returns index of the signature suite of the proof along with the data to verify.
+func (dv *DocumentVerifier) getVerifyData(jsonLdObject map[string]interface{}, p *proof.Proof,
+	opts ...jsonld.ProcessorOpts) (int, *BatchEntry, error) {
+	publicKeyID, err := p.PublicKeyID()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	publicKey, err := dv.pkResolver.Resolve(publicKeyID)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	suiteInd, err := dv.getSignatureSuiteIndex(p.Type)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	message, err := proof.CreateVerifyData(dv.signatureSuites[suiteInd], jsonLdObject, p, opts...)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	signature, err := getProofVerifyValue(p)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return suiteInd, &BatchEntry{
+		PublicKey: publicKey,
+		Message:   message,
+		Signature: signature,
+	}, nil
+}
+
+func (b *proofsBatch) verify() error {
+	if len(b.entries) == 1 {
+		return b.suite.Verify(b.entries[0].PublicKey, b.entries[0].Message, b.entries[0].Signature)
+	}
+
+	return b.suite.VerifyBatch(b.entries)
+}
+
+// getSignatureSuiteIndex returns index of signature suite based on signature type.
+func (dv *DocumentVerifier) getSignatureSuiteIndex(signatureType string) (int, error) {
+	for i, s := range dv.signatureSuites {
 		if s.Accept(signatureType) {
-			return s, nil
+			return i, nil
 		}
 	}
 
-	return nil, fmt.Errorf("signature type %s not supported", signatureType)
+	return 0, fmt.Errorf("signature type %s not supported", signatureType)
 }
 
 func getProofVerifyValue(p *proof.Proof) ([]byte, error) {
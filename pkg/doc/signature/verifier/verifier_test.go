@@ -107,6 +107,77 @@ func TestVerify(t *testing.T) {
 	require.Nil(t, v)
 }
 
+func TestVerify_SeveralProofs(t *testing.T) {
+	okKeyResolver := &testKeyResolver{
+		publicKey: &PublicKey{
+			Type:  kms.ED25519,
+			Value: []byte("signature"),
+		},
+	}
+
+	var doc map[string]interface{}
+
+	err := json.Unmarshal([]byte(validDoc), &doc)
+	require.NoError(t, err)
+
+	doc["proof"] = []interface{}{doc["proof"], doc["proof"], doc["proof"]}
+
+	docWithProofs, err := json.Marshal(doc)
+	require.NoError(t, err)
+
+	t.Run("proofs are verified in a batch", func(t *testing.T) {
+		suite := &testBatchSignatureSuite{testSignatureSuite: testSignatureSuite{accept: true}}
+
+		v, err := New(okKeyResolver, suite)
+		require.NoError(t, err)
+
+		require.NoError(t, v.Verify(docWithProofs))
+		require.Equal(t, []int{3}, suite.batches)
+
+		// single proof is not batched
+		require.NoError(t, v.Verify([]byte(validDoc)))
+		require.Equal(t, []int{3}, suite.batches)
+	})
+
+	t.Run("proofs of several documents are verified in a batch", func(t *testing.T) {
+		suite := &testBatchSignatureSuite{testSignatureSuite: testSignatureSuite{accept: true}}
+
+		v, err := New(okKeyResolver, suite)
+		require.NoError(t, err)
+
+		require.NoError(t, v.VerifyBatch([][]byte{docWithProofs, []byte(validDoc)}))
+		require.Equal(t, []int{4}, suite.batches)
+
+		err = v.VerifyBatch([][]byte{[]byte(validDoc), []byte("not JSON")})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to unmarshal json ld document")
+	})
+
+	t.Run("batch verification error", func(t *testing.T) {
+		suite := &testBatchSignatureSuite{
+			testSignatureSuite: testSignatureSuite{accept: true},
+			verifyBatchError:   errors.New("verify batch error"),
+		}
+
+		v, err := New(okKeyResolver, suite)
+		require.NoError(t, err)
+
+		err = v.Verify(docWithProofs)
+		require.EqualError(t, err, "verify batch error")
+	})
+
+	t.Run("suite without batch verification", func(t *testing.T) {
+		v, err := New(okKeyResolver, &testSignatureSuite{
+			verifyError: errors.New("verify data error"),
+			accept:      true,
+		})
+		require.NoError(t, err)
+
+		err = v.Verify(docWithProofs)
+		require.EqualError(t, err, "verify data error")
+	})
+}
+
 func Test_getProofVerifyValue(t *testing.T) {
 	jwsSignature := base64.RawURLEncoding.EncodeToString([]byte("signature"))
 
@@ -200,3 +271,16 @@ func (s *testSignatureSuite) Accept(string) bool {
 func (s *testSignatureSuite) CompactProof() bool {
 	return s.compactProof
 }
+
+type testBatchSignatureSuite struct {
+	testSignatureSuite
+
+	verifyBatchError error
+	batches          []int
+}
+
+func (s *testBatchSignatureSuite) VerifyBatch(entries []*BatchEntry) error {
+	s.batches = append(s.batches, len(entries))
+
+	return s.verifyBatchError
+}
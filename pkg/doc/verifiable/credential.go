@@ -574,6 +574,7 @@ type credentialOpts struct {
 	disabledProofCheck    bool
 	strictValidation      bool
 	ldpSuites             []verifier.SignatureSuite
+	bbsHolderSecret       []byte
 
 	jsonldCredentialOpts
 }
@@ -692,6 +693,14 @@ func WithEmbeddedSignatureSuites(suites ...verifier.SignatureSuite) CredentialOp
 	}
 }
 
+// WithBBSHolderSecret defines the holder secret the BBS+ signature of VC is bound to. It is used to check
+// BbsBlsSignature2020 proof and to generate BBS+ selective disclosure of VC issued to the hidden holder secret.
+func WithBBSHolderSecret(holderSecret []byte) CredentialOpt {
+	return func(opts *credentialOpts) {
+		opts.bbsHolderSecret = holderSecret
+	}
+}
+
 // parseIssuer parses raw issuer.
 //
 // Issuer can be defined by:
@@ -1039,6 +1048,7 @@ func getEmbeddedProofCheckOpts(vcOpts *credentialOpts) *embeddedProofCheckOpts {
 		publicKeyFetcher:     vcOpts.publicKeyFetcher,
 		disabledProofCheck:   vcOpts.disabledProofCheck,
 		ldpSuites:            vcOpts.ldpSuites,
+		bbsHolderSecret:      vcOpts.bbsHolderSecret,
 		jsonldCredentialOpts: vcOpts.jsonldCredentialOpts,
 	}
 }
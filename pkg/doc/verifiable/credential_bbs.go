@@ -36,8 +36,16 @@ func (vc *Credential) GenerateBBSSelectiveDisclosure(revealDoc map[string]interf
 
 	keyResolver := &keyResolverAdapter{vcOpts.publicKeyFetcher}
 
-	vcWithSelectiveDisclosureDoc, err := suite.SelectiveDisclosure(vcDoc, revealDoc, nonce,
-		keyResolver, jsonldProcessorOpts...)
+	var vcWithSelectiveDisclosureDoc map[string]interface{}
+
+	if len(vcOpts.bbsHolderSecret) > 0 {
+		vcWithSelectiveDisclosureDoc, err = suite.SelectiveDisclosureWithHolderSecret(vcDoc, revealDoc, nonce,
+			vcOpts.bbsHolderSecret, keyResolver, jsonldProcessorOpts...)
+	} else {
+		vcWithSelectiveDisclosureDoc, err = suite.SelectiveDisclosure(vcDoc, revealDoc, nonce,
+			keyResolver, jsonldProcessorOpts...)
+	}
+
 	if err != nil {
 		return nil, fmt.Errorf("create VC selective disclosure: %w", err)
 	}
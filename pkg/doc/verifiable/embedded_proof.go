@@ -49,7 +49,8 @@ type embeddedProofCheckOpts struct {
 	publicKeyFetcher   PublicKeyFetcher
 	disabledProofCheck bool
 
-	ldpSuites []verifier.SignatureSuite
+	ldpSuites       []verifier.SignatureSuite
+	bbsHolderSecret []byte
 
 	jsonldCredentialOpts
 }
@@ -59,30 +60,106 @@ func checkEmbeddedProof(docBytes []byte, opts *embeddedProofCheckOpts) ([]byte,
 		return docBytes, nil
 	}
 
+	checkedDoc, ldpSuites, err := prepareEmbeddedProofCheck(docBytes, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if checkedDoc == nil {
+		// do not make a check if there is no proof defined as proof presence is not mandatory
+		return docBytes, nil
+	}
+
+	err = checkLinkedDataProof(checkedDoc, ldpSuites, opts.publicKeyFetcher, &opts.jsonldCredentialOpts)
+	if err != nil {
+		return nil, fmt.Errorf("check embedded proof: %w", err)
+	}
+
+	return docBytes, nil
+}
+
+// checkEmbeddedProofs checks embedded proofs of several documents (e.g. of a presentation and its credentials)
+// verifying the signatures of the same suite at once. The documents with BBS+ signature proofs are checked
+// separately as the nonce of the proof is bound to the signature suite.
+func checkEmbeddedProofs(docs [][]byte, opts *embeddedProofCheckOpts) error {
+	if opts.disabledProofCheck {
+		return nil
+	}
+
+	var (
+		checkedDocs [][]byte
+		ldpSuites   []verifier.SignatureSuite
+	)
+
+	for _, docBytes := range docs {
+		checkedDoc, docSuites, err := prepareEmbeddedProofCheck(docBytes, opts)
+		if err != nil {
+			return err
+		}
+
+		if checkedDoc == nil {
+			continue
+		}
+
+		if len(opts.ldpSuites) == 0 && hasSuite(docSuites, bbsBlsSignatureProof2020) {
+			err = checkLinkedDataProof(checkedDoc, docSuites, opts.publicKeyFetcher, &opts.jsonldCredentialOpts)
+			if err != nil {
+				return fmt.Errorf("check embedded proof: %w", err)
+			}
+
+			continue
+		}
+
+		checkedDocs = append(checkedDocs, checkedDoc)
+
+		if len(opts.ldpSuites) == 0 {
+			ldpSuites = append(ldpSuites, docSuites...)
+		}
+	}
+
+	if len(checkedDocs) == 0 {
+		return nil
+	}
+
+	if len(opts.ldpSuites) > 0 {
+		ldpSuites = opts.ldpSuites
+	}
+
+	err := checkLinkedDataProofs(checkedDocs, ldpSuites, opts.publicKeyFetcher, &opts.jsonldCredentialOpts)
+	if err != nil {
+		return fmt.Errorf("check embedded proof: %w", err)
+	}
+
+	return nil
+}
+
+// prepareEmbeddedProofCheck returns the document to check along with the signature suites of its proofs.
+// If the document has no proof, nil document is returned.
+func prepareEmbeddedProofCheck(docBytes []byte, opts *embeddedProofCheckOpts) ([]byte,
+	[]verifier.SignatureSuite, error) {
 	var jsonldDoc map[string]interface{}
 
 	if err := json.Unmarshal(docBytes, &jsonldDoc); err != nil {
-		return nil, fmt.Errorf("embedded proof is not JSON: %w", err)
+		return nil, nil, fmt.Errorf("embedded proof is not JSON: %w", err)
 	}
 
 	proofElement, ok := jsonldDoc["proof"]
 	if !ok || proofElement == nil {
-		// do not make a check if there is no proof defined as proof presence is not mandatory
-		return docBytes, nil
+		return nil, nil, nil
 	}
 
 	proofs, err := getProofs(proofElement)
 	if err != nil {
-		return nil, fmt.Errorf("check embedded proof: %w", err)
+		return nil, nil, fmt.Errorf("check embedded proof: %w", err)
 	}
 
 	ldpSuites, err := getSuites(proofs, opts)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	if opts.publicKeyFetcher == nil {
-		return nil, errors.New("public key fetcher is not defined")
+		return nil, nil, errors.New("public key fetcher is not defined")
 	}
 
 	checkedDoc := docBytes
@@ -93,12 +170,17 @@ func checkEmbeddedProof(docBytes []byte, opts *embeddedProofCheckOpts) ([]byte,
 		checkedDoc, _ = json.Marshal(jsonldDoc) //nolint:errcheck
 	}
 
-	err = checkLinkedDataProof(checkedDoc, ldpSuites, opts.publicKeyFetcher, &opts.jsonldCredentialOpts)
-	if err != nil {
-		return nil, fmt.Errorf("check embedded proof: %w", err)
+	return checkedDoc, ldpSuites, nil
+}
+
+func hasSuite(suites []verifier.SignatureSuite, signatureType string) bool {
+	for _, s := range suites {
+		if s.Accept(signatureType) {
+			return true
+		}
 	}
 
-	return docBytes, nil
+	return false
 }
 
 func getSuites(proofs []map[string]interface{}, opts *embeddedProofCheckOpts) ([]verifier.SignatureSuite, error) {
@@ -123,7 +205,7 @@ func getSuites(proofs []map[string]interface{}, opts *embeddedProofCheckOpts) ([
 					suite.WithVerifier(ecdsasecp256k1signature2019.NewPublicKeyVerifier())))
 			case bbsBlsSignature2020:
 				ldpSuites = append(ldpSuites, bbsblssignature2020.New(
-					suite.WithVerifier(bbsblssignature2020.NewG2PublicKeyVerifier())))
+					suite.WithVerifier(getBBSG2PublicKeyVerifier(opts.bbsHolderSecret))))
 			case bbsBlsSignatureProof2020:
 				nonce, err := getNonce(proofs[i])
 				if err != nil {
@@ -139,6 +221,14 @@ func getSuites(proofs []map[string]interface{}, opts *embeddedProofCheckOpts) ([
 	return ldpSuites, nil
 }
 
+func getBBSG2PublicKeyVerifier(holderSecret []byte) *verifier.PublicKeyVerifier {
+	if len(holderSecret) > 0 {
+		return bbsblssignature2020.NewG2HolderBoundPublicKeyVerifier(holderSecret)
+	}
+
+	return bbsblssignature2020.NewG2PublicKeyVerifier()
+}
+
 func getNonce(proof map[string]interface{}) ([]byte, error) {
 	if nonce, ok := proof["nonce"]; ok {
 		n, err := base64.StdEncoding.DecodeString(nonce.(string))
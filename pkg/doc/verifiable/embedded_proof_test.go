@@ -201,6 +201,38 @@ func Test_checkEmbeddedProof(t *testing.T) {
 	})
 }
 
+func Test_checkEmbeddedProofs(t *testing.T) {
+	t.Run("Happy path - proofs of several documents", func(t *testing.T) {
+		vc1, publicKeyFetcher := createVCWithLinkedDataProof()
+		vc2, _ := createVCWithLinkedDataProof()
+
+		err := checkEmbeddedProofs([][]byte{vc1.byteJSON(t), []byte(`{}`)}, &embeddedProofCheckOpts{
+			publicKeyFetcher:     publicKeyFetcher,
+			jsonldCredentialOpts: jsonldCredentialOpts{jsonldDocumentLoader: createTestJSONLDDocumentLoader()},
+		})
+		require.NoError(t, err)
+
+		// vc2 is signed with other key
+		err = checkEmbeddedProofs([][]byte{vc1.byteJSON(t), vc2.byteJSON(t)}, &embeddedProofCheckOpts{
+			publicKeyFetcher:     publicKeyFetcher,
+			jsonldCredentialOpts: jsonldCredentialOpts{jsonldDocumentLoader: createTestJSONLDDocumentLoader()},
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "check embedded proof")
+	})
+
+	t.Run("Does not check the embedded proofs if disabledProofCheck", func(t *testing.T) {
+		require.NoError(t, checkEmbeddedProofs([][]byte{[]byte("not JSON")},
+			&embeddedProofCheckOpts{disabledProofCheck: true}))
+	})
+
+	t.Run("error on checking non-JSON embedded proof", func(t *testing.T) {
+		err := checkEmbeddedProofs([][]byte{[]byte("not JSON")}, &embeddedProofCheckOpts{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "embedded proof is not JSON")
+	})
+}
+
 func Test_getSuites(t *testing.T) {
 	createProofOfTypeFunc := func(suiteType string) map[string]interface{} {
 		return map[string]interface{}{
@@ -83,6 +83,21 @@ func checkLinkedDataProof(jsonldBytes []byte, suites []verifier.SignatureSuite,
 	return nil
 }
 
+func checkLinkedDataProofs(jsonldDocs [][]byte, suites []verifier.SignatureSuite,
+	pubKeyFetcher PublicKeyFetcher, jsonldOpts *jsonldCredentialOpts) error {
+	documentVerifier, err := verifier.New(&keyResolverAdapter{pubKeyFetcher}, suites...)
+	if err != nil {
+		return fmt.Errorf("create new signature verifier: %w", err)
+	}
+
+	err = documentVerifier.VerifyBatch(jsonldDocs, mapJSONLDProcessorOpts(jsonldOpts)...)
+	if err != nil {
+		return fmt.Errorf("check linked data proof: %w", err)
+	}
+
+	return nil
+}
+
 func mapJSONLDProcessorOpts(jsonldOpts *jsonldCredentialOpts) []jsonld.ProcessorOpts {
 	var processorOpts []jsonld.ProcessorOpts
 
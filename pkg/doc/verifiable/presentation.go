@@ -335,6 +335,8 @@ type presentationOpts struct {
 	requireVC          bool
 	requireProof       bool
 
+	credentialsProofCheck bool
+
 	jsonldCredentialOpts
 }
 
@@ -363,6 +365,14 @@ func WithPresDisabledProofCheck() PresentationOpt {
 	}
 }
 
+// WithPresEmbeddedCredentialsProofCheck option for checking the linked data proofs of the credentials embedded
+// into VP as JSON objects along with the proof of VP. The signatures of the same suite are verified at once.
+func WithPresEmbeddedCredentialsProofCheck() PresentationOpt {
+	return func(opts *presentationOpts) {
+		opts.credentialsProofCheck = true
+	}
+}
+
 // WithPresStrictValidation enabled strict JSON-LD validation of VP.
 // In case of JSON-LD validation, the comparison of JSON-LD VP document after compaction with original VP one is made.
 // In case of mismatch a validation exception is raised.
@@ -582,7 +592,7 @@ func decodeRawPresentation(vpData []byte, vpOpts *presentationOpts) ([]byte, *ra
 			return nil, nil, fmt.Errorf("decoding of Verifiable Presentation from unsecured JWT: %w", err)
 		}
 
-		if _, err := checkEmbeddedProof(rawBytes, embeddedProofCheckOpts); err != nil {
+		if err := checkPresentationProof(rawBytes, rawPres, vpOpts, embeddedProofCheckOpts); err != nil {
 			return nil, nil, err
 		}
 
@@ -594,7 +604,7 @@ func decodeRawPresentation(vpData []byte, vpOpts *presentationOpts) ([]byte, *ra
 		return nil, nil, err
 	}
 
-	_, err = checkEmbeddedProof(vpBytes, embeddedProofCheckOpts)
+	err = checkPresentationProof(vpBytes, vpRaw, vpOpts, embeddedProofCheckOpts)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -607,6 +617,40 @@ func decodeRawPresentation(vpData []byte, vpOpts *presentationOpts) ([]byte, *ra
 	return vpBytes, vpRaw, err
 }
 
+// checkPresentationProof checks embedded proof of VP and, if requested, of the credentials embedded into VP
+// as JSON objects.
+func checkPresentationProof(vpBytes []byte, vpRaw *rawPresentation, vpOpts *presentationOpts,
+	opts *embeddedProofCheckOpts) error {
+	if !vpOpts.credentialsProofCheck {
+		_, err := checkEmbeddedProof(vpBytes, opts)
+
+		return err
+	}
+
+	docs := [][]byte{vpBytes}
+
+	rawCreds, ok := vpRaw.Credential.([]interface{})
+	if !ok {
+		rawCreds = []interface{}{vpRaw.Credential}
+	}
+
+	for _, rawCred := range rawCreds {
+		if _, ok := rawCred.(map[string]interface{}); !ok {
+			// credentials in a string format (e.g. JWT) are checked when decoding them
+			continue
+		}
+
+		credBytes, err := json.Marshal(rawCred)
+		if err != nil {
+			return fmt.Errorf("marshal credential of presentation: %w", err)
+		}
+
+		docs = append(docs, credBytes)
+	}
+
+	return checkEmbeddedProofs(docs, opts)
+}
+
 func decodeVPFromJSON(vpData []byte) ([]byte, *rawPresentation, error) {
 	// unmarshal VP from JSON
 	raw := new(rawPresentation)
@@ -14,6 +14,7 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/jsonld"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/suite"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/suite/ed25519signature2018"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/verifier"
 	"github.com/hyperledger/aries-framework-go/pkg/kms"
 )
 
@@ -56,6 +57,101 @@ func TestParsePresentationFromLinkedDataProof(t *testing.T) {
 	require.Nil(t, vcWithLdp)
 }
 
+func TestParsePresentationFromLinkedDataProof_EmbeddedCredentials(t *testing.T) {
+	r := require.New(t)
+
+	loaderOpt := jsonld.WithDocumentLoader(createTestJSONLDDocumentLoader())
+	publicKeys := make(map[string][]byte)
+
+	newSignedLDPContext := func(did string) *LinkedDataProofContext {
+		signer, err := newCryptoSigner(kms.ED25519Type)
+		r.NoError(err)
+
+		publicKeys[did] = signer.PublicKeyBytes()
+
+		return &LinkedDataProofContext{
+			SignatureType:           "Ed25519Signature2018",
+			SignatureRepresentation: SignatureJWS,
+			Suite:                   ed25519signature2018.New(suite.WithSigner(signer)),
+			VerificationMethod:      did + "#key1",
+		}
+	}
+
+	newSignedCredential := func(did string) *Credential {
+		vc, err := parseTestCredential([]byte(validCredential), WithDisabledProofCheck())
+		r.NoError(err)
+
+		r.NoError(vc.AddLinkedDataProof(newSignedLDPContext(did), loaderOpt))
+
+		return vc
+	}
+
+	newSignedPresentation := func(vcs ...*Credential) []byte {
+		vp, err := NewPresentation(WithCredentials(vcs...))
+		r.NoError(err)
+
+		r.NoError(vp.AddLinkedDataProof(newSignedLDPContext("did:example:holder"), loaderOpt))
+
+		vpBytes, err := json.Marshal(vp)
+		r.NoError(err)
+
+		return vpBytes
+	}
+
+	publicKeyFetcher := func(issuerID, _ string) (*verifier.PublicKey, error) {
+		return &verifier.PublicKey{Type: kms.ED25519, Value: publicKeys[issuerID]}, nil
+	}
+
+	t.Run("proofs of presentation and credentials are verified in a batch", func(t *testing.T) {
+		vpBytes := newSignedPresentation(newSignedCredential("did:example:issuer1"),
+			newSignedCredential("did:example:issuer2"))
+
+		v := &batchCountingVerifier{PublicKeyVerifier: ed25519signature2018.NewPublicKeyVerifier()}
+
+		_, err := newTestPresentation(vpBytes,
+			WithPresEmbeddedSignatureSuites(ed25519signature2018.New(suite.WithVerifier(v))),
+			WithPresPublicKeyFetcher(publicKeyFetcher),
+			WithPresEmbeddedCredentialsProofCheck())
+		r.NoError(err)
+		r.Equal([]int{3}, v.batches)
+
+		_, err = newTestPresentation(vpBytes,
+			WithPresPublicKeyFetcher(publicKeyFetcher),
+			WithPresEmbeddedCredentialsProofCheck())
+		r.NoError(err)
+	})
+
+	t.Run("invalid proof of credential", func(t *testing.T) {
+		tamperedVC := newSignedCredential("did:example:issuer2")
+		tamperedVC.ID = "http://example.edu/credentials/other"
+
+		vpBytes := newSignedPresentation(newSignedCredential("did:example:issuer1"), tamperedVC)
+
+		_, err := newTestPresentation(vpBytes,
+			WithPresPublicKeyFetcher(publicKeyFetcher),
+			WithPresEmbeddedCredentialsProofCheck())
+		r.Error(err)
+		r.Contains(err.Error(), "check embedded proof")
+
+		// proofs of credentials are not checked by default
+		_, err = newTestPresentation(vpBytes, WithPresPublicKeyFetcher(publicKeyFetcher))
+		r.NoError(err)
+	})
+}
+
+// batchCountingVerifier records sizes of the verified signature batches.
+type batchCountingVerifier struct {
+	*verifier.PublicKeyVerifier
+
+	batches []int
+}
+
+func (v *batchCountingVerifier) VerifyBatch(entries []*verifier.BatchEntry) error {
+	v.batches = append(v.batches, len(entries))
+
+	return v.PublicKeyVerifier.VerifyBatch(entries)
+}
+
 func TestPresentation_AddLinkedDataProof(t *testing.T) {
 	r := require.New(t)
 
@@ -18,16 +18,23 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/packer"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/packer/anoncrypt"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/packer/authcrypt"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/packer/jws"
 	legacy "github.com/hyperledger/aries-framework-go/pkg/didcomm/packer/legacy/authcrypt"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/actionmenu"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didexchange"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didrotate"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/discoverfeatures"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/introduce"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/issuecredential"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/keyrotation"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/mediator"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/messagepickup"
 	mdissuecredential "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/middleware/issuecredential"
 	mdpresentproof "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/middleware/presentproof"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/notification"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/outofband"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/presentproof"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/trustping"
 	didcommtransport "github.com/hyperledger/aries-framework-go/pkg/didcomm/transport"
 	arieshttp "github.com/hyperledger/aries-framework-go/pkg/didcomm/transport/http"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/jose"
@@ -66,9 +73,13 @@ func defFrameworkOpts(frameworkOpts *Aries) error {
 	// - DIDExchange depends on Route
 	// - OutOfBand depends on DIDExchange
 	// - Introduce depends on OutOfBand
+	// - KeyRotation depends on Route
+	// - DiscoverFeatures discloses the services above, so it's the last one
 	frameworkOpts.protocolSvcCreators = append(frameworkOpts.protocolSvcCreators,
-		newMessagePickupSvc(), newRouteSvc(), newExchangeSvc(), newOutOfBandSvc(),
-		newIntroduceSvc(), newIssueCredentialSvc(), newPresentProofSvc())
+		newMessagePickupSvc(), newRouteSvc(frameworkOpts.mediatorPolicy), newExchangeSvc(frameworkOpts),
+		newOutOfBandSvc(), newIntroduceSvc(frameworkOpts), newIssueCredentialSvc(frameworkOpts),
+		newPresentProofSvc(frameworkOpts), newKeyRotationSvc(), newDIDRotateSvc(),
+		newTrustPingSvc(), newActionMenuSvc(), newNotificationSvc(), newDiscoverFeaturesSvc())
 
 	if frameworkOpts.secretLock == nil && frameworkOpts.kmsCreator == nil {
 		err = createDefSecretLock(frameworkOpts)
@@ -86,35 +97,78 @@ func defFrameworkOpts(frameworkOpts *Aries) error {
 	return setAdditionalDefaultOpts(frameworkOpts)
 }
 
-func newExchangeSvc() api.ProtocolSvcCreator {
+func newExchangeSvc(frameworkOpts *Aries) api.ProtocolSvcCreator {
+	var opts []didexchange.ServiceOption
+
+	if frameworkOpts.protocolExpiryEnabled {
+		opts = append(opts, didexchange.WithExpiry(frameworkOpts.protocolExpiryOpts...))
+	}
+
 	return func(prv api.Provider) (dispatcher.ProtocolService, error) {
-		return didexchange.New(prv)
+		return didexchange.New(prv, opts...)
 	}
 }
 
-func newIntroduceSvc() api.ProtocolSvcCreator {
+func newIntroduceSvc(frameworkOpts *Aries) api.ProtocolSvcCreator {
+	var opts []introduce.ServiceOption
+
+	if frameworkOpts.protocolExpiryEnabled {
+		opts = append(opts, introduce.WithExpiry(frameworkOpts.protocolExpiryOpts...))
+	}
+
 	return func(prv api.Provider) (dispatcher.ProtocolService, error) {
-		return introduce.New(prv)
+		return introduce.New(prv, opts...)
 	}
 }
 
-func newIssueCredentialSvc() api.ProtocolSvcCreator {
+func newIssueCredentialSvc(frameworkOpts *Aries) api.ProtocolSvcCreator {
+	var opts []issuecredential.ServiceOption
+
+	if frameworkOpts.protocolExpiryEnabled {
+		opts = append(opts, issuecredential.WithExpiry(frameworkOpts.protocolExpiryOpts...))
+	}
+
 	return func(prv api.Provider) (dispatcher.ProtocolService, error) {
-		service, err := issuecredential.New(prv)
+		svcOpts := append([]issuecredential.ServiceOption{}, opts...)
+
+		if frameworkOpts.ldProofIssuerVM != "" && frameworkOpts.ldProofAutoAccept {
+			autoAccept, err := mdissuecredential.AutoAcceptLDProofRequests(prv, frameworkOpts.ldProofIssuerVM,
+				frameworkOpts.ldProofIssuerOpts...)
+			if err != nil {
+				return nil, fmt.Errorf("ld proof credential auto accept: %w", err)
+			}
+
+			svcOpts = append(svcOpts, issuecredential.WithAutoAccept(autoAccept))
+		}
+
+		service, err := issuecredential.New(prv, svcOpts...)
 		if err != nil {
 			return nil, err
 		}
 
 		// sets default middleware to the service
-		service.Use(mdissuecredential.SaveCredentials(prv))
+		middlewares := []issuecredential.Middleware{mdissuecredential.SaveCredentials(prv)}
+
+		if frameworkOpts.ldProofIssuerVM != "" {
+			middlewares = append(middlewares, mdissuecredential.IssueLDProofCredentials(prv,
+				frameworkOpts.ldProofIssuerVM, frameworkOpts.ldProofIssuerOpts...))
+		}
+
+		service.Use(middlewares...)
 
 		return service, nil
 	}
 }
 
-func newPresentProofSvc() api.ProtocolSvcCreator {
+func newPresentProofSvc(frameworkOpts *Aries) api.ProtocolSvcCreator {
+	var opts []presentproof.ServiceOption
+
+	if frameworkOpts.protocolExpiryEnabled {
+		opts = append(opts, presentproof.WithExpiry(frameworkOpts.protocolExpiryOpts...))
+	}
+
 	return func(prv api.Provider) (dispatcher.ProtocolService, error) {
-		service, err := presentproof.New(prv)
+		service, err := presentproof.New(prv, opts...)
 		if err != nil {
 			return nil, err
 		}
@@ -131,8 +185,12 @@ func newPresentProofSvc() api.ProtocolSvcCreator {
 	}
 }
 
-func newRouteSvc() api.ProtocolSvcCreator {
+func newRouteSvc(policy mediator.Policy) api.ProtocolSvcCreator {
 	return func(prv api.Provider) (dispatcher.ProtocolService, error) {
+		if policy != nil {
+			return mediator.New(prv, mediator.WithPolicy(policy))
+		}
+
 		return mediator.New(prv)
 	}
 }
@@ -154,6 +212,47 @@ func newOutOfBandSvc() api.ProtocolSvcCreator {
 	}
 }
 
+func newTrustPingSvc() api.ProtocolSvcCreator {
+	return func(prv api.Provider) (dispatcher.ProtocolService, error) {
+		return trustping.New(prv)
+	}
+}
+
+func newActionMenuSvc() api.ProtocolSvcCreator {
+	return func(prv api.Provider) (dispatcher.ProtocolService, error) {
+		return actionmenu.New(prv)
+	}
+}
+
+func newNotificationSvc() api.ProtocolSvcCreator {
+	return func(_ api.Provider) (dispatcher.ProtocolService, error) {
+		return notification.New(), nil
+	}
+}
+
+func newDiscoverFeaturesSvc() api.ProtocolSvcCreator {
+	return func(prv api.Provider) (dispatcher.ProtocolService, error) {
+		dp, ok := prv.(discoverfeatures.Provider)
+		if !ok {
+			return nil, errors.New("failed to cast discover features provider")
+		}
+
+		return discoverfeatures.New(dp)
+	}
+}
+
+func newKeyRotationSvc() api.ProtocolSvcCreator {
+	return func(prv api.Provider) (dispatcher.ProtocolService, error) {
+		return keyrotation.New(prv)
+	}
+}
+
+func newDIDRotateSvc() api.ProtocolSvcCreator {
+	return func(prv api.Provider) (dispatcher.ProtocolService, error) {
+		return didrotate.New(prv)
+	}
+}
+
 func setAdditionalDefaultOpts(frameworkOpts *Aries) error {
 	if frameworkOpts.kmsCreator == nil {
 		frameworkOpts.kmsCreator = func(provider kms.Provider) (kms.KeyManager, error) {
@@ -186,6 +285,17 @@ func setAdditionalDefaultOpts(frameworkOpts *Aries) error {
 			func(provider packer.Provider) (packer.Packer, error) {
 				return anoncrypt.New(provider, jose.A256GCM)
 			},
+			func(provider packer.Provider) (packer.Packer, error) {
+				return authcrypt.New(provider, jose.A256GCM,
+					authcrypt.WithMediaType(transport.MediaTypeV2EncryptedEnvelope))
+			},
+			func(provider packer.Provider) (packer.Packer, error) {
+				return anoncrypt.New(provider, jose.A256GCM,
+					anoncrypt.WithMediaType(transport.MediaTypeV2EncryptedEnvelope))
+			},
+			func(provider packer.Provider) (packer.Packer, error) {
+				return jws.New(provider, frameworkOpts.vdrRegistry)
+			},
 		}
 	}
 
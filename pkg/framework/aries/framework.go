@@ -8,11 +8,13 @@ package aries
 
 import (
 	"fmt"
+	"io"
 	"strings"
 
 	"github.com/google/uuid"
 
 	"github.com/hyperledger/aries-framework-go/pkg/crypto"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/expiry"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	commontransport "github.com/hyperledger/aries-framework-go/pkg/didcomm/common/transport"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/dispatcher"
@@ -20,7 +22,11 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/packager"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/packer"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/mediator"
+	mdissuecredential "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/middleware/issuecredential"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/replay"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/transport"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/transport/ws"
 	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api"
 	vdrapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
 	"github.com/hyperledger/aries-framework-go/pkg/framework/context"
@@ -63,6 +69,21 @@ type Aries struct {
 	vdr                        []vdrapi.VDR
 	verifiableStore            verifiable.Store
 	transportReturnRoute       string
+	mediatorPolicy             mediator.Policy
+	outboundQueue              *dispatcher.OutboundQueue
+	outboundQueueOpts          []dispatcher.QueueOption
+	outboundQueueEnabled       bool
+	forwardPacking             dispatcher.ForwardPacking
+	replayCache                replay.Cache
+	replayOpts                 []replay.Option
+	replayEnabled              bool
+	wsPool                     *ws.Pool
+	wsPoolOpts                 []ws.PoolOption
+	protocolExpiryOpts         []expiry.Option
+	protocolExpiryEnabled      bool
+	ldProofIssuerVM            string
+	ldProofIssuerOpts          []mdissuecredential.IssuerOpt
+	ldProofAutoAccept          bool
 	id                         string
 }
 
@@ -128,6 +149,11 @@ func initializeServices(frameworkOpts *Aries) (*Aries, error) {
 		return nil, err
 	}
 
+	// Create the cache of the seen inbound messages
+	if err := createReplayCache(frameworkOpts); err != nil {
+		return nil, err
+	}
+
 	// Load services
 	if err := loadServices(frameworkOpts); err != nil {
 		return nil, err
@@ -268,6 +294,98 @@ func WithVerifiableStore(store verifiable.Store) Option {
 	}
 }
 
+// WithMediatorPolicy injects the policy the agent applies when it mediates for the other agents.
+func WithMediatorPolicy(policy mediator.Policy) Option {
+	return func(opts *Aries) error {
+		opts.mediatorPolicy = policy
+		return nil
+	}
+}
+
+// WithOutboundQueue enables the persistent outbound queue, the messages the outbound transports failed to send
+// are retried until they are delivered or moved to the dead letters.
+func WithOutboundQueue(queueOpts ...dispatcher.QueueOption) Option {
+	return func(opts *Aries) error {
+		opts.outboundQueueEnabled = true
+		opts.outboundQueueOpts = append(opts.outboundQueueOpts, queueOpts...)
+
+		return nil
+	}
+}
+
+// WithForwardPacking sets the packing algorithm of the forward messages sent to the mediators of the recipients,
+// anoncrypt is used by default.
+func WithForwardPacking(packing dispatcher.ForwardPacking) Option {
+	return func(opts *Aries) error {
+		opts.forwardPacking = packing
+
+		return nil
+	}
+}
+
+// WithReplayProtection enables the storage backed cache of the seen inbound messages, the duplicates and
+// the replays of the messages are rejected.
+func WithReplayProtection(cacheOpts ...replay.Option) Option {
+	return func(opts *Aries) error {
+		opts.replayEnabled = true
+		opts.replayOpts = append(opts.replayOpts, cacheOpts...)
+
+		return nil
+	}
+}
+
+// WithProtocolExpiry enables the reaper which abandons the issuecredential, presentproof, introduce and
+// didexchange protocol instances that have expired, the other agent is notified with the problem report.
+func WithProtocolExpiry(expiryOpts ...expiry.Option) Option {
+	return func(opts *Aries) error {
+		opts.protocolExpiryEnabled = true
+		opts.protocolExpiryOpts = append(opts.protocolExpiryOpts, expiryOpts...)
+
+		return nil
+	}
+}
+
+// WithLDProofCredentialIssuer enables the issuance of the linked data proof credentials requested over the issue
+// credential protocol, they are signed with the given verification method once the request is accepted.
+func WithLDProofCredentialIssuer(verificationMethod string, issuerOpts ...mdissuecredential.IssuerOpt) Option {
+	return func(opts *Aries) error {
+		opts.ldProofIssuerVM = verificationMethod
+		opts.ldProofIssuerOpts = append(opts.ldProofIssuerOpts, issuerOpts...)
+
+		return nil
+	}
+}
+
+// WithLDProofCredentialAutoAccept accepts the requests of the linked data proof credentials approved by the
+// issuer policy without raising action events, the other requests raise action events as usual. It has no effect
+// unless the issuer is enabled with WithLDProofCredentialIssuer, whose issuer policy has to restrict the types
+// of the credentials and bind their subjects to the connection (see mdissuecredential.AutoAcceptLDProofRequests).
+func WithLDProofCredentialAutoAccept() Option {
+	return func(opts *Aries) error {
+		opts.ldProofAutoAccept = true
+
+		return nil
+	}
+}
+
+// WithWebSocketPoolOptions configures the websocket connection pool of the framework. The websocket transports
+// started without a pool of their own (see ws.WithPool) share the pool, it is closed when the framework is closed.
+func WithWebSocketPoolOptions(poolOpts ...ws.PoolOption) Option {
+	return func(opts *Aries) error {
+		opts.wsPoolOpts = append(opts.wsPoolOpts, poolOpts...)
+
+		return nil
+	}
+}
+
+// WithReplayCache injects the custom cache of the seen inbound messages, it enables the replay protection.
+func WithReplayCache(cache replay.Cache) Option {
+	return func(opts *Aries) error {
+		opts.replayCache = cache
+		return nil
+	}
+}
+
 // Context provides a handle to the framework context.
 func (a *Aries) Context() (*context.Provider, error) {
 	return context.New(
@@ -289,6 +407,9 @@ func (a *Aries) Context() (*context.Provider, error) {
 		context.WithAriesFrameworkID(a.id),
 		context.WithMessageServiceProvider(a.msgSvcProvider),
 		context.WithVerifiableStore(a.verifiableStore),
+		context.WithOutboundQueue(a.outboundQueue),
+		context.WithReplayCache(a.replayCache),
+		context.WithWebSocketPool(a.wsPool),
 	)
 }
 
@@ -300,6 +421,18 @@ func (a *Aries) Messenger() service.Messenger {
 
 // Close frees resources being maintained by the framework.
 func (a *Aries) Close() error {
+	// the services stop their background work, ex. the reapers of the expired protocol instances, before
+	// the stores are closed
+	if err := a.closeServices(); err != nil {
+		return err
+	}
+
+	if a.outboundQueue != nil {
+		if err := a.outboundQueue.Close(); err != nil {
+			return fmt.Errorf("failed to close the outbound queue: %w", err)
+		}
+	}
+
 	if a.storeProvider != nil {
 		err := a.storeProvider.Close()
 		if err != nil {
@@ -320,9 +453,30 @@ func (a *Aries) Close() error {
 		}
 	}
 
+	if a.wsPool != nil {
+		if err := a.wsPool.Close(); err != nil {
+			return fmt.Errorf("websocket connection pool close failed: %w", err)
+		}
+	}
+
 	return a.closeVDR()
 }
 
+func (a *Aries) closeServices() error {
+	for _, svc := range a.services {
+		closer, ok := svc.(io.Closer)
+		if !ok {
+			continue
+		}
+
+		if err := closer.Close(); err != nil {
+			return fmt.Errorf("%s service close failed: %w", svc.Name(), err)
+		}
+	}
+
+	return nil
+}
+
 func (a *Aries) closeVDR() error {
 	if a.vdrRegistry != nil {
 		if err := a.vdrRegistry.Close(); err != nil {
@@ -393,6 +547,8 @@ func createMessengerHandler(frameworkOpts *Aries) error {
 	ctx, err := context.New(
 		context.WithOutboundDispatcher(frameworkOpts.outboundDispatcher),
 		context.WithStorageProvider(frameworkOpts.storeProvider),
+		context.WithKMS(frameworkOpts.kms),
+		context.WithServiceEndpoint(serviceEndpoint(frameworkOpts)),
 	)
 	if err != nil {
 		return fmt.Errorf("context creation failed: %w", err)
@@ -416,12 +572,42 @@ func createOutboundDispatcher(frameworkOpts *Aries) error {
 		return fmt.Errorf("context creation failed: %w", err)
 	}
 
-	frameworkOpts.outboundDispatcher = dispatcher.NewOutbound(ctx)
+	opts := []dispatcher.OutboundOption{dispatcher.WithForwardPacking(frameworkOpts.forwardPacking)}
+
+	if frameworkOpts.outboundQueueEnabled {
+		frameworkOpts.outboundQueue, err = dispatcher.NewOutboundQueue(frameworkOpts.storeProvider,
+			frameworkOpts.outboundQueueOpts...)
+		if err != nil {
+			return fmt.Errorf("outbound queue creation failed: %w", err)
+		}
+
+		opts = append(opts, dispatcher.WithOutboundQueue(frameworkOpts.outboundQueue))
+	}
+
+	frameworkOpts.outboundDispatcher = dispatcher.NewOutbound(ctx, opts...)
+
+	return nil
+}
+
+func createReplayCache(frameworkOpts *Aries) error {
+	if frameworkOpts.replayCache != nil || !frameworkOpts.replayEnabled {
+		return nil
+	}
+
+	cache, err := replay.NewCache(frameworkOpts.storeProvider, frameworkOpts.replayOpts...)
+	if err != nil {
+		return fmt.Errorf("replay cache creation failed: %w", err)
+	}
+
+	frameworkOpts.replayCache = cache
 
 	return nil
 }
 
 func startTransports(frameworkOpts *Aries) error {
+	// the websocket transports share the pool of the framework, so the messages are sent on the return routes
+	frameworkOpts.wsPool = ws.NewPool(frameworkOpts.wsPoolOpts...)
+
 	ctx, err := context.New(
 		context.WithCrypto(frameworkOpts.crypto),
 		context.WithPackager(frameworkOpts.packager),
@@ -429,6 +615,8 @@ func startTransports(frameworkOpts *Aries) error {
 		context.WithAriesFrameworkID(frameworkOpts.id),
 		context.WithMessageServiceProvider(frameworkOpts.msgSvcProvider),
 		context.WithMessengerHandler(frameworkOpts.messenger),
+		context.WithReplayCache(frameworkOpts.replayCache),
+		context.WithWebSocketPool(frameworkOpts.wsPool),
 	)
 	if err != nil {
 		return fmt.Errorf("context creation failed: %w", err)
@@ -465,6 +653,7 @@ func loadServices(frameworkOpts *Aries) error {
 		context.WithVDRegistry(frameworkOpts.vdrRegistry),
 		context.WithVerifiableStore(frameworkOpts.verifiableStore),
 		context.WithMessageServiceProvider(frameworkOpts.msgSvcProvider),
+		context.WithReplayCache(frameworkOpts.replayCache),
 	)
 	if err != nil {
 		return fmt.Errorf("create context failed: %w", err)
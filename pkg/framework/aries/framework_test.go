@@ -10,6 +10,7 @@ import (
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -18,19 +19,30 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 
 	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	cryptoapi "github.com/hyperledger/aries-framework-go/pkg/crypto"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/expiry"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	commontransport "github.com/hyperledger/aries-framework-go/pkg/didcomm/common/transport"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/dispatcher"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/packer"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didexchange"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/issuecredential"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/mediator"
+	mdissuecredential "github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/middleware/issuecredential"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/replay"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/transport"
+	memtransport "github.com/hyperledger/aries-framework-go/pkg/didcomm/transport/mem"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/transport/ws"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
 	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api"
+	vdrapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
 	"github.com/hyperledger/aries-framework-go/pkg/framework/context"
 	mocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/didcomm/common/service"
 	verifiableStoreMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/store/verifiable"
@@ -49,6 +61,7 @@ import (
 	locallock "github.com/hyperledger/aries-framework-go/pkg/secretlock/local"
 	"github.com/hyperledger/aries-framework-go/pkg/secretlock/local/masterlock/hkdf"
 	"github.com/hyperledger/aries-framework-go/pkg/secretlock/noop"
+	"github.com/hyperledger/aries-framework-go/pkg/vdr/fingerprint"
 	"github.com/hyperledger/aries-framework-go/pkg/vdr/peer"
 )
 
@@ -187,6 +200,81 @@ func TestFramework(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("test default services disclose the message types they accept", func(t *testing.T) {
+		aries, err := New(WithInboundTransport(&mockInboundTransport{}))
+		require.NoError(t, err)
+
+		defer func() { require.NoError(t, aries.Close()) }()
+
+		ctx, err := aries.Context()
+		require.NoError(t, err)
+
+		for _, svc := range ctx.AllServices() {
+			discloser, ok := svc.(dispatcher.Discloser)
+			require.True(t, ok, svc.Name())
+			require.NotEmpty(t, discloser.MessageTypes(), svc.Name())
+
+			for _, msgType := range discloser.MessageTypes() {
+				require.True(t, svc.Accept(msgType), msgType)
+			}
+		}
+	})
+
+	t.Run("test agents exchange messages over the in-memory transport", func(t *testing.T) {
+		bus := memtransport.NewBus()
+		received := make(chan service.DIDCommMsg, 1)
+
+		newAgent := func(endpoint string, msgSvcProvider *msghandler.MockMsgSvcProvider) (*Aries, string) {
+			inbound, err := memtransport.NewInbound(bus, endpoint)
+			require.NoError(t, err)
+
+			agent, err := New(WithInboundTransport(inbound), WithOutboundTransports(memtransport.NewOutbound(bus)),
+				WithMessageServiceProvider(msgSvcProvider))
+			require.NoError(t, err)
+
+			ctx, err := agent.Context()
+			require.NoError(t, err)
+			require.Equal(t, endpoint, ctx.ServiceEndpoint())
+
+			_, pubKey, err := ctx.KMS().CreateAndExportPubKeyBytes(kms.ED25519Type)
+			require.NoError(t, err)
+
+			didKey, _ := fingerprint.CreateDIDKey(pubKey)
+
+			return agent, didKey
+		}
+
+		bobMsgSvcProvider := msghandler.NewMockMsgServiceProvider()
+		require.NoError(t, bobMsgSvcProvider.Register(&generic.MockMessageSvc{
+			HandleFunc: func(msg *service.DIDCommMsg) (string, error) {
+				received <- *msg
+
+				return "", nil
+			},
+			NameVal: "test",
+		}))
+
+		alice, aliceKey := newAgent("mem://alice", msghandler.NewMockMsgServiceProvider())
+		bob, bobKey := newAgent("mem://bob", bobMsgSvcProvider)
+
+		ctx, err := alice.Context()
+		require.NoError(t, err)
+
+		require.NoError(t, ctx.OutboundDispatcher().Send(
+			service.DIDCommMsgMap{"@id": "1", "@type": "https://didcomm.org/test/1.0/msg"},
+			aliceKey, &service.Destination{ServiceEndpoint: "mem://bob", RecipientKeys: []string{bobKey}}))
+
+		select {
+		case msg := <-received:
+			require.Equal(t, "https://didcomm.org/test/1.0/msg", msg.Type())
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "message not received")
+		}
+
+		require.NoError(t, alice.Close())
+		require.NoError(t, bob.Close())
+	})
+
 	t.Run("test protocol svc - with default protocol", func(t *testing.T) {
 		aries, err := New(WithInboundTransport(&mockInboundTransport{}))
 		require.NoError(t, err)
@@ -244,6 +332,21 @@ func TestFramework(t *testing.T) {
 		require.Error(t, err)
 	})
 
+	t.Run("test close error from protocol service", func(t *testing.T) {
+		newMockSvc := func(prv api.Provider) (dispatcher.ProtocolService, error) {
+			return &closerSvc{
+				MockDIDExchangeSvc: &mockdidexchange.MockDIDExchangeSvc{ProtocolName: "mockProtocolSvc"},
+				closeErr:           errors.New("close error"),
+			}, nil
+		}
+
+		aries, err := New(WithProtocols(newMockSvc))
+		require.NoError(t, err)
+
+		err = aries.Close()
+		require.EqualError(t, err, "mockProtocolSvc service close failed: close error")
+	})
+
 	t.Run("test error from protocol service", func(t *testing.T) {
 		newMockSvc := func(prv api.Provider) (dispatcher.ProtocolService, error) {
 			return nil, errors.New("error creating the protocol")
@@ -575,6 +678,116 @@ func TestFramework(t *testing.T) {
 		require.Contains(t, err.Error(), "invalid transport return route option : "+transportReturnRoute)
 	})
 
+	t.Run("test new with replay protection", func(t *testing.T) {
+		aries, err := New(WithReplayProtection(replay.WithTTL(time.Hour)))
+		require.NoError(t, err)
+		require.NotNil(t, aries.replayCache)
+
+		ctx, err := aries.Context()
+		require.NoError(t, err)
+		require.Equal(t, aries.replayCache, ctx.ReplayCache())
+		require.NoError(t, aries.Close())
+	})
+
+	t.Run("test new with replay cache", func(t *testing.T) {
+		cache, err := replay.NewCache(mem.NewProvider())
+		require.NoError(t, err)
+
+		aries, err := New(WithReplayCache(cache))
+		require.NoError(t, err)
+		require.Equal(t, cache, aries.replayCache)
+		require.NoError(t, aries.Close())
+	})
+
+	t.Run("test new with outbound queue", func(t *testing.T) {
+		aries, err := New(WithOutboundQueue(dispatcher.WithMaxAge(time.Hour)))
+		require.NoError(t, err)
+		require.NotNil(t, aries.outboundQueue)
+
+		ctx, err := aries.Context()
+		require.NoError(t, err)
+		require.Equal(t, aries.outboundQueue, ctx.OutboundQueue())
+		require.NoError(t, aries.Close())
+	})
+
+	t.Run("test new with websocket pool options", func(t *testing.T) {
+		outbound := ws.NewOutbound()
+
+		aries, err := New(WithWebSocketPoolOptions(ws.WithMaxConnections(5)), WithOutboundTransports(outbound))
+		require.NoError(t, err)
+		require.NotNil(t, aries.wsPool)
+		require.Len(t, aries.wsPoolOpts, 1)
+		require.Equal(t, aries.wsPool, outbound.Pool())
+
+		ctx, err := aries.Context()
+		require.NoError(t, err)
+		require.Equal(t, aries.wsPool, ctx.WebSocketPool())
+		require.NoError(t, aries.Close())
+	})
+
+	t.Run("test new with forward packing", func(t *testing.T) {
+		aries, err := New(WithForwardPacking(dispatcher.AuthcryptForward))
+		require.NoError(t, err)
+		require.Equal(t, dispatcher.AuthcryptForward, aries.forwardPacking)
+		require.NoError(t, aries.Close())
+	})
+
+	t.Run("test new with protocol expiry", func(t *testing.T) {
+		aries, err := New(WithProtocolExpiry(expiry.WithTimeout(time.Hour)))
+		require.NoError(t, err)
+		require.True(t, aries.protocolExpiryEnabled)
+		require.Len(t, aries.protocolExpiryOpts, 1)
+		require.NoError(t, aries.Close())
+	})
+
+	t.Run("test new with mediator policy", func(t *testing.T) {
+		policy := mediator.NewPolicy(mediator.WithMaxKeys(10))
+
+		aries, err := New(WithMediatorPolicy(policy))
+		require.NoError(t, err)
+		require.Equal(t, policy, aries.mediatorPolicy)
+		require.NoError(t, aries.Close())
+	})
+
+	t.Run("test new with ld proof credential issuer", func(t *testing.T) {
+		aries, err := New(WithLDProofCredentialIssuer("did:example:issuer#key-1",
+			mdissuecredential.WithSignatureType(mdissuecredential.JSONWebSignature2020)))
+		require.NoError(t, err)
+		require.Equal(t, "did:example:issuer#key-1", aries.ldProofIssuerVM)
+		require.Len(t, aries.ldProofIssuerOpts, 1)
+		require.False(t, aries.ldProofAutoAccept)
+
+		ctx, err := aries.Context()
+		require.NoError(t, err)
+
+		_, err = ctx.Service(issuecredential.Name)
+		require.NoError(t, err)
+		require.NoError(t, aries.Close())
+	})
+
+	t.Run("test new with ld proof credential auto accept", func(t *testing.T) {
+		aries, err := New(WithLDProofCredentialIssuer("did:example:issuer#key-1",
+			mdissuecredential.WithIssuerPolicy(mdissuecredential.NewIssuerPolicy(
+				mdissuecredential.WithAllowedTypes("UniversityDegreeCredential"),
+				mdissuecredential.WithSubjectBinding(),
+			))), WithLDProofCredentialAutoAccept())
+		require.NoError(t, err)
+		require.True(t, aries.ldProofAutoAccept)
+
+		ctx, err := aries.Context()
+		require.NoError(t, err)
+
+		_, err = ctx.Service(issuecredential.Name)
+		require.NoError(t, err)
+		require.NoError(t, aries.Close())
+	})
+
+	t.Run("test new with ld proof credential auto accept error", func(t *testing.T) {
+		_, err := New(WithLDProofCredentialIssuer("did:example:issuer#key-1"), WithLDProofCredentialAutoAccept())
+		require.Error(t, err)
+		require.True(t, errors.Is(err, mdissuecredential.ErrPolicyNotRestrictive))
+	})
+
 	t.Run("test message service provider option", func(t *testing.T) {
 		// custom message service provider
 		handler := msghandler.NewMockMsgServiceProvider()
@@ -632,6 +845,112 @@ func Test_Packager(t *testing.T) {
 	})
 }
 
+func Test_PackagerDIDCommV1AndV2(t *testing.T) {
+	docs := map[string]*did.Doc{}
+
+	vdr := &mockvdr.MockVDR{
+		AcceptValue: true,
+		ReadFunc: func(didID string, _ ...vdrapi.ResolveOption) (*did.DocResolution, error) {
+			doc, ok := docs[didID]
+			if !ok {
+				return nil, vdrapi.ErrNotFound
+			}
+
+			return &did.DocResolution{DIDDocument: doc}, nil
+		},
+	}
+
+	newAgent := func(name string) (*context.Provider, string, string) {
+		a, err := New(WithVDR(vdr), WithInboundTransport(&mockInboundTransport{}),
+			WithStoreProvider(mem.NewProvider()))
+		require.NoError(t, err)
+
+		t.Cleanup(func() {
+			require.NoError(t, a.Close())
+		})
+
+		ctx, err := a.Context()
+		require.NoError(t, err)
+
+		// DIDComm V2 key agreement key
+		kaKID, kaKey, err := ctx.KMS().CreateAndExportPubKeyBytes(kms.X25519ECDHKWType)
+		require.NoError(t, err)
+
+		pubKey := &cryptoapi.PublicKey{}
+		require.NoError(t, json.Unmarshal(kaKey, pubKey))
+
+		didID := "did:example:" + name
+		kid := didID + "#" + kaKID
+
+		docs[didID] = &did.Doc{
+			ID: didID,
+			KeyAgreement: []did.Verification{{
+				VerificationMethod: *did.NewVerificationMethodFromBytes(kid, "X25519KeyAgreementKey2019", didID,
+					pubKey.X),
+				Relationship: did.KeyAgreement,
+			}},
+		}
+
+		// DIDComm V1 key
+		_, verKey, err := ctx.KMS().CreateAndExportPubKeyBytes(kms.ED25519Type)
+		require.NoError(t, err)
+
+		return ctx, kid, string(verKey)
+	}
+
+	alice, aliceKID, aliceVerKey := newAgent("alice")
+	bob, bobKID, bobVerKey := newAgent("bob")
+
+	t.Run("DIDComm V2 authcrypt", func(t *testing.T) {
+		msg := []byte(`{"id":"1","type":"https://didcomm.org/basicmessage/2.0/message"}`)
+
+		packed, err := alice.Packager().PackMessage(&commontransport.Envelope{
+			MediaType: commontransport.MediaTypeV2EncryptedEnvelope,
+			Message:   msg,
+			FromKey:   []byte(aliceKID),
+			ToKeys:    []string{bobKID},
+		})
+		require.NoError(t, err)
+
+		unpacked, err := bob.Packager().UnpackMessage(packed)
+		require.NoError(t, err)
+		require.Equal(t, msg, unpacked.Message)
+	})
+
+	t.Run("DIDComm V2 anoncrypt", func(t *testing.T) {
+		msg := []byte(`{"id":"2","type":"https://didcomm.org/basicmessage/2.0/message"}`)
+
+		packed, err := bob.Packager().PackMessage(&commontransport.Envelope{
+			MediaType: commontransport.MediaTypeV2EncryptedEnvelope,
+			Message:   msg,
+			ToKeys:    []string{aliceKID},
+		})
+		require.NoError(t, err)
+
+		unpacked, err := alice.Packager().UnpackMessage(packed)
+		require.NoError(t, err)
+		require.Equal(t, msg, unpacked.Message)
+	})
+
+	t.Run("DIDComm V1 reply", func(t *testing.T) {
+		msg := []byte(`{"@id":"3","@type":"https://didcomm.org/basicmessage/1.0/message"}`)
+
+		aliceDIDKey, _ := fingerprint.CreateDIDKey([]byte(aliceVerKey))
+
+		packed, err := bob.Packager().PackMessage(&commontransport.Envelope{
+			Message: msg,
+			FromKey: []byte(bobVerKey),
+			ToKeys:  []string{aliceDIDKey},
+		})
+		require.NoError(t, err)
+
+		unpacked, err := alice.Packager().UnpackMessage(packed)
+		require.NoError(t, err)
+		require.Equal(t, msg, unpacked.Message)
+		require.Equal(t, []byte(bobVerKey), unpacked.FromKey)
+	})
+}
+
 func startMockServer(t *testing.T, handler http.Handler) net.Listener {
 	// ":0" will make the listener auto assign a free port
 	listener, err := net.Listen("tcp", "127.0.0.1:0")
@@ -694,3 +1013,12 @@ func (m *mockInboundTransport) Stop() error {
 func (m *mockInboundTransport) Endpoint() string {
 	return ""
 }
+
+type closerSvc struct {
+	*mockdidexchange.MockDIDExchangeSvc
+	closeErr error
+}
+
+func (s *closerSvc) Close() error {
+	return s.closeErr
+}
@@ -7,14 +7,23 @@ SPDX-License-Identifier: Apache-2.0
 package context
 
 import (
+	"errors"
 	"fmt"
 
+	"github.com/btcsuite/btcutil/base58"
+
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
 	"github.com/hyperledger/aries-framework-go/pkg/crypto"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/expiry"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/model"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	commontransport "github.com/hyperledger/aries-framework-go/pkg/didcomm/common/transport"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/dispatcher"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/packer"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/replay"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/transport"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/transport/ws"
 	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api"
 	vdrapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
 	"github.com/hyperledger/aries-framework-go/pkg/kms"
@@ -23,6 +32,8 @@ import (
 	"github.com/hyperledger/aries-framework-go/spi/storage"
 )
 
+var logger = log.New("aries-framework/context")
+
 // package context creates a framework Provider context to add optional (non default) framework services and provides
 // simple accessor methods to those same services.
 
@@ -41,6 +52,9 @@ type Provider struct {
 	serviceEndpoint            string
 	routerEndpoint             string
 	outboundDispatcher         dispatcher.Outbound
+	outboundQueue              *dispatcher.OutboundQueue
+	replayCache                replay.Cache
+	wsPool                     *ws.Pool
 	messenger                  service.MessengerHandler
 	outboundTransports         []transport.OutboundTransport
 	vdr                        vdrapi.Registry
@@ -98,6 +112,16 @@ func (p *Provider) Service(id string) (interface{}, error) {
 	return nil, api.ErrSvcNotFound
 }
 
+// AllServices returns all the protocol services.
+func (p *Provider) AllServices() []dispatcher.ProtocolService {
+	return p.services
+}
+
+// MessageServiceProvider returns the provider of the message services.
+func (p *Provider) MessageServiceProvider() api.MessageServiceProvider {
+	return p.msgSvcProvider
+}
+
 // KMS returns a Key Management Service.
 func (p *Provider) KMS() kms.KeyManager {
 	return p.kms
@@ -153,46 +177,162 @@ func (p *Provider) tryToHandle(svc service.InboundHandler, msg service.DIDCommMs
 	}
 
 	_, err := svc.HandleInbound(msg, myDID, theirDID)
+	if err != nil {
+		return err
+	}
+
+	// the message services handle the message at once, so its outcome is known as well
+	p.acknowledge(msg, myDID, theirDID, true)
+
+	return nil
+}
+
+// connectionlessMessenger is implemented by the messengers which keep track of the connectionless threads.
+type connectionlessMessenger interface {
+	StartedConnectionless(thID string) (bool, error)
+}
+
+// acknowledge sends the generic ack if the handled message asks for it with the ~please_ack decorator,
+// on receipt or, if handled is true, on outcome of the message.
+// The ack is sent over the connection, or without it only on the threads the agent started, so the unauthenticated
+// messages can't make the agent send the acks to the endpoints of their choice.
+// The ack is not part of the message handling, so failing to send it is only logged.
+func (p *Provider) acknowledge(msg service.DIDCommMsgMap, myDID, theirDID string, handled bool) {
+	if p.messenger == nil || msg.Type() == model.AckMsgType || msg.Type() == model.ProblemReportMsgType {
+		return
+	}
+
+	pleaseAck := struct {
+		PleaseAck *decorator.PleaseAck `json:"~please_ack,omitempty"`
+	}{}
+
+	if err := msg.Decode(&pleaseAck); err != nil {
+		logger.Warnf("failed to decode ~please_ack of the message %s: %s", msg.ID(), err)
+
+		return
+	}
+
+	if !pleaseAck.PleaseAck.OnReceipt() && !(handled && pleaseAck.PleaseAck.OnOutcome()) {
+		return
+	}
+
+	if theirDID == "" && !p.startedConnectionless(msg) {
+		logger.Debugf("not acknowledging the message %s of the thread the agent didn't start", msg.ID())
 
-	return err
+		return
+	}
+
+	ack := service.NewDIDCommMsgMap(model.Ack{Type: model.AckMsgType, Status: model.AckStatusOK})
+
+	if err := p.messenger.ReplyToMsg(msg, ack, myDID, theirDID); err != nil {
+		logger.Warnf("failed to acknowledge the receipt of the message %s: %s", msg.ID(), err)
+	}
+}
+
+// startedConnectionless checks whether the thread of the message was started by the agent without the connection.
+func (p *Provider) startedConnectionless(msg service.DIDCommMsgMap) bool {
+	m, ok := p.messenger.(connectionlessMessenger)
+	if !ok {
+		return false
+	}
+
+	thID, err := msg.ThreadID()
+	if err != nil {
+		return false
+	}
+
+	started, err := m.StartedConnectionless(thID)
+	if err != nil {
+		logger.Warnf("failed to check the connectionless thread of the message %s: %s", msg.ID(), err)
+
+		return false
+	}
+
+	return started
 }
 
 // InboundMessageHandler return an inbound message handler.
 func (p *Provider) InboundMessageHandler() transport.InboundMessageHandler {
-	return func(message []byte, myDID, theirDID string) error {
-		msg, err := service.ParseDIDCommMsgMap(message)
+	return func(envelope *commontransport.Envelope) error {
+		msg, err := service.ParseDIDCommMsgMap(envelope.Message)
 		if err != nil {
 			return err
 		}
 
-		// find the service which accepts the message type
-		for _, svc := range p.services {
-			if svc.Accept(msg.Type()) {
-				_, err = svc.HandleInbound(msg, myDID, theirDID)
+		if msg.IsDIDCommV2() && msg.ID() == "" {
+			return errors.New("invalid DIDComm V2 message: missing id")
+		}
 
-				return err
+		err = expiry.Check(msg)
+		if err != nil {
+			return fmt.Errorf("expiry check: %w", err)
+		}
+
+		if p.replayCache == nil {
+			return p.handleInbound(msg, envelope.ToDID, envelope.FromDID)
+		}
+
+		senderKey := base58.Encode(envelope.FromKey)
+
+		err = p.replayCache.Check(senderKey, msg)
+		if err != nil {
+			return fmt.Errorf("replay check: %w", err)
+		}
+
+		err = p.handleInbound(msg, envelope.ToDID, envelope.FromDID)
+		if err != nil {
+			// the message is forgotten, so the retry of the sender isn't rejected as a duplicate
+			if e := p.replayCache.Forget(senderKey, msg); e != nil {
+				logger.Warnf("failed to forget the message %s: %s", msg.ID(), e)
 			}
+
+			return err
 		}
 
-		// in case of no services are registered for given message type,
-		// find generic inbound services registered for given message header
-		for _, svc := range p.msgSvcProvider.Services() {
-			h := struct {
-				Purpose []string `json:"~purpose"`
-			}{}
-			err = msg.Decode(&h)
+		if err = p.replayCache.Commit(senderKey, msg); err != nil {
+			logger.Warnf("failed to record the message %s as handled: %s", msg.ID(), err)
+		}
+
+		return nil
+	}
+}
 
+func (p *Provider) handleInbound(msg service.DIDCommMsgMap, myDID, theirDID string) error {
+	// find the service which accepts the message type
+	for _, svc := range p.services {
+		if svc.Accept(msg.Type()) {
+			_, err := svc.HandleInbound(msg, myDID, theirDID)
 			if err != nil {
 				return err
 			}
 
-			if svc.Accept(msg.Type(), h.Purpose) {
-				return p.tryToHandle(svc, msg, myDID, theirDID)
+			// the outcome of the message is acknowledged by the protocol once it is processed
+			if a, ok := svc.(dispatcher.Acknowledger); !ok || !a.Acknowledges(msg.Type()) {
+				p.acknowledge(msg, myDID, theirDID, false)
 			}
+
+			return nil
+		}
+	}
+
+	// in case of no services are registered for given message type,
+	// find generic inbound services registered for given message header
+	for _, svc := range p.msgSvcProvider.Services() {
+		h := struct {
+			Purpose []string `json:"~purpose"`
+		}{}
+
+		err := msg.Decode(&h)
+		if err != nil {
+			return err
 		}
 
-		return fmt.Errorf("no message handlers found for the message type: %s", msg.Type())
+		if svc.Accept(msg.Type(), h.Purpose) {
+			return p.tryToHandle(svc, msg, myDID, theirDID)
+		}
 	}
+
+	return fmt.Errorf("no message handlers found for the message type: %s", msg.Type())
 }
 
 // OutboundMessageHandler returns a handler composed of all registered protocol services.
@@ -233,6 +373,21 @@ func (p *Provider) VerifiableStore() verifiable.Store {
 	return p.verifiableStore
 }
 
+// OutboundQueue returns the outbound message queue, nil if the queue is not enabled.
+func (p *Provider) OutboundQueue() *dispatcher.OutboundQueue {
+	return p.outboundQueue
+}
+
+// ReplayCache returns the cache of the seen inbound messages, nil if the replay protection is not enabled.
+func (p *Provider) ReplayCache() replay.Cache {
+	return p.replayCache
+}
+
+// WebSocketPool returns the websocket connection pool shared by the websocket transports of the framework.
+func (p *Provider) WebSocketPool() *ws.Pool {
+	return p.wsPool
+}
+
 // ProviderOption configures the framework.
 type ProviderOption func(opts *Provider) error
 
@@ -385,3 +540,27 @@ func WithVerifiableStore(store verifiable.Store) ProviderOption {
 		return nil
 	}
 }
+
+// WithOutboundQueue injects the outbound message queue into the context.
+func WithOutboundQueue(queue *dispatcher.OutboundQueue) ProviderOption {
+	return func(opts *Provider) error {
+		opts.outboundQueue = queue
+		return nil
+	}
+}
+
+// WithReplayCache injects the cache of the seen inbound messages into the context.
+func WithReplayCache(cache replay.Cache) ProviderOption {
+	return func(opts *Provider) error {
+		opts.replayCache = cache
+		return nil
+	}
+}
+
+// WithWebSocketPool injects the websocket connection pool into the context.
+func WithWebSocketPool(pool *ws.Pool) ProviderOption {
+	return func(opts *Provider) error {
+		opts.wsPool = pool
+		return nil
+	}
+}
@@ -16,9 +16,13 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/expiry"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/model"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/transport"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didexchange"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/replay"
 	serviceMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/didcomm/common/service"
 	verifiableStoreMocks "github.com/hyperledger/aries-framework-go/pkg/internal/gomocks/store/verifiable"
 	mockcrypto "github.com/hyperledger/aries-framework-go/pkg/mock/crypto"
@@ -71,6 +75,16 @@ func TestNewProvider(t *testing.T) {
 
 		_, err = prov.Service("mockProtocolSvc1")
 		require.Error(t, err)
+
+		require.Len(t, prov.AllServices(), 1)
+	})
+
+	t.Run("test new with message service provider", func(t *testing.T) {
+		msgSvcProvider := msghandler.NewMockMsgServiceProvider()
+
+		prov, err := New(WithMessageServiceProvider(msgSvcProvider))
+		require.NoError(t, err)
+		require.Equal(t, msgSvcProvider, prov.MessageServiceProvider())
 	})
 
 	t.Run("test inbound message handlers/dispatchers", func(t *testing.T) {
@@ -107,42 +121,351 @@ func TestNewProvider(t *testing.T) {
 		inboundHandler := ctx.InboundMessageHandler()
 
 		// valid json and message type
-		err = inboundHandler([]byte(`
+		err = inboundHandler(&transport.Envelope{Message: []byte(`
 		{
 			"@frameworkID": "5678876542345",
 			"@type": "valid-message-type"
-		}`), "", "")
+		}`)})
 		require.NoError(t, err)
 
 		// invalid json
-		err = inboundHandler([]byte("invalid json"), "", "")
+		err = inboundHandler(&transport.Envelope{Message: []byte("invalid json")})
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "invalid payload data format")
 
 		// invalid json
-		err = inboundHandler([]byte("invalid json"), "", "")
+		err = inboundHandler(&transport.Envelope{Message: []byte("invalid json")})
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "invalid payload data format")
 
 		// no handlers
-		err = inboundHandler([]byte(`
+		err = inboundHandler(&transport.Envelope{Message: []byte(`
 		{
 			"@type": "invalid-message-type",
 			"label": "Bob"
-		}`), "", "")
+		}`)})
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "no message handlers found for the message type: invalid-message-type")
 
 		// valid json, message type but service handlers returns error
-		err = inboundHandler([]byte(`
+		err = inboundHandler(&transport.Envelope{Message: []byte(`
 		{
 			"label": "Carol",
 			"@type": "valid-message-type"
-		}`), "", "")
+		}`)})
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "error handling the message")
 	})
 
+	t.Run("test inbound message handler with replay cache", func(t *testing.T) {
+		cache, err := replay.NewCache(mem.NewProvider())
+		require.NoError(t, err)
+
+		ctx, err := New(WithProtocolServices(&mockdidexchange.MockDIDExchangeSvc{
+			AcceptFunc: func(msgType string) bool {
+				return true
+			},
+			HandleFunc: func(msg service.DIDCommMsg) (string, error) {
+				return "", nil
+			},
+		}), WithReplayCache(cache))
+		require.NoError(t, err)
+		require.Equal(t, cache, ctx.ReplayCache())
+
+		envelope := &transport.Envelope{
+			Message: []byte(`{"@id": "msg-1", "@type": "valid-message-type"}`),
+			FromKey: []byte("sender-key"),
+		}
+
+		inboundHandler := ctx.InboundMessageHandler()
+		require.NoError(t, inboundHandler(envelope))
+
+		err = inboundHandler(envelope)
+		require.True(t, errors.Is(err, replay.ErrDuplicate))
+
+		// the message with the same ID from another sender is handled
+		require.NoError(t, inboundHandler(&transport.Envelope{Message: envelope.Message, FromKey: []byte("another-key")}))
+	})
+
+	t.Run("test inbound message handler with replay cache accepts retry of failed message", func(t *testing.T) {
+		cache, err := replay.NewCache(mem.NewProvider())
+		require.NoError(t, err)
+
+		handled := 0
+
+		ctx, err := New(WithProtocolServices(&mockdidexchange.MockDIDExchangeSvc{
+			AcceptFunc: func(msgType string) bool {
+				return true
+			},
+			HandleFunc: func(msg service.DIDCommMsg) (string, error) {
+				handled++
+
+				if handled == 1 {
+					return "", errors.New("handle error")
+				}
+
+				return "", nil
+			},
+		}), WithReplayCache(cache))
+		require.NoError(t, err)
+
+		envelope := &transport.Envelope{
+			Message: []byte(`{"@id": "msg-1", "@type": "valid-message-type"}`),
+			FromKey: []byte("sender-key"),
+		}
+
+		inboundHandler := ctx.InboundMessageHandler()
+
+		err = inboundHandler(envelope)
+		require.EqualError(t, err, "handle error")
+
+		// the retry of the sender is delivered
+		require.NoError(t, inboundHandler(envelope))
+		require.Equal(t, 2, handled)
+
+		err = inboundHandler(envelope)
+		require.True(t, errors.Is(err, replay.ErrDuplicate))
+		require.Equal(t, 2, handled)
+	})
+
+	t.Run("test inbound message handler rejects expired message", func(t *testing.T) {
+		ctx, err := New(WithProtocolServices(&mockdidexchange.MockDIDExchangeSvc{
+			AcceptFunc: func(msgType string) bool {
+				return true
+			},
+			HandleFunc: func(msg service.DIDCommMsg) (string, error) {
+				return "", nil
+			},
+		}))
+		require.NoError(t, err)
+
+		inboundHandler := ctx.InboundMessageHandler()
+
+		err = inboundHandler(&transport.Envelope{Message: []byte(`{
+			"@id": "msg-1",
+			"@type": "valid-message-type",
+			"~timing": {"expires_time": "2000-01-01T00:00:00Z"}
+		}`)})
+		require.True(t, errors.Is(err, expiry.ErrExpired))
+
+		require.NoError(t, inboundHandler(&transport.Envelope{Message: []byte(`{
+			"@id": "msg-2",
+			"@type": "valid-message-type",
+			"~timing": {"expires_time": "3000-01-01T00:00:00Z"}
+		}`)}))
+	})
+
+	t.Run("test inbound message handler with DIDComm V2 message", func(t *testing.T) {
+		var handledType string
+
+		ctx, err := New(WithProtocolServices(&mockdidexchange.MockDIDExchangeSvc{
+			AcceptFunc: func(msgType string) bool {
+				return true
+			},
+			HandleFunc: func(msg service.DIDCommMsg) (string, error) {
+				handledType = msg.Type()
+
+				return "", nil
+			},
+		}))
+		require.NoError(t, err)
+
+		inboundHandler := ctx.InboundMessageHandler()
+
+		require.NoError(t, inboundHandler(&transport.Envelope{Message: []byte(`{
+			"id": "msg-1",
+			"type": "https://didcomm.org/basicmessage/2.0/message",
+			"expires_time": 32503680000
+		}`)}))
+		require.Equal(t, "https://didcomm.org/basicmessage/2.0/message", handledType)
+
+		err = inboundHandler(&transport.Envelope{Message: []byte(`{
+			"type": "https://didcomm.org/basicmessage/2.0/message"
+		}`)})
+		require.EqualError(t, err, "invalid DIDComm V2 message: missing id")
+
+		err = inboundHandler(&transport.Envelope{Message: []byte(`{
+			"id": "msg-2",
+			"type": "https://didcomm.org/basicmessage/2.0/message",
+			"expires_time": 946684800
+		}`)})
+		require.True(t, errors.Is(err, expiry.ErrExpired))
+	})
+
+	t.Run("test inbound message handler acknowledges receipt", func(t *testing.T) {
+		messengerHandler := serviceMocks.NewMockMessengerHandler(ctrl)
+		messengerHandler.EXPECT().
+			ReplyToMsg(gomock.Any(), gomock.Any(), "my-did", "their-did").
+			DoAndReturn(func(in, out service.DIDCommMsgMap, _, _ string) error {
+				require.Equal(t, "msg-1", in.ID())
+				require.Equal(t, model.AckMsgType, out.Type())
+				require.Equal(t, model.AckStatusOK, out["status"])
+
+				return errors.New("reply error")
+			}).
+			Times(1)
+
+		ctx, err := New(WithProtocolServices(&mockdidexchange.MockDIDExchangeSvc{
+			AcceptFunc: func(msgType string) bool {
+				return true
+			},
+			HandleFunc: func(msg service.DIDCommMsg) (string, error) {
+				return "", nil
+			},
+		}), WithMessengerHandler(messengerHandler))
+		require.NoError(t, err)
+
+		inboundHandler := ctx.InboundMessageHandler()
+
+		// failing to send the ack does not fail the handling of the message
+		require.NoError(t, inboundHandler(&transport.Envelope{Message: []byte(`{
+			"@id": "msg-1",
+			"@type": "valid-message-type",
+			"~please_ack": {"on": ["RECEIPT"]}
+		}`), ToDID: "my-did", FromDID: "their-did"}))
+
+		// the ack is sent only on receipt
+		require.NoError(t, inboundHandler(&transport.Envelope{Message: []byte(`{
+			"@id": "msg-2",
+			"@type": "valid-message-type",
+			"~please_ack": {"on": ["OUTCOME"]}
+		}`), ToDID: "my-did", FromDID: "their-did"}))
+
+		// the acks are never acknowledged
+		require.NoError(t, inboundHandler(&transport.Envelope{Message: []byte(`{
+			"@id": "msg-3",
+			"@type": "https://didcomm.org/notification/1.0/ack",
+			"~please_ack": {}
+		}`), ToDID: "my-did", FromDID: "their-did"}))
+
+		require.NoError(t, inboundHandler(&transport.Envelope{Message: []byte(`{
+			"@id": "msg-4",
+			"@type": "valid-message-type",
+			"~please_ack": "invalid"
+		}`), ToDID: "my-did", FromDID: "their-did"}))
+	})
+
+	t.Run("test inbound message handler acknowledges without connection only on started threads", func(t *testing.T) {
+		messengerHandler := &connectionlessMessengerHandler{
+			MockMessengerHandler: serviceMocks.NewMockMessengerHandler(ctrl),
+			started:              map[string]bool{"started-thread": true},
+		}
+		messengerHandler.EXPECT().HandleInbound(gomock.Any(), "", "").Return(nil).Times(3)
+		messengerHandler.EXPECT().
+			ReplyToMsg(gomock.Any(), gomock.Any(), "", "").
+			DoAndReturn(func(in, out service.DIDCommMsgMap, _, _ string) error {
+				require.Equal(t, "msg-1", in.ID())
+
+				return nil
+			}).
+			Times(1)
+
+		mockMsgHandler := msghandler.NewMockMsgServiceProvider()
+		require.NoError(t, mockMsgHandler.Register(&generic.MockMessageSvc{}))
+
+		ctx, err := New(WithMessageServiceProvider(mockMsgHandler), WithMessengerHandler(messengerHandler))
+		require.NoError(t, err)
+
+		inboundHandler := ctx.InboundMessageHandler()
+
+		require.NoError(t, inboundHandler(&transport.Envelope{Message: []byte(`{
+			"@id": "msg-1",
+			"@type": "valid-message-type",
+			"~thread": {"thid": "started-thread"},
+			"~please_ack": {"on": ["RECEIPT"]}
+		}`)}))
+
+		// the unsolicited connectionless message is not acknowledged
+		require.NoError(t, inboundHandler(&transport.Envelope{Message: []byte(`{
+			"@id": "msg-2",
+			"@type": "valid-message-type",
+			"~service": {"recipientKeys": ["did:key:their"], "serviceEndpoint": "https://their.example.com"},
+			"~please_ack": {"on": ["RECEIPT"]}
+		}`)}))
+
+		messengerHandler.err = errors.New("get thread error")
+
+		require.NoError(t, inboundHandler(&transport.Envelope{Message: []byte(`{
+			"@id": "msg-3",
+			"@type": "valid-message-type",
+			"~thread": {"thid": "started-thread"},
+			"~please_ack": {"on": ["RECEIPT"]}
+		}`)}))
+	})
+
+	t.Run("test inbound message handler does not acknowledge without connection", func(t *testing.T) {
+		messengerHandler := serviceMocks.NewMockMessengerHandler(ctrl)
+		messengerHandler.EXPECT().HandleInbound(gomock.Any(), "", "").Return(nil).Times(1)
+		messengerHandler.EXPECT().ReplyToMsg(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		mockMsgHandler := msghandler.NewMockMsgServiceProvider()
+		require.NoError(t, mockMsgHandler.Register(&generic.MockMessageSvc{}))
+
+		ctx, err := New(WithMessageServiceProvider(mockMsgHandler), WithMessengerHandler(messengerHandler))
+		require.NoError(t, err)
+
+		require.NoError(t, ctx.InboundMessageHandler()(&transport.Envelope{Message: []byte(`{
+			"@id": "msg-1",
+			"@type": "valid-message-type",
+			"~please_ack": {"on": ["RECEIPT"]}
+		}`)}))
+	})
+
+	t.Run("test inbound message handler does not acknowledge messages acknowledged by protocol", func(t *testing.T) {
+		messengerHandler := serviceMocks.NewMockMessengerHandler(ctrl)
+		messengerHandler.EXPECT().ReplyToMsg(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		ctx, err := New(WithProtocolServices(&acknowledgingProtocolSvc{&mockdidexchange.MockDIDExchangeSvc{
+			AcceptFunc: func(msgType string) bool {
+				return true
+			},
+			HandleFunc: func(msg service.DIDCommMsg) (string, error) {
+				return "", nil
+			},
+		}}), WithMessengerHandler(messengerHandler))
+		require.NoError(t, err)
+
+		require.NoError(t, ctx.InboundMessageHandler()(&transport.Envelope{Message: []byte(`{
+			"@id": "msg-1",
+			"@type": "valid-message-type",
+			"~please_ack": {"on": ["RECEIPT"]}
+		}`), ToDID: "my-did", FromDID: "their-did"}))
+	})
+
+	t.Run("test inbound message handler acknowledges outcome of message handled by message service",
+		func(t *testing.T) {
+			messengerHandler := serviceMocks.NewMockMessengerHandler(ctrl)
+			messengerHandler.EXPECT().HandleInbound(gomock.Any(), "my-did", "their-did").Return(nil).Times(2)
+			messengerHandler.EXPECT().
+				ReplyToMsg(gomock.Any(), gomock.Any(), "my-did", "their-did").
+				DoAndReturn(func(in, out service.DIDCommMsgMap, _, _ string) error {
+					require.Equal(t, "msg-1", in.ID())
+					require.Equal(t, model.AckMsgType, out.Type())
+
+					return nil
+				}).
+				Times(1)
+
+			mockMsgHandler := msghandler.NewMockMsgServiceProvider()
+			require.NoError(t, mockMsgHandler.Register(&generic.MockMessageSvc{}))
+
+			ctx, err := New(WithMessageServiceProvider(mockMsgHandler), WithMessengerHandler(messengerHandler))
+			require.NoError(t, err)
+
+			inboundHandler := ctx.InboundMessageHandler()
+
+			require.NoError(t, inboundHandler(&transport.Envelope{Message: []byte(`{
+				"@id": "msg-1",
+				"@type": "valid-message-type",
+				"~please_ack": {"on": ["OUTCOME"]}
+			}`), ToDID: "my-did", FromDID: "their-did"}))
+
+			require.NoError(t, inboundHandler(&transport.Envelope{Message: []byte(`{
+				"@id": "msg-2",
+				"@type": "valid-message-type"
+			}`), ToDID: "my-did", FromDID: "their-did"}))
+		})
+
 	t.Run("Messenger handle inbound error", func(t *testing.T) {
 		errTest := errors.New("test")
 
@@ -167,11 +490,11 @@ func TestNewProvider(t *testing.T) {
 		inboundHandler := ctx.InboundMessageHandler()
 
 		// valid json and message type
-		err = inboundHandler([]byte(`
+		err = inboundHandler(&transport.Envelope{Message: []byte(`
 		{
 			"@frameworkID": "5678876542345",
 			"@type": "valid-message-type"
-		}`), "", "")
+		}`)})
 
 		require.EqualError(t, errors.Unwrap(err), errTest.Error())
 	})
@@ -248,11 +571,11 @@ func TestNewProvider(t *testing.T) {
 
 		inboundHandler := prov.InboundMessageHandler()
 
-		err = inboundHandler([]byte(fmt.Sprintf(`
+		err = inboundHandler(&transport.Envelope{Message: []byte(fmt.Sprintf(`
 		{
 			"@frameworkID": "5678876542345",
 			"@type": "%s"
-		}`, sampleMsgType)), "did1", "did2")
+		}`, sampleMsgType)), ToDID: "did1", FromDID: "did2"})
 		require.NoError(t, err)
 
 		select {
@@ -396,3 +719,23 @@ func TestNewProvider(t *testing.T) {
 		require.Equal(t, frameworkID, prov.AriesFrameworkID())
 	})
 }
+
+// connectionlessMessengerHandler knows the connectionless threads started by the agent.
+type connectionlessMessengerHandler struct {
+	*serviceMocks.MockMessengerHandler
+	started map[string]bool
+	err     error
+}
+
+func (m *connectionlessMessengerHandler) StartedConnectionless(thID string) (bool, error) {
+	return m.started[thID], m.err
+}
+
+// acknowledgingProtocolSvc acknowledges the messages with the ~please_ack decorator itself.
+type acknowledgingProtocolSvc struct {
+	*mockdidexchange.MockDIDExchangeSvc
+}
+
+func (s *acknowledgingProtocolSvc) Acknowledges(string) bool {
+	return true
+}
@@ -44,6 +44,8 @@ type Crypto struct {
 	BBSSignKey        []byte
 	BBSSignFn         BBSSignFunc
 	BBSSignErr        error
+	BBSBlindSignValue []byte
+	BBSBlindSignErr   error
 	BBSVerifyErr      error
 	VerifyProofErr    error
 	DeriveProofValue  []byte
@@ -107,6 +109,12 @@ func (c *Crypto) SignMulti(messages [][]byte, kh interface{}) ([]byte, error) {
 	return c.BBSSignValue, c.BBSSignErr
 }
 
+// BlindSignMulti returns a mocked BBS+ blind signature value and a mocked error.
+func (c *Crypto) BlindSignMulti(revealedMessages [][]byte, revealedIndexes []int, messagesCount int,
+	blindContext, nonce []byte, kh interface{}) ([]byte, error) {
+	return c.BBSBlindSignValue, c.BBSBlindSignErr
+}
+
 // VerifyMulti returns a mocked BBS+ verify result.
 // returns:
 // 		error in case of errors or nil if signature verification was successful
@@ -0,0 +1,121 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package actionmenu
+
+import (
+	"github.com/google/uuid"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/actionmenu"
+)
+
+// MockActionMenuSvc mock action menu service.
+type MockActionMenuSvc struct {
+	service.Action
+	service.Message
+	HandleFunc         func(service.DIDCommMsg) (string, error)
+	PublishMenuFunc    func(connectionID string, menu *actionmenu.Menu) error
+	SendMenuFunc       func(connectionID string, menu *actionmenu.Menu) error
+	RequestMenuErr     error
+	MenuValue          *actionmenu.Menu
+	MenuErr            error
+	PerformFunc        func(connectionID, name string, params map[string]string) error
+	ActionsValue       []actionmenu.Action
+	ActionsErr         error
+	ActionContinueFunc func(piID string, opt actionmenu.Opt) error
+	ActionStopErr      error
+}
+
+// HandleInbound msg.
+func (m *MockActionMenuSvc) HandleInbound(msg service.DIDCommMsg, myDID, theirDID string) (string, error) {
+	if m.HandleFunc != nil {
+		return m.HandleFunc(msg)
+	}
+
+	return uuid.New().String(), nil
+}
+
+// HandleOutbound msg.
+func (m *MockActionMenuSvc) HandleOutbound(msg service.DIDCommMsg, myDID, theirDID string) (string, error) {
+	return "", nil
+}
+
+// Accept msg checks the msg type.
+func (m *MockActionMenuSvc) Accept(msgType string) bool {
+	switch msgType {
+	case actionmenu.MenuMsgType, actionmenu.MenuRequestMsgType, actionmenu.PerformMsgType:
+		return true
+	}
+
+	return false
+}
+
+// Name return service name.
+func (m *MockActionMenuSvc) Name() string {
+	return actionmenu.ActionMenu
+}
+
+// PublishMenu publishes the menu of the connection.
+func (m *MockActionMenuSvc) PublishMenu(connectionID string, menu *actionmenu.Menu) error {
+	if m.PublishMenuFunc != nil {
+		return m.PublishMenuFunc(connectionID, menu)
+	}
+
+	return nil
+}
+
+// PublishedMenu returns the menu published for the connection.
+func (m *MockActionMenuSvc) PublishedMenu(string) (*actionmenu.Menu, error) {
+	return m.MenuValue, m.MenuErr
+}
+
+// SendMenu sends the menu to the connection.
+func (m *MockActionMenuSvc) SendMenu(connectionID string, menu *actionmenu.Menu) error {
+	if m.SendMenuFunc != nil {
+		return m.SendMenuFunc(connectionID, menu)
+	}
+
+	return nil
+}
+
+// RequestMenu requests the menu from the connection.
+func (m *MockActionMenuSvc) RequestMenu(string) error {
+	return m.RequestMenuErr
+}
+
+// Menu returns the menu received from the connection.
+func (m *MockActionMenuSvc) Menu(string) (*actionmenu.Menu, error) {
+	return m.MenuValue, m.MenuErr
+}
+
+// Perform asks the connection to perform the action.
+func (m *MockActionMenuSvc) Perform(connectionID, name string, params map[string]string) error {
+	if m.PerformFunc != nil {
+		return m.PerformFunc(connectionID, name, params)
+	}
+
+	return nil
+}
+
+// Actions returns the pending actions.
+func (m *MockActionMenuSvc) Actions() ([]actionmenu.Action, error) {
+	return m.ActionsValue, m.ActionsErr
+}
+
+// ActionContinue continues the action.
+func (m *MockActionMenuSvc) ActionContinue(piID string, opt actionmenu.Opt) error {
+	if m.ActionContinueFunc != nil {
+		return m.ActionContinueFunc(piID, opt)
+	}
+
+	return nil
+}
+
+// ActionStop stops the action.
+func (m *MockActionMenuSvc) ActionStop(string, error) error {
+	return m.ActionStopErr
+}
@@ -0,0 +1,94 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discoverfeatures
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/discoverfeatures"
+)
+
+// MockDiscoverFeaturesSvc mock discover features service.
+type MockDiscoverFeaturesSvc struct {
+	HandleFunc         func(service.DIDCommMsg) (string, error)
+	QueryProtocolsFunc func(connectionID, query string, timeout time.Duration) ([]*discoverfeatures.Feature, error)
+	QueryFeaturesFunc  func(connectionID string, queries []*discoverfeatures.FeatureQuery,
+		timeout time.Duration) ([]*discoverfeatures.Feature, error)
+	QueryValue []*discoverfeatures.Feature
+	QueryErr   error
+	Registered []*discoverfeatures.Feature
+	AllowList  []string
+	LocalValue []*discoverfeatures.Feature
+}
+
+// HandleInbound msg.
+func (m *MockDiscoverFeaturesSvc) HandleInbound(msg service.DIDCommMsg, myDID, theirDID string) (string, error) {
+	if m.HandleFunc != nil {
+		return m.HandleFunc(msg)
+	}
+
+	return uuid.New().String(), nil
+}
+
+// HandleOutbound msg.
+func (m *MockDiscoverFeaturesSvc) HandleOutbound(msg service.DIDCommMsg, myDID, theirDID string) (string, error) {
+	return "", nil
+}
+
+// Accept msg checks the msg type.
+func (m *MockDiscoverFeaturesSvc) Accept(msgType string) bool {
+	switch msgType {
+	case discoverfeatures.QueryMsgTypeV1, discoverfeatures.DiscloseMsgTypeV1,
+		discoverfeatures.QueriesMsgTypeV2, discoverfeatures.DiscloseMsgTypeV2:
+		return true
+	}
+
+	return false
+}
+
+// Name return service name.
+func (m *MockDiscoverFeaturesSvc) Name() string {
+	return discoverfeatures.DiscoverFeatures
+}
+
+// QueryProtocols queries the protocols supported by the connection.
+func (m *MockDiscoverFeaturesSvc) QueryProtocols(connectionID, query string,
+	timeout time.Duration) ([]*discoverfeatures.Feature, error) {
+	if m.QueryProtocolsFunc != nil {
+		return m.QueryProtocolsFunc(connectionID, query, timeout)
+	}
+
+	return m.QueryValue, m.QueryErr
+}
+
+// QueryFeatures queries the features supported by the connection.
+func (m *MockDiscoverFeaturesSvc) QueryFeatures(connectionID string, queries []*discoverfeatures.FeatureQuery,
+	timeout time.Duration) ([]*discoverfeatures.Feature, error) {
+	if m.QueryFeaturesFunc != nil {
+		return m.QueryFeaturesFunc(connectionID, queries, timeout)
+	}
+
+	return m.QueryValue, m.QueryErr
+}
+
+// Register registers the features.
+func (m *MockDiscoverFeaturesSvc) Register(features ...*discoverfeatures.Feature) {
+	m.Registered = append(m.Registered, features...)
+}
+
+// SetAllowList sets the allow-list.
+func (m *MockDiscoverFeaturesSvc) SetAllowList(patterns ...string) {
+	m.AllowList = patterns
+}
+
+// LocalFeatures returns the local features.
+func (m *MockDiscoverFeaturesSvc) LocalFeatures(_ ...*discoverfeatures.FeatureQuery) []*discoverfeatures.Feature {
+	return m.LocalValue
+}
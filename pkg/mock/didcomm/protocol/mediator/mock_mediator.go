@@ -30,6 +30,10 @@ type MockMediatorSvc struct {
 	Connections        []string
 	GetConnectionsErr  error
 	AddKeyFunc         func(string) error
+	RemoveKeyErr       error
+	RemoveKeyFunc      func(string) error
+	Keys               []string
+	GetKeysErr         error
 }
 
 // HandleInbound msg.
@@ -95,6 +99,19 @@ func (m *MockMediatorSvc) AddKey(connID, recKey string) error {
 	return nil
 }
 
+// RemoveKey removes agents recKey from the router.
+func (m *MockMediatorSvc) RemoveKey(connID, recKey string) error {
+	if m.RemoveKeyErr != nil {
+		return m.RemoveKeyErr
+	}
+
+	if m.RemoveKeyFunc != nil {
+		return m.RemoveKeyFunc(recKey)
+	}
+
+	return nil
+}
+
 // Config gives back the router configuration.
 func (m *MockMediatorSvc) Config(connID string) (*mediator.Config, error) {
 	if m.ConfigErr != nil {
@@ -117,3 +134,12 @@ func (m *MockMediatorSvc) GetConnections() ([]string, error) {
 
 	return m.Connections, nil
 }
+
+// GetKeys returns the recipient keys registered with the router.
+func (m *MockMediatorSvc) GetKeys(connID string) ([]string, error) {
+	if m.GetKeysErr != nil {
+		return nil, m.GetKeysErr
+	}
+
+	return m.Keys, nil
+}
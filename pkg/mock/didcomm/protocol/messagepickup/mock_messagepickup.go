@@ -27,6 +27,12 @@ type MockMessagePickupSvc struct {
 	AcceptFunc         func(msgType string) bool
 	NoopErr            error
 	NoopFunc           func(connectionID string) error
+	StatusV2Err        error
+	StatusV2Func       func(connectionID, recipientKey string) (*messagepickup.StatusV2, error)
+	DeliveryErr        error
+	DeliveryFunc       func(connectionID string, limit int, recipientKey string) (int, error)
+	LiveDeliveryErr    error
+	LiveDeliveryFunc   func(connectionID string, liveDelivery bool) error
 }
 
 // Name return service name.
@@ -95,6 +101,50 @@ func (m *MockMessagePickupSvc) AddMessage(message *model.Envelope, theirDID stri
 	return nil
 }
 
+// AddMessageForRecipient perform AddMessage, the recipient key is ignored.
+func (m *MockMessagePickupSvc) AddMessageForRecipient(message *model.Envelope, theirDID, _ string) error {
+	return m.AddMessage(message, theirDID)
+}
+
+// StatusRequestV2 perform StatusRequestV2.
+func (m *MockMessagePickupSvc) StatusRequestV2(connectionID, recipientKey string) (*messagepickup.StatusV2, error) {
+	if m.StatusV2Err != nil {
+		return nil, m.StatusV2Err
+	}
+
+	if m.StatusV2Func != nil {
+		return m.StatusV2Func(connectionID, recipientKey)
+	}
+
+	return &messagepickup.StatusV2{}, nil
+}
+
+// DeliveryRequest perform DeliveryRequest.
+func (m *MockMessagePickupSvc) DeliveryRequest(connectionID string, limit int, recipientKey string) (int, error) {
+	if m.DeliveryErr != nil {
+		return -1, m.DeliveryErr
+	}
+
+	if m.DeliveryFunc != nil {
+		return m.DeliveryFunc(connectionID, limit, recipientKey)
+	}
+
+	return 0, nil
+}
+
+// LiveDeliveryChange perform LiveDeliveryChange.
+func (m *MockMessagePickupSvc) LiveDeliveryChange(connectionID string, liveDelivery bool) error {
+	if m.LiveDeliveryErr != nil {
+		return m.LiveDeliveryErr
+	}
+
+	if m.LiveDeliveryFunc != nil {
+		return m.LiveDeliveryFunc(connectionID, liveDelivery)
+	}
+
+	return nil
+}
+
 // Noop perform Noop.
 func (m *MockMessagePickupSvc) Noop(connectionID string) error {
 	if m.NoopErr != nil {
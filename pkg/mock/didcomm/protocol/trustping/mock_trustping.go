@@ -0,0 +1,63 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package trustping
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/trustping"
+)
+
+// MockTrustPingSvc mock trust ping service.
+type MockTrustPingSvc struct {
+	HandleFunc func(service.DIDCommMsg) (string, error)
+	PingFunc   func(connectionID string, responseRequested bool, timeout time.Duration) (time.Duration, error)
+	PingValue  time.Duration
+	PingErr    error
+}
+
+// HandleInbound msg.
+func (m *MockTrustPingSvc) HandleInbound(msg service.DIDCommMsg, myDID, theirDID string) (string, error) {
+	if m.HandleFunc != nil {
+		return m.HandleFunc(msg)
+	}
+
+	return uuid.New().String(), nil
+}
+
+// HandleOutbound msg.
+func (m *MockTrustPingSvc) HandleOutbound(msg service.DIDCommMsg, myDID, theirDID string) (string, error) {
+	return "", nil
+}
+
+// Accept msg checks the msg type.
+func (m *MockTrustPingSvc) Accept(msgType string) bool {
+	return msgType == trustping.PingMsgType || msgType == trustping.PingResponseMsgType
+}
+
+// MessageTypes returns the message types handled by the service.
+func (m *MockTrustPingSvc) MessageTypes() []string {
+	return []string{trustping.PingMsgType, trustping.PingResponseMsgType}
+}
+
+// Name return service name.
+func (m *MockTrustPingSvc) Name() string {
+	return trustping.TrustPing
+}
+
+// Ping sends the ping to the connection.
+func (m *MockTrustPingSvc) Ping(connectionID string, responseRequested bool,
+	timeout time.Duration) (time.Duration, error) {
+	if m.PingFunc != nil {
+		return m.PingFunc(connectionID, responseRequested, timeout)
+	}
+
+	return m.PingValue, m.PingErr
+}
@@ -26,6 +26,7 @@ type Provider struct {
 	PackerList                        []packer.Packer
 	PackerValue                       packer.Packer
 	OutboundDispatcherValue           dispatcher.Outbound
+	OutboundQueueValue                *dispatcher.OutboundQueue
 	VDRegistryValue                   vdrapi.Registry
 	CryptoValue                       crypto.Crypto
 }
@@ -88,6 +89,11 @@ func (p *Provider) OutboundDispatcher() dispatcher.Outbound {
 	return p.OutboundDispatcherValue
 }
 
+// OutboundQueue returns the outbound message queue.
+func (p *Provider) OutboundQueue() *dispatcher.OutboundQueue {
+	return p.OutboundQueueValue
+}
+
 // VDRegistry return vdr registry.
 func (p *Provider) VDRegistry() vdrapi.Registry {
 	return p.VDRegistryValue
@@ -99,6 +99,7 @@ type MockStore struct {
 	ErrPut    error
 	ErrGet    error
 	ErrDelete error
+	ErrBatch  error
 }
 
 // Put stores the key and the record.
@@ -191,9 +192,33 @@ func (s *MockStore) Delete(k string) error {
 	return s.ErrDelete
 }
 
-// Batch is not implemented.
+// Batch performs the Put and Delete operations in order.
 func (s *MockStore) Batch(operations []storage.Operation) error {
-	panic("implement me")
+	if s.ErrBatch != nil {
+		return s.ErrBatch
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, op := range operations {
+		if op.Key == "" {
+			return errors.New("key is mandatory")
+		}
+
+		if op.Value == nil {
+			delete(s.Store, op.Key)
+
+			continue
+		}
+
+		s.Store[op.Key] = DBEntry{
+			Value: op.Value,
+			Tags:  op.Tags,
+		}
+	}
+
+	return nil
 }
 
 // Flush is not implemented.
@@ -118,6 +118,58 @@ func (c *Recorder) SaveConnectionRecordWithMappings(record *Record) error {
 	return nil
 }
 
+// UpdateConnectionDIDs saves the completed connection record after its DIDs were changed, e.g. by a DID rotation.
+// The record and the DIDs to connection ID mapping used by GetConnectionIDByDIDs are replaced in a single batch,
+// so the connection is found either by the old or by the new DIDs.
+func (c *Recorder) UpdateConnectionDIDs(record *Record) error {
+	if record.State != StateNameCompleted {
+		return fmt.Errorf("connection %s is not completed", record.ConnectionID)
+	}
+
+	current, err := c.GetConnectionRecord(record.ConnectionID)
+	if err != nil {
+		return fmt.Errorf("get connection record: %w", err)
+	}
+
+	bytes, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("marshal connection record: %w", err)
+	}
+
+	operations := []storage.Operation{{
+		Key:   getConnectionKeyPrefix()(record.ConnectionID),
+		Value: bytes,
+		Tags: []storage.Tag{{
+			Name:  getConnectionKeyPrefix()(""),
+			Value: getConnectionKeyPrefix()(record.ConnectionID),
+		}},
+	}, {
+		Key:   getDIDConnMapKeyPrefix()(record.MyDID, record.TheirDID),
+		Value: []byte(record.ConnectionID),
+	}}
+
+	oldMapKey := getDIDConnMapKeyPrefix()(current.MyDID, current.TheirDID)
+	if oldMapKey != getDIDConnMapKeyPrefix()(record.MyDID, record.TheirDID) {
+		// a nil value deletes the old mapping
+		operations = append(operations, storage.Operation{Key: oldMapKey})
+	}
+
+	if err = c.store.Batch(operations); err != nil {
+		return fmt.Errorf("update connection DIDs in permanent store: %w", err)
+	}
+
+	// the permanent store is looked up first, the protocol state store copies are kept for consistency only
+	if err = marshalAndSave(getConnectionKeyPrefix()(record.ConnectionID), record, c.protocolStateStore,
+		storage.Tag{
+			Name:  getConnectionKeyPrefix()(""),
+			Value: getConnectionKeyPrefix()(record.ConnectionID),
+		}); err != nil {
+		return fmt.Errorf("save connection record in protocol state store: %w", err)
+	}
+
+	return nil
+}
+
 // SaveEvent saves event related data for given connection ID
 // TODO connection event data shouldn't be transient [Issues #1029].
 func (c *Recorder) SaveEvent(connectionID string, data []byte) error {
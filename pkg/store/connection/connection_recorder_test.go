@@ -10,6 +10,7 @@
 package connection
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 
@@ -455,6 +456,99 @@ func TestConnectionRecorder_SaveConnectionRecord(t *testing.T) {
 	})
 }
 
+func TestConnectionRecorder_UpdateConnectionDIDs(t *testing.T) {
+	newRecord := func(t *testing.T, recorder *Recorder) *Record {
+		t.Helper()
+
+		record := &Record{
+			ThreadID:     threadIDValue,
+			ConnectionID: uuid.New().String(),
+			State:        StateNameCompleted,
+			Namespace:    TheirNSPrefix,
+			MyDID:        "did:mydid:123",
+			TheirDID:     "did:theirdid:123",
+		}
+		require.NoError(t, recorder.SaveConnectionRecord(record))
+
+		return record
+	}
+
+	t.Run("success", func(t *testing.T) {
+		recorder, err := NewRecorder(&protocol.MockProvider{})
+		require.NoError(t, err)
+
+		record := newRecord(t, recorder)
+		record.TheirDID = "did:theirdid:456"
+
+		require.NoError(t, recorder.UpdateConnectionDIDs(record))
+
+		connID, err := recorder.GetConnectionIDByDIDs("did:mydid:123", "did:theirdid:456")
+		require.NoError(t, err)
+		require.Equal(t, record.ConnectionID, connID)
+
+		_, err = recorder.GetConnectionIDByDIDs("did:mydid:123", "did:theirdid:123")
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+		recordFound, err := recorder.GetConnectionRecord(record.ConnectionID)
+		require.NoError(t, err)
+		require.Equal(t, record, recordFound)
+
+		var r Record
+		require.NoError(t, getAndUnmarshal(getConnectionKeyPrefix()(record.ConnectionID), &r,
+			recorder.protocolStateStore))
+		require.Equal(t, record, &r)
+
+		// DIDs did not change
+		require.NoError(t, recorder.UpdateConnectionDIDs(record))
+
+		connID, err = recorder.GetConnectionIDByDIDs("did:mydid:123", "did:theirdid:456")
+		require.NoError(t, err)
+		require.Equal(t, record.ConnectionID, connID)
+	})
+
+	t.Run("error - connection is not completed", func(t *testing.T) {
+		recorder, err := NewRecorder(&protocol.MockProvider{})
+		require.NoError(t, err)
+
+		err = recorder.UpdateConnectionDIDs(&Record{ConnectionID: "id", State: stateNameInvited})
+		require.EqualError(t, err, "connection id is not completed")
+	})
+
+	t.Run("error - connection not found", func(t *testing.T) {
+		recorder, err := NewRecorder(&protocol.MockProvider{})
+		require.NoError(t, err)
+
+		err = recorder.UpdateConnectionDIDs(&Record{ConnectionID: "id", State: StateNameCompleted})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "get connection record")
+	})
+
+	t.Run("error - batch fails, old DIDs are kept", func(t *testing.T) {
+		store := &mockstorage.MockStore{Store: make(map[string]mockstorage.DBEntry)}
+
+		recorder, err := NewRecorder(&protocol.MockProvider{
+			StoreProvider: mockstorage.NewCustomMockStoreProvider(store),
+		})
+		require.NoError(t, err)
+
+		record := newRecord(t, recorder)
+		record.TheirDID = "did:theirdid:456"
+
+		store.ErrBatch = errors.New("batch error")
+
+		err = recorder.UpdateConnectionDIDs(record)
+		require.EqualError(t, err, "update connection DIDs in permanent store: batch error")
+
+		connID, err := recorder.GetConnectionIDByDIDs("did:mydid:123", "did:theirdid:123")
+		require.NoError(t, err)
+		require.Equal(t, record.ConnectionID, connID)
+
+		recordFound, err := recorder.GetConnectionRecord(record.ConnectionID)
+		require.NoError(t, err)
+		require.Equal(t, "did:theirdid:123", recordFound.TheirDID)
+	})
+}
+
 func TestConnectionRecorder_RemoveConnection(t *testing.T) {
 	t.Run("save and remove connection record with invited state - completed", func(t *testing.T) {
 		recorder, err := NewRecorder(&protocol.MockProvider{})
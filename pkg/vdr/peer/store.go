@@ -60,6 +60,33 @@ func (v *VDR) storeDID(doc *did.Doc, by *[]modifiedBy) error { //nolint: unparam
 	return v.store.Put(doc.ID, val)
 }
 
+// updateDID appends the updated Peer DID Document to the stored deltas of the DID.
+func (v *VDR) updateDID(doc *did.Doc, by *[]modifiedBy) error { //nolint: unparam
+	deltas, err := v.getDeltas(doc.ID)
+	if err != nil {
+		return fmt.Errorf("delta data fetch from store for did [%s] failed: %w", doc.ID, err)
+	}
+
+	// the change holds the whole updated document
+	jsonDoc, err := doc.JSONBytes()
+	if err != nil {
+		return fmt.Errorf("JSON marshalling of document failed: %w", err)
+	}
+
+	deltas = append(deltas, docDelta{
+		Change:     base64.URLEncoding.EncodeToString(jsonDoc),
+		ModifiedBy: by,
+		ModifiedAt: time.Now(),
+	})
+
+	val, err := json.Marshal(deltas)
+	if err != nil {
+		return fmt.Errorf("JSON marshalling of document deltas failed: %w", err)
+	}
+
+	return v.store.Put(doc.ID, val)
+}
+
 // Get returns Peer DID Document.
 func (v *VDR) Get(id string) (*did.Doc, error) {
 	if id == "" {
@@ -71,8 +98,8 @@ func (v *VDR) Get(id string) (*did.Doc, error) {
 		return nil, fmt.Errorf("delta data fetch from store for did [%s] failed: %w", id, err)
 	}
 
-	// For now, assume storage contains only one delta(genesis document)
-	delta := deltas[0]
+	// each delta holds the whole document, the latest one is the current state of the document
+	delta := deltas[len(deltas)-1]
 
 	doc, err := base64.URLEncoding.DecodeString(delta.Change)
 	if err != nil {
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package peer
 
 import (
+	"errors"
 	"fmt"
 
 	diddoc "github.com/hyperledger/aries-framework-go/pkg/doc/did"
@@ -38,9 +39,13 @@ func New(s storage.Provider) (*VDR, error) {
 	return &VDR{store: didDBStore}, nil
 }
 
-// Update did doc.
+// Update did doc. The updated document is stored as a new delta of the existing peer DID.
 func (v *VDR) Update(didDoc *diddoc.Doc, opts ...vdrapi.DIDMethodOption) error {
-	return fmt.Errorf("not supported")
+	if didDoc == nil || didDoc.ID == "" {
+		return errors.New("DID and document are mandatory")
+	}
+
+	return v.updateDID(didDoc, nil)
 }
 
 // Deactivate did doc.
@@ -7,21 +7,56 @@ SPDX-License-Identifier: Apache-2.0
 package peer
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	vdrapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdr"
 	"github.com/hyperledger/aries-framework-go/pkg/mock/storage"
 )
 
 func TestUpdate(t *testing.T) {
 	t.Run("test update", func(t *testing.T) {
-		v, err := New(&storage.MockStoreProvider{})
+		v, err := New(storage.NewMockStoreProvider())
+		require.NoError(t, err)
+
+		context := []string{"https://w3id.org/did/v1"}
+		didID := "did:peer:1234"
+
+		require.NoError(t, v.storeDID(&did.Doc{Context: context, ID: didID}, nil))
+
+		err = v.Update(&did.Doc{
+			Context: context,
+			ID:      didID,
+			Service: []did.Service{{
+				ID:              "did:example:123#svc",
+				Type:            "did-communication",
+				ServiceEndpoint: "https://localhost:8090",
+			}},
+		})
+		require.NoError(t, err)
+
+		doc, err := v.Get(didID)
+		require.NoError(t, err)
+		require.Len(t, doc.Service, 1)
+
+		deltas, err := v.getDeltas(didID)
+		require.NoError(t, err)
+		require.Len(t, deltas, 2)
+	})
+
+	t.Run("test update - invalid document", func(t *testing.T) {
+		v, err := New(storage.NewMockStoreProvider())
 		require.NoError(t, err)
 
 		err = v.Update(nil)
+		require.EqualError(t, err, "DID and document are mandatory")
+
+		err = v.Update(&did.Doc{ID: "did:peer:1234"})
 		require.Error(t, err)
-		require.Contains(t, err.Error(), "not supported")
+		require.True(t, errors.Is(err, vdrapi.ErrNotFound))
 	})
 }
 